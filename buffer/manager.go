@@ -98,6 +98,19 @@ func (mgr *Manager) FlushAll(txnum domain.TransactionNumber) error {
 	return nil
 }
 
+// DiscardFile detaches unpinned buffers from the blocks of the file.
+// file を削除した後に古い内容が読まれないようにする.
+func (mgr *Manager) DiscardFile(filename domain.FileName) {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+
+	for _, buf := range mgr.bufferPool {
+		if buf.Block().FileName() == filename && !buf.IsPinned() {
+			buf.Discard()
+		}
+	}
+}
+
 // Unpin unpins buffer.
 func (mgr *Manager) Unpin(buf *domain.Buffer) {
 	mgr.mu.Lock()
@@ -1,8 +1,12 @@
 package database
 
 import (
+	"os"
+
 	"github.com/goropikari/simpledbgo/domain"
 	"github.com/goropikari/simpledbgo/errors"
+	"github.com/goropikari/simpledbgo/index/btree"
+	"github.com/goropikari/simpledbgo/index/hash"
 	"github.com/goropikari/simpledbgo/plan"
 	"github.com/goropikari/simpledbgo/tx"
 )
@@ -13,25 +17,70 @@ const (
 	timeoutMilliSec = 10000
 )
 
+// ErrUnknownQueryPlanner is an error that means the query planner is not supported.
+var ErrUnknownQueryPlanner = errors.New("unknown query planner")
+
+// QueryPlannerType is a kind of query planner.
+type QueryPlannerType string
+
+const (
+	// BasicQueryPlanner plans tables in the order of the query.
+	BasicQueryPlanner QueryPlannerType = "basic"
+
+	// BetterQueryPlanner chooses the cheaper join method for each pair of tables.
+	BetterQueryPlanner QueryPlannerType = "better"
+
+	// HeuristicQueryPlanner pushes selections down, uses indexes and orders joins greedily.
+	HeuristicQueryPlanner QueryPlannerType = "heuristic"
+)
+
 // Config is configuration for server.
 type Config struct {
 	DBPath          string
 	BlockSize       int32
 	NumBuf          int
 	TimeoutMilliSec int
+	QueryPlanner    QueryPlannerType
 }
 
 // NewConfig constructs a Config.
+// query planner は環境変数 SIMPLEDB_QUERY_PLANNER で選択できる.
 func NewConfig() Config {
 	c := Config{
 		BlockSize:       blockSize,
 		NumBuf:          numBuf,
 		TimeoutMilliSec: timeoutMilliSec,
+		QueryPlanner:    BasicQueryPlanner,
+	}
+
+	if planner := os.Getenv("SIMPLEDB_QUERY_PLANNER"); planner != "" {
+		c.QueryPlanner = QueryPlannerType(planner)
 	}
 
 	return c
 }
 
+// NewQueryPlanner constructs the query planner specified by cfg.
+func NewQueryPlanner(cfg Config, metadataMgr domain.MetadataManager) (domain.QueryPlanner, error) {
+	switch cfg.QueryPlanner {
+	case BasicQueryPlanner:
+		return plan.NewBasicQueryPlanner(metadataMgr), nil
+	case BetterQueryPlanner:
+		return plan.NewBetterQueryPlanner(metadataMgr), nil
+	case HeuristicQueryPlanner:
+		return plan.NewHeuristicQueryPlanner(metadataMgr), nil
+	default:
+		return nil, ErrUnknownQueryPlanner
+	}
+}
+
+// NewIndexDriver constructs an IndexDriver which supports B-tree and hash index.
+// USING を省略した index は B-tree になる.
+func NewIndexDriver() domain.IndexDriver {
+	return domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator()).
+		Register(domain.HashIndexType, hash.NewIndexFactory(), hash.NewSearchCostCalculator())
+}
+
 // DB is database.
 type DB struct {
 	fmgr domain.FileManager
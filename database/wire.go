@@ -10,28 +10,12 @@ import (
 	"github.com/goropikari/simpledbgo/buffer"
 	"github.com/goropikari/simpledbgo/domain"
 	"github.com/goropikari/simpledbgo/file"
-	"github.com/goropikari/simpledbgo/index/dummy"
-	"github.com/goropikari/simpledbgo/index/hash"
 	"github.com/goropikari/simpledbgo/log"
 	"github.com/goropikari/simpledbgo/metadata"
 	"github.com/goropikari/simpledbgo/plan"
 	"github.com/goropikari/simpledbgo/tx"
 )
 
-var SetHashIndex = wire.NewSet(
-	hash.NewIndexFactory,
-	wire.Bind(new(domain.IndexFactory), new(*hash.IndexFactory)),
-	hash.NewSearchCostCalculator,
-	wire.Bind(new(domain.SearchCostCalculator), new(*hash.SearchCostCalculator)),
-)
-
-var SetDummyIndex = wire.NewSet(
-	dummy.NewIndexFactory,
-	wire.Bind(new(domain.IndexFactory), new(*dummy.IndexFactory)),
-	dummy.NewSearchCostCalculator,
-	wire.Bind(new(domain.SearchCostCalculator), new(*dummy.SearchCostCalculator)),
-)
-
 var Set = wire.NewSet(
 	file.NewManagerConfig,
 	file.NewManager,
@@ -46,15 +30,13 @@ var Set = wire.NewSet(
 	tx.NewLockTable,
 	tx.NewNumberGenerator,
 	wire.Bind(new(domain.TxNumberGenerator), new(*tx.NumberGenerator)),
-	SetDummyIndex,
-	// SetHashIndex,
-	domain.NewIndexDriver,
+	NewIndexDriver,
 	metadata.NewManager,
 	wire.Bind(new(domain.MetadataManager), new(*metadata.Manager)),
-	plan.NewBasicQueryPlanner,
-	wire.Bind(new(domain.QueryPlanner), new(*plan.BasicQueryPlanner)),
-	plan.NewBasicUpdatePlanner,
-	wire.Bind(new(domain.UpdateExecutor), new(*plan.BasicUpdatePlanner)),
+	NewConfig,
+	NewQueryPlanner,
+	plan.NewIndexUpdatePlanner,
+	wire.Bind(new(domain.UpdateExecutor), new(*plan.IndexUpdatePlanner)),
 	plan.NewExecutor,
 	NewDB,
 )
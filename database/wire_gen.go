@@ -11,8 +11,6 @@ import (
 	"github.com/goropikari/simpledbgo/buffer"
 	"github.com/goropikari/simpledbgo/domain"
 	"github.com/goropikari/simpledbgo/file"
-	"github.com/goropikari/simpledbgo/index/dummy"
-	"github.com/goropikari/simpledbgo/index/hash"
 	"github.com/goropikari/simpledbgo/log"
 	"github.com/goropikari/simpledbgo/metadata"
 	"github.com/goropikari/simpledbgo/plan"
@@ -37,27 +35,28 @@ func InitializeDB() (*DB, error) {
 	if err != nil {
 		return nil, err
 	}
-	lockTableConfig := tx.NewLockTableConfig()
+	lockTableConfig, err := tx.NewLockTableConfig()
+	if err != nil {
+		return nil, err
+	}
 	lockTable := tx.NewLockTable(lockTableConfig)
 	numberGenerator := tx.NewNumberGenerator()
-	indexFactory := dummy.NewIndexFactory()
-	searchCostCalculator := dummy.NewSearchCostCalculator()
-	indexDriver := domain.NewIndexDriver(indexFactory, searchCostCalculator)
+	indexDriver := NewIndexDriver()
 	metadataManager, err := metadata.NewManager(indexDriver, manager, logManager, bufferManager, lockTable, numberGenerator)
 	if err != nil {
 		return nil, err
 	}
-	basicQueryPlanner := plan.NewBasicQueryPlanner(metadataManager)
-	basicUpdatePlanner := plan.NewBasicUpdatePlanner(metadataManager)
-	executor := plan.NewExecutor(basicQueryPlanner, basicUpdatePlanner)
+	databaseConfig := NewConfig()
+	queryPlanner, err := NewQueryPlanner(databaseConfig, metadataManager)
+	if err != nil {
+		return nil, err
+	}
+	indexUpdatePlanner := plan.NewIndexUpdatePlanner(metadataManager)
+	executor := plan.NewExecutor(queryPlanner, indexUpdatePlanner)
 	db := NewDB(manager, logManager, bufferManager, lockTable, numberGenerator, executor)
 	return db, nil
 }
 
 // wire.go:
 
-var SetHashIndex = wire.NewSet(hash.NewIndexFactory, wire.Bind(new(domain.IndexFactory), new(*hash.IndexFactory)), hash.NewSearchCostCalculator, wire.Bind(new(domain.SearchCostCalculator), new(*hash.SearchCostCalculator)))
-
-var SetDummyIndex = wire.NewSet(dummy.NewIndexFactory, wire.Bind(new(domain.IndexFactory), new(*dummy.IndexFactory)), dummy.NewSearchCostCalculator, wire.Bind(new(domain.SearchCostCalculator), new(*dummy.SearchCostCalculator)))
-
-var Set = wire.NewSet(file.NewManagerConfig, file.NewManager, wire.Bind(new(domain.FileManager), new(*file.Manager)), log.NewManagerConfig, log.NewManager, wire.Bind(new(domain.LogManager), new(*log.Manager)), buffer.NewConfig, buffer.NewManager, wire.Bind(new(domain.BufferPoolManager), new(*buffer.Manager)), tx.NewLockTableConfig, tx.NewLockTable, tx.NewNumberGenerator, wire.Bind(new(domain.TxNumberGenerator), new(*tx.NumberGenerator)), SetDummyIndex, domain.NewIndexDriver, metadata.NewManager, wire.Bind(new(domain.MetadataManager), new(*metadata.Manager)), plan.NewBasicQueryPlanner, wire.Bind(new(domain.QueryPlanner), new(*plan.BasicQueryPlanner)), plan.NewBasicUpdatePlanner, wire.Bind(new(domain.UpdateExecutor), new(*plan.BasicUpdatePlanner)), plan.NewExecutor, NewDB)
+var Set = wire.NewSet(file.NewManagerConfig, file.NewManager, wire.Bind(new(domain.FileManager), new(*file.Manager)), log.NewManagerConfig, log.NewManager, wire.Bind(new(domain.LogManager), new(*log.Manager)), buffer.NewConfig, buffer.NewManager, wire.Bind(new(domain.BufferPoolManager), new(*buffer.Manager)), tx.NewLockTableConfig, tx.NewLockTable, tx.NewNumberGenerator, wire.Bind(new(domain.TxNumberGenerator), new(*tx.NumberGenerator)), NewIndexDriver, metadata.NewManager, wire.Bind(new(domain.MetadataManager), new(*metadata.Manager)), NewConfig, NewQueryPlanner, plan.NewIndexUpdatePlanner, wire.Bind(new(domain.UpdateExecutor), new(*plan.IndexUpdatePlanner)), plan.NewExecutor, NewDB)
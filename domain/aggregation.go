@@ -0,0 +1,240 @@
+package domain
+
+import (
+	"fmt"
+
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// ErrInvalidAggregation is an error that means the aggregation can't be applied to the field.
+var ErrInvalidAggregation = errors.New("invalid aggregation")
+
+// AggregationKind is a kind of aggregation function.
+type AggregationKind uint
+
+const (
+	// CountAggregation is count().
+	CountAggregation AggregationKind = iota
+
+	// SumAggregation is sum().
+	SumAggregation
+
+	// MinAggregation is min().
+	MinAggregation
+
+	// MaxAggregation is max().
+	MaxAggregation
+
+	// AvgAggregation is avg().
+	AvgAggregation
+)
+
+// String stringfies the kind.
+func (kind AggregationKind) String() string {
+	switch kind {
+	case CountAggregation:
+		return "count"
+	case SumAggregation:
+		return "sum"
+	case MinAggregation:
+		return "min"
+	case MaxAggregation:
+		return "max"
+	case AvgAggregation:
+		return "avg"
+	default:
+		return "unknown"
+	}
+}
+
+// Aggregation is an aggregation function applied to a field.
+// count(*) の場合 field は "*" になる.
+type Aggregation struct {
+	kind AggregationKind
+	fld  FieldName
+}
+
+// NewAggregation constructs an Aggregation.
+func NewAggregation(kind AggregationKind, fld FieldName) Aggregation {
+	return Aggregation{
+		kind: kind,
+		fld:  fld,
+	}
+}
+
+// Kind returns the kind of the aggregation.
+func (agg Aggregation) Kind() AggregationKind {
+	return agg.kind
+}
+
+// ArgFieldName returns the field name which the aggregation is applied to.
+func (agg Aggregation) ArgFieldName() FieldName {
+	return agg.fld
+}
+
+// FieldName returns the field name of the aggregated value.
+// e.g. count(a) は countofa, count(*) は countofall になる.
+func (agg Aggregation) FieldName() FieldName {
+	if agg.fld == "*" {
+		return FieldName(agg.kind.String() + "ofall")
+	}
+
+	return FieldName(agg.kind.String() + "of" + agg.fld.String())
+}
+
+// FieldType returns the type and the length of the aggregated value.
+func (agg Aggregation) FieldType(sch *Schema) (FieldType, int, error) {
+	if agg.kind == CountAggregation && agg.fld == "*" {
+		return Int32FieldType, 0, nil
+	}
+
+	if !sch.HasField(agg.fld) {
+		return UnknownFieldType, 0, fieldNotFoudError(agg.fld)
+	}
+
+	switch agg.kind {
+	case CountAggregation:
+		return Int32FieldType, 0, nil
+	case SumAggregation, AvgAggregation:
+		typ := sch.Type(agg.fld)
+		if !typ.IsNumeric() {
+			return UnknownFieldType, 0, fmt.Errorf("%w: %v", ErrInvalidAggregation, agg)
+		}
+		if agg.kind == AvgAggregation {
+			return avgType(typ), 0, nil
+		}
+
+		return sumType(typ), 0, nil
+	case MinAggregation, MaxAggregation:
+		return sch.Type(agg.fld), sch.Length(agg.fld), nil
+	default:
+		return UnknownFieldType, 0, fmt.Errorf("%w: %v", ErrInvalidAggregation, agg)
+	}
+}
+
+// String stringfies the aggregation.
+func (agg Aggregation) String() string {
+	return fmt.Sprintf("%v(%v)", agg.kind, agg.fld)
+}
+
+// sumType returns the type of sum of values of typ.
+// SMALLINT の和は INTEGER とする.
+func sumType(typ FieldType) FieldType {
+	if typ == Int16FieldType {
+		return Int32FieldType
+	}
+
+	return typ
+}
+
+// avgType returns the type of average of values of typ.
+// 整数の平均は和を件数で割った整数とする.
+func avgType(typ FieldType) FieldType {
+	t, err := arithmeticType(DivideOperator, []FieldType{sumType(typ), Int32FieldType})
+	if err != nil {
+		return UnknownFieldType
+	}
+
+	return t
+}
+
+// aggregator holds intermediate state of an aggregation.
+type aggregator struct {
+	agg   Aggregation
+	typ   FieldType
+	count int32
+	sum   Constant
+	val   Constant
+}
+
+// newAggregator constructs an aggregator whose value has type typ.
+func newAggregator(agg Aggregation, typ FieldType) *aggregator {
+	return &aggregator{agg: agg, typ: typ}
+}
+
+func (acc *aggregator) reset() {
+	acc.count = 0
+	acc.sum = NewNullConstant()
+	acc.val = NewNullConstant()
+}
+
+// process accumulates the current record of s.
+// count(*) 以外は NULL を無視する.
+func (acc *aggregator) process(s Scanner) error {
+	if acc.agg.fld == "*" {
+		acc.count++
+
+		return nil
+	}
+
+	val, err := s.GetVal(acc.agg.fld)
+	if err != nil {
+		return errors.Err(err, "GetVal")
+	}
+	if val.IsNull() {
+		return nil
+	}
+	acc.count++
+
+	switch acc.agg.kind {
+	case SumAggregation, AvgAggregation:
+		if acc.sum.IsNull() {
+			acc.sum, err = val.ConvertTo(sumType(val.typ))
+			if err != nil {
+				return errors.Err(err, "ConvertTo")
+			}
+
+			return nil
+		}
+		acc.sum, err = applyArithmetic(AddOperator, sumType(val.typ), []Constant{acc.sum, val})
+		if err != nil {
+			return errors.Err(err, "applyArithmetic")
+		}
+	case MinAggregation:
+		if acc.count == 1 || val.Less(acc.val) {
+			acc.val = val
+		}
+	case MaxAggregation:
+		if acc.count == 1 || acc.val.Less(val) {
+			acc.val = val
+		}
+	case CountAggregation:
+	}
+
+	return nil
+}
+
+// value returns the aggregated value.
+// 空の group や NULL だけの group に対する sum, avg, min, max は集約の型の NULL を返す.
+func (acc *aggregator) value() (Constant, error) {
+	switch acc.agg.kind {
+	case CountAggregation:
+		return NewConstant(Int32FieldType, acc.count), nil
+	case SumAggregation:
+		if acc.sum.IsNull() {
+			return Constant{typ: acc.typ}, nil
+		}
+
+		return acc.sum.ConvertTo(acc.typ)
+	case AvgAggregation:
+		if acc.sum.IsNull() {
+			return Constant{typ: acc.typ}, nil
+		}
+
+		count := NewConstant(Int32FieldType, acc.count)
+		avg, err := applyArithmetic(DivideOperator, avgType(acc.sum.typ), []Constant{acc.sum, count})
+		if err != nil {
+			return Constant{}, errors.Err(err, "applyArithmetic")
+		}
+
+		return avg.ConvertTo(acc.typ)
+	case MinAggregation, MaxAggregation:
+		if acc.val.IsNull() {
+			return Constant{typ: acc.typ}, nil
+		}
+
+		return acc.val, nil
+	default:
+		return Constant{}, fmt.Errorf("%w: %v", ErrInvalidAggregation, acc.agg)
+	}
+}
@@ -109,3 +109,11 @@ func (buf *Buffer) Unpin() {
 func (buf *Buffer) Page() *Page {
 	return buf.page
 }
+
+// Discard detaches the buffer from its block without flushing.
+// 削除された file の block を再利用しないようにするために使う.
+func (buf *Buffer) Discard() {
+	buf.block = Block{}
+	buf.txnum = DummyTransactionNumber
+	buf.lsn = DummyLSN
+}
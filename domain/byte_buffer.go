@@ -10,6 +10,8 @@ type ByteBuffer interface {
 	GetData() []byte
 	GetInt32(offset int64) (int32, error)
 	SetInt32(offset int64, val int32) error
+	GetInt64(offset int64) (int64, error)
+	SetInt64(offset int64, val int64) error
 	GetString(offset int64) (string, error)
 	SetString(offset int64, val string) error
 	GetBytes(offset int64) ([]byte, error)
@@ -2,11 +2,30 @@ package domain
 
 import (
 	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	stdmath "math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/goropikari/simpledbgo/math"
 )
 
+var (
+	// ErrInvalidBytes is an error that means the string can't be converted into BYTEA.
+	ErrInvalidBytes = errors.New("invalid input syntax for type bytea")
+
+	// ErrNumericOutOfRange is an error that means the value is out of range of the numeric type.
+	ErrNumericOutOfRange = errors.New("value out of range")
+)
+
+// bytesHexPrefix is the prefix of hex format of BYTEA.
+const bytesHexPrefix = `\x`
+
 // Constant is constant type of database.
+// val が nil のときは NULL を表す.
 type Constant struct {
 	typ FieldType
 	val any
@@ -17,6 +36,29 @@ func NewConstant(typ FieldType, val any) Constant {
 	return Constant{typ: typ, val: val}
 }
 
+// tuple is a value of composite key.
+type tuple []Constant
+
+// NewTupleConstant constructs a Constant of composite key.
+func NewTupleConstant(vals ...Constant) Constant {
+	return Constant{typ: TupleFieldType, val: tuple(vals)}
+}
+
+// NewNullConstant constructs a NULL Constant.
+func NewNullConstant() Constant {
+	return Constant{}
+}
+
+// IsNull checks whether c is NULL or not.
+func (c Constant) IsNull() bool {
+	return c.val == nil
+}
+
+// Type returns the field type of c.
+func (c Constant) Type() FieldType {
+	return c.typ
+}
+
 // AsInt32 returns a value as int32.
 func (c Constant) AsInt32() (int32, error) {
 	v, ok := c.val.(int32)
@@ -37,13 +79,366 @@ func (c Constant) AsString() (string, error) {
 	return v, nil
 }
 
+// AsInterval returns a value as Interval.
+func (c Constant) AsInterval() (Interval, error) {
+	v, ok := c.val.(Interval)
+	if !ok {
+		return Interval{}, errors.New("AsInterval cannot convert Constant to Interval")
+	}
+
+	return v, nil
+}
+
+// AsDecimal returns the value of numeric types as Decimal.
+// 浮動小数点数は最短の 10 進表記を値とする.
+func (c Constant) AsDecimal() (Decimal, error) {
+	switch v := c.val.(type) {
+	case Decimal:
+		return v, nil
+	case float32:
+		return decimalFromFloat(float64(v), 32)
+	case float64:
+		return decimalFromFloat(v, 64)
+	default:
+		n, err := c.asInt64()
+		if err != nil {
+			return Decimal{}, errors.New("AsDecimal cannot convert Constant to Decimal")
+		}
+
+		return newDecimal(big.NewInt(n), 0)
+	}
+}
+
+// asInt64 returns the value of integer types as int64.
+func (c Constant) asInt64() (int64, error) {
+	switch v := c.val.(type) {
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	default:
+		return 0, errors.New("asInt64 cannot convert Constant to int64")
+	}
+}
+
+// asFloat64 returns the value of numeric types as float64.
+func (c Constant) asFloat64() (float64, error) {
+	switch v := c.val.(type) {
+	case float32:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case Decimal:
+		return v.Float64(), nil
+	default:
+		n, err := c.asInt64()
+		if err != nil {
+			return 0, errors.New("asFloat64 cannot convert Constant to float64")
+		}
+
+		return float64(n), nil
+	}
+}
+
+// Bits returns the integer representation of c which is stored in pages.
+// int64 に収まらない型はないので, 固定長の型はすべて int64 で表せる.
+// 浮動小数点数は IEEE 754 の bit 列, BOOLEAN は 1 または 0 とする.
+// DATE は 1970-01-01 からの日数, TIMESTAMP は 1970-01-01 00:00:00 UTC からのマイクロ秒数とする.
+func (c Constant) Bits() (int64, error) {
+	switch v := c.val.(type) {
+	case int16:
+		return int64(v), nil
+	case int32:
+		return int64(v), nil
+	case int64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+
+		return 0, nil
+	case float32:
+		return int64(int32(stdmath.Float32bits(v))), nil
+	case float64:
+		return int64(stdmath.Float64bits(v)), nil
+	default:
+		return 0, errors.New("Bits cannot convert Constant to bits")
+	}
+}
+
+// NewConstantFromBits constructs a Constant of typ from the integer representation stored in pages.
+func NewConstantFromBits(typ FieldType, bits int64) Constant {
+	switch typ {
+	case Int16FieldType:
+		return NewConstant(typ, int16(bits))
+	case Int32FieldType:
+		return NewConstant(typ, int32(bits))
+	case Int64FieldType:
+		return NewConstant(typ, bits)
+	case BoolFieldType:
+		return NewConstant(typ, bits != 0)
+	case Float32FieldType:
+		return NewConstant(typ, stdmath.Float32frombits(uint32(bits)))
+	case Float64FieldType:
+		return NewConstant(typ, stdmath.Float64frombits(uint64(bits)))
+	case DateFieldType:
+		return NewConstant(typ, int32(bits))
+	case TimestampFieldType, TimestampTzFieldType:
+		return NewConstant(typ, bits)
+	case UnknownFieldType, StringFieldType, TupleFieldType, TextFieldType, BytesFieldType, IntervalFieldType, NumericFieldType:
+		panic(ErrUnsupportedFieldType)
+	default:
+		panic(ErrUnsupportedFieldType)
+	}
+}
+
+// ConvertTo converts the numeric or date/time value c into a value of typ.
+// 整数から整数への変換は範囲外なら error とし, 浮動小数点数から整数へは変換しない.
+// 日時の型へは日時の値と文字列 literal を変換する.
+// NUMERIC へは数値と文字列 literal を変換する. field の scale への丸めは行わない.
+// それ以外は変換せずに c を返す.
+func (c Constant) ConvertTo(typ FieldType) (Constant, error) {
+	if c.IsNull() || c.typ == typ {
+		return c, nil
+	}
+
+	if typ.IsDatetime() || typ == IntervalFieldType {
+		switch {
+		case c.typ == StringFieldType:
+			return ParseConstant(typ, c.val.(string))
+		case c.typ.IsDatetime() && typ.IsDatetime():
+			return c.convertDatetime(typ)
+		default:
+			return c, nil
+		}
+	}
+
+	if typ == NumericFieldType && c.typ == StringFieldType {
+		return ParseConstant(typ, c.val.(string))
+	}
+
+	if !c.typ.IsNumeric() || !typ.IsNumeric() {
+		return c, nil
+	}
+
+	if typ == NumericFieldType {
+		d, err := c.AsDecimal()
+		if err != nil {
+			return Constant{}, err
+		}
+
+		return NewDecimalConstant(d), nil
+	}
+
+	if typ.IsFloat() {
+		f, err := c.asFloat64()
+		if err != nil {
+			return Constant{}, err
+		}
+		if typ == Float32FieldType {
+			if stdmath.Abs(f) > stdmath.MaxFloat32 && !stdmath.IsInf(f, 0) {
+				return Constant{}, fmt.Errorf("%w: %v", ErrNumericOutOfRange, c)
+			}
+
+			return NewConstant(typ, float32(f)), nil
+		}
+
+		return NewConstant(typ, f), nil
+	}
+
+	if !c.typ.IsInteger() {
+		return Constant{}, fmt.Errorf("%w: cannot convert %v to integer", ErrTypeMismatch, c)
+	}
+
+	n, err := c.asInt64()
+	if err != nil {
+		return Constant{}, err
+	}
+
+	return newIntegerConstant(typ, n)
+}
+
+// newIntegerConstant constructs a Constant of the integer type typ.
+// n が typ の範囲外の場合は error を返す.
+func newIntegerConstant(typ FieldType, n int64) (Constant, error) {
+	switch typ {
+	case Int16FieldType:
+		if n < stdmath.MinInt16 || stdmath.MaxInt16 < n {
+			return Constant{}, fmt.Errorf("%w: smallint %v", ErrNumericOutOfRange, n)
+		}
+
+		return NewConstant(typ, int16(n)), nil
+	case Int32FieldType:
+		if n < stdmath.MinInt32 || stdmath.MaxInt32 < n {
+			return Constant{}, fmt.Errorf("%w: integer %v", ErrNumericOutOfRange, n)
+		}
+
+		return NewConstant(typ, int32(n)), nil
+	case Int64FieldType:
+		return NewConstant(typ, n), nil
+	default:
+		return Constant{}, ErrUnsupportedFieldType
+	}
+}
+
+// ParseConstant parses the stringified value of typ.
+// 文字列の型は s をそのまま文字列の Constant とする.
+func ParseConstant(typ FieldType, s string) (Constant, error) {
+	switch typ {
+	case Int16FieldType, Int32FieldType, Int64FieldType:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return Constant{}, err
+		}
+
+		return newIntegerConstant(typ, n)
+	case BoolFieldType:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return Constant{}, err
+		}
+
+		return NewConstant(typ, b), nil
+	case Float32FieldType:
+		f, err := strconv.ParseFloat(s, 32)
+		if err != nil {
+			return Constant{}, err
+		}
+
+		return NewConstant(typ, float32(f)), nil
+	case Float64FieldType:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return Constant{}, err
+		}
+
+		return NewConstant(typ, f), nil
+	case DateFieldType, TimestampFieldType, TimestampTzFieldType:
+		return parseTimestamp(typ, s)
+	case IntervalFieldType:
+		itv, err := ParseInterval(s)
+		if err != nil {
+			return Constant{}, err
+		}
+
+		return NewIntervalConstant(itv), nil
+	case NumericFieldType:
+		d, err := ParseDecimal(s)
+		if err != nil {
+			return Constant{}, err
+		}
+
+		return NewDecimalConstant(d), nil
+	case UnknownFieldType, StringFieldType, TupleFieldType, TextFieldType, BytesFieldType:
+		return NewConstant(StringFieldType, s), nil
+	default:
+		return NewConstant(StringFieldType, s), nil
+	}
+}
+
+// ParseBytes converts the string literal into the bytes of BYTEA.
+// PostgreSQL と同様に \x で始まる場合は 16 進数として読み, それ以外はそのまま bytes とする.
+func ParseBytes(s string) (string, error) {
+	if !strings.HasPrefix(s, bytesHexPrefix) {
+		return s, nil
+	}
+
+	b, err := hex.DecodeString(strings.TrimPrefix(s, bytesHexPrefix))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidBytes, err)
+	}
+
+	return string(b), nil
+}
+
+// Components returns the values of composite key.
+// composite key でなければ c だけを返す.
+func (c Constant) Components() []Constant {
+	if t, ok := c.val.(tuple); ok {
+		return t
+	}
+
+	return []Constant{c}
+}
+
 // String stringfies constant.
 func (c Constant) String() string {
+	if c.IsNull() {
+		return "null"
+	}
+
+	if t, ok := c.val.(tuple); ok {
+		strs := make([]string, 0, len(t))
+		for _, v := range t {
+			strs = append(strs, v.String())
+		}
+
+		return "(" + strings.Join(strs, ", ") + ")"
+	}
+
+	if c.typ == BytesFieldType {
+		return bytesHexPrefix + hex.EncodeToString([]byte(c.val.(string)))
+	}
+
+	if c.typ.IsDatetime() {
+		return c.formatDatetime()
+	}
+
+	switch v := c.val.(type) {
+	case float32:
+		return formatFloat(float64(v), 32)
+	case float64:
+		return formatFloat(v, 64)
+	}
+
 	return fmt.Sprintf("%v", c.val)
 }
 
+// formatFloat formats f in the same way as PostgreSQL.
+// 有効桁数 (REAL は 6 桁, DOUBLE PRECISION は 15 桁) を超える指数のときだけ指数表記にする.
+func formatFloat(f float64, bitSize int) string {
+	switch {
+	case stdmath.IsNaN(f):
+		return "NaN"
+	case stdmath.IsInf(f, 1):
+		return "Infinity"
+	case stdmath.IsInf(f, -1):
+		return "-Infinity"
+	}
+
+	digits := 15
+	if bitSize == 32 {
+		digits = 6
+	}
+
+	s := strconv.FormatFloat(f, 'e', -1, bitSize)
+	exp, _ := strconv.Atoi(s[strings.IndexByte(s, 'e')+1:])
+	if exp < -4 || exp >= digits {
+		return s
+	}
+
+	return strconv.FormatFloat(f, 'f', -1, bitSize)
+}
+
 // AsVal returns constant as any.
+// BYTEA は []byte, DATE と TIMESTAMP は time.Time, INTERVAL と NUMERIC は文字列として返す.
 func (c Constant) AsVal() any {
+	if c.IsNull() {
+		return nil
+	}
+
+	switch {
+	case c.typ == BytesFieldType:
+		return []byte(c.val.(string))
+	case c.typ.IsDatetime():
+		return c.asTime()
+	case c.typ == IntervalFieldType, c.typ == NumericFieldType:
+		return c.String()
+	}
+
 	return c.val
 }
 
@@ -51,7 +446,18 @@ func (c Constant) AsVal() any {
 func (c Constant) HashCode() int {
 	mod := 998244353
 
-	b := sha256.Sum256([]byte(fmt.Sprintf("%v", c)))
+	// Equal で等しい値は同じ hash 値になるように揃える.
+	key := fmt.Sprintf("%v", c)
+	switch {
+	case c.typ.IsDatetime():
+		key = strconv.FormatInt(c.asMicros(), 10)
+	case c.typ == IntervalFieldType:
+		key = fmt.Sprint(c.val.(Interval).normalize())
+	case c.typ == NumericFieldType:
+		key = c.val.(Decimal).normalize().String()
+	}
+
+	b := sha256.Sum256([]byte(key))
 
 	x := 0
 	for _, v := range b {
@@ -63,11 +469,45 @@ func (c Constant) HashCode() int {
 }
 
 // Equal checks the equality of Constant.
+// 数値型どうし, 日時の型どうしは型が異なっても値で比較する.
 func (c Constant) Equal(other Constant) bool {
 	if c.typ != other.typ {
+		if c.IsNull() || other.IsNull() {
+			return false
+		}
+		if c.typ.IsNumeric() && other.typ.IsNumeric() {
+			return c.compareNumeric(other) == 0
+		}
+		if c.typ.IsDatetime() && other.typ.IsDatetime() {
+			return c.asMicros() == other.asMicros()
+		}
+
 		return false
 	}
 
+	if c.typ == IntervalFieldType && !c.IsNull() && !other.IsNull() {
+		return c.val.(Interval).compare(other.val.(Interval)) == 0
+	}
+
+	if c.typ == NumericFieldType && !c.IsNull() && !other.IsNull() {
+		return c.val.(Decimal).compare(other.val.(Decimal)) == 0
+	}
+
+	// slice は == で比較できないので要素ごとに比較する.
+	if c.typ == TupleFieldType {
+		x, y := c.Components(), other.Components()
+		if len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !x[i].Equal(y[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
 	if c.val != other.val {
 		return false
 	}
@@ -76,19 +516,97 @@ func (c Constant) Equal(other Constant) bool {
 }
 
 // Less check whether c is less than other or not.
+// NULL は他のどの値よりも大きいとみなす.
 func (c Constant) Less(other Constant) bool {
-	if c.typ != other.typ {
+	if c.IsNull() || other.IsNull() {
+		return !c.IsNull()
+	}
+
+	if !c.typ.isComparableWith(other.typ) {
 		panic(errors.New("compare different types"))
 	}
 
 	switch c.typ {
-	case Int32FieldType:
-		return c.val.(int32) < other.val.(int32)
-	case StringFieldType:
+	case Int16FieldType, Int32FieldType, Int64FieldType, Float32FieldType, Float64FieldType, NumericFieldType:
+		return c.compareNumeric(other) < 0
+	case BoolFieldType:
+		return !c.val.(bool) && other.val.(bool)
+	case StringFieldType, BytesFieldType:
 		return c.val.(string) < other.val.(string)
-	case UnknownFieldType:
+	case DateFieldType, TimestampFieldType, TimestampTzFieldType:
+		return c.asMicros() < other.asMicros()
+	case IntervalFieldType:
+		return c.val.(Interval).compare(other.val.(Interval)) < 0
+	case TupleFieldType:
+		return c.lessTuple(other)
+	case UnknownFieldType, TextFieldType:
 		panic(ErrUnsupportedFieldType)
 	default:
 		panic(ErrUnsupportedFieldType)
 	}
 }
+
+// compareNumeric compares the numeric values c and other.
+// どちらも整数なら int64 で, NUMERIC を含む場合は Decimal で, それ以外は float64 で比較する.
+func (c Constant) compareNumeric(other Constant) int {
+	if c.typ.IsInteger() && other.typ.IsInteger() {
+		x, _ := c.asInt64()
+		y, _ := other.asInt64()
+
+		return math.Compare(x, y)
+	}
+
+	if c.typ == NumericFieldType || other.typ == NumericFieldType {
+		x, err1 := c.AsDecimal()
+		y, err2 := other.AsDecimal()
+		// 無限大の浮動小数点数は Decimal にできないので float64 で比較する.
+		if err1 == nil && err2 == nil {
+			return x.compare(y)
+		}
+	}
+
+	x, _ := c.asFloat64()
+	y, _ := other.asFloat64()
+
+	return math.Compare(x, y)
+}
+
+// lessTuple compares composite keys in lexicographic order.
+// 共通部分が等しい場合は短い方を小さいとみなす.
+func (c Constant) lessTuple(other Constant) bool {
+	x, y := c.Components(), other.Components()
+	for i := 0; i < len(x) && i < len(y); i++ {
+		if !x[i].Equal(y[i]) {
+			return x[i].Less(y[i])
+		}
+	}
+
+	return len(x) < len(y)
+}
+
+// comparePrefix compares c with other by the first len(other) values if both are composite keys.
+// composite index の先頭の field だけで範囲を指定するために使う.
+func (c Constant) comparePrefix(other Constant) int {
+	if c.typ == TupleFieldType && other.typ == TupleFieldType {
+		x, y := c.Components(), other.Components()
+		if len(y) < len(x) {
+			return NewTupleConstant(x[:len(y)]...).Compare(other)
+		}
+	}
+
+	return c.Compare(other)
+}
+
+// Compare compares c with other.
+// It returns -1 if c is less than other, 0 if c equals other and 1 otherwise.
+func (c Constant) Compare(other Constant) int {
+	if c.Equal(other) {
+		return 0
+	}
+
+	if c.Less(other) {
+		return -1
+	}
+
+	return 1
+}
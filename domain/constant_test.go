@@ -0,0 +1,191 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstant_Compare_tuple(t *testing.T) {
+	i := func(v int32) domain.Constant {
+		return domain.NewConstant(domain.Int32FieldType, v)
+	}
+	s := func(v string) domain.Constant {
+		return domain.NewConstant(domain.StringFieldType, v)
+	}
+
+	tests := []struct {
+		name     string
+		lhs      domain.Constant
+		rhs      domain.Constant
+		expected int
+	}{
+		{name: "equal", lhs: domain.NewTupleConstant(i(1), s("a")), rhs: domain.NewTupleConstant(i(1), s("a")), expected: 0},
+		{name: "first value", lhs: domain.NewTupleConstant(i(1), s("z")), rhs: domain.NewTupleConstant(i(2), s("a")), expected: -1},
+		{name: "second value", lhs: domain.NewTupleConstant(i(1), s("b")), rhs: domain.NewTupleConstant(i(1), s("a")), expected: 1},
+		{name: "prefix is less", lhs: domain.NewTupleConstant(i(1)), rhs: domain.NewTupleConstant(i(1), s("a")), expected: -1},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.lhs.Compare(tt.rhs))
+			require.Equal(t, -tt.expected, tt.rhs.Compare(tt.lhs))
+			require.Equal(t, tt.expected == 0, tt.lhs.Equal(tt.rhs))
+		})
+	}
+
+	require.Equal(t, "(1, a)", domain.NewTupleConstant(i(1), s("a")).String())
+	require.Equal(t, []domain.Constant{i(1), s("a")}, domain.NewTupleConstant(i(1), s("a")).Components())
+	require.Equal(t, []domain.Constant{i(1)}, i(1).Components())
+}
+
+func TestParseBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		err      error
+	}{
+		{name: "hex format", input: `\xdeadbeef`, expected: "\xde\xad\xbe\xef"},
+		{name: "empty hex", input: `\x`, expected: ""},
+		{name: "raw bytes", input: "abc", expected: "abc"},
+		{name: "invalid hex", input: `\xzz`, err: domain.ErrInvalidBytes},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := domain.ParseBytes(tt.input)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+
+			c := domain.NewConstant(domain.BytesFieldType, actual)
+			require.Equal(t, []byte(tt.expected), c.AsVal())
+		})
+	}
+
+	require.Equal(t, `\x00ff`, domain.NewConstant(domain.BytesFieldType, "\x00\xff").String())
+}
+
+func TestConstant_ConvertTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		val      domain.Constant
+		typ      domain.FieldType
+		expected domain.Constant
+		err      error
+	}{
+		{name: "int to bigint", val: domain.NewConstant(domain.Int32FieldType, int32(-3)), typ: domain.Int64FieldType, expected: domain.NewConstant(domain.Int64FieldType, int64(-3))},
+		{name: "bigint to smallint", val: domain.NewConstant(domain.Int64FieldType, int64(300)), typ: domain.Int16FieldType, expected: domain.NewConstant(domain.Int16FieldType, int16(300))},
+		{name: "int to double", val: domain.NewConstant(domain.Int32FieldType, int32(2)), typ: domain.Float64FieldType, expected: domain.NewConstant(domain.Float64FieldType, float64(2))},
+		{name: "double to real", val: domain.NewConstant(domain.Float64FieldType, 1.5), typ: domain.Float32FieldType, expected: domain.NewConstant(domain.Float32FieldType, float32(1.5))},
+		{name: "string is not converted", val: domain.NewConstant(domain.StringFieldType, "1"), typ: domain.Int32FieldType, expected: domain.NewConstant(domain.StringFieldType, "1")},
+		{name: "null", val: domain.NewNullConstant(), typ: domain.Int64FieldType, expected: domain.NewNullConstant()},
+		{name: "smallint out of range", val: domain.NewConstant(domain.Int32FieldType, int32(40000)), typ: domain.Int16FieldType, err: domain.ErrNumericOutOfRange},
+		{name: "int out of range", val: domain.NewConstant(domain.Int64FieldType, int64(1)<<40), typ: domain.Int32FieldType, err: domain.ErrNumericOutOfRange},
+		{name: "real out of range", val: domain.NewConstant(domain.Float64FieldType, 1e300), typ: domain.Float32FieldType, err: domain.ErrNumericOutOfRange},
+		{name: "double to int", val: domain.NewConstant(domain.Float64FieldType, 1.5), typ: domain.Int32FieldType, err: domain.ErrTypeMismatch},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.val.ConvertTo(tt.typ)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestConstant_Bits(t *testing.T) {
+	tests := []domain.Constant{
+		domain.NewConstant(domain.Int16FieldType, int16(-12)),
+		domain.NewConstant(domain.Int32FieldType, int32(-123456)),
+		domain.NewConstant(domain.Int64FieldType, int64(-1)<<50),
+		domain.NewConstant(domain.BoolFieldType, true),
+		domain.NewConstant(domain.BoolFieldType, false),
+		domain.NewConstant(domain.Float32FieldType, float32(-2.5)),
+		domain.NewConstant(domain.Float64FieldType, 3.14159),
+	}
+
+	for _, c := range tests {
+		c := c
+		t.Run(c.String(), func(t *testing.T) {
+			bits, err := c.Bits()
+			require.NoError(t, err)
+			require.Equal(t, c, domain.NewConstantFromBits(c.Type(), bits))
+		})
+	}
+
+	_, err := domain.NewConstant(domain.StringFieldType, "a").Bits()
+	require.Error(t, err)
+}
+
+func TestParseConstant(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      domain.FieldType
+		input    string
+		expected domain.Constant
+		err      bool
+	}{
+		{name: "smallint", typ: domain.Int16FieldType, input: "-7", expected: domain.NewConstant(domain.Int16FieldType, int16(-7))},
+		{name: "bigint", typ: domain.Int64FieldType, input: "9223372036854775807", expected: domain.NewConstant(domain.Int64FieldType, int64(9223372036854775807))},
+		{name: "boolean", typ: domain.BoolFieldType, input: "true", expected: domain.NewConstant(domain.BoolFieldType, true)},
+		{name: "real", typ: domain.Float32FieldType, input: "0.5", expected: domain.NewConstant(domain.Float32FieldType, float32(0.5))},
+		{name: "double", typ: domain.Float64FieldType, input: "1e-3", expected: domain.NewConstant(domain.Float64FieldType, 1e-3)},
+		{name: "varchar", typ: domain.StringFieldType, input: "abc", expected: domain.NewConstant(domain.StringFieldType, "abc")},
+		{name: "smallint out of range", typ: domain.Int16FieldType, input: "40000", err: true},
+		{name: "invalid boolean", typ: domain.BoolFieldType, input: "yes!", err: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := domain.ParseConstant(tt.typ, tt.input)
+			if tt.err {
+				require.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestConstant_Compare_numeric(t *testing.T) {
+	tests := []struct {
+		name     string
+		lhs      domain.Constant
+		rhs      domain.Constant
+		expected int
+	}{
+		{name: "int and bigint", lhs: domain.NewConstant(domain.Int32FieldType, int32(1)), rhs: domain.NewConstant(domain.Int64FieldType, int64(1)), expected: 0},
+		{name: "smallint and bigint", lhs: domain.NewConstant(domain.Int16FieldType, int16(-1)), rhs: domain.NewConstant(domain.Int64FieldType, int64(1)<<40), expected: -1},
+		{name: "int and double", lhs: domain.NewConstant(domain.Int32FieldType, int32(2)), rhs: domain.NewConstant(domain.Float64FieldType, 1.5), expected: 1},
+		{name: "real and double", lhs: domain.NewConstant(domain.Float32FieldType, float32(0.5)), rhs: domain.NewConstant(domain.Float64FieldType, 0.5), expected: 0},
+		{name: "boolean", lhs: domain.NewConstant(domain.BoolFieldType, false), rhs: domain.NewConstant(domain.BoolFieldType, true), expected: -1},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.lhs.Compare(tt.rhs))
+			require.Equal(t, -tt.expected, tt.rhs.Compare(tt.lhs))
+			require.Equal(t, tt.expected == 0, tt.lhs.Equal(tt.rhs))
+		})
+	}
+}
@@ -0,0 +1,404 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+var (
+	// ErrMultiplePrimaryKeys is an error that means a table has more than one primary key.
+	ErrMultiplePrimaryKeys = errors.New("multiple primary keys are not allowed")
+
+	// ErrUnknownConstraintType is an error that means the constraint type is not supported.
+	ErrUnknownConstraintType = errors.New("unknown constraint type")
+
+	// ErrConstraintTooLong is an error that means DEFAULT or CHECK constraint is too long to store.
+	ErrConstraintTooLong = errors.New("constraint is too long")
+
+	// ErrNoMatchingUniqueKey is an error that means referenced fields are not a UNIQUE or PRIMARY KEY of the table.
+	ErrNoMatchingUniqueKey = errors.New("there is no unique constraint matching given keys for referenced table")
+
+	// ErrUnknownReferentialAction is an error that means the ON DELETE action is not supported.
+	ErrUnknownReferentialAction = errors.New("unknown referential action")
+)
+
+// ConstraintType is a kind of table constraint.
+type ConstraintType string
+
+const (
+	// PrimaryKeyConstraint is a type of PRIMARY KEY constraint.
+	PrimaryKeyConstraint ConstraintType = "p"
+
+	// UniqueConstraint is a type of UNIQUE constraint.
+	UniqueConstraint ConstraintType = "u"
+
+	// ForeignKeyConstraint is a type of FOREIGN KEY constraint.
+	ForeignKeyConstraint ConstraintType = "f"
+
+	// MaxConstraintTypeLength is maximum constraint type length.
+	MaxConstraintTypeLength = 1
+)
+
+// NewConstraintType constructs ConstraintType.
+func NewConstraintType(typ string) (ConstraintType, error) {
+	switch ConstraintType(typ) {
+	case PrimaryKeyConstraint, UniqueConstraint, ForeignKeyConstraint:
+		return ConstraintType(typ), nil
+	default:
+		return "", errors.Wrap(ErrUnknownConstraintType, typ)
+	}
+}
+
+// String stringfies constraint type.
+func (typ ConstraintType) String() string {
+	return string(typ)
+}
+
+// ReferentialAction is an action for the referencing records when a referenced record is deleted.
+type ReferentialAction string
+
+const (
+	// RestrictAction rejects deletion of the referenced record.
+	RestrictAction ReferentialAction = "r"
+
+	// CascadeAction deletes the referencing records too.
+	CascadeAction ReferentialAction = "c"
+
+	// SetNullAction sets NULL to the referencing fields.
+	SetNullAction ReferentialAction = "n"
+
+	// MaxReferentialActionLength is maximum referential action length.
+	MaxReferentialActionLength = 1
+)
+
+// NewReferentialAction constructs ReferentialAction.
+func NewReferentialAction(action string) (ReferentialAction, error) {
+	switch ReferentialAction(action) {
+	case RestrictAction, CascadeAction, SetNullAction:
+		return ReferentialAction(action), nil
+	default:
+		return "", errors.Wrap(ErrUnknownReferentialAction, action)
+	}
+}
+
+// String stringfies referential action.
+func (action ReferentialAction) String() string {
+	return string(action)
+}
+
+// Constraint is a UNIQUE, PRIMARY KEY or FOREIGN KEY constraint of a table.
+// 制約は同じ名前の btree index で検査するので, 名前は IndexName として扱う.
+// FOREIGN KEY の index は参照元の field に作り, 参照先の record を消すときに使う.
+type Constraint struct {
+	name     IndexName
+	typ      ConstraintType
+	fldNames []FieldName
+
+	refTblName  TableName
+	refName     IndexName
+	refFldNames []FieldName
+	onDelete    ReferentialAction
+}
+
+// NewConstraint constructs a Constraint.
+// 名前は catalog に登録するときに付ける.
+func NewConstraint(typ ConstraintType, fldNames []FieldName) Constraint {
+	return Constraint{
+		typ:      typ,
+		fldNames: fldNames,
+	}
+}
+
+// NewForeignKeyConstraint constructs a FOREIGN KEY constraint.
+// refFldNames が空の場合は参照先の primary key を参照する.
+func NewForeignKeyConstraint(fldNames []FieldName, refTblName TableName, refFldNames []FieldName, onDelete ReferentialAction) Constraint {
+	return Constraint{
+		typ:         ForeignKeyConstraint,
+		fldNames:    fldNames,
+		refTblName:  refTblName,
+		refFldNames: refFldNames,
+		onDelete:    onDelete,
+	}
+}
+
+// WithName returns the constraint named name.
+func (c Constraint) WithName(name IndexName) Constraint {
+	c.name = name
+
+	return c
+}
+
+// Name returns the name of the constraint.
+func (c Constraint) Name() IndexName {
+	return c.name
+}
+
+// Type returns the type of the constraint.
+func (c Constraint) Type() ConstraintType {
+	return c.typ
+}
+
+// FieldNames returns the fields restricted by the constraint.
+func (c Constraint) FieldNames() []FieldName {
+	return c.fldNames
+}
+
+// WithReference returns the foreign key which references the UNIQUE or PRIMARY KEY constraint ref.
+func (c Constraint) WithReference(ref Constraint) Constraint {
+	c.refName = ref.name
+	c.refFldNames = ref.fldNames
+
+	return c
+}
+
+// RefTableName returns the table referenced by the foreign key.
+func (c Constraint) RefTableName() TableName {
+	return c.refTblName
+}
+
+// RefName returns the name of UNIQUE or PRIMARY KEY constraint referenced by the foreign key.
+func (c Constraint) RefName() IndexName {
+	return c.refName
+}
+
+// RefFieldNames returns the fields referenced by the foreign key.
+func (c Constraint) RefFieldNames() []FieldName {
+	return c.refFldNames
+}
+
+// OnDelete returns the action when a referenced record is deleted.
+func (c Constraint) OnDelete() ReferentialAction {
+	return c.onDelete
+}
+
+// UniqueViolationError is an error that means a record violates a UNIQUE or PRIMARY KEY constraint.
+type UniqueViolationError struct {
+	constraint Constraint
+	key        Constant
+}
+
+// NewUniqueViolationError constructs a UniqueViolationError.
+func NewUniqueViolationError(c Constraint, key Constant) *UniqueViolationError {
+	return &UniqueViolationError{
+		constraint: c,
+		key:        key,
+	}
+}
+
+// Constraint returns the violated constraint.
+func (e *UniqueViolationError) Constraint() Constraint {
+	return e.constraint
+}
+
+// Error implements error.
+func (e *UniqueViolationError) Error() string {
+	vals := make([]string, 0, len(e.constraint.fldNames))
+	for _, val := range e.key.Components() {
+		vals = append(vals, val.String())
+	}
+
+	return fmt.Sprintf("duplicate key value violates unique constraint %q: key (%v)=(%v) already exists",
+		e.constraint.name, joinFieldNames(e.constraint.fldNames), strings.Join(vals, ", "))
+}
+
+// ForeignKeyViolationError is an error that means a record violates a FOREIGN KEY constraint.
+// referenced が true の場合は, 参照されている record を消そうとしたことを表す.
+type ForeignKeyViolationError struct {
+	tblName    TableName
+	constraint Constraint
+	key        Constant
+	referenced bool
+}
+
+// NewForeignKeyViolationError constructs a ForeignKeyViolationError
+// which means the key of the referencing table tblName is not present in the referenced table.
+func NewForeignKeyViolationError(tblName TableName, c Constraint, key Constant) *ForeignKeyViolationError {
+	return &ForeignKeyViolationError{
+		tblName:    tblName,
+		constraint: c,
+		key:        key,
+	}
+}
+
+// NewReferencedKeyViolationError constructs a ForeignKeyViolationError
+// which means the key is still referenced from the table tblName.
+func NewReferencedKeyViolationError(tblName TableName, c Constraint, key Constant) *ForeignKeyViolationError {
+	return &ForeignKeyViolationError{
+		tblName:    tblName,
+		constraint: c,
+		key:        key,
+		referenced: true,
+	}
+}
+
+// Constraint returns the violated constraint.
+func (e *ForeignKeyViolationError) Constraint() Constraint {
+	return e.constraint
+}
+
+// Error implements error.
+func (e *ForeignKeyViolationError) Error() string {
+	vals := make([]string, 0, len(e.constraint.fldNames))
+	for _, val := range e.key.Components() {
+		vals = append(vals, val.String())
+	}
+
+	if e.referenced {
+		return fmt.Sprintf("update or delete on table %q violates foreign key constraint %q on table %q: key (%v)=(%v) is still referenced from table %q",
+			e.constraint.refTblName, e.constraint.name, e.tblName, joinFieldNames(e.constraint.refFldNames), strings.Join(vals, ", "), e.tblName)
+	}
+
+	return fmt.Sprintf("insert or update on table %q violates foreign key constraint %q: key (%v)=(%v) is not present in table %q",
+		e.tblName, e.constraint.name, joinFieldNames(e.constraint.fldNames), strings.Join(vals, ", "), e.constraint.refTblName)
+}
+
+func joinFieldNames(fldNames []FieldName) string {
+	flds := make([]string, 0, len(fldNames))
+	for _, fld := range fldNames {
+		flds = append(flds, fld.String())
+	}
+
+	return strings.Join(flds, ", ")
+}
+
+// FieldConstraint is NOT NULL, DEFAULT and CHECK constraints of a field.
+// DEFAULT が指定されていない場合の default 値は NULL.
+type FieldConstraint struct {
+	notNull bool
+	dflt    Constant
+	check   *Predicate
+}
+
+// NewFieldConstraint constructs a FieldConstraint without any constraint.
+func NewFieldConstraint() FieldConstraint {
+	return FieldConstraint{}
+}
+
+// WithNotNull returns the constraint with NOT NULL.
+func (c FieldConstraint) WithNotNull() FieldConstraint {
+	c.notNull = true
+
+	return c
+}
+
+// WithDefault returns the constraint whose default value is val.
+func (c FieldConstraint) WithDefault(val Constant) FieldConstraint {
+	c.dflt = val
+
+	return c
+}
+
+// WithCheck returns the constraint with CHECK (pred).
+// CHECK が複数ある場合はすべてを満たす必要がある.
+func (c FieldConstraint) WithCheck(pred *Predicate) FieldConstraint {
+	if c.check == nil {
+		c.check = pred
+	} else {
+		terms := make([]Term, 0, len(c.check.terms)+len(pred.terms))
+		terms = append(terms, c.check.terms...)
+		c.check = NewPredicate(append(terms, pred.terms...))
+	}
+
+	return c
+}
+
+// NotNull checks whether the field is NOT NULL or not.
+func (c FieldConstraint) NotNull() bool {
+	return c.notNull
+}
+
+// Default returns the default value of the field.
+func (c FieldConstraint) Default() Constant {
+	return c.dflt
+}
+
+// Check returns the predicate of CHECK constraint.
+func (c FieldConstraint) Check() (*Predicate, bool) {
+	return c.check, c.check != nil
+}
+
+// RenameField returns the constraint whose CHECK refers newName instead of oldName.
+func (c FieldConstraint) RenameField(oldName, newName FieldName) FieldConstraint {
+	if c.check != nil {
+		c.check = c.check.RenameField(oldName, newName)
+	}
+
+	return c
+}
+
+// CheckFieldConstraints checks NOT NULL and CHECK constraints of the current record of s.
+// CHECK は結果が Unknown の場合も満たすとみなす.
+func CheckFieldConstraints(sch *Schema, s Scanner) error {
+	for _, fld := range sch.fields {
+		c := sch.FieldConstraint(fld)
+		if c.notNull {
+			val, err := s.GetVal(fld)
+			if err != nil {
+				return errors.Err(err, "GetVal")
+			}
+			if val.IsNull() {
+				return NewNotNullViolationError(fld)
+			}
+		}
+
+		pred, ok := c.Check()
+		if !ok {
+			continue
+		}
+		t, err := pred.Evaluate(s)
+		if err != nil {
+			return errors.Err(err, "Evaluate")
+		}
+		if t == False {
+			return NewCheckViolationError(fld, pred)
+		}
+	}
+
+	return nil
+}
+
+// NotNullViolationError is an error that means NULL is stored in a NOT NULL field.
+type NotNullViolationError struct {
+	fld FieldName
+}
+
+// NewNotNullViolationError constructs a NotNullViolationError.
+func NewNotNullViolationError(fld FieldName) *NotNullViolationError {
+	return &NotNullViolationError{fld: fld}
+}
+
+// FieldName returns the field which violates the constraint.
+func (e *NotNullViolationError) FieldName() FieldName {
+	return e.fld
+}
+
+// Error implements error.
+func (e *NotNullViolationError) Error() string {
+	return fmt.Sprintf("null value in column %q violates not-null constraint", e.fld)
+}
+
+// CheckViolationError is an error that means a record doesn't satisfy CHECK constraint.
+type CheckViolationError struct {
+	fld   FieldName
+	check *Predicate
+}
+
+// NewCheckViolationError constructs a CheckViolationError.
+func NewCheckViolationError(fld FieldName, check *Predicate) *CheckViolationError {
+	return &CheckViolationError{
+		fld:   fld,
+		check: check,
+	}
+}
+
+// FieldName returns the field whose constraint is violated.
+func (e *CheckViolationError) FieldName() FieldName {
+	return e.fld
+}
+
+// Error implements error.
+func (e *CheckViolationError) Error() string {
+	return fmt.Sprintf("new row violates check constraint of column %q: %v", e.fld, e.check)
+}
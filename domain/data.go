@@ -10,9 +10,14 @@ type ExecData interface{}
 
 // QueryData is node of query.
 type QueryData struct {
-	fields []FieldName
-	tables []TableName
-	pred   *Predicate
+	fields  []FieldName
+	tables  []TableName
+	joins   []*JoinData
+	pred    *Predicate
+	exprs   map[FieldName]Expression
+	groupBy []FieldName
+	aggs    []Aggregation
+	orderBy []SortKey
 }
 
 // NewQueryData constructs a QueryData.
@@ -34,16 +39,79 @@ func (data *QueryData) Tables() []TableName {
 	return data.tables
 }
 
+// Joins returns join clauses.
+// join は tables の product に左から順に適用する.
+func (data *QueryData) Joins() []*JoinData {
+	return data.joins
+}
+
+// SetJoins sets join clauses.
+func (data *QueryData) SetJoins(joins []*JoinData) {
+	data.joins = joins
+}
+
 // Predicate returns predicate.
 func (data *QueryData) Predicate() *Predicate {
 	return data.pred
 }
 
+// Expressions returns computed fields in select list.
+// key は select list 上の field name で、fields にも含まれる.
+func (data *QueryData) Expressions() map[FieldName]Expression {
+	return data.exprs
+}
+
+// SetExpressions sets computed fields in select list.
+func (data *QueryData) SetExpressions(exprs map[FieldName]Expression) {
+	data.exprs = exprs
+}
+
+// GroupBy returns fields of group by clause.
+func (data *QueryData) GroupBy() []FieldName {
+	return data.groupBy
+}
+
+// SetGroupBy sets fields of group by clause.
+func (data *QueryData) SetGroupBy(fields []FieldName) {
+	data.groupBy = fields
+}
+
+// Aggregations returns aggregation functions in select list.
+func (data *QueryData) Aggregations() []Aggregation {
+	return data.aggs
+}
+
+// SetAggregations sets aggregation functions in select list.
+func (data *QueryData) SetAggregations(aggs []Aggregation) {
+	data.aggs = aggs
+}
+
+// OrderBy returns sort keys of order by clause.
+func (data *QueryData) OrderBy() []SortKey {
+	return data.orderBy
+}
+
+// SetOrderBy sets sort keys of order by clause.
+func (data *QueryData) SetOrderBy(keys []SortKey) {
+	data.orderBy = keys
+}
+
 // String stringfies data.
 func (data *QueryData) String() string {
+	aggs := make(map[FieldName]Aggregation)
+	for _, agg := range data.aggs {
+		aggs[agg.FieldName()] = agg
+	}
+
 	fields := make([]string, 0, len(data.fields))
 	for _, f := range data.fields {
-		fields = append(fields, f.String())
+		if agg, ok := aggs[f]; ok {
+			fields = append(fields, agg.String())
+		} else if expr, ok := data.exprs[f]; ok {
+			fields = append(fields, expressionWithAlias(expr, f))
+		} else {
+			fields = append(fields, f.String())
+		}
 	}
 
 	tables := make([]string, 0, len(data.tables))
@@ -53,13 +121,107 @@ func (data *QueryData) String() string {
 
 	query := fmt.Sprintf("select %v from %v ", strings.Join(fields, ","), strings.Join(tables, ","))
 
+	for _, join := range data.joins {
+		query += " " + join.String()
+	}
+
 	if pred := data.pred.String(); pred != "" {
-		return query + " where " + pred
+		query += " where " + pred
+	}
+
+	if len(data.groupBy) > 0 {
+		flds := make([]string, 0, len(data.groupBy))
+		for _, fld := range data.groupBy {
+			flds = append(flds, fld.String())
+		}
+		query += " group by " + strings.Join(flds, ",")
+	}
+
+	if len(data.orderBy) > 0 {
+		keys := make([]string, 0, len(data.orderBy))
+		for _, key := range data.orderBy {
+			keys = append(keys, key.String())
+		}
+		query += " order by " + strings.Join(keys, ",")
 	}
 
 	return query
 }
 
+// expressionWithAlias stringfies computed field.
+// alias が省略されている場合は expression の文字列が field name になる.
+func expressionWithAlias(expr Expression, fld FieldName) string {
+	if str := expr.String(); str != fld.String() {
+		return str + " as " + fld.String()
+	}
+
+	return fld.String()
+}
+
+// JoinType is a type of join.
+type JoinType uint
+
+const (
+	// InnerJoin is inner join.
+	InnerJoin JoinType = iota
+
+	// LeftOuterJoin is left outer join.
+	LeftOuterJoin
+
+	// RightOuterJoin is right outer join.
+	RightOuterJoin
+)
+
+// String stringfies the join type.
+func (typ JoinType) String() string {
+	switch typ {
+	case InnerJoin:
+		return "join"
+	case LeftOuterJoin:
+		return "left join"
+	case RightOuterJoin:
+		return "right join"
+	default:
+		return "unknown"
+	}
+}
+
+// JoinData is parse tree of join clause.
+type JoinData struct {
+	typ     JoinType
+	tblName TableName
+	pred    *Predicate
+}
+
+// NewJoinData constructs a JoinData.
+func NewJoinData(typ JoinType, tblName TableName, pred *Predicate) *JoinData {
+	return &JoinData{
+		typ:     typ,
+		tblName: tblName,
+		pred:    pred,
+	}
+}
+
+// Type returns the join type.
+func (data *JoinData) Type() JoinType {
+	return data.typ
+}
+
+// TableName returns the joined table name.
+func (data *JoinData) TableName() TableName {
+	return data.tblName
+}
+
+// Predicate returns the join condition.
+func (data *JoinData) Predicate() *Predicate {
+	return data.pred
+}
+
+// String stringfies data.
+func (data *JoinData) String() string {
+	return fmt.Sprintf("%v %v on %v", data.typ, data.tblName, data.pred)
+}
+
 // InsertData is parse tree of insert command.
 type InsertData struct {
 	tableName TableName
@@ -155,15 +317,19 @@ func (data *ModifyData) Predicate() *Predicate {
 
 // CreateTableData is parse tree of create table command.
 type CreateTableData struct {
-	tblName TableName
-	sch     *Schema
+	tblName     TableName
+	sch         *Schema
+	constraints []Constraint
+	format      RecordFormat
 }
 
 // NewCreateTableData constructs a CreateTableData.
-func NewCreateTableData(tblName TableName, sch *Schema) *CreateTableData {
+func NewCreateTableData(tblName TableName, sch *Schema, constraints []Constraint, format RecordFormat) *CreateTableData {
 	return &CreateTableData{
-		tblName: tblName,
-		sch:     sch,
+		tblName:     tblName,
+		sch:         sch,
+		constraints: constraints,
+		format:      format,
 	}
 }
 
@@ -177,6 +343,16 @@ func (data *CreateTableData) Schema() *Schema {
 	return data.sch
 }
 
+// Constraints returns UNIQUE and PRIMARY KEY constraints of the table.
+func (data *CreateTableData) Constraints() []Constraint {
+	return data.constraints
+}
+
+// Format returns the record format of the table.
+func (data *CreateTableData) Format() RecordFormat {
+	return data.format
+}
+
 // CreateViewData is parse tree of create view command.
 type CreateViewData struct {
 	viewName  ViewName
@@ -203,17 +379,19 @@ func (data *CreateViewData) ViewDef() ViewDef {
 
 // CreateIndexData is parse tree of create index command.
 type CreateIndexData struct {
-	idxName IndexName
-	tblName TableName
-	fldName FieldName
+	idxName  IndexName
+	tblName  TableName
+	fldNames []FieldName
+	idxType  IndexType
 }
 
 // NewCreateIndexData constructs a CreateIndexData.
-func NewCreateIndexData(idxName IndexName, tblName TableName, fldName FieldName) *CreateIndexData {
+func NewCreateIndexData(idxName IndexName, tblName TableName, fldNames []FieldName, idxType IndexType) *CreateIndexData {
 	return &CreateIndexData{
-		idxName: idxName,
-		tblName: tblName,
-		fldName: fldName,
+		idxName:  idxName,
+		tblName:  tblName,
+		fldNames: fldNames,
+		idxType:  idxType,
 	}
 }
 
@@ -227,7 +405,187 @@ func (data *CreateIndexData) TableName() TableName {
 	return data.tblName
 }
 
-// FieldName returns a field name.
-func (data *CreateIndexData) FieldName() FieldName {
+// FieldNames returns the key fields of the index.
+func (data *CreateIndexData) FieldNames() []FieldName {
+	return data.fldNames
+}
+
+// IndexType returns the type of index.
+func (data *CreateIndexData) IndexType() IndexType {
+	return data.idxType
+}
+
+// DropTableData is parse tree of drop table command.
+type DropTableData struct {
+	tblName  TableName
+	ifExists bool
+}
+
+// NewDropTableData constructs a DropTableData.
+func NewDropTableData(tblName TableName, ifExists bool) *DropTableData {
+	return &DropTableData{
+		tblName:  tblName,
+		ifExists: ifExists,
+	}
+}
+
+// TableName returns a table name.
+func (data *DropTableData) TableName() TableName {
+	return data.tblName
+}
+
+// IfExists checks whether IF EXISTS is specified or not.
+func (data *DropTableData) IfExists() bool {
+	return data.ifExists
+}
+
+// DropViewData is parse tree of drop view command.
+type DropViewData struct {
+	viewName ViewName
+	ifExists bool
+}
+
+// NewDropViewData constructs a DropViewData.
+func NewDropViewData(viewName ViewName, ifExists bool) *DropViewData {
+	return &DropViewData{
+		viewName: viewName,
+		ifExists: ifExists,
+	}
+}
+
+// ViewName returns a view name.
+func (data *DropViewData) ViewName() ViewName {
+	return data.viewName
+}
+
+// IfExists checks whether IF EXISTS is specified or not.
+func (data *DropViewData) IfExists() bool {
+	return data.ifExists
+}
+
+// DropIndexData is parse tree of drop index command.
+type DropIndexData struct {
+	idxName  IndexName
+	ifExists bool
+}
+
+// NewDropIndexData constructs a DropIndexData.
+func NewDropIndexData(idxName IndexName, ifExists bool) *DropIndexData {
+	return &DropIndexData{
+		idxName:  idxName,
+		ifExists: ifExists,
+	}
+}
+
+// IndexName returns index name.
+func (data *DropIndexData) IndexName() IndexName {
+	return data.idxName
+}
+
+// IfExists checks whether IF EXISTS is specified or not.
+func (data *DropIndexData) IfExists() bool {
+	return data.ifExists
+}
+
+// AlterTableAction is a kind of alter table command.
+type AlterTableAction uint
+
+const (
+	// AddColumnAction adds a field.
+	AddColumnAction AlterTableAction = iota
+
+	// DropColumnAction drops a field.
+	DropColumnAction
+
+	// RenameColumnAction renames a field.
+	RenameColumnAction
+
+	// RenameTableAction renames the table.
+	RenameTableAction
+)
+
+// AlterTableData is parse tree of alter table command.
+// action によって使う field が異なる.
+type AlterTableData struct {
+	tblName     TableName
+	action      AlterTableAction
+	fldName     FieldName
+	sch         *Schema
+	constraints []Constraint
+	newFldName  FieldName
+	newTblName  TableName
+}
+
+// NewAddColumnData constructs an AlterTableData which adds the field fldName defined in sch.
+func NewAddColumnData(tblName TableName, fldName FieldName, sch *Schema, constraints []Constraint) *AlterTableData {
+	return &AlterTableData{
+		tblName:     tblName,
+		action:      AddColumnAction,
+		fldName:     fldName,
+		sch:         sch,
+		constraints: constraints,
+	}
+}
+
+// NewDropColumnData constructs an AlterTableData which drops the field fldName.
+func NewDropColumnData(tblName TableName, fldName FieldName) *AlterTableData {
+	return &AlterTableData{
+		tblName: tblName,
+		action:  DropColumnAction,
+		fldName: fldName,
+	}
+}
+
+// NewRenameColumnData constructs an AlterTableData which renames the field fldName to newFldName.
+func NewRenameColumnData(tblName TableName, fldName, newFldName FieldName) *AlterTableData {
+	return &AlterTableData{
+		tblName:    tblName,
+		action:     RenameColumnAction,
+		fldName:    fldName,
+		newFldName: newFldName,
+	}
+}
+
+// NewRenameTableData constructs an AlterTableData which renames the table to newTblName.
+func NewRenameTableData(tblName, newTblName TableName) *AlterTableData {
+	return &AlterTableData{
+		tblName:    tblName,
+		action:     RenameTableAction,
+		newTblName: newTblName,
+	}
+}
+
+// TableName returns a table name.
+func (data *AlterTableData) TableName() TableName {
+	return data.tblName
+}
+
+// Action returns the kind of the command.
+func (data *AlterTableData) Action() AlterTableAction {
+	return data.action
+}
+
+// FieldName returns the field to be added, dropped or renamed.
+func (data *AlterTableData) FieldName() FieldName {
 	return data.fldName
 }
+
+// Schema returns the schema which defines the added field.
+func (data *AlterTableData) Schema() *Schema {
+	return data.sch
+}
+
+// Constraints returns UNIQUE, PRIMARY KEY and FOREIGN KEY constraints of the added field.
+func (data *AlterTableData) Constraints() []Constraint {
+	return data.constraints
+}
+
+// NewFieldName returns the new name of the field.
+func (data *AlterTableData) NewFieldName() FieldName {
+	return data.newFldName
+}
+
+// NewTableName returns the new name of the table.
+func (data *AlterTableData) NewTableName() TableName {
+	return data.newTblName
+}
@@ -0,0 +1,517 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	stdmath "math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidDatetime is an error that means the string can't be read as date, timestamp or interval.
+	ErrInvalidDatetime = errors.New("invalid input syntax for date/time")
+
+	// ErrDatetimeOutOfRange is an error that means the date/time value is out of range.
+	ErrDatetimeOutOfRange = errors.New("date/time value out of range")
+)
+
+const (
+	microsPerSecond = int64(time.Second / time.Microsecond)
+	microsPerMinute = 60 * microsPerSecond
+	microsPerHour   = 60 * microsPerMinute
+	microsPerDay    = 24 * microsPerHour
+	secondsPerDay   = microsPerDay / microsPerSecond
+	daysPerMonth    = 30
+	monthsPerYear   = 12
+)
+
+// Interval is a value of INTERVAL.
+// PostgreSQL と同様に月, 日, マイクロ秒を別々に持つ. 月の日数は加算する日付によって変わるため.
+type Interval struct {
+	Months int32
+	Days   int32
+	Micros int64
+}
+
+// NewDateConstant constructs a DATE Constant of the day of t.
+func NewDateConstant(t time.Time) Constant {
+	y, m, d := t.Date()
+
+	return NewConstant(DateFieldType, int32(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix()/secondsPerDay))
+}
+
+// NewTimestampConstant constructs a TIMESTAMP or TIMESTAMP WITH TIME ZONE Constant.
+// TIMESTAMP は t の時刻を UTC の時刻とみなし, TIMESTAMP WITH TIME ZONE は t を UTC に変換する.
+func NewTimestampConstant(typ FieldType, t time.Time) Constant {
+	if typ == TimestampFieldType {
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	}
+
+	return NewConstant(typ, t.UnixMicro())
+}
+
+// NewIntervalConstant constructs an INTERVAL Constant.
+func NewIntervalConstant(itv Interval) Constant {
+	return NewConstant(IntervalFieldType, itv)
+}
+
+// datetimeLayouts are the formats of date and timestamp literals.
+var datetimeLayouts = []string{
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05Z07",
+	"2006-01-02 15:04:05-0700",
+	"2006-01-02T15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseTimestamp parses the literal of DATE or TIMESTAMP of typ.
+// TIMESTAMP と DATE は time zone の指定を無視し, TIMESTAMP WITH TIME ZONE は UTC に変換する.
+func parseTimestamp(typ FieldType, s string) (Constant, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range datetimeLayouts {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			continue
+		}
+
+		if typ == DateFieldType {
+			return NewDateConstant(t), nil
+		}
+
+		return NewTimestampConstant(typ, t), nil
+	}
+
+	return Constant{}, fmt.Errorf("%w: %q", ErrInvalidDatetime, s)
+}
+
+// asTime returns the DATE or TIMESTAMP value as time.Time in UTC.
+func (c Constant) asTime() time.Time {
+	return time.UnixMicro(c.asMicros()).UTC()
+}
+
+// asMicros returns the DATE or TIMESTAMP value as microseconds since 1970-01-01 00:00:00 UTC.
+// DATE はその日の 0 時とする.
+func (c Constant) asMicros() int64 {
+	switch v := c.val.(type) {
+	case int32:
+		return int64(v) * microsPerDay
+	case int64:
+		return v
+	default:
+		return 0
+	}
+}
+
+// formatDatetime formats the DATE or TIMESTAMP value in the same way as PostgreSQL.
+func (c Constant) formatDatetime() string {
+	t := c.asTime()
+	switch c.typ {
+	case DateFieldType:
+		return t.Format("2006-01-02")
+	case TimestampTzFieldType:
+		return t.Format("2006-01-02 15:04:05.999999") + "+00"
+	default:
+		return t.Format("2006-01-02 15:04:05.999999")
+	}
+}
+
+// convertDatetime converts the DATE or TIMESTAMP value c into a value of typ.
+// TIMESTAMP から DATE への変換では時刻を切り捨てる.
+func (c Constant) convertDatetime(typ FieldType) (Constant, error) {
+	if typ != DateFieldType {
+		return NewConstant(typ, c.asMicros()), nil
+	}
+
+	days := floorDiv(c.asMicros(), microsPerDay)
+	if days < stdmath.MinInt32 || stdmath.MaxInt32 < days {
+		return Constant{}, fmt.Errorf("%w: %v", ErrDatetimeOutOfRange, c)
+	}
+
+	return NewConstant(DateFieldType, int32(days)), nil
+}
+
+// floorDiv returns a / b rounded toward negative infinity.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+
+	return q
+}
+
+// compare compares intervals assuming that a month is 30 days and a day is 24 hours.
+// PostgreSQL と同様に '1 mon' と '30 days' は等しいとみなす.
+func (itv Interval) compare(other Interval) int {
+	x, y := itv.normalize(), other.normalize()
+	if x[0] != y[0] {
+		if x[0] < y[0] {
+			return -1
+		}
+
+		return 1
+	}
+
+	switch {
+	case x[1] < y[1]:
+		return -1
+	case x[1] > y[1]:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// normalize returns the length of itv as days and the remaining microseconds.
+// int64 のマイクロ秒だけでは溢れるので日数と 1 日未満の部分に分ける.
+func (itv Interval) normalize() [2]int64 {
+	days := int64(itv.Months)*daysPerMonth + int64(itv.Days) + floorDiv(itv.Micros, microsPerDay)
+
+	return [2]int64{days, itv.Micros - floorDiv(itv.Micros, microsPerDay)*microsPerDay}
+}
+
+// String formats itv in the postgres style of IntervalStyle.
+func (itv Interval) String() string {
+	parts := make([]string, 0, 4)
+	negative := false
+	unit := func(n int64, name string) {
+		if n == 0 {
+			return
+		}
+		plural := "s"
+		if n == 1 {
+			plural = ""
+		}
+		parts = append(parts, fmt.Sprintf("%d %s%s", n, name, plural))
+		negative = n < 0
+	}
+	unit(int64(itv.Months/monthsPerYear), "year")
+	unit(int64(itv.Months%monthsPerYear), "mon")
+	unit(int64(itv.Days), "day")
+
+	if itv.Micros == 0 && len(parts) > 0 {
+		return strings.Join(parts, " ")
+	}
+
+	// 前の部分が負で時刻が正の場合は符号を明示する.
+	sign := ""
+	micros := itv.Micros
+	switch {
+	case micros < 0:
+		sign = "-"
+	case negative:
+		sign = "+"
+	}
+
+	// -micros は MinInt64 で溢れるので uint64 で絶対値をとる.
+	abs := uint64(micros)
+	if micros < 0 {
+		abs = -abs
+	}
+	hours := abs / uint64(microsPerHour)
+	minutes := abs % uint64(microsPerHour) / uint64(microsPerMinute)
+	seconds := abs % uint64(microsPerMinute) / uint64(microsPerSecond)
+	frac := abs % uint64(microsPerSecond)
+
+	clock := fmt.Sprintf("%s%02d:%02d:%02d", sign, hours, minutes, seconds)
+	if frac != 0 {
+		clock += strings.TrimRight(fmt.Sprintf(".%06d", frac), "0")
+	}
+
+	return strings.Join(append(parts, clock), " ")
+}
+
+// intervalUnits are the units of interval literals and the corresponding lengths.
+// 月より長い単位は月数, 日以上の単位は日数, それ以外はマイクロ秒で表す.
+var intervalUnits = map[string]struct {
+	months int64
+	days   int64
+	micros int64
+}{
+	"year":         {months: monthsPerYear},
+	"years":        {months: monthsPerYear},
+	"mon":          {months: 1},
+	"mons":         {months: 1},
+	"month":        {months: 1},
+	"months":       {months: 1},
+	"week":         {days: 7},
+	"weeks":        {days: 7},
+	"day":          {days: 1},
+	"days":         {days: 1},
+	"hour":         {micros: microsPerHour},
+	"hours":        {micros: microsPerHour},
+	"minute":       {micros: microsPerMinute},
+	"minutes":      {micros: microsPerMinute},
+	"min":          {micros: microsPerMinute},
+	"mins":         {micros: microsPerMinute},
+	"second":       {micros: microsPerSecond},
+	"seconds":      {micros: microsPerSecond},
+	"sec":          {micros: microsPerSecond},
+	"secs":         {micros: microsPerSecond},
+	"millisecond":  {micros: 1000},
+	"milliseconds": {micros: 1000},
+	"ms":           {micros: 1000},
+	"microsecond":  {micros: 1},
+	"microseconds": {micros: 1},
+	"us":           {micros: 1},
+}
+
+// ParseInterval parses the literal of INTERVAL such as '1 year 2 mons', '3 days 04:05:06' and '1 hour ago'.
+// 小数の値は PostgreSQL と同様に 1 月を 30 日, 1 日を 24 時間として下の単位に繰り下げる.
+func ParseInterval(s string) (Interval, error) {
+	invalid := fmt.Errorf("%w: %q", ErrInvalidDatetime, s)
+
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 0 {
+		return Interval{}, invalid
+	}
+
+	ago := false
+	if fields[len(fields)-1] == "ago" {
+		ago = true
+		fields = fields[:len(fields)-1]
+	}
+
+	var months, days, micros float64
+	for i := 0; i < len(fields); i++ {
+		if strings.Contains(fields[i], ":") {
+			us, err := parseClock(fields[i])
+			if err != nil {
+				return Interval{}, invalid
+			}
+			micros += float64(us)
+
+			continue
+		}
+
+		n, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil || i+1 >= len(fields) {
+			return Interval{}, invalid
+		}
+		i++
+		u, ok := intervalUnits[fields[i]]
+		if !ok {
+			return Interval{}, invalid
+		}
+
+		m := n * float64(u.months)
+		months += stdmath.Trunc(m)
+		d := n*float64(u.days) + (m-stdmath.Trunc(m))*daysPerMonth
+		days += stdmath.Trunc(d)
+		micros += n*float64(u.micros) + (d-stdmath.Trunc(d))*float64(microsPerDay)
+	}
+
+	if ago {
+		months, days, micros = -months, -days, -micros
+	}
+
+	if stdmath.Abs(months) > stdmath.MaxInt32 || stdmath.Abs(days) > stdmath.MaxInt32 || stdmath.Abs(micros) >= stdmath.MaxInt64 {
+		return Interval{}, fmt.Errorf("%w: %q", ErrDatetimeOutOfRange, s)
+	}
+
+	return Interval{Months: int32(months), Days: int32(days), Micros: int64(stdmath.Round(micros))}, nil
+}
+
+// parseClock parses [-]HH:MM[:SS[.ffffff]] and returns it as microseconds.
+func parseClock(s string) (int64, error) {
+	sign := int64(1)
+	if strings.HasPrefix(s, "-") {
+		sign = -1
+		s = s[1:]
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, ErrInvalidDatetime
+	}
+
+	units := []int64{microsPerHour, microsPerMinute, microsPerSecond}
+	var ret int64
+	for i, part := range parts {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil || n < 0 || (i < len(parts)-1 && strings.Contains(part, ".")) {
+			return 0, ErrInvalidDatetime
+		}
+		ret += int64(stdmath.Round(n * float64(units[i])))
+	}
+
+	return sign * ret, nil
+}
+
+// hasDatetime checks whether types include DATE, TIMESTAMP or INTERVAL.
+func hasDatetime(types []FieldType) bool {
+	for _, typ := range types {
+		if typ.IsDatetime() || typ == IntervalFieldType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// datetimeArithmeticType returns the type of the result of op applied to values of types
+// which include date/time values.
+// 日付と整数の加減算は日付, 日時と INTERVAL の加減算は TIMESTAMP, 日時どうしの差は INTERVAL とする.
+// ただし日付どうしの差は日数の整数とする.
+func datetimeArithmeticType(op ExpressionOperator, types []FieldType) (FieldType, error) {
+	mismatch := fmt.Errorf("%w: operator %v cannot be applied to %v", ErrTypeMismatch, op, types)
+
+	// NULL との演算は相手の型とする.
+	if len(types) == 1 {
+		if op == NegateOperator && types[0] != IntervalFieldType {
+			return UnknownFieldType, mismatch
+		}
+
+		return types[0], nil
+	}
+
+	lhs, rhs := types[0], types[1]
+	if op == AddOperator && (lhs.IsInteger() || lhs == IntervalFieldType) {
+		// 加算は可換なので日時を左辺とする.
+		lhs, rhs = rhs, lhs
+	}
+
+	switch {
+	case op != AddOperator && op != SubtractOperator:
+		return UnknownFieldType, mismatch
+	case lhs == IntervalFieldType && rhs == IntervalFieldType:
+		return IntervalFieldType, nil
+	case lhs == DateFieldType && rhs.IsInteger():
+		return DateFieldType, nil
+	case lhs == DateFieldType && rhs == IntervalFieldType:
+		return TimestampFieldType, nil
+	case lhs.IsDatetime() && rhs == IntervalFieldType:
+		return lhs, nil
+	case op == SubtractOperator && lhs == DateFieldType && rhs == DateFieldType:
+		return Int32FieldType, nil
+	case op == SubtractOperator && lhs.IsDatetime() && rhs.IsDatetime():
+		return IntervalFieldType, nil
+	default:
+		return UnknownFieldType, mismatch
+	}
+}
+
+// applyDatetimeArithmetic applies op to vals which include date/time values and returns the value of typ.
+func applyDatetimeArithmetic(op ExpressionOperator, typ FieldType, vals []Constant) (Constant, error) {
+	if op == NegateOperator {
+		itv, _ := vals[0].val.(Interval)
+		neg, err := negateInterval(itv)
+		if err != nil {
+			return Constant{}, err
+		}
+
+		return NewIntervalConstant(neg), nil
+	}
+
+	lhs, rhs := vals[0], vals[1]
+	if op == AddOperator && (lhs.typ.IsInteger() || lhs.typ == IntervalFieldType) {
+		lhs, rhs = rhs, lhs
+	}
+
+	sign := int64(1)
+	if op == SubtractOperator {
+		sign = -1
+	}
+
+	switch {
+	case typ == IntervalFieldType && lhs.typ == IntervalFieldType:
+		x, _ := lhs.val.(Interval)
+		y, _ := rhs.val.(Interval)
+		if sign < 0 {
+			var err error
+			y, err = negateInterval(y)
+			if err != nil {
+				return Constant{}, err
+			}
+		}
+
+		return addIntervals(x, y)
+	case typ == IntervalFieldType:
+		// 日時どうしの差は日数と 1 日未満の時間で表す.
+		x, y := lhs.asMicros(), rhs.asMicros()
+		diff := x - y
+		if (y > 0 && diff > x) || (y < 0 && diff < x) {
+			return Constant{}, ErrDatetimeOutOfRange
+		}
+
+		return NewIntervalConstant(Interval{Days: int32(diff / microsPerDay), Micros: diff % microsPerDay}), nil
+	case typ == Int32FieldType:
+		x, _ := lhs.val.(int32)
+		y, _ := rhs.val.(int32)
+
+		return newIntegerConstant(Int32FieldType, int64(x)-int64(y))
+	case rhs.typ.IsInteger():
+		n, _ := rhs.asInt64()
+		x, _ := lhs.val.(int32)
+		days := int64(x) + sign*n
+		if days < stdmath.MinInt32 || stdmath.MaxInt32 < days {
+			return Constant{}, ErrDatetimeOutOfRange
+		}
+
+		return NewConstant(DateFieldType, int32(days)), nil
+	default:
+		itv, _ := rhs.val.(Interval)
+		us, err := addInterval(lhs.asMicros(), itv, sign)
+		if err != nil {
+			return Constant{}, err
+		}
+
+		return NewConstant(typ, us), nil
+	}
+}
+
+// addInterval adds sign * itv to the timestamp us.
+// 月の加算で日が月末を超える場合は月末に丸める (1 月 31 日 + 1 月 = 2 月末).
+func addInterval(us int64, itv Interval, sign int64) (int64, error) {
+	t := time.UnixMicro(us).UTC()
+
+	y, m, d := t.Date()
+	months := int64(y)*monthsPerYear + int64(m-1) + sign*int64(itv.Months)
+	year, month := int(floorDiv(months, monthsPerYear)), time.Month(months-floorDiv(months, monthsPerYear)*monthsPerYear+1)
+	if last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day(); d > last {
+		d = last
+	}
+
+	t = time.Date(year, month, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	t = t.AddDate(0, 0, int(sign*int64(itv.Days)))
+
+	// time.Time は年が大きすぎると UnixMicro が溢れる.
+	if t.Year() < -290000 || 290000 < t.Year() {
+		return 0, ErrDatetimeOutOfRange
+	}
+
+	base := t.UnixMicro()
+	ret := base + sign*itv.Micros
+	if (sign*itv.Micros > 0 && ret < base) || (sign*itv.Micros < 0 && ret > base) {
+		return 0, ErrDatetimeOutOfRange
+	}
+
+	return ret, nil
+}
+
+// addIntervals adds intervals component-wise.
+func addIntervals(x, y Interval) (Constant, error) {
+	months := int64(x.Months) + int64(y.Months)
+	days := int64(x.Days) + int64(y.Days)
+	micros := x.Micros + y.Micros
+	if months < stdmath.MinInt32 || stdmath.MaxInt32 < months || days < stdmath.MinInt32 || stdmath.MaxInt32 < days ||
+		(y.Micros > 0 && micros < x.Micros) || (y.Micros < 0 && micros > x.Micros) {
+		return Constant{}, ErrDatetimeOutOfRange
+	}
+
+	return NewIntervalConstant(Interval{Months: int32(months), Days: int32(days), Micros: micros}), nil
+}
+
+// negateInterval returns -itv.
+func negateInterval(itv Interval) (Interval, error) {
+	if itv.Months == stdmath.MinInt32 || itv.Days == stdmath.MinInt32 || itv.Micros == stdmath.MinInt64 {
+		return Interval{}, ErrDatetimeOutOfRange
+	}
+
+	return Interval{Months: -itv.Months, Days: -itv.Days, Micros: -itv.Micros}, nil
+}
@@ -0,0 +1,260 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) domain.Constant {
+	c, err := domain.ParseConstant(domain.DateFieldType, s)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+func timestamp(typ domain.FieldType, s string) domain.Constant {
+	c, err := domain.ParseConstant(typ, s)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+func interval(s string) domain.Constant {
+	c, err := domain.ParseConstant(domain.IntervalFieldType, s)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+func TestParseConstant_datetime(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      domain.FieldType
+		input    string
+		expected string
+		err      error
+	}{
+		{name: "date", typ: domain.DateFieldType, input: "2024-02-29", expected: "2024-02-29"},
+		{name: "date before epoch", typ: domain.DateFieldType, input: "1969-12-31", expected: "1969-12-31"},
+		{name: "date from timestamp", typ: domain.DateFieldType, input: "2024-02-29 23:59:59", expected: "2024-02-29"},
+		{name: "timestamp", typ: domain.TimestampFieldType, input: "2024-01-02 03:04:05", expected: "2024-01-02 03:04:05"},
+		{name: "timestamp with fraction", typ: domain.TimestampFieldType, input: "2024-01-02T03:04:05.25", expected: "2024-01-02 03:04:05.25"},
+		{name: "timestamp ignores zone", typ: domain.TimestampFieldType, input: "2024-01-02 03:04:05+09", expected: "2024-01-02 03:04:05"},
+		{name: "timestamp of date", typ: domain.TimestampFieldType, input: "2024-01-02", expected: "2024-01-02 00:00:00"},
+		{name: "timestamptz", typ: domain.TimestampTzFieldType, input: "2024-01-02 03:04:05+09:00", expected: "2024-01-01 18:04:05+00"},
+		{name: "timestamptz without zone", typ: domain.TimestampTzFieldType, input: "2024-01-02 03:04", expected: "2024-01-02 03:04:00+00"},
+		{name: "invalid date", typ: domain.DateFieldType, input: "2023-02-29", err: domain.ErrInvalidDatetime},
+		{name: "invalid timestamp", typ: domain.TimestampFieldType, input: "yesterday", err: domain.ErrInvalidDatetime},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := domain.ParseConstant(tt.typ, tt.input)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.typ, actual.Type())
+			require.Equal(t, tt.expected, actual.String())
+
+			// 出力した文字列は読み戻せる.
+			again, err := domain.ParseConstant(tt.typ, actual.String())
+			require.NoError(t, err)
+			require.Equal(t, actual, again)
+
+			bits, err := actual.Bits()
+			require.NoError(t, err)
+			require.Equal(t, actual, domain.NewConstantFromBits(tt.typ, bits))
+		})
+	}
+
+	require.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), timestamp(domain.TimestampFieldType, "2024-01-02 03:04:05").AsVal())
+	require.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), date("2024-01-02").AsVal())
+}
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected domain.Interval
+		str      string
+		err      error
+	}{
+		{name: "units", input: "1 year 2 mons 3 days", expected: domain.Interval{Months: 14, Days: 3}, str: "1 year 2 mons 3 days"},
+		{name: "clock", input: "1 day 04:05:06.5", expected: domain.Interval{Days: 1, Micros: 14706500000}, str: "1 day 04:05:06.5"},
+		{name: "hours", input: "36 hours", expected: domain.Interval{Micros: 36 * 3600000000}, str: "36:00:00"},
+		{name: "weeks", input: "2 weeks", expected: domain.Interval{Days: 14}, str: "14 days"},
+		{name: "fraction", input: "1.5 months", expected: domain.Interval{Months: 1, Days: 15}, str: "1 mon 15 days"},
+		{name: "ago", input: "1 day 2 hours ago", expected: domain.Interval{Days: -1, Micros: -7200000000}, str: "-1 days -02:00:00"},
+		{name: "mixed signs", input: "-1 days 01:00", expected: domain.Interval{Days: -1, Micros: 3600000000}, str: "-1 days +01:00:00"},
+		{name: "zero", input: "0 seconds", expected: domain.Interval{}, str: "00:00:00"},
+		{name: "unknown unit", input: "1 fortnight", err: domain.ErrInvalidDatetime},
+		{name: "missing unit", input: "3", err: domain.ErrInvalidDatetime},
+		{name: "empty", input: "", err: domain.ErrInvalidDatetime},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := domain.ParseInterval(tt.input)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+			require.Equal(t, tt.str, actual.String())
+
+			again, err := domain.ParseInterval(actual.String())
+			require.NoError(t, err)
+			require.Equal(t, actual, again)
+		})
+	}
+}
+
+func TestConstant_Compare_datetime(t *testing.T) {
+	tests := []struct {
+		name     string
+		lhs      domain.Constant
+		rhs      domain.Constant
+		expected int
+	}{
+		{name: "date", lhs: date("2024-01-01"), rhs: date("2024-01-02"), expected: -1},
+		{name: "date and timestamp", lhs: date("2024-01-02"), rhs: timestamp(domain.TimestampFieldType, "2024-01-02 00:00:00"), expected: 0},
+		{name: "timestamp and timestamptz", lhs: timestamp(domain.TimestampFieldType, "2024-01-02 10:00:00"), rhs: timestamp(domain.TimestampTzFieldType, "2024-01-02 09:00:00"), expected: 1},
+		{name: "interval of month and days", lhs: interval("1 mon"), rhs: interval("30 days"), expected: 0},
+		{name: "interval of day and hours", lhs: interval("1 day"), rhs: interval("25 hours"), expected: -1},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.lhs.Compare(tt.rhs))
+			require.Equal(t, -tt.expected, tt.rhs.Compare(tt.lhs))
+			require.Equal(t, tt.expected == 0, tt.lhs.Equal(tt.rhs))
+			if tt.expected == 0 {
+				require.Equal(t, tt.lhs.HashCode(), tt.rhs.HashCode())
+			}
+		})
+	}
+}
+
+func TestConstant_ConvertTo_datetime(t *testing.T) {
+	ts := timestamp(domain.TimestampFieldType, "2024-03-04 05:06:07")
+
+	actual, err := ts.ConvertTo(domain.DateFieldType)
+	require.NoError(t, err)
+	require.Equal(t, date("2024-03-04"), actual)
+
+	actual, err = date("2024-03-04").ConvertTo(domain.TimestampTzFieldType)
+	require.NoError(t, err)
+	require.Equal(t, timestamp(domain.TimestampTzFieldType, "2024-03-04 00:00:00+00"), actual)
+
+	actual, err = domain.NewConstant(domain.StringFieldType, "1 hour").ConvertTo(domain.IntervalFieldType)
+	require.NoError(t, err)
+	require.Equal(t, interval("60 minutes"), actual)
+
+	_, err = domain.NewConstant(domain.StringFieldType, "noon").ConvertTo(domain.DateFieldType)
+	require.ErrorIs(t, err, domain.ErrInvalidDatetime)
+}
+
+func TestExpression_Evaluate_datetime(t *testing.T) {
+	c := domain.NewConstExpression
+
+	tests := []struct {
+		name     string
+		expr     domain.Expression
+		expected domain.Constant
+	}{
+		{name: "date + integer", expr: domain.NewBinaryExpression(domain.AddOperator, c(date("2024-02-28")), intConst(2)), expected: date("2024-03-01")},
+		{name: "integer + date", expr: domain.NewBinaryExpression(domain.AddOperator, intConst(1), c(date("2023-12-31"))), expected: date("2024-01-01")},
+		{name: "date - integer", expr: domain.NewBinaryExpression(domain.SubtractOperator, c(date("2024-01-01")), intConst(1)), expected: date("2023-12-31")},
+		{name: "date - date", expr: domain.NewBinaryExpression(domain.SubtractOperator, c(date("2024-03-01")), c(date("2024-02-01"))), expected: domain.NewConstant(domain.Int32FieldType, int32(29))},
+		{name: "date + interval", expr: domain.NewBinaryExpression(domain.AddOperator, c(date("2024-01-31")), c(interval("1 mon 1 hour"))), expected: timestamp(domain.TimestampFieldType, "2024-02-29 01:00:00")},
+		{name: "timestamp - interval", expr: domain.NewBinaryExpression(domain.SubtractOperator, c(timestamp(domain.TimestampFieldType, "2024-03-31 00:00:00")), c(interval("1 mon"))), expected: timestamp(domain.TimestampFieldType, "2024-02-29 00:00:00")},
+		{name: "interval + timestamptz", expr: domain.NewBinaryExpression(domain.AddOperator, c(interval("1 day")), c(timestamp(domain.TimestampTzFieldType, "2024-01-01 12:00:00+00"))), expected: timestamp(domain.TimestampTzFieldType, "2024-01-02 12:00:00+00")},
+		{name: "timestamp - timestamp", expr: domain.NewBinaryExpression(domain.SubtractOperator, c(timestamp(domain.TimestampFieldType, "2024-01-03 01:00:00")), c(timestamp(domain.TimestampFieldType, "2024-01-01 00:00:00"))), expected: interval("2 days 01:00:00")},
+		{name: "interval + interval", expr: domain.NewBinaryExpression(domain.AddOperator, c(interval("1 day")), c(interval("1 mon 2 hours"))), expected: interval("1 mon 1 day 02:00:00")},
+		{name: "negate interval", expr: domain.NewNegateExpression(c(interval("1 day"))), expected: interval("-1 days")},
+		{name: "null", expr: domain.NewBinaryExpression(domain.AddOperator, c(date("2024-01-01")), c(domain.NewNullConstant())), expected: domain.NewNullConstant()},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.expr.Evaluate(nil)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected.Type(), actual.Type())
+			require.True(t, tt.expected.Equal(actual), actual.String())
+
+			typ, _, err := tt.expr.Type(domain.NewSchema())
+			require.NoError(t, err)
+			if !tt.expected.IsNull() {
+				require.Equal(t, tt.expected.Type(), typ)
+			}
+		})
+	}
+
+	errTests := []struct {
+		name string
+		expr domain.Expression
+		err  error
+	}{
+		{name: "date + date", expr: domain.NewBinaryExpression(domain.AddOperator, c(date("2024-01-01")), c(date("2024-01-01"))), err: domain.ErrTypeMismatch},
+		{name: "date * integer", expr: domain.NewBinaryExpression(domain.MultiplyOperator, c(date("2024-01-01")), intConst(2)), err: domain.ErrTypeMismatch},
+		{name: "integer - date", expr: domain.NewBinaryExpression(domain.SubtractOperator, intConst(1), c(date("2024-01-01"))), err: domain.ErrTypeMismatch},
+		{name: "negate date", expr: domain.NewNegateExpression(c(date("2024-01-01"))), err: domain.ErrTypeMismatch},
+		{name: "date out of range", expr: domain.NewBinaryExpression(domain.AddOperator, c(date("2024-01-01")), intConst(2147483647)), err: domain.ErrDatetimeOutOfRange},
+	}
+
+	for _, tt := range errTests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.expr.Evaluate(nil)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestExpression_String_datetime(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		expr     domain.Expression
+		expected string
+	}{
+		{expr: domain.NewConstExpression(date("2024-01-02")), expected: "date '2024-01-02'"},
+		{expr: domain.NewConstExpression(timestamp(domain.TimestampTzFieldType, "2024-01-02 03:04:05")), expected: "timestamp with time zone '2024-01-02 03:04:05+00'"},
+		{expr: domain.NewConstExpression(interval("1 day")), expected: "interval '1 day'"},
+		{expr: domain.NewBinaryExpression(domain.SubtractOperator, domain.NewNowExpression(now), domain.NewFieldNameExpression("a")), expected: "(now()-a)"},
+		{expr: domain.NewCurrentDateExpression(now), expected: "current_date"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.expected, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.expr.String())
+		})
+	}
+
+	actual, err := domain.NewNowExpression(now).Evaluate(nil)
+	require.NoError(t, err)
+	require.Equal(t, timestamp(domain.TimestampTzFieldType, "2024-01-02 03:04:05+00"), actual)
+
+	actual, err = domain.NewCurrentDateExpression(now).Evaluate(nil)
+	require.NoError(t, err)
+	require.Equal(t, date("2024-01-02"), actual)
+}
@@ -0,0 +1,386 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	stdmath "math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/goropikari/simpledbgo/common"
+	"github.com/goropikari/simpledbgo/math"
+)
+
+var (
+	// ErrInvalidNumeric is an error that means the string can't be read as NUMERIC.
+	ErrInvalidNumeric = errors.New("invalid input syntax for type numeric")
+
+	// ErrInvalidNumericType is an error that means the precision or the scale of NUMERIC is invalid.
+	ErrInvalidNumericType = fmt.Errorf("NUMERIC precision must be between 1 and %v, and scale must be between 0 and precision", MaxNumericPrecision)
+)
+
+const (
+	// MaxNumericPrecision is the maximum precision of NUMERIC.
+	// 係数を 128 bit の整数で保存するので 38 桁までとする.
+	MaxNumericPrecision = 38
+
+	// minDivisionScale is the minimum scale of the quotient of NUMERIC.
+	minDivisionScale = 16
+
+	// numericScaleBits is the number of bits for the scale in the length of NUMERIC field.
+	numericScaleBits = 16
+
+	// decimalLength is the byte length of NUMERIC value.
+	// 係数の上位 (int64), 下位 (int64), scale (int32) の順に保存する.
+	decimalLength = 2*common.Int64Length + common.Int32Length
+)
+
+var (
+	// maxCoefficient is the upper bound (exclusive) of the absolute value of coefficients.
+	maxCoefficient = pow10(MaxNumericPrecision)
+
+	// MinDecimal is the minimum value of Decimal.
+	MinDecimal = Decimal{Hi: -1 << 63, Lo: 0, Scale: 0}
+)
+
+// Decimal is an exact decimal number of NUMERIC.
+// 値は 係数 × 10^(-Scale) で, 係数は 128 bit の 2 の補数を上位 Hi と下位 Lo に分けて持つ.
+// map の key や == で比較できるように *big.Int は持たない.
+type Decimal struct {
+	Hi    int64
+	Lo    uint64
+	Scale int32
+}
+
+// NumericLength packs the precision and the scale of NUMERIC(precision, scale) into the length of the field.
+// 精度を指定しない NUMERIC の length は 0 とし, 値の scale のまま保存する.
+func NumericLength(precision, scale int) (int, error) {
+	if precision < 1 || MaxNumericPrecision < precision || scale < 0 || precision < scale {
+		return 0, ErrInvalidNumericType
+	}
+
+	return precision<<numericScaleBits | scale, nil
+}
+
+// NumericPrecisionScale unpacks the precision and the scale packed by NumericLength.
+// 精度が指定されていない場合は ok が false になる.
+func NumericPrecisionScale(length int) (precision, scale int, ok bool) {
+	if length <= 0 {
+		return 0, 0, false
+	}
+
+	return length >> numericScaleBits, length & (1<<numericScaleBits - 1), true
+}
+
+// NewDecimalConstant constructs a NUMERIC Constant.
+func NewDecimalConstant(d Decimal) Constant {
+	return NewConstant(NumericFieldType, d)
+}
+
+// newDecimal constructs a Decimal whose value is coef × 10^(-scale).
+func newDecimal(coef *big.Int, scale int) (Decimal, error) {
+	if new(big.Int).Abs(coef).Cmp(maxCoefficient) >= 0 {
+		return Decimal{}, fmt.Errorf("%w: numeric", ErrNumericOutOfRange)
+	}
+
+	lo := new(big.Int).And(coef, new(big.Int).SetUint64(stdmath.MaxUint64))
+
+	return Decimal{
+		Hi:    new(big.Int).Rsh(coef, 64).Int64(),
+		Lo:    lo.Uint64(),
+		Scale: int32(scale),
+	}, nil
+}
+
+// fitDecimal constructs a Decimal by rounding the fractional digits which exceed MaxNumericPrecision.
+// 整数部が MaxNumericPrecision 桁を超える場合は error を返す.
+func fitDecimal(coef *big.Int, scale int) (Decimal, error) {
+	if excess := numDigits(coef) - MaxNumericPrecision; excess > 0 {
+		n := math.Min(excess, scale)
+		coef = roundCoef(coef, n)
+		scale -= n
+	}
+
+	return newDecimal(coef, scale)
+}
+
+// ParseDecimal parses the string of decimal number such as 1.50, -0.25 or 1e+06.
+func ParseDecimal(s string) (Decimal, error) {
+	str := strings.TrimSpace(s)
+
+	exp := 0
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		e, err := strconv.Atoi(str[i+1:])
+		if err != nil {
+			return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidNumeric, s)
+		}
+		exp = e
+		str = str[:i]
+	}
+
+	neg := false
+	if str != "" && (str[0] == '+' || str[0] == '-') {
+		neg = str[0] == '-'
+		str = str[1:]
+	}
+
+	intPart, fracPart, _ := strings.Cut(str, ".")
+	digits := intPart + fracPart
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("%w: %q", ErrInvalidNumeric, s)
+	}
+
+	coef, _ := new(big.Int).SetString(digits, 10)
+	if neg {
+		coef.Neg(coef)
+	}
+
+	scale := len(fracPart) - exp
+	switch {
+	case coef.Sign() == 0:
+		scale = math.Min(math.Max(scale, 0), MaxNumericPrecision)
+	case scale < -MaxNumericPrecision:
+		return Decimal{}, fmt.Errorf("%w: %v", ErrNumericOutOfRange, s)
+	case scale < 0:
+		coef.Mul(coef, pow10(-scale))
+		scale = 0
+	case scale > MaxNumericPrecision:
+		coef = roundCoef(coef, scale-MaxNumericPrecision)
+		scale = MaxNumericPrecision
+	}
+
+	return fitDecimal(coef, scale)
+}
+
+// decimalFromFloat converts the floating-point number into Decimal.
+// 浮動小数点数の最短の 10 進表記を値とするので, 0.1 は 0.1 になる.
+func decimalFromFloat(f float64, bitSize int) (Decimal, error) {
+	if stdmath.IsNaN(f) || stdmath.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("%w: cannot convert %v to numeric", ErrNumericOutOfRange, f)
+	}
+
+	return ParseDecimal(strconv.FormatFloat(f, 'e', -1, bitSize))
+}
+
+// coef returns the coefficient of d.
+func (d Decimal) coef() *big.Int {
+	c := big.NewInt(d.Hi)
+	c.Lsh(c, 64)
+
+	return c.Or(c, new(big.Int).SetUint64(d.Lo))
+}
+
+// String stringfies d with its scale, e.g. 1.50.
+func (d Decimal) String() string {
+	coef := d.coef()
+	str := new(big.Int).Abs(coef).String()
+
+	if scale := int(d.Scale); scale > 0 {
+		if len(str) <= scale {
+			str = strings.Repeat("0", scale-len(str)+1) + str
+		}
+		str = str[:len(str)-scale] + "." + str[len(str)-scale:]
+	}
+
+	if coef.Sign() < 0 {
+		return "-" + str
+	}
+
+	return str
+}
+
+// Float64 returns the nearest float64 to d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+
+	return f
+}
+
+// rescale rounds d to scale.
+// scale を減らすときは 0 から遠い方へ四捨五入する.
+func (d Decimal) rescale(scale int) (Decimal, error) {
+	coef := d.coef()
+	if cur := int(d.Scale); scale >= cur {
+		coef.Mul(coef, pow10(scale-cur))
+	} else {
+		coef = roundCoef(coef, cur-scale)
+	}
+
+	return newDecimal(coef, scale)
+}
+
+// roundTo rounds d to the precision and the scale packed in length of NUMERIC field.
+// 整数部が precision - scale 桁に収まらない場合は error を返す.
+func (d Decimal) roundTo(length int) (Decimal, error) {
+	precision, scale, ok := NumericPrecisionScale(length)
+	if !ok {
+		return d, nil
+	}
+
+	r, err := d.rescale(scale)
+	if err != nil {
+		return Decimal{}, err
+	}
+	if numDigits(r.coef()) > precision {
+		return Decimal{}, fmt.Errorf("%w: numeric field overflow, precision %v, scale %v: %v", ErrNumericOutOfRange, precision, scale, d)
+	}
+
+	return r, nil
+}
+
+// normalize removes trailing zeros of the fractional part.
+// 1.50 と 1.5 のように scale だけが異なる値を同じ値にするために使う.
+func (d Decimal) normalize() Decimal {
+	coef := d.coef()
+	scale := int(d.Scale)
+	ten := big.NewInt(10)
+	q, r := new(big.Int), new(big.Int)
+	for scale > 0 {
+		q.QuoRem(coef, ten, r)
+		if r.Sign() != 0 {
+			break
+		}
+		coef.Set(q)
+		scale--
+	}
+
+	n, _ := newDecimal(coef, scale)
+
+	return n
+}
+
+// aligned returns the coefficients of d and other at the larger scale of them.
+func (d Decimal) aligned(other Decimal) (*big.Int, *big.Int, int) {
+	x, y := d.coef(), other.coef()
+	scale := int(math.Max(d.Scale, other.Scale))
+	x.Mul(x, pow10(scale-int(d.Scale)))
+	y.Mul(y, pow10(scale-int(other.Scale)))
+
+	return x, y, scale
+}
+
+// compare compares d with other.
+func (d Decimal) compare(other Decimal) int {
+	x, y, _ := d.aligned(other)
+
+	return x.Cmp(y)
+}
+
+func (d Decimal) add(other Decimal) (Decimal, error) {
+	x, y, scale := d.aligned(other)
+
+	return fitDecimal(x.Add(x, y), scale)
+}
+
+func (d Decimal) sub(other Decimal) (Decimal, error) {
+	x, y, scale := d.aligned(other)
+
+	return fitDecimal(x.Sub(x, y), scale)
+}
+
+func (d Decimal) mul(other Decimal) (Decimal, error) {
+	x, y := d.coef(), other.coef()
+
+	return fitDecimal(x.Mul(x, y), int(d.Scale+other.Scale))
+}
+
+// div divides d by other.
+// 商の scale は被演算子の scale と minDivisionScale の最大値とし, 最後の桁を四捨五入する.
+func (d Decimal) div(other Decimal) (Decimal, error) {
+	y := other.coef()
+	if y.Sign() == 0 {
+		return Decimal{}, ErrDivisionByZero
+	}
+
+	scale := int(math.Max(math.Max(d.Scale, other.Scale), minDivisionScale))
+	x := d.coef()
+	x.Mul(x, pow10(scale+int(other.Scale)-int(d.Scale)+1))
+	x.Quo(x, y)
+
+	return fitDecimal(roundCoef(x, 1), scale)
+}
+
+// mod returns the remainder of d divided by other, whose sign is the same as d.
+func (d Decimal) mod(other Decimal) (Decimal, error) {
+	x, y, scale := d.aligned(other)
+	if y.Sign() == 0 {
+		return Decimal{}, ErrDivisionByZero
+	}
+
+	return newDecimal(x.Rem(x, y), scale)
+}
+
+func (d Decimal) neg() (Decimal, error) {
+	x := d.coef()
+
+	return newDecimal(x.Neg(x), int(d.Scale))
+}
+
+// applyDecimalArithmetic applies op to vals as NUMERIC.
+func applyDecimalArithmetic(op ExpressionOperator, vals []Constant) (Constant, error) {
+	nums := make([]Decimal, 0, len(vals))
+	for _, v := range vals {
+		d, err := v.AsDecimal()
+		if err != nil {
+			return Constant{}, fmt.Errorf("%w: operator %v requires number: %v", ErrTypeMismatch, op, err)
+		}
+		nums = append(nums, d)
+	}
+
+	var ret Decimal
+	var err error
+	switch op {
+	case NegateOperator:
+		ret, err = nums[0].neg()
+	case AddOperator:
+		ret, err = nums[0].add(nums[1])
+	case SubtractOperator:
+		ret, err = nums[0].sub(nums[1])
+	case MultiplyOperator:
+		ret, err = nums[0].mul(nums[1])
+	case DivideOperator:
+		ret, err = nums[0].div(nums[1])
+	case ModuloOperator:
+		ret, err = nums[0].mod(nums[1])
+	case noOperator, ConcatOperator, NowOperator, CurrentDateOperator:
+		return Constant{}, fmt.Errorf("unexpected operator %v", op)
+	default:
+		return Constant{}, fmt.Errorf("unexpected operator %v", op)
+	}
+	if err != nil {
+		return Constant{}, err
+	}
+
+	return NewDecimalConstant(ret), nil
+}
+
+// pow10 returns 10^n.
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundCoef divides coef by 10^n and rounds half away from zero.
+func roundCoef(coef *big.Int, n int) *big.Int {
+	if n <= 0 {
+		return new(big.Int).Set(coef)
+	}
+
+	p := pow10(n)
+	q, r := new(big.Int).QuoRem(coef, p, new(big.Int))
+	if r.Abs(r).Lsh(r, 1).Cmp(p) >= 0 {
+		q.Add(q, big.NewInt(int64(coef.Sign())))
+	}
+
+	return q
+}
+
+// numDigits returns the number of decimal digits of the absolute value of n.
+// 0 は 0 桁とする.
+func numDigits(n *big.Int) int {
+	if n.Sign() == 0 {
+		return 0
+	}
+
+	return len(new(big.Int).Abs(n).String())
+}
@@ -0,0 +1,190 @@
+package domain_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func numeric(s string) domain.Constant {
+	c, err := domain.ParseConstant(domain.NumericFieldType, s)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		err      error
+	}{
+		{name: "keep scale", input: "1.50", expected: "1.50"},
+		{name: "negative", input: "-0.25", expected: "-0.25"},
+		{name: "plus sign", input: "+3", expected: "3"},
+		{name: "leading point", input: ".5", expected: "0.5"},
+		{name: "trailing point", input: "5.", expected: "5"},
+		{name: "zero with scale", input: "0.00", expected: "0.00"},
+		{name: "exponent", input: "1e+06", expected: "1000000"},
+		{name: "negative exponent", input: "2.5e-3", expected: "0.0025"},
+		{name: "max precision", input: "-99999999999999999999999999999999999999", expected: "-99999999999999999999999999999999999999"},
+		{name: "round excess scale", input: "0.123456789012345678901234567890123456789", expected: "0.12345678901234567890123456789012345679"},
+		{name: "too many digits", input: "100000000000000000000000000000000000000", err: domain.ErrNumericOutOfRange},
+		{name: "too large exponent", input: "1e39", err: domain.ErrNumericOutOfRange},
+		{name: "empty", input: "", err: domain.ErrInvalidNumeric},
+		{name: "two points", input: "1.2.3", err: domain.ErrInvalidNumeric},
+		{name: "not a number", input: "abc", err: domain.ErrInvalidNumeric},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := domain.ParseDecimal(tt.input)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual.String())
+		})
+	}
+}
+
+func TestNumericLength(t *testing.T) {
+	length, err := domain.NumericLength(10, 2)
+	require.NoError(t, err)
+	precision, scale, ok := domain.NumericPrecisionScale(length)
+	require.True(t, ok)
+	require.Equal(t, 10, precision)
+	require.Equal(t, 2, scale)
+
+	_, _, ok = domain.NumericPrecisionScale(0)
+	require.False(t, ok)
+
+	for _, ps := range [][2]int{{0, 0}, {39, 0}, {5, 6}, {5, -1}} {
+		_, err := domain.NumericLength(ps[0], ps[1])
+		require.ErrorIs(t, err, domain.ErrInvalidNumericType, ps)
+	}
+}
+
+func TestConstant_ConvertTo_numeric(t *testing.T) {
+	tests := []struct {
+		name     string
+		val      domain.Constant
+		typ      domain.FieldType
+		expected domain.Constant
+		err      error
+	}{
+		{name: "int to numeric", val: domain.NewConstant(domain.Int32FieldType, int32(42)), typ: domain.NumericFieldType, expected: numeric("42")},
+		{name: "double to numeric", val: domain.NewConstant(domain.Float64FieldType, 0.1), typ: domain.NumericFieldType, expected: numeric("0.1")},
+		{name: "real to numeric", val: domain.NewConstant(domain.Float32FieldType, float32(0.1)), typ: domain.NumericFieldType, expected: numeric("0.1")},
+		{name: "string to numeric", val: domain.NewConstant(domain.StringFieldType, "12.345"), typ: domain.NumericFieldType, expected: numeric("12.345")},
+		{name: "numeric to double", val: numeric("1.5"), typ: domain.Float64FieldType, expected: domain.NewConstant(domain.Float64FieldType, 1.5)},
+		{name: "numeric to int", val: numeric("1.5"), typ: domain.Int32FieldType, err: domain.ErrTypeMismatch},
+		{name: "infinity to numeric", val: domain.NewConstant(domain.Float64FieldType, math.Inf(1)), typ: domain.NumericFieldType, err: domain.ErrNumericOutOfRange},
+		{name: "invalid string", val: domain.NewConstant(domain.StringFieldType, "1,000"), typ: domain.NumericFieldType, err: domain.ErrInvalidNumeric},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.val.ConvertTo(tt.typ)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestConstant_Compare_decimal(t *testing.T) {
+	tests := []struct {
+		name     string
+		lhs      domain.Constant
+		rhs      domain.Constant
+		expected int
+	}{
+		{name: "different scale", lhs: numeric("1.50"), rhs: numeric("1.5"), expected: 0},
+		{name: "negative", lhs: numeric("-0.01"), rhs: numeric("0"), expected: -1},
+		{name: "large", lhs: numeric("12345678901234567890.5"), rhs: numeric("12345678901234567890.49"), expected: 1},
+		{name: "numeric and int", lhs: numeric("2.00"), rhs: domain.NewConstant(domain.Int32FieldType, int32(2)), expected: 0},
+		{name: "numeric and double", lhs: numeric("0.3"), rhs: domain.NewConstant(domain.Float64FieldType, 0.30000000000000004), expected: -1},
+		{name: "numeric and infinity", lhs: numeric("1e37"), rhs: domain.NewConstant(domain.Float64FieldType, math.Inf(1)), expected: -1},
+		{name: "min", lhs: domain.NewDecimalConstant(domain.MinDecimal), rhs: numeric("-99999999999999999999999999999999999999"), expected: -1},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.lhs.Compare(tt.rhs))
+			require.Equal(t, -tt.expected, tt.rhs.Compare(tt.lhs))
+		})
+	}
+
+	require.Equal(t, numeric("1.50").HashCode(), numeric("1.5").HashCode())
+}
+
+func TestExpression_Evaluate_numeric(t *testing.T) {
+	c := domain.NewConstExpression
+
+	tests := []struct {
+		name     string
+		expr     domain.Expression
+		expected string
+	}{
+		{name: "add", expr: domain.NewBinaryExpression(domain.AddOperator, c(numeric("1.50")), c(numeric("2.255"))), expected: "3.755"},
+		{name: "subtract", expr: domain.NewBinaryExpression(domain.SubtractOperator, c(numeric("1.00")), c(numeric("0.01"))), expected: "0.99"},
+		{name: "multiply", expr: domain.NewBinaryExpression(domain.MultiplyOperator, c(numeric("1.10")), c(numeric("3"))), expected: "3.30"},
+		{name: "divide", expr: domain.NewBinaryExpression(domain.DivideOperator, c(numeric("10")), c(numeric("3"))), expected: "3.3333333333333333"},
+		{name: "divide rounds half away from zero", expr: domain.NewBinaryExpression(domain.DivideOperator, c(numeric("-2")), c(numeric("3"))), expected: "-0.6666666666666667"},
+		{name: "modulo", expr: domain.NewBinaryExpression(domain.ModuloOperator, c(numeric("-7.5")), c(numeric("2"))), expected: "-1.5"},
+		{name: "negate", expr: domain.NewNegateExpression(c(numeric("0.50"))), expected: "-0.50"},
+		{name: "numeric and int", expr: domain.NewBinaryExpression(domain.MultiplyOperator, c(numeric("19.99")), intConst(3)), expected: "59.97"},
+		{name: "numeric and double is exact", expr: domain.NewBinaryExpression(domain.AddOperator, doubleConst(0.1), c(numeric("0.2"))), expected: "0.3"},
+		{name: "rounds excess scale", expr: domain.NewBinaryExpression(domain.MultiplyOperator, c(numeric("0.12345678901234567890")), c(numeric("0.12345678901234567890"))), expected: "0.015241578753238836750190519987501905210"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.expr.Evaluate(nil)
+			require.NoError(t, err)
+			require.Equal(t, domain.NumericFieldType, actual.Type())
+			require.Equal(t, tt.expected, actual.String())
+
+			typ, _, err := tt.expr.Type(domain.NewSchema())
+			require.NoError(t, err)
+			require.Equal(t, domain.NumericFieldType, typ)
+		})
+	}
+
+	errTests := []struct {
+		name string
+		expr domain.Expression
+		err  error
+	}{
+		{name: "overflow", expr: domain.NewBinaryExpression(domain.AddOperator, c(numeric("99999999999999999999999999999999999999")), intConst(1)), err: domain.ErrNumericOutOfRange},
+		{name: "division by zero", expr: domain.NewBinaryExpression(domain.DivideOperator, c(numeric("1")), c(numeric("0.00"))), err: domain.ErrDivisionByZero},
+		{name: "modulo by zero", expr: domain.NewBinaryExpression(domain.ModuloOperator, c(numeric("1")), intConst(0)), err: domain.ErrDivisionByZero},
+		{name: "numeric and date", expr: domain.NewBinaryExpression(domain.AddOperator, c(numeric("1")), c(date("2024-01-01"))), err: domain.ErrTypeMismatch},
+	}
+
+	for _, tt := range errTests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.expr.Evaluate(nil)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	require.Equal(t, "(numeric '1.50'*2)", domain.NewBinaryExpression(domain.MultiplyOperator, c(numeric("1.50")), intConst(2)).String())
+}
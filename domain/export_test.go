@@ -23,6 +23,8 @@ func NewLayoutByElement(schema *Schema, offsets map[FieldName]int64, slotsize in
 	return &Layout{
 		schema:   schema,
 		offsets:  offsets,
+		nullBits: nullBits(schema),
 		slotsize: slotsize,
+		format:   FixedRecordFormat,
 	}
 }
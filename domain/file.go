@@ -13,6 +13,7 @@ import (
 // Explorer is an interface of file explorer.
 type Explorer interface {
 	OpenFile(FileName) (*File, error)
+	RemoveFile(FileName) error
 }
 
 // ByteSliceFactory is a factory of byte slice.
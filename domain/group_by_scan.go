@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// GroupByScan is a scanner which aggregates records by group fields.
+// 入力は group field で sort されている必要がある.
+type GroupByScan struct {
+	scan        Scanner
+	groupFields []FieldName
+	accs        []*aggregator
+	groupVal    map[FieldName]Constant
+	moreGroups  bool
+	emitted     bool
+	err         error
+}
+
+// NewGroupByScan constructs a GroupByScan.
+// sch は入力の schema で, 集約値の型を決めるのに使う.
+func NewGroupByScan(s Scanner, sch *Schema, groupFields []FieldName, aggs []Aggregation) (*GroupByScan, error) {
+	accs := make([]*aggregator, 0, len(aggs))
+	for _, agg := range aggs {
+		typ, _, err := agg.FieldType(sch)
+		if err != nil {
+			return nil, errors.Err(err, "FieldType")
+		}
+		accs = append(accs, newAggregator(agg, typ))
+	}
+
+	gs := &GroupByScan{
+		scan:        s,
+		groupFields: groupFields,
+		accs:        accs,
+		groupVal:    make(map[FieldName]Constant),
+	}
+
+	if err := gs.BeforeFirst(); err != nil {
+		return nil, errors.Err(err, "BeforeFirst")
+	}
+
+	return gs, nil
+}
+
+// BeforeFirst move to the position before the first record.
+// BeforeFirst implements Scanner.
+func (gs *GroupByScan) BeforeFirst() error {
+	if err := gs.scan.BeforeFirst(); err != nil {
+		return errors.Err(err, "BeforeFirst")
+	}
+
+	gs.moreGroups = gs.scan.HasNext()
+	if err := gs.scan.Err(); err != nil {
+		return errors.Err(err, "HasNext")
+	}
+	gs.emitted = false
+
+	return nil
+}
+
+// HasNext moves to the next group.
+// HasNext implements Scanner.
+func (gs *GroupByScan) HasNext() bool {
+	if !gs.moreGroups {
+		// group by 無しの集約は入力が空でも 1 行返す.
+		if len(gs.groupFields) == 0 && !gs.emitted {
+			for _, acc := range gs.accs {
+				acc.reset()
+			}
+			gs.emitted = true
+
+			return true
+		}
+
+		return false
+	}
+
+	for _, acc := range gs.accs {
+		acc.reset()
+	}
+
+	for _, fld := range gs.groupFields {
+		val, err := gs.scan.GetVal(fld)
+		if err != nil {
+			gs.err = err
+
+			return false
+		}
+		gs.groupVal[fld] = val
+	}
+
+	for {
+		for _, acc := range gs.accs {
+			if err := acc.process(gs.scan); err != nil {
+				gs.err = err
+
+				return false
+			}
+		}
+
+		gs.moreGroups = gs.scan.HasNext()
+		if err := gs.scan.Err(); err != nil {
+			gs.err = err
+
+			return false
+		}
+		if !gs.moreGroups {
+			break
+		}
+
+		same, err := gs.isSameGroup()
+		if err != nil {
+			gs.err = err
+
+			return false
+		}
+		if !same {
+			break
+		}
+	}
+	gs.emitted = true
+
+	return true
+}
+
+func (gs *GroupByScan) isSameGroup() (bool, error) {
+	for _, fld := range gs.groupFields {
+		val, err := gs.scan.GetVal(fld)
+		if err != nil {
+			return false, errors.Err(err, "GetVal")
+		}
+		if !val.Equal(gs.groupVal[fld]) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// GetInt32 gets int32 from the group.
+// GetInt32 implements Scanner.
+func (gs *GroupByScan) GetInt32(fld FieldName) (int32, error) {
+	val, err := gs.GetVal(fld)
+	if err != nil {
+		return 0, errors.Err(err, "GetVal")
+	}
+
+	return val.AsInt32()
+}
+
+// GetString gets string from the group.
+// GetString implements Scanner.
+func (gs *GroupByScan) GetString(fld FieldName) (string, error) {
+	val, err := gs.GetVal(fld)
+	if err != nil {
+		return "", errors.Err(err, "GetVal")
+	}
+
+	return val.AsString()
+}
+
+// GetVal gets value from the group.
+// GetVal implements Scanner.
+func (gs *GroupByScan) GetVal(fld FieldName) (Constant, error) {
+	for _, f := range gs.groupFields {
+		if f == fld {
+			return gs.groupVal[fld], nil
+		}
+	}
+
+	for _, acc := range gs.accs {
+		if acc.agg.FieldName() == fld {
+			val, err := acc.value()
+			if err != nil {
+				return Constant{}, errors.Err(err, "value")
+			}
+
+			return val, nil
+		}
+	}
+
+	return Constant{}, fieldNotFoudError(fld)
+}
+
+// HasField checks the existence of the field.
+// HasField implements Scanner.
+func (gs *GroupByScan) HasField(fld FieldName) bool {
+	for _, f := range gs.groupFields {
+		if f == fld {
+			return true
+		}
+	}
+
+	for _, acc := range gs.accs {
+		if acc.agg.FieldName() == fld {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Close closes the scan.
+// Close implements Scanner.
+func (gs *GroupByScan) Close() {
+	gs.scan.Close()
+}
+
+// Err returns iteration error.
+// Err implements Scanner.
+func (gs *GroupByScan) Err() error {
+	return gs.err
+}
@@ -0,0 +1,276 @@
+package domain
+
+import (
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// HashJoinScan is a scanner of hash join.
+// build 側の record を memory 上の hash table に読み込み, probe 側の record と突き合わせる.
+// partition されている場合は partition ごとに hash table を作り直す.
+// hash table は HashCode で bucket に分け, bucket の中の record は Equal で突き合わせる.
+type HashJoinScan struct {
+	buildParts  []*TempTable
+	probeParts  []*TempTable
+	part        int
+	probe       Scanner
+	buildFields []FieldName
+	buildFld    FieldName
+	probeFld    FieldName
+	table       map[int][]map[FieldName]Constant
+	matches     []map[FieldName]Constant
+	pos         int
+	err         error
+}
+
+// NewHashJoinScan constructs a HashJoinScan which holds the whole build side in memory.
+func NewHashJoinScan(build, probe Scanner, buildFields []FieldName, buildFld, probeFld FieldName) (*HashJoinScan, error) {
+	scan := &HashJoinScan{
+		probe:       probe,
+		buildFields: buildFields,
+		buildFld:    buildFld,
+		probeFld:    probeFld,
+	}
+
+	err := scan.load(build)
+	build.Close()
+	if err != nil {
+		probe.Close()
+
+		return nil, errors.Err(err, "load")
+	}
+
+	if err := scan.BeforeFirst(); err != nil {
+		probe.Close()
+
+		return nil, errors.Err(err, "BeforeFirst")
+	}
+
+	return scan, nil
+}
+
+// NewPartitionedHashJoinScan constructs a HashJoinScan over partitions.
+// buildParts[i] と probeParts[i] は同じ hash 値の record を持つ.
+func NewPartitionedHashJoinScan(buildParts, probeParts []*TempTable, buildFld, probeFld FieldName) (*HashJoinScan, error) {
+	if len(buildParts) == 0 || len(buildParts) != len(probeParts) {
+		return nil, errors.New("invalid partitions")
+	}
+
+	scan := &HashJoinScan{
+		buildParts:  buildParts,
+		probeParts:  probeParts,
+		buildFields: buildParts[0].Layout().schema.Fields(),
+		buildFld:    buildFld,
+		probeFld:    probeFld,
+	}
+
+	if err := scan.BeforeFirst(); err != nil {
+		scan.Close()
+
+		return nil, errors.Err(err, "BeforeFirst")
+	}
+
+	return scan, nil
+}
+
+// BeforeFirst move to the position before the first record.
+// BeforeFirst implements Scanner.
+func (scan *HashJoinScan) BeforeFirst() error {
+	scan.matches = nil
+	scan.pos = 0
+
+	if scan.buildParts == nil {
+		return scan.probe.BeforeFirst()
+	}
+
+	return scan.openPartition(0)
+}
+
+// HasNext checks the existence of next record.
+// HasNext implements Scanner.
+func (scan *HashJoinScan) HasNext() bool {
+	for {
+		if scan.pos+1 < len(scan.matches) {
+			scan.pos++
+
+			return true
+		}
+
+		if scan.probe.HasNext() {
+			key, err := scan.probe.GetVal(scan.probeFld)
+			if err != nil {
+				scan.err = err
+
+				return false
+			}
+			scan.matches = scan.lookup(key)
+			scan.pos = -1
+
+			continue
+		}
+		if err := scan.probe.Err(); err != nil {
+			scan.err = err
+
+			return false
+		}
+
+		if scan.part+1 >= len(scan.buildParts) {
+			return false
+		}
+		if err := scan.openPartition(scan.part + 1); err != nil {
+			scan.err = err
+
+			return false
+		}
+	}
+}
+
+// openPartition loads the i-th build partition and opens the i-th probe partition.
+func (scan *HashJoinScan) openPartition(i int) error {
+	if scan.probe != nil {
+		scan.probe.Close()
+		scan.probe = nil
+	}
+	scan.part = i
+	scan.matches = nil
+
+	build, err := scan.buildParts[i].Open()
+	if err != nil {
+		return errors.Err(err, "Open")
+	}
+	err = scan.load(build)
+	build.Close()
+	if err != nil {
+		return errors.Err(err, "load")
+	}
+
+	probe, err := scan.probeParts[i].Open()
+	if err != nil {
+		return errors.Err(err, "Open")
+	}
+	scan.probe = probe
+
+	return probe.BeforeFirst()
+}
+
+// load builds hash table from build scan.
+// NULL は何とも一致しないので hash table に入れない.
+func (scan *HashJoinScan) load(build Scanner) error {
+	scan.table = make(map[int][]map[FieldName]Constant)
+	if err := build.BeforeFirst(); err != nil {
+		return errors.Err(err, "BeforeFirst")
+	}
+
+	for build.HasNext() {
+		rec := make(map[FieldName]Constant, len(scan.buildFields))
+		for _, fld := range scan.buildFields {
+			val, err := build.GetVal(fld)
+			if err != nil {
+				return errors.Err(err, "GetVal")
+			}
+			rec[fld] = val
+		}
+
+		key := rec[scan.buildFld]
+		if key.IsNull() {
+			continue
+		}
+		hash := key.HashCode()
+		scan.table[hash] = append(scan.table[hash], rec)
+	}
+
+	return build.Err()
+}
+
+// lookup returns the build records whose join field equals key.
+// 同じ bucket に入る値でも等しいとは限らないので Equal で確かめる.
+func (scan *HashJoinScan) lookup(key Constant) []map[FieldName]Constant {
+	if key.IsNull() {
+		return nil
+	}
+
+	matches := make([]map[FieldName]Constant, 0)
+	for _, rec := range scan.table[key.HashCode()] {
+		if rec[scan.buildFld].Equal(key) {
+			matches = append(matches, rec)
+		}
+	}
+
+	return matches
+}
+
+// GetInt32 gets int32 from the record.
+// GetInt32 implements Scanner.
+func (scan *HashJoinScan) GetInt32(fld FieldName) (int32, error) {
+	val, err := scan.GetVal(fld)
+	if err != nil {
+		return 0, errors.Err(err, "GetVal")
+	}
+
+	return val.AsInt32()
+}
+
+// GetString gets string from the record.
+// GetString implements Scanner.
+func (scan *HashJoinScan) GetString(fld FieldName) (string, error) {
+	val, err := scan.GetVal(fld)
+	if err != nil {
+		return "", errors.Err(err, "GetVal")
+	}
+
+	return val.AsString()
+}
+
+// GetVal gets value from the record.
+// GetVal implements Scanner.
+func (scan *HashJoinScan) GetVal(fld FieldName) (Constant, error) {
+	if scan.probe.HasField(fld) {
+		return scan.probe.GetVal(fld)
+	}
+
+	if scan.pos >= 0 && scan.pos < len(scan.matches) {
+		if val, ok := scan.matches[scan.pos][fld]; ok {
+			return val, nil
+		}
+	}
+
+	return Constant{}, fieldNotFoudError(fld)
+}
+
+// HasField checks the existence of the field.
+// HasField implements Scanner.
+func (scan *HashJoinScan) HasField(fld FieldName) bool {
+	if scan.probe.HasField(fld) {
+		return true
+	}
+
+	for _, f := range scan.buildFields {
+		if f == fld {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Close closes the scan and drops the partitions.
+// Close implements Scanner.
+func (scan *HashJoinScan) Close() {
+	if scan.probe != nil {
+		scan.probe.Close()
+		scan.probe = nil
+	}
+
+	for _, part := range append(scan.buildParts, scan.probeParts...) {
+		if err := part.Drop(); err != nil && scan.err == nil {
+			scan.err = errors.Err(err, "Drop")
+		}
+	}
+	scan.buildParts = nil
+	scan.probeParts = nil
+}
+
+// Err returns iteration error.
+// Err implements Scanner.
+func (scan *HashJoinScan) Err() error {
+	return scan.err
+}
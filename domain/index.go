@@ -2,6 +2,24 @@ package domain
 
 //go:generate mockgen -source=${GOFILE} -destination=${ROOT_DIR}/testing/mock/mock_${GOPACKAGE}_${GOFILE} -package=mock
 
+import (
+	"strconv"
+	"strings"
+
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+var (
+	// ErrRangeScanNotSupported is an error that means the index can't scan a range of keys.
+	ErrRangeScanNotSupported = errors.New("range scan is not supported")
+
+	// ErrUnknownIndexType is an error that means the index type is not supported.
+	ErrUnknownIndexType = errors.New("unknown index type")
+
+	// ErrNotIndexable is an error that means the field type can't be used as index key.
+	ErrNotIndexable = errors.New("field type cannot be used as index key")
+)
+
 const (
 	// FldBlock is column name for block.
 	FldBlock = "block"
@@ -19,8 +37,10 @@ type SearchCostCalculator interface {
 }
 
 // Indexer is an interface of index.
+// BeforeRange は key の順序を保持している index のみサポートする.
 type Indexer interface {
 	BeforeFirst(searchKey Constant) error
+	BeforeRange(r IndexRange) error
 	HasNext() bool
 	GetDataRecordID() (RecordID, error)
 	Insert(Constant, RecordID) error
@@ -29,33 +49,109 @@ type Indexer interface {
 	Err() error
 }
 
-// IndexDriver is driver for index.
-type IndexDriver struct {
+// IndexType is a kind of index.
+type IndexType string
+
+const (
+	// BTreeIndexType is an index type of B-tree.
+	BTreeIndexType IndexType = "btree"
+
+	// HashIndexType is an index type of static hash.
+	HashIndexType IndexType = "hash"
+
+	// DefaultIndexType is an index type used when CREATE INDEX doesn't specify USING.
+	DefaultIndexType = BTreeIndexType
+)
+
+// NewIndexType constructs IndexType.
+func NewIndexType(typ string) (IndexType, error) {
+	if len(typ) > MaxIndexTypeLength {
+		return "", errors.Wrap(ErrUnknownIndexType, typ)
+	}
+
+	return IndexType(strings.ToLower(typ)), nil
+}
+
+// String stringfies index type.
+func (typ IndexType) String() string {
+	return string(typ)
+}
+
+// indexKind is a pair of an index factory and a search cost calculator.
+type indexKind struct {
 	fty IndexFactory
 	cal SearchCostCalculator
 }
 
-// NewIndexDriver constructs a IndexDriver.
+// IndexDriver is driver for index.
+// index の種類ごとに factory と search cost calculator を持ち, index ごとに切り替える.
+type IndexDriver struct {
+	kinds map[IndexType]indexKind
+}
+
+// NewIndexDriver constructs a IndexDriver whose default index type is implemented by fty and cal.
 func NewIndexDriver(fty IndexFactory, cal SearchCostCalculator) IndexDriver {
 	return IndexDriver{
-		fty: fty,
-		cal: cal,
+		kinds: map[IndexType]indexKind{
+			DefaultIndexType: {fty: fty, cal: cal},
+		},
 	}
 }
 
+// Register returns a driver which also supports typ.
+func (d IndexDriver) Register(typ IndexType, fty IndexFactory, cal SearchCostCalculator) IndexDriver {
+	kinds := make(map[IndexType]indexKind, len(d.kinds)+1)
+	for k, v := range d.kinds {
+		kinds[k] = v
+	}
+	kinds[typ] = indexKind{fty: fty, cal: cal}
+
+	return IndexDriver{kinds: kinds}
+}
+
+// Supports checks whether the driver can create the type of index.
+func (d IndexDriver) Supports(typ IndexType) bool {
+	_, ok := d.kinds[typ]
+
+	return ok
+}
+
 // Create creates a index.
-func (d IndexDriver) Create(txn Transaction, name IndexName, layout *Layout) Indexer {
-	return d.fty.Create(txn, name, layout)
+func (d IndexDriver) Create(typ IndexType, txn Transaction, name IndexName, layout *Layout) Indexer {
+	return d.kind(typ).fty.Create(txn, name, layout)
 }
 
 // Calculate calculates a search cost.
-func (d IndexDriver) Calculate(numBlk, rpb int) int {
-	return d.cal.Calculate(numBlk, rpb)
+func (d IndexDriver) Calculate(typ IndexType, numBlk, rpb int) int {
+	return d.kind(typ).cal.Calculate(numBlk, rpb)
+}
+
+// SupportsRange checks whether the type of index supports range scan.
+func (d IndexDriver) SupportsRange(typ IndexType) bool {
+	return d.kind(typ).fty.SupportsRange()
+}
+
+// FileNames returns the files used by the index.
+func (d IndexDriver) FileNames(typ IndexType, name IndexName) []FileName {
+	return d.kind(typ).fty.FileNames(name)
+}
+
+// kind returns the factory and the calculator of typ.
+// catalog に登録される前に Supports で確認しているので, 未知の type は来ない.
+func (d IndexDriver) kind(typ IndexType) indexKind {
+	k, ok := d.kinds[typ]
+	if !ok {
+		panic(errors.Wrap(ErrUnknownIndexType, typ.String()))
+	}
+
+	return k
 }
 
 // IndexFactory generates Index.
 type IndexFactory interface {
 	Create(Transaction, IndexName, *Layout) Indexer
+	SupportsRange() bool
+	FileNames(IndexName) []FileName
 }
 
 // IndexName is a value object of index name.
@@ -63,8 +159,8 @@ type IndexName string
 
 // NewIndexName constructs IndexName.
 func NewIndexName(name string) (IndexName, error) {
-	if len(name) > MaxFieldNameLength {
-		return "", ErrExceedMaxFieldNameLength
+	if len(name) > MaxIndexNameLength {
+		return "", ErrExceedMaxIndexNameLength
 	}
 
 	return IndexName(name), nil
@@ -76,10 +172,12 @@ func (name IndexName) String() string {
 }
 
 // IndexInfo is a model of information of index.
+// composite index の場合 fldNames は key の順に並んでいる.
 type IndexInfo struct {
 	driver    IndexDriver
 	idxName   IndexName
-	fldName   FieldName
+	idxType   IndexType
+	fldNames  []FieldName
 	txn       Transaction
 	tblSchema *Schema
 	layout    *Layout
@@ -87,21 +185,22 @@ type IndexInfo struct {
 }
 
 // NewIndexInfo constructs an IndexInfo.
-func NewIndexInfo(driver IndexDriver, idxName IndexName, fldName FieldName, tblSchema *Schema, txn Transaction, si StatInfo) *IndexInfo {
+func NewIndexInfo(driver IndexDriver, idxName IndexName, idxType IndexType, fldNames []FieldName, tblSchema *Schema, txn Transaction, si StatInfo) *IndexInfo {
 	return &IndexInfo{
 		driver:    driver,
 		idxName:   idxName,
-		fldName:   fldName,
+		idxType:   idxType,
+		fldNames:  fldNames,
 		txn:       txn,
 		tblSchema: tblSchema,
-		layout:    createIdxLayout(tblSchema, fldName),
+		layout:    createIdxLayout(tblSchema, fldNames),
 		statInfo:  si,
 	}
 }
 
 // Open opens the index.
 func (info *IndexInfo) Open() Indexer {
-	return info.driver.Create(info.txn, info.idxName, info.layout)
+	return info.driver.Create(info.idxType, info.txn, info.idxName, info.layout)
 }
 
 // EstBlockAccessed estimates the number of accessing blocks.
@@ -109,39 +208,273 @@ func (info *IndexInfo) EstBlockAccessed() int {
 	rpb := int(info.txn.BlockSize()) / int(info.layout.SlotSize())
 	numBlks := info.statInfo.EstNumRecord() / rpb
 
-	return info.driver.Calculate(numBlks, rpb)
+	return info.driver.Calculate(info.idxType, numBlks, rpb)
 }
 
 // EstNumRecord estimates the number of records.
 func (info *IndexInfo) EstNumRecord() int {
-	return info.statInfo.EstNumRecord() / info.statInfo.EstDistinctVals(info.fldName)
+	return info.estNumRecordWithPrefix(len(info.fldNames))
+}
+
+// estNumRecordWithPrefix estimates the number of records whose first n key values are fixed.
+// 各 field の値は独立に分布しているとみなす.
+func (info *IndexInfo) estNumRecordWithPrefix(n int) int {
+	numRecs := info.statInfo.EstNumRecord()
+	for _, fld := range info.fldNames[:n] {
+		numRecs /= info.statInfo.EstDistinctVals(fld)
+	}
+
+	if numRecs == 0 && info.statInfo.EstNumRecord() > 0 {
+		return 1
+	}
+
+	return numRecs
+}
+
+// EstNumRecordInRange estimates the number of records whose key is in r.
+func (info *IndexInfo) EstNumRecordInRange(r IndexRange) int {
+	if r.IsPoint() {
+		return info.estNumRecordWithPrefix(len(r.lower.Components()))
+	}
+
+	// 下限と上限の条件それぞれで 1/3 に絞られるとみなす.
+	if r.IsClosed() {
+		return info.statInfo.EstNumRecord() / (rangeReductionFactor * rangeReductionFactor)
+	}
+
+	if r.IsBounded() {
+		return info.statInfo.EstNumRecord() / rangeReductionFactor
+	}
+
+	return info.statInfo.EstNumRecord()
+}
+
+// SupportsRange checks whether the index supports range scan.
+func (info *IndexInfo) SupportsRange() bool {
+	return info.driver.SupportsRange(info.idxType)
+}
+
+// IndexName returns the name of the index.
+func (info *IndexInfo) IndexName() IndexName {
+	return info.idxName
+}
+
+// IndexType returns the type of the index.
+func (info *IndexInfo) IndexType() IndexType {
+	return info.idxType
+}
+
+// FieldNames returns the key fields of the index.
+func (info *IndexInfo) FieldNames() []FieldName {
+	return info.fldNames
 }
 
 // EstDistinctVals returns the estimation of the number of distinct values.
 func (info *IndexInfo) EstDistinctVals(fldName FieldName) int {
-	if info.fldName == fldName {
-		return 1
+	for _, fld := range info.fldNames {
+		if fld == fldName {
+			return 1
+		}
 	}
 
 	return info.statInfo.EstDistinctVals(fldName)
 }
 
-func createIdxLayout(tblSchema *Schema, fldName FieldName) *Layout {
+// IndexKeyFieldName returns the column name of i-th key value in index record.
+// 1 つ目は single column index と同じ FldDataVal を使う.
+func IndexKeyFieldName(i int) FieldName {
+	if i == 0 {
+		return FldDataVal
+	}
+
+	return FieldName(FldDataVal + strconv.Itoa(i))
+}
+
+// IndexKeyFields returns the columns of key values in the schema of index record.
+func IndexKeyFields(sch *Schema) []FieldName {
+	flds := make([]FieldName, 0)
+	for i := 0; sch.HasField(IndexKeyFieldName(i)); i++ {
+		flds = append(flds, IndexKeyFieldName(i))
+	}
+
+	return flds
+}
+
+// NewIndexKey constructs the key of index from the values of key fields.
+func NewIndexKey(vals []Constant) Constant {
+	if len(vals) == 1 {
+		return vals[0]
+	}
+
+	return NewTupleConstant(vals...)
+}
+
+func createIdxLayout(tblSchema *Schema, fldNames []FieldName) *Layout {
 	sch := NewSchema()
 	sch.AddInt32Field(FldBlock)
 	sch.AddInt32Field(FldID)
 
-	switch tblSchema.Type(fldName) {
-	case Int32FieldType:
-		sch.AddInt32Field(FldDataVal)
-	case StringFieldType:
-		fldLen := tblSchema.Length(fldName)
-		sch.AddStringField(FldDataVal, fldLen)
-	case UnknownFieldType:
-		panic(ErrUnsupportedFieldType)
-	default:
-		panic(ErrUnsupportedFieldType)
+	for i, fldName := range fldNames {
+		switch typ := tblSchema.Type(fldName); typ {
+		case Int32FieldType, Int16FieldType, Int64FieldType, BoolFieldType, Float32FieldType, Float64FieldType,
+			DateFieldType, TimestampFieldType, TimestampTzFieldType:
+			sch.AddField(IndexKeyFieldName(i), typ, 0)
+		case StringFieldType:
+			fldLen := tblSchema.Length(fldName)
+			sch.AddStringField(IndexKeyFieldName(i), fldLen)
+		case NumericFieldType:
+			sch.AddField(IndexKeyFieldName(i), typ, tblSchema.Length(fldName))
+		case UnknownFieldType, TupleFieldType, TextFieldType, BytesFieldType, IntervalFieldType:
+			panic(ErrUnsupportedFieldType)
+		default:
+			panic(ErrUnsupportedFieldType)
+		}
 	}
 
 	return NewLayout(sch)
 }
+
+// IndexRange is a range of search keys.
+// 下限, 上限はそれぞれ省略でき, 境界を含むかどうかを指定できる.
+type IndexRange struct {
+	lower          Constant
+	upper          Constant
+	hasLower       bool
+	hasUpper       bool
+	lowerInclusive bool
+	upperInclusive bool
+	order          SortOrder
+}
+
+// NewIndexRange constructs an IndexRange which contains all keys in ascending order.
+func NewIndexRange() IndexRange {
+	return IndexRange{order: Asc}
+}
+
+// WithLower narrows the range by the lower bound c.
+// 既に c より狭い下限がある場合はそちらを使う.
+func (r IndexRange) WithLower(c Constant, inclusive bool) IndexRange {
+	if r.hasLower {
+		cmp := c.Compare(r.lower)
+		if cmp < 0 || cmp == 0 && (inclusive || !r.lowerInclusive) {
+			return r
+		}
+	}
+
+	r.lower, r.hasLower, r.lowerInclusive = c, true, inclusive
+
+	return r
+}
+
+// WithUpper narrows the range by the upper bound c.
+// 既に c より狭い上限がある場合はそちらを使う.
+func (r IndexRange) WithUpper(c Constant, inclusive bool) IndexRange {
+	if r.hasUpper {
+		cmp := c.Compare(r.upper)
+		if cmp > 0 || cmp == 0 && (inclusive || !r.upperInclusive) {
+			return r
+		}
+	}
+
+	r.upper, r.hasUpper, r.upperInclusive = c, true, inclusive
+
+	return r
+}
+
+// WithOrder returns the range scanned in given order.
+func (r IndexRange) WithOrder(order SortOrder) IndexRange {
+	r.order = order
+
+	return r
+}
+
+// Order returns the scan order of the range.
+func (r IndexRange) Order() SortOrder {
+	return r.order
+}
+
+// IsBounded checks whether the range has the lower or upper bound.
+func (r IndexRange) IsBounded() bool {
+	return r.hasLower || r.hasUpper
+}
+
+// IsClosed checks whether the range has both the lower and upper bounds.
+func (r IndexRange) IsClosed() bool {
+	return r.hasLower && r.hasUpper
+}
+
+// IsPoint checks whether the range contains only one key.
+func (r IndexRange) IsPoint() bool {
+	return r.hasLower && r.hasUpper && r.lowerInclusive && r.upperInclusive && r.lower.Equal(r.upper)
+}
+
+// Lower returns the lower bound of the range.
+func (r IndexRange) Lower() (c Constant, inclusive bool, ok bool) {
+	return r.lower, r.lowerInclusive, r.hasLower
+}
+
+// Upper returns the upper bound of the range.
+func (r IndexRange) Upper() (c Constant, inclusive bool, ok bool) {
+	return r.upper, r.upperInclusive, r.hasUpper
+}
+
+// AboveLower checks whether c satisfies the lower bound.
+func (r IndexRange) AboveLower(c Constant) bool {
+	if !r.hasLower {
+		return true
+	}
+
+	cmp := c.comparePrefix(r.lower)
+
+	return cmp > 0 || cmp == 0 && r.lowerInclusive
+}
+
+// BelowUpper checks whether c satisfies the upper bound.
+func (r IndexRange) BelowUpper(c Constant) bool {
+	if !r.hasUpper {
+		return true
+	}
+
+	cmp := c.comparePrefix(r.upper)
+
+	return cmp < 0 || cmp == 0 && r.upperInclusive
+}
+
+// BeforeLower checks whether all keys less than c are out of the range.
+func (r IndexRange) BeforeLower(c Constant) bool {
+	if !r.hasLower {
+		return false
+	}
+
+	// c と等しい key も範囲外なので, c 自体が下限と等しい場合も範囲外になる.
+	cmp := c.comparePrefix(r.lower)
+
+	return cmp < 0 || cmp == 0 && c.Equal(r.lower)
+}
+
+// Contains checks whether c is in the range.
+func (r IndexRange) Contains(c Constant) bool {
+	return r.AboveLower(c) && r.BelowUpper(c)
+}
+
+// String stringfies the range.
+func (r IndexRange) String() string {
+	s := "(-inf"
+	if r.hasLower {
+		s = "(" + r.lower.String()
+		if r.lowerInclusive {
+			s = "[" + r.lower.String()
+		}
+	}
+
+	if r.hasUpper {
+		s += ", " + r.upper.String()
+		if r.upperInclusive {
+			return s + "]"
+		}
+
+		return s + ")"
+	}
+
+	return s + ", inf)"
+}
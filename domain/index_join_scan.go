@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// IndexJoinScan is a scanner of index nested loop join.
+// lhs の record ごとに join field の値で rhs の index を検索する.
+type IndexJoinScan struct {
+	lhs       Scanner
+	idx       Indexer
+	joinField FieldName
+	rhs       *TableScan
+	hasLHS    bool
+	err       error
+}
+
+// NewIndexJoinScan constructs an IndexJoinScan.
+func NewIndexJoinScan(lhs Scanner, idx Indexer, joinField FieldName, rhs *TableScan) (*IndexJoinScan, error) {
+	scan := &IndexJoinScan{
+		lhs:       lhs,
+		idx:       idx,
+		joinField: joinField,
+		rhs:       rhs,
+	}
+
+	if err := scan.BeforeFirst(); err != nil {
+		return nil, errors.Err(err, "BeforeFirst")
+	}
+
+	return scan, nil
+}
+
+// BeforeFirst move to the position before the first record.
+// BeforeFirst implements Scanner.
+func (scan *IndexJoinScan) BeforeFirst() error {
+	if err := scan.lhs.BeforeFirst(); err != nil {
+		return errors.Err(err, "BeforeFirst")
+	}
+
+	return scan.nextLHS()
+}
+
+// HasNext checks the existence of next record.
+// HasNext implements Scanner.
+func (scan *IndexJoinScan) HasNext() bool {
+	for scan.hasLHS {
+		if scan.idx.HasNext() {
+			rid, err := scan.idx.GetDataRecordID()
+			if err != nil {
+				scan.err = err
+
+				return false
+			}
+			if err := scan.rhs.MoveToRecordID(rid); err != nil {
+				scan.err = err
+
+				return false
+			}
+			visible, err := scan.rhs.IsVisible()
+			if err != nil {
+				scan.err = err
+
+				return false
+			}
+			if !visible {
+				continue
+			}
+
+			return true
+		}
+		if err := scan.idx.Err(); err != nil {
+			scan.err = err
+
+			return false
+		}
+
+		if err := scan.nextLHS(); err != nil {
+			scan.err = err
+
+			return false
+		}
+	}
+
+	return false
+}
+
+// nextLHS moves lhs to the next record whose join value is not NULL and resets the index.
+func (scan *IndexJoinScan) nextLHS() error {
+	for {
+		scan.hasLHS = scan.lhs.HasNext()
+		if err := scan.lhs.Err(); err != nil {
+			return errors.Err(err, "HasNext")
+		}
+		if !scan.hasLHS {
+			return nil
+		}
+
+		key, err := scan.lhs.GetVal(scan.joinField)
+		if err != nil {
+			return errors.Err(err, "GetVal")
+		}
+		if key.IsNull() {
+			continue
+		}
+
+		return scan.idx.BeforeFirst(key)
+	}
+}
+
+// GetInt32 gets int32 from the record.
+// GetInt32 implements Scanner.
+func (scan *IndexJoinScan) GetInt32(fld FieldName) (int32, error) {
+	if scan.rhs.HasField(fld) {
+		return scan.rhs.GetInt32(fld)
+	}
+
+	return scan.lhs.GetInt32(fld)
+}
+
+// GetString gets string from the record.
+// GetString implements Scanner.
+func (scan *IndexJoinScan) GetString(fld FieldName) (string, error) {
+	if scan.rhs.HasField(fld) {
+		return scan.rhs.GetString(fld)
+	}
+
+	return scan.lhs.GetString(fld)
+}
+
+// GetVal gets value from the record.
+// GetVal implements Scanner.
+func (scan *IndexJoinScan) GetVal(fld FieldName) (Constant, error) {
+	if scan.rhs.HasField(fld) {
+		return scan.rhs.GetVal(fld)
+	}
+
+	return scan.lhs.GetVal(fld)
+}
+
+// HasField checks the existence of the field.
+// HasField implements Scanner.
+func (scan *IndexJoinScan) HasField(fld FieldName) bool {
+	return scan.lhs.HasField(fld) || scan.rhs.HasField(fld)
+}
+
+// Close closes the scan.
+// Close implements Scanner.
+func (scan *IndexJoinScan) Close() {
+	scan.lhs.Close()
+	scan.idx.Close()
+	scan.rhs.Close()
+}
+
+// Err returns iteration error.
+// Err implements Scanner.
+func (scan *IndexJoinScan) Err() error {
+	return scan.err
+}
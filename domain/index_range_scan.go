@@ -0,0 +1,103 @@
+package domain
+
+import "github.com/goropikari/simpledbgo/errors"
+
+// IndexRangeScan is scanner which reads records whose key is in a range by index.
+// index の key の順に record を返す.
+type IndexRangeScan struct {
+	ts  *TableScan
+	idx Indexer
+	r   IndexRange
+	err error
+}
+
+// NewIndexRangeScan constructs an IndexRangeScan.
+func NewIndexRangeScan(ts *TableScan, idx Indexer, r IndexRange) (*IndexRangeScan, error) {
+	s := &IndexRangeScan{
+		ts:  ts,
+		idx: idx,
+		r:   r,
+		err: nil,
+	}
+	if err := s.BeforeFirst(); err != nil {
+		return nil, errors.Err(err, "BeforeFirst")
+	}
+
+	return s, nil
+}
+
+// BeforeFirst move to the position before the first record.
+// BeforeFirst implements Scanner.
+func (s *IndexRangeScan) BeforeFirst() error {
+	return s.idx.BeforeRange(s.r)
+}
+
+// HasNext checks the existence of next record.
+// transaction から見えない record は読み飛ばす.
+// HasNext implements Scanner.
+func (s *IndexRangeScan) HasNext() bool {
+	for s.idx.HasNext() {
+		rid, err := s.idx.GetDataRecordID()
+		if err != nil {
+			s.err = errors.Err(err, "GetDataRecordID")
+
+			return false
+		}
+		if err := s.ts.MoveToRecordID(rid); err != nil {
+			s.err = errors.Err(err, "MoveToRecordID")
+
+			return false
+		}
+		visible, err := s.ts.IsVisible()
+		if err != nil {
+			s.err = errors.Err(err, "IsVisible")
+
+			return false
+		}
+		if visible {
+			return true
+		}
+	}
+	if s.idx.Err() != nil {
+		s.err = s.idx.Err()
+	}
+
+	return false
+}
+
+// GetInt32 gets int32 from the table.
+// GetInt32 implements Scanner.
+func (s *IndexRangeScan) GetInt32(fldName FieldName) (int32, error) {
+	return s.ts.GetInt32(fldName)
+}
+
+// GetString gets string from the table.
+// GetString implements Scanner.
+func (s *IndexRangeScan) GetString(fldName FieldName) (string, error) {
+	return s.ts.GetString(fldName)
+}
+
+// GetVal gets value from the table.
+// GetVal implements Scanner.
+func (s *IndexRangeScan) GetVal(fldName FieldName) (Constant, error) {
+	return s.ts.GetVal(fldName)
+}
+
+// HasField checks the existence of the field.
+// HasField implements Scanner.
+func (s *IndexRangeScan) HasField(fldName FieldName) bool {
+	return s.ts.HasField(fldName)
+}
+
+// Close closes the table.
+// Close implements Scanner.
+func (s *IndexRangeScan) Close() {
+	s.idx.Close()
+	s.ts.Close()
+}
+
+// Err returns iteration err.
+// Err implements Scanner.
+func (s *IndexRangeScan) Err() error {
+	return s.err
+}
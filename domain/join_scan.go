@@ -0,0 +1,170 @@
+package domain
+
+import (
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// JoinScan is a scanner of nested loop join.
+// outer join の場合、対応する record が無い側の field は NULL になる.
+type JoinScan struct {
+	outer     Scanner
+	inner     Scanner
+	pred      *Predicate
+	typ       JoinType
+	hasOuter  bool
+	innerDone bool
+	matched   bool
+	padding   bool
+	err       error
+}
+
+// NewJoinScan constructs a JoinScan.
+func NewJoinScan(lhs, rhs Scanner, typ JoinType, pred *Predicate) (*JoinScan, error) {
+	// right join は rhs を外側にして走査する.
+	outer, inner := lhs, rhs
+	if typ == RightOuterJoin {
+		outer, inner = rhs, lhs
+	}
+
+	scan := &JoinScan{
+		outer: outer,
+		inner: inner,
+		pred:  pred,
+		typ:   typ,
+	}
+
+	if err := scan.BeforeFirst(); err != nil {
+		return nil, errors.Err(err, "BeforeFirst")
+	}
+
+	return scan, nil
+}
+
+// BeforeFirst move to the position before the first record.
+// BeforeFirst implements Scanner.
+func (scan *JoinScan) BeforeFirst() error {
+	if err := scan.outer.BeforeFirst(); err != nil {
+		return errors.Err(err, "BeforeFirst")
+	}
+
+	return scan.nextOuter()
+}
+
+// HasNext checks the existence of next record.
+// HasNext implements Scanner.
+func (scan *JoinScan) HasNext() bool {
+	for scan.hasOuter {
+		if !scan.innerDone {
+			for scan.inner.HasNext() {
+				ok, err := scan.pred.IsSatisfied(scan)
+				if err != nil {
+					scan.err = err
+
+					return false
+				}
+				if ok {
+					scan.matched = true
+
+					return true
+				}
+			}
+			if err := scan.inner.Err(); err != nil {
+				scan.err = err
+
+				return false
+			}
+			scan.innerDone = true
+
+			if scan.typ != InnerJoin && !scan.matched {
+				scan.padding = true
+
+				return true
+			}
+		}
+
+		if err := scan.nextOuter(); err != nil {
+			scan.err = err
+
+			return false
+		}
+	}
+
+	return false
+}
+
+// nextOuter moves the outer scan to the next record and rewinds the inner scan.
+func (scan *JoinScan) nextOuter() error {
+	scan.matched = false
+	scan.padding = false
+	scan.innerDone = false
+
+	scan.hasOuter = scan.outer.HasNext()
+	if err := scan.outer.Err(); err != nil {
+		return errors.Err(err, "HasNext")
+	}
+
+	if err := scan.inner.BeforeFirst(); err != nil {
+		return errors.Err(err, "BeforeFirst")
+	}
+
+	return nil
+}
+
+// GetInt32 gets int32 from the record.
+// GetInt32 implements Scanner.
+func (scan *JoinScan) GetInt32(fld FieldName) (int32, error) {
+	val, err := scan.GetVal(fld)
+	if err != nil {
+		return 0, errors.Err(err, "GetVal")
+	}
+
+	return val.AsInt32()
+}
+
+// GetString gets string from the record.
+// GetString implements Scanner.
+func (scan *JoinScan) GetString(fld FieldName) (string, error) {
+	val, err := scan.GetVal(fld)
+	if err != nil {
+		return "", errors.Err(err, "GetVal")
+	}
+
+	return val.AsString()
+}
+
+// GetVal gets value from the record.
+// GetVal implements Scanner.
+func (scan *JoinScan) GetVal(fld FieldName) (Constant, error) {
+	if scan.outer.HasField(fld) {
+		return scan.outer.GetVal(fld)
+	}
+
+	if scan.inner.HasField(fld) {
+		if scan.padding {
+			return NewNullConstant(), nil
+		}
+
+		return scan.inner.GetVal(fld)
+	}
+
+	return Constant{}, fieldNotFoudError(fld)
+}
+
+// HasField checks the existence of the field.
+// HasField implements Scanner.
+func (scan *JoinScan) HasField(fld FieldName) bool {
+	return scan.outer.HasField(fld) || scan.inner.HasField(fld)
+}
+
+// Close closes the scan.
+// Close implements Scanner.
+func (scan *JoinScan) Close() {
+	scan.outer.Close()
+	scan.inner.Close()
+}
+
+// Err returns iteration error.
+// Err implements Scanner.
+func (scan *JoinScan) Err() error {
+	return scan.err
+}
@@ -8,6 +8,7 @@ type FileManager interface {
 	CopyPageToBlock(*Page, Block) error
 	BlockLength(FileName) (int32, error)
 	ExtendFile(FileName) (Block, error)
+	RemoveFile(FileName) error
 	BlockSize() BlockSize
 	CreatePage() (*Page, error)
 	IsInit() bool
@@ -32,12 +33,22 @@ type LogIterator interface {
 
 // MetadataManager is an interface of MetadataManager.
 type MetadataManager interface {
-	CreateTable(tblName TableName, sch *Schema, txn Transaction) error
+	CreateTable(tblName TableName, sch *Schema, format RecordFormat, txn Transaction) error
 	GetTableLayout(tblName TableName, txn Transaction) (*Layout, error)
 	CreateView(viewName ViewName, viewDef ViewDef, txn Transaction) error
 	GetViewDef(viewName ViewName, txn Transaction) (ViewDef, error)
-	CreateIndex(idxName IndexName, tblName TableName, fldName FieldName, txn Transaction) error
-	GetIndexInfo(tblName TableName, txn Transaction) (map[FieldName]*IndexInfo, error)
+	CreateIndex(idxName IndexName, tblName TableName, fldNames []FieldName, idxType IndexType, txn Transaction) error
+	GetIndexInfo(tblName TableName, txn Transaction) ([]*IndexInfo, error)
+	CreateConstraint(tblName TableName, c Constraint, txn Transaction) error
+	GetConstraints(tblName TableName, txn Transaction) ([]Constraint, error)
+	GetReferencingTables(tblName TableName, txn Transaction) ([]TableName, error)
+	DropTable(tblName TableName, txn Transaction) error
+	DropView(viewName ViewName, txn Transaction) error
+	DropIndex(idxName IndexName, txn Transaction) error
+	AddField(tblName TableName, fldName FieldName, sch *Schema, txn Transaction) error
+	DropField(tblName TableName, fldName FieldName, txn Transaction) error
+	RenameField(tblName TableName, oldName, newName FieldName, txn Transaction) error
+	RenameTable(tblName, newName TableName, txn Transaction) error
 	GetStatInfo(tblName TableName, layout *Layout, txn Transaction) (StatInfo, error)
 }
 
@@ -47,6 +58,7 @@ type BufferPoolManager interface {
 	FlushAll(txnum TransactionNumber) error
 	Unpin(buf *Buffer)
 	Pin(Block) (*Buffer, error)
+	DiscardFile(FileName)
 }
 
 // // ConcurrencyManager is an interface of concurrency manager.
@@ -0,0 +1,196 @@
+package domain
+
+import (
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// MergeJoinScan is a scanner of sort-merge join.
+// 両方の入力は join field で昇順に sort されている必要がある.
+// lhs の join value が重複している場合は rhs を保存した位置に戻して再度走査する.
+type MergeJoinScan struct {
+	lhs        Scanner
+	rhs        *SortScan
+	lhsFld     FieldName
+	rhsFld     FieldName
+	joinVal    Constant
+	hasJoinVal bool
+	pos        SortScanPosition
+	err        error
+}
+
+// NewMergeJoinScan constructs a MergeJoinScan.
+func NewMergeJoinScan(lhs Scanner, rhs *SortScan, lhsFld, rhsFld FieldName) (*MergeJoinScan, error) {
+	scan := &MergeJoinScan{
+		lhs:    lhs,
+		rhs:    rhs,
+		lhsFld: lhsFld,
+		rhsFld: rhsFld,
+	}
+
+	if err := scan.BeforeFirst(); err != nil {
+		return nil, errors.Err(err, "BeforeFirst")
+	}
+
+	return scan, nil
+}
+
+// BeforeFirst move to the position before the first record.
+// BeforeFirst implements Scanner.
+func (scan *MergeJoinScan) BeforeFirst() error {
+	scan.hasJoinVal = false
+
+	if err := scan.lhs.BeforeFirst(); err != nil {
+		return errors.Err(err, "BeforeFirst")
+	}
+
+	if err := scan.rhs.BeforeFirst(); err != nil {
+		return errors.Err(err, "BeforeFirst")
+	}
+
+	return nil
+}
+
+// HasNext checks the existence of next record.
+// HasNext implements Scanner.
+func (scan *MergeJoinScan) HasNext() bool {
+	found, err := scan.next()
+	if err != nil {
+		scan.err = err
+
+		return false
+	}
+
+	return found
+}
+
+func (scan *MergeJoinScan) next() (bool, error) {
+	hasMoreRHS, err := scan.advance(scan.rhs)
+	if err != nil {
+		return false, errors.Err(err, "advance")
+	}
+	if hasMoreRHS && scan.hasJoinVal {
+		v, err := scan.rhs.GetVal(scan.rhsFld)
+		if err != nil {
+			return false, errors.Err(err, "GetVal")
+		}
+		if v.Equal(scan.joinVal) {
+			return true, nil
+		}
+	}
+
+	hasMoreLHS, err := scan.advance(scan.lhs)
+	if err != nil {
+		return false, errors.Err(err, "advance")
+	}
+	if hasMoreLHS && scan.hasJoinVal {
+		v, err := scan.lhs.GetVal(scan.lhsFld)
+		if err != nil {
+			return false, errors.Err(err, "GetVal")
+		}
+		if v.Equal(scan.joinVal) {
+			// lhs の join value が直前と同じなので rhs を同じ値の先頭に戻す.
+			if err := scan.rhs.RestorePosition(scan.pos); err != nil {
+				return false, errors.Err(err, "RestorePosition")
+			}
+
+			return true, nil
+		}
+	}
+
+	for hasMoreLHS && hasMoreRHS {
+		v1, err := scan.lhs.GetVal(scan.lhsFld)
+		if err != nil {
+			return false, errors.Err(err, "GetVal")
+		}
+		v2, err := scan.rhs.GetVal(scan.rhsFld)
+		if err != nil {
+			return false, errors.Err(err, "GetVal")
+		}
+
+		// NULL は最後に sort されていて, 何とも一致しない.
+		if v1.IsNull() || v2.IsNull() {
+			return false, nil
+		}
+
+		switch c := v1.Compare(v2); {
+		case c < 0:
+			if hasMoreLHS, err = scan.advance(scan.lhs); err != nil {
+				return false, errors.Err(err, "advance")
+			}
+		case c > 0:
+			if hasMoreRHS, err = scan.advance(scan.rhs); err != nil {
+				return false, errors.Err(err, "advance")
+			}
+		default:
+			scan.pos = scan.rhs.SavePosition()
+			scan.joinVal = v2
+			scan.hasJoinVal = true
+
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (scan *MergeJoinScan) advance(s Scanner) (bool, error) {
+	found := s.HasNext()
+	if err := s.Err(); err != nil {
+		return false, errors.Err(err, "HasNext")
+	}
+
+	return found, nil
+}
+
+// GetInt32 gets int32 from the record.
+// GetInt32 implements Scanner.
+func (scan *MergeJoinScan) GetInt32(fld FieldName) (int32, error) {
+	if scan.lhs.HasField(fld) {
+		return scan.lhs.GetInt32(fld)
+	}
+
+	return scan.rhs.GetInt32(fld)
+}
+
+// GetString gets string from the record.
+// GetString implements Scanner.
+func (scan *MergeJoinScan) GetString(fld FieldName) (string, error) {
+	if scan.lhs.HasField(fld) {
+		return scan.lhs.GetString(fld)
+	}
+
+	return scan.rhs.GetString(fld)
+}
+
+// GetVal gets value from the record.
+// GetVal implements Scanner.
+func (scan *MergeJoinScan) GetVal(fld FieldName) (Constant, error) {
+	if scan.lhs.HasField(fld) {
+		return scan.lhs.GetVal(fld)
+	}
+
+	if scan.rhs.HasField(fld) {
+		return scan.rhs.GetVal(fld)
+	}
+
+	return Constant{}, fieldNotFoudError(fld)
+}
+
+// HasField checks the existence of the field.
+// HasField implements Scanner.
+func (scan *MergeJoinScan) HasField(fld FieldName) bool {
+	return scan.lhs.HasField(fld) || scan.rhs.HasField(fld)
+}
+
+// Close closes the scan.
+// Close implements Scanner.
+func (scan *MergeJoinScan) Close() {
+	scan.lhs.Close()
+	scan.rhs.Close()
+}
+
+// Err returns iteration error.
+// Err implements Scanner.
+func (scan *MergeJoinScan) Err() error {
+	return scan.err
+}
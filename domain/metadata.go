@@ -17,18 +17,55 @@ const (
 	MaxViewNameLength = 16
 
 	// MaxIndexNameLength is maximum index name length.
-	MaxIndexNameLength = 16
+	// 制約から自動生成される index 名が入るように table 名より長くしている.
+	MaxIndexNameLength = 32
+
+	// MaxIndexTypeLength is maximum index type length.
+	MaxIndexTypeLength = 16
 
 	// MaxViewDefLength is maximum view definition length.
 	MaxViewDefLength = 100
+
+	// MaxDefaultValueLength is maximum length of stringified default value.
+	MaxDefaultValueLength = 32
+
+	// MaxCheckDefLength is maximum length of CHECK constraint definition.
+	MaxCheckDefLength = 64
 )
 
 var (
 	// ErrExceedMaxFieldNameLength is an error that means exceeding maximum field name length.
 	ErrExceedMaxFieldNameLength = fmt.Errorf("exceeds maximum field name length %v", MaxFieldNameLength)
 
+	// ErrExceedMaxIndexNameLength is an error that means exceeding maximum index name length.
+	ErrExceedMaxIndexNameLength = fmt.Errorf("exceeds maximum index name length %v", MaxIndexNameLength)
+
 	// ErrExceedMaxViewNameLength is an error that means exceeding maximum view name length.
 	ErrExceedMaxViewNameLength = fmt.Errorf("exceeds maximum view name length %v", MaxViewNameLength)
+
+	// ErrTableNotFound is an error that means specified table doesn't exist.
+	ErrTableNotFound = errors.New("table does not exist")
+
+	// ErrViewNotFound is an error that means specified view doesn't exist.
+	ErrViewNotFound = errors.New("view does not exist")
+
+	// ErrIndexNotFound is an error that means specified index doesn't exist.
+	ErrIndexNotFound = errors.New("index does not exist")
+
+	// ErrIndexExists is an error that means an index of the same name already exists.
+	ErrIndexExists = errors.New("index already exists")
+
+	// ErrDependentObjectsExist is an error that means other objects depend on the object to be dropped.
+	ErrDependentObjectsExist = errors.New("other objects depend on it")
+
+	// ErrDropCatalog is an error that means trying to drop a system catalog.
+	ErrDropCatalog = errors.New("system catalog cannot be dropped")
+
+	// ErrAlterCatalog is an error that means trying to alter a system catalog.
+	ErrAlterCatalog = errors.New("system catalog cannot be altered")
+
+	// ErrDropLastField is an error that means trying to drop the only field of a table.
+	ErrDropLastField = errors.New("cannot drop the last field of table")
 )
 
 // LSN is log sequence number.
@@ -88,13 +125,129 @@ const (
 
 	// StringFieldType is string field type.
 	StringFieldType
+
+	// TupleFieldType is a type of composite index key.
+	// table の field の型としては使わない.
+	TupleFieldType
+
+	// TextFieldType is a type of unbounded string stored in overflow pages.
+	TextFieldType
+
+	// BytesFieldType is a type of binary data stored in overflow pages.
+	BytesFieldType
+
+	// Int16FieldType is SMALLINT field type.
+	Int16FieldType
+
+	// Int64FieldType is BIGINT field type.
+	Int64FieldType
+
+	// BoolFieldType is BOOLEAN field type.
+	BoolFieldType
+
+	// Float32FieldType is REAL field type.
+	Float32FieldType
+
+	// Float64FieldType is DOUBLE PRECISION field type.
+	Float64FieldType
+
+	// DateFieldType is DATE field type.
+	DateFieldType
+
+	// TimestampFieldType is TIMESTAMP field type.
+	TimestampFieldType
+
+	// TimestampTzFieldType is TIMESTAMP WITH TIME ZONE field type.
+	// time zone は UTC だけを扱うので, TIMESTAMP とは出力の形式だけが異なる.
+	TimestampTzFieldType
+
+	// IntervalFieldType is INTERVAL field type.
+	IntervalFieldType
+
+	// NumericFieldType is NUMERIC field type.
+	// 精度と scale は field の length に詰めて持つ.
+	NumericFieldType
 )
 
+// IsNumeric checks whether typ is an integer, floating-point or NUMERIC type.
+func (typ FieldType) IsNumeric() bool {
+	return typ.IsInteger() || typ.IsFloat() || typ == NumericFieldType
+}
+
+// IsInteger checks whether typ is an integer type.
+func (typ FieldType) IsInteger() bool {
+	return typ == Int16FieldType || typ == Int32FieldType || typ == Int64FieldType
+}
+
+// IsFloat checks whether typ is a floating-point type.
+func (typ FieldType) IsFloat() bool {
+	return typ == Float32FieldType || typ == Float64FieldType
+}
+
+// IsDatetime checks whether typ is DATE or TIMESTAMP.
+func (typ FieldType) IsDatetime() bool {
+	return typ == DateFieldType || typ == TimestampFieldType || typ == TimestampTzFieldType
+}
+
+// IsInt64Sized checks whether values of typ are stored as int64 in pages.
+// SMALLINT, BOOLEAN, REAL, DATE は int32 に, BIGINT, DOUBLE PRECISION, TIMESTAMP は int64 に詰めて保存する.
+func (typ FieldType) IsInt64Sized() bool {
+	return typ == Int64FieldType || typ == Float64FieldType || typ == TimestampFieldType || typ == TimestampTzFieldType
+}
+
+// IsIndexable checks whether values of typ can be used as index keys.
+func (typ FieldType) IsIndexable() bool {
+	return !typ.IsOverflow() && typ != IntervalFieldType
+}
+
+// isComparableWith checks whether values of typ can be compared with values of other.
+// 数値型どうし, 日時の型どうしは型が異なっても比較できる.
+func (typ FieldType) isComparableWith(other FieldType) bool {
+	return typ == other || (typ.IsNumeric() && other.IsNumeric()) || (typ.IsDatetime() && other.IsDatetime())
+}
+
+// IsOverflow checks whether values of typ are stored in overflow pages instead of records.
+// record には overflow page の先頭の block 番号だけを保存する.
+func (typ FieldType) IsOverflow() bool {
+	return typ == TextFieldType || typ == BytesFieldType
+}
+
+// Accepts checks whether a value of valType can be assigned to a field of typ.
+// 文字列 literal は TEXT と BYTEA にも代入できる.
+// 整数は任意の数値型の field に代入でき, 範囲外の値は代入時に error になる.
+// 日時の型どうしは相互に代入でき, 文字列 literal は代入時に日時として解釈する.
+// NUMERIC には任意の数値と文字列 literal を代入でき, 代入時に field の scale に丸める.
+func (typ FieldType) Accepts(valType FieldType) bool {
+	if typ == valType {
+		return true
+	}
+
+	switch typ {
+	case StringFieldType, TextFieldType:
+		return valType == StringFieldType || valType == TextFieldType
+	case BytesFieldType:
+		return valType == StringFieldType
+	case Int16FieldType, Int32FieldType, Int64FieldType:
+		return valType.IsInteger()
+	case Float32FieldType, Float64FieldType:
+		return valType.IsNumeric()
+	case NumericFieldType:
+		return valType.IsNumeric() || valType == StringFieldType
+	case DateFieldType, TimestampFieldType, TimestampTzFieldType:
+		return valType.IsDatetime() || valType == StringFieldType
+	case IntervalFieldType:
+		return valType == StringFieldType
+	default:
+		return false
+	}
+}
+
 // FieldInfo is a model of field information.
 // length は、その field が max 何 bytes 保存できるかの情報。VARCHAR(255) なら length は 255.
 type FieldInfo struct {
-	typ    FieldType
-	length int
+	typ        FieldType
+	length     int
+	constraint FieldConstraint
 }
 
 // TableName is value object of table name.
@@ -122,6 +275,11 @@ func (name TableName) ToFileName() FileName {
 	return FileName(name)
 }
 
+// ToOverflowFileName returns the name of the file which stores overflow pages of the table.
+func (name TableName) ToOverflowFileName() FileName {
+	return FileName(name) + OverflowFileSuffix
+}
+
 // ToViewName converts type from ViewName into ViewName.
 func (name TableName) ToViewName() ViewName {
 	return ViewName(name)
@@ -0,0 +1,244 @@
+package domain
+
+import (
+	"github.com/goropikari/simpledbgo/common"
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// OverflowFileSuffix is the suffix of the file name of overflow pages.
+const OverflowFileSuffix = ".ovf"
+
+const (
+	// overflowFreeListOffset is the offset of the head of the free list in the header block.
+	overflowFreeListOffset = 0
+
+	// overflowNextOffset is the offset of the next block number in an overflow block.
+	overflowNextOffset = 0
+
+	// overflowChunkOffset is the offset of the chunk of the value in an overflow block.
+	overflowChunkOffset = common.Int32Length
+
+	// overflowHeaderBlockNumber is the block number of the header block.
+	overflowHeaderBlockNumber BlockNumber = 0
+)
+
+// OverflowFile is a file which stores TEXT and BYTEA values in chains of blocks.
+// 値を block に収まる大きさの断片に分け, 断片を持つ block を次の block の番号でつなぐ.
+// block 0 は header で, 解放された block の list の先頭の番号を持つ.
+// block 番号 0 は chain の終わり (空の値) を表す.
+//
+// Overflow block structure
+// ----------------------------------------------------------
+// | next block number | chunk length | chunk bytes | ... |
+// ----------------------------------------------------------
+//
+// 新しく追加した block には log を書かずに書き込む (RecordPage.Format と同じ).
+// chain の先頭を指す record の更新は log に残るので, rollback すれば書き込んだ block には到達できなくなる.
+// 解放された block を再利用する場合は, undo で元に戻せるように int32 単位で log を書きながら書き込む.
+// header は書き込む transaction が xlock するので, 同じ table の overflow page への書き込みは直列になる.
+type OverflowFile struct {
+	txn      Transaction
+	fileName FileName
+}
+
+// NewOverflowFile constructs an OverflowFile.
+func NewOverflowFile(txn Transaction, fileName FileName) *OverflowFile {
+	return &OverflowFile{
+		txn:      txn,
+		fileName: fileName,
+	}
+}
+
+// Read reads the value stored in the chain starting at head.
+func (f *OverflowFile) Read(head BlockNumber) (string, error) {
+	b := make([]byte, 0)
+	for blkNum := head; blkNum != overflowHeaderBlockNumber; {
+		blk := NewBlock(f.fileName, blkNum)
+		if err := f.txn.Pin(blk); err != nil {
+			return "", errors.Err(err, "Pin")
+		}
+
+		next, err := f.txn.GetInt32(blk, overflowNextOffset)
+		if err != nil {
+			f.txn.Unpin(blk)
+
+			return "", errors.Err(err, "GetInt32")
+		}
+		chunk, err := f.txn.GetString(blk, overflowChunkOffset)
+		f.txn.Unpin(blk)
+		if err != nil {
+			return "", errors.Err(err, "GetString")
+		}
+
+		b = append(b, chunk...)
+		blkNum = BlockNumber(next)
+	}
+
+	return string(b), nil
+}
+
+// Write writes val into a new chain and returns the number of its first block.
+// 空の値は block を使わずに 0 を返す.
+func (f *OverflowFile) Write(val string) (BlockNumber, error) {
+	if val == "" {
+		return overflowHeaderBlockNumber, nil
+	}
+
+	chunkLen := int(f.txn.BlockSize()) - overflowChunkOffset - common.Int32Length
+
+	// 次の block の番号が決まっているように, 末尾の断片から書く.
+	next := overflowHeaderBlockNumber
+	for end := len(val); end > 0; {
+		start := (end - 1) / chunkLen * chunkLen
+		blkNum, err := f.writeChunk(val[start:end], next)
+		if err != nil {
+			return 0, errors.Err(err, "writeChunk")
+		}
+		next = blkNum
+		end = start
+	}
+
+	return next, nil
+}
+
+// Free puts the blocks of the chain starting at head into the free list.
+func (f *OverflowFile) Free(head BlockNumber) error {
+	if head == overflowHeaderBlockNumber {
+		return nil
+	}
+
+	tail := head
+	for {
+		blk := NewBlock(f.fileName, tail)
+		if err := f.txn.Pin(blk); err != nil {
+			return errors.Err(err, "Pin")
+		}
+		next, err := f.txn.GetInt32(blk, overflowNextOffset)
+		f.txn.Unpin(blk)
+		if err != nil {
+			return errors.Err(err, "GetInt32")
+		}
+		if BlockNumber(next) == overflowHeaderBlockNumber {
+			break
+		}
+		tail = BlockNumber(next)
+	}
+
+	hdr, err := f.pinHeader()
+	if err != nil {
+		return errors.Err(err, "pinHeader")
+	}
+	defer f.txn.Unpin(hdr)
+
+	freeHead, err := f.txn.GetInt32(hdr, overflowFreeListOffset)
+	if err != nil {
+		return errors.Err(err, "GetInt32")
+	}
+
+	blk := NewBlock(f.fileName, tail)
+	if err := f.txn.Pin(blk); err != nil {
+		return errors.Err(err, "Pin")
+	}
+	err = setInt32IfChanged(f.txn, blk, overflowNextOffset, freeHead)
+	f.txn.Unpin(blk)
+	if err != nil {
+		return errors.Err(err, "setInt32IfChanged")
+	}
+
+	return f.txn.SetInt32(hdr, overflowFreeListOffset, int32(head), true)
+}
+
+// writeChunk writes the chunk into a block whose next block is next and returns its number.
+func (f *OverflowFile) writeChunk(chunk string, next BlockNumber) (BlockNumber, error) {
+	blk, fresh, err := f.allocate()
+	if err != nil {
+		return 0, errors.Err(err, "allocate")
+	}
+
+	if err := f.txn.Pin(blk); err != nil {
+		return 0, errors.Err(err, "Pin")
+	}
+	defer f.txn.Unpin(blk)
+
+	if fresh {
+		if err := f.txn.SetInt32(blk, overflowNextOffset, int32(next), false); err != nil {
+			return 0, errors.Err(err, "SetInt32")
+		}
+		if err := f.txn.SetString(blk, overflowChunkOffset, chunk, false); err != nil {
+			return 0, errors.Err(err, "SetString")
+		}
+
+		return blk.Number(), nil
+	}
+
+	words := append([]int32{int32(next)}, encodeString(chunk)...)
+	for i, word := range words {
+		if err := setInt32IfChanged(f.txn, blk, overflowNextOffset+common.Int32Length*int64(i), word); err != nil {
+			return 0, errors.Err(err, "setInt32IfChanged")
+		}
+	}
+
+	return blk.Number(), nil
+}
+
+// allocate takes a block from the free list or appends a new block.
+// 新しく追加した block の場合は fresh を true にする.
+func (f *OverflowFile) allocate() (blk Block, fresh bool, err error) {
+	hdr, err := f.pinHeader()
+	if err != nil {
+		return Block{}, false, errors.Err(err, "pinHeader")
+	}
+	defer f.txn.Unpin(hdr)
+
+	head, err := f.txn.GetInt32(hdr, overflowFreeListOffset)
+	if err != nil {
+		return Block{}, false, errors.Err(err, "GetInt32")
+	}
+
+	if BlockNumber(head) == overflowHeaderBlockNumber {
+		blk, err := f.txn.ExtendFile(f.fileName)
+		if err != nil {
+			return Block{}, false, errors.Err(err, "ExtendFile")
+		}
+
+		return blk, true, nil
+	}
+
+	blk = NewBlock(f.fileName, BlockNumber(head))
+	if err := f.txn.Pin(blk); err != nil {
+		return Block{}, false, errors.Err(err, "Pin")
+	}
+	next, err := f.txn.GetInt32(blk, overflowNextOffset)
+	f.txn.Unpin(blk)
+	if err != nil {
+		return Block{}, false, errors.Err(err, "GetInt32")
+	}
+
+	if err := f.txn.SetInt32(hdr, overflowFreeListOffset, next, true); err != nil {
+		return Block{}, false, errors.Err(err, "SetInt32")
+	}
+
+	return blk, false, nil
+}
+
+// pinHeader pins the header block and creates it if the file is empty.
+func (f *OverflowFile) pinHeader() (Block, error) {
+	size, err := f.txn.BlockLength(f.fileName)
+	if err != nil {
+		return Block{}, errors.Err(err, "BlockLength")
+	}
+
+	hdr := NewBlock(f.fileName, overflowHeaderBlockNumber)
+	if size == 0 {
+		hdr, err = f.txn.ExtendFile(f.fileName)
+		if err != nil {
+			return Block{}, errors.Err(err, "ExtendFile")
+		}
+	}
+
+	if err := f.txn.Pin(hdr); err != nil {
+		return Block{}, errors.Err(err, "Pin")
+	}
+
+	return hdr, nil
+}
@@ -0,0 +1,89 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/testing/fake"
+	"github.com/goropikari/simpledbgo/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverflowFile(t *testing.T) {
+	const (
+		blockSize = 100
+		numBuf    = 3
+	)
+
+	dbPath := fake.RandString()
+	factory := fake.NewNonDirectBufferManagerFactory(dbPath, blockSize, numBuf)
+	fileMgr, logMgr, bufMgr := factory.Create()
+	defer factory.Finish()
+
+	cfg := tx.LockTableConfig{LockTimeoutMillisecond: 1000}
+	lt := tx.NewLockTable(cfg)
+
+	gen := tx.NewNumberGenerator()
+
+	const filename = domain.FileName("T" + domain.OverflowFileSuffix)
+
+	t.Run("test write and read", func(t *testing.T) {
+		txn, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		ovf := domain.NewOverflowFile(txn, filename)
+
+		tests := []string{
+			"",
+			"hello",
+			strings.Repeat("a", blockSize-8),
+			strings.Repeat("abcdefghij", 50),
+			string([]byte{0, 1, 2, 255}),
+		}
+		for _, val := range tests {
+			head, err := ovf.Write(val)
+			require.NoError(t, err)
+
+			actual, err := ovf.Read(head)
+			require.NoError(t, err)
+			require.Equal(t, val, actual)
+		}
+		require.NoError(t, txn.Commit())
+	})
+
+	t.Run("test free and rollback", func(t *testing.T) {
+		txn, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		ovf := domain.NewOverflowFile(txn, filename)
+
+		old := strings.Repeat("old value ", 30)
+		head, err := ovf.Write(old)
+		require.NoError(t, err)
+		require.NoError(t, txn.Commit())
+
+		txn2, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		ovf2 := domain.NewOverflowFile(txn2, filename)
+
+		size, err := txn2.BlockLength(filename)
+		require.NoError(t, err)
+
+		// 解放した block を再利用するので file は大きくならない.
+		require.NoError(t, ovf2.Free(head))
+		_, err = ovf2.Write(strings.Repeat("new", 80))
+		require.NoError(t, err)
+		size2, err := txn2.BlockLength(filename)
+		require.NoError(t, err)
+		require.Equal(t, size, size2)
+		require.NoError(t, txn2.Rollback())
+
+		txn3, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		ovf3 := domain.NewOverflowFile(txn3, filename)
+
+		actual, err := ovf3.Read(head)
+		require.NoError(t, err)
+		require.Equal(t, old, actual)
+		require.NoError(t, txn3.Commit())
+	})
+}
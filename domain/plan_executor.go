@@ -27,4 +27,8 @@ type UpdateExecutor interface {
 	ExecuteCreateTable(*CreateTableData, Transaction) (int, error)
 	ExecuteCreateView(*CreateViewData, Transaction) (int, error)
 	ExecuteCreateIndex(*CreateIndexData, Transaction) (int, error)
+	ExecuteDropTable(*DropTableData, Transaction) (int, error)
+	ExecuteDropView(*DropViewData, Transaction) (int, error)
+	ExecuteDropIndex(*DropIndexData, Transaction) (int, error)
+	ExecuteAlterTable(*AlterTableData, Transaction) (int, error)
 }
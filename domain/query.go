@@ -1,16 +1,115 @@
 package domain
 
 import (
+	"fmt"
+	stdmath "math"
 	"strings"
+	"time"
 
 	"github.com/goropikari/simpledbgo/common"
+	"github.com/goropikari/simpledbgo/errors"
 	"github.com/goropikari/simpledbgo/math"
 )
 
+// ErrTypeMismatch is an error that means the operands of an expression have unexpected types.
+var ErrTypeMismatch = errors.New("type mismatch")
+
+// ErrDivisionByZero is an error that means division by zero.
+var ErrDivisionByZero = errors.New("division by zero")
+
+// ExpressionOperator is an operator of expression.
+type ExpressionOperator uint
+
+const (
+	// noOperator は定数または field name の expression を表す.
+	noOperator ExpressionOperator = iota
+
+	// AddOperator is +.
+	AddOperator
+
+	// SubtractOperator is binary -.
+	SubtractOperator
+
+	// MultiplyOperator is *.
+	MultiplyOperator
+
+	// DivideOperator is /.
+	DivideOperator
+
+	// ModuloOperator is %.
+	ModuloOperator
+
+	// NegateOperator is unary -.
+	NegateOperator
+
+	// ConcatOperator is ||.
+	ConcatOperator
+
+	// NowOperator is now().
+	NowOperator
+
+	// CurrentDateOperator is current_date.
+	CurrentDateOperator
+)
+
+// String stringfies the operator.
+func (op ExpressionOperator) String() string {
+	switch op {
+	case AddOperator:
+		return "+"
+	case SubtractOperator, NegateOperator:
+		return "-"
+	case MultiplyOperator:
+		return "*"
+	case DivideOperator:
+		return "/"
+	case ModuloOperator:
+		return "%"
+	case ConcatOperator:
+		return "||"
+	case NowOperator:
+		return "now()"
+	case CurrentDateOperator:
+		return "current_date"
+	default:
+		return ""
+	}
+}
+
+// stringEscaper escapes string constant so that lexer can read it again.
+var stringEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// stringLengths are the max lengths of stringified values of fixed-size types.
+var stringLengths = map[FieldType]int{
+	Int16FieldType:       6,
+	Int32FieldType:       11,
+	Int64FieldType:       20,
+	BoolFieldType:        5,
+	Float32FieldType:     15,
+	Float64FieldType:     24,
+	DateFieldType:        10,
+	TimestampFieldType:   26,
+	TimestampTzFieldType: 29,
+	IntervalFieldType:    64,
+	NumericFieldType:     MaxNumericPrecision + 2,
+}
+
+// typedLiteralPrefixes are the type names put before the literals of date/time and NUMERIC types.
+var typedLiteralPrefixes = map[FieldType]string{
+	DateFieldType:        "date",
+	TimestampFieldType:   "timestamp",
+	TimestampTzFieldType: "timestamp with time zone",
+	IntervalFieldType:    "interval",
+	NumericFieldType:     "numeric",
+}
+
 // Expression is node of expression.
+// op が noOperator のときは定数か field name, それ以外は args に演算子を適用した値を表す.
 type Expression struct {
 	value Constant
 	field FieldName
+	op    ExpressionOperator
+	args  []Expression
 }
 
 // NewConstExpression constructs a const expression.
@@ -23,23 +122,355 @@ func NewFieldNameExpression(fld FieldName) Expression {
 	return Expression{field: fld}
 }
 
+// NewBinaryExpression constructs an expression which applies op to lhs and rhs.
+func NewBinaryExpression(op ExpressionOperator, lhs, rhs Expression) Expression {
+	return Expression{op: op, args: []Expression{lhs, rhs}}
+}
+
+// NewNegateExpression constructs an expression which negates expr.
+func NewNegateExpression(expr Expression) Expression {
+	return Expression{op: NegateOperator, args: []Expression{expr}}
+}
+
+// NewNowExpression constructs now() whose value is t.
+// 1 つの文の中では同じ値を返すように, 文を parse した時刻を持たせる.
+func NewNowExpression(t time.Time) Expression {
+	return Expression{op: NowOperator, value: NewTimestampConstant(TimestampTzFieldType, t)}
+}
+
+// NewCurrentDateExpression constructs current_date whose value is the day of t.
+func NewCurrentDateExpression(t time.Time) Expression {
+	return Expression{op: CurrentDateOperator, value: NewDateConstant(t)}
+}
+
 // Evaluate evaluates scanner.
 func (expr Expression) Evaluate(s Scanner) (Constant, error) {
-	if (expr.value == Constant{}) {
+	if expr.isCurrentTime() {
+		return expr.value, nil
+	}
+
+	if expr.op != noOperator {
+		return expr.evaluateOperator(s)
+	}
+
+	if expr.IsFieldName() {
 		return s.GetVal(expr.field)
 	}
 
 	return expr.value, nil
 }
 
+func (expr Expression) evaluateOperator(s Scanner) (Constant, error) {
+	vals := make([]Constant, 0, len(expr.args))
+	for _, arg := range expr.args {
+		v, err := arg.Evaluate(s)
+		if err != nil {
+			return Constant{}, errors.Err(err, "Evaluate")
+		}
+		if v.IsNull() {
+			return NewNullConstant(), nil
+		}
+		vals = append(vals, v)
+	}
+
+	if expr.op == ConcatOperator {
+		return NewConstant(StringFieldType, vals[0].String()+vals[1].String()), nil
+	}
+
+	types := make([]FieldType, 0, len(vals))
+	for _, v := range vals {
+		types = append(types, v.typ)
+	}
+	typ, err := arithmeticType(expr.op, types)
+	if err != nil {
+		return Constant{}, err
+	}
+
+	if hasDatetime(types) {
+		return applyDatetimeArithmetic(expr.op, typ, vals)
+	}
+
+	return applyArithmetic(expr.op, typ, vals)
+}
+
+// arithmeticType returns the type of the result of op applied to values of types.
+// NUMERIC を含む場合は NUMERIC とする. 小数の literal は DOUBLE PRECISION なので, 混ぜても正確に計算できるようにするため.
+// 浮動小数点数を含む場合は, すべて REAL なら REAL, それ以外は DOUBLE PRECISION とする.
+// 整数だけの場合は最も大きい整数型とする.
+func arithmeticType(op ExpressionOperator, types []FieldType) (FieldType, error) {
+	if hasDatetime(types) {
+		return datetimeArithmeticType(op, types)
+	}
+
+	ret := UnknownFieldType
+	float := false
+	for _, typ := range types {
+		if !typ.IsNumeric() {
+			return UnknownFieldType, fmt.Errorf("%w: operator %v requires number", ErrTypeMismatch, op)
+		}
+		if typ.IsFloat() {
+			float = true
+		}
+		if ret == UnknownFieldType || numericRank(ret) < numericRank(typ) {
+			ret = typ
+		}
+	}
+
+	switch {
+	case ret == UnknownFieldType:
+		// NULL だけの場合.
+		return Int32FieldType, nil
+	case ret == NumericFieldType:
+		return NumericFieldType, nil
+	case !float:
+		return ret, nil
+	case op == ModuloOperator:
+		return UnknownFieldType, fmt.Errorf("%w: operator %v requires integer", ErrTypeMismatch, op)
+	}
+
+	for _, typ := range types {
+		if typ != Float32FieldType {
+			return Float64FieldType, nil
+		}
+	}
+
+	return Float32FieldType, nil
+}
+
+// numericRank returns the order of numeric types used to decide the type of arithmetic result.
+func numericRank(typ FieldType) int {
+	switch typ {
+	case Int16FieldType:
+		return 1
+	case Int32FieldType:
+		return 2
+	case Int64FieldType:
+		return 3
+	case Float32FieldType:
+		return 4
+	case Float64FieldType:
+		return 5
+	case NumericFieldType:
+		return 6
+	case UnknownFieldType, StringFieldType, TupleFieldType, TextFieldType, BytesFieldType, BoolFieldType,
+		DateFieldType, TimestampFieldType, TimestampTzFieldType, IntervalFieldType:
+		return 0
+	default:
+		return 0
+	}
+}
+
+// applyArithmetic applies op to vals and returns the value of typ.
+// 整数の演算で typ の範囲を超えた場合は error を返す.
+func applyArithmetic(op ExpressionOperator, typ FieldType, vals []Constant) (Constant, error) {
+	if typ == NumericFieldType {
+		return applyDecimalArithmetic(op, vals)
+	}
+
+	if typ.IsFloat() {
+		return applyFloatArithmetic(op, typ, vals)
+	}
+
+	nums := make([]int64, 0, len(vals))
+	for _, v := range vals {
+		n, err := v.asInt64()
+		if err != nil {
+			return Constant{}, fmt.Errorf("%w: operator %v requires integer", ErrTypeMismatch, op)
+		}
+		nums = append(nums, n)
+	}
+
+	var ret int64
+	overflow := false
+	switch op {
+	case NegateOperator:
+		ret = -nums[0]
+		overflow = nums[0] == stdmath.MinInt64
+	case AddOperator:
+		ret = nums[0] + nums[1]
+		overflow = (nums[1] > 0 && ret < nums[0]) || (nums[1] < 0 && ret > nums[0])
+	case SubtractOperator:
+		ret = nums[0] - nums[1]
+		overflow = (nums[1] > 0 && ret > nums[0]) || (nums[1] < 0 && ret < nums[0])
+	case MultiplyOperator:
+		ret = nums[0] * nums[1]
+		overflow = nums[0] != 0 && (ret/nums[0] != nums[1] || (nums[0] == -1 && nums[1] == stdmath.MinInt64))
+	case DivideOperator, ModuloOperator:
+		if nums[1] == 0 {
+			return Constant{}, ErrDivisionByZero
+		}
+		if nums[0] == stdmath.MinInt64 && nums[1] == -1 {
+			overflow = op == DivideOperator
+			break
+		}
+		if op == DivideOperator {
+			ret = nums[0] / nums[1]
+		} else {
+			ret = nums[0] % nums[1]
+		}
+	default:
+		return Constant{}, fmt.Errorf("unexpected operator %v", op)
+	}
+	if overflow {
+		return Constant{}, fmt.Errorf("%w: bigint", ErrNumericOutOfRange)
+	}
+
+	return newIntegerConstant(typ, ret)
+}
+
+// applyFloatArithmetic applies op to vals as floating-point numbers.
+func applyFloatArithmetic(op ExpressionOperator, typ FieldType, vals []Constant) (Constant, error) {
+	nums := make([]float64, 0, len(vals))
+	for _, v := range vals {
+		f, err := v.asFloat64()
+		if err != nil {
+			return Constant{}, fmt.Errorf("%w: operator %v requires number", ErrTypeMismatch, op)
+		}
+		nums = append(nums, f)
+	}
+
+	var ret float64
+	switch op {
+	case NegateOperator:
+		ret = -nums[0]
+	case AddOperator:
+		ret = nums[0] + nums[1]
+	case SubtractOperator:
+		ret = nums[0] - nums[1]
+	case MultiplyOperator:
+		ret = nums[0] * nums[1]
+	case DivideOperator:
+		if nums[1] == 0 {
+			return Constant{}, ErrDivisionByZero
+		}
+		ret = nums[0] / nums[1]
+	case noOperator, ModuloOperator, ConcatOperator:
+		return Constant{}, fmt.Errorf("unexpected operator %v", op)
+	default:
+		return Constant{}, fmt.Errorf("unexpected operator %v", op)
+	}
+
+	return NewConstant(Float64FieldType, ret).ConvertTo(typ)
+}
+
+// Type returns the type and the length of the value of expr evaluated on sch.
+func (expr Expression) Type(sch *Schema) (FieldType, int, error) {
+	switch {
+	case expr.op == ConcatOperator:
+		length := 0
+		text := false
+		for _, arg := range expr.args {
+			typ, l, err := arg.Type(sch)
+			if err != nil {
+				return UnknownFieldType, 0, err
+			}
+			if n, ok := stringLengths[typ]; ok {
+				l = n
+			}
+			// 長さの上限がない値を含む場合は結果も TEXT にする.
+			if typ.IsOverflow() {
+				text = true
+			}
+			length += l
+		}
+		if text {
+			return TextFieldType, 0, nil
+		}
+
+		return StringFieldType, length, nil
+	case expr.isCurrentTime():
+		return expr.value.typ, 0, nil
+	case expr.op != noOperator:
+		types := make([]FieldType, 0, len(expr.args))
+		for _, arg := range expr.args {
+			typ, _, err := arg.Type(sch)
+			if err != nil {
+				return UnknownFieldType, 0, err
+			}
+			// NULL は任意の型の値として扱える.
+			if !arg.IsNull() {
+				types = append(types, typ)
+			}
+		}
+
+		typ, err := arithmeticType(expr.op, types)
+		if err != nil {
+			return UnknownFieldType, 0, err
+		}
+
+		return typ, 0, nil
+	case expr.IsFieldName():
+		if !sch.HasField(expr.field) {
+			return UnknownFieldType, 0, fieldNotFoudError(expr.field)
+		}
+
+		return sch.Type(expr.field), sch.Length(expr.field), nil
+	default:
+		if expr.value.typ == StringFieldType {
+			str, _ := expr.value.AsString()
+
+			return StringFieldType, len(str), nil
+		}
+
+		return expr.value.typ, 0, nil
+	}
+}
+
+// Fields returns field names which appear in expr.
+func (expr Expression) Fields() []FieldName {
+	if expr.op == noOperator {
+		if expr.IsFieldName() {
+			return []FieldName{expr.field}
+		}
+
+		return []FieldName{}
+	}
+
+	flds := make([]FieldName, 0)
+	for _, arg := range expr.args {
+		flds = append(flds, arg.Fields()...)
+	}
+
+	return flds
+}
+
+// RenameField returns the expression whose field oldName is renamed to newName.
+func (expr Expression) RenameField(oldName, newName FieldName) Expression {
+	if expr.op == noOperator {
+		if expr.field == oldName {
+			expr.field = newName
+		}
+
+		return expr
+	}
+
+	args := make([]Expression, 0, len(expr.args))
+	for _, arg := range expr.args {
+		args = append(args, arg.RenameField(oldName, newName))
+	}
+	expr.args = args
+
+	return expr
+}
+
+// isCurrentTime checks whether expr is now() or current_date.
+func (expr Expression) isCurrentTime() bool {
+	return expr.op == NowOperator || expr.op == CurrentDateOperator
+}
+
+// IsNull checks whether expr is NULL constant or not.
+func (expr Expression) IsNull() bool {
+	return expr.IsConstant() && expr.value.IsNull()
+}
+
 // IsFieldName checks whether expr is field name or not.
 func (expr Expression) IsFieldName() bool {
-	return expr.field != ""
+	return expr.op == noOperator && expr.field != ""
 }
 
 // IsConstant checks expr is constant or not.
 func (expr Expression) IsConstant() bool {
-	return expr.value != Constant{}
+	return expr.op == noOperator && expr.field == ""
 }
 
 // AsFieldName returns expr as FieldName.
@@ -54,18 +485,161 @@ func (expr Expression) AsConstant() Constant {
 
 // String stringfies expr.
 func (expr Expression) String() string {
-	if (expr.value == Constant{}) {
-		return expr.field.String()
+	switch expr.op {
+	case noOperator:
+		if expr.IsFieldName() {
+			return expr.field.String()
+		}
+
+		if expr.value.typ == StringFieldType {
+			return "'" + stringEscaper.Replace(expr.value.String()) + "'"
+		}
+
+		if prefix, ok := typedLiteralPrefixes[expr.value.typ]; ok {
+			return prefix + " '" + expr.value.String() + "'"
+		}
+
+		// 整数の literal として読まれないように小数点を付ける.
+		if str := expr.value.String(); expr.value.typ.IsFloat() && !strings.ContainsAny(str, ".e") {
+			return str + ".0"
+		}
+
+		return expr.value.String()
+	case NegateOperator:
+		return "-(" + expr.args[0].String() + ")"
+	case NowOperator, CurrentDateOperator:
+		return expr.op.String()
+	default:
+		return "(" + expr.args[0].String() + expr.op.String() + expr.args[1].String() + ")"
 	}
+}
+
+// TermOperator is an operator of term.
+type TermOperator uint
+
+const (
+	// EqualOperator is =.
+	EqualOperator TermOperator = iota
+
+	// NotEqualOperator is <>.
+	NotEqualOperator
 
-	return expr.value.String()
+	// LessOperator is <.
+	LessOperator
+
+	// LessEqualOperator is <=.
+	LessEqualOperator
+
+	// GreaterOperator is >.
+	GreaterOperator
+
+	// GreaterEqualOperator is >=.
+	GreaterEqualOperator
+
+	// OrOperator is disjunction of predicates.
+	OrOperator
+
+	// NotOperator is negation of a predicate.
+	NotOperator
+
+	// IsNullOperator is IS NULL.
+	IsNullOperator
+
+	// IsNotNullOperator is IS NOT NULL.
+	IsNotNullOperator
+)
+
+// String stringfies the operator.
+func (op TermOperator) String() string {
+	switch op {
+	case EqualOperator:
+		return "="
+	case NotEqualOperator:
+		return "<>"
+	case LessOperator:
+		return "<"
+	case LessEqualOperator:
+		return "<="
+	case GreaterOperator:
+		return ">"
+	case GreaterEqualOperator:
+		return ">="
+	case OrOperator:
+		return "or"
+	case NotOperator:
+		return "not"
+	case IsNullOperator:
+		return "is null"
+	case IsNotNullOperator:
+		return "is not null"
+	default:
+		return "unknown"
+	}
+}
+
+// IsComparison checks whether op compares two expressions or not.
+func (op TermOperator) IsComparison() bool {
+	return op <= GreaterEqualOperator
+}
+
+// rangeReductionFactor is the reduction factor of range comparison such as F < c.
+// 分布が分からないので System R と同様に 1/3 が残ると見積もる.
+const rangeReductionFactor = 3
+
+// nullReductionFactor is the reduction factor of F IS NULL.
+// NULL の割合は統計を取っていないので 1/10 と見積もる.
+const nullReductionFactor = 10
+
+// Truth is a truth value of three-valued logic.
+// NULL との比較は True でも False でもなく Unknown になる.
+type Truth uint
+
+const (
+	// False is false.
+	False Truth = iota
+
+	// Unknown is the truth value of comparison with NULL.
+	Unknown
+
+	// True is true.
+	True
+)
+
+// And returns t AND other.
+func (t Truth) And(other Truth) Truth {
+	return math.Min[Truth](t, other)
+}
+
+// Or returns t OR other.
+func (t Truth) Or(other Truth) Truth {
+	return math.Max[Truth](t, other)
+}
+
+// Not returns NOT t.
+func (t Truth) Not() Truth {
+	return True - t
+}
+
+// String stringfies the truth value.
+func (t Truth) String() string {
+	switch t {
+	case False:
+		return "false"
+	case True:
+		return "true"
+	default:
+		return "unknown"
+	}
 }
 
 // Term is a node of term.
-// lhs = rhs を表す。その他の2項演算には対応していない。
+// 比較演算の場合は lhs op rhs を表す。
+// or の場合は preds のいずれかが成り立つこと、not の場合は preds[0] が成り立たないことを表す。
 type Term struct {
-	lhs Expression
-	rhs Expression
+	op    TermOperator
+	lhs   Expression
+	rhs   Expression
+	preds []*Predicate
 }
 
 // NewTerm constructs a Term.
@@ -73,24 +647,276 @@ func NewTerm(lhs, rhs Expression) Term {
 	return Term{lhs: lhs, rhs: rhs}
 }
 
+// NewComparisonTerm constructs a Term which compares lhs and rhs by op.
+func NewComparisonTerm(op TermOperator, lhs, rhs Expression) Term {
+	return Term{op: op, lhs: lhs, rhs: rhs}
+}
+
+// NewOrTerm constructs a Term which is satisfied if one of preds is satisfied.
+func NewOrTerm(preds []*Predicate) Term {
+	return Term{op: OrOperator, preds: preds}
+}
+
+// NewNotTerm constructs a Term which is satisfied if pred is not satisfied.
+func NewNotTerm(pred *Predicate) Term {
+	return Term{op: NotOperator, preds: []*Predicate{pred}}
+}
+
+// NewIsNullTerm constructs a Term which is satisfied if expr is NULL.
+func NewIsNullTerm(expr Expression) Term {
+	return Term{op: IsNullOperator, lhs: expr}
+}
+
+// NewIsNotNullTerm constructs a Term which is satisfied if expr is not NULL.
+func NewIsNotNullTerm(expr Expression) Term {
+	return Term{op: IsNotNullOperator, lhs: expr}
+}
+
+// Operator returns the operator of the term.
+func (term Term) Operator() TermOperator {
+	return term.op
+}
+
 // IsSatisfied checks whether a term is satisfied or not.
-func (term Term) IsSatisfied(s Scanner) bool {
+func (term Term) IsSatisfied(s Scanner) (bool, error) {
+	t, err := term.Evaluate(s)
+	if err != nil {
+		return false, err
+	}
+
+	return t == True, nil
+}
+
+// Evaluate evaluates the term in three-valued logic.
+// 式の評価に失敗した場合は error を返す.
+func (term Term) Evaluate(s Scanner) (Truth, error) {
+	switch term.op {
+	case OrOperator:
+		ret := False
+		for _, pred := range term.preds {
+			t, err := pred.Evaluate(s)
+			if err != nil {
+				return Unknown, err
+			}
+			ret = ret.Or(t)
+		}
+
+		return ret, nil
+	case NotOperator:
+		t, err := term.preds[0].Evaluate(s)
+		if err != nil {
+			return Unknown, err
+		}
+
+		return t.Not(), nil
+	case IsNullOperator, IsNotNullOperator:
+		val, err := term.lhs.Evaluate(s)
+		if err != nil {
+			return Unknown, errors.Err(err, "Evaluate")
+		}
+
+		return term.op.testNull(val), nil
+	}
+
 	lhsVal, err := term.lhs.Evaluate(s)
 	if err != nil {
-		return false
+		return Unknown, errors.Err(err, "Evaluate")
 	}
 
 	rhsVal, err := term.rhs.Evaluate(s)
 	if err != nil {
+		return Unknown, errors.Err(err, "Evaluate")
+	}
+
+	if lhsVal.IsNull() || rhsVal.IsNull() {
+		return Unknown, nil
+	}
+
+	// 文字列 literal は相手の型の値として比較する.
+	if lhsVal, err = coerceLiteral(lhsVal, rhsVal.typ); err != nil {
+		return Unknown, errors.Err(err, "coerceLiteral")
+	}
+	if rhsVal, err = coerceLiteral(rhsVal, lhsVal.typ); err != nil {
+		return Unknown, errors.Err(err, "coerceLiteral")
+	}
+
+	if term.op.compare(lhsVal, rhsVal) {
+		return True, nil
+	}
+
+	return False, nil
+}
+
+// TypeCheck checks that the operands of the term can be compared on sch.
+// 文字列 literal は相手の型に代入できれば比較できるものとする.
+func (term Term) TypeCheck(sch *Schema) error {
+	switch term.op {
+	case OrOperator, NotOperator:
+		for _, pred := range term.preds {
+			if err := pred.TypeCheck(sch); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case IsNullOperator, IsNotNullOperator:
+		_, _, err := term.lhs.Type(sch)
+
+		return err
+	}
+
+	lhsType, _, err := term.lhs.Type(sch)
+	if err != nil {
+		return err
+	}
+	rhsType, _, err := term.rhs.Type(sch)
+	if err != nil {
+		return err
+	}
+	if term.lhs.IsNull() || term.rhs.IsNull() {
+		return nil
+	}
+
+	if isComparable(lhsType, term.lhs.IsConstant(), rhsType, term.rhs.IsConstant()) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: operator %v cannot be applied to %v and %v", ErrTypeMismatch, term.op, lhsType, rhsType)
+}
+
+// isComparable checks whether a value of lhs can be compared with a value of rhs.
+// literal の文字列は相手の型に代入できる場合に比較できる.
+func isComparable(lhs FieldType, lhsConst bool, rhs FieldType, rhsConst bool) bool {
+	switch {
+	case lhs.isComparableWith(rhs):
+		return true
+	case isStringType(lhs) && isStringType(rhs):
+		return true
+	case lhsConst && lhs == StringFieldType:
+		return rhs.Accepts(lhs)
+	case rhsConst && rhs == StringFieldType:
+		return lhs.Accepts(rhs)
+	default:
 		return false
 	}
+}
 
-	return lhsVal.Equal(rhsVal)
+func isStringType(typ FieldType) bool {
+	return typ == StringFieldType || typ == TextFieldType
+}
+
+// coerceLiteral converts the string val into a value of typ to compare with a value of typ.
+func coerceLiteral(val Constant, typ FieldType) (Constant, error) {
+	if val.typ != StringFieldType {
+		return val, nil
+	}
+
+	switch {
+	case typ == BytesFieldType:
+		b, err := ParseBytes(val.val.(string))
+		if err != nil {
+			return Constant{}, err
+		}
+
+		return NewConstant(BytesFieldType, b), nil
+	case typ.IsDatetime(), typ == IntervalFieldType, typ == NumericFieldType:
+		return val.ConvertTo(typ)
+	default:
+		return val, nil
+	}
+}
+
+// testNull returns the truth value of val IS NULL or val IS NOT NULL.
+func (op TermOperator) testNull(val Constant) Truth {
+	if val.IsNull() == (op == IsNullOperator) {
+		return True
+	}
+
+	return False
+}
+
+// compare compares lhs and rhs by op.
+// 型が異なる場合は大小比較できないので、<> 以外は成り立たないとする. ただし数値型どうしは比較できる.
+// NULL との比較は常に成り立たない.
+func (op TermOperator) compare(lhs, rhs Constant) bool {
+	if lhs.IsNull() || rhs.IsNull() {
+		return false
+	}
+
+	switch op {
+	case EqualOperator:
+		return lhs.Equal(rhs)
+	case NotEqualOperator:
+		return !lhs.Equal(rhs)
+	}
+
+	if !lhs.typ.isComparableWith(rhs.typ) {
+		return false
+	}
+
+	c := lhs.Compare(rhs)
+	switch op {
+	case LessOperator:
+		return c < 0
+	case LessEqualOperator:
+		return c <= 0
+	case GreaterOperator:
+		return c > 0
+	case GreaterEqualOperator:
+		return c >= 0
+	default:
+		return false
+	}
 }
 
 // ReductionFactor is reduction factor due to the predicate.
 // predicate によってスキャン量がどれだけ減るかの割合.
 func (term Term) ReductionFactor(p Planner) int {
+	switch term.op {
+	case EqualOperator:
+		return term.equalReductionFactor(p)
+	case NotEqualOperator:
+		// F <> c はほとんどの record が残る.
+		if term.lhs.IsConstant() && term.rhs.IsConstant() {
+			return term.constantReductionFactor()
+		}
+
+		return 1
+	case LessOperator, LessEqualOperator, GreaterOperator, GreaterEqualOperator:
+		if term.lhs.IsConstant() && term.rhs.IsConstant() {
+			return term.constantReductionFactor()
+		}
+
+		return rangeReductionFactor
+	case OrOperator:
+		// 各 predicate が独立だと仮定して 1 - Π(1 - 1/rf) が残る.
+		remain := 1.0
+		for _, pred := range term.preds {
+			remain *= 1 - 1/float64(pred.ReductionFactor(p))
+		}
+
+		return selectivityToReductionFactor(1 - remain)
+	case NotOperator:
+		return selectivityToReductionFactor(1 - 1/float64(term.preds[0].ReductionFactor(p)))
+	case IsNullOperator, IsNotNullOperator:
+		if term.lhs.IsConstant() {
+			if term.op.testNull(term.lhs.AsConstant()) == True {
+				return 1
+			}
+
+			return common.MaxInt
+		}
+		if term.op == IsNullOperator {
+			return nullReductionFactor
+		}
+
+		return 1
+	default:
+		return 1
+	}
+}
+
+func (term Term) equalReductionFactor(p Planner) int {
 	if term.lhs.IsFieldName() && term.rhs.IsFieldName() {
 		lhsName := term.lhs.AsFieldName()
 		rhsName := term.rhs.AsFieldName()
@@ -106,17 +932,38 @@ func (term Term) ReductionFactor(p Planner) int {
 		return p.EstDistinctVals(term.rhs.AsFieldName())
 	}
 
-	if term.lhs.AsConstant().Equal(term.rhs.AsConstant()) {
+	return term.constantReductionFactor()
+}
+
+// constantReductionFactor returns reduction factor of a term comparing constants.
+func (term Term) constantReductionFactor() int {
+	if term.op.compare(term.lhs.AsConstant(), term.rhs.AsConstant()) {
 		return 1
 	}
 
 	return common.MaxInt
 }
 
+// selectivityToReductionFactor converts the ratio of remaining records into reduction factor.
+func selectivityToReductionFactor(selectivity float64) int {
+	if selectivity <= 1/float64(common.MaxInt) {
+		return common.MaxInt
+	}
+	if selectivity >= 1 {
+		return 1
+	}
+
+	return int(stdmath.Round(1 / selectivity))
+}
+
 // EquatesWithConstant ...
 // F=c or c=F の形式かチェックする。ここで F は field name, c は Constant。
 // この形式の場合は Constant を返却する。
 func (term Term) EquatesWithConstant(fldName FieldName) (c Constant, ok bool) {
+	if term.op != EqualOperator {
+		return Constant{}, false
+	}
+
 	lhs, rhs := term.lhs, term.rhs
 	if lhs.IsFieldName() && lhs.AsFieldName() == fldName && rhs.IsConstant() {
 		return rhs.AsConstant(), true
@@ -130,6 +977,10 @@ func (term Term) EquatesWithConstant(fldName FieldName) (c Constant, ok bool) {
 // EquatesWithField ...
 // F1=F2 の形式かチェック. ここで F1, F2 は FieldName.
 func (term Term) EquatesWithField(fldName FieldName) (FieldName, bool) {
+	if term.op != EqualOperator {
+		return "", false
+	}
+
 	lhs, rhs := term.lhs, term.rhs
 	if lhs.IsFieldName() && lhs.AsFieldName() == fldName && rhs.IsFieldName() {
 		return rhs.AsFieldName(), true
@@ -140,9 +991,98 @@ func (term Term) EquatesWithField(fldName FieldName) (FieldName, bool) {
 	return "", false
 }
 
+// Fields returns field names which appear in the term.
+func (term Term) Fields() []FieldName {
+	if term.op == OrOperator || term.op == NotOperator {
+		flds := make([]FieldName, 0)
+		for _, pred := range term.preds {
+			for _, t := range pred.terms {
+				flds = append(flds, t.Fields()...)
+			}
+		}
+
+		return flds
+	}
+
+	return append(term.lhs.Fields(), term.rhs.Fields()...)
+}
+
+// RenameField returns the term whose field oldName is renamed to newName.
+func (term Term) RenameField(oldName, newName FieldName) Term {
+	if term.op == OrOperator || term.op == NotOperator {
+		preds := make([]*Predicate, 0, len(term.preds))
+		for _, pred := range term.preds {
+			preds = append(preds, pred.RenameField(oldName, newName))
+		}
+		term.preds = preds
+
+		return term
+	}
+
+	term.lhs = term.lhs.RenameField(oldName, newName)
+	term.rhs = term.rhs.RenameField(oldName, newName)
+
+	return term
+}
+
+// AppliesTo checks whether all fields of the term are in sch.
+func (term Term) AppliesTo(sch *Schema) bool {
+	for _, fld := range term.Fields() {
+		if !sch.HasField(fld) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// comparesWithConstant checks whether the term is in the form F op c or c op F.
+// c op F の場合は F op' c の形に直した演算子を返す.
+func (term Term) comparesWithConstant(fldName FieldName) (TermOperator, Constant, bool) {
+	if !term.op.IsComparison() {
+		return 0, Constant{}, false
+	}
+
+	lhs, rhs := term.lhs, term.rhs
+	if lhs.IsFieldName() && lhs.AsFieldName() == fldName && rhs.IsConstant() {
+		return term.op, rhs.AsConstant(), true
+	}
+
+	if rhs.IsFieldName() && rhs.AsFieldName() == fldName && lhs.IsConstant() {
+		switch term.op {
+		case LessOperator:
+			return GreaterOperator, lhs.AsConstant(), true
+		case LessEqualOperator:
+			return GreaterEqualOperator, lhs.AsConstant(), true
+		case GreaterOperator:
+			return LessOperator, lhs.AsConstant(), true
+		case GreaterEqualOperator:
+			return LessEqualOperator, lhs.AsConstant(), true
+		default:
+			return term.op, lhs.AsConstant(), true
+		}
+	}
+
+	return 0, Constant{}, false
+}
+
 // String stringfies the term.
 func (term Term) String() string {
-	return term.lhs.String() + "=" + term.rhs.String()
+	switch term.op {
+	case OrOperator:
+		conds := make([]string, 0, len(term.preds))
+		for _, pred := range term.preds {
+			conds = append(conds, pred.String())
+		}
+
+		return "(" + strings.Join(conds, " or ") + ")"
+	case NotOperator:
+		return "not (" + term.preds[0].String() + ")"
+	case IsNullOperator, IsNotNullOperator:
+		return term.lhs.String() + " " + term.op.String()
+	default:
+		return term.lhs.String() + term.op.String() + term.rhs.String()
+	}
 }
 
 // Predicate is node of predicate.
@@ -157,27 +1097,94 @@ func NewPredicate(terms []Term) *Predicate {
 	}
 }
 
+// Terms returns conjunctive terms of the predicate.
+func (pred *Predicate) Terms() []Term {
+	return pred.terms
+}
+
 // IsSatisfied checks whether a term is satisfied or not.
-func (pred *Predicate) IsSatisfied(s Scanner) bool {
+// Unknown になった record は条件を満たさないとみなす.
+func (pred *Predicate) IsSatisfied(s Scanner) (bool, error) {
+	t, err := pred.Evaluate(s)
+	if err != nil {
+		return false, err
+	}
+
+	return t == True, nil
+}
+
+// Evaluate evaluates the conjunction of terms in three-valued logic.
+func (pred *Predicate) Evaluate(s Scanner) (Truth, error) {
+	ret := True
 	for _, term := range pred.terms {
-		if !term.IsSatisfied(s) {
-			return false
+		t, err := term.Evaluate(s)
+		if err != nil {
+			return Unknown, err
+		}
+		ret = ret.And(t)
+		if ret == False {
+			return False, nil
 		}
 	}
 
-	return true
+	return ret, nil
+}
+
+// TypeCheck checks that all terms of the predicate can be evaluated on sch.
+func (pred *Predicate) TypeCheck(sch *Schema) error {
+	for _, term := range pred.terms {
+		if err := term.TypeCheck(sch); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // ReductionFactor ...
 func (pred *Predicate) ReductionFactor(p Planner) int {
 	factor := 1
 	for _, term := range pred.terms {
-		factor *= term.ReductionFactor(p)
+		rf := term.ReductionFactor(p)
+		if rf > 0 && factor > common.MaxInt/rf {
+			return common.MaxInt
+		}
+		factor *= rf
 	}
 
 	return factor
 }
 
+// SelectSubPred returns the sub predicate which consists of terms applying to sch.
+// table 単体に push down できる条件を取り出す.
+func (pred *Predicate) SelectSubPred(sch *Schema) *Predicate {
+	terms := make([]Term, 0)
+	for _, term := range pred.terms {
+		if term.AppliesTo(sch) {
+			terms = append(terms, term)
+		}
+	}
+
+	return NewPredicate(terms)
+}
+
+// JoinSubPred returns the sub predicate which applies to the union of sch1 and sch2 but neither of them alone.
+// 2 つの table を結合するときに初めて評価できる条件を取り出す.
+func (pred *Predicate) JoinSubPred(sch1, sch2 *Schema) *Predicate {
+	union := NewSchema()
+	union.AddAllFields(sch1)
+	union.AddAllFields(sch2)
+
+	terms := make([]Term, 0)
+	for _, term := range pred.terms {
+		if !term.AppliesTo(sch1) && !term.AppliesTo(sch2) && term.AppliesTo(union) {
+			terms = append(terms, term)
+		}
+	}
+
+	return NewPredicate(terms)
+}
+
 // EquatesWithConstant ...
 func (pred *Predicate) EquatesWithConstant(fldName FieldName) Constant {
 	for _, term := range pred.terms {
@@ -200,6 +1207,91 @@ func (pred *Predicate) EquatesWithField(fldName FieldName) FieldName {
 	return ""
 }
 
+// IndexRange returns the range of fldName restricted by terms such as F < c.
+// typ と型が異なる constant や NULL との比較は範囲の計算に使わない.
+func (pred *Predicate) IndexRange(fldName FieldName, typ FieldType) (IndexRange, bool) {
+	r := NewIndexRange()
+	for _, term := range pred.terms {
+		op, c, ok := term.comparesWithConstant(fldName)
+		if !ok || c.IsNull() || c.Type() != typ {
+			continue
+		}
+
+		switch op {
+		case EqualOperator:
+			r = r.WithLower(c, true).WithUpper(c, true)
+		case LessOperator:
+			r = r.WithUpper(c, false)
+		case LessEqualOperator:
+			r = r.WithUpper(c, true)
+		case GreaterOperator:
+			r = r.WithLower(c, false)
+		case GreaterEqualOperator:
+			r = r.WithLower(c, true)
+		case NotEqualOperator, OrOperator, NotOperator, IsNullOperator, IsNotNullOperator:
+			// 範囲を絞ることはできない.
+		}
+	}
+
+	return r, r.IsBounded()
+}
+
+// CompositeIndexRange returns the range of the key of an index on flds restricted by pred.
+// 先頭から等値条件で値が決まる field を prefix とし, その次の field の範囲条件を加える.
+// 返り値の int は等値条件で値が決まった field の数.
+func (pred *Predicate) CompositeIndexRange(flds []FieldName, sch *Schema) (IndexRange, int, bool) {
+	prefix := make([]Constant, 0, len(flds))
+	for _, fld := range flds {
+		val := pred.EquatesWithConstant(fld)
+		if val.IsNull() || val.Type() != sch.Type(fld) {
+			break
+		}
+		prefix = append(prefix, val)
+	}
+
+	if len(flds) == 1 {
+		r, ok := pred.IndexRange(flds[0], sch.Type(flds[0]))
+
+		return r, len(prefix), ok
+	}
+
+	r := NewIndexRange()
+	if len(prefix) > 0 {
+		key := NewTupleConstant(prefix...)
+		r = r.WithLower(key, true).WithUpper(key, true)
+	}
+
+	if len(prefix) < len(flds) {
+		next := flds[len(prefix)]
+		nr, _ := pred.IndexRange(next, sch.Type(next))
+		if c, inclusive, ok := nr.Lower(); ok {
+			r.lower, r.hasLower, r.lowerInclusive = appendKey(prefix, c), true, inclusive
+		}
+		if c, inclusive, ok := nr.Upper(); ok {
+			r.upper, r.hasUpper, r.upperInclusive = appendKey(prefix, c), true, inclusive
+		}
+	}
+
+	return r, len(prefix), r.IsBounded()
+}
+
+func appendKey(prefix []Constant, c Constant) Constant {
+	vals := make([]Constant, 0, len(prefix)+1)
+	vals = append(vals, prefix...)
+
+	return NewTupleConstant(append(vals, c)...)
+}
+
+// RenameField returns the predicate whose field oldName is renamed to newName.
+func (pred *Predicate) RenameField(oldName, newName FieldName) *Predicate {
+	terms := make([]Term, 0, len(pred.terms))
+	for _, term := range pred.terms {
+		terms = append(terms, term.RenameField(oldName, newName))
+	}
+
+	return NewPredicate(terms)
+}
+
 // String stringfies predicate.
 func (pred *Predicate) String() string {
 	if len(pred.terms) == 0 {
@@ -0,0 +1,561 @@
+package domain_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/goropikari/simpledbgo/common"
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/testing/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func intConst(v int32) domain.Expression {
+	return domain.NewConstExpression(domain.NewConstant(domain.Int32FieldType, v))
+}
+
+func smallintConst(v int16) domain.Expression {
+	return domain.NewConstExpression(domain.NewConstant(domain.Int16FieldType, v))
+}
+
+func bigintConst(v int64) domain.Expression {
+	return domain.NewConstExpression(domain.NewConstant(domain.Int64FieldType, v))
+}
+
+func realConst(v float32) domain.Expression {
+	return domain.NewConstExpression(domain.NewConstant(domain.Float32FieldType, v))
+}
+
+func doubleConst(v float64) domain.Expression {
+	return domain.NewConstExpression(domain.NewConstant(domain.Float64FieldType, v))
+}
+
+func TestTerm_IsSatisfied(t *testing.T) {
+	fld := domain.NewFieldNameExpression("a")
+
+	tests := []struct {
+		name     string
+		term     domain.Term
+		expected bool
+	}{
+		{name: "equal", term: domain.NewTerm(fld, intConst(10)), expected: true},
+		{name: "not equal", term: domain.NewComparisonTerm(domain.NotEqualOperator, fld, intConst(10)), expected: false},
+		{name: "less", term: domain.NewComparisonTerm(domain.LessOperator, fld, intConst(10)), expected: false},
+		{name: "less equal", term: domain.NewComparisonTerm(domain.LessEqualOperator, fld, intConst(10)), expected: true},
+		{name: "greater", term: domain.NewComparisonTerm(domain.GreaterOperator, intConst(11), fld), expected: true},
+		{name: "greater equal", term: domain.NewComparisonTerm(domain.GreaterEqualOperator, fld, intConst(11)), expected: false},
+		{
+			name: "compare different types",
+			term: domain.NewComparisonTerm(
+				domain.LessOperator,
+				fld,
+				domain.NewConstExpression(domain.NewConstant(domain.StringFieldType, "x")),
+			),
+			expected: false,
+		},
+		{
+			name:     "compare with null",
+			term:     domain.NewComparisonTerm(domain.NotEqualOperator, fld, domain.NewConstExpression(domain.NewNullConstant())),
+			expected: false,
+		},
+		{
+			name: "or",
+			term: domain.NewOrTerm([]*domain.Predicate{
+				domain.NewPredicate([]domain.Term{domain.NewTerm(fld, intConst(1))}),
+				domain.NewPredicate([]domain.Term{domain.NewTerm(fld, intConst(10))}),
+			}),
+			expected: true,
+		},
+		{
+			name:     "not",
+			term:     domain.NewNotTerm(domain.NewPredicate([]domain.Term{domain.NewTerm(fld, intConst(10))})),
+			expected: false,
+		},
+		{name: "is null", term: domain.NewIsNullTerm(fld), expected: false},
+		{name: "is not null", term: domain.NewIsNotNullTerm(fld), expected: true},
+		{name: "null is null", term: domain.NewIsNullTerm(domain.NewConstExpression(domain.NewNullConstant())), expected: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			s := mock.NewMockScanner(ctrl)
+			s.EXPECT().GetVal(domain.FieldName("a")).Return(domain.NewConstant(domain.Int32FieldType, int32(10)), nil).AnyTimes()
+
+			ok, err := tt.term.IsSatisfied(s)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, ok)
+		})
+	}
+}
+
+func TestPredicate_Evaluate(t *testing.T) {
+	fld := domain.NewFieldNameExpression("a")
+	null := domain.NewFieldNameExpression("b")
+	pred := func(terms ...domain.Term) *domain.Predicate {
+		return domain.NewPredicate(terms)
+	}
+
+	tests := []struct {
+		name     string
+		pred     *domain.Predicate
+		expected domain.Truth
+	}{
+		{name: "empty", pred: pred(), expected: domain.True},
+		{name: "compare with null field", pred: pred(domain.NewTerm(null, intConst(10))), expected: domain.Unknown},
+		{name: "null in expression", pred: pred(domain.NewTerm(domain.NewBinaryExpression(domain.AddOperator, null, intConst(1)), fld)), expected: domain.Unknown},
+		{name: "not unknown", pred: pred(domain.NewNotTerm(pred(domain.NewTerm(null, intConst(10))))), expected: domain.Unknown},
+		{name: "true and unknown", pred: pred(domain.NewTerm(fld, intConst(10)), domain.NewTerm(null, intConst(10))), expected: domain.Unknown},
+		{name: "false and unknown", pred: pred(domain.NewTerm(fld, intConst(1)), domain.NewTerm(null, intConst(10))), expected: domain.False},
+		{
+			name: "true or unknown",
+			pred: pred(domain.NewOrTerm([]*domain.Predicate{
+				pred(domain.NewTerm(null, intConst(10))),
+				pred(domain.NewTerm(fld, intConst(10))),
+			})),
+			expected: domain.True,
+		},
+		{
+			name: "false or unknown",
+			pred: pred(domain.NewOrTerm([]*domain.Predicate{
+				pred(domain.NewTerm(null, intConst(10))),
+				pred(domain.NewTerm(fld, intConst(1))),
+			})),
+			expected: domain.Unknown,
+		},
+		{name: "is null", pred: pred(domain.NewIsNullTerm(null)), expected: domain.True},
+		{name: "not is not null", pred: pred(domain.NewNotTerm(pred(domain.NewIsNotNullTerm(null)))), expected: domain.True},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			s := mock.NewMockScanner(ctrl)
+			s.EXPECT().GetVal(domain.FieldName("a")).Return(domain.NewConstant(domain.Int32FieldType, int32(10)), nil).AnyTimes()
+			s.EXPECT().GetVal(domain.FieldName("b")).Return(domain.NewNullConstant(), nil).AnyTimes()
+
+			truth, err := tt.pred.Evaluate(s)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, truth)
+			ok, err := tt.pred.IsSatisfied(s)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected == domain.True, ok)
+		})
+	}
+
+	t.Run("error in expression", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := mock.NewMockScanner(ctrl)
+		s.EXPECT().GetVal(domain.FieldName("a")).Return(domain.NewConstant(domain.Int32FieldType, int32(10)), nil).AnyTimes()
+
+		p := pred(domain.NewOrTerm([]*domain.Predicate{
+			pred(domain.NewTerm(fld, intConst(10))),
+			pred(domain.NewTerm(domain.NewBinaryExpression(domain.DivideOperator, fld, intConst(0)), intConst(1))),
+		}))
+		_, err := p.Evaluate(s)
+		require.ErrorIs(t, err, domain.ErrDivisionByZero)
+		_, err = p.IsSatisfied(s)
+		require.ErrorIs(t, err, domain.ErrDivisionByZero)
+	})
+}
+
+func TestPredicate_TypeCheck(t *testing.T) {
+	sch := domain.NewSchema()
+	sch.AddInt32Field("a")
+	sch.AddStringField("b", 10)
+	sch.AddField("d", domain.DateFieldType, 0)
+
+	str := func(s string) domain.Expression {
+		return domain.NewConstExpression(domain.NewConstant(domain.StringFieldType, s))
+	}
+	fld := domain.NewFieldNameExpression
+	pred := func(terms ...domain.Term) *domain.Predicate {
+		return domain.NewPredicate(terms)
+	}
+
+	tests := []struct {
+		name string
+		pred *domain.Predicate
+		err  error
+	}{
+		{name: "same types", pred: pred(domain.NewTerm(fld("a"), intConst(1)))},
+		{name: "numbers", pred: pred(domain.NewComparisonTerm(domain.LessOperator, fld("a"), doubleConst(1.5)))},
+		{name: "string literal as date", pred: pred(domain.NewComparisonTerm(domain.GreaterOperator, fld("d"), str("2024-01-01")))},
+		{name: "null", pred: pred(domain.NewTerm(fld("b"), domain.NewConstExpression(domain.NewNullConstant())))},
+		{name: "arithmetic on string", pred: pred(domain.NewTerm(domain.NewBinaryExpression(domain.AddOperator, fld("a"), fld("b")), intConst(1))), err: domain.ErrTypeMismatch},
+		{name: "string field and date field", pred: pred(domain.NewTerm(fld("b"), fld("d"))), err: domain.ErrTypeMismatch},
+		{name: "integer and string", pred: pred(domain.NewTerm(fld("a"), str("x"))), err: domain.ErrTypeMismatch},
+		{name: "in or", pred: pred(domain.NewOrTerm([]*domain.Predicate{pred(domain.NewTerm(fld("a"), fld("b")))})), err: domain.ErrTypeMismatch},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.pred.TypeCheck(sch)
+			if tt.err == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+
+	t.Run("unknown field", func(t *testing.T) {
+		require.Error(t, pred(domain.NewIsNullTerm(fld("x"))).TypeCheck(sch))
+	})
+}
+
+func TestTerm_ReductionFactor(t *testing.T) {
+	fld := domain.NewFieldNameExpression("a")
+
+	tests := []struct {
+		name     string
+		term     domain.Term
+		expected int
+	}{
+		{name: "equal", term: domain.NewTerm(fld, intConst(1)), expected: 10},
+		{name: "not equal", term: domain.NewComparisonTerm(domain.NotEqualOperator, fld, intConst(1)), expected: 1},
+		{name: "range", term: domain.NewComparisonTerm(domain.LessOperator, fld, intConst(1)), expected: 3},
+		{name: "true constant range", term: domain.NewComparisonTerm(domain.LessOperator, intConst(0), intConst(1)), expected: 1},
+		{name: "false constant range", term: domain.NewComparisonTerm(domain.LessOperator, intConst(1), intConst(0)), expected: common.MaxInt},
+		{
+			name: "or",
+			term: domain.NewOrTerm([]*domain.Predicate{
+				domain.NewPredicate([]domain.Term{domain.NewTerm(fld, intConst(1))}),
+				domain.NewPredicate([]domain.Term{domain.NewTerm(fld, intConst(2))}),
+			}),
+			// 1 - 0.9 * 0.9 = 0.19
+			expected: 5,
+		},
+		{
+			name:     "not",
+			term:     domain.NewNotTerm(domain.NewPredicate([]domain.Term{domain.NewTerm(fld, intConst(1))})),
+			expected: 1,
+		},
+		{
+			name:     "not of always true",
+			term:     domain.NewNotTerm(domain.NewPredicate([]domain.Term{domain.NewTerm(intConst(1), intConst(1))})),
+			expected: common.MaxInt,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			p := mock.NewMockPlanner(ctrl)
+			p.EXPECT().EstDistinctVals(domain.FieldName("a")).Return(10).AnyTimes()
+
+			require.Equal(t, tt.expected, tt.term.ReductionFactor(p))
+		})
+	}
+}
+
+func TestPredicate_ReductionFactor_saturate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	p := mock.NewMockPlanner(ctrl)
+
+	never := domain.NewTerm(intConst(0), intConst(1))
+	pred := domain.NewPredicate([]domain.Term{never, never, never})
+
+	require.Equal(t, common.MaxInt, pred.ReductionFactor(p))
+}
+
+func TestPredicate_SubPred(t *testing.T) {
+	a := domain.NewFieldNameExpression("a")
+	b := domain.NewFieldNameExpression("b")
+	c := domain.NewFieldNameExpression("c")
+
+	sch1 := domain.NewSchema()
+	sch1.AddInt32Field("a")
+	sch2 := domain.NewSchema()
+	sch2.AddInt32Field("b")
+
+	selTerm := domain.NewTerm(a, intConst(1))
+	joinTerm := domain.NewTerm(a, b)
+	orTerm := domain.NewOrTerm([]*domain.Predicate{
+		domain.NewPredicate([]domain.Term{domain.NewTerm(a, intConst(1))}),
+		domain.NewPredicate([]domain.Term{domain.NewTerm(b, intConst(2))}),
+	})
+	otherTerm := domain.NewTerm(a, c)
+	pred := domain.NewPredicate([]domain.Term{selTerm, joinTerm, orTerm, otherTerm})
+
+	require.Equal(t, []domain.Term{selTerm}, pred.SelectSubPred(sch1).Terms())
+	require.Equal(t, []domain.Term{}, pred.SelectSubPred(sch2).Terms())
+	require.Equal(t, []domain.Term{joinTerm, orTerm}, pred.JoinSubPred(sch1, sch2).Terms())
+}
+
+func TestPredicate_IndexRange(t *testing.T) {
+	a := domain.NewFieldNameExpression("a")
+	b := domain.NewFieldNameExpression("b")
+	str := domain.NewConstExpression(domain.NewConstant(domain.StringFieldType, "x"))
+
+	tests := []struct {
+		name     string
+		terms    []domain.Term
+		expected string
+		ok       bool
+	}{
+		{
+			name: "closed open",
+			terms: []domain.Term{
+				domain.NewComparisonTerm(domain.GreaterEqualOperator, a, intConst(10)),
+				domain.NewComparisonTerm(domain.LessOperator, a, intConst(20)),
+			},
+			expected: "[10, 20)",
+			ok:       true,
+		},
+		{
+			name:     "constant on lhs",
+			terms:    []domain.Term{domain.NewComparisonTerm(domain.LessOperator, intConst(10), a)},
+			expected: "(10, inf)",
+			ok:       true,
+		},
+		{
+			name: "narrowest bounds",
+			terms: []domain.Term{
+				domain.NewComparisonTerm(domain.GreaterOperator, a, intConst(1)),
+				domain.NewComparisonTerm(domain.GreaterEqualOperator, a, intConst(5)),
+				domain.NewComparisonTerm(domain.LessEqualOperator, a, intConst(8)),
+				domain.NewComparisonTerm(domain.LessOperator, a, intConst(8)),
+			},
+			expected: "[5, 8)",
+			ok:       true,
+		},
+		{
+			name:     "equal",
+			terms:    []domain.Term{domain.NewTerm(a, intConst(3))},
+			expected: "[3, 3]",
+			ok:       true,
+		},
+		{
+			name: "ignore other field and type",
+			terms: []domain.Term{
+				domain.NewComparisonTerm(domain.LessOperator, b, intConst(3)),
+				domain.NewComparisonTerm(domain.LessOperator, a, str),
+				domain.NewComparisonTerm(domain.NotEqualOperator, a, intConst(3)),
+			},
+			expected: "(-inf, inf)",
+			ok:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, ok := domain.NewPredicate(tt.terms).IndexRange("a", domain.Int32FieldType)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.expected, r.String())
+		})
+	}
+}
+
+func TestPredicate_CompositeIndexRange(t *testing.T) {
+	a := domain.NewFieldNameExpression("a")
+	b := domain.NewFieldNameExpression("b")
+	c := domain.NewFieldNameExpression("c")
+
+	sch := domain.NewSchema()
+	sch.AddInt32Field("a")
+	sch.AddInt32Field("b")
+	sch.AddInt32Field("c")
+
+	key := func(vals ...int32) domain.Constant {
+		consts := make([]domain.Constant, 0, len(vals))
+		for _, v := range vals {
+			consts = append(consts, domain.NewConstant(domain.Int32FieldType, v))
+		}
+
+		return domain.NewTupleConstant(consts...)
+	}
+
+	tests := []struct {
+		name     string
+		terms    []domain.Term
+		expected string
+		numEq    int
+		ok       bool
+		in       []domain.Constant
+		out      []domain.Constant
+	}{
+		{
+			name:     "full key",
+			terms:    []domain.Term{domain.NewTerm(b, intConst(2)), domain.NewTerm(a, intConst(1)), domain.NewTerm(c, intConst(3))},
+			expected: "[(1, 2, 3), (1, 2, 3)]",
+			numEq:    3,
+			ok:       true,
+			in:       []domain.Constant{key(1, 2, 3)},
+			out:      []domain.Constant{key(1, 2, 4)},
+		},
+		{
+			name:     "prefix",
+			terms:    []domain.Term{domain.NewTerm(a, intConst(1))},
+			expected: "[(1), (1)]",
+			numEq:    1,
+			ok:       true,
+			in:       []domain.Constant{key(1, -5, 0), key(1, 2, 3)},
+			out:      []domain.Constant{key(0, 9, 9), key(2, 0, 0)},
+		},
+		{
+			name: "prefix and range of next field",
+			terms: []domain.Term{
+				domain.NewTerm(a, intConst(1)),
+				domain.NewComparisonTerm(domain.GreaterOperator, b, intConst(2)),
+				domain.NewComparisonTerm(domain.LessEqualOperator, b, intConst(4)),
+			},
+			expected: "((1, 2), (1, 4)]",
+			numEq:    1,
+			ok:       true,
+			in:       []domain.Constant{key(1, 3, 0), key(1, 4, 9)},
+			out:      []domain.Constant{key(1, 2, 9), key(1, 5, 0), key(2, 3, 0)},
+		},
+		{
+			name:     "range of first field",
+			terms:    []domain.Term{domain.NewComparisonTerm(domain.GreaterEqualOperator, a, intConst(5))},
+			expected: "[(5), inf)",
+			numEq:    0,
+			ok:       true,
+			in:       []domain.Constant{key(5, 0, 0), key(6, -1, -1)},
+			out:      []domain.Constant{key(4, 9, 9)},
+		},
+		{
+			name:     "not prefix",
+			terms:    []domain.Term{domain.NewTerm(b, intConst(2))},
+			expected: "(-inf, inf)",
+			numEq:    0,
+			ok:       false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			r, numEq, ok := domain.NewPredicate(tt.terms).CompositeIndexRange([]domain.FieldName{"a", "b", "c"}, sch)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.numEq, numEq)
+			require.Equal(t, tt.expected, r.String())
+			for _, k := range tt.in {
+				require.True(t, r.Contains(k), k.String())
+			}
+			for _, k := range tt.out {
+				require.False(t, r.Contains(k), k.String())
+			}
+		})
+	}
+}
+
+func TestExpression_Evaluate(t *testing.T) {
+	a := domain.NewFieldNameExpression("a")
+	b := domain.NewFieldNameExpression("b")
+	str := domain.NewConstExpression(domain.NewConstant(domain.StringFieldType, "x"))
+
+	tests := []struct {
+		name     string
+		expr     domain.Expression
+		expected domain.Constant
+	}{
+		{name: "add", expr: domain.NewBinaryExpression(domain.AddOperator, a, intConst(3)), expected: domain.NewConstant(domain.Int32FieldType, int32(13))},
+		{name: "subtract", expr: domain.NewBinaryExpression(domain.SubtractOperator, a, intConst(3)), expected: domain.NewConstant(domain.Int32FieldType, int32(7))},
+		{name: "multiply", expr: domain.NewBinaryExpression(domain.MultiplyOperator, a, intConst(3)), expected: domain.NewConstant(domain.Int32FieldType, int32(30))},
+		{name: "divide", expr: domain.NewBinaryExpression(domain.DivideOperator, a, intConst(3)), expected: domain.NewConstant(domain.Int32FieldType, int32(3))},
+		{name: "modulo", expr: domain.NewBinaryExpression(domain.ModuloOperator, a, intConst(3)), expected: domain.NewConstant(domain.Int32FieldType, int32(1))},
+		{name: "negate", expr: domain.NewNegateExpression(a), expected: domain.NewConstant(domain.Int32FieldType, int32(-10))},
+		{name: "concat", expr: domain.NewBinaryExpression(domain.ConcatOperator, b, str), expected: domain.NewConstant(domain.StringFieldType, "bobx")},
+		{name: "concat int", expr: domain.NewBinaryExpression(domain.ConcatOperator, b, a), expected: domain.NewConstant(domain.StringFieldType, "bob10")},
+		{name: "null", expr: domain.NewBinaryExpression(domain.AddOperator, a, domain.NewConstExpression(domain.NewNullConstant())), expected: domain.NewNullConstant()},
+		{name: "int and bigint", expr: domain.NewBinaryExpression(domain.MultiplyOperator, a, bigintConst(1<<40)), expected: domain.NewConstant(domain.Int64FieldType, int64(10)<<40)},
+		{name: "smallint and smallint", expr: domain.NewBinaryExpression(domain.AddOperator, smallintConst(1), smallintConst(2)), expected: domain.NewConstant(domain.Int16FieldType, int16(3))},
+		{name: "int and double", expr: domain.NewBinaryExpression(domain.DivideOperator, a, doubleConst(4)), expected: domain.NewConstant(domain.Float64FieldType, 2.5)},
+		{name: "real and real", expr: domain.NewBinaryExpression(domain.SubtractOperator, realConst(1.5), realConst(0.25)), expected: domain.NewConstant(domain.Float32FieldType, float32(1.25))},
+		{name: "negate double", expr: domain.NewNegateExpression(doubleConst(1.5)), expected: domain.NewConstant(domain.Float64FieldType, -1.5)},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			s := mock.NewMockScanner(ctrl)
+			s.EXPECT().GetVal(domain.FieldName("a")).Return(domain.NewConstant(domain.Int32FieldType, int32(10)), nil).AnyTimes()
+			s.EXPECT().GetVal(domain.FieldName("b")).Return(domain.NewConstant(domain.StringFieldType, "bob"), nil).AnyTimes()
+
+			actual, err := tt.expr.Evaluate(s)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+
+	t.Run("division by zero", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		s := mock.NewMockScanner(ctrl)
+		s.EXPECT().GetVal(domain.FieldName("a")).Return(domain.NewConstant(domain.Int32FieldType, int32(10)), nil)
+
+		_, err := domain.NewBinaryExpression(domain.DivideOperator, a, intConst(0)).Evaluate(s)
+		require.ErrorIs(t, err, domain.ErrDivisionByZero)
+	})
+
+	errTests := []struct {
+		name string
+		expr domain.Expression
+		err  error
+	}{
+		{name: "int overflow", expr: domain.NewBinaryExpression(domain.MultiplyOperator, intConst(1<<20), intConst(1<<20)), err: domain.ErrNumericOutOfRange},
+		{name: "smallint overflow", expr: domain.NewBinaryExpression(domain.AddOperator, smallintConst(32767), smallintConst(1)), err: domain.ErrNumericOutOfRange},
+		{name: "bigint overflow", expr: domain.NewBinaryExpression(domain.AddOperator, bigintConst(math.MaxInt64), bigintConst(1)), err: domain.ErrNumericOutOfRange},
+		{name: "double division by zero", expr: domain.NewBinaryExpression(domain.DivideOperator, doubleConst(1), doubleConst(0)), err: domain.ErrDivisionByZero},
+		{name: "modulo of double", expr: domain.NewBinaryExpression(domain.ModuloOperator, doubleConst(1), intConst(1)), err: domain.ErrTypeMismatch},
+	}
+
+	for _, tt := range errTests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.expr.Evaluate(nil)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestExpression_Type(t *testing.T) {
+	sch := domain.NewSchema()
+	sch.AddInt32Field("a")
+	sch.AddStringField("b", 9)
+
+	a := domain.NewFieldNameExpression("a")
+	b := domain.NewFieldNameExpression("b")
+
+	tests := []struct {
+		name   string
+		expr   domain.Expression
+		typ    domain.FieldType
+		length int
+		err    bool
+	}{
+		{name: "field", expr: b, typ: domain.StringFieldType, length: 9},
+		{name: "arithmetic", expr: domain.NewBinaryExpression(domain.AddOperator, a, intConst(1)), typ: domain.Int32FieldType},
+		{name: "bigint arithmetic", expr: domain.NewBinaryExpression(domain.AddOperator, a, bigintConst(1)), typ: domain.Int64FieldType},
+		{name: "real arithmetic", expr: domain.NewBinaryExpression(domain.AddOperator, realConst(1), realConst(1)), typ: domain.Float32FieldType},
+		{name: "real and integer", expr: domain.NewBinaryExpression(domain.AddOperator, realConst(1), smallintConst(1)), typ: domain.Float64FieldType},
+		{name: "concat", expr: domain.NewBinaryExpression(domain.ConcatOperator, b, a), typ: domain.StringFieldType, length: 20},
+		{name: "arithmetic on string", expr: domain.NewBinaryExpression(domain.AddOperator, a, b), err: true},
+		{name: "negate string", expr: domain.NewNegateExpression(b), err: true},
+		{name: "unknown field", expr: domain.NewNegateExpression(domain.NewFieldNameExpression("c")), err: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			typ, length, err := tt.expr.Type(sch)
+			if tt.err {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.typ, typ)
+			require.Equal(t, tt.length, length)
+		})
+	}
+}
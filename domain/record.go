@@ -2,6 +2,7 @@ package domain
 
 import (
 	"log"
+	"strings"
 
 	"github.com/goropikari/simpledbgo/common"
 	"github.com/goropikari/simpledbgo/errors"
@@ -10,9 +11,62 @@ import (
 // ErrFieldNotFound is an error that means specified field is not found.
 var ErrFieldNotFound = errors.New("specified field is not found")
 
+// ErrDuplicateField is an error that means specified field already exists.
+var ErrDuplicateField = errors.New("specified field already exists")
+
+// ErrNullNotSupported is an error that means the table can't store NULL.
+var ErrNullNotSupported = errors.New("table created before NULL was supported can't store NULL")
+
+// ErrUnknownRecordFormat is an error that means the record format is not supported.
+var ErrUnknownRecordFormat = errors.New("unknown record format")
+
+// RecordFormat is a format of records in a table file.
+type RecordFormat string
+
+const (
+	// FixedRecordFormat is a format which stores records in fixed size slots.
+	FixedRecordFormat RecordFormat = "fixed"
+
+	// SlottedRecordFormat is a format which stores variable-length records in slotted pages.
+	SlottedRecordFormat RecordFormat = "slotted"
+
+	// DefaultRecordFormat is a record format used when CREATE TABLE doesn't specify USING.
+	DefaultRecordFormat = FixedRecordFormat
+
+	// MaxRecordFormatLength is maximum record format name length.
+	MaxRecordFormatLength = 16
+)
+
+// NewRecordFormat constructs RecordFormat.
+func NewRecordFormat(format string) (RecordFormat, error) {
+	switch RecordFormat(strings.ToLower(format)) {
+	case FixedRecordFormat:
+		return FixedRecordFormat, nil
+	case SlottedRecordFormat:
+		return SlottedRecordFormat, nil
+	default:
+		return "", errors.Wrap(ErrUnknownRecordFormat, format)
+	}
+}
+
+// String stringfies record format.
+func (format RecordFormat) String() string {
+	return string(format)
+}
+
 const (
 	// RecordOffset is offset of record.
 	RecordOffset = common.Int32Length
+
+	// versionLength is the byte length of the version of a record in a versioned layout.
+	versionLength = 2 * common.Int32Length
+
+	// nullBitsPerWord is the number of null flags in a word of null bitmap.
+	nullBitsPerWord = 32
+
+	// intervalLength is the byte length of INTERVAL value.
+	// マイクロ秒 (int64), 日 (int32), 月 (int32) の順に保存する.
+	intervalLength = common.Int64Length + 2*common.Int32Length
 )
 
 // Schema is model of table schema.
@@ -57,6 +111,16 @@ func (schema *Schema) AddStringField(fldname FieldName, length int) {
 	schema.AddField(fldname, StringFieldType, length)
 }
 
+// AddTextField adds a text field.
+func (schema *Schema) AddTextField(fldname FieldName) {
+	schema.AddField(fldname, TextFieldType, 0)
+}
+
+// AddBytesField adds a bytea field.
+func (schema *Schema) AddBytesField(fldname FieldName) {
+	schema.AddField(fldname, BytesFieldType, 0)
+}
+
 // Add adds other's field into the schema.
 func (schema *Schema) Add(fldname FieldName, other *Schema) {
 	typ := other.Type(fldname)
@@ -66,6 +130,23 @@ func (schema *Schema) Add(fldname FieldName, other *Schema) {
 	schema.AddField(fldname, typ, length)
 }
 
+// SetFieldConstraint sets NOT NULL, DEFAULT and CHECK constraints of the field.
+// Add や AddAllFields では制約はコピーされない.
+func (schema *Schema) SetFieldConstraint(fldname FieldName, c FieldConstraint) {
+	if v, found := schema.info[fldname]; found {
+		v.constraint = c
+	}
+}
+
+// FieldConstraint returns NOT NULL, DEFAULT and CHECK constraints of the field.
+func (schema *Schema) FieldConstraint(fldname FieldName) FieldConstraint {
+	if v, found := schema.info[fldname]; found {
+		return v.constraint
+	}
+
+	return NewFieldConstraint()
+}
+
 // AddAllFields adds all fields of other into the schema.
 func (schema *Schema) AddAllFields(other *Schema) {
 	for _, fld := range other.fields {
@@ -96,47 +177,173 @@ func (schema *Schema) Length(fldname FieldName) int {
 	return -1
 }
 
+// StoredValue converts val into the value stored in the field.
+// 整数の literal などを field の型に変換し, NUMERIC(p, s) は scale に丸める.
+func (schema *Schema) StoredValue(fldname FieldName, val Constant) (Constant, error) {
+	typ := schema.Type(fldname)
+	val, err := val.ConvertTo(typ)
+	if err != nil {
+		return Constant{}, errors.Err(err, "ConvertTo")
+	}
+	if val.IsNull() || typ != NumericFieldType {
+		return val, nil
+	}
+
+	d, err := val.AsDecimal()
+	if err != nil {
+		return Constant{}, errors.Wrap(ErrTypeMismatch, fldname.String())
+	}
+	d, err = d.roundTo(schema.Length(fldname))
+	if err != nil {
+		return Constant{}, errors.Err(err, "roundTo")
+	}
+
+	return NewDecimalConstant(d), nil
+}
+
 // Layout is model of table layout.
 type Layout struct {
-	schema   *Schema
-	offsets  map[FieldName]int64
-	slotsize int64
+	schema    *Schema
+	offsets   map[FieldName]int64
+	nullBits  map[FieldName]int
+	slotsize  int64
+	format    RecordFormat
+	versioned bool
+	legacy    bool
 }
 
 // NewLayout constructs Layout.
 func NewLayout(schema *Schema) *Layout {
+	return newFixedLayout(schema, false)
+}
+
+// NewVersionedLayout constructs Layout whose records have xmin and xmax.
+// multi-version mode で古い version を残せるのは versioned な layout の table だけ.
+func NewVersionedLayout(schema *Schema) *Layout {
+	return newFixedLayout(schema, true)
+}
+
+func newFixedLayout(schema *Schema, versioned bool) *Layout {
 	pos := int64(RecordOffset) // flag for used/unused
+	if versioned {
+		pos += versionLength
+	}
+	pos += nullBitmapLength(len(schema.fields))
 	offsets := make(map[FieldName]int64)
 	for _, fld := range schema.fields {
 		offsets[fld] = pos
 
 		// length in bytes
 		switch schema.Type(fld) {
-		case Int32FieldType:
+		case Int32FieldType, Int16FieldType, BoolFieldType, Float32FieldType, DateFieldType:
 			pos += common.Int32Length
+		case Int64FieldType, Float64FieldType, TimestampFieldType, TimestampTzFieldType:
+			pos += common.Int64Length
+		case IntervalFieldType:
+			pos += intervalLength
+		case NumericFieldType:
+			pos += decimalLength
 		case StringFieldType:
 			pos += common.Int32Length + int64(schema.Length(fld))
-		case UnknownFieldType:
+		case TextFieldType, BytesFieldType:
+			// overflow page の先頭の block 番号.
+			pos += common.Int32Length
+		case UnknownFieldType, TupleFieldType:
 			log.Fatal(errors.New("Invalid field type"))
 		}
 	}
 
 	return &Layout{
-		schema:   schema,
-		offsets:  offsets,
-		slotsize: pos,
+		schema:    schema,
+		offsets:   offsets,
+		nullBits:  nullBits(schema),
+		slotsize:  pos,
+		format:    FixedRecordFormat,
+		versioned: versioned,
 	}
 }
 
 // NewLayoutWithFields constructs a Layout with fields.
-func NewLayoutWithFields(sch *Schema, offsets map[FieldName]int64, slotsize int64) *Layout {
+func NewLayoutWithFields(sch *Schema, offsets map[FieldName]int64, slotsize int64, versioned bool) *Layout {
+	return &Layout{
+		schema:    sch,
+		offsets:   offsets,
+		nullBits:  nullBits(sch),
+		slotsize:  slotsize,
+		format:    FixedRecordFormat,
+		versioned: versioned,
+	}
+}
+
+// NewLegacyLayout constructs a Layout of the records written before the null bitmap was introduced.
+// slot は usage flag と値だけからなり, NULL を保存できない.
+func NewLegacyLayout(sch *Schema, offsets map[FieldName]int64, slotsize int64) *Layout {
 	return &Layout{
 		schema:   sch,
 		offsets:  offsets,
+		nullBits: nullBits(sch),
 		slotsize: slotsize,
+		format:   FixedRecordFormat,
+		legacy:   true,
 	}
 }
 
+// NewSlottedLayout constructs a Layout of slotted pages.
+// record は可変長なので field の offset は持たず, slot size は record の最大の byte 長とする.
+func NewSlottedLayout(sch *Schema) *Layout {
+	return newSlottedLayout(sch, false)
+}
+
+// NewVersionedSlottedLayout constructs a Layout of slotted pages whose records have xmin and xmax.
+func NewVersionedSlottedLayout(sch *Schema) *Layout {
+	return newSlottedLayout(sch, true)
+}
+
+func newSlottedLayout(sch *Schema, versioned bool) *Layout {
+	size := nullBitmapLength(len(sch.fields))
+	if versioned {
+		size += versionLength
+	}
+	for _, fld := range sch.fields {
+		size += common.Int32Length
+		switch {
+		case sch.Type(fld) == StringFieldType:
+			size += wordAligned(int64(sch.Length(fld)))
+		case sch.Type(fld).IsInt64Sized():
+			size += common.Int32Length
+		case sch.Type(fld) == IntervalFieldType:
+			size += intervalLength - common.Int32Length
+		case sch.Type(fld) == NumericFieldType:
+			size += decimalLength - common.Int32Length
+		}
+	}
+
+	return &Layout{
+		schema:    sch,
+		offsets:   make(map[FieldName]int64),
+		nullBits:  nullBits(sch),
+		slotsize:  size,
+		format:    SlottedRecordFormat,
+		versioned: versioned,
+	}
+}
+
+// nullBitmapLength returns the byte length of the null bitmap for n fields.
+// bitmap は int32 単位で確保する.
+func nullBitmapLength(n int) int64 {
+	return common.Int32Length * int64((n+nullBitsPerWord-1)/nullBitsPerWord)
+}
+
+// nullBits assigns the bit of null bitmap to each field in the schema order.
+func nullBits(sch *Schema) map[FieldName]int {
+	bits := make(map[FieldName]int, len(sch.fields))
+	for i, fld := range sch.fields {
+		bits[fld] = i
+	}
+
+	return bits
+}
+
 // Schema returns schema.
 func (layout *Layout) Schema() *Schema {
 	return layout.schema
@@ -152,11 +359,53 @@ func (layout *Layout) SlotSize() int64 {
 	return layout.slotsize
 }
 
+// Format returns the record format.
+func (layout *Layout) Format() RecordFormat {
+	return layout.format
+}
+
+// Versioned checks whether records of the layout have xmin and xmax.
+// version を持たない layout は, この field が無かった頃に作られた table や catalog, 一時 table に使う.
+func (layout *Layout) Versioned() bool {
+	return layout.versioned
+}
+
+// Legacy checks whether records of the layout have no null bitmap.
+func (layout *Layout) Legacy() bool {
+	return layout.legacy
+}
+
+// versionBytes returns the byte length of the version in a record of the layout.
+func (layout *Layout) versionBytes() int64 {
+	if layout.versioned {
+		return versionLength
+	}
+
+	return 0
+}
+
 // Length returns byte size of given field name.
 func (layout *Layout) Length(fldName FieldName) int {
 	return layout.schema.Length(fldName)
 }
 
+// NullBitmapLength returns the byte length of the null bitmap.
+func (layout *Layout) NullBitmapLength() int64 {
+	if layout.legacy {
+		return 0
+	}
+
+	return nullBitmapLength(len(layout.schema.fields))
+}
+
+// NullFlagPosition returns the offset of the null bitmap word of given field and its bit mask.
+func (layout *Layout) NullFlagPosition(fldName FieldName) (int64, int32) {
+	bit := layout.nullBits[fldName]
+	offset := int64(RecordOffset) + layout.versionBytes() + common.Int32Length*int64(bit/nullBitsPerWord)
+
+	return offset, int32(uint32(1) << (bit % nullBitsPerWord))
+}
+
 // SlotCondition is flag of record slot condition.
 type SlotCondition = int32
 
@@ -167,12 +416,52 @@ const (
 	Used
 )
 
+// RecordPager is an interface of a page which stores records of a table.
+type RecordPager interface {
+	GetInt32(SlotID, FieldName) (int32, error)
+	SetInt32(SlotID, FieldName, int32) error
+	GetInt64(SlotID, FieldName) (int64, error)
+	SetInt64(SlotID, FieldName, int64) error
+	GetInterval(SlotID, FieldName) (Interval, error)
+	SetInterval(SlotID, FieldName, Interval) error
+	GetDecimal(SlotID, FieldName) (Decimal, error)
+	SetDecimal(SlotID, FieldName, Decimal) error
+	GetString(SlotID, FieldName) (string, error)
+	SetString(SlotID, FieldName, string) error
+	IsNull(SlotID, FieldName) (bool, error)
+	SetNull(SlotID, FieldName) error
+	GetVersion(SlotID) (Version, error)
+	SetVersion(SlotID, Version) error
+	Delete(SlotID) error
+	Format() error
+	Clear() error
+	NextUsedSlot(SlotID) (SlotID, error)
+	InsertAfter(SlotID) (SlotID, error)
+	Block() Block
+}
+
+// NewRecordPager constructs a page of the record format of the layout.
+func NewRecordPager(txn Transaction, blk Block, layout *Layout) (RecordPager, error) {
+	if layout.format == SlottedRecordFormat {
+		return NewSlottedPage(txn, blk, layout)
+	}
+
+	return NewRecordPage(txn, blk, layout)
+}
+
 // RecordPage is a model of RecordPage.
-// Slot は record に usage flag をもたせたもの。
+// Slot は record に usage flag, version と null bitmap をもたせたもの。
 // Slot structure
-// -------------------------------
-// | usage flag (int32) | record |
-// -------------------------------
+// ----------------------------------------------------------------------------
+// | usage flag (int32) | xmin (int32) | xmax (int32) | null bitmap | record |
+// ----------------------------------------------------------------------------
+//
+// xmin と xmax は record を作った transaction と削除した transaction の番号.
+// versioned でない layout の slot には xmin と xmax が無い.
+//
+// null bitmap は field ごとに 1 bit で, 立っている field の値は NULL を表す.
+// field 数 32 ごとに int32 を 1 つ使う.
+// null bitmap が導入される前に作られた legacy な layout の slot には null bitmap も無い.
 //
 // record structure
 // ---------------------------
@@ -208,7 +497,111 @@ func (page *RecordPage) GetInt32(slotID SlotID, fldname FieldName) (int32, error
 func (page *RecordPage) SetInt32(slotID SlotID, fldname FieldName, val int32) error {
 	offset := page.offset(slotID) + page.layout.Offset(fldname)
 
-	return page.txn.SetInt32(page.blk, offset, val, true)
+	if err := page.txn.SetInt32(page.blk, offset, val, true); err != nil {
+		return errors.Err(err, "SetInt32")
+	}
+
+	return page.setNullFlag(slotID, fldname, false)
+}
+
+// GetInt64 gets int64 from the block.
+func (page *RecordPage) GetInt64(slotID SlotID, fldname FieldName) (int64, error) {
+	offset := page.offset(slotID) + page.layout.Offset(fldname)
+
+	return page.txn.GetInt64(page.blk, offset)
+}
+
+// SetInt64 sets int64 to the block.
+func (page *RecordPage) SetInt64(slotID SlotID, fldname FieldName, val int64) error {
+	offset := page.offset(slotID) + page.layout.Offset(fldname)
+
+	if err := page.txn.SetInt64(page.blk, offset, val, true); err != nil {
+		return errors.Err(err, "SetInt64")
+	}
+
+	return page.setNullFlag(slotID, fldname, false)
+}
+
+// GetInterval gets interval from the block.
+func (page *RecordPage) GetInterval(slotID SlotID, fldname FieldName) (Interval, error) {
+	offset := page.offset(slotID) + page.layout.Offset(fldname)
+
+	micros, err := page.txn.GetInt64(page.blk, offset)
+	if err != nil {
+		return Interval{}, errors.Err(err, "GetInt64")
+	}
+
+	days, err := page.txn.GetInt32(page.blk, offset+common.Int64Length)
+	if err != nil {
+		return Interval{}, errors.Err(err, "GetInt32")
+	}
+
+	months, err := page.txn.GetInt32(page.blk, offset+common.Int64Length+common.Int32Length)
+	if err != nil {
+		return Interval{}, errors.Err(err, "GetInt32")
+	}
+
+	return Interval{Months: months, Days: days, Micros: micros}, nil
+}
+
+// SetInterval sets interval to the block.
+func (page *RecordPage) SetInterval(slotID SlotID, fldname FieldName, val Interval) error {
+	offset := page.offset(slotID) + page.layout.Offset(fldname)
+
+	if err := page.txn.SetInt64(page.blk, offset, val.Micros, true); err != nil {
+		return errors.Err(err, "SetInt64")
+	}
+
+	if err := page.txn.SetInt32(page.blk, offset+common.Int64Length, val.Days, true); err != nil {
+		return errors.Err(err, "SetInt32")
+	}
+
+	if err := page.txn.SetInt32(page.blk, offset+common.Int64Length+common.Int32Length, val.Months, true); err != nil {
+		return errors.Err(err, "SetInt32")
+	}
+
+	return page.setNullFlag(slotID, fldname, false)
+}
+
+// GetDecimal gets decimal from the block.
+func (page *RecordPage) GetDecimal(slotID SlotID, fldname FieldName) (Decimal, error) {
+	offset := page.offset(slotID) + page.layout.Offset(fldname)
+
+	hi, err := page.txn.GetInt64(page.blk, offset)
+	if err != nil {
+		return Decimal{}, errors.Err(err, "GetInt64")
+	}
+
+	lo, err := page.txn.GetInt64(page.blk, offset+common.Int64Length)
+	if err != nil {
+		return Decimal{}, errors.Err(err, "GetInt64")
+	}
+
+	scale, err := page.txn.GetInt32(page.blk, offset+2*common.Int64Length)
+	if err != nil {
+		return Decimal{}, errors.Err(err, "GetInt32")
+	}
+
+	return Decimal{Hi: hi, Lo: uint64(lo), Scale: scale}, nil
+}
+
+// SetDecimal sets decimal to the block.
+func (page *RecordPage) SetDecimal(slotID SlotID, fldname FieldName, val Decimal) error {
+	offset := page.offset(slotID) + page.layout.Offset(fldname)
+
+	if err := page.txn.SetInt64(page.blk, offset, val.Hi, true); err != nil {
+		return errors.Err(err, "SetInt64")
+	}
+
+	if err := page.txn.SetInt64(page.blk, offset+common.Int64Length, int64(val.Lo), true); err != nil {
+		return errors.Err(err, "SetInt64")
+	}
+
+	if err := page.txn.SetInt32(page.blk, offset+2*common.Int64Length, val.Scale, true); err != nil {
+		return errors.Err(err, "SetInt32")
+	}
+
+	return page.setNullFlag(slotID, fldname, false)
 }
 
 // GetString gets string from the block.
@@ -222,7 +615,137 @@ func (page *RecordPage) GetString(slotID SlotID, fldname FieldName) (string, err
 func (page *RecordPage) SetString(slotID SlotID, fldname FieldName, val string) error {
 	offset := page.offset(slotID) + page.layout.Offset(fldname)
 
-	return page.txn.SetString(page.blk, offset, val, true)
+	if err := page.txn.SetString(page.blk, offset, val, true); err != nil {
+		return errors.Err(err, "SetString")
+	}
+
+	return page.setNullFlag(slotID, fldname, false)
+}
+
+// IsNull checks whether the value of the field is NULL or not.
+// null bitmap の無い legacy な layout の値は NULL にならない.
+func (page *RecordPage) IsNull(slotID SlotID, fldname FieldName) (bool, error) {
+	if page.layout.legacy {
+		return false, nil
+	}
+
+	offset, mask := page.layout.NullFlagPosition(fldname)
+	word, err := page.txn.GetInt32(page.blk, page.offset(slotID)+offset)
+	if err != nil {
+		return false, errors.Err(err, "GetInt32")
+	}
+
+	return word&mask != 0, nil
+}
+
+// SetNull sets NULL to the field.
+func (page *RecordPage) SetNull(slotID SlotID, fldname FieldName) error {
+	if page.layout.legacy {
+		return errors.Wrap(ErrNullNotSupported, fldname.String())
+	}
+
+	return page.setNullFlag(slotID, fldname, true)
+}
+
+// setNullFlag sets or clears the null flag of the field.
+// 値が変わらない場合は log を残さないように書き込まない.
+func (page *RecordPage) setNullFlag(slotID SlotID, fldname FieldName, isNull bool) error {
+	if page.layout.legacy {
+		return nil
+	}
+
+	offset, mask := page.layout.NullFlagPosition(fldname)
+	pos := page.offset(slotID) + offset
+	word, err := page.txn.GetInt32(page.blk, pos)
+	if err != nil {
+		return errors.Err(err, "GetInt32")
+	}
+
+	newWord := word &^ mask
+	if isNull {
+		newWord = word | mask
+	}
+	if newWord == word {
+		return nil
+	}
+
+	return page.txn.SetInt32(page.blk, pos, newWord, true)
+}
+
+// setAllNull sets NULL to all fields of the slot.
+// 新しく使う slot は値が設定されるまで NULL とする.
+// legacy な layout では NULL の代わりに zero value にする.
+func (page *RecordPage) setAllNull(slotID SlotID) error {
+	if page.layout.legacy {
+		return page.zeroFields(slotID, true)
+	}
+
+	n := len(page.layout.schema.fields)
+	for i := 0; i < n; i += nullBitsPerWord {
+		word := int32(-1)
+		if rest := n - i; rest < nullBitsPerWord {
+			word = int32(uint32(1)<<rest - 1)
+		}
+		pos := page.offset(slotID) + int64(RecordOffset) + page.layout.versionBytes() + common.Int32Length*int64(i/nullBitsPerWord)
+		if err := page.txn.SetInt32(page.blk, pos, word, true); err != nil {
+			return errors.Err(err, "SetInt32")
+		}
+	}
+
+	return nil
+}
+
+// GetVersion returns the version of the record.
+// versioned でない layout の record は zero value の version を返す.
+func (page *RecordPage) GetVersion(slotID SlotID) (Version, error) {
+	if !page.layout.versioned {
+		return Version{}, nil
+	}
+
+	xmin, err := page.txn.GetInt32(page.blk, page.offset(slotID)+RecordOffset)
+	if err != nil {
+		return Version{}, errors.Err(err, "GetInt32")
+	}
+
+	xmax, err := page.txn.GetInt32(page.blk, page.offset(slotID)+RecordOffset+common.Int32Length)
+	if err != nil {
+		return Version{}, errors.Err(err, "GetInt32")
+	}
+
+	return Version{Xmin: TransactionNumber(xmin), Xmax: TransactionNumber(xmax)}, nil
+}
+
+// SetVersion sets the version of the record.
+// versioned でない layout では何もしない.
+func (page *RecordPage) SetVersion(slotID SlotID, ver Version) error {
+	if !page.layout.versioned {
+		return nil
+	}
+
+	if err := page.txn.SetInt32(page.blk, page.offset(slotID)+RecordOffset, int32(ver.Xmin), true); err != nil {
+		return errors.Err(err, "SetInt32")
+	}
+
+	return page.txn.SetInt32(page.blk, page.offset(slotID)+RecordOffset+common.Int32Length, int32(ver.Xmax), true)
+}
+
+// Clear fills the whole block with zero, which is the same as the formatted block.
+// 別の layout で書かれた block でも値を読めるように slot だけでなく block 全体を消す.
+// Format と違い log を書くので, rollback すると元の record に戻る.
+func (page *RecordPage) Clear() error {
+	return zeroFill(page.txn, page.blk)
+}
+
+// zeroFill fills the whole block with zero with writing log.
+func zeroFill(txn Transaction, blk Block) error {
+	blkSize := int64(txn.BlockSize())
+	for pos := int64(0); pos+common.Int32Length <= blkSize; pos += common.Int32Length {
+		if err := txn.SetInt32(blk, pos, 0, true); err != nil {
+			return errors.Err(err, "SetInt32")
+		}
+	}
+
+	return nil
 }
 
 // Delete deletes the slot.
@@ -237,25 +760,56 @@ func (page *RecordPage) Format() error {
 		if err := page.txn.SetInt32(page.blk, page.offset(slotID), Empty, false); err != nil {
 			return errors.Err(err, "SetInt32")
 		}
+		headerLen := page.layout.versionBytes() + page.layout.NullBitmapLength()
+		for pos := int64(RecordOffset); pos < RecordOffset+headerLen; pos += common.Int32Length {
+			if err := page.txn.SetInt32(page.blk, page.offset(slotID)+pos, 0, false); err != nil {
+				return errors.Err(err, "SetInt32")
+			}
+		}
 
-		sch := page.layout.schema
-		for _, fldname := range sch.fields {
-			typ := sch.Type(fldname)
-			fldpos := page.offset(slotID) + page.layout.Offset(fldname)
-			switch typ {
-			case Int32FieldType:
-				if err := page.txn.SetInt32(page.blk, fldpos, 0, false); err != nil {
-					return errors.Err(err, "SetInt32")
+		if err := page.zeroFields(slotID, false); err != nil {
+			return errors.Err(err, "zeroFields")
+		}
+		slotID++
+	}
+
+	return nil
+}
+
+// zeroFields sets the zero value to all fields of the slot.
+func (page *RecordPage) zeroFields(slotID SlotID, writeLog bool) error {
+	sch := page.layout.schema
+	for _, fldname := range sch.fields {
+		typ := sch.Type(fldname)
+		fldpos := page.offset(slotID) + page.layout.Offset(fldname)
+		switch typ {
+		case Int32FieldType, TextFieldType, BytesFieldType, Int16FieldType, BoolFieldType, Float32FieldType, DateFieldType:
+			if err := page.txn.SetInt32(page.blk, fldpos, 0, writeLog); err != nil {
+				return errors.Err(err, "SetInt32")
+			}
+		case Int64FieldType, Float64FieldType, TimestampFieldType, TimestampTzFieldType:
+			if err := page.txn.SetInt64(page.blk, fldpos, 0, writeLog); err != nil {
+				return errors.Err(err, "SetInt64")
+			}
+		case IntervalFieldType:
+			for pos := int64(0); pos < intervalLength; pos += common.Int64Length {
+				if err := page.txn.SetInt64(page.blk, fldpos+pos, 0, writeLog); err != nil {
+					return errors.Err(err, "SetInt64")
 				}
-			case StringFieldType:
-				if err := page.txn.SetString(page.blk, fldpos, "", false); err != nil {
-					return errors.Err(err, "SetString")
+			}
+		case NumericFieldType:
+			for pos := int64(0); pos < decimalLength; pos += common.Int32Length {
+				if err := page.txn.SetInt32(page.blk, fldpos+pos, 0, writeLog); err != nil {
+					return errors.Err(err, "SetInt32")
 				}
-			case UnknownFieldType:
-				log.Fatal(errors.New("unexpected record type"))
 			}
+		case StringFieldType:
+			if err := page.txn.SetString(page.blk, fldpos, "", writeLog); err != nil {
+				return errors.Err(err, "SetString")
+			}
+		case UnknownFieldType, TupleFieldType:
+			log.Fatal(errors.New("unexpected record type"))
 		}
-		slotID++
 	}
 
 	return nil
@@ -268,6 +822,7 @@ func (page *RecordPage) NextUsedSlot(slotID SlotID) (SlotID, error) {
 }
 
 // InsertAfter searches the slot id after slot with Empty flag, set Used flag and returns its id.
+// versioned な layout では, 挿入した record の xmin に transaction の番号を記録する.
 // 与えられた slotID よりもあとにある empty flag の slot を used にし、その ID を返却する。
 // MEMO: empty を探す作業と、flag を used にする作業は method を分けたほうがよいのではないか？
 func (page *RecordPage) InsertAfter(slotID SlotID) (SlotID, error) {
@@ -276,10 +831,16 @@ func (page *RecordPage) InsertAfter(slotID SlotID) (SlotID, error) {
 		return 0, errors.Err(err, "searchAfter")
 	}
 	if newSlot >= 0 {
+		if err := page.SetVersion(newSlot, Version{Xmin: page.txn.GetTxNum()}); err != nil {
+			return 0, errors.Err(err, "SetVersion")
+		}
 		err := page.setSlotCondition(newSlot, Used)
 		if err != nil {
 			return 0, errors.Err(err, "setSlotCondition")
 		}
+		if err := page.setAllNull(newSlot); err != nil {
+			return 0, errors.Err(err, "setAllNull")
+		}
 	}
 
 	return newSlot, nil
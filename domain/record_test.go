@@ -204,13 +204,34 @@ func TestLayout(t *testing.T) {
 
 		layout := domain.NewLayout(schema)
 
+		// usage flag と null bitmap の後に値が並ぶ.
 		mp := map[domain.FieldName]int64{
-			"hoge": 4,
-			"piyo": 8,
+			"hoge": 8,
+			"piyo": 12,
 		}
 
-		expected := domain.NewLayoutByElement(schema, mp, 20)
+		expected := domain.NewLayoutByElement(schema, mp, 24)
 
 		require.Equal(t, expected, layout)
 	})
+
+	t.Run("constructs versioned Layout", func(t *testing.T) {
+		schema := domain.NewSchema()
+		schema.AddField("hoge", domain.Int32FieldType, 0)
+		schema.AddField("piyo", domain.StringFieldType, 8)
+
+		layout := domain.NewVersionedLayout(schema)
+
+		// usage flag, xmin, xmax と null bitmap の後に値が並ぶ.
+		mp := map[domain.FieldName]int64{
+			"hoge": 16,
+			"piyo": 20,
+		}
+
+		expected := domain.NewLayoutWithFields(schema, mp, 32, true)
+
+		require.Equal(t, expected, layout)
+		require.True(t, layout.Versioned())
+		require.False(t, domain.NewLayout(schema).Versioned())
+	})
 }
@@ -34,19 +34,22 @@ type UpdateScanner interface {
 	Delete() error
 	RecordID() RecordID
 	MoveToRecordID(rid RecordID) error
+	SaveVersion() (RecordID, bool, error)
 }
 
 // TableScan is a model of database table.
+// record の version のうち transaction の snapshot から見えるものだけを読む.
 type TableScan struct {
 	txn           Transaction
 	layout        *Layout
-	recordPage    *RecordPage
+	recordPage    RecordPager
 	tblName       TableName
 	currentSlotID SlotID
 	err           error
 }
 
 // NewTableScan constructs a Table.
+// version を持たない layout の table は, catalog や hash index の bucket のように常に最新の record を扱う.
 func NewTableScan(txn Transaction, tblName TableName, layout *Layout) (*TableScan, error) {
 	tbl := &TableScan{
 		txn:           txn,
@@ -84,44 +87,231 @@ func (tbl *TableScan) BeforeFirst() error {
 }
 
 // HasNext checks the existence of next record.
+// snapshot から見えない version の record は読み飛ばす.
 // HasNext implements Scanner.
 func (tbl *TableScan) HasNext() bool {
-	currentSlotID, err := tbl.recordPage.NextUsedSlot(tbl.currentSlotID)
+	for {
+		found, err := tbl.nextUsedSlot()
+		if err != nil {
+			tbl.err = err
+
+			return false
+		}
+		if !found {
+			return false
+		}
+
+		visible, err := tbl.IsVisible()
+		if err != nil {
+			tbl.err = err
+
+			return false
+		}
+		if visible {
+			return true
+		}
+	}
+}
+
+// HasNextVersion checks the existence of next record including versions which the snapshot doesn't see.
+// 古い version も含めて table の全ての record を処理するのに使う.
+func (tbl *TableScan) HasNextVersion() bool {
+	found, err := tbl.nextUsedSlot()
 	if err != nil {
 		tbl.err = err
 
 		return false
 	}
+
+	return found
+}
+
+// nextUsedSlot moves to the next used slot.
+func (tbl *TableScan) nextUsedSlot() (bool, error) {
+	currentSlotID, err := tbl.recordPage.NextUsedSlot(tbl.currentSlotID)
+	if err != nil {
+		return false, errors.Err(err, "NextUsedSlot")
+	}
 	tbl.currentSlotID = currentSlotID
 
 	for tbl.currentSlotID < 0 {
 		last, err := tbl.isAtLastBlock()
 		if err != nil {
-			tbl.err = err
-
-			return false
+			return false, errors.Err(err, "isAtLastBlock")
 		}
 		if last {
-			return false
+			return false, nil
 		}
 
 		blk := tbl.recordPage.Block()
 		if err := tbl.moveToBlock(blk.Number() + 1); err != nil {
-			tbl.err = err
-
-			return false
+			return false, errors.Err(err, "moveToBlock")
 		}
 
 		slotID, err := tbl.recordPage.NextUsedSlot(tbl.currentSlotID)
 		if err != nil {
-			tbl.err = err
-
-			return false
+			return false, errors.Err(err, "NextUsedSlot")
 		}
 		tbl.currentSlotID = slotID
 	}
 
-	return true
+	return true, nil
+}
+
+// IsVisible checks whether the current record is visible to the transaction.
+// two-phase locking では snapshot が無いので, 削除されていない record を全て見る.
+func (tbl *TableScan) IsVisible() (bool, error) {
+	if !tbl.layout.Versioned() {
+		return true, nil
+	}
+
+	ver, err := tbl.recordPage.GetVersion(tbl.currentSlotID)
+	if err != nil {
+		return false, errors.Err(err, "GetVersion")
+	}
+
+	snapshot := tbl.txn.Snapshot()
+	if snapshot == nil {
+		return ver.Xmax == 0, nil
+	}
+
+	return snapshot.IsVisibleVersion(ver), nil
+}
+
+// lockVersion takes the exclusive lock of the current block and returns the version of the current record.
+// snapshot の後に他の transaction が更新, 削除した record は書き換えられない (first-updater-wins).
+func (tbl *TableScan) lockVersion() (Version, error) {
+	if err := tbl.txn.XLock(tbl.recordPage.Block()); err != nil {
+		return Version{}, errors.Err(err, "XLock")
+	}
+
+	ver, err := tbl.recordPage.GetVersion(tbl.currentSlotID)
+	if err != nil {
+		return Version{}, errors.Err(err, "GetVersion")
+	}
+
+	if ver.Xmax != 0 || !tbl.txn.Snapshot().IsVisible(ver.Xmin) {
+		return Version{}, errors.Wrap(ErrSerializationFailure, tbl.tblName.String())
+	}
+
+	return ver, nil
+}
+
+// SaveVersion makes the current record writable by the transaction.
+// 他の transaction から見えている record は, 古い version を別の slot に複製してから書き換える.
+// 複製した場合はその slot の record id を返すので, 呼び出し側は古い version の index の entry を登録する.
+// SaveVersion implements UpdateScanner.
+func (tbl *TableScan) SaveVersion() (RecordID, bool, error) {
+	if !KeepsVersions(tbl.txn, tbl.layout) {
+		return RecordID{}, false, nil
+	}
+
+	ver, err := tbl.lockVersion()
+	if err != nil {
+		return RecordID{}, false, errors.Err(err, "lockVersion")
+	}
+	txNum := tbl.txn.GetTxNum()
+	if ver.Xmin == txNum {
+		return RecordID{}, false, nil
+	}
+
+	rid := tbl.RecordID()
+	vals := make([]Constant, 0, len(tbl.layout.schema.fields))
+	for _, fld := range tbl.layout.schema.fields {
+		val, err := tbl.GetVal(fld)
+		if err != nil {
+			return RecordID{}, false, errors.Err(err, "GetVal")
+		}
+		vals = append(vals, val)
+	}
+
+	if err := tbl.AdvanceNextInsertSlotID(); err != nil {
+		return RecordID{}, false, errors.Err(err, "AdvanceNextInsertSlotID")
+	}
+	for i, fld := range tbl.layout.schema.fields {
+		if vals[i].IsNull() {
+			continue
+		}
+		if err := tbl.SetVal(fld, vals[i]); err != nil {
+			return RecordID{}, false, errors.Err(err, "SetVal")
+		}
+	}
+	if err := tbl.recordPage.SetVersion(tbl.currentSlotID, Version{Xmin: ver.Xmin, Xmax: txNum}); err != nil {
+		return RecordID{}, false, errors.Err(err, "SetVersion")
+	}
+	saved := tbl.RecordID()
+
+	if err := tbl.MoveToRecordID(rid); err != nil {
+		return RecordID{}, false, errors.Err(err, "MoveToRecordID")
+	}
+
+	if err := tbl.recordPage.SetVersion(tbl.currentSlotID, Version{Xmin: txNum}); err != nil {
+		return RecordID{}, false, errors.Err(err, "SetVersion")
+	}
+
+	return saved, true, nil
+}
+
+// Prune removes the old versions which no transaction reads any more.
+// 削除する前に record ごとに fn を呼ぶので, 呼び出し側はその record の index の entry を削除する.
+func (tbl *TableScan) Prune(fn func(Scanner, RecordID) error) error {
+	if !tbl.layout.Versioned() {
+		return nil
+	}
+
+	if err := tbl.BeforeFirst(); err != nil {
+		return errors.Err(err, "BeforeFirst")
+	}
+	for tbl.HasNextVersion() {
+		ver, err := tbl.recordPage.GetVersion(tbl.currentSlotID)
+		if err != nil {
+			return errors.Err(err, "GetVersion")
+		}
+		if ver.Xmax == 0 || !tbl.txn.IsVisibleToAll(ver.Xmax) {
+			continue
+		}
+
+		if fn != nil {
+			if err := fn(tbl, tbl.RecordID()); err != nil {
+				return err
+			}
+		}
+		if err := tbl.Purge(); err != nil {
+			return errors.Err(err, "Purge")
+		}
+	}
+	if tbl.err != nil {
+		return errors.Err(tbl.err, "HasNextVersion")
+	}
+
+	return tbl.BeforeFirst()
+}
+
+// KeepsVersions checks whether the transaction leaves old versions when it updates or deletes records of the layout.
+// multi-version mode の versioned な layout の table だけが古い version を残す.
+func KeepsVersions(txn Transaction, layout *Layout) bool {
+	return layout.Versioned() && txn.Snapshot() != nil
+}
+
+// IsLatestVersion checks whether the record of rid is the latest version, which is neither updated nor deleted.
+// index には古い version の record の entry も残るので, 制約の確認ではこれで最新の record だけを見る.
+func IsLatestVersion(txn Transaction, tblName TableName, layout *Layout, rid RecordID) (bool, error) {
+	if !layout.Versioned() {
+		return true, nil
+	}
+
+	page, err := NewRecordPager(txn, NewBlock(FileName(tblName), rid.BlockNumber()), layout)
+	if err != nil {
+		return false, errors.Err(err, "NewRecordPager")
+	}
+	defer txn.Unpin(page.Block())
+
+	ver, err := page.GetVersion(rid.SlotID())
+	if err != nil {
+		return false, errors.Err(err, "GetVersion")
+	}
+
+	return ver.Xmax == 0, nil
 }
 
 // GetInt32 gets int32 from the table.
@@ -133,12 +323,24 @@ func (tbl *TableScan) GetInt32(fldName FieldName) (int32, error) {
 // GetString gets string from the table.
 // GetString  implements Scanner.
 func (tbl *TableScan) GetString(fldName FieldName) (string, error) {
+	if tbl.layout.schema.Type(fldName).IsOverflow() {
+		return tbl.getOverflow(fldName)
+	}
+
 	return tbl.recordPage.GetString(tbl.currentSlotID, fldName)
 }
 
 // GetVal gets value from the table.
 // GetVal implements Scanner.
 func (tbl *TableScan) GetVal(fldName FieldName) (Constant, error) {
+	isNull, err := tbl.recordPage.IsNull(tbl.currentSlotID, fldName)
+	if err != nil {
+		return Constant{}, errors.Err(err, "IsNull")
+	}
+	if isNull {
+		return NewNullConstant(), nil
+	}
+
 	typ := tbl.layout.schema.Type(fldName)
 	switch typ {
 	case Int32FieldType:
@@ -148,6 +350,34 @@ func (tbl *TableScan) GetVal(fldName FieldName) (Constant, error) {
 		}
 
 		return NewConstant(Int32FieldType, val), nil
+	case Int16FieldType, BoolFieldType, Float32FieldType, DateFieldType:
+		bits, err := tbl.recordPage.GetInt32(tbl.currentSlotID, fldName)
+		if err != nil {
+			return Constant{}, errors.Err(err, "GetInt32")
+		}
+
+		return NewConstantFromBits(typ, int64(bits)), nil
+	case Int64FieldType, Float64FieldType, TimestampFieldType, TimestampTzFieldType:
+		bits, err := tbl.recordPage.GetInt64(tbl.currentSlotID, fldName)
+		if err != nil {
+			return Constant{}, errors.Err(err, "GetInt64")
+		}
+
+		return NewConstantFromBits(typ, bits), nil
+	case IntervalFieldType:
+		val, err := tbl.recordPage.GetInterval(tbl.currentSlotID, fldName)
+		if err != nil {
+			return Constant{}, errors.Err(err, "GetInterval")
+		}
+
+		return NewIntervalConstant(val), nil
+	case NumericFieldType:
+		val, err := tbl.recordPage.GetDecimal(tbl.currentSlotID, fldName)
+		if err != nil {
+			return Constant{}, errors.Err(err, "GetDecimal")
+		}
+
+		return NewDecimalConstant(val), nil
 	case StringFieldType:
 		val, err := tbl.GetString(fldName)
 		if err != nil {
@@ -155,7 +385,19 @@ func (tbl *TableScan) GetVal(fldName FieldName) (Constant, error) {
 		}
 
 		return NewConstant(StringFieldType, val), nil
-	case UnknownFieldType:
+	case TextFieldType, BytesFieldType:
+		val, err := tbl.getOverflow(fldName)
+		if err != nil {
+			return Constant{}, errors.Err(err, "getOverflow")
+		}
+
+		// TEXT の値は VARCHAR と比較できるように文字列として返す.
+		if typ == TextFieldType {
+			return NewConstant(StringFieldType, val), nil
+		}
+
+		return NewConstant(BytesFieldType, val), nil
+	case UnknownFieldType, TupleFieldType:
 		return Constant{}, errors.New("unexpected field type")
 	}
 
@@ -185,12 +427,24 @@ func (tbl *TableScan) Err() error {
 // SetInt32 sets int32 to the table.
 // SetInt32 implements UpdateScanner.
 func (tbl *TableScan) SetInt32(fldName FieldName, val int32) error {
+	if _, _, err := tbl.SaveVersion(); err != nil {
+		return errors.Err(err, "SaveVersion")
+	}
+
 	return tbl.recordPage.SetInt32(tbl.currentSlotID, fldName, val)
 }
 
 // SetString sets string to the table.
 // SetString implements UpdateScanner.
 func (tbl *TableScan) SetString(fldName FieldName, val string) error {
+	if _, _, err := tbl.SaveVersion(); err != nil {
+		return errors.Err(err, "SaveVersion")
+	}
+
+	if tbl.layout.schema.Type(fldName).IsOverflow() {
+		return tbl.setOverflow(fldName, val)
+	}
+
 	l := tbl.layout.Length(fldName)
 	if len(val) > l {
 		return fmt.Errorf("exceed varchar size %v: value '%v'", l, val)
@@ -202,8 +456,48 @@ func (tbl *TableScan) SetString(fldName FieldName, val string) error {
 // SetVal sets value to the table.
 // SetVal implements UpdateScanner.
 func (tbl *TableScan) SetVal(fldName FieldName, val Constant) error {
+	if _, _, err := tbl.SaveVersion(); err != nil {
+		return errors.Err(err, "SaveVersion")
+	}
+
 	typ := tbl.layout.schema.Type(fldName)
+	if val.IsNull() {
+		if typ.IsOverflow() {
+			if err := tbl.freeOverflow(fldName); err != nil {
+				return errors.Err(err, "freeOverflow")
+			}
+		}
+
+		return tbl.recordPage.SetNull(tbl.currentSlotID, fldName)
+	}
+
+	val, err := tbl.layout.schema.StoredValue(fldName, val)
+	if err != nil {
+		return errors.Err(err, "StoredValue")
+	}
+
 	switch typ {
+	case Int16FieldType, Int64FieldType, BoolFieldType, Float32FieldType, Float64FieldType,
+		DateFieldType, TimestampFieldType, TimestampTzFieldType:
+		if err := tbl.setBits(fldName, val); err != nil {
+			return errors.Err(err, "setBits")
+		}
+	case IntervalFieldType:
+		v, err := val.AsInterval()
+		if err != nil {
+			return errors.Wrap(ErrTypeMismatch, fldName.String())
+		}
+		if err := tbl.recordPage.SetInterval(tbl.currentSlotID, fldName, v); err != nil {
+			return errors.Err(err, "SetInterval")
+		}
+	case NumericFieldType:
+		v, err := val.AsDecimal()
+		if err != nil {
+			return errors.Wrap(ErrTypeMismatch, fldName.String())
+		}
+		if err := tbl.recordPage.SetDecimal(tbl.currentSlotID, fldName, v); err != nil {
+			return errors.Err(err, "SetDecimal")
+		}
 	case Int32FieldType:
 		v, err := val.AsInt32()
 		if err != nil {
@@ -212,7 +506,7 @@ func (tbl *TableScan) SetVal(fldName FieldName, val Constant) error {
 		if err := tbl.SetInt32(fldName, v); err != nil {
 			return errors.Err(err, "SetInt32")
 		}
-	case StringFieldType:
+	case StringFieldType, TextFieldType:
 		v, err := val.AsString()
 		if err != nil {
 			return errors.Err(err, "AsString")
@@ -220,13 +514,92 @@ func (tbl *TableScan) SetVal(fldName FieldName, val Constant) error {
 		if err := tbl.SetString(fldName, v); err != nil {
 			return errors.Err(err, "SetString")
 		}
-	case UnknownFieldType:
+	case BytesFieldType:
+		v, err := val.AsString()
+		if err != nil {
+			return errors.Err(err, "AsString")
+		}
+		if val.Type() == StringFieldType {
+			v, err = ParseBytes(v)
+			if err != nil {
+				return errors.Err(err, "ParseBytes")
+			}
+		}
+		if err := tbl.setOverflow(fldName, v); err != nil {
+			return errors.Err(err, "setOverflow")
+		}
+	case UnknownFieldType, TupleFieldType:
 		return ErrUnsupportedFieldType
 	}
 
 	return nil
 }
 
+// setBits sets the integer representation of val to the field.
+func (tbl *TableScan) setBits(fldName FieldName, val Constant) error {
+	typ := tbl.layout.schema.Type(fldName)
+	if val.Type() != typ {
+		return errors.Wrap(ErrTypeMismatch, fldName.String())
+	}
+
+	bits, err := val.Bits()
+	if err != nil {
+		return errors.Err(err, "Bits")
+	}
+
+	if typ.IsInt64Sized() {
+		return tbl.recordPage.SetInt64(tbl.currentSlotID, fldName, bits)
+	}
+
+	return tbl.recordPage.SetInt32(tbl.currentSlotID, fldName, int32(bits))
+}
+
+// getOverflow reads the value of the field stored in overflow pages.
+func (tbl *TableScan) getOverflow(fldName FieldName) (string, error) {
+	head, err := tbl.recordPage.GetInt32(tbl.currentSlotID, fldName)
+	if err != nil {
+		return "", errors.Err(err, "GetInt32")
+	}
+
+	return tbl.overflowFile().Read(BlockNumber(head))
+}
+
+// setOverflow writes val into overflow pages and frees the pages of the old value.
+func (tbl *TableScan) setOverflow(fldName FieldName, val string) error {
+	if err := tbl.freeOverflow(fldName); err != nil {
+		return errors.Err(err, "freeOverflow")
+	}
+
+	head, err := tbl.overflowFile().Write(val)
+	if err != nil {
+		return errors.Err(err, "Write")
+	}
+
+	return tbl.recordPage.SetInt32(tbl.currentSlotID, fldName, int32(head))
+}
+
+// freeOverflow frees the overflow pages of the value of the field.
+func (tbl *TableScan) freeOverflow(fldName FieldName) error {
+	isNull, err := tbl.recordPage.IsNull(tbl.currentSlotID, fldName)
+	if err != nil {
+		return errors.Err(err, "IsNull")
+	}
+	if isNull {
+		return nil
+	}
+
+	head, err := tbl.recordPage.GetInt32(tbl.currentSlotID, fldName)
+	if err != nil {
+		return errors.Err(err, "GetInt32")
+	}
+
+	return tbl.overflowFile().Free(BlockNumber(head))
+}
+
+func (tbl *TableScan) overflowFile() *OverflowFile {
+	return NewOverflowFile(tbl.txn, tbl.tblName.ToOverflowFileName())
+}
+
 // AdvanceNextInsertSlotID  advances current slot id to next to unused slot id.
 // If there is no unused record, append file block.
 // AdvanceNextInsertSlotID implements UpdateScanner.
@@ -266,11 +639,56 @@ func (tbl *TableScan) AdvanceNextInsertSlotID() error {
 }
 
 // Delete deletes the current slot logically.
+// 古い version を残す場合は record を消さずに xmax を記録するので, 呼び出し側は index の entry も残す.
 // Delete implements UpdateScanner.
 func (tbl *TableScan) Delete() error {
+	if KeepsVersions(tbl.txn, tbl.layout) {
+		ver, err := tbl.lockVersion()
+		if err != nil {
+			return errors.Err(err, "lockVersion")
+		}
+
+		return tbl.recordPage.SetVersion(tbl.currentSlotID, Version{Xmin: ver.Xmin, Xmax: tbl.txn.GetTxNum()})
+	}
+
+	return tbl.Purge()
+}
+
+// Purge removes the current slot even if other transactions read it as an old version.
+// TEXT と BYTEA の値を保存していた overflow page も解放する.
+func (tbl *TableScan) Purge() error {
+	for _, fld := range tbl.layout.schema.fields {
+		if !tbl.layout.schema.Type(fld).IsOverflow() {
+			continue
+		}
+		if err := tbl.freeOverflow(fld); err != nil {
+			return errors.Err(err, "freeOverflow")
+		}
+	}
+
 	return tbl.recordPage.Delete(tbl.currentSlotID)
 }
 
+// Clear deletes all records of the table and moves to the position before the first record.
+// 別の layout で書かれた file も, 全ての block をこの layout の空の slot にする.
+func (tbl *TableScan) Clear() error {
+	size, err := tbl.txn.BlockLength(FileName(tbl.tblName))
+	if err != nil {
+		return errors.Err(err, "BlockLength")
+	}
+
+	for blkNum := BlockNumber(0); blkNum < BlockNumber(size); blkNum++ {
+		if err := tbl.moveToBlock(blkNum); err != nil {
+			return errors.Err(err, "moveToBlock")
+		}
+		if err := tbl.recordPage.Clear(); err != nil {
+			return errors.Err(err, "Clear")
+		}
+	}
+
+	return tbl.BeforeFirst()
+}
+
 // RecordID is a identifier of record.
 // RecordID implements UpdateScanner.
 func (tbl *TableScan) RecordID() RecordID {
@@ -284,9 +702,9 @@ func (tbl *TableScan) RecordID() RecordID {
 func (tbl *TableScan) MoveToRecordID(rid RecordID) error {
 	tbl.Close()
 	blk := NewBlock(FileName(tbl.tblName), rid.BlockNumber())
-	recordPage, err := NewRecordPage(tbl.txn, blk, tbl.layout)
+	recordPage, err := NewRecordPager(tbl.txn, blk, tbl.layout)
 	if err != nil {
-		return errors.Err(err, "NewRecordPage")
+		return errors.Err(err, "NewRecordPager")
 	}
 	tbl.recordPage = recordPage
 	tbl.currentSlotID = rid.SlotID()
@@ -297,9 +715,9 @@ func (tbl *TableScan) MoveToRecordID(rid RecordID) error {
 func (tbl *TableScan) moveToBlock(blkNum BlockNumber) error {
 	tbl.Close()
 	blk := NewBlock(FileName(tbl.tblName), blkNum)
-	recordPage, err := NewRecordPage(tbl.txn, blk, tbl.layout)
+	recordPage, err := NewRecordPager(tbl.txn, blk, tbl.layout)
 	if err != nil {
-		return errors.Err(err, "NewRecordPage")
+		return errors.Err(err, "NewRecordPager")
 	}
 
 	tbl.recordPage = recordPage
@@ -331,9 +749,9 @@ func (tbl *TableScan) moveToNewBlock() error {
 		return errors.Err(err, "txn.ExtendFile")
 	}
 
-	recordPage, err := NewRecordPage(tbl.txn, blk, tbl.layout)
+	recordPage, err := NewRecordPager(tbl.txn, blk, tbl.layout)
 	if err != nil {
-		return errors.Err(err, "NewRecordPage")
+		return errors.Err(err, "NewRecordPager")
 	}
 
 	tbl.recordPage = recordPage
@@ -511,7 +929,13 @@ func (s *SelectScan) BeforeFirst() error {
 // HasNext checks the existence of next record.
 func (s *SelectScan) HasNext() bool {
 	for s.scan.HasNext() {
-		if s.pred.IsSatisfied(s.scan) {
+		ok, err := s.pred.IsSatisfied(s.scan)
+		if err != nil {
+			s.err = err
+
+			return false
+		}
+		if ok {
 			return true
 		}
 	}
@@ -617,6 +1041,17 @@ func (s *SelectScan) RecordID() RecordID {
 	return us.RecordID()
 }
 
+// SaveVersion keeps the old version of the current record before it is changed.
+// SaveVersion implements UpdateScanner.
+func (s *SelectScan) SaveVersion() (RecordID, bool, error) {
+	us, ok := s.scan.(UpdateScanner)
+	if !ok {
+		return RecordID{}, false, ErrNotUpdatable
+	}
+
+	return us.SaveVersion()
+}
+
 // MoveToRecordID moves to the record id.
 // MoveToRecordID implements UpdateScanner.
 func (s *SelectScan) MoveToRecordID(rid RecordID) error {
@@ -664,9 +1099,9 @@ func (ss *IndexSelectScan) BeforeFirst() error {
 }
 
 // HasNext checks the existence of next record.
+// transaction から見えない record は読み飛ばす.
 func (ss *IndexSelectScan) HasNext() bool {
-	found := ss.idx.HasNext()
-	if found {
+	for ss.idx.HasNext() {
 		rid, err := ss.idx.GetDataRecordID()
 		if err != nil {
 			ss.err = errors.Err(err, "GetDataRecordID")
@@ -678,12 +1113,21 @@ func (ss *IndexSelectScan) HasNext() bool {
 
 			return false
 		}
+		visible, err := ss.ts.IsVisible()
+		if err != nil {
+			ss.err = errors.Err(err, "IsVisible")
+
+			return false
+		}
+		if visible {
+			return true
+		}
 	}
 	if ss.idx.Err() != nil {
 		ss.err = ss.idx.Err()
 	}
 
-	return found
+	return false
 }
 
 // GetInt32 gets int32 from the table.
@@ -726,14 +1170,26 @@ func (ss *IndexSelectScan) Err() error {
 type ProjectScan struct {
 	scan   Scanner
 	fields []FieldName
+	exprs  map[FieldName]Expression
 	err    error
 }
 
 // NewProjectScan constructs a ProjectScan.
 func NewProjectScan(s Scanner, fields []FieldName) *ProjectScan {
+	return NewProjectScanWithExpressions(s, fields, nil)
+}
+
+// NewProjectScanWithExpressions constructs a ProjectScan which has computed fields.
+// exprs の field は scan の値から計算される.
+func NewProjectScanWithExpressions(s Scanner, fields []FieldName, exprs map[FieldName]Expression) *ProjectScan {
+	if exprs == nil {
+		exprs = make(map[FieldName]Expression)
+	}
+
 	return &ProjectScan{
 		scan:   s,
 		fields: fields,
+		exprs:  exprs,
 	}
 }
 
@@ -753,6 +1209,15 @@ func (s *ProjectScan) HasNext() bool {
 
 // GetInt32 gets int32 from the table.
 func (s *ProjectScan) GetInt32(fld FieldName) (int32, error) {
+	if expr, ok := s.exprs[fld]; ok {
+		val, err := expr.Evaluate(s.scan)
+		if err != nil {
+			return 0, errors.Err(err, "Evaluate")
+		}
+
+		return val.AsInt32()
+	}
+
 	if s.HasField(fld) {
 		return s.scan.GetInt32(fld)
 	}
@@ -763,6 +1228,15 @@ func (s *ProjectScan) GetInt32(fld FieldName) (int32, error) {
 // GetString gets string from the table.
 // GetString  implements Scanner.
 func (s *ProjectScan) GetString(fld FieldName) (string, error) {
+	if expr, ok := s.exprs[fld]; ok {
+		val, err := expr.Evaluate(s.scan)
+		if err != nil {
+			return "", errors.Err(err, "Evaluate")
+		}
+
+		return val.AsString()
+	}
+
 	if s.HasField(fld) {
 		return s.scan.GetString(fld)
 	}
@@ -773,6 +1247,10 @@ func (s *ProjectScan) GetString(fld FieldName) (string, error) {
 // GetVal gets value from the table.
 // GetVal implements Scanner.
 func (s *ProjectScan) GetVal(fld FieldName) (Constant, error) {
+	if expr, ok := s.exprs[fld]; ok {
+		return expr.Evaluate(s.scan)
+	}
+
 	if s.HasField(fld) {
 		return s.scan.GetVal(fld)
 	}
@@ -783,6 +1261,10 @@ func (s *ProjectScan) GetVal(fld FieldName) (Constant, error) {
 // HasField checks the existence of the field.
 // HasField implements Scanner.
 func (s *ProjectScan) HasField(fld FieldName) bool {
+	if _, ok := s.exprs[fld]; ok {
+		return true
+	}
+
 	return s.scan.HasField(fld)
 }
 
@@ -125,6 +125,86 @@ func TestTableScan(t *testing.T) {
 	})
 }
 
+func TestTableScan_null(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 2
+	)
+
+	dbPath := fake.RandString()
+	factory := fake.NewNonDirectBufferManagerFactory(dbPath, blockSize, numBuf)
+	fileMgr, logMgr, bufMgr := factory.Create()
+	defer factory.Finish()
+
+	cfg := tx.LockTableConfig{LockTimeoutMillisecond: 1000}
+	lt := tx.NewLockTable(cfg)
+
+	gen := tx.NewNumberGenerator()
+
+	txn, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+	require.NoError(t, err)
+
+	// null bitmap が複数の word にまたがるように field を作る.
+	sch := domain.NewSchema()
+	for i := 0; i < 40; i++ {
+		sch.AddInt32Field(domain.FieldName(fmt.Sprintf("f%v", i)))
+	}
+	layout := domain.NewLayout(sch)
+	require.Equal(t, int64(domain.RecordOffset+8+40*4), layout.SlotSize())
+
+	table, err := domain.NewTableScan(txn, "T.tbl", layout)
+	require.NoError(t, err)
+
+	// 値を設定していない field は NULL になる.
+	err = table.AdvanceNextInsertSlotID()
+	require.NoError(t, err)
+	err = table.SetVal("f1", domain.NewConstant(domain.Int32FieldType, int32(1)))
+	require.NoError(t, err)
+	err = table.SetVal("f35", domain.NewConstant(domain.Int32FieldType, int32(35)))
+	require.NoError(t, err)
+	err = table.SetVal("f35", domain.NewNullConstant())
+	require.NoError(t, err)
+	err = table.SetVal("f39", domain.NewConstant(domain.Int32FieldType, int32(39)))
+	require.NoError(t, err)
+	rid := table.RecordID()
+
+	// 削除された slot を再利用しても前の値は残らない.
+	err = table.AdvanceNextInsertSlotID()
+	require.NoError(t, err)
+	for _, fld := range sch.Fields() {
+		err = table.SetVal(fld, domain.NewConstant(domain.Int32FieldType, int32(0)))
+		require.NoError(t, err)
+	}
+	err = table.Delete()
+	require.NoError(t, err)
+	err = table.BeforeFirst()
+	require.NoError(t, err)
+	err = table.AdvanceNextInsertSlotID()
+	require.NoError(t, err)
+
+	for _, r := range []domain.RecordID{rid, table.RecordID()} {
+		err = table.MoveToRecordID(r)
+		require.NoError(t, err)
+
+		nonNull := make([]string, 0)
+		for _, fld := range sch.Fields() {
+			val, err := table.GetVal(fld)
+			require.NoError(t, err)
+			if !val.IsNull() {
+				nonNull = append(nonNull, fld.String()+"="+val.String())
+			}
+		}
+
+		if r == rid {
+			require.Equal(t, []string{"f1=1", "f39=39"}, nonNull)
+		} else {
+			require.Equal(t, []string{}, nonNull)
+		}
+	}
+	table.Close()
+	require.NoError(t, txn.Commit())
+}
+
 func TestTableScan2(t *testing.T) {
 	const (
 		blockSize = 100
@@ -0,0 +1,700 @@
+package domain
+
+import (
+	"encoding/binary"
+
+	"github.com/goropikari/simpledbgo/common"
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+var (
+	// ErrRecordTooLarge is an error that means a record can't be stored in a block even if the block is empty.
+	ErrRecordTooLarge = errors.New("record is too large for a block")
+
+	// ErrPageFull is an error that means the page has no space to grow the record.
+	ErrPageFull = errors.New("no space left in the page for the record")
+)
+
+const (
+	// slotCountOffset is the offset of the number of slots.
+	slotCountOffset = 0
+
+	// freeEndOffset is the offset of the end of free space.
+	freeEndOffset = common.Int32Length
+
+	// slotDirOffset is the offset of the slot directory.
+	slotDirOffset = 2 * common.Int32Length
+
+	// slotEntryLength is the byte length of an entry of the slot directory.
+	slotEntryLength = 2 * common.Int32Length
+)
+
+// SlottedPage is a page which stores variable-length records.
+// block の先頭に header と slot directory を置き, record は block の末尾から詰めて置く.
+//
+// Page structure
+// ----------------------------------------------------------------------------------------
+// | number of slots | free space end | slot 0 | slot 1 | ... | free space | ... | record 0 |
+// ----------------------------------------------------------------------------------------
+//
+// slot は record の offset と byte 長の組で, offset が 0 の slot は空を表す.
+// record は version, null bitmap と field の値を並べたもので, varchar は長さと実際の文字列だけを保存する.
+// NULL の varchar は空文字列として保存する.
+//
+// record structure
+// ---------------------------------------------------------------------------------------
+// | xmin | xmax | null bitmap | int32 | varchar length | varchar bytes (4 byte 境界) | ...
+// ---------------------------------------------------------------------------------------
+//
+// versioned でない layout の record には xmin と xmax が無い.
+//
+// record の大きさが変わると, その下にある record を動かして空き領域を常に 1 つにまとめる.
+// slot の番号は変わらないので record id は変わらない.
+// log の undo で元に戻せるように, block は全て int32 単位で log を書きながら読み書きする.
+type SlottedPage struct {
+	txn    Transaction
+	blk    Block
+	layout *Layout
+}
+
+// NewSlottedPage constructs a SlottedPage.
+func NewSlottedPage(txn Transaction, blk Block, layout *Layout) (*SlottedPage, error) {
+	if err := txn.Pin(blk); err != nil {
+		return nil, errors.Err(err, "Pin")
+	}
+
+	return &SlottedPage{
+		txn:    txn,
+		blk:    blk,
+		layout: layout,
+	}, nil
+}
+
+// GetInt32 gets int32 from the block.
+func (page *SlottedPage) GetInt32(slotID SlotID, fldname FieldName) (int32, error) {
+	offset, err := page.fieldOffset(slotID, fldname)
+	if err != nil {
+		return 0, errors.Err(err, "fieldOffset")
+	}
+
+	return page.txn.GetInt32(page.blk, offset)
+}
+
+// SetInt32 sets int32 to the block.
+func (page *SlottedPage) SetInt32(slotID SlotID, fldname FieldName, val int32) error {
+	return page.setField(slotID, fldname, []int32{val}, false)
+}
+
+// GetInt64 gets int64 from the block.
+func (page *SlottedPage) GetInt64(slotID SlotID, fldname FieldName) (int64, error) {
+	offset, err := page.fieldOffset(slotID, fldname)
+	if err != nil {
+		return 0, errors.Err(err, "fieldOffset")
+	}
+
+	return page.txn.GetInt64(page.blk, offset)
+}
+
+// SetInt64 sets int64 to the block.
+// int64 は上位, 下位の順に 2 word に分けて保存する.
+func (page *SlottedPage) SetInt64(slotID SlotID, fldname FieldName, val int64) error {
+	return page.setField(slotID, fldname, []int32{int32(val >> 32), int32(val)}, false)
+}
+
+// GetInterval gets interval from the block.
+func (page *SlottedPage) GetInterval(slotID SlotID, fldname FieldName) (Interval, error) {
+	offset, err := page.fieldOffset(slotID, fldname)
+	if err != nil {
+		return Interval{}, errors.Err(err, "fieldOffset")
+	}
+
+	words, err := page.readWords(offset, intervalLength/common.Int32Length)
+	if err != nil {
+		return Interval{}, errors.Err(err, "readWords")
+	}
+
+	return decodeInterval(words), nil
+}
+
+// SetInterval sets interval to the block.
+func (page *SlottedPage) SetInterval(slotID SlotID, fldname FieldName, val Interval) error {
+	return page.setField(slotID, fldname, encodeInterval(val), false)
+}
+
+// GetDecimal gets decimal from the block.
+func (page *SlottedPage) GetDecimal(slotID SlotID, fldname FieldName) (Decimal, error) {
+	offset, err := page.fieldOffset(slotID, fldname)
+	if err != nil {
+		return Decimal{}, errors.Err(err, "fieldOffset")
+	}
+
+	words, err := page.readWords(offset, decimalLength/common.Int32Length)
+	if err != nil {
+		return Decimal{}, errors.Err(err, "readWords")
+	}
+
+	return decodeDecimal(words), nil
+}
+
+// SetDecimal sets decimal to the block.
+func (page *SlottedPage) SetDecimal(slotID SlotID, fldname FieldName, val Decimal) error {
+	return page.setField(slotID, fldname, encodeDecimal(val), false)
+}
+
+// GetString gets string from the block.
+func (page *SlottedPage) GetString(slotID SlotID, fldname FieldName) (string, error) {
+	offset, err := page.fieldOffset(slotID, fldname)
+	if err != nil {
+		return "", errors.Err(err, "fieldOffset")
+	}
+
+	length, err := page.txn.GetInt32(page.blk, offset)
+	if err != nil {
+		return "", errors.Err(err, "GetInt32")
+	}
+
+	words, err := page.readWords(offset+common.Int32Length, wordAligned(int64(length))/common.Int32Length)
+	if err != nil {
+		return "", errors.Err(err, "readWords")
+	}
+
+	return string(unpackBytes(words)[:length]), nil
+}
+
+// SetString sets the string to the block.
+func (page *SlottedPage) SetString(slotID SlotID, fldname FieldName, val string) error {
+	return page.setField(slotID, fldname, encodeString(val), false)
+}
+
+// IsNull checks whether the value of the field is NULL or not.
+func (page *SlottedPage) IsNull(slotID SlotID, fldname FieldName) (bool, error) {
+	offset, _, err := page.slot(slotID)
+	if err != nil {
+		return false, errors.Err(err, "slot")
+	}
+
+	word, mask := page.nullFlagPosition(fldname)
+	flags, err := page.txn.GetInt32(page.blk, offset+common.Int32Length*int64(word))
+	if err != nil {
+		return false, errors.Err(err, "GetInt32")
+	}
+
+	return flags&mask != 0, nil
+}
+
+// SetNull sets NULL to the field.
+// varchar は空文字列にして領域を空ける.
+func (page *SlottedPage) SetNull(slotID SlotID, fldname FieldName) error {
+	return page.setField(slotID, fldname, page.emptyValue(fldname), true)
+}
+
+// GetVersion returns the version of the record.
+// versioned でない layout の record は zero value の version を返す.
+func (page *SlottedPage) GetVersion(slotID SlotID) (Version, error) {
+	if !page.layout.versioned {
+		return Version{}, nil
+	}
+
+	offset, _, err := page.slot(slotID)
+	if err != nil {
+		return Version{}, errors.Err(err, "slot")
+	}
+
+	words, err := page.readWords(offset, versionLength/common.Int32Length)
+	if err != nil {
+		return Version{}, errors.Err(err, "readWords")
+	}
+
+	return Version{Xmin: TransactionNumber(words[0]), Xmax: TransactionNumber(words[1])}, nil
+}
+
+// SetVersion sets the version of the record.
+// versioned でない layout では何もしない.
+func (page *SlottedPage) SetVersion(slotID SlotID, ver Version) error {
+	if !page.layout.versioned {
+		return nil
+	}
+
+	offset, _, err := page.slot(slotID)
+	if err != nil {
+		return errors.Err(err, "slot")
+	}
+
+	if err := page.setWord(offset, int32(ver.Xmin)); err != nil {
+		return errors.Err(err, "setWord")
+	}
+
+	return page.setWord(offset+common.Int32Length, int32(ver.Xmax))
+}
+
+// Delete deletes the record and moves the records below it to fill the space.
+func (page *SlottedPage) Delete(slotID SlotID) error {
+	if err := page.resize(slotID, 0); err != nil {
+		return errors.Err(err, "resize")
+	}
+
+	return page.setSlot(slotID, 0, 0)
+}
+
+// Format formats blk as an empty page.
+func (page *SlottedPage) Format() error {
+	if err := page.txn.SetInt32(page.blk, slotCountOffset, 0, false); err != nil {
+		return errors.Err(err, "SetInt32")
+	}
+
+	return page.txn.SetInt32(page.blk, freeEndOffset, int32(page.txn.BlockSize()), false)
+}
+
+// Clear fills the whole block with zero.
+// 0 で埋めた block は空の page として読める.
+func (page *SlottedPage) Clear() error {
+	return zeroFill(page.txn, page.blk)
+}
+
+// NextUsedSlot returns the slot id of the record after slot.
+func (page *SlottedPage) NextUsedSlot(slotID SlotID) (SlotID, error) {
+	n, err := page.numSlots()
+	if err != nil {
+		return 0, errors.Err(err, "numSlots")
+	}
+
+	for slotID++; slotID < n; slotID++ {
+		offset, _, err := page.slot(slotID)
+		if err != nil {
+			return 0, errors.Err(err, "slot")
+		}
+		if offset != 0 {
+			return slotID, nil
+		}
+	}
+
+	return -1, nil
+}
+
+// InsertAfter inserts a record whose fields are all NULL after slot and returns its id.
+// 挿入した record の値を後から設定できるように, 最大の大きさの record が入る場合だけ挿入する.
+func (page *SlottedPage) InsertAfter(slotID SlotID) (SlotID, error) {
+	maxLen := page.layout.slotsize
+	if slotDirOffset+slotEntryLength+maxLen > int64(page.txn.BlockSize()) {
+		return 0, ErrRecordTooLarge
+	}
+
+	n, err := page.numSlots()
+	if err != nil {
+		return 0, errors.Err(err, "numSlots")
+	}
+
+	newSlot := n
+	for id := slotID + 1; id < n; id++ {
+		offset, _, err := page.slot(id)
+		if err != nil {
+			return 0, errors.Err(err, "slot")
+		}
+		if offset == 0 {
+			newSlot = id
+
+			break
+		}
+	}
+
+	free, err := page.freeSpace()
+	if err != nil {
+		return 0, errors.Err(err, "freeSpace")
+	}
+	need := maxLen
+	if newSlot == n {
+		need += slotEntryLength
+	}
+	if free < need {
+		return -1, nil
+	}
+
+	if newSlot == n {
+		if err := page.txn.SetInt32(page.blk, slotCountOffset, int32(n+1), true); err != nil {
+			return 0, errors.Err(err, "SetInt32")
+		}
+	}
+
+	if err := page.writeRecord(newSlot, page.emptyRecord()); err != nil {
+		return 0, errors.Err(err, "writeRecord")
+	}
+
+	return newSlot, nil
+}
+
+// Block returns SlottedPage's block.
+func (page *SlottedPage) Block() Block {
+	return page.blk
+}
+
+// setField replaces the value of the field with the encoded words and sets its null flag.
+func (page *SlottedPage) setField(slotID SlotID, fldname FieldName, val []int32, isNull bool) error {
+	words, err := page.readRecord(slotID)
+	if err != nil {
+		return errors.Err(err, "readRecord")
+	}
+
+	pos := int((page.layout.versionBytes() + nullBitmapLength(len(page.layout.schema.fields))) / common.Int32Length)
+	for _, fld := range page.layout.schema.fields {
+		if fld == fldname {
+			break
+		}
+		pos += fieldWords(page.layout.schema.Type(fld), words[pos])
+	}
+	end := pos + fieldWords(page.layout.schema.Type(fldname), words[pos])
+
+	newWords := make([]int32, 0, len(words)-(end-pos)+len(val))
+	newWords = append(newWords, words[:pos]...)
+	newWords = append(newWords, val...)
+	newWords = append(newWords, words[end:]...)
+
+	word, mask := page.nullFlagPosition(fldname)
+	newWords[word] &^= mask
+	if isNull {
+		newWords[word] |= mask
+	}
+
+	return page.writeRecord(slotID, newWords)
+}
+
+// fieldOffset returns the offset of the field of the record in the block.
+func (page *SlottedPage) fieldOffset(slotID SlotID, fldname FieldName) (int64, error) {
+	offset, _, err := page.slot(slotID)
+	if err != nil {
+		return 0, errors.Err(err, "slot")
+	}
+
+	pos := offset + page.layout.versionBytes() + nullBitmapLength(len(page.layout.schema.fields))
+	for _, fld := range page.layout.schema.fields {
+		if fld == fldname {
+			return pos, nil
+		}
+
+		word, err := page.txn.GetInt32(page.blk, pos)
+		if err != nil {
+			return 0, errors.Err(err, "GetInt32")
+		}
+		pos += common.Int32Length * int64(fieldWords(page.layout.schema.Type(fld), word))
+	}
+
+	return 0, errors.Wrap(ErrFieldNotFound, fldname.String())
+}
+
+// readRecord reads the words of the record.
+// 空の slot は長さ 0 の record として扱う.
+func (page *SlottedPage) readRecord(slotID SlotID) ([]int32, error) {
+	offset, length, err := page.slot(slotID)
+	if err != nil {
+		return nil, errors.Err(err, "slot")
+	}
+
+	return page.readWords(offset, length/common.Int32Length)
+}
+
+// writeRecord writes the words as the record of the slot.
+// 値が変わらない word は log を残さないように書き込まない.
+func (page *SlottedPage) writeRecord(slotID SlotID, words []int32) error {
+	if err := page.resize(slotID, common.Int32Length*int64(len(words))); err != nil {
+		return errors.Err(err, "resize")
+	}
+
+	offset, _, err := page.slot(slotID)
+	if err != nil {
+		return errors.Err(err, "slot")
+	}
+
+	for i, word := range words {
+		if err := page.setWord(offset+common.Int32Length*int64(i), word); err != nil {
+			return errors.Err(err, "setWord")
+		}
+	}
+
+	return nil
+}
+
+// resize changes the byte length of the record of the slot.
+// record の下 (小さい offset) にある record を動かして, record と空き領域の間に隙間を作らない.
+// 長さが 0 になった record の領域は空き領域に戻る.
+func (page *SlottedPage) resize(slotID SlotID, newLen int64) error {
+	offset, length, err := page.slot(slotID)
+	if err != nil {
+		return errors.Err(err, "slot")
+	}
+	freeEnd, err := page.freeEnd()
+	if err != nil {
+		return errors.Err(err, "freeEnd")
+	}
+	if offset == 0 {
+		offset, length = freeEnd, 0
+	}
+
+	delta := newLen - length
+	if delta == 0 && length > 0 {
+		return nil
+	}
+
+	free, err := page.freeSpace()
+	if err != nil {
+		return errors.Err(err, "freeSpace")
+	}
+	if delta > free {
+		return ErrPageFull
+	}
+
+	// 下にある record を delta だけ下げる (delta が負なら上げる).
+	// 重なった領域を壊さないように, 動かす向きに応じて端から順に書く.
+	if delta > 0 {
+		for pos := freeEnd; pos < offset; pos += common.Int32Length {
+			if err := page.moveWord(pos, pos-delta); err != nil {
+				return errors.Err(err, "moveWord")
+			}
+		}
+	} else {
+		for pos := offset - common.Int32Length; pos >= freeEnd; pos -= common.Int32Length {
+			if err := page.moveWord(pos, pos-delta); err != nil {
+				return errors.Err(err, "moveWord")
+			}
+		}
+	}
+
+	n, err := page.numSlots()
+	if err != nil {
+		return errors.Err(err, "numSlots")
+	}
+	for id := SlotID(0); id < n; id++ {
+		if id == slotID {
+			continue
+		}
+		off, l, err := page.slot(id)
+		if err != nil {
+			return errors.Err(err, "slot")
+		}
+		if off != 0 && off < offset {
+			if err := page.setSlot(id, off-delta, l); err != nil {
+				return errors.Err(err, "setSlot")
+			}
+		}
+	}
+
+	if err := page.setWord(freeEndOffset, int32(freeEnd-delta)); err != nil {
+		return errors.Err(err, "setWord")
+	}
+
+	return page.setSlot(slotID, offset-delta, newLen)
+}
+
+func (page *SlottedPage) moveWord(from, to int64) error {
+	word, err := page.txn.GetInt32(page.blk, from)
+	if err != nil {
+		return errors.Err(err, "GetInt32")
+	}
+
+	return page.setWord(to, word)
+}
+
+// setWord writes the word with log if it differs from the current value.
+func (page *SlottedPage) setWord(offset int64, word int32) error {
+	return setInt32IfChanged(page.txn, page.blk, offset, word)
+}
+
+// setInt32IfChanged writes val at offset of blk with log if it differs from the current value.
+func setInt32IfChanged(txn Transaction, blk Block, offset int64, val int32) error {
+	old, err := txn.GetInt32(blk, offset)
+	if err != nil {
+		return errors.Err(err, "GetInt32")
+	}
+	if old == val {
+		return nil
+	}
+
+	return txn.SetInt32(blk, offset, val, true)
+}
+
+func (page *SlottedPage) readWords(offset int64, n int64) ([]int32, error) {
+	words := make([]int32, 0, n)
+	for i := int64(0); i < n; i++ {
+		word, err := page.txn.GetInt32(page.blk, offset+common.Int32Length*i)
+		if err != nil {
+			return nil, errors.Err(err, "GetInt32")
+		}
+		words = append(words, word)
+	}
+
+	return words, nil
+}
+
+// slot returns the offset and the byte length of the record of the slot.
+func (page *SlottedPage) slot(slotID SlotID) (int64, int64, error) {
+	pos := slotDirOffset + slotEntryLength*int64(slotID)
+	offset, err := page.txn.GetInt32(page.blk, pos)
+	if err != nil {
+		return 0, 0, errors.Err(err, "GetInt32")
+	}
+	length, err := page.txn.GetInt32(page.blk, pos+common.Int32Length)
+	if err != nil {
+		return 0, 0, errors.Err(err, "GetInt32")
+	}
+
+	return int64(offset), int64(length), nil
+}
+
+func (page *SlottedPage) setSlot(slotID SlotID, offset, length int64) error {
+	pos := slotDirOffset + slotEntryLength*int64(slotID)
+	if err := page.setWord(pos, int32(offset)); err != nil {
+		return errors.Err(err, "setWord")
+	}
+
+	return page.setWord(pos+common.Int32Length, int32(length))
+}
+
+func (page *SlottedPage) numSlots() (SlotID, error) {
+	n, err := page.txn.GetInt32(page.blk, slotCountOffset)
+	if err != nil {
+		return 0, errors.Err(err, "GetInt32")
+	}
+
+	return SlotID(n), nil
+}
+
+// freeEnd returns the end of free space.
+// 0 で埋められた block は空の page とみなす.
+func (page *SlottedPage) freeEnd() (int64, error) {
+	end, err := page.txn.GetInt32(page.blk, freeEndOffset)
+	if err != nil {
+		return 0, errors.Err(err, "GetInt32")
+	}
+	if end == 0 {
+		return int64(page.txn.BlockSize()), nil
+	}
+
+	return int64(end), nil
+}
+
+// freeSpace returns the byte length between the slot directory and the records.
+func (page *SlottedPage) freeSpace() (int64, error) {
+	n, err := page.numSlots()
+	if err != nil {
+		return 0, errors.Err(err, "numSlots")
+	}
+	end, err := page.freeEnd()
+	if err != nil {
+		return 0, errors.Err(err, "freeEnd")
+	}
+
+	return end - slotDirOffset - slotEntryLength*int64(n), nil
+}
+
+// emptyRecord returns the words of a record whose fields are all NULL.
+// versioned な layout では, xmin に transaction の番号を記録する.
+func (page *SlottedPage) emptyRecord() []int32 {
+	n := len(page.layout.schema.fields)
+	words := make([]int32, 0)
+	if page.layout.versioned {
+		words = append(words, int32(page.txn.GetTxNum()), 0)
+	}
+	for i := 0; i < n; i += nullBitsPerWord {
+		word := int32(-1)
+		if rest := n - i; rest < nullBitsPerWord {
+			word = int32(uint32(1)<<rest - 1)
+		}
+		words = append(words, word)
+	}
+	for _, fld := range page.layout.schema.fields {
+		words = append(words, page.emptyValue(fld)...)
+	}
+
+	return words
+}
+
+// emptyValue returns the words of zero value of the field.
+func (page *SlottedPage) emptyValue(fldname FieldName) []int32 {
+	typ := page.layout.schema.Type(fldname)
+	switch {
+	case typ == StringFieldType:
+		return encodeString("")
+	case typ.IsInt64Sized():
+		return []int32{0, 0}
+	case typ == IntervalFieldType:
+		return encodeInterval(Interval{})
+	case typ == NumericFieldType:
+		return encodeDecimal(Decimal{})
+	default:
+		return []int32{0}
+	}
+}
+
+// nullFlagPosition returns the index of the null bitmap word of the field in the record and its bit mask.
+func (page *SlottedPage) nullFlagPosition(fldname FieldName) (int, int32) {
+	bit := page.layout.nullBits[fldname]
+
+	return int(page.layout.versionBytes()/common.Int32Length) + bit/nullBitsPerWord, int32(uint32(1) << (bit % nullBitsPerWord))
+}
+
+// fieldWords returns the number of words of the field value whose first word is head.
+func fieldWords(typ FieldType, head int32) int {
+	switch {
+	case typ == StringFieldType:
+		return 1 + int(wordAligned(int64(head))/common.Int32Length)
+	case typ.IsInt64Sized():
+		return common.Int64Length / common.Int32Length
+	case typ == IntervalFieldType:
+		return intervalLength / common.Int32Length
+	case typ == NumericFieldType:
+		return decimalLength / common.Int32Length
+	default:
+		return 1
+	}
+}
+
+// wordAligned rounds n up to a multiple of int32 length.
+func wordAligned(n int64) int64 {
+	return (n + common.Int32Length - 1) / common.Int32Length * common.Int32Length
+}
+
+// encodeInterval encodes the interval into words in the same order as RecordPage.
+func encodeInterval(val Interval) []int32 {
+	return []int32{int32(val.Micros >> 32), int32(val.Micros), val.Days, val.Months}
+}
+
+// decodeInterval decodes the words encoded by encodeInterval.
+func decodeInterval(words []int32) Interval {
+	micros := int64(words[0])<<32 | int64(uint32(words[1]))
+
+	return Interval{Months: words[3], Days: words[2], Micros: micros}
+}
+
+// encodeDecimal encodes the decimal into words in the same order as RecordPage.
+func encodeDecimal(val Decimal) []int32 {
+	return []int32{int32(val.Hi >> 32), int32(val.Hi), int32(val.Lo >> 32), int32(val.Lo), val.Scale}
+}
+
+// decodeDecimal decodes the words encoded by encodeDecimal.
+func decodeDecimal(words []int32) Decimal {
+	hi := int64(words[0])<<32 | int64(uint32(words[1]))
+	lo := uint64(uint32(words[2]))<<32 | uint64(uint32(words[3]))
+
+	return Decimal{Hi: hi, Lo: lo, Scale: words[4]}
+}
+
+// encodeString encodes the string into its length and bytes packed into words.
+func encodeString(val string) []int32 {
+	b := make([]byte, wordAligned(int64(len(val))))
+	copy(b, val)
+
+	words := []int32{int32(len(val))}
+	for i := 0; i < len(b); i += common.Int32Length {
+		words = append(words, int32(binary.BigEndian.Uint32(b[i:])))
+	}
+
+	return words
+}
+
+// unpackBytes unpacks the bytes packed into words.
+func unpackBytes(words []int32) []byte {
+	b := make([]byte, common.Int32Length*len(words))
+	for i, word := range words {
+		binary.BigEndian.PutUint32(b[common.Int32Length*i:], uint32(word))
+	}
+
+	return b
+}
@@ -0,0 +1,185 @@
+package domain_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/testing/fake"
+	"github.com/goropikari/simpledbgo/tx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlottedPage(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 3
+	)
+
+	dbPath := fake.RandString()
+	factory := fake.NewNonDirectBufferManagerFactory(dbPath, blockSize, numBuf)
+	fileMgr, logMgr, bufMgr := factory.Create()
+	defer factory.Finish()
+
+	cfg := tx.LockTableConfig{LockTimeoutMillisecond: 1000}
+	lt := tx.NewLockTable(cfg)
+
+	gen := tx.NewNumberGenerator()
+
+	sch := domain.NewSchema()
+	sch.AddInt32Field("A")
+	sch.AddStringField("B", 200)
+	layout := domain.NewSlottedLayout(sch)
+
+	scanAll := func(ts *domain.TableScan) []string {
+		err := ts.BeforeFirst()
+		require.NoError(t, err)
+
+		actual := make([]string, 0)
+		for ts.HasNext() {
+			a, err := ts.GetInt32("A")
+			require.NoError(t, err)
+			b, err := ts.GetVal("B")
+			require.NoError(t, err)
+			actual = append(actual, fmt.Sprintf("%v %v", a, b))
+		}
+		require.NoError(t, ts.Err())
+
+		return actual
+	}
+
+	t.Run("test short records share a block", func(t *testing.T) {
+		txn, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+
+		ts, err := domain.NewTableScan(txn, "short.tbl", layout)
+		require.NoError(t, err)
+		expected := make([]string, 0)
+		for i := 1; i <= 20; i++ {
+			err := ts.AdvanceNextInsertSlotID()
+			require.NoError(t, err)
+			err = ts.SetInt32("A", int32(i))
+			require.NoError(t, err)
+			err = ts.SetString("B", fmt.Sprintf("rec%v", i))
+			require.NoError(t, err)
+			expected = append(expected, fmt.Sprintf("%v rec%v", i, i))
+		}
+		require.Equal(t, expected, scanAll(ts))
+		ts.Close()
+
+		// fixed format なら 1 block に 1 record しか入らない.
+		size, err := txn.BlockLength("short.tbl")
+		require.NoError(t, err)
+		require.Less(t, size, int32(20))
+		require.NoError(t, txn.Commit())
+	})
+
+	t.Run("test update, delete and rollback", func(t *testing.T) {
+		txn, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+
+		ts, err := domain.NewTableScan(txn, "update.tbl", layout)
+		require.NoError(t, err)
+		for i := 1; i <= 4; i++ {
+			err := ts.AdvanceNextInsertSlotID()
+			require.NoError(t, err)
+			err = ts.SetInt32("A", int32(i))
+			require.NoError(t, err)
+			err = ts.SetString("B", fmt.Sprintf("rec%v", i))
+			require.NoError(t, err)
+		}
+		ts.Close()
+		require.NoError(t, txn.Commit())
+
+		txn2, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		ts2, err := domain.NewTableScan(txn2, "update.tbl", layout)
+		require.NoError(t, err)
+
+		long := strings.Repeat("x", 40)
+		for ts2.HasNext() {
+			a, err := ts2.GetInt32("A")
+			require.NoError(t, err)
+			switch a {
+			case 1:
+				err = ts2.SetString("B", long)
+			case 2:
+				err = ts2.Delete()
+			case 3:
+				err = ts2.SetVal("B", domain.NewNullConstant())
+			case 4:
+				err = ts2.SetString("B", "")
+			}
+			require.NoError(t, err)
+		}
+		require.NoError(t, ts2.Err())
+		require.Equal(t, []string{"1 " + long, "3 null", "4 "}, scanAll(ts2))
+
+		// 削除した slot を再利用し, 縮めた record の領域に書き込める.
+		err = ts2.BeforeFirst()
+		require.NoError(t, err)
+		err = ts2.AdvanceNextInsertSlotID()
+		require.NoError(t, err)
+		require.Equal(t, domain.NewRecordID(0, 1), ts2.RecordID())
+		err = ts2.SetInt32("A", 5)
+		require.NoError(t, err)
+		err = ts2.SetString("B", "rec5")
+		require.NoError(t, err)
+		err = ts2.SetString("B", "r5")
+		require.NoError(t, err)
+		require.Equal(t, []string{"1 " + long, "5 r5", "3 null", "4 "}, scanAll(ts2))
+		ts2.Close()
+		require.NoError(t, txn2.Rollback())
+
+		txn3, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		ts3, err := domain.NewTableScan(txn3, "update.tbl", layout)
+		require.NoError(t, err)
+		require.Equal(t, []string{"1 rec1", "2 rec2", "3 rec3", "4 rec4"}, scanAll(ts3))
+		ts3.Close()
+		require.NoError(t, txn3.Commit())
+	})
+
+	t.Run("test record too large", func(t *testing.T) {
+		txn, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+
+		bigSch := domain.NewSchema()
+		bigSch.AddStringField("B", blockSize)
+
+		ts, err := domain.NewTableScan(txn, "big.tbl", domain.NewSlottedLayout(bigSch))
+		require.NoError(t, err)
+		err = ts.AdvanceNextInsertSlotID()
+		require.ErrorIs(t, err, domain.ErrRecordTooLarge)
+		ts.Close()
+		require.NoError(t, txn.Rollback())
+	})
+}
+
+func TestNewRecordFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		expected domain.RecordFormat
+		err      error
+	}{
+		{name: "fixed", format: "fixed", expected: domain.FixedRecordFormat},
+		{name: "slotted", format: "SLOTTED", expected: domain.SlottedRecordFormat},
+		{name: "unknown", format: "heap", err: domain.ErrUnknownRecordFormat},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := domain.NewRecordFormat(tt.format)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
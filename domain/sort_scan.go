@@ -0,0 +1,365 @@
+package domain
+
+import (
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// SortOrder is an order of sorting.
+type SortOrder uint
+
+const (
+	// Asc is ascending order.
+	Asc SortOrder = iota
+
+	// Desc is descending order.
+	Desc
+)
+
+// SortKey is a key of sorting.
+type SortKey struct {
+	fld   FieldName
+	order SortOrder
+}
+
+// NewSortKey constructs a SortKey.
+func NewSortKey(fld FieldName, order SortOrder) SortKey {
+	return SortKey{
+		fld:   fld,
+		order: order,
+	}
+}
+
+// FieldName returns the field name of the key.
+func (key SortKey) FieldName() FieldName {
+	return key.fld
+}
+
+// Order returns the sort order of the key.
+func (key SortKey) Order() SortOrder {
+	return key.order
+}
+
+// String stringfies the key.
+func (key SortKey) String() string {
+	if key.order == Desc {
+		return key.fld.String() + " desc"
+	}
+
+	return key.fld.String()
+}
+
+// RecordComparator compares records by sort keys.
+type RecordComparator struct {
+	keys []SortKey
+}
+
+// NewRecordComparator constructs a RecordComparator.
+func NewRecordComparator(keys []SortKey) *RecordComparator {
+	return &RecordComparator{
+		keys: keys,
+	}
+}
+
+// Compare compares the current records of s1 and s2.
+// It returns negative value if the record of s1 comes first.
+func (comp *RecordComparator) Compare(s1, s2 Scanner) (int, error) {
+	for _, key := range comp.keys {
+		v1, err := s1.GetVal(key.fld)
+		if err != nil {
+			return 0, errors.Err(err, "GetVal")
+		}
+
+		v2, err := s2.GetVal(key.fld)
+		if err != nil {
+			return 0, errors.Err(err, "GetVal")
+		}
+
+		if c := compareByOrder(v1, v2, key.order); c != 0 {
+			return c, nil
+		}
+	}
+
+	return 0, nil
+}
+
+// CompareValues compares the records given as field values.
+// It returns negative value if r1 comes first.
+func (comp *RecordComparator) CompareValues(r1, r2 map[FieldName]Constant) int {
+	for _, key := range comp.keys {
+		if c := compareByOrder(r1[key.fld], r2[key.fld], key.order); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+func compareByOrder(v1, v2 Constant, order SortOrder) int {
+	c := v1.Compare(v2)
+	if order == Desc {
+		return -c
+	}
+
+	return c
+}
+
+// SortScan is a scanner which merges sorted runs.
+// Each run is a temporary table whose records are already sorted.
+// 全ての record が memory に収まった場合は temporary table に書き出さずに memory 上の record を返す.
+type SortScan struct {
+	runs    []*TempTable
+	scans   []*TableScan
+	hasMore []bool
+	current int
+	comp    *RecordComparator
+	err     error
+
+	sch      *Schema
+	recs     []map[FieldName]Constant
+	inMemory bool
+}
+
+// SortScanPosition is a saved position of SortScan.
+type SortScanPosition struct {
+	rids    []RecordID
+	hasMore []bool
+	current int
+}
+
+// NewSortScan constructs a SortScan.
+func NewSortScan(runs []*TempTable, comp *RecordComparator) (*SortScan, error) {
+	scans := make([]*TableScan, 0, len(runs))
+	for _, run := range runs {
+		ts, err := run.Open()
+		if err != nil {
+			for _, s := range scans {
+				s.Close()
+			}
+
+			return nil, errors.Err(err, "Open")
+		}
+		scans = append(scans, ts)
+	}
+
+	ss := &SortScan{
+		runs:    runs,
+		scans:   scans,
+		hasMore: make([]bool, len(scans)),
+		current: -1,
+		comp:    comp,
+	}
+
+	if err := ss.BeforeFirst(); err != nil {
+		ss.Close()
+
+		return nil, errors.Err(err, "BeforeFirst")
+	}
+
+	return ss, nil
+}
+
+// NewMemorySortScan constructs a SortScan of the records sorted in memory.
+func NewMemorySortScan(sch *Schema, recs []map[FieldName]Constant) *SortScan {
+	return &SortScan{
+		current:  -1,
+		sch:      sch,
+		recs:     recs,
+		inMemory: true,
+	}
+}
+
+// BeforeFirst move to the position before the first record.
+// BeforeFirst implements Scanner.
+func (ss *SortScan) BeforeFirst() error {
+	ss.current = -1
+	if ss.inMemory {
+		return nil
+	}
+
+	for i, s := range ss.scans {
+		if err := s.BeforeFirst(); err != nil {
+			return errors.Err(err, "BeforeFirst")
+		}
+
+		ss.hasMore[i] = s.HasNext()
+		if err := s.Err(); err != nil {
+			return errors.Err(err, "HasNext")
+		}
+	}
+
+	return nil
+}
+
+// HasNext checks the existence of next record.
+// HasNext implements Scanner.
+func (ss *SortScan) HasNext() bool {
+	if ss.inMemory {
+		if ss.current < len(ss.recs) {
+			ss.current++
+		}
+
+		return ss.current < len(ss.recs)
+	}
+
+	if ss.current >= 0 {
+		s := ss.scans[ss.current]
+		ss.hasMore[ss.current] = s.HasNext()
+		if err := s.Err(); err != nil {
+			ss.err = err
+
+			return false
+		}
+	}
+
+	ss.current = -1
+	for i, s := range ss.scans {
+		if !ss.hasMore[i] {
+			continue
+		}
+		if ss.current < 0 {
+			ss.current = i
+
+			continue
+		}
+
+		c, err := ss.comp.Compare(s, ss.scans[ss.current])
+		if err != nil {
+			ss.err = err
+
+			return false
+		}
+		if c < 0 {
+			ss.current = i
+		}
+	}
+
+	return ss.current >= 0
+}
+
+// GetInt32 gets int32 from the table.
+// GetInt32 implements Scanner.
+func (ss *SortScan) GetInt32(fld FieldName) (int32, error) {
+	if ss.inMemory {
+		val, err := ss.GetVal(fld)
+		if err != nil {
+			return 0, errors.Err(err, "GetVal")
+		}
+
+		return val.AsInt32()
+	}
+
+	return ss.scans[ss.current].GetInt32(fld)
+}
+
+// GetString gets string from the table.
+// GetString implements Scanner.
+func (ss *SortScan) GetString(fld FieldName) (string, error) {
+	if ss.inMemory {
+		val, err := ss.GetVal(fld)
+		if err != nil {
+			return "", errors.Err(err, "GetVal")
+		}
+
+		return val.AsString()
+	}
+
+	return ss.scans[ss.current].GetString(fld)
+}
+
+// GetVal gets value from the table.
+// GetVal implements Scanner.
+func (ss *SortScan) GetVal(fld FieldName) (Constant, error) {
+	if ss.inMemory {
+		if ss.current >= 0 && ss.current < len(ss.recs) {
+			if val, ok := ss.recs[ss.current][fld]; ok {
+				return val, nil
+			}
+		}
+
+		return Constant{}, fieldNotFoudError(fld)
+	}
+
+	return ss.scans[ss.current].GetVal(fld)
+}
+
+// HasField checks the existence of the field.
+// HasField implements Scanner.
+func (ss *SortScan) HasField(fld FieldName) bool {
+	if ss.inMemory {
+		return ss.sch.HasField(fld)
+	}
+
+	if len(ss.scans) == 0 {
+		return false
+	}
+
+	return ss.scans[0].HasField(fld)
+}
+
+// Close closes the scan and drops the runs.
+// Close implements Scanner.
+func (ss *SortScan) Close() {
+	for _, s := range ss.scans {
+		s.Close()
+	}
+	ss.scans = nil
+
+	for _, run := range ss.runs {
+		if err := run.Drop(); err != nil && ss.err == nil {
+			ss.err = errors.Err(err, "Drop")
+		}
+	}
+	ss.runs = nil
+}
+
+// Err returns iteration err.
+// Err implements Scanner.
+func (ss *SortScan) Err() error {
+	return ss.err
+}
+
+// SavePosition saves the current position.
+func (ss *SortScan) SavePosition() SortScanPosition {
+	if ss.inMemory {
+		return SortScanPosition{current: ss.current}
+	}
+
+	rids := make([]RecordID, len(ss.scans))
+	for i, s := range ss.scans {
+		if ss.hasMore[i] {
+			rids[i] = s.RecordID()
+		}
+	}
+
+	hasMore := make([]bool, len(ss.hasMore))
+	copy(hasMore, ss.hasMore)
+
+	return SortScanPosition{
+		rids:    rids,
+		hasMore: hasMore,
+		current: ss.current,
+	}
+}
+
+// RestorePosition moves to the saved position.
+func (ss *SortScan) RestorePosition(pos SortScanPosition) error {
+	if ss.inMemory {
+		ss.current = pos.current
+
+		return nil
+	}
+
+	for i, s := range ss.scans {
+		if !pos.hasMore[i] {
+			continue
+		}
+		if err := s.MoveToRecordID(pos.rids[i]); err != nil {
+			return errors.Err(err, "MoveToRecordID")
+		}
+	}
+
+	copy(ss.hasMore, pos.hasMore)
+	ss.current = pos.current
+
+	return nil
+}
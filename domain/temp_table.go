@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// TempTablePrefix is the prefix of temporary table names.
+// Files having this prefix are removed when the database starts.
+// '#' は identifier に使えないので user が作る table と名前が衝突しない.
+const TempTablePrefix = "#temp"
+
+var tempTableNumber int64
+
+// TempTable is a table used for materializing intermediate results.
+type TempTable struct {
+	txn     Transaction
+	tblName TableName
+	layout  *Layout
+}
+
+// NewTempTable constructs a TempTable.
+func NewTempTable(txn Transaction, sch *Schema) *TempTable {
+	return &TempTable{
+		txn:     txn,
+		tblName: nextTempTableName(),
+		layout:  NewLayout(sch),
+	}
+}
+
+// Open opens a table scan for the temporary table.
+func (tt *TempTable) Open() (*TableScan, error) {
+	ts, err := NewTableScan(tt.txn, tt.tblName, tt.layout)
+	if err != nil {
+		return nil, errors.Err(err, "NewTableScan")
+	}
+
+	return ts, nil
+}
+
+// TableName returns the name of the temporary table.
+func (tt *TempTable) TableName() TableName {
+	return tt.tblName
+}
+
+// Layout returns the layout of the temporary table.
+func (tt *TempTable) Layout() *Layout {
+	return tt.layout
+}
+
+// Drop removes the files of the temporary table when the transaction ends.
+// drop した後の temporary table は開けない.
+func (tt *TempTable) Drop() error {
+	for _, filename := range []FileName{tt.tblName.ToFileName(), tt.tblName.ToOverflowFileName()} {
+		if err := tt.txn.RemoveFile(filename); err != nil {
+			return errors.Err(err, "RemoveFile")
+		}
+	}
+
+	return nil
+}
+
+func nextTempTableName() TableName {
+	n := atomic.AddInt64(&tempTableNumber, 1)
+
+	return TableName(fmt.Sprintf("%v%v", TempTablePrefix, n))
+}
@@ -1,7 +1,12 @@
 package domain
 
+import "github.com/goropikari/simpledbgo/errors"
+
 //go:generate mockgen -source=${GOFILE} -destination=${ROOT_DIR}/testing/mock/mock_${GOPACKAGE}_${GOFILE} -package=mock
 
+// ErrSerializationFailure is an error that means the record was updated by a concurrent transaction.
+var ErrSerializationFailure = errors.New("could not serialize access due to concurrent update")
+
 // TransactionNumber is transaction number.
 type TransactionNumber int32
 
@@ -19,14 +24,63 @@ type Transaction interface {
 	Recover() error
 	GetInt32(blk Block, offset int64) (val int32, err error)
 	SetInt32(blk Block, offset int64, val int32, writeLog bool) error
+	GetInt64(blk Block, offset int64) (val int64, err error)
+	SetInt64(blk Block, offset int64, val int64, writeLog bool) error
 	GetString(blk Block, offset int64) (val string, err error)
 	SetString(blk Block, offset int64, val string, writeLog bool) error
 	BlockLength(FileName) (int32, error)
 	ExtendFile(FileName) (Block, error)
+	RemoveFile(FileName) error
+	RemoveFileOnRollback(FileName) error
 	BlockSize() BlockSize
-	// Available() int
+	Available() int
+	SLock(Block) error
+	XLock(Block) error
+	GetTxNum() TransactionNumber
+	Snapshot() *Snapshot
+	ForUpdate(bool)
+	IsVisibleToAll(TransactionNumber) bool
 }
 
 type TxNumberGenerator interface {
 	Generate() TransactionNumber
 }
+
+// Version is the pair of the transactions which created and deleted a record.
+// 0 は transaction が無いことを表す.
+type Version struct {
+	Xmin TransactionNumber
+	Xmax TransactionNumber
+}
+
+// Snapshot is the set of transactions whose changes are visible to a transaction.
+// transaction の開始時に実行中だった transaction と, それより後に始まった transaction の変更は見えない.
+type Snapshot struct {
+	txNum  TransactionNumber
+	active map[TransactionNumber]bool
+}
+
+// NewSnapshot constructs a Snapshot of the transaction txNum.
+// active is the set of transactions which were running when txNum started.
+func NewSnapshot(txNum TransactionNumber, active map[TransactionNumber]bool) *Snapshot {
+	return &Snapshot{
+		txNum:  txNum,
+		active: active,
+	}
+}
+
+// TxNum returns the number of the transaction which owns the snapshot.
+func (s *Snapshot) TxNum() TransactionNumber {
+	return s.txNum
+}
+
+// IsVisible checks whether the changes of txNum are visible.
+// rollback した transaction の変更は undo で消えているので, 終わった transaction は commit したものとみなせる.
+func (s *Snapshot) IsVisible(txNum TransactionNumber) bool {
+	return txNum == s.txNum || (txNum < s.txNum && !s.active[txNum])
+}
+
+// IsVisibleVersion checks whether the record version is visible.
+func (s *Snapshot) IsVisibleVersion(ver Version) bool {
+	return s.IsVisible(ver.Xmin) && (ver.Xmax == 0 || !s.IsVisible(ver.Xmax))
+}
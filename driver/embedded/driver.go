@@ -135,10 +135,7 @@ func (stmt *Stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (d
 
 	fields := stmt.plan.Schema().Fields()
 
-	if !stmt.cn.inTxn {
-		return nil, stmt.cn.txn.Commit()
-	}
-
+	// transaction 外の query は rows を読み終えた後の Stmt.Close で commit する.
 	return &Rows{
 		scan:   scan,
 		fields: fields,
@@ -156,6 +153,7 @@ func (cn *Conn) Commit() error {
 // Rollback satisfies driver.Tx interface.
 func (cn *Conn) Rollback() error {
 	// fmt.Println("Tx.Rollback")
+	cn.inTxn = false
 
 	return cn.txn.Rollback()
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	_ "github.com/goropikari/simpledbgo/driver/embedded"
 	"github.com/goropikari/simpledbgo/testing/fake"
@@ -110,4 +111,149 @@ func TestConn(t *testing.T) {
 		acstr5 = append(acstr5, b)
 	}
 	require.Equal(t, []string{"rec1"}, acstr5)
+
+	rows6, err := db.QueryContext(context.Background(), "select A + 1 as x, B || '!' from T1 where A = 2")
+	require.NoError(t, err)
+	cols, err := rows6.Columns()
+	require.NoError(t, err)
+	require.Equal(t, []string{"x", "(b||'!')"}, cols)
+	acnum6 := make([]int, 0)
+	for rows6.Next() {
+		var x int
+		var b string
+		err = rows6.Scan(&x, &b)
+		require.NoError(t, err)
+		require.Equal(t, "rec2!", b)
+
+		acnum6 = append(acnum6, x)
+	}
+	require.Equal(t, []int{3}, acnum6)
+}
+
+func TestConn_Null(t *testing.T) {
+	dbpath := "simpledb_" + fake.RandString()
+	t.Setenv("SIMPLEDB_PATH", dbpath)
+	defer os.RemoveAll(dbpath)
+
+	db, err := sql.Open("simpledb", "dsn hoge")
+	require.NoError(t, err)
+
+	cmds := []string{
+		"create table T1(A int, B varchar(9))",
+		"insert into T1(A, B) values (1, 'rec1')",
+		"insert into T1(A) values (2)",
+		"insert into T1(A, B) values (3, null)",
+		"insert into T1(B) values ('rec4')",
+	}
+	for _, cmd := range cmds {
+		_, err := db.Exec(cmd)
+		require.NoError(t, err)
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{name: "select all", query: "select A, B from T1", expected: []string{"1,rec1", "2,NULL", "3,NULL", "NULL,rec4"}},
+		{name: "is null", query: "select A, B from T1 where B is null", expected: []string{"2,NULL", "3,NULL"}},
+		{name: "is not null", query: "select A, B from T1 where A is not null and B is not null", expected: []string{"1,rec1"}},
+		{name: "comparison with null is unknown", query: "select A, B from T1 where B = 'rec1' or not (B = 'rec1')", expected: []string{"1,rec1", "NULL,rec4"}},
+		{name: "not unknown is unknown", query: "select A, B from T1 where not (A + 1 > 2)", expected: []string{"1,rec1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := db.QueryContext(context.Background(), tt.query)
+			require.NoError(t, err)
+			defer rows.Close()
+
+			actual := make([]string, 0)
+			for rows.Next() {
+				var a sql.NullInt32
+				var b sql.NullString
+				err := rows.Scan(&a, &b)
+				require.NoError(t, err)
+
+				row := "NULL,"
+				if a.Valid {
+					row = fmt.Sprintf("%v,", a.Int32)
+				}
+				if b.Valid {
+					row += b.String
+				} else {
+					row += "NULL"
+				}
+				actual = append(actual, row)
+			}
+			require.NoError(t, rows.Err())
+			require.ElementsMatch(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestConn_Datetime(t *testing.T) {
+	dbpath := "simpledb_" + fake.RandString()
+	t.Setenv("SIMPLEDB_PATH", dbpath)
+	defer os.RemoveAll(dbpath)
+
+	db, err := sql.Open("simpledb", "dsn hoge")
+	require.NoError(t, err)
+
+	cmds := []string{
+		"create table T1(A date, B timestamp, C timestamptz, D interval)",
+		"insert into T1(A, B, C, D) values (date '2024-02-29', timestamp '2024-02-29 12:34:56.5', timestamptz '2024-02-29 12:00:00+09', interval '1 day 02:00:00')",
+	}
+	for _, cmd := range cmds {
+		_, err := db.Exec(cmd)
+		require.NoError(t, err)
+	}
+
+	rows, err := db.QueryContext(context.Background(), "select A, B, C, D from T1")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	require.True(t, rows.Next())
+	var a, b, c time.Time
+	var d string
+	require.NoError(t, rows.Scan(&a, &b, &c, &d))
+	require.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), a)
+	require.Equal(t, time.Date(2024, 2, 29, 12, 34, 56, 500000000, time.UTC), b)
+	require.True(t, time.Date(2024, 2, 29, 3, 0, 0, 0, time.UTC).Equal(c))
+	require.Equal(t, "1 day 02:00:00", d)
+	require.False(t, rows.Next())
+	require.NoError(t, rows.Err())
+}
+
+func TestConn_Decimal(t *testing.T) {
+	dbpath := "simpledb_" + fake.RandString()
+	t.Setenv("SIMPLEDB_PATH", dbpath)
+	defer os.RemoveAll(dbpath)
+
+	db, err := sql.Open("simpledb", "dsn hoge")
+	require.NoError(t, err)
+
+	cmds := []string{
+		"create table T1(A numeric(10, 2))",
+		"insert into T1(A) values (1234.5)",
+	}
+	for _, cmd := range cmds {
+		_, err := db.Exec(cmd)
+		require.NoError(t, err)
+	}
+
+	rows, err := db.QueryContext(context.Background(), "select A from T1")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	require.True(t, rows.Next())
+	var a string
+	require.NoError(t, rows.Scan(&a))
+	require.Equal(t, "1234.50", a)
+
+	var f float64
+	require.NoError(t, rows.Scan(&f))
+	require.Equal(t, 1234.5, f)
+	require.False(t, rows.Next())
+	require.NoError(t, rows.Err())
 }
@@ -118,4 +118,22 @@ func TestPG(t *testing.T) {
 		acstr5 = append(acstr5, b)
 	}
 	require.Equal(t, []string{"rec1"}, acstr5)
+
+	_, err = db.Exec("create table T2(C int, D varchar(9))")
+	require.NoError(t, err)
+	_, err = db.Exec("insert into T2(C, D) values (1, 'one')")
+	require.NoError(t, err)
+
+	rows6, err := db.QueryContext(context.Background(), "select A, D from T1 left join T2 on A = C")
+	require.NoError(t, err)
+	acstr6 := make([]sql.NullString, 0)
+	for rows6.Next() {
+		var a int
+		var d sql.NullString
+		err = rows6.Scan(&a, &d)
+		require.NoError(t, err)
+
+		acstr6 = append(acstr6, d)
+	}
+	require.Equal(t, []sql.NullString{{}, {String: "one", Valid: true}, {}}, acstr6)
 }
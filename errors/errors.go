@@ -27,3 +27,7 @@ func Err(err error, word string) error {
 func Is(err1, err2 error) bool {
 	return errors.Is(err1, err2)
 }
+
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
+}
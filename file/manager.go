@@ -176,6 +176,18 @@ func (mgr *Manager) ExtendFile(filename domain.FileName) (domain.Block, error) {
 	return blk, nil
 }
 
+// RemoveFile removes the file.
+func (mgr *Manager) RemoveFile(filename domain.FileName) error {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+
+	if err := mgr.explorer.RemoveFile(filename); err != nil {
+		return errors.Err(err, "RemoveFile")
+	}
+
+	return nil
+}
+
 // BlockLength returns the number of block of the file.
 func (mgr *Manager) BlockLength(filename domain.FileName) (int32, error) {
 	file, err := mgr.OpenFile(filename)
@@ -5,6 +5,7 @@ go 1.18
 require (
 	github.com/golang/mock v1.6.0
 	github.com/google/wire v0.5.0
+	github.com/lib/pq v1.10.6
 	github.com/ncw/directio v1.0.5-0.20220118110502-743c0ba8bd96
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.7.0
@@ -14,7 +15,6 @@ require (
 
 require (
 	github.com/davecgh/go-spew v1.1.0 // indirect
-	github.com/lib/pq v1.10.6 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/yuin/goldmark v1.4.1 // indirect
 	golang.org/x/mod v0.6.0-dev.0.20220419223038-86c51ed26bb4 // indirect
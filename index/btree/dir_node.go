@@ -65,8 +65,8 @@ func (p *DirPage) insertDir(slotID domain.SlotID, val domain.Constant, blkNum do
 	if err := p.insert(slotID); err != nil {
 		return errors.Err(err, "insert")
 	}
-	if err := p.setVal(slotID, domain.FldDataVal, val); err != nil {
-		return errors.Err(err, "setVal")
+	if err := p.setDataVal(slotID, val); err != nil {
+		return errors.Err(err, "setDataVal")
 	}
 	if err := p.setInt32(slotID, domain.FldBlock, int32(blkNum)); err != nil {
 		return errors.Err(err, "setInt32")
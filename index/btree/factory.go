@@ -0,0 +1,40 @@
+package btree
+
+import (
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// IndexFactory is generator of index.
+type IndexFactory struct{}
+
+// NewIndexFactory constructs an IndexFactory.
+func NewIndexFactory() *IndexFactory {
+	return &IndexFactory{}
+}
+
+// Create creates an Index.
+// index file の準備に失敗した場合は, 全ての操作がその error を返す Index を返す.
+func (fty *IndexFactory) Create(txn domain.Transaction, idxName domain.IndexName, layout *domain.Layout) domain.Indexer {
+	idx, err := NewIndex(txn, idxName, layout)
+	if err != nil {
+		err = errors.Err(err, "NewIndex")
+
+		return &Index{err: err, openErr: err}
+	}
+
+	return idx
+}
+
+// SupportsRange checks whether the index supports range scan.
+func (fty *IndexFactory) SupportsRange() bool {
+	return true
+}
+
+// FileNames returns the leaf and directory files of the index.
+func (fty *IndexFactory) FileNames(idxName domain.IndexName) []domain.FileName {
+	return []domain.FileName{
+		domain.FileName(idxName.String() + "leaf"),
+		domain.FileName(idxName.String() + "dir"),
+	}
+}
@@ -15,8 +15,10 @@ type Index struct {
 	leafLayout  *domain.Layout // domain.createIdxLayout で作られた Layout が入ってくる。
 	leafTblName domain.FileName
 	leaf        *LeafNode
+	cursor      *rangeCursor
 	rootBlk     domain.Block
 	err         error
+	openErr     error
 }
 
 // NewIndex constructs a index.
@@ -48,7 +50,10 @@ func NewIndex(txn domain.Transaction, idxName domain.IndexName, leafLayout *doma
 	// dir node
 	dirSch := domain.NewSchema()
 	dirSch.Add(domain.FldBlock, leafLayout.Schema())
-	dirSch.Add(domain.FldDataVal, leafLayout.Schema())
+	keyFlds := domain.IndexKeyFields(leafLayout.Schema())
+	for _, fld := range keyFlds {
+		dirSch.Add(fld, leafLayout.Schema())
+	}
 	dirTblName, err := domain.NewFileName(idxName.String() + "dir")
 	if err != nil {
 		return nil, errors.Err(err, "NewFileName")
@@ -75,18 +80,38 @@ func NewIndex(txn domain.Transaction, idxName domain.IndexName, leafLayout *doma
 		}
 
 		// insert initial directory entry (sentinel)
-		fldType := dirSch.Type(domain.FldDataVal)
-		var minVal domain.Constant
-		switch fldType {
-		case domain.Int32FieldType:
-			minVal = domain.NewConstant(fldType, int32(math.MinInt32))
-		case domain.StringFieldType:
-			minVal = domain.NewConstant(fldType, "")
-		case domain.UnknownFieldType:
-			panic(fmt.Errorf("not supported FieldType %v", fldType))
-		default:
-			panic(fmt.Errorf("not supported FieldType %v", fldType))
+		minVals := make([]domain.Constant, 0, len(keyFlds))
+		for _, fld := range keyFlds {
+			fldType := dirSch.Type(fld)
+			switch fldType {
+			case domain.Int32FieldType:
+				minVals = append(minVals, domain.NewConstant(fldType, int32(math.MinInt32)))
+			case domain.Int16FieldType:
+				minVals = append(minVals, domain.NewConstant(fldType, int16(math.MinInt16)))
+			case domain.Int64FieldType:
+				minVals = append(minVals, domain.NewConstant(fldType, int64(math.MinInt64)))
+			case domain.BoolFieldType:
+				minVals = append(minVals, domain.NewConstant(fldType, false))
+			case domain.Float32FieldType:
+				minVals = append(minVals, domain.NewConstant(fldType, float32(math.Inf(-1))))
+			case domain.Float64FieldType:
+				minVals = append(minVals, domain.NewConstant(fldType, math.Inf(-1)))
+			case domain.DateFieldType:
+				minVals = append(minVals, domain.NewConstantFromBits(fldType, math.MinInt32))
+			case domain.TimestampFieldType, domain.TimestampTzFieldType:
+				minVals = append(minVals, domain.NewConstantFromBits(fldType, math.MinInt64))
+			case domain.StringFieldType:
+				minVals = append(minVals, domain.NewConstant(fldType, ""))
+			case domain.NumericFieldType:
+				minVals = append(minVals, domain.NewDecimalConstant(domain.MinDecimal))
+			case domain.UnknownFieldType, domain.TupleFieldType, domain.TextFieldType, domain.BytesFieldType,
+				domain.IntervalFieldType:
+				panic(fmt.Errorf("not supported FieldType %v", fldType))
+			default:
+				panic(fmt.Errorf("not supported FieldType %v", fldType))
+			}
 		}
+		minVal := domain.NewIndexKey(minVals)
 		initSlotID := domain.NewSlotID(0)
 		initBlkNum, err := domain.NewBlockNumber(0)
 		if err != nil {
@@ -111,7 +136,12 @@ func NewIndex(txn domain.Transaction, idxName domain.IndexName, leafLayout *doma
 // BeforeFirst moves current slot where that is before the lowerbound of given search key.
 // search key を lowerbound とする index の1つ手前の index を返す。
 func (idx *Index) BeforeFirst(searchKey domain.Constant) error {
+	if idx.openErr != nil {
+		return idx.openErr
+	}
+
 	idx.Close()
+	idx.cursor = nil
 	root, err := NewDirNode(idx.txn, idx.rootBlk, idx.dirLayout)
 	if err != nil {
 		return errors.Err(err, "NewDirNode")
@@ -137,6 +167,14 @@ func (idx *Index) BeforeFirst(searchKey domain.Constant) error {
 
 // HasNext checks the existence of given search key.
 func (idx *Index) HasNext() bool {
+	if idx.cursor != nil {
+		return idx.hasNextInRange()
+	}
+
+	if idx.leaf == nil {
+		return false
+	}
+
 	found := idx.leaf.hasNext()
 	if idx.leaf.Err() != nil {
 		idx.err = idx.leaf.Err()
@@ -147,6 +185,10 @@ func (idx *Index) HasNext() bool {
 
 // GetDataRecordID gets record id from current slot.
 func (idx *Index) GetDataRecordID() (domain.RecordID, error) {
+	if idx.cursor != nil {
+		return idx.cursor.entries[idx.cursor.pos].rid, nil
+	}
+
 	return idx.leaf.getDataRecordID()
 }
 
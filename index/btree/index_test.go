@@ -1,6 +1,7 @@
 package btree_test
 
 import (
+	"sort"
 	"testing"
 
 	"github.com/goropikari/simpledbgo/domain"
@@ -318,3 +319,122 @@ func TestIndex_SplitLeaf4(t *testing.T) {
 	err = txn.Commit()
 	require.NoError(t, err)
 }
+
+func TestIndex_BeforeRange(t *testing.T) {
+	var err error
+
+	blockSize := int32(400)
+	numBuf := 100
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+	txn := cr.NewTxn()
+
+	sch := domain.NewSchema()
+	sch.AddInt32Field("id")
+	sch.AddInt32Field("block")
+	sch.AddInt32Field("dataval")
+	layout := domain.NewLayout(sch)
+	idx, err := btree.NewIndex(txn, idxName, layout)
+	require.NoError(t, err)
+
+	// 各 key を 2 回ずつ挿入し, key 250 は overflow block ができるほど挿入する.
+	keys := make([]int32, 0)
+	for i := 0; i < 1000; i++ {
+		keys = append(keys, int32(i*37%500))
+	}
+	for i := 0; i < 60; i++ {
+		keys = append(keys, 250)
+	}
+	for i, key := range keys {
+		rid := domain.NewRecordID(domain.BlockNumber(key), domain.SlotID(int32(i)))
+		err = idx.Insert(domain.NewConstant(domain.Int32FieldType, key), rid)
+		require.NoError(t, err)
+	}
+
+	intVal := func(v int32) domain.Constant {
+		return domain.NewConstant(domain.Int32FieldType, v)
+	}
+
+	tests := []struct {
+		name     string
+		r        domain.IndexRange
+		contains func(int32) bool
+	}{
+		{
+			name:     "all",
+			r:        domain.NewIndexRange(),
+			contains: func(k int32) bool { return true },
+		},
+		{
+			name:     "closed open",
+			r:        domain.NewIndexRange().WithLower(intVal(100), true).WithUpper(intVal(200), false),
+			contains: func(k int32) bool { return 100 <= k && k < 200 },
+		},
+		{
+			name:     "open closed",
+			r:        domain.NewIndexRange().WithLower(intVal(100), false).WithUpper(intVal(200), true),
+			contains: func(k int32) bool { return 100 < k && k <= 200 },
+		},
+		{
+			name:     "lower only",
+			r:        domain.NewIndexRange().WithLower(intVal(480), false),
+			contains: func(k int32) bool { return 480 < k },
+		},
+		{
+			name:     "upper only",
+			r:        domain.NewIndexRange().WithUpper(intVal(3), true),
+			contains: func(k int32) bool { return k <= 3 },
+		},
+		{
+			name:     "overflow key",
+			r:        domain.NewIndexRange().WithLower(intVal(249), true).WithUpper(intVal(251), true),
+			contains: func(k int32) bool { return 249 <= k && k <= 251 },
+		},
+		{
+			name:     "descending",
+			r:        domain.NewIndexRange().WithLower(intVal(240), true).WithUpper(intVal(260), false).WithOrder(domain.Desc),
+			contains: func(k int32) bool { return 240 <= k && k < 260 },
+		},
+		{
+			name:     "empty",
+			r:        domain.NewIndexRange().WithLower(intVal(10), false).WithUpper(intVal(10), false),
+			contains: func(k int32) bool { return false },
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			expected := make([]int32, 0)
+			for _, key := range keys {
+				if tt.contains(key) {
+					expected = append(expected, key)
+				}
+			}
+
+			err := idx.BeforeRange(tt.r)
+			require.NoError(t, err)
+
+			actual := make([]int32, 0)
+			for idx.HasNext() {
+				rid, err := idx.GetDataRecordID()
+				require.NoError(t, err)
+				actual = append(actual, int32(rid.BlockNumber()))
+			}
+			require.NoError(t, idx.Err())
+
+			require.ElementsMatch(t, expected, actual)
+			require.True(t, sort.SliceIsSorted(actual, func(i, j int) bool {
+				if tt.r.Order() == domain.Desc {
+					return actual[i] > actual[j]
+				}
+
+				return actual[i] < actual[j]
+			}))
+		})
+	}
+
+	idx.Close()
+	err = txn.Commit()
+	require.NoError(t, err)
+}
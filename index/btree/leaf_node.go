@@ -42,8 +42,8 @@ func (page *LeafPage) insertLeaf(slotID domain.SlotID, val domain.Constant, rid
 		return errors.Err(err, "insert")
 	}
 
-	if err := page.setVal(slotID, domain.FldDataVal, val); err != nil {
-		return errors.Err(err, "setVal")
+	if err := page.setDataVal(slotID, val); err != nil {
+		return errors.Err(err, "setDataVal")
 	}
 	if err := page.setInt32(slotID, domain.FldBlock, rid.BlockNumber().ToInt32()); err != nil {
 		return errors.Err(err, "setInt32")
@@ -31,6 +31,7 @@ func newPageFlag(flag int32) pageFlag {
 // ------------------------------------------------------------------------------
 // 本では Slot といっているが domain.RecordPage と違って usage flag がない素の record を使っている
 // 使わない record はそもそもアクセスしない想定になっているから usage flag をもたせていないのだと思われる.
+// NULL の key を保存できるように null bitmap は domain.RecordPage と同じ位置に置いている.
 type Page struct {
 	txn     domain.Transaction
 	currBlk domain.Block
@@ -113,8 +114,31 @@ func (page *Page) split(splitPos domain.SlotID, flag pageFlag) (domain.Block, er
 	return newBlk, nil
 }
 
+// getDataVal returns the key of the record.
+// composite index の場合は key の field を並べた tuple を返す.
 func (page *Page) getDataVal(slotID domain.SlotID) (domain.Constant, error) {
-	return page.getVal(slotID, domain.FldDataVal)
+	flds := domain.IndexKeyFields(page.layout.Schema())
+	vals := make([]domain.Constant, 0, len(flds))
+	for _, fld := range flds {
+		val, err := page.getVal(slotID, fld)
+		if err != nil {
+			return domain.Constant{}, errors.Err(err, "getVal")
+		}
+		vals = append(vals, val)
+	}
+
+	return domain.NewIndexKey(vals), nil
+}
+
+func (page *Page) setDataVal(slotID domain.SlotID, val domain.Constant) error {
+	vals := val.Components()
+	for i, fld := range domain.IndexKeyFields(page.layout.Schema()) {
+		if err := page.setVal(slotID, fld, vals[i]); err != nil {
+			return errors.Err(err, "setVal")
+		}
+	}
+
+	return nil
 }
 
 func (page *Page) getFlag() (pageFlag, error) {
@@ -143,6 +167,8 @@ func (page *Page) appendNew(flag pageFlag) (domain.Block, error) {
 	if err := page.txn.Pin(blk); err != nil {
 		return domain.Block{}, errors.Err(err, "Pin")
 	}
+	// 呼び出し側は block を開き直すので format 後は unpin する.
+	defer page.txn.Unpin(blk)
 
 	if err := page.format(blk, flag); err != nil {
 		return domain.Block{}, errors.Err(err, "format")
@@ -174,17 +200,27 @@ func (page *Page) format(blk domain.Block, flag pageFlag) error {
 
 // これ Page method に入れるべき処理なのか？
 func (page *Page) makeDefaultRecord(blk domain.Block, pos int64) error {
+	bitmapLen := page.layout.NullBitmapLength()
+	for off := int64(domain.RecordOffset); off < domain.RecordOffset+bitmapLen; off += common.Int32Length {
+		if err := page.txn.SetInt32(blk, pos+off, 0, false); err != nil {
+			return errors.Err(err, "SetInt32")
+		}
+	}
+
 	for _, fldName := range page.layout.Schema().Fields() {
 		offset := page.layout.Offset(fldName)
 
 		var err error
-		switch page.layout.Schema().Type(fldName) {
-		case domain.Int32FieldType:
+		switch typ := page.layout.Schema().Type(fldName); {
+		case typ.IsInt64Sized():
+			err = page.txn.SetInt64(blk, pos+offset, 0, false)
+		case typ == domain.Int32FieldType || typ == domain.Int16FieldType || typ == domain.BoolFieldType || typ == domain.Float32FieldType ||
+			typ == domain.DateFieldType:
 			err = page.txn.SetInt32(blk, pos+offset, 0, false)
-		case domain.StringFieldType:
+		case typ == domain.StringFieldType:
 			err = page.txn.SetString(blk, pos+offset, "", false)
-		case domain.UnknownFieldType:
-			panic(errors.New("unsupported field type"))
+		case typ == domain.NumericFieldType:
+			err = page.setDecimal(blk, pos+offset, domain.Decimal{}, false)
 		default:
 			panic(errors.New("unsupported field type"))
 		}
@@ -323,6 +359,14 @@ func (page *Page) getString(slotID domain.SlotID, fldName domain.FieldName) (str
 }
 
 func (page *Page) getVal(slotID domain.SlotID, fldName domain.FieldName) (domain.Constant, error) {
+	isNull, err := page.isNull(slotID, fldName)
+	if err != nil {
+		return domain.Constant{}, errors.Err(err, "isNull")
+	}
+	if isNull {
+		return domain.NewNullConstant(), nil
+	}
+
 	typ := page.layout.Schema().Type(fldName)
 	switch typ {
 	case domain.Int32FieldType:
@@ -332,6 +376,20 @@ func (page *Page) getVal(slotID domain.SlotID, fldName domain.FieldName) (domain
 		}
 
 		return domain.NewConstant(typ, num), nil
+	case domain.Int16FieldType, domain.BoolFieldType, domain.Float32FieldType, domain.DateFieldType:
+		bits, err := page.getInt32(slotID, fldName)
+		if err != nil {
+			return domain.Constant{}, errors.Err(err, "getInt32")
+		}
+
+		return domain.NewConstantFromBits(typ, int64(bits)), nil
+	case domain.Int64FieldType, domain.Float64FieldType, domain.TimestampFieldType, domain.TimestampTzFieldType:
+		bits, err := page.txn.GetInt64(page.currBlk, page.fldPos(slotID, fldName))
+		if err != nil {
+			return domain.Constant{}, errors.Err(err, "GetInt64")
+		}
+
+		return domain.NewConstantFromBits(typ, bits), nil
 	case domain.StringFieldType:
 		str, err := page.getString(slotID, fldName)
 		if err != nil {
@@ -339,6 +397,13 @@ func (page *Page) getVal(slotID domain.SlotID, fldName domain.FieldName) (domain
 		}
 
 		return domain.NewConstant(typ, str), nil
+	case domain.NumericFieldType:
+		d, err := page.getDecimal(page.currBlk, page.fldPos(slotID, fldName))
+		if err != nil {
+			return domain.Constant{}, errors.Err(err, "getDecimal")
+		}
+
+		return domain.NewDecimalConstant(d), nil
 	case domain.UnknownFieldType:
 		return domain.Constant{}, fmt.Errorf("unsupported field type %v", typ)
 	default:
@@ -358,7 +423,51 @@ func (page *Page) setString(slotID domain.SlotID, fldName domain.FieldName, val
 	return page.txn.SetString(page.currBlk, pos, val, true)
 }
 
+// getDecimal reads the decimal at pos in the same layout as domain.RecordPage.
+func (page *Page) getDecimal(blk domain.Block, pos int64) (domain.Decimal, error) {
+	hi, err := page.txn.GetInt64(blk, pos)
+	if err != nil {
+		return domain.Decimal{}, errors.Err(err, "GetInt64")
+	}
+
+	lo, err := page.txn.GetInt64(blk, pos+common.Int64Length)
+	if err != nil {
+		return domain.Decimal{}, errors.Err(err, "GetInt64")
+	}
+
+	scale, err := page.txn.GetInt32(blk, pos+2*common.Int64Length)
+	if err != nil {
+		return domain.Decimal{}, errors.Err(err, "GetInt32")
+	}
+
+	return domain.Decimal{Hi: hi, Lo: uint64(lo), Scale: scale}, nil
+}
+
+// setDecimal writes the decimal at pos in the same layout as domain.RecordPage.
+func (page *Page) setDecimal(blk domain.Block, pos int64, val domain.Decimal, writeLog bool) error {
+	if err := page.txn.SetInt64(blk, pos, val.Hi, writeLog); err != nil {
+		return errors.Err(err, "SetInt64")
+	}
+
+	if err := page.txn.SetInt64(blk, pos+common.Int64Length, int64(val.Lo), writeLog); err != nil {
+		return errors.Err(err, "SetInt64")
+	}
+
+	if err := page.txn.SetInt32(blk, pos+2*common.Int64Length, val.Scale, writeLog); err != nil {
+		return errors.Err(err, "SetInt32")
+	}
+
+	return nil
+}
+
 func (page *Page) setVal(slotID domain.SlotID, fldName domain.FieldName, val domain.Constant) error {
+	if err := page.setNullFlag(slotID, fldName, val.IsNull()); err != nil {
+		return errors.Err(err, "setNullFlag")
+	}
+	if val.IsNull() {
+		return nil
+	}
+
 	typ := page.layout.Schema().Type(fldName)
 	switch typ {
 	case domain.Int32FieldType:
@@ -368,6 +477,20 @@ func (page *Page) setVal(slotID domain.SlotID, fldName domain.FieldName, val dom
 		}
 
 		return page.setInt32(slotID, fldName, v)
+	case domain.Int16FieldType, domain.BoolFieldType, domain.Float32FieldType, domain.DateFieldType:
+		bits, err := val.Bits()
+		if err != nil {
+			return errors.Err(err, "Bits")
+		}
+
+		return page.setInt32(slotID, fldName, int32(bits))
+	case domain.Int64FieldType, domain.Float64FieldType, domain.TimestampFieldType, domain.TimestampTzFieldType:
+		bits, err := val.Bits()
+		if err != nil {
+			return errors.Err(err, "Bits")
+		}
+
+		return page.txn.SetInt64(page.currBlk, page.fldPos(slotID, fldName), bits, true)
 	case domain.StringFieldType:
 		v, err := val.AsString()
 		if err != nil {
@@ -375,6 +498,13 @@ func (page *Page) setVal(slotID domain.SlotID, fldName domain.FieldName, val dom
 		}
 
 		return page.setString(slotID, fldName, v)
+	case domain.NumericFieldType:
+		v, err := val.AsDecimal()
+		if err != nil {
+			return errors.Err(err, "AsDecimal")
+		}
+
+		return page.setDecimal(page.currBlk, page.fldPos(slotID, fldName), v, true)
 	case domain.UnknownFieldType:
 		return fmt.Errorf("unsupported field type %v", typ)
 	default:
@@ -382,6 +512,37 @@ func (page *Page) setVal(slotID domain.SlotID, fldName domain.FieldName, val dom
 	}
 }
 
+// isNull checks whether the value of the field is NULL or not.
+// record の null bitmap は domain.RecordPage と同じ位置にある.
+func (page *Page) isNull(slotID domain.SlotID, fldName domain.FieldName) (bool, error) {
+	offset, mask := page.layout.NullFlagPosition(fldName)
+	word, err := page.txn.GetInt32(page.currBlk, page.slotPos(slotID)+offset)
+	if err != nil {
+		return false, errors.Err(err, "GetInt32")
+	}
+
+	return word&mask != 0, nil
+}
+
+func (page *Page) setNullFlag(slotID domain.SlotID, fldName domain.FieldName, isNull bool) error {
+	offset, mask := page.layout.NullFlagPosition(fldName)
+	pos := page.slotPos(slotID) + offset
+	word, err := page.txn.GetInt32(page.currBlk, pos)
+	if err != nil {
+		return errors.Err(err, "GetInt32")
+	}
+
+	newWord := word &^ mask
+	if isNull {
+		newWord = word | mask
+	}
+	if newWord == word {
+		return nil
+	}
+
+	return page.txn.SetInt32(page.currBlk, pos, newWord, true)
+}
+
 func (page *Page) setLastSlotID(n domain.SlotID) error {
 	return page.txn.SetInt32(page.currBlk, numRecordOffset, int32(n+1), true)
 }
@@ -0,0 +1,222 @@
+package btree
+
+import (
+	"sort"
+
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// leafEntry is an index record of leaf node.
+type leafEntry struct {
+	dataval domain.Constant
+	rid     domain.RecordID
+}
+
+// rangeCursor iterates index records whose key is in a range.
+// leaf node 同士は繋がっていないので, directory から範囲に含まれうる leaf block を key の順に列挙しておき,
+// leaf block ごとに index record を読み込む.
+type rangeCursor struct {
+	r       domain.IndexRange
+	leaves  []domain.BlockNumber
+	entries []leafEntry
+	pos     int
+}
+
+// BeforeRange moves before the first index record in given range.
+func (idx *Index) BeforeRange(r domain.IndexRange) error {
+	if idx.openErr != nil {
+		return idx.openErr
+	}
+
+	idx.Close()
+	idx.cursor = nil
+
+	leaves, err := idx.collectLeaves(idx.rootBlk, r, domain.Constant{}, false)
+	if err != nil {
+		return errors.Err(err, "collectLeaves")
+	}
+
+	if r.Order() == domain.Desc {
+		for i, j := 0, len(leaves)-1; i < j; i, j = i+1, j-1 {
+			leaves[i], leaves[j] = leaves[j], leaves[i]
+		}
+	}
+
+	idx.cursor = &rangeCursor{
+		r:      r,
+		leaves: leaves,
+		pos:    -1,
+	}
+
+	return nil
+}
+
+// collectLeaves returns leaf block numbers which may contain keys in r in ascending order.
+// dir entry i が指す子は [entry i の key, entry i+1 の key) の key を持つ.
+// 最後の entry の上限は親から引き継ぐ.
+func (idx *Index) collectLeaves(blk domain.Block, r domain.IndexRange, upper domain.Constant, hasUpper bool) ([]domain.BlockNumber, error) {
+	page, err := NewDirPage(idx.txn, blk, idx.dirLayout)
+	if err != nil {
+		return nil, errors.Err(err, "NewDirPage")
+	}
+
+	level, err := page.getLevel()
+	if err != nil {
+		page.close()
+
+		return nil, errors.Err(err, "getLevel")
+	}
+
+	ents, err := readDirEntries(page)
+	page.close()
+	if err != nil {
+		return nil, errors.Err(err, "readDirEntries")
+	}
+
+	leaves := make([]domain.BlockNumber, 0)
+	for i, ent := range ents {
+		if !r.BelowUpper(ent.getDataVal()) {
+			break
+		}
+
+		nextVal, hasNext := upper, hasUpper
+		if i+1 < len(ents) {
+			nextVal, hasNext = ents[i+1].getDataVal(), true
+		}
+		if hasNext && r.BeforeLower(nextVal) {
+			continue
+		}
+
+		if level == zeroLevelDirNodeFlag {
+			leaves = append(leaves, ent.getBlockNumber())
+
+			continue
+		}
+
+		childBlk := domain.NewBlock(blk.FileName(), ent.getBlockNumber())
+		children, err := idx.collectLeaves(childBlk, r, nextVal, hasNext)
+		if err != nil {
+			return nil, errors.Err(err, "collectLeaves")
+		}
+		leaves = append(leaves, children...)
+	}
+
+	return leaves, nil
+}
+
+func readDirEntries(page *DirPage) ([]DirEntry, error) {
+	lastID, err := page.getLastSlotID()
+	if err != nil {
+		return nil, errors.Err(err, "getLastSlotID")
+	}
+
+	ents := make([]DirEntry, 0, lastID+1)
+	for slotID := domain.NewSlotID(0); slotID <= lastID; slotID++ {
+		val, err := page.getDataVal(slotID)
+		if err != nil {
+			return nil, errors.Err(err, "getDataVal")
+		}
+		blkNum, err := page.getChildBlockNumber(slotID)
+		if err != nil {
+			return nil, errors.Err(err, "getChildBlockNumber")
+		}
+		ents = append(ents, NewDirEntry(val, blkNum))
+	}
+
+	return ents, nil
+}
+
+// readLeaf reads index records in r from the leaf block and its overflow blocks.
+// overflow block には leaf block の先頭と同じ key の record だけが入っているので, 読み込んだ後に key で並べ直す.
+func (idx *Index) readLeaf(blkNum domain.BlockNumber, r domain.IndexRange) ([]leafEntry, error) {
+	entries := make([]leafEntry, 0)
+	blk := domain.NewBlock(idx.leafTblName, blkNum)
+	for {
+		page, err := NewLeafPage(idx.txn, blk, idx.leafLayout)
+		if err != nil {
+			return nil, errors.Err(err, "NewLeafPage")
+		}
+
+		ents, next, err := readLeafEntries(page)
+		page.close()
+		if err != nil {
+			return nil, errors.Err(err, "readLeafEntries")
+		}
+		entries = append(entries, ents...)
+
+		if next == noOverflowLeafNode {
+			break
+		}
+		blk = domain.NewBlock(idx.leafTblName, domain.BlockNumber(next))
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].dataval.Less(entries[j].dataval)
+	})
+
+	inRange := make([]leafEntry, 0, len(entries))
+	for _, ent := range entries {
+		if r.Contains(ent.dataval) {
+			inRange = append(inRange, ent)
+		}
+	}
+
+	if r.Order() == domain.Desc {
+		for i, j := 0, len(inRange)-1; i < j; i, j = i+1, j-1 {
+			inRange[i], inRange[j] = inRange[j], inRange[i]
+		}
+	}
+
+	return inRange, nil
+}
+
+func readLeafEntries(page *LeafPage) ([]leafEntry, pageFlag, error) {
+	lastID, err := page.getLastSlotID()
+	if err != nil {
+		return nil, 0, errors.Err(err, "getLastSlotID")
+	}
+
+	ents := make([]leafEntry, 0, lastID+1)
+	for slotID := domain.NewSlotID(0); slotID <= lastID; slotID++ {
+		val, err := page.getDataVal(slotID)
+		if err != nil {
+			return nil, 0, errors.Err(err, "getDataVal")
+		}
+		rid, err := page.getDataRecordID(slotID)
+		if err != nil {
+			return nil, 0, errors.Err(err, "getDataRecordID")
+		}
+		ents = append(ents, leafEntry{dataval: val, rid: rid})
+	}
+
+	next, err := page.getFlag()
+	if err != nil {
+		return nil, 0, errors.Err(err, "getFlag")
+	}
+
+	return ents, next, nil
+}
+
+// hasNextInRange moves to the next index record in the range.
+func (idx *Index) hasNextInRange() bool {
+	c := idx.cursor
+	c.pos++
+	for c.pos >= len(c.entries) {
+		if len(c.leaves) == 0 {
+			return false
+		}
+
+		entries, err := idx.readLeaf(c.leaves[0], c.r)
+		if err != nil {
+			idx.err = errors.Err(err, "readLeaf")
+
+			return false
+		}
+		c.leaves = c.leaves[1:]
+		c.entries = entries
+		c.pos = 0
+	}
+
+	return true
+}
@@ -13,5 +13,10 @@ func NewSearchCostCalculator() *SearchCostCalculator {
 // Calculate calculates search cost.
 // rpb: record per block
 func (cal *SearchCostCalculator) Calculate(numBlocks, rpb int) int {
+	// leaf が 1 block 以下のときは log が定義できないので root から 1 段とみなす.
+	if numBlocks <= 1 || rpb <= 1 {
+		return 1
+	}
+
 	return 1 + int(math.Round(math.Log(float64(numBlocks))/math.Log(float64(rpb))))
 }
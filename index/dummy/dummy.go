@@ -15,6 +15,14 @@ func (fty *IndexFactory) Create(txn domain.Transaction, idxName domain.IndexName
 	return &Index{}
 }
 
+func (fty *IndexFactory) SupportsRange() bool {
+	return false
+}
+
+func (fty *IndexFactory) FileNames(idxName domain.IndexName) []domain.FileName {
+	return nil
+}
+
 type SearchCostCalculator struct{}
 
 func NewSearchCostCalculator() *SearchCostCalculator {
@@ -31,6 +39,10 @@ func (idx *Index) BeforeFirst(searchKey domain.Constant) error {
 	return errors.ErrNotImplemented
 }
 
+func (idx *Index) BeforeRange(r domain.IndexRange) error {
+	return errors.ErrNotImplemented
+}
+
 func (idx *Index) HasNext() bool {
 	return false
 }
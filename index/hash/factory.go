@@ -1,6 +1,10 @@
 package hash
 
-import "github.com/goropikari/simpledbgo/domain"
+import (
+	"fmt"
+
+	"github.com/goropikari/simpledbgo/domain"
+)
 
 // IndexFactory is generator of index.
 type IndexFactory struct{}
@@ -15,6 +19,23 @@ func (gen *IndexFactory) Create(txn domain.Transaction, idxName domain.IndexName
 	return NewIndex(txn, idxName, layout)
 }
 
+// SupportsRange checks whether the index supports range scan.
+// hash index は key の順序を保持しないので range scan はできない.
+func (gen *IndexFactory) SupportsRange() bool {
+	return false
+}
+
+// FileNames returns the bucket files of the index.
+// bucket の file は使われたときに作られるので, 存在しないものも含む.
+func (gen *IndexFactory) FileNames(idxName domain.IndexName) []domain.FileName {
+	names := make([]domain.FileName, 0, numBuckets)
+	for bucket := 0; bucket < numBuckets; bucket++ {
+		names = append(names, domain.FileName(fmt.Sprintf("%v%v", idxName, bucket)))
+	}
+
+	return names
+}
+
 // SearchCostCalculator calculates search cost.
 type SearchCostCalculator struct{}
 
@@ -8,7 +8,6 @@ import (
 
 const (
 	numBuckets = 100
-	fldDataVal = "dataval"
 	fldBlock   = "block"
 	fldID      = "id"
 )
@@ -61,10 +60,15 @@ func (idx *Index) BeforeFirst(searchKey domain.Constant) error {
 	return nil
 }
 
+// BeforeRange returns an error because hash index doesn't keep the order of keys.
+func (idx *Index) BeforeRange(r domain.IndexRange) error {
+	return domain.ErrRangeScanNotSupported
+}
+
 // HasNext checks whether tbl has a record having the searchKey.
 func (idx *Index) HasNext() bool {
 	for idx.tbl.HasNext() {
-		v, err := idx.tbl.GetVal(fldDataVal)
+		v, err := idx.getDataVal()
 		if err != nil {
 			idx.err = err
 
@@ -122,13 +126,32 @@ func (idx *Index) Insert(searchKey domain.Constant, rid domain.RecordID) error {
 	if err := idx.tbl.SetInt32(fldID, int32(rid.SlotID())); err != nil {
 		return err
 	}
-	if err := idx.tbl.SetVal(fldDataVal, searchKey); err != nil {
-		return err
+	vals := searchKey.Components()
+	for i, fld := range domain.IndexKeyFields(idx.layout.Schema()) {
+		if err := idx.tbl.SetVal(fld, vals[i]); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// getDataVal reads the key of the current index record.
+// composite index の場合は key の field を並べた tuple を返す.
+func (idx *Index) getDataVal() (domain.Constant, error) {
+	flds := domain.IndexKeyFields(idx.layout.Schema())
+	vals := make([]domain.Constant, 0, len(flds))
+	for _, fld := range flds {
+		v, err := idx.tbl.GetVal(fld)
+		if err != nil {
+			return domain.Constant{}, err
+		}
+		vals = append(vals, v)
+	}
+
+	return domain.NewIndexKey(vals), nil
+}
+
 // Delete given record from index file.
 func (idx *Index) Delete(searchKey domain.Constant, rid domain.RecordID) error {
 	if err := idx.BeforeFirst(searchKey); err != nil {
@@ -9,15 +9,44 @@ import (
 )
 
 var keywords = []string{
-	"select", "from", "where", "and",
+	"select", "from", "where", "and", "or", "not",
 	"insert", "into", "values", "delete", "update", "set",
-	"create", "table", "int", "varchar", "view", "as", "index", "on",
+	"create", "table", "int", "varchar", "view", "as", "index", "on", "using",
+	"order", "by", "asc", "desc", "group",
+	"join", "inner", "left", "right", "outer",
+	"primary", "key", "unique", "null", "is", "default", "check",
+	"foreign", "references", "restrict", "cascade",
+	"drop", "if", "exists",
+	"alter", "add", "column", "rename", "to",
+	"text", "bytea",
+	"smallint", "bigint", "boolean", "real", "double", "precision", "true", "false",
+	"date", "timestamp", "timestamptz", "with", "time", "zone", "interval",
+	"now", "current_date", "current_timestamp",
+	"numeric", "decimal",
+}
+
+// operandKeywords are keywords which can be operands of binary operators.
+var operandKeywords = map[string]bool{
+	"null": true, "true": true, "false": true, "current_date": true, "current_timestamp": true,
+}
+
+// nonReservedKeywords are keywords which can also be used as identifiers.
+// 型名や特定の構文の中でしか使わない keyword は table 名や field 名にも使える.
+var nonReservedKeywords = map[string]bool{
+	"int": true, "varchar": true, "text": true, "bytea": true,
+	"smallint": true, "bigint": true, "boolean": true, "real": true, "double": true, "precision": true,
+	"date": true, "timestamp": true, "timestamptz": true, "time": true, "zone": true, "interval": true,
+	"numeric": true, "decimal": true, "now": true,
+	"key": true, "restrict": true, "cascade": true,
+	"add": true, "column": true, "rename": true, "to": true,
+	"left": true, "right": true,
 }
 
 // Lexer is a model of lexer.
 type Lexer struct {
 	reader   *strings.Reader
 	keywords map[string]bool
+	prev     Token
 }
 
 // NewLexer constructs a lexer.
@@ -47,6 +76,7 @@ func (lex *Lexer) ScanTokens() ([]Token, error) {
 			return nil, errors.Err(err, "scan")
 		}
 		tokens = append(tokens, token)
+		lex.prev = token
 	}
 
 	return tokens, nil
@@ -74,6 +104,44 @@ func (lex *Lexer) scan() (Token, error) {
 		return NewToken(TLParen, "("), nil
 	case ')':
 		return NewToken(TRParen, ")"), nil
+	case '<':
+		switch {
+		case lex.next('='):
+			return NewToken(TLessEqual, "<="), nil
+		case lex.next('>'):
+			return NewToken(TNotEqual, "<>"), nil
+		default:
+			return NewToken(TLess, "<"), nil
+		}
+	case '>':
+		if lex.next('=') {
+			return NewToken(TGreaterEqual, ">="), nil
+		}
+
+		return NewToken(TGreater, ">"), nil
+	case '!':
+		if lex.next('=') {
+			return NewToken(TNotEqual, "<>"), nil
+		}
+
+		return Token{}, errors.New("error at scan")
+	case '|':
+		if lex.next('|') {
+			return NewToken(TConcat, "||"), nil
+		}
+
+		return Token{}, errors.New("error at scan")
+	case '+':
+		return NewToken(TPlus, "+"), nil
+	case '/':
+		return NewToken(TSlash, "/"), nil
+	case '%':
+		return NewToken(TPercent, "%"), nil
+	case '-':
+		// 値の直後の - は減算、それ以外で数字が続く場合は負の数とみなす.
+		if lex.afterOperand() || !lex.followedByNumber() {
+			return NewToken(TMinus, "-"), nil
+		}
 	}
 
 	err = lex.unreadByte()
@@ -83,19 +151,33 @@ func (lex *Lexer) scan() (Token, error) {
 
 	switch {
 	case isNumber(c) || c == '-':
-		numStr, err := lex.scanInteger()
+		numStr, err := lex.scanNumber()
 		if err != nil {
-			return Token{}, errors.Err(err, "scanInteger")
+			return Token{}, errors.Err(err, "scanNumber")
+		}
+
+		if strings.ContainsAny(numStr, ".eE") {
+			num, err := strconv.ParseFloat(numStr, 64)
+			if err != nil {
+				return Token{}, errors.Err(err, "ParseFloat")
+			}
+
+			return NewToken(TFloat64, num), nil
 		}
 
 		base := 10
-		precision := 32
+		precision := 64
 		num, err := strconv.ParseInt(numStr, base, precision)
 		if err != nil {
 			return Token{}, errors.Err(err, "ParseInt")
 		}
 
-		return NewToken(TInt32, int32(num)), nil
+		// int32 に収まる整数は int32 とする.
+		if int64(int32(num)) == num {
+			return NewToken(TInt32, int32(num)), nil
+		}
+
+		return NewToken(TInt64, num), nil
 	case isAlpha(c):
 		id, err := lex.scanIdentifier()
 		if err != nil {
@@ -119,7 +201,8 @@ func (lex *Lexer) scan() (Token, error) {
 	return Token{}, errors.New("error at scan")
 }
 
-func (lex *Lexer) scanInteger() (string, error) {
+// scanNumber scans integer or decimal number such as 12, -3.5 and 1e+06.
+func (lex *Lexer) scanNumber() (string, error) {
 	b := make([]byte, 0)
 	c, _ := lex.readByte()
 	if c == '-' {
@@ -131,32 +214,57 @@ func (lex *Lexer) scanInteger() (string, error) {
 		}
 	}
 
-	for {
-		c, err := lex.readByte()
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				break
-			}
-
-			return "", errors.Err(err, "readByte")
+	b = append(b, lex.scanDigits()...)
+	if lex.next('.') {
+		b = append(b, '.')
+		b = append(b, lex.scanDigits()...)
+	}
+	if lex.next('e') || lex.next('E') {
+		b = append(b, 'e')
+		switch {
+		case lex.next('+'):
+			b = append(b, '+')
+		case lex.next('-'):
+			b = append(b, '-')
 		}
+		digits := lex.scanDigits()
+		if len(digits) == 0 {
+			return "", errors.New("not number")
+		}
+		b = append(b, digits...)
+	}
 
+	c, err := lex.readByte()
+	if err == nil {
 		if isAlpha(c) {
 			return "", errors.New("not number")
 		}
-		if !isNumber(c) {
-			err := lex.unreadByte()
-			if err != nil {
-				return "", errors.Wrap(err, "not number")
-			}
+		if err := lex.unreadByte(); err != nil {
+			return "", errors.Wrap(err, "not number")
+		}
+	}
 
+	return string(b), nil
+}
+
+// scanDigits scans consecutive digits.
+func (lex *Lexer) scanDigits() []byte {
+	b := make([]byte, 0)
+	for {
+		c, err := lex.readByte()
+		if err != nil {
 			break
 		}
+		if !isNumber(c) {
+			// 直前に読んだ byte を戻すだけなので失敗しない.
+			_ = lex.unreadByte()
 
+			break
+		}
 		b = append(b, c)
 	}
 
-	return string(b), nil
+	return b
 }
 
 func (lex *Lexer) scanIdentifier() (string, error) {
@@ -247,6 +355,48 @@ func (lex *Lexer) skipWhitespace() error {
 	return nil
 }
 
+// afterOperand checks whether the previous token can be an operand of binary operator.
+func (lex *Lexer) afterOperand() bool {
+	switch lex.prev.Type() {
+	case TIdentifier, TInt32, TInt64, TFloat64, TString, TRParen:
+		return true
+	case TKeyword:
+		kw, _ := lex.prev.Value().(string)
+
+		return operandKeywords[kw] || nonReservedKeywords[kw]
+	default:
+		return false
+	}
+}
+
+// followedByNumber checks whether the next byte is a number without consuming it.
+func (lex *Lexer) followedByNumber() bool {
+	c, err := lex.readByte()
+	if err != nil {
+		return false
+	}
+	_ = lex.unreadByte()
+
+	return isNumber(c)
+}
+
+// next consumes the next byte if it equals c.
+func (lex *Lexer) next(c byte) bool {
+	b, err := lex.readByte()
+	if err != nil {
+		return false
+	}
+
+	if b != c {
+		// 直前に読んだ byte を戻すだけなので失敗しない.
+		_ = lex.unreadByte()
+
+		return false
+	}
+
+	return true
+}
+
 func (lex *Lexer) readByte() (byte, error) {
 	return lex.reader.ReadByte()
 }
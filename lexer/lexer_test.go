@@ -43,6 +43,122 @@ func TestLexer(t *testing.T) {
 				lexer.NewToken(lexer.TIdentifier, "foo"),
 			},
 		},
+		{
+			name:  "select query with order by",
+			query: "select a, b from foo order by a desc, b ASC",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "a"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "b"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "order"),
+				lexer.NewToken(lexer.TKeyword, "by"),
+				lexer.NewToken(lexer.TIdentifier, "a"),
+				lexer.NewToken(lexer.TKeyword, "desc"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "b"),
+				lexer.NewToken(lexer.TKeyword, "asc"),
+			},
+		},
+		{
+			name:  "select query with join",
+			query: "from foo left outer join bar on a = b inner join baz on c = d",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "left"),
+				lexer.NewToken(lexer.TKeyword, "outer"),
+				lexer.NewToken(lexer.TKeyword, "join"),
+				lexer.NewToken(lexer.TIdentifier, "bar"),
+				lexer.NewToken(lexer.TKeyword, "on"),
+				lexer.NewToken(lexer.TIdentifier, "a"),
+				lexer.NewToken(lexer.TEqual, "="),
+				lexer.NewToken(lexer.TIdentifier, "b"),
+				lexer.NewToken(lexer.TKeyword, "inner"),
+				lexer.NewToken(lexer.TKeyword, "join"),
+				lexer.NewToken(lexer.TIdentifier, "baz"),
+				lexer.NewToken(lexer.TKeyword, "on"),
+				lexer.NewToken(lexer.TIdentifier, "c"),
+				lexer.NewToken(lexer.TEqual, "="),
+				lexer.NewToken(lexer.TIdentifier, "d"),
+			},
+		},
+		{
+			name:  "comparison operators",
+			query: "where a<>1 or not (b<=2) and c>=-3 and d<4 and e>5 and f != 6",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "where"),
+				lexer.NewToken(lexer.TIdentifier, "a"),
+				lexer.NewToken(lexer.TNotEqual, "<>"),
+				lexer.NewToken(lexer.TInt32, int32(1)),
+				lexer.NewToken(lexer.TKeyword, "or"),
+				lexer.NewToken(lexer.TKeyword, "not"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "b"),
+				lexer.NewToken(lexer.TLessEqual, "<="),
+				lexer.NewToken(lexer.TInt32, int32(2)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "and"),
+				lexer.NewToken(lexer.TIdentifier, "c"),
+				lexer.NewToken(lexer.TGreaterEqual, ">="),
+				lexer.NewToken(lexer.TInt32, int32(-3)),
+				lexer.NewToken(lexer.TKeyword, "and"),
+				lexer.NewToken(lexer.TIdentifier, "d"),
+				lexer.NewToken(lexer.TLess, "<"),
+				lexer.NewToken(lexer.TInt32, int32(4)),
+				lexer.NewToken(lexer.TKeyword, "and"),
+				lexer.NewToken(lexer.TIdentifier, "e"),
+				lexer.NewToken(lexer.TGreater, ">"),
+				lexer.NewToken(lexer.TInt32, int32(5)),
+				lexer.NewToken(lexer.TKeyword, "and"),
+				lexer.NewToken(lexer.TIdentifier, "f"),
+				lexer.NewToken(lexer.TNotEqual, "<>"),
+				lexer.NewToken(lexer.TInt32, int32(6)),
+			},
+		},
+		{
+			name:  "arithmetic operators",
+			query: "select a+1, a-1, a - -2, -b, (a)-3, a*b/2%3, c || 'x' from t",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "a"),
+				lexer.NewToken(lexer.TPlus, "+"),
+				lexer.NewToken(lexer.TInt32, int32(1)),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "a"),
+				lexer.NewToken(lexer.TMinus, "-"),
+				lexer.NewToken(lexer.TInt32, int32(1)),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "a"),
+				lexer.NewToken(lexer.TMinus, "-"),
+				lexer.NewToken(lexer.TInt32, int32(-2)),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TMinus, "-"),
+				lexer.NewToken(lexer.TIdentifier, "b"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "a"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TMinus, "-"),
+				lexer.NewToken(lexer.TInt32, int32(3)),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "a"),
+				lexer.NewToken(lexer.TStar, "*"),
+				lexer.NewToken(lexer.TIdentifier, "b"),
+				lexer.NewToken(lexer.TSlash, "/"),
+				lexer.NewToken(lexer.TInt32, int32(2)),
+				lexer.NewToken(lexer.TPercent, "%"),
+				lexer.NewToken(lexer.TInt32, int32(3)),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "c"),
+				lexer.NewToken(lexer.TConcat, "||"),
+				lexer.NewToken(lexer.TString, "x"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "t"),
+			},
+		},
 		{
 			name:  "insert command",
 			query: "INSERT INTO foo (id,name, address) VALUES (-123, 'mike','tokyo')",
@@ -125,6 +241,75 @@ func TestLexer(t *testing.T) {
 				lexer.NewToken(lexer.TRParen, ")"),
 			},
 		},
+		{
+			name:  "numeric literals",
+			query: "2147483647, 2147483648, -9223372036854775808, 1.5, -0.25, 1e+06, 2E-3, 3., true",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TInt32, int32(2147483647)),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TInt64, int64(2147483648)),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TInt64, int64(-9223372036854775808)),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TFloat64, 1.5),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TFloat64, -0.25),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TFloat64, 1e+06),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TFloat64, 2e-3),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TFloat64, 3.0),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TKeyword, "true"),
+			},
+		},
+		{
+			name:  "date/time keywords",
+			query: "current_date -1 > DATE '2024-01-01' - interval '1 day' + now()",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "current_date"),
+				lexer.NewToken(lexer.TMinus, "-"),
+				lexer.NewToken(lexer.TInt32, int32(1)),
+				lexer.NewToken(lexer.TGreater, ">"),
+				lexer.NewToken(lexer.TKeyword, "date"),
+				lexer.NewToken(lexer.TString, "2024-01-01"),
+				lexer.NewToken(lexer.TMinus, "-"),
+				lexer.NewToken(lexer.TKeyword, "interval"),
+				lexer.NewToken(lexer.TString, "1 day"),
+				lexer.NewToken(lexer.TPlus, "+"),
+				lexer.NewToken(lexer.TKeyword, "now"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+		},
+		{
+			name:  "numeric keywords",
+			query: "numeric(10, 2) decimal NUMERIC '1.50'",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "numeric"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(10)),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TInt32, int32(2)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "decimal"),
+				lexer.NewToken(lexer.TKeyword, "numeric"),
+				lexer.NewToken(lexer.TString, "1.50"),
+			},
+		},
+		{
+			name:  "binary minus after non-reserved keyword",
+			query: "key -1 date-2",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "key"),
+				lexer.NewToken(lexer.TMinus, "-"),
+				lexer.NewToken(lexer.TInt32, int32(1)),
+				lexer.NewToken(lexer.TKeyword, "date"),
+				lexer.NewToken(lexer.TMinus, "-"),
+				lexer.NewToken(lexer.TInt32, int32(2)),
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
@@ -152,6 +337,22 @@ func TestLexer_Error(t *testing.T) {
 			name:  "invalid character",
 			query: "あ",
 		},
+		{
+			name:  "exclamation without equal",
+			query: "a ! b",
+		},
+		{
+			name:  "single vertical bar",
+			query: "a | b",
+		},
+		{
+			name:  "exponent without digits",
+			query: "1e+",
+		},
+		{
+			name:  "integer out of range",
+			query: "9223372036854775808",
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
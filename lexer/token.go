@@ -33,6 +33,43 @@ const (
 
 	// TRParen is right parentheses token type.
 	TRParen
+
+	// TNotEqual is not equal token type.
+	TNotEqual
+
+	// TLess is less than token type.
+	TLess
+
+	// TLessEqual is less than or equal token type.
+	TLessEqual
+
+	// TGreater is greater than token type.
+	TGreater
+
+	// TGreaterEqual is greater than or equal token type.
+	TGreaterEqual
+
+	// TPlus is plus token type.
+	TPlus
+
+	// TMinus is minus token type.
+	TMinus
+
+	// TSlash is slash token type.
+	TSlash
+
+	// TPercent is percent token type.
+	TPercent
+
+	// TConcat is string concatenation token type.
+	TConcat
+
+	// TInt64 is int64 token type.
+	// int32 に収まらない整数の literal を表す.
+	TInt64
+
+	// TFloat64 is float64 token type.
+	TFloat64
 )
 
 // Token is model of token.
@@ -58,3 +95,14 @@ func (tok Token) Type() TokenType {
 func (tok Token) Value() any {
 	return tok.value
 }
+
+// IsIdentifier checks whether the token can be used as an identifier.
+// 予約されていない keyword も identifier として扱える.
+func (tok Token) IsIdentifier() bool {
+	if tok.typ == TIdentifier {
+		return true
+	}
+	kw, _ := tok.value.(string)
+
+	return tok.typ == TKeyword && nonReservedKeywords[kw]
+}
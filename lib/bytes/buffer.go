@@ -148,6 +148,43 @@ func (buf *Buffer) SetInt32(offset int64, x int32) error {
 	return nil
 }
 
+// GetInt64 returns int64 from buffer.
+func (buf *Buffer) GetInt64(offset int64) (int64, error) {
+	if _, err := buf.Seek(offset, io.SeekStart); err != nil {
+		return 0, errors.Err(err, "Seek")
+	}
+
+	var ret int64
+	if !buf.hasSpace(ret) {
+		return 0, ErrInvalidOffset
+	}
+	if err := binary.Read(buf, endianness, &ret); err != nil {
+		return 0, errors.Err(err, "Read")
+	}
+
+	return ret, nil
+}
+
+// SetInt64 sets int64 to buffer.
+// --------------------
+// |  int64 (8 bytes) |
+// --------------------.
+func (buf *Buffer) SetInt64(offset int64, x int64) error {
+	if _, err := buf.Seek(offset, io.SeekStart); err != nil {
+		return errors.Err(err, "Seek")
+	}
+
+	if !buf.hasSpace(x) {
+		return ErrInvalidOffset
+	}
+
+	if err := binary.Write(buf, endianness, x); err != nil {
+		return errors.Err(err, "Write")
+	}
+
+	return nil
+}
+
 // getUint32 returns uint32 from buffer.
 func (buf *Buffer) getUint32(offset int64) (uint32, error) {
 	if _, err := buf.Seek(offset, io.SeekStart); err != nil {
@@ -142,6 +142,39 @@ func TestBuffer_SetInt32(t *testing.T) {
 	})
 }
 
+func TestBuffer_GetInt64(t *testing.T) {
+	t.Run("valid request", func(t *testing.T) {
+		data := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe}
+		buf := bytes.NewBufferBytes(data)
+		n, err := buf.GetInt64(0)
+		require.NoError(t, err)
+		require.Equal(t, int64(-2), n)
+	})
+
+	t.Run("invalid request: has no space", func(t *testing.T) {
+		data := []byte{0, 0, 0, 0, 0, 0, 9}
+		buf := bytes.NewBufferBytes(data)
+		n, err := buf.GetInt64(0)
+		require.Error(t, err)
+		require.Equal(t, int64(0), n)
+	})
+}
+
+func TestBuffer_SetInt64(t *testing.T) {
+	t.Run("valid request", func(t *testing.T) {
+		buf := bytes.NewBuffer(8)
+		err := buf.SetInt64(0, 1<<32+9)
+		require.NoError(t, err)
+		require.Equal(t, []byte{0, 0, 0, 1, 0, 0, 0, 9}, buf.GetData())
+	})
+
+	t.Run("invalid request: has no space", func(t *testing.T) {
+		buf := bytes.NewBuffer(7)
+		err := buf.SetInt64(0, 9)
+		require.Error(t, err)
+	})
+}
+
 // func TestBuffer_getUint32(t *testing.T) {
 // 	t.Run("valid request", func(t *testing.T) {
 // 		data := []byte{0, 0, 0, 9}
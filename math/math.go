@@ -10,3 +10,24 @@ func Max[T constraints.Ordered](a, b T) T {
 
 	return b
 }
+
+// Min returns min number.
+func Min[T constraints.Ordered](a, b T) T {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// Compare returns -1 if a is less than b, 1 if a is greater than b and 0 otherwise.
+func Compare[T constraints.Ordered](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -8,11 +8,33 @@ const (
 	fldTableName = "table_name"
 	fldFieldName = "field_name"
 	fldSlotSize  = "slot_size"
+	fldFormat    = "format"
 	fldType      = "type"
 	fldLength    = "length"
 	fldOffset    = "offset"
+	fldNotNull   = "notnull"
+	fldDefault   = "dflt"
+	fldCheck     = "checkdef"
 
-	fldIndexName = "indexname"
+	// versionedFormatSuffix is appended to the record format in table catalog for the table whose records have xmin and xmax.
+	versionedFormatSuffix = "/v2"
+
+	// legacyFormatSuffix is appended to the record format in table catalog for the table whose records have no null bitmap.
+	legacyFormatSuffix = "/v0"
+
+	fldIndexName   = "indexname"
+	fldIndexType   = "indextype"
+	fldKeyPosition = "keypos"
+)
+
+const (
+	constraintCatalog = "con_catalog"
+
+	fldConstraintName = "conname"
+	fldConstraintType = "contype"
+	fldRefTableName   = "reftable"
+	fldRefName        = "refname"
+	fldOnDelete       = "ondelete"
 )
 
 const (
@@ -24,3 +46,13 @@ const (
 const (
 	updateTimes = 100
 )
+
+const (
+	// catalogVersionFile is the file which stores the version of the catalog format.
+	// table の file と重ならないように, identifier に使えない文字を名前に含める.
+	catalogVersionFile = "catalog.version"
+
+	// currentCatalogVersion is the version of the catalog format written by this code.
+	// version file の無い database は version 0 の catalog を持つ.
+	currentCatalogVersion = 1
+)
@@ -0,0 +1,448 @@
+package metadata
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// ConstraintManager is a manager of UNIQUE, PRIMARY KEY and FOREIGN KEY constraints.
+// 制約ごとに同じ名前の btree index を作り, 制約の field は index catalog から読む.
+// FOREIGN KEY は参照先の制約の名前を保存し, 参照先の field もその index から読む.
+type ConstraintManager struct {
+	layout *domain.Layout
+	tblMgr *TableManager
+	idxMgr *IndexManager
+}
+
+// NewConstraintManager constructs a constraint manager.
+// 制約が導入される前に作られた database には constraint catalog が無いので, ここで作る.
+func NewConstraintManager(tblMgr *TableManager, idxMgr *IndexManager, txn domain.Transaction) (*ConstraintManager, error) {
+	if !tblMgr.Exists(constraintCatalog, txn) {
+		return CreateConstraintManager(tblMgr, idxMgr, txn)
+	}
+
+	return newConstraintManager(tblMgr, idxMgr, txn)
+}
+
+// CreateConstraintManager creates new constraint manager.
+func CreateConstraintManager(tblMgr *TableManager, idxMgr *IndexManager, txn domain.Transaction) (*ConstraintManager, error) {
+	sch := domain.NewSchema()
+	sch.AddStringField(fldConstraintName, domain.MaxIndexNameLength)
+	sch.AddStringField(fldTableName, domain.MaxTableNameLength)
+	sch.AddStringField(fldConstraintType, domain.MaxConstraintTypeLength)
+	sch.AddStringField(fldRefTableName, domain.MaxTableNameLength)
+	sch.AddStringField(fldRefName, domain.MaxIndexNameLength)
+	sch.AddStringField(fldOnDelete, domain.MaxReferentialActionLength)
+	if err := tblMgr.createCatalog(constraintCatalog, sch, txn); err != nil {
+		return nil, errors.Err(err, "createCatalog")
+	}
+
+	return newConstraintManager(tblMgr, idxMgr, txn)
+}
+
+func newConstraintManager(tblMgr *TableManager, idxMgr *IndexManager, txn domain.Transaction) (*ConstraintManager, error) {
+	layout, err := tblMgr.GetTableLayout(constraintCatalog, txn)
+	if err != nil {
+		return nil, errors.Err(err, "GetTableLayout")
+	}
+
+	return &ConstraintManager{
+		layout: layout,
+		tblMgr: tblMgr,
+		idxMgr: idxMgr,
+	}, nil
+}
+
+// CreateConstraint creates a constraint and its index.
+// 名前は PostgreSQL と同様に table 名と field 名から付ける.
+func (conMgr *ConstraintManager) CreateConstraint(tblName domain.TableName, c domain.Constraint, txn domain.Transaction) error {
+	layout, err := conMgr.tblMgr.GetTableLayout(tblName, txn)
+	if err != nil {
+		return errors.Err(err, "GetTableLayout")
+	}
+	for _, fld := range c.FieldNames() {
+		if !layout.Schema().HasField(fld) {
+			return errors.Wrap(domain.ErrFieldNotFound, fld.String())
+		}
+		if !layout.Schema().Type(fld).IsIndexable() {
+			return errors.Wrap(domain.ErrNotIndexable, fld.String())
+		}
+	}
+
+	cons, err := conMgr.GetConstraints(tblName, txn)
+	if err != nil {
+		return errors.Err(err, "GetConstraints")
+	}
+
+	// 制約の index の名前は, 他の table のものも含めて既存の index と重ならないようにする.
+	used, err := conMgr.idxMgr.indexNames(txn)
+	if err != nil {
+		return errors.Err(err, "indexNames")
+	}
+	for _, con := range cons {
+		if con.Type() == domain.PrimaryKeyConstraint && c.Type() == domain.PrimaryKeyConstraint {
+			return errors.Wrap(domain.ErrMultiplePrimaryKeys, tblName.String())
+		}
+		used[con.Name()] = true
+	}
+
+	if c.Type() == domain.ForeignKeyConstraint {
+		ref, err := conMgr.referencedConstraint(tblName, layout.Schema(), c, cons, txn)
+		if err != nil {
+			return errors.Err(err, "referencedConstraint")
+		}
+		c = c.WithReference(ref)
+	}
+
+	name := constraintName(tblName, c, used)
+	if err := conMgr.idxMgr.CreateIndex(name, tblName, c.FieldNames(), domain.BTreeIndexType, txn); err != nil {
+		return errors.Err(err, "CreateIndex")
+	}
+
+	tbl, err := domain.NewTableScan(txn, constraintCatalog, conMgr.layout)
+	if err != nil {
+		return errors.Err(err, "NewTableScan")
+	}
+	defer tbl.Close()
+
+	if err := tbl.AdvanceNextInsertSlotID(); err != nil {
+		return errors.Err(err, "AdvanceNextInsertSlotID")
+	}
+	if err := tbl.SetString(fldConstraintName, name.String()); err != nil {
+		return errors.Err(err, "SetString")
+	}
+	if err := tbl.SetString(fldTableName, tblName.String()); err != nil {
+		return errors.Err(err, "SetString")
+	}
+	if err := tbl.SetString(fldConstraintType, c.Type().String()); err != nil {
+		return errors.Err(err, "SetString")
+	}
+	if c.Type() == domain.ForeignKeyConstraint {
+		if err := tbl.SetString(fldRefTableName, c.RefTableName().String()); err != nil {
+			return errors.Err(err, "SetString")
+		}
+		if err := tbl.SetString(fldRefName, c.RefName().String()); err != nil {
+			return errors.Err(err, "SetString")
+		}
+		if err := tbl.SetString(fldOnDelete, c.OnDelete().String()); err != nil {
+			return errors.Err(err, "SetString")
+		}
+	}
+
+	return nil
+}
+
+// referencedConstraint returns the UNIQUE or PRIMARY KEY constraint referenced by the foreign key c.
+// 参照先の field を省略した場合は primary key を参照する.
+func (conMgr *ConstraintManager) referencedConstraint(tblName domain.TableName, sch *domain.Schema, c domain.Constraint, cons []domain.Constraint, txn domain.Transaction) (domain.Constraint, error) {
+	refCons := cons
+	refSch := sch
+	if c.RefTableName() != tblName {
+		layout, err := conMgr.tblMgr.GetTableLayout(c.RefTableName(), txn)
+		if err != nil {
+			return domain.Constraint{}, errors.Err(err, "GetTableLayout")
+		}
+		refSch = layout.Schema()
+
+		refCons, err = conMgr.GetConstraints(c.RefTableName(), txn)
+		if err != nil {
+			return domain.Constraint{}, errors.Err(err, "GetConstraints")
+		}
+	}
+
+	for _, ref := range refCons {
+		if ref.Type() == domain.ForeignKeyConstraint {
+			continue
+		}
+		if len(c.RefFieldNames()) == 0 && ref.Type() != domain.PrimaryKeyConstraint {
+			continue
+		}
+		if len(c.RefFieldNames()) > 0 && !equalFieldNames(c.RefFieldNames(), ref.FieldNames()) {
+			continue
+		}
+		if len(c.FieldNames()) != len(ref.FieldNames()) {
+			break
+		}
+
+		for i, fld := range c.FieldNames() {
+			if sch.Type(fld) != refSch.Type(ref.FieldNames()[i]) {
+				return domain.Constraint{}, errors.Wrap(domain.ErrTypeMismatch, fld.String())
+			}
+		}
+
+		return ref, nil
+	}
+
+	return domain.Constraint{}, errors.Wrap(domain.ErrNoMatchingUniqueKey, c.RefTableName().String())
+}
+
+// GetReferencingTables returns tables which have foreign keys referencing given table.
+func (conMgr *ConstraintManager) GetReferencingTables(tblName domain.TableName, txn domain.Transaction) ([]domain.TableName, error) {
+	tbl, err := domain.NewTableScan(txn, constraintCatalog, conMgr.layout)
+	if err != nil {
+		return nil, errors.Err(err, "NewTableScan")
+	}
+	defer tbl.Close()
+
+	tblNames := make([]domain.TableName, 0)
+	found := make(map[domain.TableName]bool)
+	for tbl.HasNext() {
+		refTblName, err := tbl.GetVal(fldRefTableName)
+		if err != nil {
+			return nil, errors.Err(err, "GetVal")
+		}
+		if refTblName.IsNull() || refTblName.String() != tblName.String() {
+			continue
+		}
+
+		name, err := tbl.GetString(fldTableName)
+		if err != nil {
+			return nil, errors.Err(err, "GetString")
+		}
+		if !found[domain.TableName(name)] {
+			found[domain.TableName(name)] = true
+			tblNames = append(tblNames, domain.TableName(name))
+		}
+	}
+	if err := tbl.Err(); err != nil {
+		return nil, errors.Err(err, "HasNext")
+	}
+
+	return tblNames, nil
+}
+
+// DropConstraints removes all constraints of given table from the catalog.
+// 制約の index は IndexManager が削除する.
+func (conMgr *ConstraintManager) DropConstraints(tblName domain.TableName, txn domain.Transaction) error {
+	if _, err := deleteCatalogRecords(txn, constraintCatalog, conMgr.layout, fldTableName, tblName.String()); err != nil {
+		return errors.Err(err, "deleteCatalogRecords")
+	}
+
+	return nil
+}
+
+// DropConstraint removes the constraint from the catalog.
+// 制約の index は IndexManager が削除する.
+func (conMgr *ConstraintManager) DropConstraint(name domain.IndexName, txn domain.Transaction) error {
+	if _, err := deleteCatalogRecords(txn, constraintCatalog, conMgr.layout, fldConstraintName, name.String()); err != nil {
+		return errors.Err(err, "deleteCatalogRecords")
+	}
+
+	return nil
+}
+
+// RenameTable renames the table in the catalog, including references of foreign keys.
+// 制約の名前は変えない.
+func (conMgr *ConstraintManager) RenameTable(tblName, newName domain.TableName, txn domain.Transaction) error {
+	if _, err := updateCatalogRecords(txn, constraintCatalog, conMgr.layout, fldTableName, tblName.String(), newName.String()); err != nil {
+		return errors.Err(err, "updateCatalogRecords")
+	}
+	if _, err := updateCatalogRecords(txn, constraintCatalog, conMgr.layout, fldRefTableName, tblName.String(), newName.String()); err != nil {
+		return errors.Err(err, "updateCatalogRecords")
+	}
+
+	return nil
+}
+
+// IsConstraint checks whether the index implements a constraint or not.
+func (conMgr *ConstraintManager) IsConstraint(idxName domain.IndexName, txn domain.Transaction) (bool, error) {
+	tbl, err := domain.NewTableScan(txn, constraintCatalog, conMgr.layout)
+	if err != nil {
+		return false, errors.Err(err, "NewTableScan")
+	}
+	defer tbl.Close()
+
+	for tbl.HasNext() {
+		name, err := tbl.GetString(fldConstraintName)
+		if err != nil {
+			return false, errors.Err(err, "GetString")
+		}
+		if name == idxName.String() {
+			return true, nil
+		}
+	}
+	if err := tbl.Err(); err != nil {
+		return false, errors.Err(err, "HasNext")
+	}
+
+	return false, nil
+}
+
+// GetConstraints returns all constraints of given table.
+func (conMgr *ConstraintManager) GetConstraints(tblName domain.TableName, txn domain.Transaction) ([]domain.Constraint, error) {
+	tbl, err := domain.NewTableScan(txn, constraintCatalog, conMgr.layout)
+	if err != nil {
+		return nil, errors.Err(err, "NewTableScan")
+	}
+	defer tbl.Close()
+
+	types := make(map[domain.IndexName]domain.ConstraintType)
+	fkeys := make(map[domain.IndexName]domain.Constraint)
+	for tbl.HasNext() {
+		storedTblName, err := tbl.GetString(fldTableName)
+		if err != nil {
+			return nil, errors.Err(err, "GetString")
+		}
+		if storedTblName != tblName.String() {
+			continue
+		}
+
+		nameStr, err := tbl.GetString(fldConstraintName)
+		if err != nil {
+			return nil, errors.Err(err, "GetString")
+		}
+		name, err := domain.NewIndexName(nameStr)
+		if err != nil {
+			return nil, errors.Err(err, "NewIndexName")
+		}
+
+		typStr, err := tbl.GetString(fldConstraintType)
+		if err != nil {
+			return nil, errors.Err(err, "GetString")
+		}
+		typ, err := domain.NewConstraintType(typStr)
+		if err != nil {
+			return nil, errors.Err(err, "NewConstraintType")
+		}
+
+		types[name] = typ
+
+		if typ == domain.ForeignKeyConstraint {
+			fkey, err := conMgr.foreignKey(tbl)
+			if err != nil {
+				return nil, errors.Err(err, "foreignKey")
+			}
+			fkeys[name] = fkey
+		}
+	}
+	if err := tbl.Err(); err != nil {
+		return nil, errors.Err(err, "HasNext")
+	}
+
+	cons := make([]domain.Constraint, 0, len(types))
+	if len(types) == 0 {
+		return cons, nil
+	}
+
+	// 制約の field は対応する index の key と同じ.
+	idxInfos, err := conMgr.idxMgr.GetIndexInfo(tblName, txn)
+	if err != nil {
+		return nil, errors.Err(err, "GetIndexInfo")
+	}
+	for _, info := range idxInfos {
+		typ, ok := types[info.IndexName()]
+		if !ok {
+			continue
+		}
+
+		if typ != domain.ForeignKeyConstraint {
+			cons = append(cons, domain.NewConstraint(typ, info.FieldNames()).WithName(info.IndexName()))
+
+			continue
+		}
+
+		fkey := fkeys[info.IndexName()]
+		refInfos := idxInfos
+		if fkey.RefTableName() != tblName {
+			refInfos, err = conMgr.idxMgr.GetIndexInfo(fkey.RefTableName(), txn)
+			if err != nil {
+				return nil, errors.Err(err, "GetIndexInfo")
+			}
+		}
+		for _, refInfo := range refInfos {
+			if refInfo.IndexName() == fkey.RefName() {
+				ref := domain.NewConstraint(domain.UniqueConstraint, refInfo.FieldNames()).WithName(refInfo.IndexName())
+				fkey = domain.NewForeignKeyConstraint(info.FieldNames(), fkey.RefTableName(), nil, fkey.OnDelete()).
+					WithName(info.IndexName()).
+					WithReference(ref)
+			}
+		}
+		cons = append(cons, fkey)
+	}
+
+	return cons, nil
+}
+
+// foreignKey reads the referenced table, constraint and ON DELETE action from the current record.
+// field は index catalog から読むので, ここでは設定しない.
+func (conMgr *ConstraintManager) foreignKey(tbl *domain.TableScan) (domain.Constraint, error) {
+	refTblStr, err := tbl.GetString(fldRefTableName)
+	if err != nil {
+		return domain.Constraint{}, errors.Err(err, "GetString")
+	}
+	refTblName, err := domain.NewTableName(refTblStr)
+	if err != nil {
+		return domain.Constraint{}, errors.Err(err, "NewTableName")
+	}
+
+	refNameStr, err := tbl.GetString(fldRefName)
+	if err != nil {
+		return domain.Constraint{}, errors.Err(err, "GetString")
+	}
+	refName, err := domain.NewIndexName(refNameStr)
+	if err != nil {
+		return domain.Constraint{}, errors.Err(err, "NewIndexName")
+	}
+
+	actionStr, err := tbl.GetString(fldOnDelete)
+	if err != nil {
+		return domain.Constraint{}, errors.Err(err, "GetString")
+	}
+	action, err := domain.NewReferentialAction(actionStr)
+	if err != nil {
+		return domain.Constraint{}, errors.Err(err, "NewReferentialAction")
+	}
+
+	ref := domain.NewConstraint(domain.UniqueConstraint, nil).WithName(refName)
+
+	return domain.NewForeignKeyConstraint(nil, refTblName, nil, action).WithReference(ref), nil
+}
+
+// constraintName names the constraint like tbl_pkey or tbl_fld_key.
+// 長すぎる場合は table 名と field 名の部分を切り詰め, 重複する場合は番号を付ける.
+func constraintName(tblName domain.TableName, c domain.Constraint, used map[domain.IndexName]bool) domain.IndexName {
+	base, suffix := tblName.String(), "_pkey"
+	if c.Type() != domain.PrimaryKeyConstraint {
+		flds := make([]string, 0, len(c.FieldNames()))
+		for _, fld := range c.FieldNames() {
+			flds = append(flds, fld.String())
+		}
+		base, suffix = base+"_"+strings.Join(flds, "_"), "_key"
+		if c.Type() == domain.ForeignKeyConstraint {
+			suffix = "_fkey"
+		}
+	}
+
+	for i := 0; ; i++ {
+		s := suffix
+		if i > 0 {
+			s = fmt.Sprintf("%v%v", suffix, i)
+		}
+
+		b := base
+		if len(b)+len(s) > domain.MaxIndexNameLength {
+			b = b[:domain.MaxIndexNameLength-len(s)]
+		}
+
+		name := domain.IndexName(b + s)
+		if !used[name] {
+			return name
+		}
+	}
+}
+
+func equalFieldNames(a, b []domain.FieldName) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,11 @@
+package metadata
+
+import "github.com/goropikari/simpledbgo/domain"
+
+func (tblMgr *TableManager) CreateUnversionedTable(tblName domain.TableName, sch *domain.Schema, format domain.RecordFormat, txn domain.Transaction) error {
+	return tblMgr.createTable(tblName, newLayout(sch, tableFormat{format: format}), txn)
+}
+
+func (mgr *Manager) DropCatalog(tblName domain.TableName, txn domain.Transaction) error {
+	return mgr.tblMgr.DropTable(tblName, txn)
+}
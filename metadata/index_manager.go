@@ -34,7 +34,9 @@ func CreateIndexManager(factory domain.IndexDriver, tblMgr *TableManager, statMg
 	sch.AddStringField(fldIndexName, domain.MaxIndexNameLength)
 	sch.AddStringField(fldTableName, domain.MaxTableNameLength)
 	sch.AddStringField(fldFieldName, domain.MaxFieldNameLength)
-	if err := tblMgr.CreateTable(fldIndexCatalog, sch, txn); err != nil {
+	sch.AddStringField(fldIndexType, domain.MaxIndexTypeLength)
+	sch.AddInt32Field(fldKeyPosition)
+	if err := tblMgr.createCatalog(fldIndexCatalog, sch, txn); err != nil {
 		return nil, err
 	}
 
@@ -52,48 +54,246 @@ func CreateIndexManager(factory domain.IndexDriver, tblMgr *TableManager, statMg
 }
 
 // CreateIndex creates an index.
-func (idxMgr *IndexManager) CreateIndex(idxName domain.IndexName, tblName domain.TableName, fldName domain.FieldName, txn domain.Transaction) error {
+// composite index は key の field ごとに catalog の record を作り, key の中での位置を記録する.
+func (idxMgr *IndexManager) CreateIndex(idxName domain.IndexName, tblName domain.TableName, fldNames []domain.FieldName, idxType domain.IndexType, txn domain.Transaction) error {
+	if !idxMgr.idxFactory.Supports(idxType) {
+		return errors.Wrap(domain.ErrUnknownIndexType, idxType.String())
+	}
+
+	names, err := idxMgr.indexNames(txn)
+	if err != nil {
+		return errors.Err(err, "indexNames")
+	}
+	if names[idxName] {
+		return errors.Wrap(domain.ErrIndexExists, idxName.String())
+	}
+
 	tbl, err := domain.NewTableScan(txn, fldIndexCatalog, idxMgr.layout)
 	if err != nil {
 		return errors.Err(err, "NewTableScan")
 	}
 	defer tbl.Close()
 
-	if err := tbl.AdvanceNextInsertSlotID(); err != nil {
-		return errors.Err(err, "AdvanceNextInsertSlotID")
+	for pos, fldName := range fldNames {
+		if err := tbl.AdvanceNextInsertSlotID(); err != nil {
+			return errors.Err(err, "AdvanceNextInsertSlotID")
+		}
+
+		err = tbl.SetString(fldIndexName, idxName.String())
+		if err != nil {
+			return errors.Err(err, "SetString")
+		}
+
+		err = tbl.SetString(fldTableName, tblName.String())
+		if err != nil {
+			return errors.Err(err, "SetString")
+		}
+
+		err = tbl.SetString(fldFieldName, fldName.String())
+		if err != nil {
+			return errors.Err(err, "SetString")
+		}
+
+		err = tbl.SetString(fldIndexType, idxType.String())
+		if err != nil {
+			return errors.Err(err, "SetString")
+		}
+
+		err = tbl.SetInt32(fldKeyPosition, int32(pos))
+		if err != nil {
+			return errors.Err(err, "SetInt32")
+		}
+	}
+
+	return nil
+}
+
+// indexNames returns the names of all indexes.
+// 制約のための index も含む.
+func (idxMgr *IndexManager) indexNames(txn domain.Transaction) (map[domain.IndexName]bool, error) {
+	tbl, err := domain.NewTableScan(txn, fldIndexCatalog, idxMgr.layout)
+	if err != nil {
+		return nil, errors.Err(err, "NewTableScan")
+	}
+	defer tbl.Close()
+
+	names := make(map[domain.IndexName]bool)
+	for tbl.HasNext() {
+		name, err := tbl.GetString(fldIndexName)
+		if err != nil {
+			return nil, errors.Err(err, "GetString")
+		}
+		names[domain.IndexName(name)] = true
+	}
+	if err := tbl.Err(); err != nil {
+		return nil, errors.Err(err, "HasNext")
 	}
 
-	err = tbl.SetString(fldIndexName, idxName.String())
+	return names, nil
+}
+
+// DropIndex removes the index from the catalog and removes its files when the transaction is committed.
+func (idxMgr *IndexManager) DropIndex(idxName domain.IndexName, txn domain.Transaction) error {
+	tbl, err := domain.NewTableScan(txn, fldIndexCatalog, idxMgr.layout)
 	if err != nil {
-		return errors.Err(err, "SetString")
+		return errors.Err(err, "NewTableScan")
+	}
+	defer tbl.Close()
+
+	var idxType domain.IndexType
+	found := false
+	for tbl.HasNext() {
+		name, err := tbl.GetString(fldIndexName)
+		if err != nil {
+			return errors.Err(err, "GetString")
+		}
+		if name != idxName.String() {
+			continue
+		}
+
+		typ, err := tbl.GetString(fldIndexType)
+		if err != nil {
+			return errors.Err(err, "GetString")
+		}
+		idxType, err = domain.NewIndexType(typ)
+		if err != nil {
+			return errors.Err(err, "NewIndexType")
+		}
+
+		if err := tbl.Delete(); err != nil {
+			return errors.Err(err, "Delete")
+		}
+		found = true
+	}
+	if err := tbl.Err(); err != nil {
+		return errors.Err(err, "HasNext")
+	}
+	if !found {
+		return errors.Wrap(domain.ErrIndexNotFound, idxName.String())
 	}
 
-	err = tbl.SetString(fldTableName, tblName.String())
+	for _, filename := range idxMgr.idxFactory.FileNames(idxType, idxName) {
+		if err := txn.RemoveFile(filename); err != nil {
+			return errors.Err(err, "RemoveFile")
+		}
+	}
+
+	return nil
+}
+
+// DropIndexes drops all indexes of given table.
+func (idxMgr *IndexManager) DropIndexes(tblName domain.TableName, txn domain.Transaction) error {
+	entries, err := idxMgr.readIndexEntries(tblName, txn)
 	if err != nil {
-		return errors.Err(err, "SetString")
+		return errors.Err(err, "readIndexEntries")
 	}
 
-	err = tbl.SetString(fldFieldName, fldName.String())
+	for _, ent := range entries {
+		if err := idxMgr.DropIndex(ent.idxName, txn); err != nil {
+			return errors.Err(err, "DropIndex")
+		}
+	}
+
+	return nil
+}
+
+// RenameTable renames the table in the catalog.
+// index の名前と file は変えない.
+func (idxMgr *IndexManager) RenameTable(tblName, newName domain.TableName, txn domain.Transaction) error {
+	if _, err := updateCatalogRecords(txn, fldIndexCatalog, idxMgr.layout, fldTableName, tblName.String(), newName.String()); err != nil {
+		return errors.Err(err, "updateCatalogRecords")
+	}
+
+	return nil
+}
+
+// RenameField renames the field of the table in the catalog.
+func (idxMgr *IndexManager) RenameField(tblName domain.TableName, oldName, newName domain.FieldName, txn domain.Transaction) error {
+	tbl, err := domain.NewTableScan(txn, fldIndexCatalog, idxMgr.layout)
 	if err != nil {
-		return errors.Err(err, "SetString")
+		return errors.Err(err, "NewTableScan")
+	}
+	defer tbl.Close()
+
+	for tbl.HasNext() {
+		storedTblName, err := tbl.GetString(fldTableName)
+		if err != nil {
+			return errors.Err(err, "GetString")
+		}
+		fldName, err := tbl.GetString(fldFieldName)
+		if err != nil {
+			return errors.Err(err, "GetString")
+		}
+		if storedTblName != tblName.String() || fldName != oldName.String() {
+			continue
+		}
+
+		if err := tbl.SetString(fldFieldName, newName.String()); err != nil {
+			return errors.Err(err, "SetString")
+		}
+	}
+	if err := tbl.Err(); err != nil {
+		return errors.Err(err, "HasNext")
 	}
 
 	return nil
 }
 
-// GetIndexInfo returns all index information of given table.
-func (idxMgr *IndexManager) GetIndexInfo(tblName domain.TableName, txn domain.Transaction) (map[domain.FieldName]*domain.IndexInfo, error) {
-	infos := make(map[domain.FieldName]*domain.IndexInfo)
+// indexEntry is an index read from the catalog.
+type indexEntry struct {
+	idxName  domain.IndexName
+	idxType  domain.IndexType
+	fldNames map[int32]domain.FieldName
+}
+
+// GetIndexInfo returns all index information of given table in the order of the catalog.
+func (idxMgr *IndexManager) GetIndexInfo(tblName domain.TableName, txn domain.Transaction) ([]*domain.IndexInfo, error) {
+	entries, err := idxMgr.readIndexEntries(tblName, txn)
+	if err != nil {
+		return nil, errors.Err(err, "readIndexEntries")
+	}
+
+	infos := make([]*domain.IndexInfo, 0, len(entries))
+	if len(entries) == 0 {
+		return infos, nil
+	}
+
+	tblLayout, err := idxMgr.tblMgr.GetTableLayout(tblName, txn)
+	if err != nil {
+		return nil, errors.Err(err, "GetTableLayout")
+	}
+
+	tblsi, err := idxMgr.statMgr.GetStatInfo(tblName, tblLayout, txn)
+	if err != nil {
+		return nil, errors.Err(err, "GetStatInfo")
+	}
+
+	for _, ent := range entries {
+		fldNames := make([]domain.FieldName, len(ent.fldNames))
+		for pos, fldName := range ent.fldNames {
+			fldNames[pos] = fldName
+		}
+
+		infos = append(infos, domain.NewIndexInfo(idxMgr.idxFactory, ent.idxName, ent.idxType, fldNames, tblLayout.Schema(), txn, tblsi))
+	}
+
+	return infos, nil
+}
+
+// readIndexEntries reads the indexes of given table from the catalog.
+func (idxMgr *IndexManager) readIndexEntries(tblName domain.TableName, txn domain.Transaction) ([]*indexEntry, error) {
 	tbl, err := domain.NewTableScan(txn, fldIndexCatalog, idxMgr.layout)
 	if err != nil {
-		return nil, errors.Err(err, "SetString")
+		return nil, errors.Err(err, "NewTableScan")
 	}
 	defer tbl.Close()
 
+	entries := make([]*indexEntry, 0)
+	found := make(map[domain.IndexName]*indexEntry)
 	for tbl.HasNext() {
 		storedTblName, err := tbl.GetString(fldTableName)
 		if err != nil {
-			return nil, errors.Err(err, "SetString")
+			return nil, errors.Err(err, "GetString")
 		}
 		if storedTblName != tblName.String() {
 			continue
@@ -101,39 +301,51 @@ func (idxMgr *IndexManager) GetIndexInfo(tblName domain.TableName, txn domain.Tr
 
 		idxNameStr, err := tbl.GetString(fldIndexName)
 		if err != nil {
-			return nil, errors.Err(err, "SetString")
+			return nil, errors.Err(err, "GetString")
 		}
 		idxName, err := domain.NewIndexName(idxNameStr)
 		if err != nil {
-			return nil, errors.Err(err, "SetString")
+			return nil, errors.Err(err, "NewIndexName")
 		}
 
 		fldNameStr, err := tbl.GetString(fldFieldName)
 		if err != nil {
-			return nil, errors.Err(err, "SetString")
+			return nil, errors.Err(err, "GetString")
 		}
 		fldName, err := domain.NewFieldName(fldNameStr)
 		if err != nil {
-			return nil, errors.Err(err, "SetString")
+			return nil, errors.Err(err, "NewFieldName")
 		}
 
-		tblLayout, err := idxMgr.tblMgr.GetTableLayout(tblName, txn)
+		idxTypeStr, err := tbl.GetString(fldIndexType)
 		if err != nil {
-			return nil, errors.Err(err, "SetString")
+			return nil, errors.Err(err, "GetString")
 		}
-
-		tblsi, err := idxMgr.statMgr.GetStatInfo(tblName, tblLayout, txn)
+		idxType, err := domain.NewIndexType(idxTypeStr)
 		if err != nil {
-			return nil, errors.Err(err, "SetString")
+			return nil, errors.Err(err, "NewIndexType")
 		}
 
-		idxInfo := domain.NewIndexInfo(idxMgr.idxFactory, idxName, fldName, tblLayout.Schema(), txn, tblsi)
+		pos, err := tbl.GetInt32(fldKeyPosition)
+		if err != nil {
+			return nil, errors.Err(err, "GetInt32")
+		}
 
-		infos[fldName] = idxInfo
+		ent, ok := found[idxName]
+		if !ok {
+			ent = &indexEntry{
+				idxName:  idxName,
+				idxType:  idxType,
+				fldNames: make(map[int32]domain.FieldName),
+			}
+			found[idxName] = ent
+			entries = append(entries, ent)
+		}
+		ent.fldNames[pos] = fldName
 	}
 	if err := tbl.Err(); err != nil {
 		return nil, errors.Err(err, "HasNext")
 	}
 
-	return infos, nil
+	return entries, nil
 }
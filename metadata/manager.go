@@ -14,6 +14,7 @@ type Manager struct {
 	viewMgr *ViewManager
 	statMgr *StatManager
 	idxMgr  *IndexManager
+	conMgr  *ConstraintManager
 }
 
 // NewManager constructs a metadata manager.
@@ -52,6 +53,15 @@ func createManager(driver domain.IndexDriver, fileMgr domain.FileManager, logMgr
 		return nil, errors.Err(err, "CreateIndexManager")
 	}
 
+	conMgr, err := CreateConstraintManager(tblMgr, idxMgr, txn)
+	if err != nil {
+		return nil, errors.Err(err, "CreateConstraintManager")
+	}
+
+	if err := setCatalogVersion(txn); err != nil {
+		return nil, errors.Err(err, "setCatalogVersion")
+	}
+
 	err = txn.Commit()
 	if err != nil {
 		return nil, errors.Err(err, "Commit")
@@ -62,6 +72,7 @@ func createManager(driver domain.IndexDriver, fileMgr domain.FileManager, logMgr
 		viewMgr: viewMgr,
 		statMgr: statMgr,
 		idxMgr:  idxMgr,
+		conMgr:  conMgr,
 	}, nil
 }
 
@@ -72,6 +83,11 @@ func newManager(driver domain.IndexDriver, fileMgr domain.FileManager, logMgr do
 		return nil, errors.Err(err, "NewTransaction")
 	}
 
+	// version 0 の catalog を持つ database は, 今の format の catalog に書き直してから使う.
+	if err := upgradeCatalogs(driver, txn); err != nil {
+		return nil, errors.Err(err, "upgradeCatalogs")
+	}
+
 	tblMgr := NewTableManager()
 	viewMgr := NewViewManager(tblMgr)
 	statMgr, err := NewStatManager(tblMgr, txn)
@@ -84,6 +100,11 @@ func newManager(driver domain.IndexDriver, fileMgr domain.FileManager, logMgr do
 		return nil, errors.Err(err, "NewIndexManager")
 	}
 
+	conMgr, err := NewConstraintManager(tblMgr, idxMgr, txn)
+	if err != nil {
+		return nil, errors.Err(err, "NewConstraintManager")
+	}
+
 	err = txn.Commit()
 	if err != nil {
 		return nil, errors.Err(err, "Commit")
@@ -94,16 +115,17 @@ func newManager(driver domain.IndexDriver, fileMgr domain.FileManager, logMgr do
 		viewMgr: viewMgr,
 		statMgr: statMgr,
 		idxMgr:  idxMgr,
+		conMgr:  conMgr,
 	}, nil
 }
 
-// CreateTable creates a table.
-func (mgr *Manager) CreateTable(tblName domain.TableName, sch *domain.Schema, txn domain.Transaction) error {
+// CreateTable creates a table whose records are stored in the format.
+func (mgr *Manager) CreateTable(tblName domain.TableName, sch *domain.Schema, format domain.RecordFormat, txn domain.Transaction) error {
 	if mgr.tblMgr.Exists(tblName, txn) {
 		return fmt.Errorf("relation \"%v\" already exists", tblName)
 	}
 
-	return mgr.tblMgr.CreateTable(tblName, sch, txn)
+	return mgr.tblMgr.CreateTable(tblName, sch, format, txn)
 }
 
 // GetTableLayout returns given table layout.
@@ -122,12 +144,12 @@ func (mgr *Manager) GetViewDef(viewName domain.ViewName, txn domain.Transaction)
 }
 
 // CreateIndex creates an index.
-func (mgr *Manager) CreateIndex(idxName domain.IndexName, tblName domain.TableName, fldName domain.FieldName, txn domain.Transaction) error {
-	return mgr.idxMgr.CreateIndex(idxName, tblName, fldName, txn)
+func (mgr *Manager) CreateIndex(idxName domain.IndexName, tblName domain.TableName, fldNames []domain.FieldName, idxType domain.IndexType, txn domain.Transaction) error {
+	return mgr.idxMgr.CreateIndex(idxName, tblName, fldNames, idxType, txn)
 }
 
 // GetIndexInfo returns the index information of given table.
-func (mgr *Manager) GetIndexInfo(tblName domain.TableName, txn domain.Transaction) (map[domain.FieldName]*domain.IndexInfo, error) {
+func (mgr *Manager) GetIndexInfo(tblName domain.TableName, txn domain.Transaction) ([]*domain.IndexInfo, error) {
 	return mgr.idxMgr.GetIndexInfo(tblName, txn)
 }
 
@@ -135,3 +157,305 @@ func (mgr *Manager) GetIndexInfo(tblName domain.TableName, txn domain.Transactio
 func (mgr *Manager) GetStatInfo(tblName domain.TableName, layout *domain.Layout, txn domain.Transaction) (domain.StatInfo, error) {
 	return mgr.statMgr.GetStatInfo(tblName, layout, txn)
 }
+
+// CreateConstraint creates a UNIQUE, PRIMARY KEY or FOREIGN KEY constraint and its index.
+func (mgr *Manager) CreateConstraint(tblName domain.TableName, c domain.Constraint, txn domain.Transaction) error {
+	return mgr.conMgr.CreateConstraint(tblName, c, txn)
+}
+
+// GetConstraints returns the constraints of given table.
+func (mgr *Manager) GetConstraints(tblName domain.TableName, txn domain.Transaction) ([]domain.Constraint, error) {
+	return mgr.conMgr.GetConstraints(tblName, txn)
+}
+
+// GetReferencingTables returns tables which have foreign keys referencing given table.
+func (mgr *Manager) GetReferencingTables(tblName domain.TableName, txn domain.Transaction) ([]domain.TableName, error) {
+	return mgr.conMgr.GetReferencingTables(tblName, txn)
+}
+
+// DropTable drops a table with its indexes and constraints.
+// 他の table の foreign key や view から参照されている table は削除できない.
+func (mgr *Manager) DropTable(tblName domain.TableName, txn domain.Transaction) error {
+	if isCatalog(tblName) {
+		return errors.Wrap(domain.ErrDropCatalog, tblName.String())
+	}
+	if !mgr.tblMgr.Exists(tblName, txn) {
+		return errors.Wrap(domain.ErrTableNotFound, tblName.String())
+	}
+
+	refs, err := mgr.conMgr.GetReferencingTables(tblName, txn)
+	if err != nil {
+		return errors.Err(err, "GetReferencingTables")
+	}
+	for _, ref := range refs {
+		if ref != tblName {
+			return errors.Wrap(domain.ErrDependentObjectsExist, fmt.Sprintf("table %v references table %v", ref, tblName))
+		}
+	}
+
+	views, err := mgr.viewMgr.GetReferencingViews(tblName, txn)
+	if err != nil {
+		return errors.Err(err, "GetReferencingViews")
+	}
+	if len(views) > 0 {
+		return errors.Wrap(domain.ErrDependentObjectsExist, fmt.Sprintf("view %v depends on table %v", views[0], tblName))
+	}
+
+	if err := mgr.conMgr.DropConstraints(tblName, txn); err != nil {
+		return errors.Err(err, "DropConstraints")
+	}
+	if err := mgr.idxMgr.DropIndexes(tblName, txn); err != nil {
+		return errors.Err(err, "DropIndexes")
+	}
+	if err := mgr.tblMgr.DropTable(tblName, txn); err != nil {
+		return errors.Err(err, "DropTable")
+	}
+	mgr.statMgr.Invalidate(tblName)
+
+	return nil
+}
+
+// DropView drops a view.
+func (mgr *Manager) DropView(viewName domain.ViewName, txn domain.Transaction) error {
+	return mgr.viewMgr.DropView(viewName, txn)
+}
+
+// DropIndex drops an index.
+// 制約の検査に使っている index は削除できない.
+func (mgr *Manager) DropIndex(idxName domain.IndexName, txn domain.Transaction) error {
+	isCon, err := mgr.conMgr.IsConstraint(idxName, txn)
+	if err != nil {
+		return errors.Err(err, "IsConstraint")
+	}
+	if isCon {
+		return errors.Wrap(domain.ErrDependentObjectsExist, fmt.Sprintf("constraint %v requires index %v", idxName, idxName))
+	}
+
+	return mgr.idxMgr.DropIndex(idxName, txn)
+}
+
+// AddField adds the field fldName defined in sch to the table.
+// 既存の record は planner が新しい layout で書き直す.
+func (mgr *Manager) AddField(tblName domain.TableName, fldName domain.FieldName, sch *domain.Schema, txn domain.Transaction) error {
+	layout, err := mgr.alterableLayout(tblName, txn)
+	if err != nil {
+		return errors.Err(err, "alterableLayout")
+	}
+	if layout.Schema().HasField(fldName) {
+		return errors.Wrap(domain.ErrDuplicateField, fldName.String())
+	}
+
+	newSch := domain.NewSchema()
+	for _, fld := range layout.Schema().Fields() {
+		newSch.Add(fld, layout.Schema())
+		newSch.SetFieldConstraint(fld, layout.Schema().FieldConstraint(fld))
+	}
+	newSch.Add(fldName, sch)
+	newSch.SetFieldConstraint(fldName, sch.FieldConstraint(fldName))
+
+	if err := mgr.tblMgr.RedefineTable(tblName, newSch, txn); err != nil {
+		return errors.Err(err, "RedefineTable")
+	}
+	mgr.statMgr.Invalidate(tblName)
+
+	return nil
+}
+
+// DropField drops the field from the table with the indexes and constraints containing it.
+// 他の field の CHECK 制約や, 残る foreign key から参照されている field は削除できない.
+func (mgr *Manager) DropField(tblName domain.TableName, fldName domain.FieldName, txn domain.Transaction) error {
+	layout, err := mgr.alterableLayout(tblName, txn)
+	if err != nil {
+		return errors.Err(err, "alterableLayout")
+	}
+	sch := layout.Schema()
+	if !sch.HasField(fldName) {
+		return errors.Wrap(domain.ErrFieldNotFound, fldName.String())
+	}
+	if len(sch.Fields()) == 1 {
+		return errors.Wrap(domain.ErrDropLastField, tblName.String())
+	}
+
+	newSch := domain.NewSchema()
+	for _, fld := range sch.Fields() {
+		if fld == fldName {
+			continue
+		}
+		if pred, ok := sch.FieldConstraint(fld).Check(); ok && checkRefersField(pred, fldName) {
+			return errors.Wrap(domain.ErrDependentObjectsExist, fmt.Sprintf("check constraint of %v depends on %v", fld, fldName))
+		}
+		newSch.Add(fld, sch)
+		newSch.SetFieldConstraint(fld, sch.FieldConstraint(fld))
+	}
+
+	if err := mgr.dropFieldConstraints(tblName, fldName, txn); err != nil {
+		return errors.Err(err, "dropFieldConstraints")
+	}
+
+	infos, err := mgr.idxMgr.GetIndexInfo(tblName, txn)
+	if err != nil {
+		return errors.Err(err, "GetIndexInfo")
+	}
+	for _, info := range infos {
+		if !containsField(info.FieldNames(), fldName) {
+			continue
+		}
+		if err := mgr.idxMgr.DropIndex(info.IndexName(), txn); err != nil {
+			return errors.Err(err, "DropIndex")
+		}
+	}
+
+	if err := mgr.tblMgr.RedefineTable(tblName, newSch, txn); err != nil {
+		return errors.Err(err, "RedefineTable")
+	}
+	mgr.statMgr.Invalidate(tblName)
+
+	return nil
+}
+
+// dropFieldConstraints drops the constraints containing the field.
+// 削除されない foreign key が参照している制約があれば error を返す.
+func (mgr *Manager) dropFieldConstraints(tblName domain.TableName, fldName domain.FieldName, txn domain.Transaction) error {
+	cons, err := mgr.conMgr.GetConstraints(tblName, txn)
+	if err != nil {
+		return errors.Err(err, "GetConstraints")
+	}
+
+	refs, err := mgr.conMgr.GetReferencingTables(tblName, txn)
+	if err != nil {
+		return errors.Err(err, "GetReferencingTables")
+	}
+	for _, ref := range refs {
+		refCons, err := mgr.conMgr.GetConstraints(ref, txn)
+		if err != nil {
+			return errors.Err(err, "GetConstraints")
+		}
+		for _, fk := range refCons {
+			if fk.Type() != domain.ForeignKeyConstraint || fk.RefTableName() != tblName {
+				continue
+			}
+			if ref == tblName && containsField(fk.FieldNames(), fldName) {
+				continue
+			}
+			for _, c := range cons {
+				if c.Name() == fk.RefName() && containsField(c.FieldNames(), fldName) {
+					return errors.Wrap(domain.ErrDependentObjectsExist, fmt.Sprintf("constraint %v on table %v depends on %v", fk.Name(), ref, fldName))
+				}
+			}
+		}
+	}
+
+	for _, c := range cons {
+		if !containsField(c.FieldNames(), fldName) {
+			continue
+		}
+		if err := mgr.conMgr.DropConstraint(c.Name(), txn); err != nil {
+			return errors.Err(err, "DropConstraint")
+		}
+	}
+
+	return nil
+}
+
+// RenameField renames the field of the table.
+// layout は変わらないので, 既存の record を書き直す必要はない.
+func (mgr *Manager) RenameField(tblName domain.TableName, oldName, newName domain.FieldName, txn domain.Transaction) error {
+	layout, err := mgr.alterableLayout(tblName, txn)
+	if err != nil {
+		return errors.Err(err, "alterableLayout")
+	}
+	sch := layout.Schema()
+	if !sch.HasField(oldName) {
+		return errors.Wrap(domain.ErrFieldNotFound, oldName.String())
+	}
+	if sch.HasField(newName) {
+		return errors.Wrap(domain.ErrDuplicateField, newName.String())
+	}
+
+	newSch := domain.NewSchema()
+	for _, fld := range sch.Fields() {
+		name := fld
+		if fld == oldName {
+			name = newName
+		}
+		newSch.AddField(name, sch.Type(fld), sch.Length(fld))
+		newSch.SetFieldConstraint(name, sch.FieldConstraint(fld).RenameField(oldName, newName))
+	}
+
+	if err := mgr.tblMgr.RedefineTable(tblName, newSch, txn); err != nil {
+		return errors.Err(err, "RedefineTable")
+	}
+	if err := mgr.idxMgr.RenameField(tblName, oldName, newName, txn); err != nil {
+		return errors.Err(err, "RenameField")
+	}
+	mgr.statMgr.Invalidate(tblName)
+
+	return nil
+}
+
+// RenameTable renames the table in all catalogs.
+// file の移動は planner が行う.
+func (mgr *Manager) RenameTable(tblName, newName domain.TableName, txn domain.Transaction) error {
+	if _, err := mgr.alterableLayout(tblName, txn); err != nil {
+		return errors.Err(err, "alterableLayout")
+	}
+	if mgr.tblMgr.Exists(newName, txn) || isCatalog(newName) {
+		return fmt.Errorf("relation \"%v\" already exists", newName)
+	}
+
+	if err := mgr.tblMgr.RenameTable(tblName, newName, txn); err != nil {
+		return errors.Err(err, "RenameTable")
+	}
+	if err := mgr.idxMgr.RenameTable(tblName, newName, txn); err != nil {
+		return errors.Err(err, "RenameTable")
+	}
+	if err := mgr.conMgr.RenameTable(tblName, newName, txn); err != nil {
+		return errors.Err(err, "RenameTable")
+	}
+	mgr.statMgr.Invalidate(tblName)
+	mgr.statMgr.Invalidate(newName)
+
+	return nil
+}
+
+// alterableLayout returns the layout of the table which can be altered.
+func (mgr *Manager) alterableLayout(tblName domain.TableName, txn domain.Transaction) (*domain.Layout, error) {
+	if isCatalog(tblName) {
+		return nil, errors.Wrap(domain.ErrAlterCatalog, tblName.String())
+	}
+	if !mgr.tblMgr.Exists(tblName, txn) {
+		return nil, errors.Wrap(domain.ErrTableNotFound, tblName.String())
+	}
+
+	return mgr.tblMgr.GetTableLayout(tblName, txn)
+}
+
+// checkRefersField checks whether the CHECK constraint pred refers the field.
+func checkRefersField(pred *domain.Predicate, fldName domain.FieldName) bool {
+	for _, term := range pred.Terms() {
+		if containsField(term.Fields(), fldName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsField(flds []domain.FieldName, fldName domain.FieldName) bool {
+	for _, fld := range flds {
+		if fld == fldName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isCatalog(tblName domain.TableName) bool {
+	switch tblName {
+	case tableCatalog, fieldCatalog, fldIndexCatalog, constraintCatalog, fldViewCatalog:
+		return true
+	default:
+		return false
+	}
+}
@@ -24,7 +24,11 @@ func TestMetadataManager(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	cal := mock.NewMockSearchCostCalculator(ctrl)
 	cal.EXPECT().Calculate(gomock.Any(), gomock.Any()).Return(0).AnyTimes()
-	idxDriver := domain.NewIndexDriver(mock.NewMockIndexFactory(ctrl), cal)
+	btreeFty := mock.NewMockIndexFactory(ctrl)
+	btreeFty.EXPECT().SupportsRange().Return(true).AnyTimes()
+	hashFty := mock.NewMockIndexFactory(ctrl)
+	hashFty.EXPECT().SupportsRange().Return(false).AnyTimes()
+	idxDriver := domain.NewIndexDriver(btreeFty, cal).Register(domain.HashIndexType, hashFty, cal)
 	metaMgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
 	require.NoError(t, err)
 
@@ -34,7 +38,7 @@ func TestMetadataManager(t *testing.T) {
 
 	// table metadata
 	txn := cr.NewTxn()
-	metaMgr.CreateTable("MyTable", sch, txn)
+	metaMgr.CreateTable("MyTable", sch, domain.FixedRecordFormat, txn)
 	layout, err := metaMgr.GetTableLayout("MyTable", txn)
 	require.NoError(t, err)
 	sch2 := layout.Schema()
@@ -44,7 +48,7 @@ func TestMetadataManager(t *testing.T) {
 		types = append(types, sch.Type(fld))
 	}
 	require.Equal(t, []domain.FieldType{domain.Int32FieldType, domain.StringFieldType}, types)
-	require.Equal(t, int64(21), layout.SlotSize())
+	require.Equal(t, int64(33), layout.SlotSize())
 
 	// Statistics Metadata
 	tbl, err := domain.NewTableScan(txn, "MyTable", layout)
@@ -63,7 +67,7 @@ func TestMetadataManager(t *testing.T) {
 	si, err := metaMgr.GetStatInfo("MyTable", layout, txn)
 	require.NoError(t, err)
 
-	require.Equal(t, 3, si.EstNumBlocks())
+	require.Equal(t, 5, si.EstNumBlocks())
 	require.Equal(t, 50, si.EstNumRecord())
 	require.Equal(t, 1+50/3, si.EstDistinctVals("A"))
 	require.Equal(t, 1+50/3, si.EstDistinctVals("B"))
@@ -77,26 +81,73 @@ func TestMetadataManager(t *testing.T) {
 	require.Equal(t, viewDef, gotViewDef)
 
 	// index metadata
-	err = metaMgr.CreateIndex("indexA", "MyTable", "A", txn)
+	err = metaMgr.CreateIndex("indexA", "MyTable", []domain.FieldName{"A"}, domain.BTreeIndexType, txn)
 	require.NoError(t, err)
-	err = metaMgr.CreateIndex("indexB", "MyTable", "B", txn)
+	err = metaMgr.CreateIndex("indexB", "MyTable", []domain.FieldName{"B"}, domain.HashIndexType, txn)
 	require.NoError(t, err)
+	err = metaMgr.CreateIndex("indexC", "MyTable", []domain.FieldName{"A"}, "gist", txn)
+	require.ErrorIs(t, err, domain.ErrUnknownIndexType)
 	idxMap, err := metaMgr.GetIndexInfo("MyTable", txn)
+	require.NoError(t, err)
+	require.Len(t, idxMap, 2)
 
-	ii, found := idxMap["A"]
-	require.True(t, found)
+	ii := idxMap[0]
+	require.Equal(t, domain.IndexName("indexA"), ii.IndexName())
+	require.Equal(t, []domain.FieldName{"A"}, ii.FieldNames())
 	require.Equal(t, 0, ii.EstBlockAccessed())
 	require.Equal(t, 2, ii.EstNumRecord())
 	require.Equal(t, 1, ii.EstDistinctVals("A"))
 	require.Equal(t, 17, ii.EstDistinctVals("B"))
+	require.Equal(t, domain.BTreeIndexType, ii.IndexType())
+	require.True(t, ii.SupportsRange())
 
-	ii2, found := idxMap["B"]
-	require.True(t, found)
+	ii2 := idxMap[1]
+	require.Equal(t, domain.IndexName("indexB"), ii2.IndexName())
+	require.Equal(t, []domain.FieldName{"B"}, ii2.FieldNames())
 	require.Equal(t, 0, ii2.EstBlockAccessed())
 	require.Equal(t, 2, ii2.EstNumRecord())
 	require.Equal(t, 17, ii2.EstDistinctVals("A"))
 	require.Equal(t, 1, ii2.EstDistinctVals("B"))
+	require.Equal(t, domain.HashIndexType, ii2.IndexType())
+	require.False(t, ii2.SupportsRange())
 
 	err = txn.Commit()
 	require.NoError(t, err)
 }
+
+func TestMetadataManager_without_constraint_catalog(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 8
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	ctrl := gomock.NewController(t)
+	cal := mock.NewMockSearchCostCalculator(ctrl)
+	fty := mock.NewMockIndexFactory(ctrl)
+	idxDriver := domain.NewIndexDriver(fty, cal)
+	metaMgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	// 制約が導入される前の database を再現する.
+	txn := cr.NewTxn()
+	require.NoError(t, metaMgr.DropCatalog("con_catalog", txn))
+	require.NoError(t, txn.Commit())
+
+	metaMgr, err = metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	sch := domain.NewSchema()
+	sch.AddInt32Field("A")
+
+	txn = cr.NewTxn()
+	require.NoError(t, metaMgr.CreateTable("MyTable", sch, domain.FixedRecordFormat, txn))
+	require.NoError(t, metaMgr.CreateConstraint("MyTable", domain.NewConstraint(domain.PrimaryKeyConstraint, []domain.FieldName{"A"}), txn))
+	cons, err := metaMgr.GetConstraints("MyTable", txn)
+	require.NoError(t, err)
+	require.Len(t, cons, 1)
+	require.Equal(t, []domain.FieldName{"A"}, cons[0].FieldNames())
+	require.NoError(t, txn.Commit())
+}
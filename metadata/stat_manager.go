@@ -58,6 +58,14 @@ func (statMgr *StatManager) GetStatInfo(tblName domain.TableName, layout *domain
 	return si, nil
 }
 
+// Invalidate discards the cached statistics of given table.
+func (statMgr *StatManager) Invalidate(tblName domain.TableName) {
+	statMgr.mu.Lock()
+	defer statMgr.mu.Unlock()
+
+	delete(statMgr.tableStats, tblName)
+}
+
 func (statMgr *StatManager) refreshStatistics(txn domain.Transaction) error {
 	statMgr.tableStats = make(map[domain.TableName]domain.StatInfo)
 	statMgr.numCalls = 0
@@ -110,6 +118,7 @@ func (statMgr *StatManager) calcTableStats(tblName domain.TableName, layout *dom
 	if err != nil {
 		return domain.StatInfo{}, errors.Err(err, "NewTableScan")
 	}
+	defer tbl.Close()
 
 	for tbl.HasNext() {
 		numRecs++
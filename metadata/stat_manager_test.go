@@ -29,7 +29,7 @@ func TestStatManager(t *testing.T) {
 		sch.AddStringField("B", 10)
 
 		tblName := domain.TableName(fake.RandString())
-		err = tblMgr.CreateTable(tblName, sch, txn)
+		err = tblMgr.CreateTable(tblName, sch, domain.FixedRecordFormat, txn)
 		require.NoError(t, err)
 
 		tblLayout, err := tblMgr.GetTableLayout(tblName, txn)
@@ -119,7 +119,7 @@ func TestStatManager_Error(t *testing.T) {
 		sch.AddStringField("B", 10)
 
 		tblName := domain.TableName(fake.RandString())
-		err = tblMgr.CreateTable(tblName, sch, txn)
+		err = tblMgr.CreateTable(tblName, sch, domain.FixedRecordFormat, txn)
 		require.NoError(t, err)
 
 		tblLayout, err := tblMgr.GetTableLayout(tblName, txn)
@@ -144,7 +144,7 @@ func TestStatManager_Error(t *testing.T) {
 		sch2.AddInt32Field("AA")
 		sch2.AddStringField("BB", 10)
 		tblName2 := domain.TableName(fake.RandString())
-		err = tblMgr.CreateTable(tblName2, sch2, txn31)
+		err = tblMgr.CreateTable(tblName2, sch2, domain.FixedRecordFormat, txn31)
 		require.NoError(t, err)
 		err = txn31.Commit()
 		require.NoError(t, err)
@@ -1,10 +1,12 @@
 package metadata
 
 import (
-	"fmt"
+	"strings"
 
 	"github.com/goropikari/simpledbgo/domain"
 	"github.com/goropikari/simpledbgo/errors"
+	"github.com/goropikari/simpledbgo/lexer"
+	"github.com/goropikari/simpledbgo/parser"
 )
 
 // TableManager is manager of table.
@@ -18,6 +20,7 @@ func NewTableManager() *TableManager {
 	tblCatalogSchema := domain.NewSchema()
 	tblCatalogSchema.AddStringField(domain.FieldName(fldTableName), domain.MaxTableNameLength)
 	tblCatalogSchema.AddInt32Field(domain.FieldName(fldSlotSize))
+	tblCatalogSchema.AddStringField(fldFormat, domain.MaxRecordFormatLength)
 	tblCatalogLayout := domain.NewLayout(tblCatalogSchema)
 
 	fldCatalogSchema := domain.NewSchema()
@@ -26,6 +29,9 @@ func NewTableManager() *TableManager {
 	fldCatalogSchema.AddInt32Field(fldType)
 	fldCatalogSchema.AddInt32Field(fldLength)
 	fldCatalogSchema.AddInt32Field(fldOffset)
+	fldCatalogSchema.AddInt32Field(fldNotNull)
+	fldCatalogSchema.AddStringField(fldDefault, domain.MaxDefaultValueLength)
+	fldCatalogSchema.AddStringField(fldCheck, domain.MaxCheckDefLength)
 	fldCatalogLayout := domain.NewLayout(fldCatalogSchema)
 
 	return &TableManager{
@@ -37,11 +43,11 @@ func NewTableManager() *TableManager {
 // CreateTableManager creates table manager and table catalog.
 func CreateTableManager(txn domain.Transaction) (*TableManager, error) {
 	tblMgr := NewTableManager()
-	if err := tblMgr.CreateTable(tableCatalog, tblMgr.tblCatalogLayout.Schema(), txn); err != nil {
-		return nil, errors.Err(err, "CreateTable")
+	if err := tblMgr.createCatalog(tableCatalog, tblMgr.tblCatalogLayout.Schema(), txn); err != nil {
+		return nil, errors.Err(err, "createCatalog")
 	}
-	if err := tblMgr.CreateTable(fieldCatalog, tblMgr.fldCatalogLayout.Schema(), txn); err != nil {
-		return nil, errors.Err(err, "CreateTable")
+	if err := tblMgr.createCatalog(fieldCatalog, tblMgr.fldCatalogLayout.Schema(), txn); err != nil {
+		return nil, errors.Err(err, "createCatalog")
 	}
 
 	return tblMgr, nil
@@ -57,9 +63,26 @@ func CreateTableManager(txn domain.Transaction) (*TableManager, error) {
 // 	return tblMgr.fldCatalogLayout
 // }
 
-// CreateTable create a table.
-func (tblMgr *TableManager) CreateTable(tblName domain.TableName, sch *domain.Schema, txn domain.Transaction) error {
-	layout := domain.NewLayout(sch)
+// CreateTable create a table whose records are stored in the format.
+// record には multi-version mode のための xmin と xmax を持たせる.
+func (tblMgr *TableManager) CreateTable(tblName domain.TableName, sch *domain.Schema, format domain.RecordFormat, txn domain.Transaction) error {
+	return tblMgr.createTable(tblName, newLayout(sch, tableFormat{format: format, versioned: true}), txn)
+}
+
+// createCatalog creates a catalog table.
+// catalog の record は version を持たず, 常に最新のものを読み書きする.
+func (tblMgr *TableManager) createCatalog(tblName domain.TableName, sch *domain.Schema, txn domain.Transaction) error {
+	return tblMgr.createTable(tblName, domain.NewLayout(sch), txn)
+}
+
+// createTable registers the table of the layout in the table and field catalogs.
+func (tblMgr *TableManager) createTable(tblName domain.TableName, layout *domain.Layout, txn domain.Transaction) error {
+	sch := layout.Schema()
+	for _, fld := range sch.Fields() {
+		if err := validateFieldConstraint(sch, fld); err != nil {
+			return errors.Err(err, "validateFieldConstraint")
+		}
+	}
 
 	// register table
 	tcat, err := domain.NewTableScan(txn, tableCatalog, tblMgr.tblCatalogLayout)
@@ -76,6 +99,9 @@ func (tblMgr *TableManager) CreateTable(tblName domain.TableName, sch *domain.Sc
 	if err := tcat.SetInt32(fldSlotSize, int32(layout.SlotSize())); err != nil {
 		return errors.Err(err, "SetInt32")
 	}
+	if err := tcat.SetString(fldFormat, catalogFormat(layout)); err != nil {
+		return errors.Err(err, "SetString")
+	}
 	tcat.Close()
 
 	// register fields
@@ -103,6 +129,9 @@ func (tblMgr *TableManager) CreateTable(tblName domain.TableName, sch *domain.Sc
 		if err := fcat.SetInt32(fldOffset, int32(layout.Offset(fld))); err != nil {
 			return errors.Err(err, "SetInt32")
 		}
+		if err := tblMgr.setFieldConstraint(fcat, sch.FieldConstraint(fld)); err != nil {
+			return errors.Err(err, "setFieldConstraint")
+		}
 	}
 	fcat.Close()
 
@@ -111,7 +140,7 @@ func (tblMgr *TableManager) CreateTable(tblName domain.TableName, sch *domain.Sc
 
 // GetTableLayout returns the layout of given table name.
 func (tblMgr *TableManager) GetTableLayout(tblName domain.TableName, txn domain.Transaction) (*domain.Layout, error) {
-	slotsize, err := tblMgr.tableSlotSize(tblName, txn)
+	slotsize, tf, err := tblMgr.tableSlotSize(tblName, txn)
 	if err != nil {
 		return nil, errors.Err(err, "tableSlotSize")
 	}
@@ -121,7 +150,206 @@ func (tblMgr *TableManager) GetTableLayout(tblName domain.TableName, txn domain.
 		return nil, errors.Err(err, "tableSchema")
 	}
 
-	return domain.NewLayoutWithFields(sch, offsets, int64(slotsize)), nil
+	switch {
+	case tf.legacy:
+		return domain.NewLegacyLayout(sch, offsets, int64(slotsize)), nil
+	case tf.format == domain.SlottedRecordFormat:
+		return newLayout(sch, tf), nil
+	default:
+		return domain.NewLayoutWithFields(sch, offsets, int64(slotsize), tf.versioned), nil
+	}
+}
+
+// RecordFormat returns the record format of the table.
+func (tblMgr *TableManager) RecordFormat(tblName domain.TableName, txn domain.Transaction) (domain.RecordFormat, error) {
+	_, tf, err := tblMgr.tableSlotSize(tblName, txn)
+	if err != nil {
+		return "", errors.Err(err, "tableSlotSize")
+	}
+
+	return tf.format, nil
+}
+
+// tableFormat is the record format of a table stored in table catalog.
+type tableFormat struct {
+	format    domain.RecordFormat
+	versioned bool
+	legacy    bool
+}
+
+// newLayout constructs the layout of sch in tf.
+// legacy な layout は catalog に保存された offset が無いと作れないので, ここでは作らない.
+func newLayout(sch *domain.Schema, tf tableFormat) *domain.Layout {
+	switch {
+	case tf.format == domain.SlottedRecordFormat && tf.versioned:
+		return domain.NewVersionedSlottedLayout(sch)
+	case tf.format == domain.SlottedRecordFormat:
+		return domain.NewSlottedLayout(sch)
+	case tf.versioned:
+		return domain.NewVersionedLayout(sch)
+	default:
+		return domain.NewLayout(sch)
+	}
+}
+
+// catalogFormat returns the record format of the layout stored in table catalog.
+// version を持つ layout は format に versionedFormatSuffix を, null bitmap を持たない layout は legacyFormatSuffix を付ける.
+func catalogFormat(layout *domain.Layout) string {
+	switch {
+	case layout.Versioned():
+		return layout.Format().String() + versionedFormatSuffix
+	case layout.Legacy():
+		return layout.Format().String() + legacyFormatSuffix
+	default:
+		return layout.Format().String()
+	}
+}
+
+// parseTableFormat parses the record format stored in table catalog.
+func parseTableFormat(s string) (tableFormat, error) {
+	tf := tableFormat{}
+	switch {
+	case strings.HasSuffix(s, versionedFormatSuffix):
+		tf.versioned = true
+		s = strings.TrimSuffix(s, versionedFormatSuffix)
+	case strings.HasSuffix(s, legacyFormatSuffix):
+		tf.legacy = true
+		s = strings.TrimSuffix(s, legacyFormatSuffix)
+	}
+
+	format, err := domain.NewRecordFormat(s)
+	if err != nil {
+		return tableFormat{}, errors.Err(err, "NewRecordFormat")
+	}
+	tf.format = format
+
+	return tf, nil
+}
+
+// DropTable removes the table from the catalogs and removes its file when the transaction is committed.
+func (tblMgr *TableManager) DropTable(tblName domain.TableName, txn domain.Transaction) error {
+	n, err := deleteCatalogRecords(txn, tableCatalog, tblMgr.tblCatalogLayout, fldTableName, tblName.String())
+	if err != nil {
+		return errors.Err(err, "deleteCatalogRecords")
+	}
+	if n == 0 {
+		return errors.Wrap(domain.ErrTableNotFound, tblName.String())
+	}
+
+	if _, err := deleteCatalogRecords(txn, fieldCatalog, tblMgr.fldCatalogLayout, fldTableName, tblName.String()); err != nil {
+		return errors.Err(err, "deleteCatalogRecords")
+	}
+
+	for _, filename := range []domain.FileName{tblName.ToFileName(), tblName.ToOverflowFileName()} {
+		if err := txn.RemoveFile(filename); err != nil {
+			return errors.Err(err, "RemoveFile")
+		}
+	}
+
+	return nil
+}
+
+// RedefineTable replaces the definition of the table in the catalogs with sch.
+// layout は sch から計算し直すので, 既存の record は呼び出し側で書き直す.
+// record format と version の有無は元の table のものを引き継ぐ.
+// record は全て書き直されるので, null bitmap の無い legacy な table も null bitmap を持つ layout にする.
+func (tblMgr *TableManager) RedefineTable(tblName domain.TableName, sch *domain.Schema, txn domain.Transaction) error {
+	_, tf, err := tblMgr.tableSlotSize(tblName, txn)
+	if err != nil {
+		return errors.Err(err, "tableSlotSize")
+	}
+	tf.legacy = false
+
+	n, err := deleteCatalogRecords(txn, tableCatalog, tblMgr.tblCatalogLayout, fldTableName, tblName.String())
+	if err != nil {
+		return errors.Err(err, "deleteCatalogRecords")
+	}
+	if n == 0 {
+		return errors.Wrap(domain.ErrTableNotFound, tblName.String())
+	}
+
+	if _, err := deleteCatalogRecords(txn, fieldCatalog, tblMgr.fldCatalogLayout, fldTableName, tblName.String()); err != nil {
+		return errors.Err(err, "deleteCatalogRecords")
+	}
+
+	return tblMgr.createTable(tblName, newLayout(sch, tf), txn)
+}
+
+// RenameTable renames the table in the table and field catalogs.
+func (tblMgr *TableManager) RenameTable(tblName, newName domain.TableName, txn domain.Transaction) error {
+	n, err := updateCatalogRecords(txn, tableCatalog, tblMgr.tblCatalogLayout, fldTableName, tblName.String(), newName.String())
+	if err != nil {
+		return errors.Err(err, "updateCatalogRecords")
+	}
+	if n == 0 {
+		return errors.Wrap(domain.ErrTableNotFound, tblName.String())
+	}
+
+	if _, err := updateCatalogRecords(txn, fieldCatalog, tblMgr.fldCatalogLayout, fldTableName, tblName.String(), newName.String()); err != nil {
+		return errors.Err(err, "updateCatalogRecords")
+	}
+
+	return nil
+}
+
+// deleteCatalogRecords deletes the records of the catalog whose fld is name and returns the number of deleted records.
+// catalog の削除も log に残るので, rollback すれば元に戻る.
+func deleteCatalogRecords(txn domain.Transaction, catalog domain.TableName, layout *domain.Layout, fld domain.FieldName, name string) (int, error) {
+	cat, err := domain.NewTableScan(txn, catalog, layout)
+	if err != nil {
+		return 0, errors.Err(err, "NewTableScan")
+	}
+	defer cat.Close()
+
+	cnt := 0
+	for cat.HasNext() {
+		v, err := cat.GetString(fld)
+		if err != nil {
+			return 0, errors.Err(err, "GetString")
+		}
+		if v != name {
+			continue
+		}
+		if err := cat.Delete(); err != nil {
+			return 0, errors.Err(err, "Delete")
+		}
+		cnt++
+	}
+	if err := cat.Err(); err != nil {
+		return 0, errors.Err(err, "HasNext")
+	}
+
+	return cnt, nil
+}
+
+// updateCatalogRecords replaces fld of the records of the catalog whose fld is oldName with newName
+// and returns the number of updated records.
+func updateCatalogRecords(txn domain.Transaction, catalog domain.TableName, layout *domain.Layout, fld domain.FieldName, oldName, newName string) (int, error) {
+	cat, err := domain.NewTableScan(txn, catalog, layout)
+	if err != nil {
+		return 0, errors.Err(err, "NewTableScan")
+	}
+	defer cat.Close()
+
+	cnt := 0
+	for cat.HasNext() {
+		v, err := cat.GetString(fld)
+		if err != nil {
+			return 0, errors.Err(err, "GetString")
+		}
+		if v != oldName {
+			continue
+		}
+		if err := cat.SetString(fld, newName); err != nil {
+			return 0, errors.Err(err, "SetString")
+		}
+		cnt++
+	}
+	if err := cat.Err(); err != nil {
+		return 0, errors.Err(err, "HasNext")
+	}
+
+	return cnt, nil
 }
 
 // Exists checks the existence of table.
@@ -148,39 +376,53 @@ func (tblMgr *TableManager) Exists(tblName domain.TableName, txn domain.Transact
 	return false
 }
 
-func (tblMgr *TableManager) tableSlotSize(tblName domain.TableName, txn domain.Transaction) (int32, error) {
+// tableSlotSize returns the slot size and the record format of the table.
+// format が NULL の table は fixed として扱う.
+// versionedFormatSuffix の無い format の table は, record が version を持たなかった頃に作られたものとして扱う.
+func (tblMgr *TableManager) tableSlotSize(tblName domain.TableName, txn domain.Transaction) (int32, tableFormat, error) {
 	const NonExistSlotSize = -1
 
 	tcat, err := domain.NewTableScan(txn, tableCatalog, tblMgr.tblCatalogLayout)
 	if err != nil {
-		return NonExistSlotSize, errors.Err(err, "NewTableScan")
+		return NonExistSlotSize, tableFormat{}, errors.Err(err, "NewTableScan")
 	}
 	defer tcat.Close()
 
 	slotsize := int32(NonExistSlotSize)
+	tf := tableFormat{format: domain.FixedRecordFormat}
 	for tcat.HasNext() {
 		v, err := tcat.GetString(fldTableName)
 		if err != nil {
-			return NonExistSlotSize, errors.Err(err, "GetString")
+			return NonExistSlotSize, tableFormat{}, errors.Err(err, "GetString")
 		}
 		if v == tblName.String() {
 			slotsize, err = tcat.GetInt32(fldSlotSize)
 			if err != nil {
-				return NonExistSlotSize, errors.Err(err, "GetInt32")
+				return NonExistSlotSize, tableFormat{}, errors.Err(err, "GetInt32")
+			}
+			fmtVal, err := tcat.GetVal(fldFormat)
+			if err != nil {
+				return NonExistSlotSize, tableFormat{}, errors.Err(err, "GetVal")
+			}
+			if !fmtVal.IsNull() && fmtVal.String() != "" {
+				tf, err = parseTableFormat(fmtVal.String())
+				if err != nil {
+					return NonExistSlotSize, tableFormat{}, errors.Err(err, "parseTableFormat")
+				}
 			}
 
 			break
 		}
 	}
 	if err := tcat.Err(); err != nil {
-		return NonExistSlotSize, errors.Err(err, "HasNext")
+		return NonExistSlotSize, tableFormat{}, errors.Err(err, "HasNext")
 	}
 
 	if slotsize <= 0 {
-		return NonExistSlotSize, fmt.Errorf("relation \"%v\" does not exist", tblName)
+		return NonExistSlotSize, tableFormat{}, errors.Wrap(domain.ErrTableNotFound, tblName.String())
 	}
 
-	return slotsize, nil
+	return slotsize, tf, nil
 }
 
 func (tblMgr *TableManager) tableSchema(tblName domain.TableName, txn domain.Transaction) (*domain.Schema, map[domain.FieldName]int64, error) {
@@ -223,6 +465,12 @@ func (tblMgr *TableManager) tableSchema(tblName domain.TableName, txn domain.Tra
 
 			fldType := domain.FieldType(typ)
 			sch.AddField(fldName, fldType, int(length))
+
+			c, err := tblMgr.fieldConstraint(fcat, fldType)
+			if err != nil {
+				return nil, nil, errors.Err(err, "fieldConstraint")
+			}
+			sch.SetFieldConstraint(fldName, c)
 		}
 	}
 	if err := fcat.Err(); err != nil {
@@ -231,3 +479,130 @@ func (tblMgr *TableManager) tableSchema(tblName domain.TableName, txn domain.Tra
 
 	return sch, offsets, nil
 }
+
+// setFieldConstraint writes NOT NULL, DEFAULT and CHECK constraints into the current record of field catalog.
+// DEFAULT と CHECK がない場合は NULL のままにする.
+func (tblMgr *TableManager) setFieldConstraint(fcat *domain.TableScan, c domain.FieldConstraint) error {
+	notNull := int32(0)
+	if c.NotNull() {
+		notNull = 1
+	}
+	if err := fcat.SetInt32(fldNotNull, notNull); err != nil {
+		return errors.Err(err, "SetInt32")
+	}
+
+	if dflt := c.Default(); !dflt.IsNull() {
+		if err := fcat.SetString(fldDefault, dflt.String()); err != nil {
+			return errors.Err(err, "SetString")
+		}
+	}
+
+	if pred, ok := c.Check(); ok {
+		if err := fcat.SetString(fldCheck, pred.String()); err != nil {
+			return errors.Err(err, "SetString")
+		}
+	}
+
+	return nil
+}
+
+// fieldConstraint reads NOT NULL, DEFAULT and CHECK constraints from the current record of field catalog.
+func (tblMgr *TableManager) fieldConstraint(fcat *domain.TableScan, typ domain.FieldType) (domain.FieldConstraint, error) {
+	c := domain.NewFieldConstraint()
+
+	notNull, err := fcat.GetInt32(fldNotNull)
+	if err != nil {
+		return c, errors.Err(err, "GetInt32")
+	}
+	if notNull != 0 {
+		c = c.WithNotNull()
+	}
+
+	dflt, err := fcat.GetVal(fldDefault)
+	if err != nil {
+		return c, errors.Err(err, "GetVal")
+	}
+	if !dflt.IsNull() {
+		val, err := decodeDefault(dflt, typ)
+		if err != nil {
+			return c, errors.Err(err, "decodeDefault")
+		}
+		c = c.WithDefault(val)
+	}
+
+	check, err := fcat.GetVal(fldCheck)
+	if err != nil {
+		return c, errors.Err(err, "GetVal")
+	}
+	if !check.IsNull() {
+		pred, err := parsePredicate(check.String())
+		if err != nil {
+			return c, errors.Err(err, "parsePredicate")
+		}
+		c = c.WithCheck(pred)
+	}
+
+	return c, nil
+}
+
+// validateFieldConstraint checks that constraints of the field can be stored in field catalog.
+func validateFieldConstraint(sch *domain.Schema, fld domain.FieldName) error {
+	c := sch.FieldConstraint(fld)
+
+	if dflt := c.Default(); !dflt.IsNull() {
+		if !sch.Type(fld).Accepts(dflt.Type()) {
+			return errors.Wrap(domain.ErrTypeMismatch, fld.String())
+		}
+		if _, err := dflt.ConvertTo(sch.Type(fld)); err != nil {
+			return errors.Err(err, "ConvertTo")
+		}
+		if sch.Type(fld) == domain.StringFieldType && len(dflt.String()) > sch.Length(fld) {
+			return errors.Wrap(domain.ErrConstraintTooLong, fld.String())
+		}
+		if sch.Type(fld) == domain.BytesFieldType {
+			if _, err := domain.ParseBytes(dflt.String()); err != nil {
+				return errors.Err(err, "ParseBytes")
+			}
+		}
+		if len(dflt.String()) > domain.MaxDefaultValueLength {
+			return errors.Wrap(domain.ErrConstraintTooLong, fld.String())
+		}
+	}
+
+	if pred, ok := c.Check(); ok {
+		for _, term := range pred.Terms() {
+			for _, f := range term.Fields() {
+				if !sch.HasField(f) {
+					return errors.Wrap(domain.ErrFieldNotFound, f.String())
+				}
+			}
+		}
+		if len(pred.String()) > domain.MaxCheckDefLength {
+			return errors.Wrap(domain.ErrConstraintTooLong, fld.String())
+		}
+	}
+
+	return nil
+}
+
+func decodeDefault(val domain.Constant, typ domain.FieldType) (domain.Constant, error) {
+	if typ == domain.StringFieldType || typ.IsOverflow() {
+		return val, nil
+	}
+
+	return domain.ParseConstant(typ, val.String())
+}
+
+func parsePredicate(def string) (*domain.Predicate, error) {
+	tokens, err := lexer.NewLexer(def).ScanTokens()
+	if err != nil {
+		return nil, errors.Err(err, "ScanTokens")
+	}
+
+	pred, err := parser.NewParser(tokens).Predicate()
+	if err != nil {
+		return nil, errors.Err(err, "Predicate")
+	}
+
+	return pred, nil
+}
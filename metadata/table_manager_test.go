@@ -28,13 +28,51 @@ func TestTableManager(t *testing.T) {
 		sch.AddStringField("B", 9)
 
 		tblName := domain.TableName(fake.RandString())
-		err = tblMgr.CreateTable(tblName, sch, txn)
+		err = tblMgr.CreateTable(tblName, sch, domain.FixedRecordFormat, txn)
 		require.NoError(t, err)
 
 		layout, err := tblMgr.GetTableLayout(tblName, txn)
 		require.NoError(t, err)
 
-		expected := domain.NewLayout(sch)
+		expected := domain.NewVersionedLayout(sch)
 		require.Equal(t, expected, layout)
 	})
+
+	t.Run("reads tables whose records have no version", func(t *testing.T) {
+		cr := fake.NewTransactionCreater(blockSize, numBuf)
+		defer cr.Finish()
+		txn := cr.NewTxn()
+
+		tblMgr, err := metadata.CreateTableManager(txn)
+		require.NoError(t, err)
+
+		sch := domain.NewSchema()
+		sch.AddInt32Field("A")
+		sch.AddStringField("B", 9)
+
+		// record が version を持たなかった頃と同じ format で catalog に登録する.
+		fixed := domain.TableName(fake.RandString())
+		err = tblMgr.CreateUnversionedTable(fixed, sch, domain.FixedRecordFormat, txn)
+		require.NoError(t, err)
+		slotted := domain.TableName(fake.RandString())
+		err = tblMgr.CreateUnversionedTable(slotted, sch, domain.SlottedRecordFormat, txn)
+		require.NoError(t, err)
+
+		layout, err := tblMgr.GetTableLayout(fixed, txn)
+		require.NoError(t, err)
+		require.Equal(t, domain.NewLayout(sch), layout)
+
+		layout, err = tblMgr.GetTableLayout(slotted, txn)
+		require.NoError(t, err)
+		require.Equal(t, domain.NewSlottedLayout(sch), layout)
+
+		// 定義を変えても version の有無は引き継ぐ.
+		sch.AddInt32Field("C")
+		err = tblMgr.RedefineTable(fixed, sch, txn)
+		require.NoError(t, err)
+
+		layout, err = tblMgr.GetTableLayout(fixed, txn)
+		require.NoError(t, err)
+		require.Equal(t, domain.NewLayout(sch), layout)
+	})
 }
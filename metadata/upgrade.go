@@ -0,0 +1,374 @@
+package metadata
+
+import (
+	"github.com/goropikari/simpledbgo/common"
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// legacyNameLength is the maximum length of names in the catalogs of version 0.
+const legacyNameLength = 16
+
+// legacyTable is a table registered in the catalogs of version 0.
+type legacyTable struct {
+	name   domain.TableName
+	layout *domain.Layout
+}
+
+// legacyView is a view registered in the catalogs of version 0.
+type legacyView struct {
+	name domain.ViewName
+	def  domain.ViewDef
+}
+
+// legacyIndex is an index registered in the catalogs of version 0.
+type legacyIndex struct {
+	name    domain.IndexName
+	tblName domain.TableName
+	fldName domain.FieldName
+}
+
+// catalogVersion returns the version of the catalog format of the database.
+func catalogVersion(txn domain.Transaction) (int32, error) {
+	size, err := txn.BlockLength(catalogVersionFile)
+	if err != nil {
+		return 0, errors.Err(err, "BlockLength")
+	}
+	if size == 0 {
+		return 0, nil
+	}
+
+	blk := domain.NewBlock(catalogVersionFile, 0)
+	if err := txn.Pin(blk); err != nil {
+		return 0, errors.Err(err, "Pin")
+	}
+	defer txn.Unpin(blk)
+
+	return txn.GetInt32(blk, 0)
+}
+
+// setCatalogVersion records that the catalogs are written in the current format.
+func setCatalogVersion(txn domain.Transaction) error {
+	size, err := txn.BlockLength(catalogVersionFile)
+	if err != nil {
+		return errors.Err(err, "BlockLength")
+	}
+
+	blk := domain.NewBlock(catalogVersionFile, 0)
+	if size == 0 {
+		blk, err = txn.ExtendFile(catalogVersionFile)
+		if err != nil {
+			return errors.Err(err, "ExtendFile")
+		}
+	}
+	if err := txn.Pin(blk); err != nil {
+		return errors.Err(err, "Pin")
+	}
+	defer txn.Unpin(blk)
+
+	return txn.SetInt32(blk, 0, currentCatalogVersion, true)
+}
+
+// upgradeCatalogs rewrites the catalogs of the older version in the current format.
+// version 0 の catalog は null bitmap も, 制約や index の種類の field も持たない.
+// user の table の record はそのまま残し, catalog には legacy な layout として登録する.
+// version 0 の index は中身を持たない dummy index なので, default の種類の index として作り直す.
+func upgradeCatalogs(driver domain.IndexDriver, txn domain.Transaction) error {
+	version, err := catalogVersion(txn)
+	if err != nil {
+		return errors.Err(err, "catalogVersion")
+	}
+	if version >= currentCatalogVersion {
+		return nil
+	}
+
+	tables, views, indexes, err := readLegacyCatalogs(txn)
+	if err != nil {
+		return errors.Err(err, "readLegacyCatalogs")
+	}
+
+	// 古い catalog の file を空にしてから, 今の format の catalog を作る.
+	for _, t := range tables {
+		if !isCatalog(t.name) {
+			continue
+		}
+		if err := clearTable(t, txn); err != nil {
+			return errors.Err(err, "clearTable")
+		}
+	}
+
+	tblMgr, err := CreateTableManager(txn)
+	if err != nil {
+		return errors.Err(err, "CreateTableManager")
+	}
+	for _, t := range tables {
+		if isCatalog(t.name) {
+			continue
+		}
+		if err := tblMgr.createTable(t.name, t.layout, txn); err != nil {
+			return errors.Err(err, "createTable")
+		}
+	}
+
+	viewMgr, err := CreateViewManager(tblMgr, txn)
+	if err != nil {
+		return errors.Err(err, "CreateViewManager")
+	}
+	for _, v := range views {
+		if err := viewMgr.CreateView(v.name, v.def, txn); err != nil {
+			return errors.Err(err, "CreateView")
+		}
+	}
+
+	statMgr, err := NewStatManager(tblMgr, txn)
+	if err != nil {
+		return errors.Err(err, "NewStatManager")
+	}
+
+	idxMgr, err := CreateIndexManager(driver, tblMgr, statMgr, txn)
+	if err != nil {
+		return errors.Err(err, "CreateIndexManager")
+	}
+	for _, idx := range indexes {
+		if err := idxMgr.CreateIndex(idx.name, idx.tblName, []domain.FieldName{idx.fldName}, domain.DefaultIndexType, txn); err != nil {
+			return errors.Err(err, "CreateIndex")
+		}
+		if err := fillIndex(idxMgr, tblMgr, idx, txn); err != nil {
+			return errors.Err(err, "fillIndex")
+		}
+	}
+
+	if _, err := CreateConstraintManager(tblMgr, idxMgr, txn); err != nil {
+		return errors.Err(err, "CreateConstraintManager")
+	}
+
+	return setCatalogVersion(txn)
+}
+
+// readLegacyCatalogs reads the tables, views and indexes from the catalogs of version 0.
+func readLegacyCatalogs(txn domain.Transaction) ([]legacyTable, []legacyView, []legacyIndex, error) {
+	tables, err := readLegacyTables(txn)
+	if err != nil {
+		return nil, nil, nil, errors.Err(err, "readLegacyTables")
+	}
+
+	layouts := make(map[domain.TableName]*domain.Layout, len(tables))
+	for _, t := range tables {
+		layouts[t.name] = t.layout
+	}
+
+	views := make([]legacyView, 0)
+	err = scanLegacyCatalog(txn, fldViewCatalog, layouts[fldViewCatalog], func(s domain.Scanner) error {
+		name, err := s.GetString(fldViewName)
+		if err != nil {
+			return errors.Err(err, "GetString")
+		}
+		def, err := s.GetString(fldViewDef)
+		if err != nil {
+			return errors.Err(err, "GetString")
+		}
+		views = append(views, legacyView{name: domain.ViewName(name), def: domain.NewViewDef(def)})
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, errors.Err(err, "scanLegacyCatalog")
+	}
+
+	indexes := make([]legacyIndex, 0)
+	err = scanLegacyCatalog(txn, fldIndexCatalog, layouts[fldIndexCatalog], func(s domain.Scanner) error {
+		idxName, err := s.GetString(fldIndexName)
+		if err != nil {
+			return errors.Err(err, "GetString")
+		}
+		tblName, err := s.GetString(fldTableName)
+		if err != nil {
+			return errors.Err(err, "GetString")
+		}
+		fldName, err := s.GetString(fldFieldName)
+		if err != nil {
+			return errors.Err(err, "GetString")
+		}
+		indexes = append(indexes, legacyIndex{
+			name:    domain.IndexName(idxName),
+			tblName: domain.TableName(tblName),
+			fldName: domain.FieldName(fldName),
+		})
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, nil, errors.Err(err, "scanLegacyCatalog")
+	}
+
+	return tables, views, indexes, nil
+}
+
+// readLegacyTables reads the layouts of all tables including catalogs from the catalogs of version 0.
+func readLegacyTables(txn domain.Transaction) ([]legacyTable, error) {
+	tblSch := domain.NewSchema()
+	tblSch.AddStringField(fldTableName, legacyNameLength)
+	tblSch.AddInt32Field(fldSlotSize)
+
+	fldSch := domain.NewSchema()
+	fldSch.AddStringField(fldTableName, legacyNameLength)
+	fldSch.AddStringField(fldFieldName, legacyNameLength)
+	fldSch.AddInt32Field(fldType)
+	fldSch.AddInt32Field(fldLength)
+	fldSch.AddInt32Field(fldOffset)
+
+	tables := make([]legacyTable, 0)
+	slotSizes := make(map[domain.TableName]int32)
+	err := scanLegacyCatalog(txn, tableCatalog, legacyCatalogLayout(tblSch), func(s domain.Scanner) error {
+		name, err := s.GetString(fldTableName)
+		if err != nil {
+			return errors.Err(err, "GetString")
+		}
+		size, err := s.GetInt32(fldSlotSize)
+		if err != nil {
+			return errors.Err(err, "GetInt32")
+		}
+		tables = append(tables, legacyTable{name: domain.TableName(name)})
+		slotSizes[domain.TableName(name)] = size
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Err(err, "scanLegacyCatalog")
+	}
+
+	schemas := make(map[domain.TableName]*domain.Schema, len(tables))
+	offsets := make(map[domain.TableName]map[domain.FieldName]int64, len(tables))
+	for _, t := range tables {
+		schemas[t.name] = domain.NewSchema()
+		offsets[t.name] = make(map[domain.FieldName]int64)
+	}
+	err = scanLegacyCatalog(txn, fieldCatalog, legacyCatalogLayout(fldSch), func(s domain.Scanner) error {
+		tblName, err := s.GetString(fldTableName)
+		if err != nil {
+			return errors.Err(err, "GetString")
+		}
+		fldName, err := s.GetString(fldFieldName)
+		if err != nil {
+			return errors.Err(err, "GetString")
+		}
+		typ, err := s.GetInt32(fldType)
+		if err != nil {
+			return errors.Err(err, "GetInt32")
+		}
+		length, err := s.GetInt32(fldLength)
+		if err != nil {
+			return errors.Err(err, "GetInt32")
+		}
+		offset, err := s.GetInt32(fldOffset)
+		if err != nil {
+			return errors.Err(err, "GetInt32")
+		}
+
+		sch, ok := schemas[domain.TableName(tblName)]
+		if !ok {
+			return nil
+		}
+		sch.AddField(domain.FieldName(fldName), domain.FieldType(typ), int(length))
+		offsets[domain.TableName(tblName)][domain.FieldName(fldName)] = int64(offset)
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Err(err, "scanLegacyCatalog")
+	}
+
+	for i, t := range tables {
+		tables[i].layout = domain.NewLegacyLayout(schemas[t.name], offsets[t.name], int64(slotSizes[t.name]))
+	}
+
+	return tables, nil
+}
+
+// legacyCatalogLayout computes the layout of the catalog of version 0 from its schema.
+// slot は usage flag の後に field を並べたもので, null bitmap を持たない.
+func legacyCatalogLayout(sch *domain.Schema) *domain.Layout {
+	pos := int64(domain.RecordOffset)
+	offsets := make(map[domain.FieldName]int64)
+	for _, fld := range sch.Fields() {
+		offsets[fld] = pos
+		pos += common.Int32Length
+		if sch.Type(fld) == domain.StringFieldType {
+			pos += int64(sch.Length(fld))
+		}
+	}
+
+	return domain.NewLegacyLayout(sch, offsets, pos)
+}
+
+// scanLegacyCatalog calls fn for each record of the catalog.
+// layout が無い場合は catalog が無いものとして何もしない.
+func scanLegacyCatalog(txn domain.Transaction, catalog domain.TableName, layout *domain.Layout, fn func(domain.Scanner) error) error {
+	if layout == nil {
+		return nil
+	}
+
+	ts, err := domain.NewTableScan(txn, catalog, layout)
+	if err != nil {
+		return errors.Err(err, "NewTableScan")
+	}
+	defer ts.Close()
+
+	for ts.HasNext() {
+		if err := fn(ts); err != nil {
+			return err
+		}
+	}
+	if err := ts.Err(); err != nil {
+		return errors.Err(err, "HasNext")
+	}
+
+	return nil
+}
+
+// clearTable deletes all records of the table.
+func clearTable(t legacyTable, txn domain.Transaction) error {
+	ts, err := domain.NewTableScan(txn, t.name, t.layout)
+	if err != nil {
+		return errors.Err(err, "NewTableScan")
+	}
+	defer ts.Close()
+
+	return ts.Clear()
+}
+
+// fillIndex inserts the entries of all records of the table into the index.
+func fillIndex(idxMgr *IndexManager, tblMgr *TableManager, idx legacyIndex, txn domain.Transaction) error {
+	layout, err := tblMgr.GetTableLayout(idx.tblName, txn)
+	if err != nil {
+		return errors.Err(err, "GetTableLayout")
+	}
+
+	infos, err := idxMgr.GetIndexInfo(idx.tblName, txn)
+	if err != nil {
+		return errors.Err(err, "GetIndexInfo")
+	}
+	for _, info := range infos {
+		if info.IndexName() != idx.name {
+			continue
+		}
+
+		indexer := info.Open()
+		defer indexer.Close()
+
+		return scanLegacyCatalog(txn, idx.tblName, layout, func(s domain.Scanner) error {
+			val, err := s.GetVal(idx.fldName)
+			if err != nil {
+				return errors.Err(err, "GetVal")
+			}
+			us, ok := s.(domain.UpdateScanner)
+			if !ok {
+				return errors.Wrap(domain.ErrTableNotFound, idx.tblName.String())
+			}
+
+			return indexer.Insert(domain.NewIndexKey([]domain.Constant{val}), us.RecordID())
+		})
+	}
+
+	return nil
+}
@@ -3,6 +3,8 @@ package metadata
 import (
 	"github.com/goropikari/simpledbgo/domain"
 	"github.com/goropikari/simpledbgo/errors"
+	"github.com/goropikari/simpledbgo/lexer"
+	"github.com/goropikari/simpledbgo/parser"
 )
 
 // ViewManager is manager of view.
@@ -23,8 +25,8 @@ func CreateViewManager(tblMgr *TableManager, txn domain.Transaction) (*ViewManag
 	sch := domain.NewSchema()
 	sch.AddStringField(fldViewName, domain.MaxTableNameLength)
 	sch.AddStringField(fldViewDef, domain.MaxViewDefLength)
-	if err := tblMgr.CreateTable(fldViewCatalog, sch, txn); err != nil {
-		return nil, errors.Err(err, "CreateTable")
+	if err := tblMgr.createCatalog(fldViewCatalog, sch, txn); err != nil {
+		return nil, errors.Err(err, "createCatalog")
 	}
 
 	return viewMgr, nil
@@ -55,6 +57,24 @@ func (viewMgr *ViewManager) CreateView(vName domain.ViewName, vDef domain.ViewDe
 	return nil
 }
 
+// DropView removes the definition of view.
+func (viewMgr *ViewManager) DropView(viewName domain.ViewName, txn domain.Transaction) error {
+	layout, err := viewMgr.tblMgr.GetTableLayout(fldViewCatalog, txn)
+	if err != nil {
+		return errors.Err(err, "GetTableLayout")
+	}
+
+	n, err := deleteCatalogRecords(txn, fldViewCatalog, layout, fldViewName, viewName.String())
+	if err != nil {
+		return errors.Err(err, "deleteCatalogRecords")
+	}
+	if n == 0 {
+		return errors.Wrap(domain.ErrViewNotFound, viewName.String())
+	}
+
+	return nil
+}
+
 // GetViewDef gets the definition of view.
 func (viewMgr *ViewManager) GetViewDef(viewName domain.ViewName, txn domain.Transaction) (domain.ViewDef, error) {
 	layout, err := viewMgr.tblMgr.GetTableLayout(fldViewCatalog, txn)
@@ -90,3 +110,63 @@ func (viewMgr *ViewManager) GetViewDef(viewName domain.ViewName, txn domain.Tran
 
 	return domain.NewViewDef(defStr), nil
 }
+
+// GetReferencingViews returns views which refer to given table.
+// view の定義を parse して, FROM 句と JOIN 句に現れる table を調べる.
+func (viewMgr *ViewManager) GetReferencingViews(tblName domain.TableName, txn domain.Transaction) ([]domain.ViewName, error) {
+	layout, err := viewMgr.tblMgr.GetTableLayout(fldViewCatalog, txn)
+	if err != nil {
+		return nil, errors.Err(err, "GetTableLayout")
+	}
+
+	tbl, err := domain.NewTableScan(txn, fldViewCatalog, layout)
+	if err != nil {
+		return nil, errors.Err(err, "NewTableScan")
+	}
+	defer tbl.Close()
+
+	views := make([]domain.ViewName, 0)
+	for tbl.HasNext() {
+		view, err := tbl.GetString(fldViewName)
+		if err != nil {
+			return nil, errors.Err(err, "GetString")
+		}
+		def, err := tbl.GetString(fldViewDef)
+		if err != nil {
+			return nil, errors.Err(err, "GetString")
+		}
+
+		tokens, err := lexer.NewLexer(def).ScanTokens()
+		if err != nil {
+			return nil, errors.Err(err, "ScanTokens")
+		}
+		data, err := parser.NewParser(tokens).Query()
+		if err != nil {
+			return nil, errors.Err(err, "Query")
+		}
+
+		if refersTable(data, tblName) {
+			views = append(views, domain.ViewName(view))
+		}
+	}
+	if err := tbl.Err(); err != nil {
+		return nil, errors.Err(err, "HasNext")
+	}
+
+	return views, nil
+}
+
+func refersTable(data *domain.QueryData, tblName domain.TableName) bool {
+	for _, name := range data.Tables() {
+		if name == tblName {
+			return true
+		}
+	}
+	for _, join := range data.Joins() {
+		if join.TableName() == tblName {
+			return true
+		}
+	}
+
+	return false
+}
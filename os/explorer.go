@@ -27,6 +27,17 @@ func NewExplorer(rootDir string, opener opener) *Explorer {
 		log.Fatal(err)
 	}
 
+	// 前回起動時に残った temporary table を削除する.
+	temps, err := filepath.Glob(filepath.Join(rootDir, domain.TempTablePrefix+"*"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, temp := range temps {
+		if err := os.Remove(temp); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	return &Explorer{
 		rootDir:   rootDir,
 		openFiles: make(map[domain.FileName]*domain.File),
@@ -52,3 +63,21 @@ func (exp *Explorer) OpenFile(filename domain.FileName) (*domain.File, error) {
 
 	return file, nil
 }
+
+// RemoveFile closes and removes a file.
+// 存在しない file の削除は成功とみなす.
+func (exp *Explorer) RemoveFile(filename domain.FileName) error {
+	if f, ok := exp.openFiles[filename]; ok {
+		if err := f.Close(); err != nil {
+			return errors.Err(err, "Close")
+		}
+		delete(exp.openFiles, filename)
+	}
+
+	path := filepath.Join(exp.rootDir, string(filename))
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.Err(err, "Remove")
+	}
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/goropikari/simpledbgo/domain"
 	"github.com/goropikari/simpledbgo/errors"
 	"github.com/goropikari/simpledbgo/lexer"
@@ -10,10 +13,12 @@ import (
 var ErrParse = errors.New("parse error")
 
 // Parser is a model of parser.
+// now は now() と current_date の値で, 1 つの文の中では同じ時刻を使う.
 type Parser struct {
 	tokens []lexer.Token
 	pos    int
 	len    int
+	now    time.Time
 }
 
 // NewParser constructs a Parser.
@@ -22,18 +27,32 @@ func NewParser(tokens []lexer.Token) *Parser {
 		tokens: tokens,
 		pos:    0,
 		len:    len(tokens),
+		now:    time.Now(),
 	}
 }
 
+var comparisonOperators = map[lexer.TokenType]domain.TermOperator{
+	lexer.TEqual:        domain.EqualOperator,
+	lexer.TNotEqual:     domain.NotEqualOperator,
+	lexer.TLess:         domain.LessOperator,
+	lexer.TLessEqual:    domain.LessEqualOperator,
+	lexer.TGreater:      domain.GreaterOperator,
+	lexer.TGreaterEqual: domain.GreaterEqualOperator,
+}
+
 func (parser *Parser) term() (domain.Term, error) {
 	lhs, err := parser.expression()
 	if err != nil {
 		return domain.Term{}, errors.Err(err, "expression")
 	}
 
-	err = parser.eatToken(lexer.TEqual)
+	if parser.matchKeyword("is") {
+		return parser.nullTest(lhs)
+	}
+
+	op, err := parser.comparisonOperator()
 	if err != nil {
-		return domain.Term{}, errors.Err(err, "eatToken")
+		return domain.Term{}, errors.Err(err, "comparisonOperator")
 	}
 
 	rhs, err := parser.expression()
@@ -41,7 +60,48 @@ func (parser *Parser) term() (domain.Term, error) {
 		return domain.Term{}, errors.Err(err, "expression")
 	}
 
-	return domain.NewTerm(lhs, rhs), nil
+	return domain.NewComparisonTerm(op, lhs, rhs), nil
+}
+
+// nullTest parses IS NULL or IS NOT NULL.
+func (parser *Parser) nullTest(expr domain.Expression) (domain.Term, error) {
+	err := parser.eatKeyword("is")
+	if err != nil {
+		return domain.Term{}, errors.Err(err, "eatKeyword")
+	}
+
+	not := parser.matchKeyword("not")
+	if not {
+		err = parser.eatKeyword("not")
+		if err != nil {
+			return domain.Term{}, errors.Err(err, "eatKeyword")
+		}
+	}
+
+	err = parser.eatKeyword("null")
+	if err != nil {
+		return domain.Term{}, errors.Err(err, "eatKeyword")
+	}
+
+	if not {
+		return domain.NewIsNotNullTerm(expr), nil
+	}
+
+	return domain.NewIsNullTerm(expr), nil
+}
+
+func (parser *Parser) comparisonOperator() (domain.TermOperator, error) {
+	if parser.pos >= parser.len {
+		return 0, ErrParse
+	}
+
+	op, ok := comparisonOperators[parser.tokens[parser.pos].Type()]
+	if !ok {
+		return 0, ErrParse
+	}
+	parser.pos++
+
+	return op, nil
 }
 
 // Query connstructs a query parse tree.
@@ -51,7 +111,7 @@ func (parser *Parser) Query() (*domain.QueryData, error) {
 		return nil, errors.Err(err, "eatKeyword")
 	}
 
-	fields, err := parser.selectList()
+	fields, aggs, exprs, err := parser.selectList()
 	if err != nil {
 		return nil, errors.Err(err, "selectList")
 	}
@@ -66,6 +126,11 @@ func (parser *Parser) Query() (*domain.QueryData, error) {
 		return nil, errors.Err(err, "tableList")
 	}
 
+	joins, err := parser.joinList()
+	if err != nil {
+		return nil, errors.Err(err, "joinList")
+	}
+
 	pred := &domain.Predicate{}
 	if parser.matchKeyword("where") {
 		err = parser.eatKeyword("where")
@@ -79,31 +144,298 @@ func (parser *Parser) Query() (*domain.QueryData, error) {
 		}
 	}
 
-	return domain.NewQueryData(fields, tables, pred), nil
+	data := domain.NewQueryData(fields, tables, pred)
+	if len(joins) > 0 {
+		data.SetJoins(joins)
+	}
+	if len(exprs) > 0 {
+		data.SetExpressions(exprs)
+	}
+
+	groupBy := make([]domain.FieldName, 0)
+	if parser.matchKeyword("group") {
+		groupBy, err = parser.groupBy()
+		if err != nil {
+			return nil, errors.Err(err, "groupBy")
+		}
+	}
+
+	if len(groupBy) > 0 || len(aggs) > 0 {
+		if err := checkGroupedFields(fields, exprs, groupBy, aggs); err != nil {
+			return nil, errors.Err(err, "checkGroupedFields")
+		}
+		data.SetGroupBy(groupBy)
+		data.SetAggregations(aggs)
+	}
+
+	if parser.matchKeyword("order") {
+		keys, err := parser.orderBy()
+		if err != nil {
+			return nil, errors.Err(err, "orderBy")
+		}
+		data.SetOrderBy(keys)
+	}
+
+	return data, nil
 }
 
-func (parser *Parser) selectList() ([]domain.FieldName, error) {
-	fields := make([]domain.FieldName, 0)
-	fld, err := parser.field()
+func (parser *Parser) groupBy() ([]domain.FieldName, error) {
+	err := parser.eatKeyword("group")
 	if err != nil {
-		return nil, errors.Err(err, "field")
+		return nil, errors.Err(err, "eatKeyword")
 	}
 
-	fields = append(fields, fld)
+	err = parser.eatKeyword("by")
+	if err != nil {
+		return nil, errors.Err(err, "eatKeyword")
+	}
+
+	flds, err := parser.fieldList()
+	if err != nil {
+		return nil, errors.Err(err, "fieldList")
+	}
+
+	return flds, nil
+}
+
+// checkGroupedFields checks that each field in select list is a group field or an aggregation.
+// 計算列の場合は式に現れる field をチェックする.
+func checkGroupedFields(fields []domain.FieldName, exprs map[domain.FieldName]domain.Expression, groupBy []domain.FieldName, aggs []domain.Aggregation) error {
+	grouped := make(map[domain.FieldName]bool)
+	for _, fld := range groupBy {
+		grouped[fld] = true
+	}
+	for _, agg := range aggs {
+		grouped[agg.FieldName()] = true
+	}
+
+	for _, fld := range fields {
+		flds := []domain.FieldName{fld}
+		if expr, ok := exprs[fld]; ok {
+			flds = expr.Fields()
+		}
+
+		for _, f := range flds {
+			if !grouped[f] {
+				return errors.Wrap(ErrParse, fmt.Sprintf("column %v must appear in the group by clause or be used in an aggregate function", f))
+			}
+		}
+	}
+
+	return nil
+}
+
+func (parser *Parser) orderBy() ([]domain.SortKey, error) {
+	err := parser.eatKeyword("order")
+	if err != nil {
+		return nil, errors.Err(err, "eatKeyword")
+	}
+
+	err = parser.eatKeyword("by")
+	if err != nil {
+		return nil, errors.Err(err, "eatKeyword")
+	}
+
+	keys := make([]domain.SortKey, 0)
+	key, err := parser.sortKey()
+	if err != nil {
+		return nil, errors.Err(err, "sortKey")
+	}
+
+	keys = append(keys, key)
 
 	for parser.match(lexer.TComma) {
 		err = parser.eatToken(lexer.TComma)
 		if err != nil {
 			return nil, errors.Err(err, "eatToken")
 		}
-		fld, err = parser.field()
+
+		key, err = parser.sortKey()
 		if err != nil {
-			return nil, errors.Err(err, "field")
+			return nil, errors.Err(err, "sortKey")
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (parser *Parser) sortKey() (domain.SortKey, error) {
+	id, err := parser.eatIdentifier()
+	if err != nil {
+		return domain.SortKey{}, errors.Err(err, "eatIdentifier")
+	}
+
+	fld, err := domain.NewFieldName(id)
+	if err != nil {
+		return domain.SortKey{}, errors.Err(err, "NewFieldName")
+	}
+
+	order := domain.Asc
+	switch {
+	case parser.matchKeyword("asc"):
+		err = parser.eatKeyword("asc")
+	case parser.matchKeyword("desc"):
+		err = parser.eatKeyword("desc")
+		order = domain.Desc
+	}
+	if err != nil {
+		return domain.SortKey{}, errors.Err(err, "eatKeyword")
+	}
+
+	return domain.NewSortKey(fld, order), nil
+}
+
+// selectList parses select list.
+// 計算列は field name と expression の組として返す.
+func (parser *Parser) selectList() ([]domain.FieldName, []domain.Aggregation, map[domain.FieldName]domain.Expression, error) {
+	fields := make([]domain.FieldName, 0)
+	aggs := make([]domain.Aggregation, 0)
+	exprs := make(map[domain.FieldName]domain.Expression)
+
+	for {
+		var (
+			fld      domain.FieldName
+			expr     domain.Expression
+			computed bool
+		)
+		if parser.isAggregation() {
+			agg, err := parser.aggregation()
+			if err != nil {
+				return nil, nil, nil, errors.Err(err, "aggregation")
+			}
+			aggs = append(aggs, agg)
+			fld = agg.FieldName()
+		} else {
+			var err error
+			fld, expr, computed, err = parser.selectItem()
+			if err != nil {
+				return nil, nil, nil, errors.Err(err, "selectItem")
+			}
+		}
+
+		if parser.matchKeyword("as") {
+			if fld == "*" {
+				return nil, nil, nil, ErrParse
+			}
+
+			alias, err := parser.alias()
+			if err != nil {
+				return nil, nil, nil, errors.Err(err, "alias")
+			}
+			if !computed {
+				expr = domain.NewFieldNameExpression(fld)
+				computed = true
+			}
+			fld = alias
+		}
+
+		if computed {
+			exprs[fld] = expr
 		}
 		fields = append(fields, fld)
+
+		if !parser.match(lexer.TComma) {
+			break
+		}
+
+		err := parser.eatToken(lexer.TComma)
+		if err != nil {
+			return nil, nil, nil, errors.Err(err, "eatToken")
+		}
 	}
 
-	return fields, nil
+	return fields, aggs, exprs, nil
+}
+
+// selectItem parses an item of select list.
+// 計算列の場合は expression の文字列を field name とする.
+func (parser *Parser) selectItem() (domain.FieldName, domain.Expression, bool, error) {
+	if parser.match(lexer.TStar) {
+		fld, err := parser.field()
+		if err != nil {
+			return "", domain.Expression{}, false, errors.Err(err, "field")
+		}
+
+		return fld, domain.Expression{}, false, nil
+	}
+
+	expr, err := parser.expression()
+	if err != nil {
+		return "", domain.Expression{}, false, errors.Err(err, "expression")
+	}
+	if expr.IsFieldName() {
+		return expr.AsFieldName(), domain.Expression{}, false, nil
+	}
+
+	return domain.FieldName(expr.String()), expr, true, nil
+}
+
+func (parser *Parser) alias() (domain.FieldName, error) {
+	err := parser.eatKeyword("as")
+	if err != nil {
+		return "", errors.Err(err, "eatKeyword")
+	}
+
+	id, err := parser.eatIdentifier()
+	if err != nil {
+		return "", errors.Err(err, "eatIdentifier")
+	}
+
+	return domain.NewFieldName(id)
+}
+
+var aggregationKinds = map[string]domain.AggregationKind{
+	"count": domain.CountAggregation,
+	"sum":   domain.SumAggregation,
+	"min":   domain.MinAggregation,
+	"max":   domain.MaxAggregation,
+	"avg":   domain.AvgAggregation,
+}
+
+// isAggregation checks whether the following tokens are aggregation function or not.
+// count などは keyword にせず、直後に ( が続く identifier を集約関数とみなす.
+func (parser *Parser) isAggregation() bool {
+	if !parser.match(lexer.TIdentifier) || parser.pos+1 >= parser.len {
+		return false
+	}
+
+	id, _ := parser.tokens[parser.pos].Value().(string)
+	_, ok := aggregationKinds[id]
+
+	return ok && parser.tokens[parser.pos+1].Type() == lexer.TLParen
+}
+
+func (parser *Parser) aggregation() (domain.Aggregation, error) {
+	id, err := parser.eatIdentifier()
+	if err != nil {
+		return domain.Aggregation{}, errors.Err(err, "eatIdentifier")
+	}
+
+	kind, ok := aggregationKinds[id]
+	if !ok {
+		return domain.Aggregation{}, ErrParse
+	}
+
+	err = parser.eatToken(lexer.TLParen)
+	if err != nil {
+		return domain.Aggregation{}, errors.Err(err, "eatToken")
+	}
+
+	fld, err := parser.field()
+	if err != nil {
+		return domain.Aggregation{}, errors.Err(err, "field")
+	}
+	if fld == "*" && kind != domain.CountAggregation {
+		return domain.Aggregation{}, ErrParse
+	}
+
+	err = parser.eatToken(lexer.TRParen)
+	if err != nil {
+		return domain.Aggregation{}, errors.Err(err, "eatToken")
+	}
+
+	return domain.NewAggregation(kind, fld), nil
 }
 
 func (parser *Parser) tableList() ([]domain.TableName, error) {
@@ -133,6 +465,74 @@ func (parser *Parser) tableList() ([]domain.TableName, error) {
 	return tables, nil
 }
 
+// joinList parses join clauses following table list.
+func (parser *Parser) joinList() ([]*domain.JoinData, error) {
+	joins := make([]*domain.JoinData, 0)
+	for parser.matchKeyword("join") || parser.matchKeyword("inner") ||
+		parser.matchKeyword("left") || parser.matchKeyword("right") {
+		join, err := parser.join()
+		if err != nil {
+			return nil, errors.Err(err, "join")
+		}
+		joins = append(joins, join)
+	}
+
+	return joins, nil
+}
+
+func (parser *Parser) join() (*domain.JoinData, error) {
+	typ, err := parser.joinType()
+	if err != nil {
+		return nil, errors.Err(err, "joinType")
+	}
+
+	err = parser.eatKeyword("join")
+	if err != nil {
+		return nil, errors.Err(err, "eatKeyword")
+	}
+
+	tbl, err := parser.table()
+	if err != nil {
+		return nil, errors.Err(err, "table")
+	}
+
+	err = parser.eatKeyword("on")
+	if err != nil {
+		return nil, errors.Err(err, "eatKeyword")
+	}
+
+	pred, err := parser.predicate()
+	if err != nil {
+		return nil, errors.Err(err, "predicate")
+	}
+
+	return domain.NewJoinData(typ, tbl, pred), nil
+}
+
+// joinType parses [INNER | LEFT [OUTER] | RIGHT [OUTER]].
+func (parser *Parser) joinType() (domain.JoinType, error) {
+	typ := domain.InnerJoin
+	switch {
+	case parser.matchKeyword("inner"):
+		return typ, parser.eatKeyword("inner")
+	case parser.matchKeyword("left"):
+		typ = domain.LeftOuterJoin
+	case parser.matchKeyword("right"):
+		typ = domain.RightOuterJoin
+	default:
+		return typ, nil
+	}
+	parser.pos++
+
+	if parser.matchKeyword("outer") {
+		if err := parser.eatKeyword("outer"); err != nil {
+			return typ, errors.Err(err, "eatKeyword")
+		}
+	}
+
+	return typ, nil
+}
+
 // ExecCmd parses execution command.
 func (parser *Parser) ExecCmd() (domain.ExecData, error) {
 	switch {
@@ -144,6 +544,10 @@ func (parser *Parser) ExecCmd() (domain.ExecData, error) {
 		return parser.modifyCmd()
 	case parser.matchKeyword("create"):
 		return parser.createCmd()
+	case parser.matchKeyword("drop"):
+		return parser.dropCmd()
+	case parser.matchKeyword("alter"):
+		return parser.alterTable()
 	default:
 		return nil, ErrParse
 	}
@@ -204,6 +608,10 @@ func (parser *Parser) insertCmd() (domain.ExecData, error) {
 		return nil, errors.Err(err, "eatToken")
 	}
 
+	if len(flds) != len(vals) {
+		return nil, errors.Wrap(ErrParse, "INSERT has a different number of target columns and values")
+	}
+
 	return domain.NewInsertData(tblName, flds, vals), nil
 }
 
@@ -334,7 +742,7 @@ func (parser *Parser) createTable() (domain.ExecData, error) {
 		return nil, errors.Err(err, "eatToken")
 	}
 
-	sch, err := parser.fieldDefs()
+	sch, cons, err := parser.fieldDefs()
 	if err != nil {
 		return nil, errors.Err(err, "fieldDefs")
 	}
@@ -344,115 +752,742 @@ func (parser *Parser) createTable() (domain.ExecData, error) {
 		return nil, errors.Err(err, "eatToken")
 	}
 
-	return domain.NewCreateTableData(tblName, sch), nil
-}
-
-func (parser *Parser) fieldDefs() (*domain.Schema, error) {
-	sch, err := parser.fieldDef()
-	if err != nil {
-		return nil, errors.Err(err, "fieldDef")
+	numPrimaryKeys := 0
+	for _, c := range cons {
+		if c.Type() == domain.PrimaryKeyConstraint {
+			numPrimaryKeys++
+		}
+	}
+	if numPrimaryKeys > 1 {
+		return nil, errors.Wrap(domain.ErrMultiplePrimaryKeys, tblName.String())
 	}
 
-	for parser.match(lexer.TComma) {
-		err = parser.eatToken(lexer.TComma)
+	format := domain.DefaultRecordFormat
+	if parser.matchKeyword("using") {
+		err = parser.eatKeyword("using")
 		if err != nil {
-			return nil, errors.Err(err, "eatToken")
+			return nil, errors.Err(err, "eatKeyword")
 		}
 
-		sch2, err := parser.fieldDef()
+		formatStr, err := parser.eatIdentifier()
 		if err != nil {
-			return nil, errors.Err(err, "fieldDef")
+			return nil, errors.Err(err, "eatIdentifier")
+		}
+		format, err = domain.NewRecordFormat(formatStr)
+		if err != nil {
+			return nil, errors.Err(err, "NewRecordFormat")
 		}
-
-		sch.AddAllFields(sch2)
-	}
-
-	return sch, nil
-}
-
-func (parser *Parser) fieldDef() (*domain.Schema, error) {
-	fld, err := parser.field()
-	if err != nil {
-		return nil, errors.Err(err, "field")
 	}
 
-	return parser.fieldType(fld)
+	return domain.NewCreateTableData(tblName, sch, cons, format), nil
 }
 
-func (parser *Parser) fieldType(fld domain.FieldName) (*domain.Schema, error) {
+// fieldDefs parses column definitions and table constraints.
+func (parser *Parser) fieldDefs() (*domain.Schema, []domain.Constraint, error) {
 	sch := domain.NewSchema()
+	cons := make([]domain.Constraint, 0)
+	for {
+		if parser.matchKeyword("primary") || parser.matchKeyword("unique") || parser.matchKeyword("foreign") {
+			c, err := parser.tableConstraint()
+			if err != nil {
+				return nil, nil, errors.Err(err, "tableConstraint")
+			}
+			cons = append(cons, c)
+		} else {
+			sch2, colCons, err := parser.fieldDef()
+			if err != nil {
+				return nil, nil, errors.Err(err, "fieldDef")
+			}
+			sch.AddAllFields(sch2)
+			for _, fld := range sch2.Fields() {
+				sch.SetFieldConstraint(fld, sch2.FieldConstraint(fld))
+			}
+			cons = append(cons, colCons...)
+		}
 
-	switch {
-	case parser.matchKeyword("int"):
-		err := parser.eatKeyword("int")
-		if err != nil {
-			return nil, errors.Err(err, "eatKeyword")
+		if !parser.match(lexer.TComma) {
+			break
 		}
-		sch.AddInt32Field(fld)
-	case parser.matchKeyword("varchar"):
-		err := parser.eatKeyword("varchar")
+		err := parser.eatToken(lexer.TComma)
 		if err != nil {
-			return nil, errors.Err(err, "eatKeyword")
+			return nil, nil, errors.Err(err, "eatToken")
 		}
+	}
 
-		err = parser.eatToken(lexer.TLParen)
-		if err != nil {
-			return nil, errors.Err(err, "eatToken")
+	// PostgreSQL と同様に primary key の field は NOT NULL になる.
+	for _, c := range cons {
+		if c.Type() != domain.PrimaryKeyConstraint {
+			continue
 		}
-
-		num, err := parser.eatInt32()
-		if err != nil {
-			return nil, errors.Err(err, "eatInt32")
+		for _, fld := range c.FieldNames() {
+			sch.SetFieldConstraint(fld, sch.FieldConstraint(fld).WithNotNull())
 		}
+	}
 
-		err = parser.eatToken(lexer.TRParen)
-		if err != nil {
-			return nil, errors.Err(err, "eatToken")
-		}
+	return sch, cons, nil
+}
+
+func (parser *Parser) fieldDef() (*domain.Schema, []domain.Constraint, error) {
+	fld, err := parser.field()
+	if err != nil {
+		return nil, nil, errors.Err(err, "field")
+	}
+
+	sch, err := parser.fieldType(fld)
+	if err != nil {
+		return nil, nil, errors.Err(err, "fieldType")
+	}
+
+	// column constraint は複数並べられる.
+	cons := make([]domain.Constraint, 0)
+	fc := domain.NewFieldConstraint()
+	for {
+		switch {
+		case parser.matchKeyword("primary") || parser.matchKeyword("unique"):
+			typ, err := parser.constraintType()
+			if err != nil {
+				return nil, nil, errors.Err(err, "constraintType")
+			}
+			cons = append(cons, domain.NewConstraint(typ, []domain.FieldName{fld}))
+		case parser.matchKeyword("references"):
+			c, err := parser.reference([]domain.FieldName{fld})
+			if err != nil {
+				return nil, nil, errors.Err(err, "reference")
+			}
+			cons = append(cons, c)
+		case parser.matchKeyword("not"):
+			err := parser.eatKeyword("not")
+			if err != nil {
+				return nil, nil, errors.Err(err, "eatKeyword")
+			}
+
+			err = parser.eatKeyword("null")
+			if err != nil {
+				return nil, nil, errors.Err(err, "eatKeyword")
+			}
+			fc = fc.WithNotNull()
+		case parser.matchKeyword("null"):
+			// NULL を許すのは default なので読み飛ばす.
+			err := parser.eatKeyword("null")
+			if err != nil {
+				return nil, nil, errors.Err(err, "eatKeyword")
+			}
+		case parser.matchKeyword("default"):
+			err := parser.eatKeyword("default")
+			if err != nil {
+				return nil, nil, errors.Err(err, "eatKeyword")
+			}
+
+			val, err := parser.constant()
+			if err != nil {
+				return nil, nil, errors.Err(err, "constant")
+			}
+			fc = fc.WithDefault(val)
+		case parser.matchKeyword("check"):
+			pred, err := parser.checkConstraint()
+			if err != nil {
+				return nil, nil, errors.Err(err, "checkConstraint")
+			}
+			fc = fc.WithCheck(pred)
+		default:
+			sch.SetFieldConstraint(fld, fc)
+
+			return sch, cons, nil
+		}
+	}
+}
+
+// checkConstraint parses CHECK (predicate).
+func (parser *Parser) checkConstraint() (*domain.Predicate, error) {
+	err := parser.eatKeyword("check")
+	if err != nil {
+		return nil, errors.Err(err, "eatKeyword")
+	}
+
+	err = parser.eatToken(lexer.TLParen)
+	if err != nil {
+		return nil, errors.Err(err, "eatToken")
+	}
+
+	pred, err := parser.predicate()
+	if err != nil {
+		return nil, errors.Err(err, "predicate")
+	}
+
+	err = parser.eatToken(lexer.TRParen)
+	if err != nil {
+		return nil, errors.Err(err, "eatToken")
+	}
+
+	return pred, nil
+}
+
+// tableConstraint parses PRIMARY KEY (fields), UNIQUE (fields) or FOREIGN KEY (fields) REFERENCES ...
+func (parser *Parser) tableConstraint() (domain.Constraint, error) {
+	isForeignKey := parser.matchKeyword("foreign")
+
+	var typ domain.ConstraintType
+	if isForeignKey {
+		err := parser.eatKeyword("foreign")
+		if err != nil {
+			return domain.Constraint{}, errors.Err(err, "eatKeyword")
+		}
+
+		err = parser.eatKeyword("key")
+		if err != nil {
+			return domain.Constraint{}, errors.Err(err, "eatKeyword")
+		}
+	} else {
+		var err error
+		typ, err = parser.constraintType()
+		if err != nil {
+			return domain.Constraint{}, errors.Err(err, "constraintType")
+		}
+	}
+
+	err := parser.eatToken(lexer.TLParen)
+	if err != nil {
+		return domain.Constraint{}, errors.Err(err, "eatToken")
+	}
+
+	fldNames, err := parser.identifierList()
+	if err != nil {
+		return domain.Constraint{}, errors.Err(err, "identifierList")
+	}
+
+	err = parser.eatToken(lexer.TRParen)
+	if err != nil {
+		return domain.Constraint{}, errors.Err(err, "eatToken")
+	}
+
+	if isForeignKey {
+		return parser.reference(fldNames)
+	}
+
+	return domain.NewConstraint(typ, fldNames), nil
+}
+
+// reference parses REFERENCES table [(fields)] [ON DELETE action].
+// ON DELETE を省略した場合は RESTRICT.
+func (parser *Parser) reference(fldNames []domain.FieldName) (domain.Constraint, error) {
+	err := parser.eatKeyword("references")
+	if err != nil {
+		return domain.Constraint{}, errors.Err(err, "eatKeyword")
+	}
+
+	refTblName, err := parser.table()
+	if err != nil {
+		return domain.Constraint{}, errors.Err(err, "table")
+	}
+
+	var refFldNames []domain.FieldName
+	if parser.match(lexer.TLParen) {
+		err = parser.eatToken(lexer.TLParen)
+		if err != nil {
+			return domain.Constraint{}, errors.Err(err, "eatToken")
+		}
+
+		refFldNames, err = parser.identifierList()
+		if err != nil {
+			return domain.Constraint{}, errors.Err(err, "identifierList")
+		}
+
+		err = parser.eatToken(lexer.TRParen)
+		if err != nil {
+			return domain.Constraint{}, errors.Err(err, "eatToken")
+		}
+	}
+
+	action := domain.RestrictAction
+	if parser.matchKeyword("on") {
+		action, err = parser.onDelete()
+		if err != nil {
+			return domain.Constraint{}, errors.Err(err, "onDelete")
+		}
+	}
+
+	return domain.NewForeignKeyConstraint(fldNames, refTblName, refFldNames, action), nil
+}
+
+func (parser *Parser) onDelete() (domain.ReferentialAction, error) {
+	err := parser.eatKeyword("on")
+	if err != nil {
+		return "", errors.Err(err, "eatKeyword")
+	}
+
+	err = parser.eatKeyword("delete")
+	if err != nil {
+		return "", errors.Err(err, "eatKeyword")
+	}
+
+	switch {
+	case parser.matchKeyword("restrict"):
+		return domain.RestrictAction, parser.eatKeyword("restrict")
+	case parser.matchKeyword("cascade"):
+		return domain.CascadeAction, parser.eatKeyword("cascade")
+	case parser.matchKeyword("set"):
+		err = parser.eatKeyword("set")
+		if err != nil {
+			return "", errors.Err(err, "eatKeyword")
+		}
+
+		return domain.SetNullAction, parser.eatKeyword("null")
+	default:
+		return "", ErrParse
+	}
+}
+
+func (parser *Parser) constraintType() (domain.ConstraintType, error) {
+	if parser.matchKeyword("unique") {
+		err := parser.eatKeyword("unique")
+		if err != nil {
+			return "", errors.Err(err, "eatKeyword")
+		}
+
+		return domain.UniqueConstraint, nil
+	}
+
+	err := parser.eatKeyword("primary")
+	if err != nil {
+		return "", errors.Err(err, "eatKeyword")
+	}
+
+	err = parser.eatKeyword("key")
+	if err != nil {
+		return "", errors.Err(err, "eatKeyword")
+	}
+
+	return domain.PrimaryKeyConstraint, nil
+}
+
+func (parser *Parser) fieldType(fld domain.FieldName) (*domain.Schema, error) {
+	sch := domain.NewSchema()
+
+	switch {
+	case parser.matchKeyword("int"):
+		err := parser.eatKeyword("int")
+		if err != nil {
+			return nil, errors.Err(err, "eatKeyword")
+		}
+		sch.AddInt32Field(fld)
+	case parser.matchKeyword("varchar"):
+		err := parser.eatKeyword("varchar")
+		if err != nil {
+			return nil, errors.Err(err, "eatKeyword")
+		}
+
+		err = parser.eatToken(lexer.TLParen)
+		if err != nil {
+			return nil, errors.Err(err, "eatToken")
+		}
+
+		num, err := parser.eatInt32()
+		if err != nil {
+			return nil, errors.Err(err, "eatInt32")
+		}
+
+		err = parser.eatToken(lexer.TRParen)
+		if err != nil {
+			return nil, errors.Err(err, "eatToken")
+		}
+
+		sch.AddStringField(fld, int(num))
+	case parser.matchKeyword("smallint"):
+		err := parser.eatKeyword("smallint")
+		if err != nil {
+			return nil, errors.Err(err, "eatKeyword")
+		}
+		sch.AddField(fld, domain.Int16FieldType, 0)
+	case parser.matchKeyword("bigint"):
+		err := parser.eatKeyword("bigint")
+		if err != nil {
+			return nil, errors.Err(err, "eatKeyword")
+		}
+		sch.AddField(fld, domain.Int64FieldType, 0)
+	case parser.matchKeyword("boolean"):
+		err := parser.eatKeyword("boolean")
+		if err != nil {
+			return nil, errors.Err(err, "eatKeyword")
+		}
+		sch.AddField(fld, domain.BoolFieldType, 0)
+	case parser.matchKeyword("real"):
+		err := parser.eatKeyword("real")
+		if err != nil {
+			return nil, errors.Err(err, "eatKeyword")
+		}
+		sch.AddField(fld, domain.Float32FieldType, 0)
+	case parser.matchKeyword("double"):
+		err := parser.eatKeyword("double")
+		if err != nil {
+			return nil, errors.Err(err, "eatKeyword")
+		}
+		err = parser.eatKeyword("precision")
+		if err != nil {
+			return nil, errors.Err(err, "eatKeyword")
+		}
+		sch.AddField(fld, domain.Float64FieldType, 0)
+	case parser.matchNumericType():
+		length, err := parser.numericType()
+		if err != nil {
+			return nil, errors.Err(err, "numericType")
+		}
+		sch.AddField(fld, domain.NumericFieldType, length)
+	case parser.matchDatetimeType():
+		typ, err := parser.datetimeType()
+		if err != nil {
+			return nil, errors.Err(err, "datetimeType")
+		}
+		sch.AddField(fld, typ, 0)
+	case parser.matchKeyword("text"):
+		err := parser.eatKeyword("text")
+		if err != nil {
+			return nil, errors.Err(err, "eatKeyword")
+		}
+		sch.AddTextField(fld)
+	case parser.matchKeyword("bytea"):
+		err := parser.eatKeyword("bytea")
+		if err != nil {
+			return nil, errors.Err(err, "eatKeyword")
+		}
+		sch.AddBytesField(fld)
+	default:
+		return nil, ErrParse
+	}
+
+	return sch, nil
+}
+
+// matchDatetimeType checks whether the current token is the name of date/time types.
+func (parser *Parser) matchDatetimeType() bool {
+	return parser.matchKeyword("date") || parser.matchKeyword("timestamp") ||
+		parser.matchKeyword("timestamptz") || parser.matchKeyword("interval")
+}
+
+// datetimeType parses DATE, TIMESTAMP [WITH TIME ZONE], TIMESTAMPTZ or INTERVAL.
+func (parser *Parser) datetimeType() (domain.FieldType, error) {
+	names := map[string]domain.FieldType{
+		"date":        domain.DateFieldType,
+		"timestamp":   domain.TimestampFieldType,
+		"timestamptz": domain.TimestampTzFieldType,
+		"interval":    domain.IntervalFieldType,
+	}
+
+	if !parser.matchDatetimeType() {
+		return domain.UnknownFieldType, ErrParse
+	}
+	kw, _ := parser.tokens[parser.pos].Value().(string)
+	typ := names[kw]
+
+	err := parser.eatKeyword(kw)
+	if err != nil {
+		return domain.UnknownFieldType, errors.Err(err, "eatKeyword")
+	}
+
+	if typ != domain.TimestampFieldType || !parser.matchKeyword("with") {
+		return typ, nil
+	}
+
+	for _, kw := range []string{"with", "time", "zone"} {
+		err := parser.eatKeyword(kw)
+		if err != nil {
+			return domain.UnknownFieldType, errors.Err(err, "eatKeyword")
+		}
+	}
+
+	return domain.TimestampTzFieldType, nil
+}
+
+// matchNumericType checks whether the current token is NUMERIC or DECIMAL.
+func (parser *Parser) matchNumericType() bool {
+	return parser.matchKeyword("numeric") || parser.matchKeyword("decimal")
+}
+
+// numericType parses NUMERIC [(precision [, scale])] and returns the length of the field.
+// DECIMAL は NUMERIC の別名.
+func (parser *Parser) numericType() (int, error) {
+	if !parser.matchNumericType() {
+		return 0, ErrParse
+	}
+	kw, _ := parser.tokens[parser.pos].Value().(string)
+
+	err := parser.eatKeyword(kw)
+	if err != nil {
+		return 0, errors.Err(err, "eatKeyword")
+	}
+
+	if !parser.match(lexer.TLParen) {
+		return 0, nil
+	}
+
+	err = parser.eatToken(lexer.TLParen)
+	if err != nil {
+		return 0, errors.Err(err, "eatToken")
+	}
+
+	precision, err := parser.eatInt32()
+	if err != nil {
+		return 0, errors.Err(err, "eatInt32")
+	}
+
+	scale := int32(0)
+	if parser.match(lexer.TComma) {
+		err = parser.eatToken(lexer.TComma)
+		if err != nil {
+			return 0, errors.Err(err, "eatToken")
+		}
+
+		scale, err = parser.eatInt32()
+		if err != nil {
+			return 0, errors.Err(err, "eatInt32")
+		}
+	}
+
+	err = parser.eatToken(lexer.TRParen)
+	if err != nil {
+		return 0, errors.Err(err, "eatToken")
+	}
+
+	length, err := domain.NumericLength(int(precision), int(scale))
+	if err != nil {
+		return 0, errors.Err(err, "NumericLength")
+	}
+
+	return length, nil
+}
+
+// typedLiteral parses a literal with its type name such as DATE '2024-01-01' or NUMERIC '1.50'.
+func (parser *Parser) typedLiteral() (domain.Constant, error) {
+	typ := domain.NumericFieldType
+	if parser.matchNumericType() {
+		kw, _ := parser.tokens[parser.pos].Value().(string)
+		err := parser.eatKeyword(kw)
+		if err != nil {
+			return domain.Constant{}, errors.Err(err, "eatKeyword")
+		}
+	} else {
+		t, err := parser.datetimeType()
+		if err != nil {
+			return domain.Constant{}, errors.Err(err, "datetimeType")
+		}
+		typ = t
+	}
+
+	str, err := parser.eatString()
+	if err != nil {
+		return domain.Constant{}, errors.Err(err, "eatString")
+	}
+
+	c, err := domain.ParseConstant(typ, str)
+	if err != nil {
+		return domain.Constant{}, errors.Err(err, "ParseConstant")
+	}
+
+	return c, nil
+}
+
+// currentTime parses now(), current_timestamp or current_date.
+func (parser *Parser) currentTime() (domain.Expression, error) {
+	if parser.matchKeyword("current_date") {
+		err := parser.eatKeyword("current_date")
+		if err != nil {
+			return domain.Expression{}, errors.Err(err, "eatKeyword")
+		}
+
+		return domain.NewCurrentDateExpression(parser.now), nil
+	}
+
+	if parser.matchKeyword("current_timestamp") {
+		err := parser.eatKeyword("current_timestamp")
+		if err != nil {
+			return domain.Expression{}, errors.Err(err, "eatKeyword")
+		}
+
+		return domain.NewNowExpression(parser.now), nil
+	}
+
+	err := parser.eatKeyword("now")
+	if err != nil {
+		return domain.Expression{}, errors.Err(err, "eatKeyword")
+	}
+
+	err = parser.eatToken(lexer.TLParen)
+	if err != nil {
+		return domain.Expression{}, errors.Err(err, "eatToken")
+	}
+
+	err = parser.eatToken(lexer.TRParen)
+	if err != nil {
+		return domain.Expression{}, errors.Err(err, "eatToken")
+	}
+
+	return domain.NewNowExpression(parser.now), nil
+}
+
+func (parser *Parser) createView() (domain.ExecData, error) {
+	err := parser.eatKeyword("view")
+	if err != nil {
+		return nil, errors.Err(err, "eatKeyword")
+	}
+
+	viewNameStr, err := parser.eatIdentifier()
+	if err != nil {
+		return nil, errors.Err(err, "eatIdentifier")
+	}
+
+	viewName, err := domain.NewViewName(viewNameStr)
+	if err != nil {
+		return nil, errors.Err(err, "NewViewName")
+	}
+
+	err = parser.eatKeyword("as")
+	if err != nil {
+		return nil, errors.Err(err, "eatKeyword")
+	}
+
+	qd, err := parser.Query()
+	if err != nil {
+		return nil, errors.Err(err, "Query")
+	}
+
+	return domain.NewCreateViewData(viewName, qd), nil
+}
+
+func (parser *Parser) createIndex() (domain.ExecData, error) {
+	err := parser.eatKeyword("index")
+	if err != nil {
+		return nil, errors.Err(err, "eatKeyword")
+	}
+
+	idxNameStr, err := parser.eatIdentifier()
+	if err != nil {
+		return nil, errors.Err(err, "eatIdentifier")
+	}
+	idxName, err := domain.NewIndexName(idxNameStr)
+	if err != nil {
+		return nil, errors.Err(err, "NewIndexName")
+	}
+
+	err = parser.eatKeyword("on")
+	if err != nil {
+		return nil, errors.Err(err, "eatKeyword")
+	}
+
+	tblNameStr, err := parser.eatIdentifier()
+	if err != nil {
+		return nil, errors.Err(err, "eatIdentifier")
+	}
+	tblName, err := domain.NewTableName(tblNameStr)
+	if err != nil {
+		return nil, errors.Err(err, "NewTableName")
+	}
+
+	err = parser.eatToken(lexer.TLParen)
+	if err != nil {
+		return nil, errors.Err(err, "eatToken")
+	}
+
+	// composite index の key は comma 区切りで並べる.
+	fldNames, err := parser.identifierList()
+	if err != nil {
+		return nil, errors.Err(err, "identifierList")
+	}
+
+	err = parser.eatToken(lexer.TRParen)
+	if err != nil {
+		return nil, errors.Err(err, "eatToken")
+	}
+
+	idxType := domain.DefaultIndexType
+	if parser.matchKeyword("using") {
+		err = parser.eatKeyword("using")
+		if err != nil {
+			return nil, errors.Err(err, "eatKeyword")
+		}
+
+		idxTypeStr, err := parser.eatIdentifier()
+		if err != nil {
+			return nil, errors.Err(err, "eatIdentifier")
+		}
+		idxType, err = domain.NewIndexType(idxTypeStr)
+		if err != nil {
+			return nil, errors.Err(err, "NewIndexType")
+		}
+	}
 
-		sch.AddStringField(fld, int(num))
+	return domain.NewCreateIndexData(idxName, tblName, fldNames, idxType), nil
+}
+
+func (parser *Parser) dropCmd() (domain.ExecData, error) {
+	err := parser.eatKeyword("drop")
+	if err != nil {
+		return nil, errors.Err(err, "eatKeyword")
+	}
+
+	switch {
+	case parser.matchKeyword("table"):
+		return parser.dropTable()
+	case parser.matchKeyword("view"):
+		return parser.dropView()
+	case parser.matchKeyword("index"):
+		return parser.dropIndex()
 	default:
 		return nil, ErrParse
 	}
-
-	return sch, nil
 }
 
-func (parser *Parser) createView() (domain.ExecData, error) {
-	err := parser.eatKeyword("view")
+func (parser *Parser) dropTable() (domain.ExecData, error) {
+	err := parser.eatKeyword("table")
 	if err != nil {
 		return nil, errors.Err(err, "eatKeyword")
 	}
 
-	viewNameStr, err := parser.eatIdentifier()
+	ifExists, err := parser.ifExists()
 	if err != nil {
-		return nil, errors.Err(err, "eatIdentifier")
+		return nil, errors.Err(err, "ifExists")
 	}
 
-	viewName, err := domain.NewViewName(viewNameStr)
+	tblName, err := parser.table()
 	if err != nil {
-		return nil, errors.Err(err, "NewViewName")
+		return nil, errors.Err(err, "table")
 	}
 
-	err = parser.eatKeyword("as")
+	return domain.NewDropTableData(tblName, ifExists), nil
+}
+
+func (parser *Parser) dropView() (domain.ExecData, error) {
+	err := parser.eatKeyword("view")
 	if err != nil {
 		return nil, errors.Err(err, "eatKeyword")
 	}
 
-	qd, err := parser.Query()
+	ifExists, err := parser.ifExists()
 	if err != nil {
-		return nil, errors.Err(err, "Query")
+		return nil, errors.Err(err, "ifExists")
 	}
 
-	return domain.NewCreateViewData(viewName, qd), nil
+	viewNameStr, err := parser.eatIdentifier()
+	if err != nil {
+		return nil, errors.Err(err, "eatIdentifier")
+	}
+	viewName, err := domain.NewViewName(viewNameStr)
+	if err != nil {
+		return nil, errors.Err(err, "NewViewName")
+	}
+
+	return domain.NewDropViewData(viewName, ifExists), nil
 }
 
-func (parser *Parser) createIndex() (domain.ExecData, error) {
+func (parser *Parser) dropIndex() (domain.ExecData, error) {
 	err := parser.eatKeyword("index")
 	if err != nil {
 		return nil, errors.Err(err, "eatKeyword")
 	}
 
+	ifExists, err := parser.ifExists()
+	if err != nil {
+		return nil, errors.Err(err, "ifExists")
+	}
+
 	idxNameStr, err := parser.eatIdentifier()
 	if err != nil {
 		return nil, errors.Err(err, "eatIdentifier")
@@ -462,40 +1497,173 @@ func (parser *Parser) createIndex() (domain.ExecData, error) {
 		return nil, errors.Err(err, "NewIndexName")
 	}
 
-	err = parser.eatKeyword("on")
+	return domain.NewDropIndexData(idxName, ifExists), nil
+}
+
+func (parser *Parser) alterTable() (domain.ExecData, error) {
+	err := parser.eatKeyword("alter")
 	if err != nil {
 		return nil, errors.Err(err, "eatKeyword")
 	}
 
-	tblNameStr, err := parser.eatIdentifier()
+	err = parser.eatKeyword("table")
 	if err != nil {
-		return nil, errors.Err(err, "eatIdentifier")
+		return nil, errors.Err(err, "eatKeyword")
 	}
-	tblName, err := domain.NewTableName(tblNameStr)
+
+	tblName, err := parser.table()
 	if err != nil {
-		return nil, errors.Err(err, "NewTableName")
+		return nil, errors.Err(err, "table")
 	}
 
-	err = parser.eatToken(lexer.TLParen)
+	switch {
+	case parser.matchKeyword("add"):
+		return parser.addColumn(tblName)
+	case parser.matchKeyword("drop"):
+		return parser.dropColumn(tblName)
+	case parser.matchKeyword("rename"):
+		return parser.rename(tblName)
+	default:
+		return nil, ErrParse
+	}
+}
+
+// addColumn parses ADD [COLUMN] column definition.
+func (parser *Parser) addColumn(tblName domain.TableName) (domain.ExecData, error) {
+	err := parser.eatKeyword("add")
 	if err != nil {
-		return nil, errors.Err(err, "eatToken")
+		return nil, errors.Err(err, "eatKeyword")
+	}
+
+	if err := parser.optionalColumn(); err != nil {
+		return nil, errors.Err(err, "optionalColumn")
 	}
 
-	fldNameStr, err := parser.eatIdentifier()
+	sch, cons, err := parser.fieldDef()
 	if err != nil {
-		return nil, errors.Err(err, "eatIdentifier")
+		return nil, errors.Err(err, "fieldDef")
+	}
+
+	fld := sch.Fields()[0]
+	for _, c := range cons {
+		if c.Type() == domain.PrimaryKeyConstraint {
+			sch.SetFieldConstraint(fld, sch.FieldConstraint(fld).WithNotNull())
+		}
 	}
-	fldName, err := domain.NewFieldName(fldNameStr)
+
+	return domain.NewAddColumnData(tblName, fld, sch, cons), nil
+}
+
+// dropColumn parses DROP [COLUMN] field.
+func (parser *Parser) dropColumn(tblName domain.TableName) (domain.ExecData, error) {
+	err := parser.eatKeyword("drop")
 	if err != nil {
-		return nil, errors.Err(err, "NewFieldName")
+		return nil, errors.Err(err, "eatKeyword")
 	}
 
-	err = parser.eatToken(lexer.TRParen)
+	if err := parser.optionalColumn(); err != nil {
+		return nil, errors.Err(err, "optionalColumn")
+	}
+
+	fld, err := parser.field()
 	if err != nil {
-		return nil, errors.Err(err, "eatToken")
+		return nil, errors.Err(err, "field")
+	}
+
+	return domain.NewDropColumnData(tblName, fld), nil
+}
+
+// rename parses RENAME [COLUMN] field TO field or RENAME TO table.
+func (parser *Parser) rename(tblName domain.TableName) (domain.ExecData, error) {
+	err := parser.eatKeyword("rename")
+	if err != nil {
+		return nil, errors.Err(err, "eatKeyword")
+	}
+
+	if parser.matchKeyword("to") {
+		if err := parser.eatKeyword("to"); err != nil {
+			return nil, errors.Err(err, "eatKeyword")
+		}
+
+		newTblName, err := parser.table()
+		if err != nil {
+			return nil, errors.Err(err, "table")
+		}
+
+		return domain.NewRenameTableData(tblName, newTblName), nil
+	}
+
+	if err := parser.optionalColumn(); err != nil {
+		return nil, errors.Err(err, "optionalColumn")
+	}
+
+	fld, err := parser.field()
+	if err != nil {
+		return nil, errors.Err(err, "field")
+	}
+
+	err = parser.eatKeyword("to")
+	if err != nil {
+		return nil, errors.Err(err, "eatKeyword")
+	}
+
+	newFld, err := parser.field()
+	if err != nil {
+		return nil, errors.Err(err, "field")
+	}
+
+	return domain.NewRenameColumnData(tblName, fld, newFld), nil
+}
+
+// optionalColumn skips optional COLUMN keyword.
+// COLUMN の後に field 名が続かなければ COLUMN を field 名とみなす.
+func (parser *Parser) optionalColumn() error {
+	if !parser.matchKeyword("column") || parser.pos+1 >= parser.len || !parser.tokens[parser.pos+1].IsIdentifier() {
+		return nil
 	}
 
-	return domain.NewCreateIndexData(idxName, tblName, fldName), nil
+	return parser.eatKeyword("column")
+}
+
+// ifExists parses optional IF EXISTS.
+func (parser *Parser) ifExists() (bool, error) {
+	if !parser.matchKeyword("if") {
+		return false, nil
+	}
+
+	if err := parser.eatKeyword("if"); err != nil {
+		return false, errors.Err(err, "eatKeyword")
+	}
+	if err := parser.eatKeyword("exists"); err != nil {
+		return false, errors.Err(err, "eatKeyword")
+	}
+
+	return true, nil
+}
+
+// identifierList parses comma separated field names.
+// fieldList と違い * は受け付けない.
+func (parser *Parser) identifierList() ([]domain.FieldName, error) {
+	fldNames := make([]domain.FieldName, 0)
+	for {
+		fldNameStr, err := parser.eatIdentifier()
+		if err != nil {
+			return nil, errors.Err(err, "eatIdentifier")
+		}
+		fldName, err := domain.NewFieldName(fldNameStr)
+		if err != nil {
+			return nil, errors.Err(err, "NewFieldName")
+		}
+		fldNames = append(fldNames, fldName)
+
+		if !parser.match(lexer.TComma) {
+			return fldNames, nil
+		}
+		err = parser.eatToken(lexer.TComma)
+		if err != nil {
+			return nil, errors.Err(err, "eatToken")
+		}
+	}
 }
 
 func (parser *Parser) fieldList() ([]domain.FieldName, error) {
@@ -546,14 +1714,57 @@ func (parser *Parser) constList() ([]domain.Constant, error) {
 	return consts, nil
 }
 
+// Predicate parses a whole token sequence as a predicate.
+// catalog に保存した CHECK 制約を読み戻すときに使う.
+func (parser *Parser) Predicate() (*domain.Predicate, error) {
+	pred, err := parser.predicate()
+	if err != nil {
+		return nil, errors.Err(err, "predicate")
+	}
+
+	if parser.pos != parser.len {
+		return nil, ErrParse
+	}
+
+	return pred, nil
+}
+
+// predicate parses disjunction of conjunctions.
+// and は or より優先される.
 func (parser *Parser) predicate() (*domain.Predicate, error) {
-	terms := make([]domain.Term, 0)
-	term, err := parser.term()
+	preds := make([]*domain.Predicate, 0)
+	pred, err := parser.conjunction()
 	if err != nil {
 		return &domain.Predicate{}, err
 	}
 
-	terms = append(terms, term)
+	preds = append(preds, pred)
+
+	for parser.matchKeyword("or") {
+		err = parser.eatKeyword("or")
+		if err != nil {
+			return &domain.Predicate{}, errors.Err(err, "eatKeyword")
+		}
+
+		pred, err := parser.conjunction()
+		if err != nil {
+			return &domain.Predicate{}, err
+		}
+		preds = append(preds, pred)
+	}
+
+	if len(preds) == 1 {
+		return preds[0], nil
+	}
+
+	return domain.NewPredicate([]domain.Term{domain.NewOrTerm(preds)}), nil
+}
+
+func (parser *Parser) conjunction() (*domain.Predicate, error) {
+	terms, err := parser.factor()
+	if err != nil {
+		return &domain.Predicate{}, err
+	}
 
 	for parser.matchKeyword("and") {
 		err = parser.eatKeyword("and")
@@ -561,16 +1772,76 @@ func (parser *Parser) predicate() (*domain.Predicate, error) {
 			return &domain.Predicate{}, errors.Err(err, "eatKeyword")
 		}
 
-		term, err := parser.term()
+		ts, err := parser.factor()
 		if err != nil {
 			return &domain.Predicate{}, err
 		}
-		terms = append(terms, term)
+		terms = append(terms, ts...)
 	}
 
 	return domain.NewPredicate(terms), nil
 }
 
+// factor parses negation, parenthesised predicate or comparison.
+// 括弧で囲まれた and の連言は外側の連言に展開する.
+func (parser *Parser) factor() ([]domain.Term, error) {
+	switch {
+	case parser.matchKeyword("not"):
+		err := parser.eatKeyword("not")
+		if err != nil {
+			return nil, errors.Err(err, "eatKeyword")
+		}
+
+		terms, err := parser.factor()
+		if err != nil {
+			return nil, err
+		}
+
+		return []domain.Term{domain.NewNotTerm(domain.NewPredicate(terms))}, nil
+	case parser.match(lexer.TLParen):
+		// ( は predicate と expression のどちらの開始にもなりうるので、
+		// predicate として読めなければ比較の左辺として読み直す.
+		pos := parser.pos
+		if terms, err := parser.parenthesizedPredicate(); err == nil {
+			return terms, nil
+		}
+		parser.pos = pos
+
+		term, err := parser.term()
+		if err != nil {
+			return nil, err
+		}
+
+		return []domain.Term{term}, nil
+	default:
+		term, err := parser.term()
+		if err != nil {
+			return nil, err
+		}
+
+		return []domain.Term{term}, nil
+	}
+}
+
+func (parser *Parser) parenthesizedPredicate() ([]domain.Term, error) {
+	err := parser.eatToken(lexer.TLParen)
+	if err != nil {
+		return nil, errors.Err(err, "eatToken")
+	}
+
+	pred, err := parser.predicate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = parser.eatToken(lexer.TRParen)
+	if err != nil {
+		return nil, errors.Err(err, "eatToken")
+	}
+
+	return pred.Terms(), nil
+}
+
 func (parser *Parser) field() (domain.FieldName, error) {
 	if parser.match(lexer.TStar) {
 		err := parser.eatToken(lexer.TStar)
@@ -598,9 +1869,95 @@ func (parser *Parser) table() (domain.TableName, error) {
 	return domain.NewTableName(id)
 }
 
+var expressionOperators = map[lexer.TokenType]domain.ExpressionOperator{
+	lexer.TPlus:    domain.AddOperator,
+	lexer.TMinus:   domain.SubtractOperator,
+	lexer.TStar:    domain.MultiplyOperator,
+	lexer.TSlash:   domain.DivideOperator,
+	lexer.TPercent: domain.ModuloOperator,
+	lexer.TConcat:  domain.ConcatOperator,
+}
+
+// expression parses an expression.
+// 優先順位は低い順に ||, + -, * / %, 単項 - となる.
 func (parser *Parser) expression() (domain.Expression, error) {
+	return parser.binaryExpression(
+		[]lexer.TokenType{lexer.TConcat},
+		func() (domain.Expression, error) {
+			return parser.binaryExpression(
+				[]lexer.TokenType{lexer.TPlus, lexer.TMinus},
+				func() (domain.Expression, error) {
+					return parser.binaryExpression(
+						[]lexer.TokenType{lexer.TStar, lexer.TSlash, lexer.TPercent},
+						parser.unaryExpression,
+					)
+				},
+			)
+		},
+	)
+}
+
+// binaryExpression parses left associative binary operations of ops whose operands are parsed by operand.
+func (parser *Parser) binaryExpression(ops []lexer.TokenType, operand func() (domain.Expression, error)) (domain.Expression, error) {
+	lhs, err := operand()
+	if err != nil {
+		return domain.Expression{}, err
+	}
+
+	for {
+		var typ lexer.TokenType
+		found := false
+		for _, op := range ops {
+			if parser.match(op) {
+				typ, found = op, true
+
+				break
+			}
+		}
+		if !found {
+			return lhs, nil
+		}
+
+		err := parser.eatToken(typ)
+		if err != nil {
+			return domain.Expression{}, errors.Err(err, "eatToken")
+		}
+
+		rhs, err := operand()
+		if err != nil {
+			return domain.Expression{}, err
+		}
+
+		lhs = domain.NewBinaryExpression(expressionOperators[typ], lhs, rhs)
+	}
+}
+
+func (parser *Parser) unaryExpression() (domain.Expression, error) {
+	if parser.match(lexer.TMinus) {
+		err := parser.eatToken(lexer.TMinus)
+		if err != nil {
+			return domain.Expression{}, errors.Err(err, "eatToken")
+		}
+
+		expr, err := parser.unaryExpression()
+		if err != nil {
+			return domain.Expression{}, err
+		}
+
+		return domain.NewNegateExpression(expr), nil
+	}
+
+	return parser.primaryExpression()
+}
+
+// primaryExpression parses a field, a literal, a function call or a parenthesized expression.
+// 型名や now は後続の token で literal や関数呼び出しか field 名かを判断する.
+func (parser *Parser) primaryExpression() (domain.Expression, error) {
 	switch {
-	case parser.match(lexer.TIdentifier):
+	case parser.matchKeyword("now") && parser.matchAt(parser.pos+1, lexer.TLParen),
+		parser.matchKeyword("current_date") || parser.matchKeyword("current_timestamp"):
+		return parser.currentTime()
+	case parser.matchIdentifier() && !parser.matchTypedLiteral():
 		id, err := parser.eatIdentifier()
 		if err != nil {
 			return domain.Expression{}, errors.Err(err, "eatIdentifier")
@@ -612,13 +1969,30 @@ func (parser *Parser) expression() (domain.Expression, error) {
 		}
 
 		return domain.NewFieldNameExpression(fldName), nil
-	case parser.match(lexer.TString) || parser.match(lexer.TInt32):
+	case parser.matchConstant():
 		c, err := parser.constant()
 		if err != nil {
 			return domain.Expression{}, err
 		}
 
 		return domain.NewConstExpression(c), nil
+	case parser.match(lexer.TLParen):
+		err := parser.eatToken(lexer.TLParen)
+		if err != nil {
+			return domain.Expression{}, errors.Err(err, "eatToken")
+		}
+
+		expr, err := parser.expression()
+		if err != nil {
+			return domain.Expression{}, err
+		}
+
+		err = parser.eatToken(lexer.TRParen)
+		if err != nil {
+			return domain.Expression{}, errors.Err(err, "eatToken")
+		}
+
+		return expr, nil
 	default:
 		return domain.Expression{}, ErrParse
 	}
@@ -640,17 +2014,69 @@ func (parser *Parser) constant() (domain.Constant, error) {
 		}
 
 		return domain.NewConstant(domain.Int32FieldType, num), nil
+	case parser.match(lexer.TInt64):
+		num, _ := parser.tokens[parser.pos].Value().(int64)
+		parser.pos++
+
+		return domain.NewConstant(domain.Int64FieldType, num), nil
+	case parser.match(lexer.TFloat64):
+		num, _ := parser.tokens[parser.pos].Value().(float64)
+		parser.pos++
+
+		return domain.NewConstant(domain.Float64FieldType, num), nil
+	case parser.matchKeyword("true") || parser.matchKeyword("false"):
+		b := parser.matchKeyword("true")
+		parser.pos++
+
+		return domain.NewConstant(domain.BoolFieldType, b), nil
+	case parser.matchKeyword("null"):
+		err := parser.eatKeyword("null")
+		if err != nil {
+			return domain.Constant{}, err
+		}
+
+		return domain.NewNullConstant(), nil
+	case parser.matchTypedLiteral():
+		return parser.typedLiteral()
 	default:
 		return domain.Constant{}, ErrParse
 	}
 }
 
+// matchConstant checks whether the current token is a literal.
+func (parser *Parser) matchConstant() bool {
+	return parser.match(lexer.TString) || parser.match(lexer.TInt32) || parser.match(lexer.TInt64) ||
+		parser.match(lexer.TFloat64) || parser.matchKeyword("true") || parser.matchKeyword("false") ||
+		parser.matchKeyword("null") || parser.matchTypedLiteral()
+}
+
+// matchTypedLiteral checks whether the current tokens are a type name followed by a string literal.
+// TIMESTAMP WITH TIME ZONE '...' も typed literal とする.
+func (parser *Parser) matchTypedLiteral() bool {
+	if !parser.matchDatetimeType() && !parser.matchNumericType() {
+		return false
+	}
+
+	return parser.matchAt(parser.pos+1, lexer.TString) ||
+		(parser.matchKeyword("timestamp") && parser.pos+1 < parser.len && parser.tokens[parser.pos+1].Value() == "with")
+}
+
 func (parser *Parser) match(typ lexer.TokenType) bool {
-	if parser.pos >= parser.len {
+	return parser.matchAt(parser.pos, typ)
+}
+
+// matchAt checks whether the token at pos has type typ.
+func (parser *Parser) matchAt(pos int, typ lexer.TokenType) bool {
+	if pos >= parser.len {
 		return false
 	}
 
-	return parser.tokens[parser.pos].Type() == typ
+	return parser.tokens[pos].Type() == typ
+}
+
+// matchIdentifier checks whether the current token can be used as an identifier.
+func (parser *Parser) matchIdentifier() bool {
+	return parser.pos < parser.len && parser.tokens[parser.pos].IsIdentifier()
 }
 
 func (parser *Parser) matchKeyword(kw string) bool {
@@ -671,7 +2097,7 @@ func (parser *Parser) eatKeyword(kw string) error {
 }
 
 func (parser *Parser) eatIdentifier() (string, error) {
-	if !parser.match(lexer.TIdentifier) {
+	if !parser.matchIdentifier() {
 		return "", ErrParse
 	}
 
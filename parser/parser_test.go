@@ -2,6 +2,7 @@ package parser_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/goropikari/simpledbgo/domain"
 	"github.com/goropikari/simpledbgo/lexer"
@@ -52,6 +53,340 @@ func TestParser_Query(t *testing.T) {
 				}),
 			),
 		},
+		{
+			name: "parse select with order by",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "order"),
+				lexer.NewToken(lexer.TKeyword, "by"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "desc"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TKeyword, "asc"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "age"),
+			},
+			expected: func() *domain.QueryData {
+				data := domain.NewQueryData(
+					[]domain.FieldName{"id"},
+					[]domain.TableName{"foo"},
+					&domain.Predicate{},
+				)
+				data.SetOrderBy([]domain.SortKey{
+					domain.NewSortKey("id", domain.Desc),
+					domain.NewSortKey("name", domain.Asc),
+					domain.NewSortKey("age", domain.Asc),
+				})
+
+				return data
+			}(),
+		},
+		{
+			name: "parse select with group by",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "dept"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "count"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TStar, "*"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "max"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "salary"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "emp"),
+				lexer.NewToken(lexer.TKeyword, "group"),
+				lexer.NewToken(lexer.TKeyword, "by"),
+				lexer.NewToken(lexer.TIdentifier, "dept"),
+			},
+			expected: func() *domain.QueryData {
+				data := domain.NewQueryData(
+					[]domain.FieldName{"dept", "countofall", "maxofsalary"},
+					[]domain.TableName{"emp"},
+					&domain.Predicate{},
+				)
+				data.SetGroupBy([]domain.FieldName{"dept"})
+				data.SetAggregations([]domain.Aggregation{
+					domain.NewAggregation(domain.CountAggregation, "*"),
+					domain.NewAggregation(domain.MaxAggregation, "salary"),
+				})
+
+				return data
+			}(),
+		},
+		{
+			name: "parse select with aggregation without group by",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "sum"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "salary"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "emp"),
+			},
+			expected: func() *domain.QueryData {
+				data := domain.NewQueryData(
+					[]domain.FieldName{"sumofsalary"},
+					[]domain.TableName{"emp"},
+					&domain.Predicate{},
+				)
+				data.SetGroupBy([]domain.FieldName{})
+				data.SetAggregations([]domain.Aggregation{
+					domain.NewAggregation(domain.SumAggregation, "salary"),
+				})
+
+				return data
+			}(),
+		},
+		{
+			name: "aggregation name as field",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "count"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "emp"),
+			},
+			expected: domain.NewQueryData(
+				[]domain.FieldName{"count"},
+				[]domain.TableName{"emp"},
+				&domain.Predicate{},
+			),
+		},
+		{
+			name: "parse select with boolean predicate",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "where"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TGreaterEqual, ">="),
+				lexer.NewToken(lexer.TInt32, int32(1)),
+				lexer.NewToken(lexer.TKeyword, "and"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TLess, "<"),
+				lexer.NewToken(lexer.TInt32, int32(10)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "and"),
+				lexer.NewToken(lexer.TKeyword, "not"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TNotEqual, "<>"),
+				lexer.NewToken(lexer.TInt32, int32(5)),
+				lexer.NewToken(lexer.TKeyword, "or"),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TEqual, "="),
+				lexer.NewToken(lexer.TString, "bob"),
+			},
+			expected: domain.NewQueryData(
+				[]domain.FieldName{"id"},
+				[]domain.TableName{"foo"},
+				domain.NewPredicate([]domain.Term{
+					domain.NewOrTerm([]*domain.Predicate{
+						domain.NewPredicate([]domain.Term{
+							domain.NewComparisonTerm(
+								domain.GreaterEqualOperator,
+								domain.NewFieldNameExpression("id"),
+								domain.NewConstExpression(domain.NewConstant(domain.Int32FieldType, int32(1))),
+							),
+							domain.NewComparisonTerm(
+								domain.LessOperator,
+								domain.NewFieldNameExpression("id"),
+								domain.NewConstExpression(domain.NewConstant(domain.Int32FieldType, int32(10))),
+							),
+							domain.NewNotTerm(domain.NewPredicate([]domain.Term{
+								domain.NewComparisonTerm(
+									domain.NotEqualOperator,
+									domain.NewFieldNameExpression("id"),
+									domain.NewConstExpression(domain.NewConstant(domain.Int32FieldType, int32(5))),
+								),
+							})),
+						}),
+						domain.NewPredicate([]domain.Term{
+							domain.NewTerm(
+								domain.NewFieldNameExpression("name"),
+								domain.NewConstExpression(domain.NewConstant(domain.StringFieldType, "bob")),
+							),
+						}),
+					}),
+				}),
+			),
+		},
+		{
+			name: "parse select with null test",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "where"),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TKeyword, "is"),
+				lexer.NewToken(lexer.TKeyword, "null"),
+				lexer.NewToken(lexer.TKeyword, "and"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TPlus, "+"),
+				lexer.NewToken(lexer.TInt32, int32(1)),
+				lexer.NewToken(lexer.TKeyword, "is"),
+				lexer.NewToken(lexer.TKeyword, "not"),
+				lexer.NewToken(lexer.TKeyword, "null"),
+				lexer.NewToken(lexer.TKeyword, "and"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TNotEqual, "<>"),
+				lexer.NewToken(lexer.TKeyword, "null"),
+			},
+			expected: domain.NewQueryData(
+				[]domain.FieldName{"id"},
+				[]domain.TableName{"foo"},
+				domain.NewPredicate([]domain.Term{
+					domain.NewIsNullTerm(domain.NewFieldNameExpression("name")),
+					domain.NewIsNotNullTerm(domain.NewBinaryExpression(
+						domain.AddOperator,
+						domain.NewFieldNameExpression("id"),
+						domain.NewConstExpression(domain.NewConstant(domain.Int32FieldType, int32(1))),
+					)),
+					domain.NewComparisonTerm(
+						domain.NotEqualOperator,
+						domain.NewFieldNameExpression("id"),
+						domain.NewConstExpression(domain.NewNullConstant()),
+					),
+				}),
+			),
+		},
+		{
+			name: "parse select with computed fields",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "price"),
+				lexer.NewToken(lexer.TStar, "*"),
+				lexer.NewToken(lexer.TIdentifier, "qty"),
+				lexer.NewToken(lexer.TKeyword, "as"),
+				lexer.NewToken(lexer.TIdentifier, "total"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TMinus, "-"),
+				lexer.NewToken(lexer.TIdentifier, "qty"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TKeyword, "as"),
+				lexer.NewToken(lexer.TIdentifier, "n"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "where"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "qty"),
+				lexer.NewToken(lexer.TPlus, "+"),
+				lexer.NewToken(lexer.TInt32, int32(1)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TGreater, ">"),
+				lexer.NewToken(lexer.TInt32, int32(2)),
+			},
+			expected: func() *domain.QueryData {
+				total := domain.NewBinaryExpression(
+					domain.MultiplyOperator,
+					domain.NewFieldNameExpression("price"),
+					domain.NewFieldNameExpression("qty"),
+				)
+				neg := domain.NewNegateExpression(domain.NewFieldNameExpression("qty"))
+				data := domain.NewQueryData(
+					[]domain.FieldName{"total", "-(qty)", "n"},
+					[]domain.TableName{"foo"},
+					domain.NewPredicate([]domain.Term{
+						domain.NewComparisonTerm(
+							domain.GreaterOperator,
+							domain.NewBinaryExpression(
+								domain.AddOperator,
+								domain.NewFieldNameExpression("qty"),
+								domain.NewConstExpression(domain.NewConstant(domain.Int32FieldType, int32(1))),
+							),
+							domain.NewConstExpression(domain.NewConstant(domain.Int32FieldType, int32(2))),
+						),
+					}),
+				)
+				data.SetExpressions(map[domain.FieldName]domain.Expression{
+					"total":  total,
+					"-(qty)": neg,
+					"n":      domain.NewFieldNameExpression("name"),
+				})
+
+				return data
+			}(),
+		},
+		{
+			name: "parse select with join",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TStar, "*"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "join"),
+				lexer.NewToken(lexer.TIdentifier, "bar"),
+				lexer.NewToken(lexer.TKeyword, "on"),
+				lexer.NewToken(lexer.TIdentifier, "a"),
+				lexer.NewToken(lexer.TEqual, "="),
+				lexer.NewToken(lexer.TIdentifier, "b"),
+				lexer.NewToken(lexer.TKeyword, "left"),
+				lexer.NewToken(lexer.TKeyword, "outer"),
+				lexer.NewToken(lexer.TKeyword, "join"),
+				lexer.NewToken(lexer.TIdentifier, "baz"),
+				lexer.NewToken(lexer.TKeyword, "on"),
+				lexer.NewToken(lexer.TIdentifier, "b"),
+				lexer.NewToken(lexer.TEqual, "="),
+				lexer.NewToken(lexer.TIdentifier, "c"),
+				lexer.NewToken(lexer.TKeyword, "and"),
+				lexer.NewToken(lexer.TIdentifier, "c"),
+				lexer.NewToken(lexer.TGreater, ">"),
+				lexer.NewToken(lexer.TInt32, int32(1)),
+				lexer.NewToken(lexer.TKeyword, "right"),
+				lexer.NewToken(lexer.TKeyword, "join"),
+				lexer.NewToken(lexer.TIdentifier, "qux"),
+				lexer.NewToken(lexer.TKeyword, "on"),
+				lexer.NewToken(lexer.TIdentifier, "c"),
+				lexer.NewToken(lexer.TEqual, "="),
+				lexer.NewToken(lexer.TIdentifier, "d"),
+				lexer.NewToken(lexer.TKeyword, "where"),
+				lexer.NewToken(lexer.TIdentifier, "a"),
+				lexer.NewToken(lexer.TEqual, "="),
+				lexer.NewToken(lexer.TInt32, int32(1)),
+			},
+			expected: func() *domain.QueryData {
+				equal := func(lhs, rhs domain.FieldName) domain.Term {
+					return domain.NewTerm(domain.NewFieldNameExpression(lhs), domain.NewFieldNameExpression(rhs))
+				}
+				data := domain.NewQueryData(
+					[]domain.FieldName{"*"},
+					[]domain.TableName{"foo"},
+					domain.NewPredicate([]domain.Term{
+						domain.NewTerm(
+							domain.NewFieldNameExpression("a"),
+							domain.NewConstExpression(domain.NewConstant(domain.Int32FieldType, int32(1))),
+						),
+					}),
+				)
+				data.SetJoins([]*domain.JoinData{
+					domain.NewJoinData(domain.InnerJoin, "bar", domain.NewPredicate([]domain.Term{equal("a", "b")})),
+					domain.NewJoinData(domain.LeftOuterJoin, "baz", domain.NewPredicate([]domain.Term{
+						equal("b", "c"),
+						domain.NewComparisonTerm(
+							domain.GreaterOperator,
+							domain.NewFieldNameExpression("c"),
+							domain.NewConstExpression(domain.NewConstant(domain.Int32FieldType, int32(1))),
+						),
+					})),
+					domain.NewJoinData(domain.RightOuterJoin, "qux", domain.NewPredicate([]domain.Term{equal("c", "d")})),
+				})
+
+				return data
+			}(),
+		},
 	}
 
 	for _, tt := range tests {
@@ -88,6 +423,92 @@ func TestParser_Error(t *testing.T) {
 				lexer.NewToken(lexer.TComma, ","),
 			},
 		},
+		{
+			name: "invalid date literal",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "where"),
+				lexer.NewToken(lexer.TIdentifier, "d"),
+				lexer.NewToken(lexer.TEqual, "="),
+				lexer.NewToken(lexer.TKeyword, "date"),
+				lexer.NewToken(lexer.TString, "2024-13-01"),
+			},
+		},
+		{
+			name: "numeric scale exceeds precision",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "n"),
+				lexer.NewToken(lexer.TKeyword, "numeric"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(2)),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TInt32, int32(3)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+		},
+		{
+			name: "invalid numeric literal",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "where"),
+				lexer.NewToken(lexer.TIdentifier, "n"),
+				lexer.NewToken(lexer.TEqual, "="),
+				lexer.NewToken(lexer.TKeyword, "numeric"),
+				lexer.NewToken(lexer.TString, "1.2.3"),
+			},
+		},
+		{
+			name: "now without closing parenthesis",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "where"),
+				lexer.NewToken(lexer.TIdentifier, "t"),
+				lexer.NewToken(lexer.TLess, "<"),
+				lexer.NewToken(lexer.TKeyword, "now"),
+				lexer.NewToken(lexer.TLParen, "("),
+			},
+		},
+		{
+			name: "join without on",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TStar, "*"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "join"),
+				lexer.NewToken(lexer.TIdentifier, "bar"),
+			},
+		},
+		{
+			name: "left outer without join",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TStar, "*"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "left"),
+				lexer.NewToken(lexer.TKeyword, "outer"),
+				lexer.NewToken(lexer.TIdentifier, "bar"),
+				lexer.NewToken(lexer.TKeyword, "on"),
+				lexer.NewToken(lexer.TIdentifier, "a"),
+				lexer.NewToken(lexer.TEqual, "="),
+				lexer.NewToken(lexer.TIdentifier, "b"),
+			},
+		},
 		{
 			name: "missing from",
 			tokens: []lexer.Token{
@@ -139,54 +560,302 @@ func TestParser_Error(t *testing.T) {
 				lexer.NewToken(lexer.TComma, ","),
 				lexer.NewToken(lexer.TIdentifier, "id"),
 				lexer.NewToken(lexer.TComma, ","),
-				lexer.NewToken(lexer.TIdentifier, "name"),
-				lexer.NewToken(lexer.TKeyword, "from"),
-				lexer.NewToken(lexer.TIdentifier, "foo_bar"),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo_bar"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "fizz_baz"),
+				lexer.NewToken(lexer.TKeyword, "where"),
+				lexer.NewToken(lexer.TEqual, "="),
+			},
+		},
+		{
+			name: "missing =",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TStar, "*"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo_bar"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "fizz_baz"),
+				lexer.NewToken(lexer.TKeyword, "where"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+			},
+		},
+		{
+			name: "missing by",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo_bar"),
+				lexer.NewToken(lexer.TKeyword, "order"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+			},
+		},
+		{
+			name: "missing sort key",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo_bar"),
+				lexer.NewToken(lexer.TKeyword, "order"),
+				lexer.NewToken(lexer.TKeyword, "by"),
+				lexer.NewToken(lexer.TKeyword, "desc"),
+			},
+		},
+		{
+			name: "missing rparen of predicate",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo_bar"),
+				lexer.NewToken(lexer.TKeyword, "where"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TLess, "<"),
+				lexer.NewToken(lexer.TInt32, int32(1)),
+			},
+		},
+		{
+			name: "missing rhs of or",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo_bar"),
+				lexer.NewToken(lexer.TKeyword, "where"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TLess, "<"),
+				lexer.NewToken(lexer.TInt32, int32(1)),
+				lexer.NewToken(lexer.TKeyword, "or"),
+			},
+		},
+		{
+			name: "alias of star",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TStar, "*"),
+				lexer.NewToken(lexer.TKeyword, "as"),
+				lexer.NewToken(lexer.TIdentifier, "x"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo_bar"),
+			},
+		},
+		{
+			name: "missing operand",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TPlus, "+"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo_bar"),
+			},
+		},
+		{
+			name: "computed field not in group by",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TPlus, "+"),
+				lexer.NewToken(lexer.TIdentifier, "age"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "count"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TStar, "*"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo_bar"),
+				lexer.NewToken(lexer.TKeyword, "group"),
+				lexer.NewToken(lexer.TKeyword, "by"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+			},
+		},
+		{
+			name: "field not in group by",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "count"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo_bar"),
+			},
+		},
+		{
+			name: "sum of star",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "sum"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TStar, "*"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo_bar"),
+			},
+		},
+		{
+			name: "missing rparen of aggregation",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "select"),
+				lexer.NewToken(lexer.TIdentifier, "max"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "from"),
+				lexer.NewToken(lexer.TIdentifier, "foo_bar"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+
+			p := parser.NewParser(tt.tokens)
+			_, err := p.Query()
+
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParser_ExecCmd_Insert(t *testing.T) {
+	tests := []struct {
+		name     string
+		tokens   []lexer.Token
+		expected *domain.InsertData
+	}{
+		{
+			name: "parse insert",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "insert"),
+				lexer.NewToken(lexer.TKeyword, "into"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "values"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(123)),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TString, "mike"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+			expected: domain.NewInsertData(
+				domain.TableName("foo"),
+				[]domain.FieldName{"id", "name"},
+				[]domain.Constant{
+					domain.NewConstant(domain.Int32FieldType, int32(123)),
+					domain.NewConstant(domain.StringFieldType, "mike"),
+				},
+			),
+		},
+		{
+			name: "parse insert numeric and boolean literals",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "insert"),
+				lexer.NewToken(lexer.TKeyword, "into"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "b"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "d"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "f"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "values"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt64, int64(1)<<40),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TFloat64, 1.5),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TKeyword, "true"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+			expected: domain.NewInsertData(
+				domain.TableName("foo"),
+				[]domain.FieldName{"b", "d", "f"},
+				[]domain.Constant{
+					domain.NewConstant(domain.Int64FieldType, int64(1)<<40),
+					domain.NewConstant(domain.Float64FieldType, 1.5),
+					domain.NewConstant(domain.BoolFieldType, true),
+				},
+			),
+		},
+		{
+			name: "parse insert date/time literals",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "insert"),
+				lexer.NewToken(lexer.TKeyword, "into"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "d"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "tz"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "i"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "values"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TKeyword, "date"),
+				lexer.NewToken(lexer.TString, "2024-01-02"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TKeyword, "timestamp"),
+				lexer.NewToken(lexer.TKeyword, "with"),
+				lexer.NewToken(lexer.TKeyword, "time"),
+				lexer.NewToken(lexer.TKeyword, "zone"),
+				lexer.NewToken(lexer.TString, "2024-01-02 09:00:00+09"),
 				lexer.NewToken(lexer.TComma, ","),
-				lexer.NewToken(lexer.TIdentifier, "fizz_baz"),
-				lexer.NewToken(lexer.TKeyword, "where"),
-				lexer.NewToken(lexer.TEqual, "="),
+				lexer.NewToken(lexer.TKeyword, "interval"),
+				lexer.NewToken(lexer.TString, "1 day"),
+				lexer.NewToken(lexer.TRParen, ")"),
 			},
+			expected: domain.NewInsertData(
+				domain.TableName("foo"),
+				[]domain.FieldName{"d", "tz", "i"},
+				[]domain.Constant{
+					domain.NewDateConstant(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+					domain.NewTimestampConstant(domain.TimestampTzFieldType, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+					domain.NewIntervalConstant(domain.Interval{Days: 1}),
+				},
+			),
 		},
 		{
-			name: "missing =",
+			name: "parse insert numeric literal",
 			tokens: []lexer.Token{
-				lexer.NewToken(lexer.TKeyword, "select"),
-				lexer.NewToken(lexer.TStar, "*"),
-				lexer.NewToken(lexer.TComma, ","),
-				lexer.NewToken(lexer.TIdentifier, "id"),
-				lexer.NewToken(lexer.TComma, ","),
-				lexer.NewToken(lexer.TIdentifier, "name"),
-				lexer.NewToken(lexer.TKeyword, "from"),
-				lexer.NewToken(lexer.TIdentifier, "foo_bar"),
-				lexer.NewToken(lexer.TComma, ","),
-				lexer.NewToken(lexer.TIdentifier, "fizz_baz"),
-				lexer.NewToken(lexer.TKeyword, "where"),
-				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "insert"),
+				lexer.NewToken(lexer.TKeyword, "into"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "n"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "values"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TKeyword, "numeric"),
+				lexer.NewToken(lexer.TString, "19.990"),
+				lexer.NewToken(lexer.TRParen, ")"),
 			},
+			expected: domain.NewInsertData(
+				domain.TableName("foo"),
+				[]domain.FieldName{"n"},
+				[]domain.Constant{
+					domain.NewDecimalConstant(domain.Decimal{Lo: 19990, Scale: 3}),
+				},
+			),
 		},
-	}
-
-	for _, tt := range tests {
-		tt := tt
-		t.Run(tt.name, func(t *testing.T) {
-
-			p := parser.NewParser(tt.tokens)
-			_, err := p.Query()
-
-			require.Error(t, err)
-		})
-	}
-}
-
-func TestParser_ExecCmd_Insert(t *testing.T) {
-	tests := []struct {
-		name     string
-		tokens   []lexer.Token
-		expected *domain.InsertData
-	}{
 		{
-			name: "parse insert",
+			name: "parse insert null",
 			tokens: []lexer.Token{
 				lexer.NewToken(lexer.TKeyword, "insert"),
 				lexer.NewToken(lexer.TKeyword, "into"),
@@ -200,7 +869,7 @@ func TestParser_ExecCmd_Insert(t *testing.T) {
 				lexer.NewToken(lexer.TLParen, "("),
 				lexer.NewToken(lexer.TInt32, int32(123)),
 				lexer.NewToken(lexer.TComma, ","),
-				lexer.NewToken(lexer.TString, "mike"),
+				lexer.NewToken(lexer.TKeyword, "null"),
 				lexer.NewToken(lexer.TRParen, ")"),
 			},
 			expected: domain.NewInsertData(
@@ -208,7 +877,7 @@ func TestParser_ExecCmd_Insert(t *testing.T) {
 				[]domain.FieldName{"id", "name"},
 				[]domain.Constant{
 					domain.NewConstant(domain.Int32FieldType, int32(123)),
-					domain.NewConstant(domain.StringFieldType, "mike"),
+					domain.NewNullConstant(),
 				},
 			),
 		},
@@ -357,6 +1026,40 @@ func TestParser_ExecCmd_Insert_Error(t *testing.T) {
 				lexer.NewToken(lexer.TString, "mike"),
 			},
 		},
+		{
+			name: "fewer values than fields",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "insert"),
+				lexer.NewToken(lexer.TKeyword, "into"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "values"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(123)),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+		},
+		{
+			name: "more values than fields",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "insert"),
+				lexer.NewToken(lexer.TKeyword, "into"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "values"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(123)),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TString, "mike"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -516,6 +1219,43 @@ func TestParser_ExecCmd_Modify(t *testing.T) {
 				&domain.Predicate{},
 			),
 		},
+		{
+			name: "parse update cmd with arithmetic",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "update"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "set"),
+				lexer.NewToken(lexer.TIdentifier, "age"),
+				lexer.NewToken(lexer.TEqual, "="),
+				lexer.NewToken(lexer.TIdentifier, "age"),
+				lexer.NewToken(lexer.TPlus, "+"),
+				lexer.NewToken(lexer.TInt32, int32(1)),
+				lexer.NewToken(lexer.TStar, "*"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(2)),
+				lexer.NewToken(lexer.TMinus, "-"),
+				lexer.NewToken(lexer.TIdentifier, "x"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+			expected: domain.NewModifyData(
+				domain.TableName("foo"),
+				domain.FieldName("age"),
+				domain.NewBinaryExpression(
+					domain.AddOperator,
+					domain.NewFieldNameExpression("age"),
+					domain.NewBinaryExpression(
+						domain.MultiplyOperator,
+						domain.NewConstExpression(domain.NewConstant(domain.Int32FieldType, int32(1))),
+						domain.NewBinaryExpression(
+							domain.SubtractOperator,
+							domain.NewConstExpression(domain.NewConstant(domain.Int32FieldType, int32(2))),
+							domain.NewFieldNameExpression("x"),
+						),
+					),
+				),
+				&domain.Predicate{},
+			),
+		},
 		{
 			name: "parse update cmd with predicate",
 			tokens: []lexer.Token{
@@ -613,60 +1353,347 @@ func TestParser_ExecCmd_Modify_Error(t *testing.T) {
 			tokens: []lexer.Token{
 				lexer.NewToken(lexer.TKeyword, "update"),
 				lexer.NewToken(lexer.TIdentifier, "foo"),
-				lexer.NewToken(lexer.TKeyword, "set"),
+				lexer.NewToken(lexer.TKeyword, "set"),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TString, "mike"),
+			},
+		},
+		{
+			name: "missing value",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "update"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "set"),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TEqual, "="),
+			},
+		},
+		{
+			name: "missing predicate field",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "update"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "set"),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TEqual, "="),
+				lexer.NewToken(lexer.TString, "mike"),
+				lexer.NewToken(lexer.TKeyword, "where"),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+
+			p := parser.NewParser(tt.tokens)
+			_, err := p.ExecCmd()
+
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParser_ExecCmd_CreateTable(t *testing.T) {
+	sch := domain.NewSchema()
+	sch.AddInt32Field("id")
+	sch.AddStringField("name", 255)
+
+	// primary key の field は NOT NULL になる.
+	pkSch := domain.NewSchema()
+	pkSch.AddInt32Field("id")
+	pkSch.AddStringField("name", 255)
+	pkSch.SetFieldConstraint("id", domain.NewFieldConstraint().WithNotNull())
+
+	fcSch := domain.NewSchema()
+	fcSch.AddInt32Field("id")
+	fcSch.AddStringField("name", 255)
+	fcSch.SetFieldConstraint("id", domain.NewFieldConstraint().
+		WithNotNull().
+		WithDefault(domain.NewConstant(domain.Int32FieldType, int32(-1))).
+		WithCheck(domain.NewPredicate([]domain.Term{
+			domain.NewComparisonTerm(
+				domain.NotEqualOperator,
+				domain.NewFieldNameExpression("id"),
+				domain.NewConstExpression(domain.NewConstant(domain.Int32FieldType, int32(0))),
+			),
+		})))
+	fcSch.SetFieldConstraint("name", domain.NewFieldConstraint().
+		WithDefault(domain.NewConstant(domain.StringFieldType, "none")))
+
+	textSch := domain.NewSchema()
+	textSch.AddTextField("body")
+	textSch.AddBytesField("data")
+
+	numSch := domain.NewSchema()
+	numSch.AddField("s", domain.Int16FieldType, 0)
+	numSch.AddField("b", domain.Int64FieldType, 0)
+	numSch.AddField("f", domain.BoolFieldType, 0)
+	numSch.AddField("r", domain.Float32FieldType, 0)
+	numSch.AddField("d", domain.Float64FieldType, 0)
+
+	dtSch := domain.NewSchema()
+	dtSch.AddField("d", domain.DateFieldType, 0)
+	dtSch.AddField("t", domain.TimestampFieldType, 0)
+	dtSch.AddField("tz", domain.TimestampTzFieldType, 0)
+	dtSch.AddField("tz2", domain.TimestampTzFieldType, 0)
+	dtSch.AddField("i", domain.IntervalFieldType, 0)
+
+	decSch := domain.NewSchema()
+	decSch.AddField("n", domain.NumericFieldType, 0)
+	decSch.AddField("p", domain.NumericFieldType, 10<<16)
+	decSch.AddField("ps", domain.NumericFieldType, 10<<16|2)
+
+	tests := []struct {
+		name     string
+		tokens   []lexer.Token
+		expected *domain.CreateTableData
+	}{
+		{
+			name: "parse create table",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TKeyword, "varchar"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(255)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+			expected: domain.NewCreateTableData(
+				domain.TableName("foo"),
+				sch,
+				[]domain.Constraint{},
+				domain.DefaultRecordFormat,
+			),
+		},
+		{
+			name: "parse create table with text and bytea",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "body"),
+				lexer.NewToken(lexer.TKeyword, "text"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "data"),
+				lexer.NewToken(lexer.TKeyword, "bytea"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+			expected: domain.NewCreateTableData(
+				domain.TableName("foo"),
+				textSch,
+				[]domain.Constraint{},
+				domain.DefaultRecordFormat,
+			),
+		},
+		{
+			name: "parse create table with numeric and boolean types",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "s"),
+				lexer.NewToken(lexer.TKeyword, "smallint"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "b"),
+				lexer.NewToken(lexer.TKeyword, "bigint"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "f"),
+				lexer.NewToken(lexer.TKeyword, "boolean"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "r"),
+				lexer.NewToken(lexer.TKeyword, "real"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "d"),
+				lexer.NewToken(lexer.TKeyword, "double"),
+				lexer.NewToken(lexer.TKeyword, "precision"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+			expected: domain.NewCreateTableData(
+				domain.TableName("foo"),
+				numSch,
+				[]domain.Constraint{},
+				domain.DefaultRecordFormat,
+			),
+		},
+		{
+			name: "parse create table with date/time types",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "d"),
+				lexer.NewToken(lexer.TKeyword, "date"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "t"),
+				lexer.NewToken(lexer.TKeyword, "timestamp"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "tz"),
+				lexer.NewToken(lexer.TKeyword, "timestamp"),
+				lexer.NewToken(lexer.TKeyword, "with"),
+				lexer.NewToken(lexer.TKeyword, "time"),
+				lexer.NewToken(lexer.TKeyword, "zone"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "tz2"),
+				lexer.NewToken(lexer.TKeyword, "timestamptz"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "i"),
+				lexer.NewToken(lexer.TKeyword, "interval"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+			expected: domain.NewCreateTableData(
+				domain.TableName("foo"),
+				dtSch,
+				[]domain.Constraint{},
+				domain.DefaultRecordFormat,
+			),
+		},
+		{
+			name: "parse create table with numeric types",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "n"),
+				lexer.NewToken(lexer.TKeyword, "numeric"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "p"),
+				lexer.NewToken(lexer.TKeyword, "decimal"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(10)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "ps"),
+				lexer.NewToken(lexer.TKeyword, "numeric"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(10)),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TInt32, int32(2)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+			expected: domain.NewCreateTableData(
+				domain.TableName("foo"),
+				decSch,
+				[]domain.Constraint{},
+				domain.DefaultRecordFormat,
+			),
+		},
+		{
+			name: "parse create table using slotted",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TComma, ","),
 				lexer.NewToken(lexer.TIdentifier, "name"),
-				lexer.NewToken(lexer.TString, "mike"),
+				lexer.NewToken(lexer.TKeyword, "varchar"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(255)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "using"),
+				lexer.NewToken(lexer.TIdentifier, "slotted"),
 			},
+			expected: domain.NewCreateTableData(
+				domain.TableName("foo"),
+				sch,
+				[]domain.Constraint{},
+				domain.SlottedRecordFormat,
+			),
 		},
 		{
-			name: "missing value",
+			name: "parse create table with constraints",
 			tokens: []lexer.Token{
-				lexer.NewToken(lexer.TKeyword, "update"),
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
 				lexer.NewToken(lexer.TIdentifier, "foo"),
-				lexer.NewToken(lexer.TKeyword, "set"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TKeyword, "primary"),
+				lexer.NewToken(lexer.TKeyword, "key"),
+				lexer.NewToken(lexer.TComma, ","),
 				lexer.NewToken(lexer.TIdentifier, "name"),
-				lexer.NewToken(lexer.TEqual, "="),
+				lexer.NewToken(lexer.TKeyword, "varchar"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(255)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "unique"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TKeyword, "unique"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TRParen, ")"),
 			},
+			expected: domain.NewCreateTableData(
+				domain.TableName("foo"),
+				pkSch,
+				[]domain.Constraint{
+					domain.NewConstraint(domain.PrimaryKeyConstraint, []domain.FieldName{"id"}),
+					domain.NewConstraint(domain.UniqueConstraint, []domain.FieldName{"name"}),
+					domain.NewConstraint(domain.UniqueConstraint, []domain.FieldName{"id", "name"}),
+				},
+				domain.DefaultRecordFormat,
+			),
 		},
 		{
-			name: "missing predicate field",
+			name: "parse create table with field constraints",
 			tokens: []lexer.Token{
-				lexer.NewToken(lexer.TKeyword, "update"),
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
 				lexer.NewToken(lexer.TIdentifier, "foo"),
-				lexer.NewToken(lexer.TKeyword, "set"),
-				lexer.NewToken(lexer.TIdentifier, "name"),
-				lexer.NewToken(lexer.TEqual, "="),
-				lexer.NewToken(lexer.TString, "mike"),
-				lexer.NewToken(lexer.TKeyword, "where"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TKeyword, "not"),
+				lexer.NewToken(lexer.TKeyword, "null"),
+				lexer.NewToken(lexer.TKeyword, "default"),
+				lexer.NewToken(lexer.TInt32, int32(-1)),
+				lexer.NewToken(lexer.TKeyword, "check"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TNotEqual, "<>"),
+				lexer.NewToken(lexer.TInt32, int32(0)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TComma, ","),
 				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TKeyword, "varchar"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(255)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "null"),
+				lexer.NewToken(lexer.TKeyword, "default"),
+				lexer.NewToken(lexer.TString, "none"),
+				lexer.NewToken(lexer.TRParen, ")"),
 			},
+			expected: domain.NewCreateTableData(
+				domain.TableName("foo"),
+				fcSch,
+				[]domain.Constraint{},
+				domain.DefaultRecordFormat,
+			),
 		},
-	}
-
-	for _, tt := range tests {
-		tt := tt
-		t.Run(tt.name, func(t *testing.T) {
-
-			p := parser.NewParser(tt.tokens)
-			_, err := p.ExecCmd()
-
-			require.Error(t, err)
-		})
-	}
-}
-
-func TestParser_ExecCmd_CreateTable(t *testing.T) {
-	sch := domain.NewSchema()
-	sch.AddInt32Field("id")
-	sch.AddStringField("name", 255)
-
-	tests := []struct {
-		name     string
-		tokens   []lexer.Token
-		expected *domain.CreateTableData
-	}{
 		{
-			name: "parse create table",
+			name: "parse create table with foreign keys",
 			tokens: []lexer.Token{
 				lexer.NewToken(lexer.TKeyword, "create"),
 				lexer.NewToken(lexer.TKeyword, "table"),
@@ -674,17 +1701,52 @@ func TestParser_ExecCmd_CreateTable(t *testing.T) {
 				lexer.NewToken(lexer.TLParen, "("),
 				lexer.NewToken(lexer.TIdentifier, "id"),
 				lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TKeyword, "references"),
+				lexer.NewToken(lexer.TIdentifier, "bar"),
 				lexer.NewToken(lexer.TComma, ","),
 				lexer.NewToken(lexer.TIdentifier, "name"),
 				lexer.NewToken(lexer.TKeyword, "varchar"),
 				lexer.NewToken(lexer.TLParen, "("),
 				lexer.NewToken(lexer.TInt32, int32(255)),
 				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "references"),
+				lexer.NewToken(lexer.TIdentifier, "bar"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "on"),
+				lexer.NewToken(lexer.TKeyword, "delete"),
+				lexer.NewToken(lexer.TKeyword, "cascade"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TKeyword, "foreign"),
+				lexer.NewToken(lexer.TKeyword, "key"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "references"),
+				lexer.NewToken(lexer.TIdentifier, "baz"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "a"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "b"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "on"),
+				lexer.NewToken(lexer.TKeyword, "delete"),
+				lexer.NewToken(lexer.TKeyword, "set"),
+				lexer.NewToken(lexer.TKeyword, "null"),
 				lexer.NewToken(lexer.TRParen, ")"),
 			},
 			expected: domain.NewCreateTableData(
 				domain.TableName("foo"),
 				sch,
+				[]domain.Constraint{
+					domain.NewForeignKeyConstraint([]domain.FieldName{"id"}, "bar", nil, domain.RestrictAction),
+					domain.NewForeignKeyConstraint([]domain.FieldName{"name"}, "bar", []domain.FieldName{"name"}, domain.CascadeAction),
+					domain.NewForeignKeyConstraint([]domain.FieldName{"id", "name"}, "baz", []domain.FieldName{"a", "b"}, domain.SetNullAction),
+				},
+				domain.DefaultRecordFormat,
 			),
 		},
 	}
@@ -806,25 +1868,188 @@ func TestParser_ExecCmd_CreateTable_Error(t *testing.T) {
 			},
 		},
 		{
-			name: "missing type",
+			name: "missing type",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				// lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TKeyword, "varchar"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(255)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+		},
+		{
+			name: "missing comma",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "int"),
+				// lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TKeyword, "varchar"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(255)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+		},
+		{
+			name: "missing varchar left paren",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TKeyword, "varchar"),
+				// lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(255)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+		},
+		{
+			name: "missing varchar size",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TKeyword, "varchar"),
+				lexer.NewToken(lexer.TLParen, "("),
+				// lexer.NewToken(lexer.TInt32, int32(255)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+		},
+		{
+			name: "missing varchar right paren",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "name"),
+				lexer.NewToken(lexer.TKeyword, "varchar"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(255)),
+				// lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+		},
+		{
+			name: "missing key",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TKeyword, "primary"),
+				// lexer.NewToken(lexer.TKeyword, "key"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+		},
+		{
+			name: "missing constraint field",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TKeyword, "unique"),
+				lexer.NewToken(lexer.TLParen, "("),
+				// lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+		},
+		{
+			name: "missing null after not",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TKeyword, "not"),
+				// lexer.NewToken(lexer.TKeyword, "null"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+		},
+		{
+			name: "missing default value",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TKeyword, "default"),
+				// lexer.NewToken(lexer.TInt32, int32(1)),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+		},
+		{
+			name: "missing check paren",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TKeyword, "check"),
+				// lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TGreater, ">"),
+				lexer.NewToken(lexer.TInt32, int32(0)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+		},
+		{
+			name: "missing referenced table",
 			tokens: []lexer.Token{
 				lexer.NewToken(lexer.TKeyword, "create"),
 				lexer.NewToken(lexer.TKeyword, "table"),
 				lexer.NewToken(lexer.TIdentifier, "foo"),
 				lexer.NewToken(lexer.TLParen, "("),
 				lexer.NewToken(lexer.TIdentifier, "id"),
-				// lexer.NewToken(lexer.TKeyword, "int"),
-				lexer.NewToken(lexer.TComma, ","),
-				lexer.NewToken(lexer.TIdentifier, "name"),
-				lexer.NewToken(lexer.TKeyword, "varchar"),
-				lexer.NewToken(lexer.TLParen, "("),
-				lexer.NewToken(lexer.TInt32, int32(255)),
-				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TKeyword, "references"),
+				// lexer.NewToken(lexer.TIdentifier, "bar"),
 				lexer.NewToken(lexer.TRParen, ")"),
 			},
 		},
 		{
-			name: "missing comma",
+			name: "missing foreign key references",
 			tokens: []lexer.Token{
 				lexer.NewToken(lexer.TKeyword, "create"),
 				lexer.NewToken(lexer.TKeyword, "table"),
@@ -832,17 +2057,19 @@ func TestParser_ExecCmd_CreateTable_Error(t *testing.T) {
 				lexer.NewToken(lexer.TLParen, "("),
 				lexer.NewToken(lexer.TIdentifier, "id"),
 				lexer.NewToken(lexer.TKeyword, "int"),
-				// lexer.NewToken(lexer.TComma, ","),
-				lexer.NewToken(lexer.TIdentifier, "name"),
-				lexer.NewToken(lexer.TKeyword, "varchar"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TKeyword, "foreign"),
+				lexer.NewToken(lexer.TKeyword, "key"),
 				lexer.NewToken(lexer.TLParen, "("),
-				lexer.NewToken(lexer.TInt32, int32(255)),
+				lexer.NewToken(lexer.TIdentifier, "id"),
 				lexer.NewToken(lexer.TRParen, ")"),
+				// lexer.NewToken(lexer.TKeyword, "references"),
+				lexer.NewToken(lexer.TIdentifier, "bar"),
 				lexer.NewToken(lexer.TRParen, ")"),
 			},
 		},
 		{
-			name: "missing varchar left paren",
+			name: "unknown on delete action",
 			tokens: []lexer.Token{
 				lexer.NewToken(lexer.TKeyword, "create"),
 				lexer.NewToken(lexer.TKeyword, "table"),
@@ -850,17 +2077,17 @@ func TestParser_ExecCmd_CreateTable_Error(t *testing.T) {
 				lexer.NewToken(lexer.TLParen, "("),
 				lexer.NewToken(lexer.TIdentifier, "id"),
 				lexer.NewToken(lexer.TKeyword, "int"),
-				lexer.NewToken(lexer.TComma, ","),
-				lexer.NewToken(lexer.TIdentifier, "name"),
-				lexer.NewToken(lexer.TKeyword, "varchar"),
-				// lexer.NewToken(lexer.TLParen, "("),
-				lexer.NewToken(lexer.TInt32, int32(255)),
-				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "references"),
+				lexer.NewToken(lexer.TIdentifier, "bar"),
+				lexer.NewToken(lexer.TKeyword, "on"),
+				lexer.NewToken(lexer.TKeyword, "delete"),
+				lexer.NewToken(lexer.TKeyword, "set"),
+				lexer.NewToken(lexer.TKeyword, "default"),
 				lexer.NewToken(lexer.TRParen, ")"),
 			},
 		},
 		{
-			name: "missing varchar size",
+			name: "multiple primary keys",
 			tokens: []lexer.Token{
 				lexer.NewToken(lexer.TKeyword, "create"),
 				lexer.NewToken(lexer.TKeyword, "table"),
@@ -868,17 +2095,19 @@ func TestParser_ExecCmd_CreateTable_Error(t *testing.T) {
 				lexer.NewToken(lexer.TLParen, "("),
 				lexer.NewToken(lexer.TIdentifier, "id"),
 				lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TKeyword, "primary"),
+				lexer.NewToken(lexer.TKeyword, "key"),
 				lexer.NewToken(lexer.TComma, ","),
-				lexer.NewToken(lexer.TIdentifier, "name"),
-				lexer.NewToken(lexer.TKeyword, "varchar"),
+				lexer.NewToken(lexer.TKeyword, "primary"),
+				lexer.NewToken(lexer.TKeyword, "key"),
 				lexer.NewToken(lexer.TLParen, "("),
-				// lexer.NewToken(lexer.TInt32, int32(255)),
+				lexer.NewToken(lexer.TIdentifier, "id"),
 				lexer.NewToken(lexer.TRParen, ")"),
 				lexer.NewToken(lexer.TRParen, ")"),
 			},
 		},
 		{
-			name: "missing varchar right paren",
+			name: "unknown record format",
 			tokens: []lexer.Token{
 				lexer.NewToken(lexer.TKeyword, "create"),
 				lexer.NewToken(lexer.TKeyword, "table"),
@@ -886,13 +2115,9 @@ func TestParser_ExecCmd_CreateTable_Error(t *testing.T) {
 				lexer.NewToken(lexer.TLParen, "("),
 				lexer.NewToken(lexer.TIdentifier, "id"),
 				lexer.NewToken(lexer.TKeyword, "int"),
-				lexer.NewToken(lexer.TComma, ","),
-				lexer.NewToken(lexer.TIdentifier, "name"),
-				lexer.NewToken(lexer.TKeyword, "varchar"),
-				lexer.NewToken(lexer.TLParen, "("),
-				lexer.NewToken(lexer.TInt32, int32(255)),
-				// lexer.NewToken(lexer.TRParen, ")"),
 				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "using"),
+				lexer.NewToken(lexer.TIdentifier, "heap"),
 			},
 		},
 	}
@@ -1153,7 +2378,50 @@ func TestParser_ExecCmd_CreateIndex(t *testing.T) {
 			expected: domain.NewCreateIndexData(
 				domain.IndexName("idx_id"),
 				domain.TableName("foo"),
-				domain.FieldName("id"),
+				[]domain.FieldName{"id"},
+				domain.BTreeIndexType,
+			),
+		},
+		{
+			name: "parse create index using hash",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "index"),
+				lexer.NewToken(lexer.TIdentifier, "idx_id"),
+				lexer.NewToken(lexer.TKeyword, "on"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "using"),
+				lexer.NewToken(lexer.TIdentifier, "HASH"),
+			},
+			expected: domain.NewCreateIndexData(
+				domain.IndexName("idx_id"),
+				domain.TableName("foo"),
+				[]domain.FieldName{"id"},
+				domain.HashIndexType,
+			),
+		},
+		{
+			name: "parse create composite index",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "create"),
+				lexer.NewToken(lexer.TKeyword, "index"),
+				lexer.NewToken(lexer.TIdentifier, "idx_tenant_id"),
+				lexer.NewToken(lexer.TKeyword, "on"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TIdentifier, "tenant_id"),
+				lexer.NewToken(lexer.TComma, ","),
+				lexer.NewToken(lexer.TIdentifier, "id"),
+				lexer.NewToken(lexer.TRParen, ")"),
+			},
+			expected: domain.NewCreateIndexData(
+				domain.IndexName("idx_tenant_id"),
+				domain.TableName("foo"),
+				[]domain.FieldName{"tenant_id", "id"},
+				domain.BTreeIndexType,
 			),
 		},
 	}
@@ -1297,3 +2565,285 @@ func TestParser_ExecCmd_CreateIndex_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestParser_ExecCmd_Drop(t *testing.T) {
+	tests := []struct {
+		name     string
+		tokens   []lexer.Token
+		expected domain.ExecData
+	}{
+		{
+			name: "parse drop table",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "drop"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+			},
+			expected: domain.NewDropTableData(domain.TableName("foo"), false),
+		},
+		{
+			name: "parse drop table if exists",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "drop"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TKeyword, "if"),
+				lexer.NewToken(lexer.TKeyword, "exists"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+			},
+			expected: domain.NewDropTableData(domain.TableName("foo"), true),
+		},
+		{
+			name: "parse drop view",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "drop"),
+				lexer.NewToken(lexer.TKeyword, "view"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+			},
+			expected: domain.NewDropViewData(domain.ViewName("foo"), false),
+		},
+		{
+			name: "parse drop index if exists",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "drop"),
+				lexer.NewToken(lexer.TKeyword, "index"),
+				lexer.NewToken(lexer.TKeyword, "if"),
+				lexer.NewToken(lexer.TKeyword, "exists"),
+				lexer.NewToken(lexer.TIdentifier, "foo_idx"),
+			},
+			expected: domain.NewDropIndexData(domain.IndexName("foo_idx"), true),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+
+			p := parser.NewParser(tt.tokens)
+			got, err := p.ExecCmd()
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParser_ExecCmd_Drop_Error(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens []lexer.Token
+	}{
+		{
+			name: "missing object type",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "drop"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+			},
+		},
+		{
+			name: "missing table name",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "drop"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+			},
+		},
+		{
+			name: "missing exists",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "drop"),
+				lexer.NewToken(lexer.TKeyword, "view"),
+				lexer.NewToken(lexer.TKeyword, "if"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+			},
+		},
+		{
+			name: "missing index name",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "drop"),
+				lexer.NewToken(lexer.TKeyword, "index"),
+				lexer.NewToken(lexer.TKeyword, "if"),
+				lexer.NewToken(lexer.TKeyword, "exists"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+
+			p := parser.NewParser(tt.tokens)
+			_, err := p.ExecCmd()
+
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParser_ExecCmd_AlterTable(t *testing.T) {
+	addSch := domain.NewSchema()
+	addSch.AddInt32Field("bar")
+	addSch.SetFieldConstraint("bar", domain.NewFieldConstraint().
+		WithNotNull().
+		WithDefault(domain.NewConstant(domain.Int32FieldType, int32(1))))
+
+	uniqueSch := domain.NewSchema()
+	uniqueSch.AddStringField("bar", 10)
+	uniqueSch.SetFieldConstraint("bar", domain.NewFieldConstraint())
+
+	tests := []struct {
+		name     string
+		tokens   []lexer.Token
+		expected domain.ExecData
+	}{
+		{
+			name: "parse add column",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "alter"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "add"),
+				lexer.NewToken(lexer.TKeyword, "column"),
+				lexer.NewToken(lexer.TIdentifier, "bar"),
+				lexer.NewToken(lexer.TKeyword, "int"),
+				lexer.NewToken(lexer.TKeyword, "not"),
+				lexer.NewToken(lexer.TKeyword, "null"),
+				lexer.NewToken(lexer.TKeyword, "default"),
+				lexer.NewToken(lexer.TInt32, int32(1)),
+			},
+			expected: domain.NewAddColumnData("foo", "bar", addSch, []domain.Constraint{}),
+		},
+		{
+			name: "parse add without column keyword",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "alter"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "add"),
+				lexer.NewToken(lexer.TIdentifier, "bar"),
+				lexer.NewToken(lexer.TKeyword, "varchar"),
+				lexer.NewToken(lexer.TLParen, "("),
+				lexer.NewToken(lexer.TInt32, int32(10)),
+				lexer.NewToken(lexer.TRParen, ")"),
+				lexer.NewToken(lexer.TKeyword, "unique"),
+			},
+			expected: domain.NewAddColumnData("foo", "bar", uniqueSch, []domain.Constraint{
+				domain.NewConstraint(domain.UniqueConstraint, []domain.FieldName{"bar"}),
+			}),
+		},
+		{
+			name: "parse drop column",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "alter"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "drop"),
+				lexer.NewToken(lexer.TKeyword, "column"),
+				lexer.NewToken(lexer.TIdentifier, "bar"),
+			},
+			expected: domain.NewDropColumnData("foo", "bar"),
+		},
+		{
+			name: "parse rename column",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "alter"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "rename"),
+				lexer.NewToken(lexer.TIdentifier, "bar"),
+				lexer.NewToken(lexer.TKeyword, "to"),
+				lexer.NewToken(lexer.TIdentifier, "baz"),
+			},
+			expected: domain.NewRenameColumnData("foo", "bar", "baz"),
+		},
+		{
+			name: "parse rename table",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "alter"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "rename"),
+				lexer.NewToken(lexer.TKeyword, "to"),
+				lexer.NewToken(lexer.TIdentifier, "qux"),
+			},
+			expected: domain.NewRenameTableData("foo", "qux"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+
+			p := parser.NewParser(tt.tokens)
+			got, err := p.ExecCmd()
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParser_ExecCmd_AlterTable_Error(t *testing.T) {
+	tests := []struct {
+		name   string
+		tokens []lexer.Token
+	}{
+		{
+			name: "missing table keyword",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "alter"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+			},
+		},
+		{
+			name: "missing action",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "alter"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+			},
+		},
+		{
+			name: "missing field type",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "alter"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "add"),
+				lexer.NewToken(lexer.TIdentifier, "bar"),
+			},
+		},
+		{
+			name: "missing new field name",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "alter"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "rename"),
+				lexer.NewToken(lexer.TKeyword, "column"),
+				lexer.NewToken(lexer.TIdentifier, "bar"),
+				lexer.NewToken(lexer.TKeyword, "to"),
+			},
+		},
+		{
+			name: "missing to",
+			tokens: []lexer.Token{
+				lexer.NewToken(lexer.TKeyword, "alter"),
+				lexer.NewToken(lexer.TKeyword, "table"),
+				lexer.NewToken(lexer.TIdentifier, "foo"),
+				lexer.NewToken(lexer.TKeyword, "rename"),
+				lexer.NewToken(lexer.TIdentifier, "bar"),
+				lexer.NewToken(lexer.TIdentifier, "baz"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+
+			p := parser.NewParser(tt.tokens)
+			_, err := p.ExecCmd()
+
+			require.Error(t, err)
+		})
+	}
+}
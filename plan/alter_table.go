@@ -0,0 +1,542 @@
+package plan
+
+import (
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// ExecuteAlterTable executes alter table command.
+// index を更新しないので, index で検査する制約は追加できない.
+func (p *BasicUpdatePlanner) ExecuteAlterTable(data *domain.AlterTableData, txn domain.Transaction) (int, error) {
+	if len(data.Constraints()) > 0 {
+		return 0, ErrConstraintNotSupported
+	}
+
+	noIndexes := func(domain.TableName) ([]openedIndex, error) {
+		return nil, nil
+	}
+
+	return 0, alterTable(p.metadataMgr, data, noIndexes, nil, txn)
+}
+
+// ExecuteAlterTable executes alter table command.
+// 追加した field の UNIQUE, PRIMARY KEY, FOREIGN KEY 制約は, 既存の record を検査しながら作る.
+func (p *IndexUpdatePlanner) ExecuteAlterTable(data *domain.AlterTableData, txn domain.Transaction) (int, error) {
+	openIndexes := func(tblName domain.TableName) ([]openedIndex, error) {
+		return p.openIndexes(tblName, txn)
+	}
+	checkRow, err := p.addedConstraintsChecker(data, txn)
+	if err != nil {
+		return 0, errors.Err(err, "addedConstraintsChecker")
+	}
+	if err := alterTable(p.metadataMgr, data, openIndexes, checkRow, txn); err != nil {
+		return 0, errors.Err(err, "alterTable")
+	}
+
+	for _, fk := range []bool{false, true} {
+		for _, c := range data.Constraints() {
+			if (c.Type() == domain.ForeignKeyConstraint) != fk {
+				continue
+			}
+			if err := p.addConstraint(data.TableName(), c, txn); err != nil {
+				return 0, errors.Err(err, "addConstraint")
+			}
+		}
+	}
+
+	return 0, nil
+}
+
+// alterTable changes the definition of the table in the catalogs and rewrites its records with the new layout.
+// RENAME COLUMN は layout が変わらないので catalog だけを書き換える.
+// checkRow は field を追加した後の record が満たすべき制約を検査する.
+func alterTable(mgr domain.MetadataManager, data *domain.AlterTableData, openIndexes func(domain.TableName) ([]openedIndex, error), checkRow func(domain.Scanner) error, txn domain.Transaction) error {
+	tblName := data.TableName()
+	if data.Action() == domain.RenameColumnAction {
+		return mgr.RenameField(tblName, data.FieldName(), data.NewFieldName(), txn)
+	}
+
+	oldLayout, err := mgr.GetTableLayout(tblName, txn)
+	if err != nil {
+		return errors.Err(err, "GetTableLayout")
+	}
+
+	// 失敗しても途中までの変更が残らないように, catalog や record を書き換える前に検査する.
+	if data.Action() == domain.AddColumnAction {
+		if err := checkAddedField(tblName, oldLayout, data, checkRow, txn); err != nil {
+			return errors.Err(err, "checkAddedField")
+		}
+	}
+
+	newTblName := tblName
+	switch data.Action() {
+	case domain.AddColumnAction:
+		err = mgr.AddField(tblName, data.FieldName(), data.Schema(), txn)
+	case domain.DropColumnAction:
+		err = mgr.DropField(tblName, data.FieldName(), txn)
+	case domain.RenameTableAction:
+		newTblName = data.NewTableName()
+		err = mgr.RenameTable(tblName, newTblName, txn)
+	case domain.RenameColumnAction:
+		// 上で処理済み.
+	}
+	if err != nil {
+		return errors.Err(err, "alter catalog")
+	}
+
+	newLayout, err := mgr.GetTableLayout(newTblName, txn)
+	if err != nil {
+		return errors.Err(err, "GetTableLayout")
+	}
+
+	idxs, err := openIndexes(newTblName)
+	if err != nil {
+		return errors.Err(err, "openIndexes")
+	}
+	defer closeIndexes(idxs)
+
+	return rewriteTable(tableFile{name: tblName, layout: oldLayout}, tableFile{name: newTblName, layout: newLayout}, idxs, txn)
+}
+
+// checkAddedField checks that the existing records satisfy the constraints of the added field.
+// 既存の record には default 値が入るので, record があれば default の無い NOT NULL の field は追加できない.
+func checkAddedField(tblName domain.TableName, layout *domain.Layout, data *domain.AlterTableData, checkRow func(domain.Scanner) error, txn domain.Transaction) error {
+	fld, sch := data.FieldName(), data.Schema()
+	if layout.Schema().HasField(fld) {
+		return errors.Wrap(domain.ErrDuplicateField, fld.String())
+	}
+
+	ts, err := domain.NewTableScan(txn, tblName, layout)
+	if err != nil {
+		return errors.Err(err, "NewTableScan")
+	}
+	defer ts.Close()
+
+	for ts.HasNext() {
+		as, err := newAssignedScan(ts, sch, fld, sch.FieldConstraint(fld).Default())
+		if err != nil {
+			return errors.Err(err, "newAssignedScan")
+		}
+		if err := domain.CheckFieldConstraints(sch, as); err != nil {
+			return errors.Err(err, "CheckFieldConstraints")
+		}
+		if checkRow == nil {
+			continue
+		}
+		if err := checkRow(as); err != nil {
+			return errors.Err(err, "checkRow")
+		}
+	}
+	if err := ts.Err(); err != nil {
+		return errors.Err(err, "HasNext")
+	}
+
+	return nil
+}
+
+// addedConstraintsChecker returns a function which checks the constraints added with the field against a record.
+// 制約の定義も catalog を書き換える前に確かめる. 一意性は検査した record の key を覚えておいて調べる.
+func (p *IndexUpdatePlanner) addedConstraintsChecker(data *domain.AlterTableData, txn domain.Transaction) (func(domain.Scanner) error, error) {
+	if data.Action() != domain.AddColumnAction || len(data.Constraints()) == 0 {
+		return nil, nil
+	}
+
+	tblName := data.TableName()
+	layout, err := p.metadataMgr.GetTableLayout(tblName, txn)
+	if err != nil {
+		return nil, errors.Err(err, "GetTableLayout")
+	}
+	cons, err := p.metadataMgr.GetConstraints(tblName, txn)
+	if err != nil {
+		return nil, errors.Err(err, "GetConstraints")
+	}
+
+	fieldType := func(fld domain.FieldName) domain.FieldType {
+		if data.Schema().HasField(fld) {
+			return data.Schema().Type(fld)
+		}
+
+		return layout.Schema().Type(fld)
+	}
+
+	hasPrimaryKey := false
+	for _, c := range cons {
+		hasPrimaryKey = hasPrimaryKey || c.Type() == domain.PrimaryKeyConstraint
+	}
+
+	uniques := make([]domain.Constraint, 0)
+	fkeys := make([]domain.Constraint, 0)
+	for _, c := range data.Constraints() {
+		for _, fld := range c.FieldNames() {
+			if fld != data.FieldName() && !layout.Schema().HasField(fld) {
+				return nil, errors.Wrap(domain.ErrFieldNotFound, fld.String())
+			}
+			if !fieldType(fld).IsIndexable() {
+				return nil, errors.Wrap(domain.ErrNotIndexable, fld.String())
+			}
+		}
+
+		switch c.Type() {
+		case domain.ForeignKeyConstraint:
+			fk, ok, err := p.addedForeignKey(tblName, c, cons, data.Constraints(), fieldType, txn)
+			if err != nil {
+				return nil, errors.Err(err, "addedForeignKey")
+			}
+			if ok {
+				fkeys = append(fkeys, fk)
+			}
+		case domain.PrimaryKeyConstraint:
+			if hasPrimaryKey {
+				return nil, errors.Wrap(domain.ErrMultiplePrimaryKeys, tblName.String())
+			}
+			hasPrimaryKey = true
+			uniques = append(uniques, c)
+		default:
+			uniques = append(uniques, c)
+		}
+	}
+
+	seen := make([]map[string]bool, len(uniques))
+	for i := range seen {
+		seen[i] = make(map[string]bool)
+	}
+
+	return func(s domain.Scanner) error {
+		for i, c := range uniques {
+			key, err := indexKey(s, c.FieldNames())
+			if err != nil {
+				return errors.Err(err, "indexKey")
+			}
+			if hasNull(key) {
+				continue
+			}
+			if seen[i][key.String()] {
+				return domain.NewUniqueViolationError(c, key)
+			}
+			seen[i][key.String()] = true
+		}
+
+		return p.checkForeignKeys(tblName, s, fkeys, txn)
+	}, nil
+}
+
+// addedForeignKey resolves the UNIQUE or PRIMARY KEY constraint referenced by the foreign key c added to the table.
+// 同時に追加する制約を参照する場合, 全ての record が同じ default 値を参照し合うので検査しない.
+func (p *IndexUpdatePlanner) addedForeignKey(tblName domain.TableName, c domain.Constraint, cons, added []domain.Constraint, fieldType func(domain.FieldName) domain.FieldType, txn domain.Transaction) (domain.Constraint, bool, error) {
+	refCons, refType := cons, fieldType
+	if c.RefTableName() == tblName {
+		for _, ref := range added {
+			if referencesConstraint(c, ref) {
+				return domain.Constraint{}, false, nil
+			}
+		}
+	} else {
+		layout, err := p.metadataMgr.GetTableLayout(c.RefTableName(), txn)
+		if err != nil {
+			return domain.Constraint{}, false, errors.Err(err, "GetTableLayout")
+		}
+		refType = layout.Schema().Type
+
+		refCons, err = p.metadataMgr.GetConstraints(c.RefTableName(), txn)
+		if err != nil {
+			return domain.Constraint{}, false, errors.Err(err, "GetConstraints")
+		}
+	}
+
+	for _, ref := range refCons {
+		if !referencesConstraint(c, ref) {
+			continue
+		}
+		if len(c.FieldNames()) != len(ref.FieldNames()) {
+			break
+		}
+
+		for i, fld := range c.FieldNames() {
+			if fieldType(fld) != refType(ref.FieldNames()[i]) {
+				return domain.Constraint{}, false, errors.Wrap(domain.ErrTypeMismatch, fld.String())
+			}
+		}
+
+		return c.WithReference(ref), true, nil
+	}
+
+	return domain.Constraint{}, false, errors.Wrap(domain.ErrNoMatchingUniqueKey, c.RefTableName().String())
+}
+
+// referencesConstraint checks whether the foreign key c references the constraint ref.
+// 参照先の field を省略した場合は primary key を参照する.
+func referencesConstraint(c, ref domain.Constraint) bool {
+	switch {
+	case ref.Type() == domain.ForeignKeyConstraint:
+		return false
+	case len(c.RefFieldNames()) == 0:
+		return ref.Type() == domain.PrimaryKeyConstraint
+	case len(c.RefFieldNames()) != len(ref.FieldNames()):
+		return false
+	}
+
+	for i, fld := range c.RefFieldNames() {
+		if fld != ref.FieldNames()[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tableFile is a table file read or written with the layout.
+type tableFile struct {
+	name   domain.TableName
+	layout *domain.Layout
+}
+
+// rewriteTable moves all records of src into dst whose layout may differ from src.
+// record を一時 table に退避してから dst の layout で書き直し, index の record id も付け替える.
+// 書き換えは全て log に残るので, rollback すれば元の file に戻る.
+// 別の file に書き直す場合, dst の file は rollback すると削除される.
+// 一時 table は成功しても失敗しても transaction の終了時に削除する.
+func rewriteTable(src, dst tableFile, idxs []openedIndex, txn domain.Transaction) error {
+	if src.name != dst.name {
+		for _, filename := range []domain.FileName{dst.name.ToFileName(), dst.name.ToOverflowFileName()} {
+			if err := txn.RemoveFileOnRollback(filename); err != nil {
+				return errors.Err(err, "RemoveFileOnRollback")
+			}
+		}
+	}
+
+	tmp := domain.NewTempTable(txn, src.layout.Schema())
+	saved, err := tmp.Open()
+	if err != nil {
+		dropTempTables([]*domain.TempTable{tmp})
+
+		return errors.Err(err, "Open")
+	}
+
+	err = rewriteRecords(src, dst, saved, idxs, txn)
+	saved.Close()
+	if err != nil {
+		dropTempTables([]*domain.TempTable{tmp})
+
+		return errors.Err(err, "rewriteRecords")
+	}
+
+	if err := tmp.Drop(); err != nil {
+		return errors.Err(err, "Drop")
+	}
+
+	return nil
+}
+
+// rewriteRecords moves all records of src into dst through saved.
+// 元の record は古い version も含めて削除して, TEXT と BYTEA の overflow page を解放する.
+// dst にない field は捨て, src にない field には default 値か NULL を入れる.
+func rewriteRecords(src, dst tableFile, saved *domain.TableScan, idxs []openedIndex, txn domain.Transaction) error {
+	srcSch, dstSch := src.layout.Schema(), dst.layout.Schema()
+
+	in, err := domain.NewTableScan(txn, src.name, src.layout)
+	if err != nil {
+		return errors.Err(err, "NewTableScan")
+	}
+	for in.HasNextVersion() {
+		rid := in.RecordID()
+		for _, idx := range idxs {
+			key, err := indexKey(in, idx.info.FieldNames())
+			if err != nil {
+				return errors.Err(err, "indexKey")
+			}
+			if err := idx.Delete(key, rid); err != nil {
+				return errors.Err(err, "Delete")
+			}
+		}
+
+		// snapshot から見えない古い version は書き直さずに捨てる.
+		visible, err := in.IsVisible()
+		if err != nil {
+			return errors.Err(err, "IsVisible")
+		}
+		if visible {
+			if err := saveRecord(in, saved, srcSch); err != nil {
+				return errors.Err(err, "saveRecord")
+			}
+		}
+
+		// TEXT と BYTEA の overflow page を解放して再利用できるようにする.
+		if err := in.Purge(); err != nil {
+			return errors.Err(err, "Purge")
+		}
+	}
+	if err := in.Err(); err != nil {
+		return errors.Err(err, "HasNextVersion")
+	}
+	in.Close()
+
+	// 同じ file なら dst の layout で全ての slot を空にし, 別の file なら commit 時に削除する.
+	if src.name == dst.name {
+		ts, err := domain.NewTableScan(txn, dst.name, dst.layout)
+		if err != nil {
+			return errors.Err(err, "NewTableScan")
+		}
+		if err := ts.Clear(); err != nil {
+			return errors.Err(err, "Clear")
+		}
+		ts.Close()
+	} else if err := removeTableFiles(txn, src.name); err != nil {
+		return errors.Err(err, "removeTableFiles")
+	}
+
+	out, err := domain.NewTableScan(txn, dst.name, dst.layout)
+	if err != nil {
+		return errors.Err(err, "NewTableScan")
+	}
+	defer out.Close()
+
+	if err := saved.BeforeFirst(); err != nil {
+		return errors.Err(err, "BeforeFirst")
+	}
+	for saved.HasNext() {
+		if err := out.AdvanceNextInsertSlotID(); err != nil {
+			return errors.Err(err, "AdvanceNextInsertSlotID")
+		}
+		for _, fld := range dstSch.Fields() {
+			val := dstSch.FieldConstraint(fld).Default()
+			if srcSch.HasField(fld) {
+				val, err = saved.GetVal(fld)
+				if err != nil {
+					return errors.Err(err, "GetVal")
+				}
+			}
+			if err := out.SetVal(fld, val); err != nil {
+				return errors.Err(err, "SetVal")
+			}
+		}
+		if err := domain.CheckFieldConstraints(dstSch, out); err != nil {
+			return errors.Err(err, "CheckFieldConstraints")
+		}
+
+		rid := out.RecordID()
+		for _, idx := range idxs {
+			key, err := indexKey(out, idx.info.FieldNames())
+			if err != nil {
+				return errors.Err(err, "indexKey")
+			}
+			if err := idx.Insert(key, rid); err != nil {
+				return errors.Err(err, "Insert")
+			}
+		}
+	}
+	if err := saved.Err(); err != nil {
+		return errors.Err(err, "HasNext")
+	}
+
+	return nil
+}
+
+// removeTableFiles removes the file of the table and its overflow pages when the transaction is committed.
+func removeTableFiles(txn domain.Transaction, tblName domain.TableName) error {
+	for _, filename := range []domain.FileName{tblName.ToFileName(), tblName.ToOverflowFileName()} {
+		if err := txn.RemoveFile(filename); err != nil {
+			return errors.Err(err, "RemoveFile")
+		}
+	}
+
+	return nil
+}
+
+// saveRecord copies the current record of src into a new record of dst.
+func saveRecord(src domain.Scanner, dst domain.UpdateScanner, sch *domain.Schema) error {
+	if err := dst.AdvanceNextInsertSlotID(); err != nil {
+		return errors.Err(err, "AdvanceNextInsertSlotID")
+	}
+	for _, fld := range sch.Fields() {
+		val, err := src.GetVal(fld)
+		if err != nil {
+			return errors.Err(err, "GetVal")
+		}
+		if err := dst.SetVal(fld, val); err != nil {
+			return errors.Err(err, "SetVal")
+		}
+	}
+
+	return nil
+}
+
+// addConstraint creates the constraint c on the table which may already have records.
+// 既存の record を index に登録しながら一意性を検査し, 全て登録した後に参照先を確認する.
+func (p *IndexUpdatePlanner) addConstraint(tblName domain.TableName, c domain.Constraint, txn domain.Transaction) error {
+	infos, err := p.metadataMgr.GetIndexInfo(tblName, txn)
+	if err != nil {
+		return errors.Err(err, "GetIndexInfo")
+	}
+	existing := make(map[domain.IndexName]bool, len(infos))
+	for _, info := range infos {
+		existing[info.IndexName()] = true
+	}
+
+	if err := p.metadataMgr.CreateConstraint(tblName, c, txn); err != nil {
+		return errors.Err(err, "CreateConstraint")
+	}
+
+	idxs, err := p.openIndexes(tblName, txn)
+	if err != nil {
+		return errors.Err(err, "openIndexes")
+	}
+	defer closeIndexes(idxs)
+
+	var idx openedIndex
+	for _, opened := range idxs {
+		if !existing[opened.info.IndexName()] {
+			idx = opened
+		}
+	}
+
+	plan, err := NewTablePlan(txn, tblName, p.metadataMgr)
+	if err != nil {
+		return errors.Err(err, "NewTablePlan")
+	}
+	s, err := plan.Open()
+	if err != nil {
+		return errors.Err(err, "Open")
+	}
+	us, ok := s.(domain.UpdateScanner)
+	if !ok {
+		return ErrNotUpdatable
+	}
+	defer us.Close()
+
+	for us.HasNext() {
+		rid := us.RecordID()
+		if err := checkUnique(us, idx, rid); err != nil {
+			return errors.Err(err, "checkUnique")
+		}
+
+		key, err := indexKey(us, idx.info.FieldNames())
+		if err != nil {
+			return errors.Err(err, "indexKey")
+		}
+		if err := idx.Insert(key, rid); err != nil {
+			return errors.Err(err, "Insert")
+		}
+	}
+	if us.Err() != nil {
+		return errors.Err(us.Err(), "HasNext")
+	}
+
+	if c.Type() != domain.ForeignKeyConstraint {
+		return nil
+	}
+
+	// 自身を参照する record も許すため, 全ての record を登録した後に参照先を確認する.
+	if err := us.BeforeFirst(); err != nil {
+		return errors.Err(err, "BeforeFirst")
+	}
+	for us.HasNext() {
+		if err := p.checkForeignKeys(tblName, us, []domain.Constraint{*idx.constraint}, txn); err != nil {
+			return errors.Err(err, "checkForeignKeys")
+		}
+	}
+	if us.Err() != nil {
+		return errors.Err(us.Err(), "HasNext")
+	}
+
+	return nil
+}
@@ -24,36 +24,11 @@ func (planner *BasicQueryPlanner) CreatePlan(data *domain.QueryData, txn domain.
 	plans := make([]domain.Planner, 0)
 
 	for _, tblName := range data.Tables() {
-		viewDef, err := planner.metadataMgr.GetViewDef(tblName.ToViewName(), txn)
+		plan, err := planner.tablePlan(tblName, txn)
 		if err != nil {
-			return nil, errors.Err(err, "GetViewDef")
-		}
-
-		if viewDef == "" {
-			plan, err := NewTablePlan(txn, tblName, planner.metadataMgr)
-			if err != nil {
-				return nil, errors.Err(err, "NewTablePlan")
-			}
-			plans = append(plans, plan)
-		} else {
-			l := lexer.NewLexer(viewDef.String())
-			tokens, err := l.ScanTokens()
-			if err != nil {
-				return nil, errors.Err(err, "ScanTokens")
-			}
-
-			p := parser.NewParser(tokens)
-			viewData, err := p.Query()
-			if err != nil {
-				return nil, errors.Err(err, "Query")
-			}
-
-			plan, err := planner.CreatePlan(viewData, txn)
-			if err != nil {
-				return nil, errors.Err(err, "CreatePlan")
-			}
-			plans = append(plans, plan)
+			return nil, errors.Err(err, "tablePlan")
 		}
+		plans = append(plans, plan)
 	}
 
 	plan := plans[0]
@@ -62,9 +37,97 @@ func (planner *BasicQueryPlanner) CreatePlan(data *domain.QueryData, txn domain.
 		plan = NewProductPlan(plan, nextPlan)
 	}
 
-	plan = NewSelectPlan(plan, data.Predicate())
+	for _, join := range data.Joins() {
+		rhs, err := planner.tablePlan(join.TableName(), txn)
+		if err != nil {
+			return nil, errors.Err(err, "tablePlan")
+		}
+		jp, err := NewJoinPlan(plan, rhs, join.Type(), join.Predicate())
+		if err != nil {
+			return nil, errors.Err(err, "NewJoinPlan")
+		}
+		plan = jp
+	}
+
+	sp, err := NewSelectPlan(plan, data.Predicate())
+	if err != nil {
+		return nil, errors.Err(err, "NewSelectPlan")
+	}
 
-	plan = NewProjectPlan(plan, data.Fields())
+	return projectQueryPlan(txn, sp, data)
+}
+
+// tablePlan creates a planner of the table or the view.
+func (planner *BasicQueryPlanner) tablePlan(tblName domain.TableName, txn domain.Transaction) (domain.Planner, error) {
+	viewDef, err := planner.metadataMgr.GetViewDef(tblName.ToViewName(), txn)
+	if err != nil {
+		return nil, errors.Err(err, "GetViewDef")
+	}
+
+	if viewDef == "" {
+		plan, err := NewTablePlan(txn, tblName, planner.metadataMgr)
+		if err != nil {
+			return nil, errors.Err(err, "NewTablePlan")
+		}
+
+		return plan, nil
+	}
+
+	l := lexer.NewLexer(viewDef.String())
+	tokens, err := l.ScanTokens()
+	if err != nil {
+		return nil, errors.Err(err, "ScanTokens")
+	}
+
+	p := parser.NewParser(tokens)
+	viewData, err := p.Query()
+	if err != nil {
+		return nil, errors.Err(err, "Query")
+	}
+
+	plan, err := planner.CreatePlan(viewData, txn)
+	if err != nil {
+		return nil, errors.Err(err, "CreatePlan")
+	}
 
 	return plan, nil
 }
+
+// projectQueryPlan adds grouping, sorting and projection on top of the plan.
+// order by の key が全て select list にあれば射影後に sort して計算列でも sort できるようにする.
+func projectQueryPlan(txn domain.Transaction, plan domain.Planner, data *domain.QueryData) (domain.Planner, error) {
+	if len(data.GroupBy()) > 0 || len(data.Aggregations()) > 0 {
+		gp, err := NewGroupByPlan(txn, plan, data.GroupBy(), data.Aggregations())
+		if err != nil {
+			return nil, errors.Err(err, "NewGroupByPlan")
+		}
+		plan = gp
+	}
+
+	pp, err := NewProjectPlanWithExpressions(plan, data.Fields(), data.Expressions())
+	if err != nil {
+		return nil, errors.Err(err, "NewProjectPlanWithExpressions")
+	}
+
+	if len(data.OrderBy()) == 0 {
+		return pp, nil
+	}
+
+	for _, key := range data.OrderBy() {
+		if !pp.Schema().HasField(key.FieldName()) {
+			if !plan.Schema().HasField(key.FieldName()) {
+				return nil, errors.Wrap(domain.ErrFieldNotFound, key.FieldName().String())
+			}
+
+			// 射影で消える field で sort する場合は射影前に sort する.
+			pp, err = NewProjectPlanWithExpressions(NewSortPlan(txn, plan, data.OrderBy()), data.Fields(), data.Expressions())
+			if err != nil {
+				return nil, errors.Err(err, "NewProjectPlanWithExpressions")
+			}
+
+			return pp, nil
+		}
+	}
+
+	return NewSortPlan(txn, pp, data.OrderBy()), nil
+}
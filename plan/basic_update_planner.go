@@ -8,6 +8,9 @@ import (
 // ErrNotUpdatable is error that indicates the scan is not updatable.
 var ErrNotUpdatable = errors.New("not updatable")
 
+// ErrConstraintNotSupported is an error that means the update planner can't enforce constraints.
+var ErrConstraintNotSupported = errors.New("constraints are not supported by this update planner")
+
 // BasicUpdatePlanner is a BasicUpdatePlanner.
 type BasicUpdatePlanner struct {
 	metadataMgr domain.MetadataManager
@@ -40,12 +43,8 @@ func (p *BasicUpdatePlanner) ExecuteInsert(data *domain.InsertData, txn domain.T
 		return 0, errors.Err(err, "AdvanceNextInsertSlotID")
 	}
 
-	vals := data.Values()
-	fields := data.Fields()
-	for i := 0; i < len(vals); i++ {
-		if err = us.SetVal(fields[i], vals[i]); err != nil {
-			return 0, errors.Err(err, "SetVal")
-		}
+	if err := insertValues(us, plan.Schema(), data); err != nil {
+		return 0, errors.Err(err, "insertValues")
 	}
 	us.Close()
 
@@ -54,13 +53,19 @@ func (p *BasicUpdatePlanner) ExecuteInsert(data *domain.InsertData, txn domain.T
 
 // ExecuteDelete executes delete command.
 func (p *BasicUpdatePlanner) ExecuteDelete(data *domain.DeleteData, txn domain.Transaction) (int, error) {
-	var plan domain.Planner
-	plan, err := NewTablePlan(txn, data.TableName(), p.metadataMgr)
+	tp, err := NewTablePlan(txn, data.TableName(), p.metadataMgr)
 	if err != nil {
 		return 0, errors.Err(err, "NewTablePlan")
 	}
 
-	plan = NewSelectPlan(plan, data.Predicate())
+	if err := pruneTable(data.TableName(), tp.layout, nil, txn); err != nil {
+		return 0, errors.Err(err, "pruneTable")
+	}
+
+	plan, err := NewSelectPlan(tp, data.Predicate())
+	if err != nil {
+		return 0, errors.Err(err, "NewSelectPlan")
+	}
 	s, err := plan.Open()
 	if err != nil {
 		return 0, errors.Err(err, "Open")
@@ -88,12 +93,22 @@ func (p *BasicUpdatePlanner) ExecuteDelete(data *domain.DeleteData, txn domain.T
 
 // ExecuteModify executes update command.
 func (p *BasicUpdatePlanner) ExecuteModify(data *domain.ModifyData, txn domain.Transaction) (int, error) {
-	var plan domain.Planner
-	plan, err := NewTablePlan(txn, data.TableName(), p.metadataMgr)
+	tp, err := NewTablePlan(txn, data.TableName(), p.metadataMgr)
 	if err != nil {
 		return 0, errors.Err(err, "NewTablePlan")
 	}
-	plan = NewSelectPlan(plan, data.Predicate())
+
+	if err := pruneTable(data.TableName(), tp.layout, nil, txn); err != nil {
+		return 0, errors.Err(err, "pruneTable")
+	}
+	plan, err := NewSelectPlan(tp, data.Predicate())
+	if err != nil {
+		return 0, errors.Err(err, "NewSelectPlan")
+	}
+
+	if err := checkAssignment(plan.Schema(), data.FieldName(), data.Expression()); err != nil {
+		return 0, errors.Err(err, "checkAssignment")
+	}
 
 	s, err := plan.Open()
 	if err != nil {
@@ -112,7 +127,16 @@ func (p *BasicUpdatePlanner) ExecuteModify(data *domain.ModifyData, txn domain.T
 			return 0, errors.Err(err, "Evaluate")
 		}
 
-		if err = us.SetVal(data.FieldName(), val); err != nil {
+		// 制約に違反する値は record に書き込まない.
+		as, err := newAssignedScan(us, plan.Schema(), data.FieldName(), val)
+		if err != nil {
+			return 0, errors.Err(err, "newAssignedScan")
+		}
+		if err := domain.CheckFieldConstraints(plan.Schema(), as); err != nil {
+			return 0, errors.Err(err, "CheckFieldConstraints")
+		}
+
+		if err = us.SetVal(data.FieldName(), as.val); err != nil {
 			return 0, errors.Err(err, "SetVal")
 		}
 		cnt++
@@ -126,8 +150,13 @@ func (p *BasicUpdatePlanner) ExecuteModify(data *domain.ModifyData, txn domain.T
 }
 
 // ExecuteCreateTable executes create table command.
+// index を更新しないので, index で検査する制約は作れない.
 func (p *BasicUpdatePlanner) ExecuteCreateTable(data *domain.CreateTableData, txn domain.Transaction) (int, error) {
-	return 0, p.metadataMgr.CreateTable(data.TableName(), data.Schema(), txn)
+	if len(data.Constraints()) > 0 {
+		return 0, ErrConstraintNotSupported
+	}
+
+	return 0, p.metadataMgr.CreateTable(data.TableName(), data.Schema(), data.Format(), txn)
 }
 
 // ExecuteCreateView executes create view command.
@@ -137,5 +166,151 @@ func (p *BasicUpdatePlanner) ExecuteCreateView(data *domain.CreateViewData, txn
 
 // ExecuteCreateIndex executes create index command.
 func (p *BasicUpdatePlanner) ExecuteCreateIndex(data *domain.CreateIndexData, txn domain.Transaction) (int, error) {
-	return 0, p.metadataMgr.CreateIndex(data.IndexName(), data.TableName(), data.FieldName(), txn)
+	return 0, p.metadataMgr.CreateIndex(data.IndexName(), data.TableName(), data.FieldNames(), data.IndexType(), txn)
+}
+
+// ExecuteDropTable executes drop table command.
+func (p *BasicUpdatePlanner) ExecuteDropTable(data *domain.DropTableData, txn domain.Transaction) (int, error) {
+	err := p.metadataMgr.DropTable(data.TableName(), txn)
+
+	return 0, ignoreNotFound(err, domain.ErrTableNotFound, data.IfExists())
+}
+
+// ExecuteDropView executes drop view command.
+func (p *BasicUpdatePlanner) ExecuteDropView(data *domain.DropViewData, txn domain.Transaction) (int, error) {
+	err := p.metadataMgr.DropView(data.ViewName(), txn)
+
+	return 0, ignoreNotFound(err, domain.ErrViewNotFound, data.IfExists())
+}
+
+// ExecuteDropIndex executes drop index command.
+func (p *BasicUpdatePlanner) ExecuteDropIndex(data *domain.DropIndexData, txn domain.Transaction) (int, error) {
+	err := p.metadataMgr.DropIndex(data.IndexName(), txn)
+
+	return 0, ignoreNotFound(err, domain.ErrIndexNotFound, data.IfExists())
+}
+
+// ignoreNotFound ignores notFound error of drop command if IF EXISTS is specified.
+func ignoreNotFound(err, notFound error, ifExists bool) error {
+	if ifExists && errors.Is(err, notFound) {
+		return nil
+	}
+
+	return err
+}
+
+// checkAssignment checks that the value of expr can be assigned to fld.
+func checkAssignment(sch *domain.Schema, fld domain.FieldName, expr domain.Expression) error {
+	if !sch.HasField(fld) {
+		return errors.Wrap(domain.ErrFieldNotFound, fld.String())
+	}
+
+	typ, _, err := expr.Type(sch)
+	if err != nil {
+		return errors.Err(err, "Type")
+	}
+
+	// NULL はどの型の field にも代入できる.
+	if !sch.Type(fld).Accepts(typ) && !expr.IsNull() {
+		return errors.Wrap(domain.ErrTypeMismatch, fld.String())
+	}
+
+	return nil
+}
+
+// insertValues sets the values of insert command into the current record.
+// 値が指定されなかった field には default 値を入れる.
+// 値を書き込めない場合や NOT NULL, CHECK 制約に違反する場合は record を消してから error を返す.
+func insertValues(us domain.UpdateScanner, sch *domain.Schema, data *domain.InsertData) error {
+	if err := setInsertValues(us, sch, data); err != nil {
+		if err2 := us.Delete(); err2 != nil {
+			return errors.Err(err2, "Delete")
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func setInsertValues(us domain.UpdateScanner, sch *domain.Schema, data *domain.InsertData) error {
+	given := make(map[domain.FieldName]bool, len(data.Fields()))
+	vals := data.Values()
+	for i, fld := range data.Fields() {
+		if err := us.SetVal(fld, vals[i]); err != nil {
+			return errors.Err(err, "SetVal")
+		}
+		given[fld] = true
+	}
+
+	for _, fld := range sch.Fields() {
+		if given[fld] {
+			continue
+		}
+		if dflt := sch.FieldConstraint(fld).Default(); !dflt.IsNull() {
+			if err := us.SetVal(fld, dflt); err != nil {
+				return errors.Err(err, "SetVal")
+			}
+		}
+	}
+
+	if err := domain.CheckFieldConstraints(sch, us); err != nil {
+		return errors.Err(err, "CheckFieldConstraints")
+	}
+
+	return nil
+}
+
+// assignedScan is a scanner which reads the assigned value instead of the field of the current record.
+// record を書き換える前に, 代入後の record が制約を満たすか検査するために使う.
+type assignedScan struct {
+	domain.Scanner
+	fld domain.FieldName
+	val domain.Constant
+}
+
+// newAssignedScan constructs an assignedScan which reads val as the stored value of fld.
+func newAssignedScan(s domain.Scanner, sch *domain.Schema, fld domain.FieldName, val domain.Constant) (*assignedScan, error) {
+	val, err := sch.StoredValue(fld, val)
+	if err != nil {
+		return nil, errors.Err(err, "StoredValue")
+	}
+
+	return &assignedScan{Scanner: s, fld: fld, val: val}, nil
+}
+
+// GetInt32 gets int32 from the record.
+// GetInt32 implements Scanner.
+func (s *assignedScan) GetInt32(fldName domain.FieldName) (int32, error) {
+	if fldName == s.fld {
+		return s.val.AsInt32()
+	}
+
+	return s.Scanner.GetInt32(fldName)
+}
+
+// GetString gets string from the record.
+// GetString implements Scanner.
+func (s *assignedScan) GetString(fldName domain.FieldName) (string, error) {
+	if fldName == s.fld {
+		return s.val.AsString()
+	}
+
+	return s.Scanner.GetString(fldName)
+}
+
+// GetVal gets value from the record.
+// GetVal implements Scanner.
+func (s *assignedScan) GetVal(fldName domain.FieldName) (domain.Constant, error) {
+	if fldName == s.fld {
+		return s.val, nil
+	}
+
+	return s.Scanner.GetVal(fldName)
+}
+
+// HasField checks whether the record has the field.
+// HasField implements Scanner.
+func (s *assignedScan) HasField(fldName domain.FieldName) bool {
+	return fldName == s.fld || s.Scanner.HasField(fldName)
 }
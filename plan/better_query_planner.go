@@ -24,53 +24,86 @@ func (planner *BetterQueryPlanner) CreatePlan(data *domain.QueryData, txn domain
 	plans := make([]domain.Planner, 0)
 
 	for _, tblName := range data.Tables() {
-		viewDef, err := planner.metadataMgr.GetViewDef(tblName.ToViewName(), txn)
+		plan, err := planner.tablePlan(tblName, txn)
 		if err != nil {
-			return nil, errors.Err(err, "GetViewDef")
+			return nil, errors.Err(err, "tablePlan")
 		}
+		plans = append(plans, plan)
+	}
 
-		if viewDef == "" {
-			plan, err := NewTablePlan(txn, tblName, planner.metadataMgr)
-			if err != nil {
-				return nil, errors.Err(err, "NewTablePlan")
-			}
-			plans = append(plans, plan)
-		} else {
-			l := lexer.NewLexer(viewDef.String())
-			tokens, err := l.ScanTokens()
+	plan := plans[0]
+	plans = plans[1:]
+	for _, nextPlan := range plans {
+		jp, err := joinPlan(txn, planner.metadataMgr, plan, nextPlan, data.Predicate())
+		if err != nil {
+			return nil, errors.Err(err, "joinPlan")
+		}
+		plan = jp
+	}
+
+	for _, join := range data.Joins() {
+		rhs, err := planner.tablePlan(join.TableName(), txn)
+		if err != nil {
+			return nil, errors.Err(err, "tablePlan")
+		}
+		if join.Type() == domain.InnerJoin {
+			jp, err := joinPlan(txn, planner.metadataMgr, plan, rhs, join.Predicate())
 			if err != nil {
-				return nil, errors.Err(err, "ScanTokens")
+				return nil, errors.Err(err, "joinPlan")
 			}
-
-			p := parser.NewParser(tokens)
-			viewData, err := p.Query()
+			plan, err = NewSelectPlan(jp, join.Predicate())
 			if err != nil {
-				return nil, errors.Err(err, "Query")
+				return nil, errors.Err(err, "NewSelectPlan")
 			}
-
-			plan, err := planner.CreatePlan(viewData, txn)
+		} else {
+			jp, err := NewJoinPlan(plan, rhs, join.Type(), join.Predicate())
 			if err != nil {
-				return nil, errors.Err(err, "CreatePlan")
+				return nil, errors.Err(err, "NewJoinPlan")
 			}
-			plans = append(plans, plan)
+			plan = jp
 		}
 	}
 
-	plan := plans[0]
-	plans = plans[1:]
-	for _, nextPlan := range plans {
-		choice1 := NewProductPlan(plan, nextPlan)
-		choice2 := NewProductPlan(nextPlan, plan)
-		if choice1.EstNumBlocks() < choice2.EstNumBlocks() {
-			plan = choice1
-		} else {
-			plan = choice2
+	sp, err := NewSelectPlan(plan, data.Predicate())
+	if err != nil {
+		return nil, errors.Err(err, "NewSelectPlan")
+	}
+
+	return projectQueryPlan(txn, sp, data)
+}
+
+// tablePlan creates a planner of the table or the view.
+func (planner *BetterQueryPlanner) tablePlan(tblName domain.TableName, txn domain.Transaction) (domain.Planner, error) {
+	viewDef, err := planner.metadataMgr.GetViewDef(tblName.ToViewName(), txn)
+	if err != nil {
+		return nil, errors.Err(err, "GetViewDef")
+	}
+
+	if viewDef == "" {
+		plan, err := NewTablePlan(txn, tblName, planner.metadataMgr)
+		if err != nil {
+			return nil, errors.Err(err, "NewTablePlan")
 		}
+
+		return plan, nil
 	}
 
-	plan = NewSelectPlan(plan, data.Predicate())
+	l := lexer.NewLexer(viewDef.String())
+	tokens, err := l.ScanTokens()
+	if err != nil {
+		return nil, errors.Err(err, "ScanTokens")
+	}
+
+	p := parser.NewParser(tokens)
+	viewData, err := p.Query()
+	if err != nil {
+		return nil, errors.Err(err, "Query")
+	}
 
-	plan = NewProjectPlan(plan, data.Fields())
+	plan, err := planner.CreatePlan(viewData, txn)
+	if err != nil {
+		return nil, errors.Err(err, "CreatePlan")
+	}
 
 	return plan, nil
 }
@@ -0,0 +1,11 @@
+package plan
+
+import "github.com/goropikari/simpledbgo/domain"
+
+func (plan *ProjectPlan) Underlying() domain.Planner {
+	return plan.plan
+}
+
+func (p *SelectPlan) Underlying() domain.Planner {
+	return p.plan
+}
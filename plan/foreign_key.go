@@ -0,0 +1,338 @@
+package plan
+
+import (
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// referencingKey is a foreign key of the table tblName which references another table.
+type referencingKey struct {
+	tblName    domain.TableName
+	constraint domain.Constraint
+}
+
+// referencingKeys returns all foreign keys referencing the table.
+func (p *IndexUpdatePlanner) referencingKeys(tblName domain.TableName, txn domain.Transaction) ([]referencingKey, error) {
+	tblNames, err := p.metadataMgr.GetReferencingTables(tblName, txn)
+	if err != nil {
+		return nil, errors.Err(err, "GetReferencingTables")
+	}
+
+	refs := make([]referencingKey, 0)
+	for _, name := range tblNames {
+		cons, err := p.metadataMgr.GetConstraints(name, txn)
+		if err != nil {
+			return nil, errors.Err(err, "GetConstraints")
+		}
+		for _, c := range cons {
+			if c.Type() == domain.ForeignKeyConstraint && c.RefTableName() == tblName {
+				refs = append(refs, referencingKey{tblName: name, constraint: c})
+			}
+		}
+	}
+
+	return refs, nil
+}
+
+// checkForeignKeys checks that the records referenced by the current record of s exist.
+// 参照先の record がある block に共有 lock を取り, transaction が終わるまで削除されないようにする.
+func (p *IndexUpdatePlanner) checkForeignKeys(tblName domain.TableName, s domain.Scanner, fkeys []domain.Constraint, txn domain.Transaction) error {
+	for _, c := range fkeys {
+		key, err := indexKey(s, c.FieldNames())
+		if err != nil {
+			return errors.Err(err, "indexKey")
+		}
+		// NULL を含む key は何も参照しない.
+		if hasNull(key) {
+			continue
+		}
+
+		found, err := p.lockReferenced(c, key, txn)
+		if err != nil {
+			return errors.Err(err, "lockReferenced")
+		}
+		if !found {
+			return domain.NewForeignKeyViolationError(tblName, c, key)
+		}
+	}
+
+	return nil
+}
+
+// lockReferenced looks up the record referenced by key through the index of referenced constraint and locks it.
+func (p *IndexUpdatePlanner) lockReferenced(c domain.Constraint, key domain.Constant, txn domain.Transaction) (bool, error) {
+	idx, ok, err := p.openIndex(c.RefTableName(), c.RefName(), txn)
+	if err != nil {
+		return false, errors.Err(err, "openIndex")
+	}
+	if !ok {
+		return false, errors.Wrap(domain.ErrNoMatchingUniqueKey, c.RefTableName().String())
+	}
+	defer idx.Close()
+
+	if err := idx.BeforeFirst(key); err != nil {
+		return false, errors.Err(err, "BeforeFirst")
+	}
+	found, err := idx.hasNextLatest()
+	if err != nil {
+		return false, errors.Err(err, "hasNextLatest")
+	}
+	if !found {
+		return false, nil
+	}
+
+	rid, err := idx.GetDataRecordID()
+	if err != nil {
+		return false, errors.Err(err, "GetDataRecordID")
+	}
+	if err := txn.SLock(domain.NewBlock(c.RefTableName().ToFileName(), rid.BlockNumber())); err != nil {
+		return false, errors.Err(err, "SLock")
+	}
+
+	return true, nil
+}
+
+// deleteRecord deletes the current record of us and its index entries,
+// and applies ON DELETE action of the foreign keys referencing the record.
+// 先に排他 lock を取って record を消すので, 確認した後に参照する record が挿入されることはない.
+func (p *IndexUpdatePlanner) deleteRecord(tblName domain.TableName, layout *domain.Layout, us domain.UpdateScanner, idxs []openedIndex, refs []referencingKey, txn domain.Transaction) error {
+	rid := us.RecordID()
+	if err := txn.XLock(domain.NewBlock(tblName.ToFileName(), rid.BlockNumber())); err != nil {
+		return errors.Err(err, "XLock")
+	}
+
+	keys := make([]domain.Constant, 0, len(refs))
+	for _, ref := range refs {
+		key, err := indexKey(us, ref.constraint.RefFieldNames())
+		if err != nil {
+			return errors.Err(err, "indexKey")
+		}
+		keys = append(keys, key)
+	}
+
+	// 古い version として record が残る場合は, その version を読む transaction のため index の entry も残す.
+	if !domain.KeepsVersions(txn, layout) {
+		for _, idx := range idxs {
+			val, err := indexKey(us, idx.info.FieldNames())
+			if err != nil {
+				return errors.Err(err, "indexKey")
+			}
+			if err := idx.Delete(val, rid); err != nil {
+				return errors.Err(err, "Delete")
+			}
+		}
+	}
+
+	if err := us.Delete(); err != nil {
+		return errors.Err(err, "Delete")
+	}
+
+	for i, ref := range refs {
+		if hasNull(keys[i]) {
+			continue
+		}
+		if err := p.onDelete(ref, keys[i], txn); err != nil {
+			return errors.Err(err, "onDelete")
+		}
+	}
+
+	return nil
+}
+
+// onDelete applies ON DELETE action to the records which reference the deleted key.
+// 参照元の record は foreign key の index で探す.
+func (p *IndexUpdatePlanner) onDelete(ref referencingKey, key domain.Constant, txn domain.Transaction) error {
+	layout, err := p.metadataMgr.GetTableLayout(ref.tblName, txn)
+	if err != nil {
+		return errors.Err(err, "GetTableLayout")
+	}
+
+	ts, err := domain.NewTableScan(txn, ref.tblName, layout)
+	if err != nil {
+		return errors.Err(err, "NewTableScan")
+	}
+	defer ts.Close()
+
+	idxs, err := p.openIndexes(ref.tblName, txn)
+	if err != nil {
+		return errors.Err(err, "openIndexes")
+	}
+	defer closeIndexes(idxs)
+
+	var fkIdx openedIndex
+	for _, idx := range idxs {
+		if idx.info.IndexName() == ref.constraint.Name() {
+			fkIdx = idx
+		}
+	}
+
+	var refs []referencingKey
+	if ref.constraint.OnDelete() == domain.CascadeAction {
+		refs, err = p.referencingKeys(ref.tblName, txn)
+		if err != nil {
+			return errors.Err(err, "referencingKeys")
+		}
+	}
+
+	// 削除や NULL の代入で最新の record の entry が無くなるので, 見つからなくなるまで先頭の record を処理する.
+	for {
+		if err := fkIdx.BeforeFirst(key); err != nil {
+			return errors.Err(err, "BeforeFirst")
+		}
+		found, err := fkIdx.hasNextLatest()
+		if err != nil {
+			return errors.Err(err, "hasNextLatest")
+		}
+		if !found {
+			return nil
+		}
+		rid, err := fkIdx.GetDataRecordID()
+		if err != nil {
+			return errors.Err(err, "GetDataRecordID")
+		}
+
+		switch ref.constraint.OnDelete() {
+		case domain.CascadeAction:
+			if err := ts.MoveToRecordID(rid); err != nil {
+				return errors.Err(err, "MoveToRecordID")
+			}
+			if err := p.deleteRecord(ref.tblName, layout, ts, idxs, refs, txn); err != nil {
+				return errors.Err(err, "deleteRecord")
+			}
+		case domain.SetNullAction:
+			if err := ts.MoveToRecordID(rid); err != nil {
+				return errors.Err(err, "MoveToRecordID")
+			}
+			if err := setNull(ts, layout.Schema(), idxs, ref.constraint.FieldNames()); err != nil {
+				return errors.Err(err, "setNull")
+			}
+		default:
+			return domain.NewReferencedKeyViolationError(ref.tblName, ref.constraint, key)
+		}
+	}
+}
+
+// checkKeyNotReferenced checks that the old key of the current record of s is not referenced
+// if the key has been changed.
+func (p *IndexUpdatePlanner) checkKeyNotReferenced(s domain.Scanner, ref referencingKey, oldKey domain.Constant, txn domain.Transaction) error {
+	key, err := indexKey(s, ref.constraint.RefFieldNames())
+	if err != nil {
+		return errors.Err(err, "indexKey")
+	}
+	if hasNull(oldKey) || key.Equal(oldKey) {
+		return nil
+	}
+
+	referenced, err := p.isReferenced(ref, oldKey, txn)
+	if err != nil {
+		return errors.Err(err, "isReferenced")
+	}
+	if referenced {
+		return domain.NewReferencedKeyViolationError(ref.tblName, ref.constraint, oldKey)
+	}
+
+	return nil
+}
+
+// isReferenced checks whether a record of the table references the key through the foreign key.
+func (p *IndexUpdatePlanner) isReferenced(ref referencingKey, key domain.Constant, txn domain.Transaction) (bool, error) {
+	idx, ok, err := p.openIndex(ref.tblName, ref.constraint.Name(), txn)
+	if err != nil {
+		return false, errors.Err(err, "openIndex")
+	}
+	if !ok {
+		return false, nil
+	}
+	defer idx.Close()
+
+	if err := idx.BeforeFirst(key); err != nil {
+		return false, errors.Err(err, "BeforeFirst")
+	}
+
+	return idx.hasNextLatest()
+}
+
+// setNull sets NULL to flds of the current record of us and updates the indexes.
+func setNull(us domain.UpdateScanner, sch *domain.Schema, idxs []openedIndex, flds []domain.FieldName) error {
+	affected := make([]openedIndex, 0, len(idxs))
+	oldKeys := make([]domain.Constant, 0, len(idxs))
+	for _, idx := range idxs {
+		if !containsAny(idx.info.FieldNames(), flds) {
+			continue
+		}
+		key, err := indexKey(us, idx.info.FieldNames())
+		if err != nil {
+			return errors.Err(err, "indexKey")
+		}
+		affected = append(affected, idx)
+		oldKeys = append(oldKeys, key)
+	}
+
+	if err := saveVersion(us, idxs); err != nil {
+		return errors.Err(err, "saveVersion")
+	}
+	for _, fld := range flds {
+		if err := us.SetVal(fld, domain.NewNullConstant()); err != nil {
+			return errors.Err(err, "SetVal")
+		}
+	}
+	if err := domain.CheckFieldConstraints(sch, us); err != nil {
+		return errors.Err(err, "CheckFieldConstraints")
+	}
+
+	rid := us.RecordID()
+	for i, idx := range affected {
+		key, err := indexKey(us, idx.info.FieldNames())
+		if err != nil {
+			return errors.Err(err, "indexKey")
+		}
+		if err := idx.Delete(oldKeys[i], rid); err != nil {
+			return errors.Err(err, "Delete")
+		}
+		if err := idx.Insert(key, rid); err != nil {
+			return errors.Err(err, "Insert")
+		}
+	}
+
+	return nil
+}
+
+// foreignKeys returns the foreign keys of the opened indexes which contain one of flds.
+// flds が nil の場合はすべての foreign key を返す.
+func foreignKeys(idxs []openedIndex, flds []domain.FieldName) []domain.Constraint {
+	fkeys := make([]domain.Constraint, 0)
+	for _, idx := range idxs {
+		c := idx.constraint
+		if c == nil || c.Type() != domain.ForeignKeyConstraint {
+			continue
+		}
+		if flds == nil || containsAny(c.FieldNames(), flds) {
+			fkeys = append(fkeys, *c)
+		}
+	}
+
+	return fkeys
+}
+
+func containsAny(flds, targets []domain.FieldName) bool {
+	for _, fld := range flds {
+		for _, target := range targets {
+			if fld == target {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// hasNull checks whether the key contains NULL.
+func hasNull(key domain.Constant) bool {
+	for _, val := range key.Components() {
+		if val.IsNull() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,103 @@
+package plan
+
+import (
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+	"github.com/goropikari/simpledbgo/math"
+)
+
+// GroupByPlan is planner for grouping and aggregation.
+// 入力を group field で sort してから集約する.
+type GroupByPlan struct {
+	p           domain.Planner
+	groupFields []domain.FieldName
+	aggs        []domain.Aggregation
+	sch         *domain.Schema
+}
+
+// NewGroupByPlan constructs a GroupByPlan.
+func NewGroupByPlan(txn domain.Transaction, p domain.Planner, groupFields []domain.FieldName, aggs []domain.Aggregation) (*GroupByPlan, error) {
+	sch := domain.NewSchema()
+	for _, fld := range groupFields {
+		if !p.Schema().HasField(fld) {
+			return nil, errors.Wrap(domain.ErrFieldNotFound, fld.String())
+		}
+		sch.Add(fld, p.Schema())
+	}
+
+	for _, agg := range aggs {
+		typ, length, err := agg.FieldType(p.Schema())
+		if err != nil {
+			return nil, errors.Err(err, "FieldType")
+		}
+		sch.AddField(agg.FieldName(), typ, length)
+	}
+
+	if len(groupFields) > 0 {
+		keys := make([]domain.SortKey, 0, len(groupFields))
+		for _, fld := range groupFields {
+			keys = append(keys, domain.NewSortKey(fld, domain.Asc))
+		}
+		p = NewSortPlan(txn, p, keys)
+	}
+
+	return &GroupByPlan{
+		p:           p,
+		groupFields: groupFields,
+		aggs:        aggs,
+		sch:         sch,
+	}, nil
+}
+
+// Open opens scanner.
+func (gp *GroupByPlan) Open() (domain.Scanner, error) {
+	s, err := gp.p.Open()
+	if err != nil {
+		return nil, errors.Err(err, "Open")
+	}
+
+	gs, err := domain.NewGroupByScan(s, gp.p.Schema(), gp.groupFields, gp.aggs)
+	if err != nil {
+		s.Close()
+
+		return nil, errors.Err(err, "NewGroupByScan")
+	}
+
+	return gs, nil
+}
+
+// EstNumBlocks estimates the number of block access.
+func (gp *GroupByPlan) EstNumBlocks() int {
+	return gp.p.EstNumBlocks()
+}
+
+// EstNumRecord estimates the number of record access.
+// group の数は各 group field の distinct value の積で見積もる.
+func (gp *GroupByPlan) EstNumRecord() int {
+	numGroups := 1
+	for _, fld := range gp.groupFields {
+		numGroups *= math.Max(1, gp.p.EstDistinctVals(fld))
+	}
+
+	if len(gp.groupFields) == 0 {
+		return numGroups
+	}
+
+	return math.Min(numGroups, math.Max(1, gp.p.EstNumRecord()))
+}
+
+// EstDistinctVals estimates the number of distinct value at given fldName.
+func (gp *GroupByPlan) EstDistinctVals(fldName domain.FieldName) int {
+	for _, fld := range gp.groupFields {
+		if fld == fldName {
+			return gp.p.EstDistinctVals(fldName)
+		}
+	}
+
+	return gp.EstNumRecord()
+}
+
+// Schema returns schema of grouped records.
+func (gp *GroupByPlan) Schema() *domain.Schema {
+	return gp.sch
+}
@@ -0,0 +1,201 @@
+package plan
+
+import (
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+	"github.com/goropikari/simpledbgo/math"
+)
+
+// HashJoinPlan is planner for hash join.
+// 小さい方の入力を build 側にする. build 側が利用可能な buffer に収まらない場合は
+// Grace hash join のように両方の入力を temporary table に partition してから join する.
+type HashJoinPlan struct {
+	txn     domain.Transaction
+	lhsPlan domain.Planner
+	rhsPlan domain.Planner
+	lhsFld  domain.FieldName
+	rhsFld  domain.FieldName
+	schema  *domain.Schema
+}
+
+// NewHashJoinPlan constructs a HashJoinPlan which joins records satisfying lhsFld = rhsFld.
+func NewHashJoinPlan(txn domain.Transaction, lhs, rhs domain.Planner, lhsFld, rhsFld domain.FieldName) *HashJoinPlan {
+	sch := domain.NewSchema()
+	sch.AddAllFields(lhs.Schema())
+	sch.AddAllFields(rhs.Schema())
+
+	return &HashJoinPlan{
+		txn:     txn,
+		lhsPlan: lhs,
+		rhsPlan: rhs,
+		lhsFld:  lhsFld,
+		rhsFld:  rhsFld,
+		schema:  sch,
+	}
+}
+
+// Open opens scanner.
+func (plan *HashJoinPlan) Open() (domain.Scanner, error) {
+	build, probe, buildFld, probeFld := plan.buildProbe()
+
+	if !plan.needsPartition() {
+		bs, err := build.Open()
+		if err != nil {
+			return nil, errors.Err(err, "Open")
+		}
+		ps, err := probe.Open()
+		if err != nil {
+			bs.Close()
+
+			return nil, errors.Err(err, "Open")
+		}
+
+		return domain.NewHashJoinScan(bs, ps, build.Schema().Fields(), buildFld, probeFld)
+	}
+
+	numParts := plan.numPartitions()
+	buildParts, err := plan.partition(build, buildFld, numParts)
+	if err != nil {
+		return nil, errors.Err(err, "partition")
+	}
+	probeParts, err := plan.partition(probe, probeFld, numParts)
+	if err != nil {
+		dropTempTables(buildParts)
+
+		return nil, errors.Err(err, "partition")
+	}
+
+	return domain.NewPartitionedHashJoinScan(buildParts, probeParts, buildFld, probeFld)
+}
+
+// EstNumBlocks estimates the number of block access.
+// partition する場合は両方の入力の読み込み, temporary table への書き出し, 読み直しの分を見積もる.
+func (plan *HashJoinPlan) EstNumBlocks() int {
+	blocks := plan.lhsPlan.EstNumBlocks() + plan.rhsPlan.EstNumBlocks()
+	if plan.needsPartition() {
+		return 3 * blocks
+	}
+
+	return blocks
+}
+
+// EstNumRecord estimates the number of record access.
+func (plan *HashJoinPlan) EstNumRecord() int {
+	distinct := math.Max(plan.lhsPlan.EstDistinctVals(plan.lhsFld), plan.rhsPlan.EstDistinctVals(plan.rhsFld))
+
+	return plan.lhsPlan.EstNumRecord() * plan.rhsPlan.EstNumRecord() / math.Max(1, distinct)
+}
+
+// EstDistinctVals estimates the number of distinct value at given fldName.
+func (plan *HashJoinPlan) EstDistinctVals(fldName domain.FieldName) int {
+	if plan.lhsPlan.Schema().HasField(fldName) {
+		return plan.lhsPlan.EstDistinctVals(fldName)
+	}
+
+	return plan.rhsPlan.EstDistinctVals(fldName)
+}
+
+// Schema returns schema of joined records.
+func (plan *HashJoinPlan) Schema() *domain.Schema {
+	return plan.schema
+}
+
+// buildProbe returns the build side and the probe side.
+// block 数が少ない方, 同じなら record 数が少ない方を build 側にする.
+func (plan *HashJoinPlan) buildProbe() (domain.Planner, domain.Planner, domain.FieldName, domain.FieldName) {
+	lhsBlocks, rhsBlocks := plan.lhsPlan.EstNumBlocks(), plan.rhsPlan.EstNumBlocks()
+	if rhsBlocks < lhsBlocks || (rhsBlocks == lhsBlocks && plan.rhsPlan.EstNumRecord() < plan.lhsPlan.EstNumRecord()) {
+		return plan.rhsPlan, plan.lhsPlan, plan.rhsFld, plan.lhsFld
+	}
+
+	return plan.lhsPlan, plan.rhsPlan, plan.lhsFld, plan.rhsFld
+}
+
+func (plan *HashJoinPlan) needsPartition() bool {
+	build, _, _, _ := plan.buildProbe()
+
+	return build.EstNumBlocks() > plan.txn.Available()
+}
+
+// numPartitions returns the number of partitions.
+// partition 中はそれぞれの partition が buffer を一つずつ pin するので, 利用可能な buffer 数未満にする.
+func (plan *HashJoinPlan) numPartitions() int {
+	build, _, _, _ := plan.buildProbe()
+	available := math.Max(1, plan.txn.Available())
+	num := (build.EstNumBlocks() + available - 1) / available
+
+	return math.Max(2, math.Min(num, available-1))
+}
+
+// partition splits records of p into temporary tables by hash value of fld.
+// fld が NULL の record はどの record とも join されないので捨てる.
+// 失敗した場合は作りかけの partition を drop する.
+func (plan *HashJoinPlan) partition(p domain.Planner, fld domain.FieldName, numParts int) ([]*domain.TempTable, error) {
+	parts := make([]*domain.TempTable, 0, numParts)
+	for i := 0; i < numParts; i++ {
+		parts = append(parts, domain.NewTempTable(plan.txn, p.Schema()))
+	}
+
+	if err := plan.writePartitions(p, fld, parts); err != nil {
+		dropTempTables(parts)
+
+		return nil, errors.Err(err, "writePartitions")
+	}
+
+	return parts, nil
+}
+
+// writePartitions writes records of p into parts by hash value of fld.
+func (plan *HashJoinPlan) writePartitions(p domain.Planner, fld domain.FieldName, parts []*domain.TempTable) error {
+	src, err := p.Open()
+	if err != nil {
+		return errors.Err(err, "Open")
+	}
+	defer src.Close()
+
+	dests := make([]*domain.TableScan, 0, len(parts))
+	defer func() {
+		for _, dest := range dests {
+			dest.Close()
+		}
+	}()
+	for _, part := range parts {
+		dest, err := part.Open()
+		if err != nil {
+			return errors.Err(err, "Open")
+		}
+		dests = append(dests, dest)
+	}
+
+	if err := src.BeforeFirst(); err != nil {
+		return errors.Err(err, "BeforeFirst")
+	}
+	for src.HasNext() {
+		key, err := src.GetVal(fld)
+		if err != nil {
+			return errors.Err(err, "GetVal")
+		}
+		if key.IsNull() {
+			continue
+		}
+
+		dest := dests[key.HashCode()%len(parts)]
+		if err := dest.AdvanceNextInsertSlotID(); err != nil {
+			return errors.Err(err, "AdvanceNextInsertSlotID")
+		}
+		for _, f := range p.Schema().Fields() {
+			val, err := src.GetVal(f)
+			if err != nil {
+				return errors.Err(err, "GetVal")
+			}
+			if err := dest.SetVal(f, val); err != nil {
+				return errors.Err(err, "SetVal")
+			}
+		}
+	}
+	if err := src.Err(); err != nil {
+		return errors.Err(err, "HasNext")
+	}
+
+	return nil
+}
@@ -0,0 +1,382 @@
+package plan
+
+import (
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+	"github.com/goropikari/simpledbgo/lexer"
+	"github.com/goropikari/simpledbgo/parser"
+)
+
+// HeuristicQueryPlanner is a query planner which pushes selections down and uses indexes.
+// 各 table の select を先に適用し, 出力件数が最小になるように greedy に結合順を決める.
+type HeuristicQueryPlanner struct {
+	metadataMgr domain.MetadataManager
+}
+
+// NewHeuristicQueryPlanner constructs a HeuristicQueryPlanner.
+func NewHeuristicQueryPlanner(metadataMgr domain.MetadataManager) *HeuristicQueryPlanner {
+	return &HeuristicQueryPlanner{
+		metadataMgr: metadataMgr,
+	}
+}
+
+// CreatePlan creates a planner.
+func (planner *HeuristicQueryPlanner) CreatePlan(data *domain.QueryData, txn domain.Transaction) (domain.Planner, error) {
+	// right join では comma で並べた table 側が NULL で埋められるので, where を push down できない.
+	pred := data.Predicate()
+	pushDown := true
+	for _, join := range data.Joins() {
+		if join.Type() == domain.RightOuterJoin {
+			pushDown = false
+			pred = domain.NewPredicate([]domain.Term{})
+		}
+	}
+
+	tps := make([]*tablePlanner, 0, len(data.Tables()))
+	for _, tblName := range data.Tables() {
+		plan, err := planner.tablePlan(tblName, txn)
+		if err != nil {
+			return nil, errors.Err(err, "tablePlan")
+		}
+		tp, err := newTablePlanner(txn, planner.metadataMgr, plan, pred)
+		if err != nil {
+			return nil, errors.Err(err, "newTablePlanner")
+		}
+		tps = append(tps, tp)
+	}
+
+	// 1 つの table を 1 つの key で sort するだけなら index の順序で読んで sort を省く.
+	if len(tps) == 1 && len(data.Joins()) == 0 && len(data.GroupBy()) == 0 && len(data.Aggregations()) == 0 && len(data.OrderBy()) == 1 {
+		key := data.OrderBy()[0]
+		if _, computed := data.Expressions()[key.FieldName()]; !computed {
+			plan, ok, err := tps[0].orderedPlan(key)
+			if err != nil {
+				return nil, errors.Err(err, "orderedPlan")
+			}
+			if ok {
+				pp, err := NewProjectPlanWithExpressions(plan, data.Fields(), data.Expressions())
+				if err != nil {
+					return nil, errors.Err(err, "NewProjectPlanWithExpressions")
+				}
+
+				return pp, nil
+			}
+		}
+	}
+
+	plan, tps := lowestSelectPlan(tps)
+	for len(tps) > 0 {
+		next, rest, err := lowestJoinPlan(plan, tps)
+		if err != nil {
+			return nil, errors.Err(err, "lowestJoinPlan")
+		}
+		plan, tps = next, rest
+	}
+	commaSch := plan.Schema()
+
+	for _, join := range data.Joins() {
+		rhs, err := planner.tablePlan(join.TableName(), txn)
+		if err != nil {
+			return nil, errors.Err(err, "tablePlan")
+		}
+		if join.Type() == domain.InnerJoin {
+			jp, err := joinPlan(txn, planner.metadataMgr, plan, rhs, join.Predicate())
+			if err != nil {
+				return nil, errors.Err(err, "joinPlan")
+			}
+			plan, err = NewSelectPlan(jp, join.Predicate())
+			if err != nil {
+				return nil, errors.Err(err, "NewSelectPlan")
+			}
+		} else {
+			jp, err := NewJoinPlan(plan, rhs, join.Type(), join.Predicate())
+			if err != nil {
+				return nil, errors.Err(err, "NewJoinPlan")
+			}
+			plan = jp
+		}
+	}
+
+	// comma で並べた table だけで評価できる条件は push down 済みなので, 残りを最後に適用する.
+	remaining := make([]domain.Term, 0)
+	for _, term := range data.Predicate().Terms() {
+		if !pushDown || !term.AppliesTo(commaSch) {
+			remaining = append(remaining, term)
+		}
+	}
+	if len(remaining) > 0 {
+		sp, err := NewSelectPlan(plan, domain.NewPredicate(remaining))
+		if err != nil {
+			return nil, errors.Err(err, "NewSelectPlan")
+		}
+		plan = sp
+	}
+
+	return projectQueryPlan(txn, plan, data)
+}
+
+// tablePlan creates a planner of the table or the view.
+func (planner *HeuristicQueryPlanner) tablePlan(tblName domain.TableName, txn domain.Transaction) (domain.Planner, error) {
+	viewDef, err := planner.metadataMgr.GetViewDef(tblName.ToViewName(), txn)
+	if err != nil {
+		return nil, errors.Err(err, "GetViewDef")
+	}
+
+	if viewDef == "" {
+		plan, err := NewTablePlan(txn, tblName, planner.metadataMgr)
+		if err != nil {
+			return nil, errors.Err(err, "NewTablePlan")
+		}
+
+		return plan, nil
+	}
+
+	l := lexer.NewLexer(viewDef.String())
+	tokens, err := l.ScanTokens()
+	if err != nil {
+		return nil, errors.Err(err, "ScanTokens")
+	}
+
+	p := parser.NewParser(tokens)
+	viewData, err := p.Query()
+	if err != nil {
+		return nil, errors.Err(err, "Query")
+	}
+
+	plan, err := planner.CreatePlan(viewData, txn)
+	if err != nil {
+		return nil, errors.Err(err, "CreatePlan")
+	}
+
+	return plan, nil
+}
+
+// lowestSelectPlan picks the table whose selected output is the smallest.
+func lowestSelectPlan(tps []*tablePlanner) (domain.Planner, []*tablePlanner) {
+	best := 0
+	for i, tp := range tps {
+		if tp.selectPlan.EstNumRecord() < tps[best].selectPlan.EstNumRecord() {
+			best = i
+		}
+	}
+
+	return tps[best].selectPlan, removeTablePlanner(tps, best)
+}
+
+// lowestJoinPlan joins current with the table which makes the smallest output.
+// 結合条件を持つ table を優先し, 無い場合は product を取る.
+func lowestJoinPlan(current domain.Planner, tps []*tablePlanner) (domain.Planner, []*tablePlanner, error) {
+	var best domain.Planner
+	bestIdx := -1
+	for i, tp := range tps {
+		plan, found, err := tp.makeJoinPlan(current)
+		if err != nil {
+			return nil, nil, errors.Err(err, "makeJoinPlan")
+		}
+		if found && (best == nil || plan.EstNumRecord() < best.EstNumRecord()) {
+			best, bestIdx = plan, i
+		}
+	}
+	if best != nil {
+		return best, removeTablePlanner(tps, bestIdx), nil
+	}
+
+	for i, tp := range tps {
+		plan, err := tp.makeProductPlan(current)
+		if err != nil {
+			return nil, nil, errors.Err(err, "makeProductPlan")
+		}
+		if best == nil || plan.EstNumRecord() < best.EstNumRecord() {
+			best, bestIdx = plan, i
+		}
+	}
+
+	return best, removeTablePlanner(tps, bestIdx), nil
+}
+
+func removeTablePlanner(tps []*tablePlanner, i int) []*tablePlanner {
+	rest := make([]*tablePlanner, 0, len(tps)-1)
+	rest = append(rest, tps[:i]...)
+
+	return append(rest, tps[i+1:]...)
+}
+
+// tablePlanner makes plans of a table for HeuristicQueryPlanner.
+type tablePlanner struct {
+	txn        domain.Transaction
+	md         domain.MetadataManager
+	plan       domain.Planner
+	pred       *domain.Predicate
+	selectPlan domain.Planner
+	indexed    bool
+}
+
+// newTablePlanner constructs a tablePlanner.
+// plan が table の場合は等値条件に合う index があれば index select を使う.
+func newTablePlanner(txn domain.Transaction, md domain.MetadataManager, plan domain.Planner, pred *domain.Predicate) (*tablePlanner, error) {
+	tp := &tablePlanner{
+		txn:  txn,
+		md:   md,
+		plan: plan,
+		pred: pred,
+	}
+
+	selectPlan, err := tp.indexSelectPlan()
+	if err != nil {
+		return nil, errors.Err(err, "indexSelectPlan")
+	}
+
+	tp.selectPlan, err = addSelectPred(selectPlan, pred.SelectSubPred(plan.Schema()))
+	if err != nil {
+		return nil, errors.Err(err, "addSelectPred")
+	}
+
+	return tp, nil
+}
+
+// indexSelectPlan returns the cheapest plan using an index if the predicate restricts an indexed field.
+// key 全体が等値条件で決まる index を優先し, 無い場合は範囲条件で range scan する.
+// composite index は先頭から等値条件で決まる field とその次の field の範囲条件で range scan できる.
+func (tp *tablePlanner) indexSelectPlan() (domain.Planner, error) {
+	tblPlan, ok := tp.plan.(*TablePlan)
+	if !ok {
+		return tp.plan, nil
+	}
+
+	idxInfos, err := tp.md.GetIndexInfo(tblPlan.tblName, tp.txn)
+	if err != nil {
+		return nil, errors.Err(err, "GetIndexInfo")
+	}
+
+	var best domain.Planner
+	for _, idxInfo := range idxInfos {
+		r, numEq, _ := tp.pred.CompositeIndexRange(idxInfo.FieldNames(), tblPlan.Schema())
+		if numEq < len(idxInfo.FieldNames()) {
+			continue
+		}
+
+		key, _, _ := r.Lower()
+		p := NewIndexSelectPlan(tblPlan, idxInfo, key)
+		if best == nil || p.EstNumBlocks() < best.EstNumBlocks() {
+			best = p
+		}
+	}
+	if best != nil {
+		tp.indexed = true
+
+		return best, nil
+	}
+
+	best = tblPlan
+	for _, idxInfo := range idxInfos {
+		if !idxInfo.SupportsRange() {
+			continue
+		}
+
+		r, _, ok := tp.pred.CompositeIndexRange(idxInfo.FieldNames(), tblPlan.Schema())
+		if !ok {
+			continue
+		}
+
+		// 両側から絞られている範囲は選択率が低いとみなして常に index を使う.
+		p := NewIndexRangePlan(tblPlan, idxInfo, r)
+		if r.IsClosed() && !tp.indexed || p.EstNumBlocks() < best.EstNumBlocks() {
+			best = p
+			tp.indexed = true
+		}
+	}
+
+	return best, nil
+}
+
+// orderedPlan returns a plan which reads the table in the order of key by an index.
+// 他の index を使わず table 全体を読む場合に限り, sort の代わりに index の順序を使う.
+// composite index は先頭の field の順に並んでいるので, 先頭の field で sort する場合に使える.
+func (tp *tablePlanner) orderedPlan(key domain.SortKey) (domain.Planner, bool, error) {
+	tblPlan, ok := tp.plan.(*TablePlan)
+	if !ok || tp.indexed || !tblPlan.Schema().HasField(key.FieldName()) {
+		return nil, false, nil
+	}
+
+	idxInfos, err := tp.md.GetIndexInfo(tblPlan.tblName, tp.txn)
+	if err != nil {
+		return nil, false, errors.Err(err, "GetIndexInfo")
+	}
+
+	for _, idxInfo := range idxInfos {
+		if idxInfo.FieldNames()[0] != key.FieldName() || !idxInfo.SupportsRange() {
+			continue
+		}
+
+		r, _, _ := tp.pred.CompositeIndexRange(idxInfo.FieldNames(), tblPlan.Schema())
+		p := NewIndexRangePlan(tblPlan, idxInfo, r.WithOrder(key.Order()))
+
+		sp, err := addSelectPred(p, tp.pred)
+		if err != nil {
+			return nil, false, errors.Err(err, "addSelectPred")
+		}
+
+		return sp, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// makeJoinPlan joins current and the table if there is a join predicate between them.
+func (tp *tablePlanner) makeJoinPlan(current domain.Planner) (domain.Planner, bool, error) {
+	joinPred := tp.pred.JoinSubPred(current.Schema(), tp.plan.Schema())
+	if len(joinPred.Terms()) == 0 {
+		return nil, false, nil
+	}
+
+	plan, err := joinPlan(tp.txn, tp.md, current, tp.selectPlan, joinPred)
+	if err != nil {
+		return nil, false, errors.Err(err, "joinPlan")
+	}
+
+	// index join は table を直接 scan する必要があるので, select 条件は結合後に適用する.
+	ip, found, err := indexJoinPlan(tp.txn, tp.md, current, tp.plan, joinPred)
+	if err != nil {
+		return nil, false, errors.Err(err, "indexJoinPlan")
+	}
+	if found {
+		p, err := addSelectPred(ip, tp.pred.SelectSubPred(tp.plan.Schema()))
+		if err != nil {
+			return nil, false, errors.Err(err, "addSelectPred")
+		}
+		if p.EstNumBlocks() < plan.EstNumBlocks() {
+			plan = p
+		}
+	}
+
+	sp, err := NewSelectPlan(plan, joinPred)
+	if err != nil {
+		return nil, false, errors.Err(err, "NewSelectPlan")
+	}
+
+	return sp, true, nil
+}
+
+// makeProductPlan makes a product of current and the table.
+func (tp *tablePlanner) makeProductPlan(current domain.Planner) (domain.Planner, error) {
+	plan, err := joinPlan(tp.txn, tp.md, current, tp.selectPlan, domain.NewPredicate([]domain.Term{}))
+	if err != nil {
+		return nil, errors.Err(err, "joinPlan")
+	}
+
+	return plan, nil
+}
+
+// addSelectPred wraps plan by a SelectPlan if pred is not empty.
+func addSelectPred(plan domain.Planner, pred *domain.Predicate) (domain.Planner, error) {
+	if len(pred.Terms()) == 0 {
+		return plan, nil
+	}
+
+	sp, err := NewSelectPlan(plan, pred)
+	if err != nil {
+		return nil, errors.Err(err, "NewSelectPlan")
+	}
+
+	return sp, nil
+}
@@ -0,0 +1,81 @@
+package plan
+
+import (
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// IndexJoinPlan is planner for index nested loop join.
+// lhs の joinField と rhs の index field が等しい record を join する.
+type IndexJoinPlan struct {
+	lhsPlan   domain.Planner
+	rhsPlan   *TablePlan
+	idxInfo   *domain.IndexInfo
+	joinField domain.FieldName
+	schema    *domain.Schema
+}
+
+// NewIndexJoinPlan constructs an IndexJoinPlan.
+func NewIndexJoinPlan(lhs domain.Planner, rhs *TablePlan, idxInfo *domain.IndexInfo, joinField domain.FieldName) *IndexJoinPlan {
+	sch := domain.NewSchema()
+	sch.AddAllFields(lhs.Schema())
+	sch.AddAllFields(rhs.Schema())
+
+	return &IndexJoinPlan{
+		lhsPlan:   lhs,
+		rhsPlan:   rhs,
+		idxInfo:   idxInfo,
+		joinField: joinField,
+		schema:    sch,
+	}
+}
+
+// Open opens scanner.
+func (plan *IndexJoinPlan) Open() (domain.Scanner, error) {
+	lhs, err := plan.lhsPlan.Open()
+	if err != nil {
+		return nil, errors.Err(err, "Open")
+	}
+
+	rhs, err := plan.rhsPlan.Open()
+	if err != nil {
+		lhs.Close()
+
+		return nil, errors.Err(err, "Open")
+	}
+
+	ts, ok := rhs.(*domain.TableScan)
+	if !ok {
+		lhs.Close()
+		rhs.Close()
+
+		return nil, ErrUnexpectedScan
+	}
+
+	return domain.NewIndexJoinScan(lhs, plan.idxInfo.Open(), plan.joinField, ts)
+}
+
+// EstNumBlocks estimates the number of block access.
+// lhs の record ごとに index を検索し, 該当する rhs の record を読む.
+func (plan *IndexJoinPlan) EstNumBlocks() int {
+	return plan.lhsPlan.EstNumBlocks() + plan.lhsPlan.EstNumRecord()*plan.idxInfo.EstBlockAccessed() + plan.EstNumRecord()
+}
+
+// EstNumRecord estimates the number of record access.
+func (plan *IndexJoinPlan) EstNumRecord() int {
+	return plan.lhsPlan.EstNumRecord() * plan.idxInfo.EstNumRecord()
+}
+
+// EstDistinctVals estimates the number of distinct value at given fldName.
+func (plan *IndexJoinPlan) EstDistinctVals(fldName domain.FieldName) int {
+	if plan.lhsPlan.Schema().HasField(fldName) {
+		return plan.lhsPlan.EstDistinctVals(fldName)
+	}
+
+	return plan.rhsPlan.EstDistinctVals(fldName)
+}
+
+// Schema returns schema of joined records.
+func (plan *IndexJoinPlan) Schema() *domain.Schema {
+	return plan.schema
+}
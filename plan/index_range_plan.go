@@ -0,0 +1,59 @@
+package plan
+
+import (
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+	"github.com/goropikari/simpledbgo/math"
+)
+
+// IndexRangePlan is planner which reads records whose key is in a range by index.
+type IndexRangePlan struct {
+	p       domain.Planner
+	idxInfo *domain.IndexInfo
+	r       domain.IndexRange
+}
+
+// NewIndexRangePlan constructs an IndexRangePlan.
+func NewIndexRangePlan(p domain.Planner, idxInfo *domain.IndexInfo, r domain.IndexRange) *IndexRangePlan {
+	return &IndexRangePlan{
+		p:       p,
+		idxInfo: idxInfo,
+		r:       r,
+	}
+}
+
+// Open opens a scanner.
+func (rp *IndexRangePlan) Open() (domain.Scanner, error) {
+	s, err := rp.p.Open()
+	if err != nil {
+		return nil, errors.Err(err, "Open")
+	}
+	ts, ok := s.(*domain.TableScan)
+	if !ok {
+		return nil, ErrUnexpectedScan
+	}
+
+	return domain.NewIndexRangeScan(ts, rp.idxInfo.Open(), rp.r)
+}
+
+// EstNumBlocks estimates the number of block access.
+// 範囲内の record ごとに table の block を読むとみなす.
+func (rp *IndexRangePlan) EstNumBlocks() int {
+	return rp.idxInfo.EstBlockAccessed() + rp.EstNumRecord()
+}
+
+// EstNumRecord estimates the number of record access.
+func (rp *IndexRangePlan) EstNumRecord() int {
+	return rp.idxInfo.EstNumRecordInRange(rp.r)
+}
+
+// EstDistinctVals estimates the number of distinct value at given fldName.
+func (rp *IndexRangePlan) EstDistinctVals(fldName domain.FieldName) int {
+	// statistics が空の table でも reduction factor が 0 にならないようにする.
+	return math.Max[int](1, math.Min[int](rp.p.EstDistinctVals(fldName), rp.EstNumRecord()))
+}
+
+// Schema returns schema of table schema.
+func (rp *IndexRangePlan) Schema() *domain.Schema {
+	return rp.p.Schema()
+}
@@ -0,0 +1,605 @@
+package plan
+
+import (
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// IndexUpdatePlanner is an update planner which keeps indexes up to date.
+type IndexUpdatePlanner struct {
+	metadataMgr domain.MetadataManager
+}
+
+// NewIndexUpdatePlanner constructs an IndexUpdatePlanner.
+func NewIndexUpdatePlanner(mgr domain.MetadataManager) *IndexUpdatePlanner {
+	return &IndexUpdatePlanner{metadataMgr: mgr}
+}
+
+// ExecuteInsert executes insersion command.
+// record を挿入した後に, 各 index に挿入した値と record id を登録する.
+func (p *IndexUpdatePlanner) ExecuteInsert(data *domain.InsertData, txn domain.Transaction) (int, error) {
+	plan, err := NewTablePlan(txn, data.TableName(), p.metadataMgr)
+	if err != nil {
+		return 0, errors.Err(err, "NewTablePlan")
+	}
+
+	s, err := plan.Open()
+	if err != nil {
+		return 0, errors.Err(err, "Open")
+	}
+
+	us, ok := s.(domain.UpdateScanner)
+	if !ok {
+		return 0, ErrNotUpdatable
+	}
+	defer us.Close()
+
+	if err = us.AdvanceNextInsertSlotID(); err != nil {
+		return 0, errors.Err(err, "AdvanceNextInsertSlotID")
+	}
+
+	if err := insertValues(us, plan.Schema(), data); err != nil {
+		return 0, errors.Err(err, "insertValues")
+	}
+
+	idxs, err := p.openIndexes(data.TableName(), txn)
+	if err != nil {
+		return 0, errors.Err(err, "openIndexes")
+	}
+	defer closeIndexes(idxs)
+
+	// 制約に違反する場合は挿入した record を消してから error を返す.
+	rid := us.RecordID()
+	for _, idx := range idxs {
+		if err := checkUnique(us, idx, rid); err != nil {
+			if err2 := us.Delete(); err2 != nil {
+				return 0, errors.Err(err2, "Delete")
+			}
+
+			return 0, errors.Err(err, "checkUnique")
+		}
+	}
+
+	// 値が指定されなかった field も index に登録するため scan から値を読む.
+	keys := make([]domain.Constant, 0, len(idxs))
+	for _, idx := range idxs {
+		val, err := indexKey(us, idx.info.FieldNames())
+		if err != nil {
+			return 0, errors.Err(err, "indexKey")
+		}
+		if err := idx.Insert(val, rid); err != nil {
+			return 0, errors.Err(err, "Insert")
+		}
+		keys = append(keys, val)
+	}
+
+	// 自身を参照する record も許すため, index に登録した後に参照先を確認する.
+	if err := p.checkForeignKeys(data.TableName(), us, foreignKeys(idxs, nil), txn); err != nil {
+		for i, idx := range idxs {
+			if err2 := idx.Delete(keys[i], rid); err2 != nil {
+				return 0, errors.Err(err2, "Delete")
+			}
+		}
+		if err2 := us.Delete(); err2 != nil {
+			return 0, errors.Err(err2, "Delete")
+		}
+
+		return 0, errors.Err(err, "checkForeignKeys")
+	}
+
+	return 1, nil
+}
+
+// ExecuteDelete executes delete command.
+// record を削除する前に, 各 index から該当する entry を削除する.
+// 削除した record を参照する record には ON DELETE の動作を適用する.
+func (p *IndexUpdatePlanner) ExecuteDelete(data *domain.DeleteData, txn domain.Transaction) (int, error) {
+	tp, err := NewTablePlan(txn, data.TableName(), p.metadataMgr)
+	if err != nil {
+		return 0, errors.Err(err, "NewTablePlan")
+	}
+
+	idxs, err := p.openIndexes(data.TableName(), txn)
+	if err != nil {
+		return 0, errors.Err(err, "openIndexes")
+	}
+	defer closeIndexes(idxs)
+
+	if err := pruneTable(data.TableName(), tp.layout, idxs, txn); err != nil {
+		return 0, errors.Err(err, "pruneTable")
+	}
+
+	plan, err := NewSelectPlan(tp, data.Predicate())
+	if err != nil {
+		return 0, errors.Err(err, "NewSelectPlan")
+	}
+	s, err := plan.Open()
+	if err != nil {
+		return 0, errors.Err(err, "Open")
+	}
+
+	us, ok := s.(domain.UpdateScanner)
+	if !ok {
+		return 0, ErrNotUpdatable
+	}
+	defer us.Close()
+
+	refs, err := p.referencingKeys(data.TableName(), txn)
+	if err != nil {
+		return 0, errors.Err(err, "referencingKeys")
+	}
+
+	cnt := 0
+	for us.HasNext() {
+		if err := p.deleteRecord(data.TableName(), tp.layout, us, idxs, refs, txn); err != nil {
+			return 0, errors.Err(err, "deleteRecord")
+		}
+		cnt++
+	}
+	if us.Err() != nil {
+		return 0, errors.Err(us.Err(), "HasNext")
+	}
+
+	return cnt, nil
+}
+
+// ExecuteModify executes update command.
+// 更新する field を key に含む index は古い値の entry を削除して新しい値を登録する.
+func (p *IndexUpdatePlanner) ExecuteModify(data *domain.ModifyData, txn domain.Transaction) (int, error) {
+	tp, err := NewTablePlan(txn, data.TableName(), p.metadataMgr)
+	if err != nil {
+		return 0, errors.Err(err, "NewTablePlan")
+	}
+	plan, err := NewSelectPlan(tp, data.Predicate())
+	if err != nil {
+		return 0, errors.Err(err, "NewSelectPlan")
+	}
+
+	if err := checkAssignment(plan.Schema(), data.FieldName(), data.Expression()); err != nil {
+		return 0, errors.Err(err, "checkAssignment")
+	}
+
+	idxs, err := p.openIndexes(data.TableName(), txn)
+	if err != nil {
+		return 0, errors.Err(err, "openIndexes")
+	}
+	defer closeIndexes(idxs)
+
+	if err := pruneTable(data.TableName(), tp.layout, idxs, txn); err != nil {
+		return 0, errors.Err(err, "pruneTable")
+	}
+
+	affected := make([]openedIndex, 0, len(idxs))
+	for _, idx := range idxs {
+		for _, fld := range idx.info.FieldNames() {
+			if fld == data.FieldName() {
+				affected = append(affected, idx)
+
+				break
+			}
+		}
+	}
+	fkeys := foreignKeys(idxs, []domain.FieldName{data.FieldName()})
+
+	// 参照されている key は変更できない.
+	allRefs, err := p.referencingKeys(data.TableName(), txn)
+	if err != nil {
+		return 0, errors.Err(err, "referencingKeys")
+	}
+	refs := make([]referencingKey, 0, len(allRefs))
+	for _, ref := range allRefs {
+		if containsAny(ref.constraint.RefFieldNames(), []domain.FieldName{data.FieldName()}) {
+			refs = append(refs, ref)
+		}
+	}
+
+	s, err := plan.Open()
+	if err != nil {
+		return 0, errors.Err(err, "Open")
+	}
+
+	us, ok := s.(domain.UpdateScanner)
+	if !ok {
+		return 0, ErrNotUpdatable
+	}
+	defer us.Close()
+
+	cnt := 0
+	for us.HasNext() {
+		val, err := data.Expression().Evaluate(us)
+		if err != nil {
+			return 0, errors.Err(err, "Evaluate")
+		}
+
+		oldKeys := make([]domain.Constant, 0, len(affected))
+		for _, idx := range affected {
+			key, err := indexKey(us, idx.info.FieldNames())
+			if err != nil {
+				return 0, errors.Err(err, "indexKey")
+			}
+			oldKeys = append(oldKeys, key)
+		}
+		oldRefKeys := make([]domain.Constant, 0, len(refs))
+		for _, ref := range refs {
+			key, err := indexKey(us, ref.constraint.RefFieldNames())
+			if err != nil {
+				return 0, errors.Err(err, "indexKey")
+			}
+			oldRefKeys = append(oldRefKeys, key)
+		}
+
+		// 制約に違反する値は record に書き込まない.
+		as, err := newAssignedScan(us, plan.Schema(), data.FieldName(), val)
+		if err != nil {
+			return 0, errors.Err(err, "newAssignedScan")
+		}
+		if err := domain.CheckFieldConstraints(plan.Schema(), as); err != nil {
+			return 0, errors.Err(err, "CheckFieldConstraints")
+		}
+		rid := us.RecordID()
+		for _, idx := range affected {
+			if err := checkUnique(as, idx, rid); err != nil {
+				return 0, errors.Err(err, "checkUnique")
+			}
+		}
+		for i, ref := range refs {
+			if err := p.checkKeyNotReferenced(as, ref, oldRefKeys[i], txn); err != nil {
+				return 0, errors.Err(err, "checkKeyNotReferenced")
+			}
+		}
+
+		oldVal, err := us.GetVal(data.FieldName())
+		if err != nil {
+			return 0, errors.Err(err, "GetVal")
+		}
+		if err := saveVersion(us, idxs); err != nil {
+			return 0, errors.Err(err, "saveVersion")
+		}
+		if err = us.SetVal(data.FieldName(), as.val); err != nil {
+			return 0, errors.Err(err, "SetVal")
+		}
+		newKeys, err := moveIndexEntries(us, affected, oldKeys, rid)
+		if err != nil {
+			return 0, errors.Err(err, "moveIndexEntries")
+		}
+
+		// 自身を参照する record も許すため, index を更新した後に参照先を確認する.
+		// 違反する場合は元の値と index の entry に戻してから error を返す.
+		if err := p.checkForeignKeys(data.TableName(), us, fkeys, txn); err != nil {
+			if err2 := us.SetVal(data.FieldName(), oldVal); err2 != nil {
+				return 0, errors.Err(err2, "SetVal")
+			}
+			if _, err2 := moveIndexEntries(us, affected, newKeys, rid); err2 != nil {
+				return 0, errors.Err(err2, "moveIndexEntries")
+			}
+
+			return 0, errors.Err(err, "checkForeignKeys")
+		}
+		cnt++
+	}
+	if us.Err() != nil {
+		return 0, us.Err()
+	}
+
+	return cnt, nil
+}
+
+// ExecuteCreateTable executes create table command.
+// UNIQUE, PRIMARY KEY, FOREIGN KEY 制約は table と一緒に index を作る.
+// 自身の key を参照する FOREIGN KEY のため, FOREIGN KEY は最後に作る.
+func (p *IndexUpdatePlanner) ExecuteCreateTable(data *domain.CreateTableData, txn domain.Transaction) (int, error) {
+	if err := p.metadataMgr.CreateTable(data.TableName(), data.Schema(), data.Format(), txn); err != nil {
+		return 0, errors.Err(err, "CreateTable")
+	}
+
+	for _, fk := range []bool{false, true} {
+		for _, c := range data.Constraints() {
+			if (c.Type() == domain.ForeignKeyConstraint) != fk {
+				continue
+			}
+			if err := p.metadataMgr.CreateConstraint(data.TableName(), c, txn); err != nil {
+				return 0, errors.Err(err, "CreateConstraint")
+			}
+		}
+	}
+
+	return 0, nil
+}
+
+// ExecuteCreateView executes create view command.
+func (p *IndexUpdatePlanner) ExecuteCreateView(data *domain.CreateViewData, txn domain.Transaction) (int, error) {
+	return 0, p.metadataMgr.CreateView(data.ViewName(), data.ViewDef(), txn)
+}
+
+// ExecuteDropTable executes drop table command.
+func (p *IndexUpdatePlanner) ExecuteDropTable(data *domain.DropTableData, txn domain.Transaction) (int, error) {
+	err := p.metadataMgr.DropTable(data.TableName(), txn)
+
+	return 0, ignoreNotFound(err, domain.ErrTableNotFound, data.IfExists())
+}
+
+// ExecuteDropView executes drop view command.
+func (p *IndexUpdatePlanner) ExecuteDropView(data *domain.DropViewData, txn domain.Transaction) (int, error) {
+	err := p.metadataMgr.DropView(data.ViewName(), txn)
+
+	return 0, ignoreNotFound(err, domain.ErrViewNotFound, data.IfExists())
+}
+
+// ExecuteDropIndex executes drop index command.
+func (p *IndexUpdatePlanner) ExecuteDropIndex(data *domain.DropIndexData, txn domain.Transaction) (int, error) {
+	err := p.metadataMgr.DropIndex(data.IndexName(), txn)
+
+	return 0, ignoreNotFound(err, domain.ErrIndexNotFound, data.IfExists())
+}
+
+// ExecuteCreateIndex executes create index command.
+// 既存の record も index に登録する.
+func (p *IndexUpdatePlanner) ExecuteCreateIndex(data *domain.CreateIndexData, txn domain.Transaction) (int, error) {
+	plan, err := NewTablePlan(txn, data.TableName(), p.metadataMgr)
+	if err != nil {
+		return 0, errors.Err(err, "NewTablePlan")
+	}
+
+	for _, fld := range data.FieldNames() {
+		if !plan.Schema().HasField(fld) {
+			return 0, errors.Wrap(domain.ErrFieldNotFound, fld.String())
+		}
+		if !plan.Schema().Type(fld).IsIndexable() {
+			return 0, errors.Wrap(domain.ErrNotIndexable, fld.String())
+		}
+	}
+
+	if err := p.metadataMgr.CreateIndex(data.IndexName(), data.TableName(), data.FieldNames(), data.IndexType(), txn); err != nil {
+		return 0, errors.Err(err, "CreateIndex")
+	}
+
+	idxs, err := p.openIndexes(data.TableName(), txn)
+	if err != nil {
+		return 0, errors.Err(err, "openIndexes")
+	}
+	defer closeIndexes(idxs)
+
+	var idx openedIndex
+	for _, opened := range idxs {
+		if opened.info.IndexName() == data.IndexName() {
+			idx = opened
+		}
+	}
+
+	// 他の transaction が読む古い version の record も登録する.
+	ts, err := domain.NewTableScan(txn, data.TableName(), plan.layout)
+	if err != nil {
+		return 0, errors.Err(err, "NewTableScan")
+	}
+	defer ts.Close()
+
+	for ts.HasNextVersion() {
+		val, err := indexKey(ts, data.FieldNames())
+		if err != nil {
+			return 0, errors.Err(err, "indexKey")
+		}
+		if err := idx.Insert(val, ts.RecordID()); err != nil {
+			return 0, errors.Err(err, "Insert")
+		}
+	}
+	if ts.Err() != nil {
+		return 0, errors.Err(ts.Err(), "HasNextVersion")
+	}
+
+	return 0, nil
+}
+
+// openedIndex is an opened index with its information.
+// constraint は index が UNIQUE, PRIMARY KEY, FOREIGN KEY 制約のためのものである場合に設定される.
+// tblName と layout は entry の record が最新の version かどうかを確かめるのに使う.
+type openedIndex struct {
+	domain.Indexer
+	info       *domain.IndexInfo
+	constraint *domain.Constraint
+	tblName    domain.TableName
+	layout     *domain.Layout
+	txn        domain.Transaction
+}
+
+// hasNextLatest moves to the next entry whose record is the latest version.
+// 更新, 削除された古い version の record の entry も残っているので読み飛ばす.
+func (idx openedIndex) hasNextLatest() (bool, error) {
+	for idx.HasNext() {
+		rid, err := idx.GetDataRecordID()
+		if err != nil {
+			return false, errors.Err(err, "GetDataRecordID")
+		}
+		latest, err := domain.IsLatestVersion(idx.txn, idx.tblName, idx.layout, rid)
+		if err != nil {
+			return false, errors.Err(err, "IsLatestVersion")
+		}
+		if latest {
+			return true, nil
+		}
+	}
+
+	return false, idx.Err()
+}
+
+// openIndexes opens all indexes of the table.
+func (p *IndexUpdatePlanner) openIndexes(tblName domain.TableName, txn domain.Transaction) ([]openedIndex, error) {
+	idxInfos, err := p.metadataMgr.GetIndexInfo(tblName, txn)
+	if err != nil {
+		return nil, errors.Err(err, "GetIndexInfo")
+	}
+
+	cons, err := p.metadataMgr.GetConstraints(tblName, txn)
+	if err != nil {
+		return nil, errors.Err(err, "GetConstraints")
+	}
+
+	layout, err := p.metadataMgr.GetTableLayout(tblName, txn)
+	if err != nil {
+		return nil, errors.Err(err, "GetTableLayout")
+	}
+
+	idxs := make([]openedIndex, 0, len(idxInfos))
+	for _, info := range idxInfos {
+		idx := openedIndex{Indexer: info.Open(), info: info, tblName: tblName, layout: layout, txn: txn}
+		for i := range cons {
+			if cons[i].Name() == info.IndexName() {
+				idx.constraint = &cons[i]
+			}
+		}
+		idxs = append(idxs, idx)
+	}
+
+	return idxs, nil
+}
+
+// openIndex opens the index of the table.
+// index が無い場合は false を返す.
+func (p *IndexUpdatePlanner) openIndex(tblName domain.TableName, idxName domain.IndexName, txn domain.Transaction) (openedIndex, bool, error) {
+	infos, err := p.metadataMgr.GetIndexInfo(tblName, txn)
+	if err != nil {
+		return openedIndex{}, false, errors.Err(err, "GetIndexInfo")
+	}
+
+	for _, info := range infos {
+		if info.IndexName() != idxName {
+			continue
+		}
+
+		layout, err := p.metadataMgr.GetTableLayout(tblName, txn)
+		if err != nil {
+			return openedIndex{}, false, errors.Err(err, "GetTableLayout")
+		}
+
+		return openedIndex{Indexer: info.Open(), info: info, tblName: tblName, layout: layout, txn: txn}, true, nil
+	}
+
+	return openedIndex{}, false, nil
+}
+
+// checkUnique checks that no other record has the same key as the current record of s.
+// NULL を含む key は他の key と等しくないとみなす.
+func checkUnique(s domain.Scanner, idx openedIndex, rid domain.RecordID) error {
+	if idx.constraint == nil || idx.constraint.Type() == domain.ForeignKeyConstraint {
+		return nil
+	}
+
+	key, err := indexKey(s, idx.info.FieldNames())
+	if err != nil {
+		return errors.Err(err, "indexKey")
+	}
+	if hasNull(key) {
+		return nil
+	}
+
+	if err := idx.BeforeFirst(key); err != nil {
+		return errors.Err(err, "BeforeFirst")
+	}
+	for {
+		found, err := idx.hasNextLatest()
+		if err != nil {
+			return errors.Err(err, "hasNextLatest")
+		}
+		if !found {
+			return nil
+		}
+
+		other, err := idx.GetDataRecordID()
+		if err != nil {
+			return errors.Err(err, "GetDataRecordID")
+		}
+		if !other.Equal(rid) {
+			return domain.NewUniqueViolationError(*idx.constraint, key)
+		}
+	}
+}
+
+// saveVersion keeps the old version of the current record of us before it is changed
+// and registers the entries of the old version in all indexes.
+// 古い version を読む transaction も index から record を見つけられるようにする.
+func saveVersion(us domain.UpdateScanner, idxs []openedIndex) error {
+	rid, saved, err := us.SaveVersion()
+	if err != nil {
+		return errors.Err(err, "SaveVersion")
+	}
+	if !saved {
+		return nil
+	}
+
+	for _, idx := range idxs {
+		key, err := indexKey(us, idx.info.FieldNames())
+		if err != nil {
+			return errors.Err(err, "indexKey")
+		}
+		if err := idx.Insert(key, rid); err != nil {
+			return errors.Err(err, "Insert")
+		}
+	}
+
+	return nil
+}
+
+// moveIndexEntries replaces the entries of rid whose keys are oldKeys with the keys of the current record of s.
+// 登録した key を返す.
+func moveIndexEntries(s domain.Scanner, idxs []openedIndex, oldKeys []domain.Constant, rid domain.RecordID) ([]domain.Constant, error) {
+	newKeys := make([]domain.Constant, 0, len(idxs))
+	for i, idx := range idxs {
+		key, err := indexKey(s, idx.info.FieldNames())
+		if err != nil {
+			return nil, errors.Err(err, "indexKey")
+		}
+		if err := idx.Delete(oldKeys[i], rid); err != nil {
+			return nil, errors.Err(err, "Delete")
+		}
+		if err := idx.Insert(key, rid); err != nil {
+			return nil, errors.Err(err, "Insert")
+		}
+		newKeys = append(newKeys, key)
+	}
+
+	return newKeys, nil
+}
+
+// pruneTable removes the old versions of the table which no transaction reads any more
+// and the entries of them in idxs.
+// 古い version が溜まり続けないように update, delete の前に回収する.
+func pruneTable(tblName domain.TableName, layout *domain.Layout, idxs []openedIndex, txn domain.Transaction) error {
+	ts, err := domain.NewTableScan(txn, tblName, layout)
+	if err != nil {
+		return errors.Err(err, "NewTableScan")
+	}
+	defer ts.Close()
+
+	return ts.Prune(func(s domain.Scanner, rid domain.RecordID) error {
+		for _, idx := range idxs {
+			key, err := indexKey(s, idx.info.FieldNames())
+			if err != nil {
+				return errors.Err(err, "indexKey")
+			}
+			if err := idx.Delete(key, rid); err != nil {
+				return errors.Err(err, "Delete")
+			}
+		}
+
+		return nil
+	})
+}
+
+func closeIndexes(idxs []openedIndex) {
+	for _, idx := range idxs {
+		idx.Close()
+	}
+}
+
+// indexKey reads the key of the index on flds from the current record of s.
+func indexKey(s domain.Scanner, flds []domain.FieldName) (domain.Constant, error) {
+	vals := make([]domain.Constant, 0, len(flds))
+	for _, fld := range flds {
+		val, err := s.GetVal(fld)
+		if err != nil {
+			return domain.Constant{}, errors.Err(err, "GetVal")
+		}
+		vals = append(vals, val)
+	}
+
+	return domain.NewIndexKey(vals), nil
+}
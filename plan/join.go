@@ -0,0 +1,83 @@
+package plan
+
+import (
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+)
+
+// joinPlan returns the cheapest plan which joins lhs and rhs.
+// 等結合の場合は product に加えて hash join と merge join, index がある場合は index join も候補にする.
+// 返す plan は pred による絞り込みを含まないので, 呼び出し側で pred を適用する.
+func joinPlan(txn domain.Transaction, md domain.MetadataManager, lhs, rhs domain.Planner, pred *domain.Predicate) (domain.Planner, error) {
+	candidates := []domain.Planner{
+		NewProductPlan(lhs, rhs),
+		NewProductPlan(rhs, lhs),
+	}
+
+	if lhsFld, rhsFld, ok := equiJoinFields(lhs.Schema(), rhs.Schema(), pred); ok {
+		candidates = append(candidates,
+			NewHashJoinPlan(txn, lhs, rhs, lhsFld, rhsFld),
+			NewMergeJoinPlan(txn, lhs, rhs, lhsFld, rhsFld),
+		)
+	}
+
+	for _, pair := range [][]domain.Planner{{lhs, rhs}, {rhs, lhs}} {
+		p, found, err := indexJoinPlan(txn, md, pair[0], pair[1], pred)
+		if err != nil {
+			return nil, errors.Err(err, "indexJoinPlan")
+		}
+		if found {
+			candidates = append(candidates, p)
+		}
+	}
+
+	best := candidates[0]
+	for _, p := range candidates[1:] {
+		if p.EstNumBlocks() < best.EstNumBlocks() {
+			best = p
+		}
+	}
+
+	return best, nil
+}
+
+// indexJoinPlan returns an IndexJoinPlan if inner is a table which has an index on the field joined with outer.
+func indexJoinPlan(txn domain.Transaction, md domain.MetadataManager, outer, inner domain.Planner, pred *domain.Predicate) (*IndexJoinPlan, bool, error) {
+	tp, ok := inner.(*TablePlan)
+	if !ok {
+		return nil, false, nil
+	}
+
+	idxInfos, err := md.GetIndexInfo(tp.tblName, txn)
+	if err != nil {
+		return nil, false, errors.Err(err, "GetIndexInfo")
+	}
+
+	// index join は key 全体で検索するので single column index だけを使う.
+	for _, idxInfo := range idxInfos {
+		if len(idxInfo.FieldNames()) != 1 {
+			continue
+		}
+
+		fld := idxInfo.FieldNames()[0]
+		other := pred.EquatesWithField(fld)
+		if other != "" && outer.Schema().HasField(other) && outer.Schema().Type(other) == tp.Schema().Type(fld) {
+			return NewIndexJoinPlan(outer, tp, idxInfo, other), true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+// equiJoinFields finds a term F1=F2 in pred such that F1 is a field of lhs and F2 is a field of rhs.
+// 型が異なる field 同士は比較できないので対象外とする.
+func equiJoinFields(lhs, rhs *domain.Schema, pred *domain.Predicate) (domain.FieldName, domain.FieldName, bool) {
+	for _, fld := range lhs.Fields() {
+		other := pred.EquatesWithField(fld)
+		if other != "" && rhs.HasField(other) && lhs.Type(fld) == rhs.Type(other) {
+			return fld, other, true
+		}
+	}
+
+	return "", "", false
+}
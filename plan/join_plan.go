@@ -0,0 +1,94 @@
+package plan
+
+import (
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+	"github.com/goropikari/simpledbgo/math"
+)
+
+// JoinPlan is planner for nested loop join.
+type JoinPlan struct {
+	lhsPlan domain.Planner
+	rhsPlan domain.Planner
+	typ     domain.JoinType
+	pred    *domain.Predicate
+	schema  *domain.Schema
+}
+
+// NewJoinPlan constructs a JoinPlan.
+// pred の式の型は結合した schema で検査する.
+func NewJoinPlan(lhs, rhs domain.Planner, typ domain.JoinType, pred *domain.Predicate) (*JoinPlan, error) {
+	sch := domain.NewSchema()
+	sch.AddAllFields(lhs.Schema())
+	sch.AddAllFields(rhs.Schema())
+
+	if err := pred.TypeCheck(sch); err != nil {
+		return nil, errors.Err(err, "TypeCheck")
+	}
+
+	return &JoinPlan{
+		lhsPlan: lhs,
+		rhsPlan: rhs,
+		typ:     typ,
+		pred:    pred,
+		schema:  sch,
+	}, nil
+}
+
+// Open opens scanner.
+func (plan *JoinPlan) Open() (domain.Scanner, error) {
+	lhs, err := plan.lhsPlan.Open()
+	if err != nil {
+		return nil, errors.Err(err, "Open")
+	}
+	rhs, err := plan.rhsPlan.Open()
+	if err != nil {
+		lhs.Close()
+
+		return nil, errors.Err(err, "Open")
+	}
+
+	return domain.NewJoinScan(lhs, rhs, plan.typ, plan.pred)
+}
+
+// EstNumBlocks estimates the number of block access.
+func (plan *JoinPlan) EstNumBlocks() int {
+	outer, inner := plan.outerInner()
+
+	return outer.EstNumBlocks() + outer.EstNumRecord()*inner.EstNumBlocks()
+}
+
+// EstNumRecord estimates the number of record access.
+// outer join の場合は少なくとも外側の record 数だけ返す.
+func (plan *JoinPlan) EstNumRecord() int {
+	num := plan.lhsPlan.EstNumRecord() * plan.rhsPlan.EstNumRecord() / plan.pred.ReductionFactor(plan)
+	if plan.typ == domain.InnerJoin {
+		return num
+	}
+
+	outer, _ := plan.outerInner()
+
+	return math.Max(num, outer.EstNumRecord())
+}
+
+// EstDistinctVals estimates the number of distinct value at given fldName.
+func (plan *JoinPlan) EstDistinctVals(fldName domain.FieldName) int {
+	if plan.lhsPlan.Schema().HasField(fldName) {
+		return plan.lhsPlan.EstDistinctVals(fldName)
+	}
+
+	return plan.rhsPlan.EstDistinctVals(fldName)
+}
+
+// Schema returns schema of joined records.
+func (plan *JoinPlan) Schema() *domain.Schema {
+	return plan.schema
+}
+
+func (plan *JoinPlan) outerInner() (domain.Planner, domain.Planner) {
+	if plan.typ == domain.RightOuterJoin {
+		return plan.rhsPlan, plan.lhsPlan
+	}
+
+	return plan.lhsPlan, plan.rhsPlan
+}
@@ -0,0 +1,88 @@
+package plan
+
+import (
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+	"github.com/goropikari/simpledbgo/math"
+)
+
+// ErrUnexpectedScan is an error that means a plan returns unexpected type of scanner.
+var ErrUnexpectedScan = errors.New("unexpected scanner")
+
+// MergeJoinPlan is planner for sort-merge join.
+// 両方の入力を join field で sort してから merge する.
+// sort は SortPlan が行うので, buffer に収まらない入力は run として temporary table に書き出される.
+type MergeJoinPlan struct {
+	lhsPlan domain.Planner
+	rhsPlan domain.Planner
+	lhsFld  domain.FieldName
+	rhsFld  domain.FieldName
+	schema  *domain.Schema
+}
+
+// NewMergeJoinPlan constructs a MergeJoinPlan which joins records satisfying lhsFld = rhsFld.
+func NewMergeJoinPlan(txn domain.Transaction, lhs, rhs domain.Planner, lhsFld, rhsFld domain.FieldName) *MergeJoinPlan {
+	sch := domain.NewSchema()
+	sch.AddAllFields(lhs.Schema())
+	sch.AddAllFields(rhs.Schema())
+
+	return &MergeJoinPlan{
+		lhsPlan: NewSortPlan(txn, lhs, []domain.SortKey{domain.NewSortKey(lhsFld, domain.Asc)}),
+		rhsPlan: NewSortPlan(txn, rhs, []domain.SortKey{domain.NewSortKey(rhsFld, domain.Asc)}),
+		lhsFld:  lhsFld,
+		rhsFld:  rhsFld,
+		schema:  sch,
+	}
+}
+
+// Open opens scanner.
+func (plan *MergeJoinPlan) Open() (domain.Scanner, error) {
+	lhs, err := plan.lhsPlan.Open()
+	if err != nil {
+		return nil, errors.Err(err, "Open")
+	}
+
+	rhs, err := plan.rhsPlan.Open()
+	if err != nil {
+		lhs.Close()
+
+		return nil, errors.Err(err, "Open")
+	}
+
+	ss, ok := rhs.(*domain.SortScan)
+	if !ok {
+		lhs.Close()
+		rhs.Close()
+
+		return nil, ErrUnexpectedScan
+	}
+
+	return domain.NewMergeJoinScan(lhs, ss, plan.lhsFld, plan.rhsFld)
+}
+
+// EstNumBlocks estimates the number of block access.
+// SortPlan と同様に sort の cost は含めず, sort 済みの入力を読む cost だけを見積もる.
+func (plan *MergeJoinPlan) EstNumBlocks() int {
+	return plan.lhsPlan.EstNumBlocks() + plan.rhsPlan.EstNumBlocks()
+}
+
+// EstNumRecord estimates the number of record access.
+func (plan *MergeJoinPlan) EstNumRecord() int {
+	distinct := math.Max(plan.lhsPlan.EstDistinctVals(plan.lhsFld), plan.rhsPlan.EstDistinctVals(plan.rhsFld))
+
+	return plan.lhsPlan.EstNumRecord() * plan.rhsPlan.EstNumRecord() / math.Max(1, distinct)
+}
+
+// EstDistinctVals estimates the number of distinct value at given fldName.
+func (plan *MergeJoinPlan) EstDistinctVals(fldName domain.FieldName) int {
+	if plan.lhsPlan.Schema().HasField(fldName) {
+		return plan.lhsPlan.EstDistinctVals(fldName)
+	}
+
+	return plan.rhsPlan.EstDistinctVals(fldName)
+}
+
+// Schema returns schema of joined records.
+func (plan *MergeJoinPlan) Schema() *domain.Schema {
+	return plan.schema
+}
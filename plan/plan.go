@@ -3,6 +3,7 @@ package plan
 import (
 	"github.com/goropikari/simpledbgo/domain"
 	"github.com/goropikari/simpledbgo/errors"
+	"github.com/goropikari/simpledbgo/math"
 )
 
 // TablePlan is planner for table.
@@ -123,11 +124,16 @@ type SelectPlan struct {
 }
 
 // NewSelectPlan constructs a SelectPlan.
-func NewSelectPlan(plan domain.Planner, pred *domain.Predicate) *SelectPlan {
+// pred の式の型は plan の schema で検査する.
+func NewSelectPlan(plan domain.Planner, pred *domain.Predicate) (*SelectPlan, error) {
+	if err := pred.TypeCheck(plan.Schema()); err != nil {
+		return nil, errors.Err(err, "TypeCheck")
+	}
+
 	return &SelectPlan{
 		plan: plan,
 		pred: pred,
-	}
+	}, nil
 }
 
 // Open opens scanner.
@@ -220,7 +226,8 @@ func (sp *IndexSelectPlan) EstNumRecord() int {
 
 // EstDistinctVals estimates the number of distinct value at given fldName.
 func (sp *IndexSelectPlan) EstDistinctVals(fldName domain.FieldName) int {
-	return sp.idxInfo.EstNumRecord()
+	// statistics が空の table でも reduction factor が 0 にならないようにする.
+	return math.Max[int](1, sp.idxInfo.EstNumRecord())
 }
 
 // Schema returns schema of table schema.
@@ -232,13 +239,29 @@ func (sp *IndexSelectPlan) Schema() *domain.Schema {
 type ProjectPlan struct {
 	plan   domain.Planner
 	schema *domain.Schema
+	exprs  map[domain.FieldName]domain.Expression
 }
 
 // NewProjectPlan constructs a ProjectPlan.
 func NewProjectPlan(plan domain.Planner, fields []domain.FieldName) *ProjectPlan {
+	// 計算列が無い場合は error にならない.
+	p, _ := NewProjectPlanWithExpressions(plan, fields, nil)
+
+	return p
+}
+
+// NewProjectPlanWithExpressions constructs a ProjectPlan which has computed fields.
+// 計算列の型は plan の schema から決める.
+func NewProjectPlanWithExpressions(plan domain.Planner, fields []domain.FieldName, exprs map[domain.FieldName]domain.Expression) (*ProjectPlan, error) {
 	sch := domain.NewSchema()
 	for _, f := range fields {
-		if f == "*" {
+		if expr, ok := exprs[f]; ok {
+			typ, length, err := expr.Type(plan.Schema())
+			if err != nil {
+				return nil, errors.Err(err, "Type")
+			}
+			sch.AddField(f, typ, length)
+		} else if f == "*" {
 			for _, f2 := range plan.Schema().Fields() {
 				sch.Add(f2, plan.Schema())
 			}
@@ -250,7 +273,8 @@ func NewProjectPlan(plan domain.Planner, fields []domain.FieldName) *ProjectPlan
 	return &ProjectPlan{
 		plan:   plan,
 		schema: sch,
-	}
+		exprs:  exprs,
+	}, nil
 }
 
 // Open opens scanner.
@@ -260,7 +284,7 @@ func (p *ProjectPlan) Open() (domain.Scanner, error) {
 		return nil, errors.Err(err, "Open")
 	}
 
-	return domain.NewProjectScan(s, p.schema.Fields()), nil
+	return domain.NewProjectScanWithExpressions(s, p.schema.Fields(), p.exprs), nil
 }
 
 // EstNumBlocks estimates the number of block access.
@@ -44,7 +44,12 @@ func (pe Executor) CreateQueryPlan(query string, txn domain.Transaction) (domain
 }
 
 // ExecuteUpdate executes command.
+// 書き換える record と index は snapshot ではなく最新の内容から探す.
+// command が終われば, 続く query は再び snapshot を読む.
 func (pe Executor) ExecuteUpdate(cmd string, txn domain.Transaction) (int, error) {
+	txn.ForUpdate(true)
+	defer txn.ForUpdate(false)
+
 	lex := lexer.NewLexer(cmd)
 	tokens, err := lex.ScanTokens()
 	if err != nil {
@@ -75,6 +80,14 @@ func (pe Executor) ExecuteUpdate(cmd string, txn domain.Transaction) (int, error
 		return pe.updateExecutor.ExecuteCreateView(v, txn)
 	case *domain.CreateIndexData:
 		return pe.updateExecutor.ExecuteCreateIndex(v, txn)
+	case *domain.DropTableData:
+		return pe.updateExecutor.ExecuteDropTable(v, txn)
+	case *domain.DropViewData:
+		return pe.updateExecutor.ExecuteDropView(v, txn)
+	case *domain.DropIndexData:
+		return pe.updateExecutor.ExecuteDropIndex(v, txn)
+	case *domain.AlterTableData:
+		return pe.updateExecutor.ExecuteAlterTable(v, txn)
 	default:
 		return 0, errors.New("must not reach here")
 	}
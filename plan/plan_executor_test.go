@@ -2,14 +2,24 @@ package plan_test
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/goropikari/simpledbgo/buffer"
 	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+	"github.com/goropikari/simpledbgo/file"
+	"github.com/goropikari/simpledbgo/index/btree"
+	"github.com/goropikari/simpledbgo/index/hash"
+	"github.com/goropikari/simpledbgo/log"
 	"github.com/goropikari/simpledbgo/metadata"
 	"github.com/goropikari/simpledbgo/plan"
 	"github.com/goropikari/simpledbgo/testing/fake"
 	"github.com/goropikari/simpledbgo/testing/mock"
+	"github.com/goropikari/simpledbgo/tx"
 	"github.com/stretchr/testify/require"
 )
 
@@ -210,6 +220,3302 @@ func TestExecutor_select_multi_table_better(t *testing.T) {
 	})
 }
 
+func TestExecutor_select_order_by(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 8
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	ctrl := gomock.NewController(t)
+	idxDriver := domain.NewIndexDriver(mock.NewMockIndexFactory(ctrl), mock.NewMockSearchCostCalculator(ctrl))
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	t.Run("test Executor", func(t *testing.T) {
+		qp := plan.NewBasicQueryPlanner(mmgr)
+		ue := plan.NewBasicUpdatePlanner(mmgr)
+		pe := plan.NewExecutor(qp, ue)
+
+		txn := cr.NewTxn()
+		cmd := "create table T1(A int, B varchar(9))"
+		x, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+		require.Equal(t, 0, x)
+
+		// buffer に収まらない件数を入れて run の merge を発生させる.
+		n := 300
+		for i := 0; i < n; i++ {
+			cmd := fmt.Sprintf("insert into T1(A, B) values (%v, 'rec%03d')", (i*37)%50, i)
+			pe.ExecuteUpdate(cmd, txn)
+		}
+
+		qry := "select A, B from T1 order by A desc, B"
+		p, err := pe.CreateQueryPlan(qry, txn)
+		require.NoError(t, err)
+		s, err := p.Open()
+		require.NoError(t, err)
+
+		actual := make([][]any, 0)
+		for s.HasNext() {
+			a, err := s.GetInt32("a")
+			require.NoError(t, err)
+			b, err := s.GetString("b")
+			require.NoError(t, err)
+			actual = append(actual, []any{a, b})
+		}
+		require.NoError(t, s.Err())
+		s.Close()
+
+		err = txn.Commit()
+		require.NoError(t, err)
+
+		expected := make([][]any, 0)
+		for a := 49; a >= 0; a-- {
+			for i := 0; i < n; i++ {
+				if (i*37)%50 == a {
+					expected = append(expected, []any{int32(a), fmt.Sprintf("rec%03d", i)})
+				}
+			}
+		}
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("temporary tables are removed", func(t *testing.T) {
+		qp := plan.NewBasicQueryPlanner(mmgr)
+		ue := plan.NewBasicUpdatePlanner(mmgr)
+		pe := plan.NewExecutor(qp, ue)
+
+		// 一つの run に収まる場合は temporary table を作らない.
+		txn := cr.NewTxn()
+		p, err := pe.CreateQueryPlan("select A, B from T1 where A = 1 order by B", txn)
+		require.NoError(t, err)
+		s, err := p.Open()
+		require.NoError(t, err)
+		require.Empty(t, tempFiles(t, cr.DBPath()))
+		cnt := 0
+		for s.HasNext() {
+			cnt++
+		}
+		require.NoError(t, s.Err())
+		s.Close()
+		require.Equal(t, 6, cnt)
+		require.NoError(t, txn.Commit())
+
+		for _, commit := range []bool{true, false} {
+			txn := cr.NewTxn()
+			p, err := pe.CreateQueryPlan("select A, B from T1 order by A desc, B", txn)
+			require.NoError(t, err)
+			s, err := p.Open()
+			require.NoError(t, err)
+			require.NotEmpty(t, tempFiles(t, cr.DBPath()))
+			cnt := 0
+			for s.HasNext() {
+				cnt++
+			}
+			require.NoError(t, s.Err())
+			s.Close()
+			require.Equal(t, 300, cnt)
+
+			if commit {
+				require.NoError(t, txn.Commit())
+			} else {
+				require.NoError(t, txn.Rollback())
+			}
+			require.Empty(t, tempFiles(t, cr.DBPath()))
+		}
+	})
+}
+
+func TestExecutor_select_group_by(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 8
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	ctrl := gomock.NewController(t)
+	idxDriver := domain.NewIndexDriver(mock.NewMockIndexFactory(ctrl), mock.NewMockSearchCostCalculator(ctrl))
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	qp := plan.NewBetterQueryPlanner(mmgr)
+	ue := plan.NewBasicUpdatePlanner(mmgr)
+	pe := plan.NewExecutor(qp, ue)
+
+	txn := cr.NewTxn()
+	_, err = pe.ExecuteUpdate("create table emp(dept varchar(9), salary int)", txn)
+	require.NoError(t, err)
+	_, err = pe.ExecuteUpdate("create table empty(a int)", txn)
+	require.NoError(t, err)
+
+	n := 100
+	for i := 0; i < n; i++ {
+		cmd := fmt.Sprintf("insert into emp(dept, salary) values ('dept%v', %v)", i%3, i)
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		query    string
+		fields   []domain.FieldName
+		expected [][]any
+	}{
+		{
+			name:   "group by",
+			query:  "select dept, count(*), sum(salary), min(salary), max(salary), avg(salary) from emp group by dept order by dept desc",
+			fields: []domain.FieldName{"dept", "countofall", "sumofsalary", "minofsalary", "maxofsalary", "avgofsalary"},
+			expected: [][]any{
+				{"dept2", int32(33), int32(1650), int32(2), int32(98), int32(50)},
+				{"dept1", int32(33), int32(1617), int32(1), int32(97), int32(49)},
+				{"dept0", int32(34), int32(1683), int32(0), int32(99), int32(49)},
+			},
+		},
+		{
+			name:   "aggregation without group by",
+			query:  "select count(salary), max(dept) from emp where dept = 'dept1'",
+			fields: []domain.FieldName{"countofsalary", "maxofdept"},
+			expected: [][]any{
+				{int32(33), "dept1"},
+			},
+		},
+		{
+			name:   "count empty table",
+			query:  "select count(*) from empty",
+			fields: []domain.FieldName{"countofall"},
+			expected: [][]any{
+				{int32(0)},
+			},
+		},
+		{
+			name:     "group by empty table",
+			query:    "select a, count(*) from empty group by a",
+			fields:   []domain.FieldName{"a", "countofall"},
+			expected: [][]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			p, err := pe.CreateQueryPlan(tt.query, txn)
+			require.NoError(t, err)
+			require.Equal(t, tt.fields, p.Schema().Fields())
+
+			s, err := p.Open()
+			require.NoError(t, err)
+
+			actual := make([][]any, 0)
+			for s.HasNext() {
+				row := make([]any, 0, len(tt.fields))
+				for _, fld := range tt.fields {
+					v, err := s.GetVal(fld)
+					require.NoError(t, err)
+					row = append(row, v.AsVal())
+				}
+				actual = append(actual, row)
+			}
+			require.NoError(t, s.Err())
+			s.Close()
+
+			err = txn.Commit()
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+
+	t.Run("aggregation of null values", func(t *testing.T) {
+		txn := cr.NewTxn()
+		_, err := pe.ExecuteUpdate("create table nums(g int, a int, f double precision, n numeric(10, 2))", txn)
+		require.NoError(t, err)
+		_, err = pe.ExecuteUpdate("insert into nums(g, a, f, n) values (1, null, null, null)", txn)
+		require.NoError(t, err)
+		_, err = pe.ExecuteUpdate("insert into nums(g, a, f, n) values (2, 3, 1.5, '2.50')", txn)
+		require.NoError(t, err)
+
+		for _, query := range []string{
+			"select sum(a), avg(a), sum(f), avg(f), avg(n), min(f), max(n) from nums where g = 1",
+			"select sum(a), avg(a), sum(f), avg(f), avg(n), min(f), max(n) from nums where g = 3",
+			"select g, sum(a), avg(a), sum(f), avg(f), avg(n), min(f), max(n) from nums group by g",
+		} {
+			p, err := pe.CreateQueryPlan(query, txn)
+			require.NoError(t, err)
+
+			s, err := p.Open()
+			require.NoError(t, err)
+			for s.HasNext() {
+				// g = 2 の group 以外は集約する値が無い.
+				allNull := true
+				if s.HasField("g") {
+					g, err := s.GetVal("g")
+					require.NoError(t, err)
+					allNull = g.AsVal() != int32(2)
+				}
+				for _, fld := range p.Schema().Fields() {
+					v, err := s.GetVal(fld)
+					require.NoError(t, err)
+					require.Equal(t, p.Schema().Type(fld), v.Type(), fld)
+					if fld != "g" {
+						require.Equal(t, allNull, v.IsNull(), fld)
+					}
+				}
+			}
+			require.NoError(t, s.Err())
+			s.Close()
+		}
+		require.NoError(t, txn.Commit())
+	})
+
+	t.Run("sum of string field", func(t *testing.T) {
+		txn := cr.NewTxn()
+		_, err := pe.CreateQueryPlan("select sum(dept) from emp", txn)
+		require.ErrorIs(t, err, domain.ErrInvalidAggregation)
+		require.NoError(t, txn.Commit())
+	})
+}
+
+func TestExecutor_select_boolean_predicate(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 8
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	ctrl := gomock.NewController(t)
+	idxDriver := domain.NewIndexDriver(mock.NewMockIndexFactory(ctrl), mock.NewMockSearchCostCalculator(ctrl))
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	qp := plan.NewBasicQueryPlanner(mmgr)
+	ue := plan.NewBasicUpdatePlanner(mmgr)
+	pe := plan.NewExecutor(qp, ue)
+
+	txn := cr.NewTxn()
+	_, err = pe.ExecuteUpdate("create table T1(A int, B varchar(9))", txn)
+	require.NoError(t, err)
+
+	n := 20
+	for i := 0; i < n; i++ {
+		cmd := fmt.Sprintf("insert into T1(A, B) values (%v, 'rec%v')", i, i%2)
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []int32
+	}{
+		{name: "less", query: "select A from T1 where A < 3", expected: []int32{0, 1, 2}},
+		{name: "less equal", query: "select A from T1 where A <= 2", expected: []int32{0, 1, 2}},
+		{name: "greater", query: "select A from T1 where A > 17", expected: []int32{18, 19}},
+		{name: "greater equal", query: "select A from T1 where 18 <= A", expected: []int32{18, 19}},
+		{name: "not equal", query: "select A from T1 where A <> 0 and A < 3", expected: []int32{1, 2}},
+		{name: "or", query: "select A from T1 where A = 1 or A = 15", expected: []int32{1, 15}},
+		{name: "not", query: "select A from T1 where not A >= 2", expected: []int32{0, 1}},
+		{
+			name:     "parentheses",
+			query:    "select A from T1 where (A < 2 or A > 17) and B = 'rec1'",
+			expected: []int32{1, 19},
+		},
+		{
+			name:     "and binds tighter than or",
+			query:    "select A from T1 where A < 2 or A > 17 and B = 'rec1'",
+			expected: []int32{0, 1, 19},
+		},
+		{
+			name:     "not of parentheses",
+			query:    "select A from T1 where not (A >= 2 and A <= 17) and B <> 'rec0'",
+			expected: []int32{1, 19},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			p, err := pe.CreateQueryPlan(tt.query, txn)
+			require.NoError(t, err)
+			s, err := p.Open()
+			require.NoError(t, err)
+
+			actual := make([]int32, 0)
+			for s.HasNext() {
+				a, err := s.GetInt32("a")
+				require.NoError(t, err)
+				actual = append(actual, a)
+			}
+			require.NoError(t, s.Err())
+			s.Close()
+
+			err = txn.Commit()
+			require.NoError(t, err)
+
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestExecutor_select_expression(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 8
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	ctrl := gomock.NewController(t)
+	idxDriver := domain.NewIndexDriver(mock.NewMockIndexFactory(ctrl), mock.NewMockSearchCostCalculator(ctrl))
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	qp := plan.NewBasicQueryPlanner(mmgr)
+	ue := plan.NewBasicUpdatePlanner(mmgr)
+	pe := plan.NewExecutor(qp, ue)
+
+	txn := cr.NewTxn()
+	_, err = pe.ExecuteUpdate("create table T1(A int, B varchar(9))", txn)
+	require.NoError(t, err)
+
+	n := 5
+	for i := 0; i < n; i++ {
+		cmd := fmt.Sprintf("insert into T1(A, B) values (%v, 'rec%v')", i, i)
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+
+	_, err = pe.ExecuteUpdate("update T1 set A = A * 10 + 1 where A < 2", txn)
+	require.NoError(t, err)
+
+	_, err = pe.ExecuteUpdate("update T1 set A = B", txn)
+	require.ErrorIs(t, err, domain.ErrTypeMismatch)
+
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	txn = cr.NewTxn()
+	p, err := pe.CreateQueryPlan("select A, A * 2 as dbl, B || '!' as msg, -A from T1 order by dbl desc", txn)
+	require.NoError(t, err)
+	require.Equal(t, []domain.FieldName{"a", "dbl", "msg", "-(a)"}, p.Schema().Fields())
+
+	s, err := p.Open()
+	require.NoError(t, err)
+
+	dbls := make([]int32, 0)
+	msgs := make([]string, 0)
+	for s.HasNext() {
+		a, err := s.GetInt32("a")
+		require.NoError(t, err)
+		dbl, err := s.GetInt32("dbl")
+		require.NoError(t, err)
+		msg, err := s.GetString("msg")
+		require.NoError(t, err)
+		neg, err := s.GetInt32("-(a)")
+		require.NoError(t, err)
+		require.Equal(t, -a, neg)
+
+		dbls = append(dbls, dbl)
+		msgs = append(msgs, msg)
+	}
+	require.NoError(t, s.Err())
+	s.Close()
+
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	require.Equal(t, []int32{22, 8, 6, 4, 2}, dbls)
+	require.Equal(t, []string{"rec1!", "rec4!", "rec3!", "rec2!", "rec0!"}, msgs)
+
+	t.Run("errors in where clause", func(t *testing.T) {
+		txn := cr.NewTxn()
+		defer txn.Rollback()
+
+		_, err := pe.ExecuteUpdate("delete from T1 where A / 0 = 1", txn)
+		require.ErrorIs(t, err, domain.ErrDivisionByZero)
+
+		_, err = pe.ExecuteUpdate("update T1 set A = 1 where A + B = 1", txn)
+		require.ErrorIs(t, err, domain.ErrTypeMismatch)
+
+		_, err = pe.CreateQueryPlan("select A from T1 where A + B = 1", txn)
+		require.ErrorIs(t, err, domain.ErrTypeMismatch)
+
+		p, err := pe.CreateQueryPlan("select A from T1 where 10 / (A - 2) > 1", txn)
+		require.NoError(t, err)
+		s, err := p.Open()
+		require.NoError(t, err)
+		defer s.Close()
+		for s.HasNext() {
+		}
+		require.ErrorIs(t, s.Err(), domain.ErrDivisionByZero)
+	})
+}
+
+func TestExecutor_select_join(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 8
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	ctrl := gomock.NewController(t)
+	idxDriver := domain.NewIndexDriver(mock.NewMockIndexFactory(ctrl), mock.NewMockSearchCostCalculator(ctrl))
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	ue := plan.NewBasicUpdatePlanner(mmgr)
+	pe := plan.NewExecutor(plan.NewBasicQueryPlanner(mmgr), ue)
+
+	txn := cr.NewTxn()
+	cmds := []string{
+		"create table T1(A int, B varchar(9))",
+		"create table T2(C int, D varchar(9))",
+		"insert into T1(A, B) values (1, 'a1')",
+		"insert into T1(A, B) values (2, 'a2')",
+		"insert into T1(A, B) values (3, 'a3')",
+		"insert into T2(C, D) values (2, 'c2')",
+		"insert into T2(C, D) values (3, 'c3')",
+		"insert into T2(C, D) values (3, 'c3x')",
+		"insert into T2(C, D) values (4, 'c4')",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	null := domain.NewNullConstant()
+	str := func(s string) domain.Constant {
+		return domain.NewConstant(domain.StringFieldType, s)
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected [][]domain.Constant
+	}{
+		{
+			name:     "inner join",
+			query:    "select B, D from T1 join T2 on A = C",
+			expected: [][]domain.Constant{{str("a2"), str("c2")}, {str("a3"), str("c3")}, {str("a3"), str("c3x")}},
+		},
+		{
+			name:     "inner join with where",
+			query:    "select B, D from T1 inner join T2 on A = C where D <> 'c3'",
+			expected: [][]domain.Constant{{str("a2"), str("c2")}, {str("a3"), str("c3x")}},
+		},
+		{
+			name:  "left join",
+			query: "select B, D from T1 left outer join T2 on A = C",
+			expected: [][]domain.Constant{
+				{str("a1"), null}, {str("a2"), str("c2")}, {str("a3"), str("c3")}, {str("a3"), str("c3x")},
+			},
+		},
+		{
+			name:     "left join with condition on inner side",
+			query:    "select B, D from T1 left join T2 on A = C and D = 'c3x'",
+			expected: [][]domain.Constant{{str("a1"), null}, {str("a2"), null}, {str("a3"), str("c3x")}},
+		},
+		{
+			name:  "right join",
+			query: "select B, D from T1 right join T2 on A = C",
+			expected: [][]domain.Constant{
+				{str("a2"), str("c2")}, {str("a3"), str("c3")}, {str("a3"), str("c3x")}, {null, str("c4")},
+			},
+		},
+		{
+			name:     "right join with where",
+			query:    "select B, D from T1 right join T2 on A = C where D <> 'c3'",
+			expected: [][]domain.Constant{{str("a2"), str("c2")}, {str("a3"), str("c3x")}, {null, str("c4")}},
+		},
+		{
+			name:     "where on null padded field",
+			query:    "select B from T1 left join T2 on A = C where C > 0",
+			expected: [][]domain.Constant{{str("a2")}, {str("a3")}, {str("a3")}},
+		},
+		{
+			name:  "aggregate ignores null",
+			query: "select count(D), count(*), max(C) from T1 left join T2 on A = C",
+			expected: [][]domain.Constant{{
+				domain.NewConstant(domain.Int32FieldType, int32(3)),
+				domain.NewConstant(domain.Int32FieldType, int32(4)),
+				domain.NewConstant(domain.Int32FieldType, int32(3)),
+			}},
+		},
+	}
+
+	planners := map[string]domain.QueryPlanner{
+		"basic":     plan.NewBasicQueryPlanner(mmgr),
+		"better":    plan.NewBetterQueryPlanner(mmgr),
+		"heuristic": plan.NewHeuristicQueryPlanner(mmgr),
+	}
+
+	for name, qp := range planners {
+		pe := plan.NewExecutor(qp, ue)
+		for _, tt := range tests {
+			tt := tt
+			t.Run(name+" "+tt.name, func(t *testing.T) {
+				txn := cr.NewTxn()
+				p, err := pe.CreateQueryPlan(tt.query, txn)
+				require.NoError(t, err)
+				s, err := p.Open()
+				require.NoError(t, err)
+
+				actual := make([][]domain.Constant, 0)
+				for s.HasNext() {
+					row := make([]domain.Constant, 0)
+					for _, fld := range p.Schema().Fields() {
+						val, err := s.GetVal(fld)
+						require.NoError(t, err)
+						row = append(row, val)
+					}
+					actual = append(actual, row)
+				}
+				require.NoError(t, s.Err())
+				s.Close()
+
+				err = txn.Commit()
+				require.NoError(t, err)
+
+				require.Equal(t, tt.expected, actual)
+			})
+		}
+	}
+}
+
+func TestHashJoinPlan(t *testing.T) {
+	const blockSize = 400
+
+	tests := []struct {
+		name        string
+		numBuf      int
+		partitioned bool
+	}{
+		{name: "in memory", numBuf: 20, partitioned: false},
+		{name: "partitioned", numBuf: 4, partitioned: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			cr := fake.NewTransactionCreater(blockSize, tt.numBuf)
+			defer cr.Finish()
+
+			ctrl := gomock.NewController(t)
+			idxDriver := domain.NewIndexDriver(mock.NewMockIndexFactory(ctrl), mock.NewMockSearchCostCalculator(ctrl))
+			mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+			require.NoError(t, err)
+
+			pe := plan.NewExecutor(plan.NewBasicQueryPlanner(mmgr), plan.NewBasicUpdatePlanner(mmgr))
+
+			txn := cr.NewTxn()
+			_, err = pe.ExecuteUpdate("create table T1(A int, B varchar(9))", txn)
+			require.NoError(t, err)
+			_, err = pe.ExecuteUpdate("create table T2(C int, D varchar(9))", txn)
+			require.NoError(t, err)
+
+			for i := 0; i < 100; i++ {
+				_, err := pe.ExecuteUpdate(fmt.Sprintf("insert into T1(A, B) values (%v, 'a%v')", i, i), txn)
+				require.NoError(t, err)
+			}
+			// C = 0..49 がそれぞれ 3 回ずつ現れる.
+			for i := 0; i < 150; i++ {
+				_, err := pe.ExecuteUpdate(fmt.Sprintf("insert into T2(C, D) values (%v, 'd%v')", i%50, i), txn)
+				require.NoError(t, err)
+			}
+			err = txn.Commit()
+			require.NoError(t, err)
+
+			txn = cr.NewTxn()
+			lhs, err := plan.NewTablePlan(txn, "t1", mmgr)
+			require.NoError(t, err)
+			rhs, err := plan.NewTablePlan(txn, "t2", mmgr)
+			require.NoError(t, err)
+
+			p := plan.NewHashJoinPlan(txn, lhs, rhs, "a", "c")
+			blocks := lhs.EstNumBlocks() + rhs.EstNumBlocks()
+			if tt.partitioned {
+				require.Equal(t, 3*blocks, p.EstNumBlocks())
+			} else {
+				require.Equal(t, blocks, p.EstNumBlocks())
+			}
+
+			s, err := p.Open()
+			require.NoError(t, err)
+
+			actual := make(map[string]int)
+			for s.HasNext() {
+				a, err := s.GetInt32("a")
+				require.NoError(t, err)
+				c, err := s.GetInt32("c")
+				require.NoError(t, err)
+				require.Equal(t, a, c)
+				b, err := s.GetString("b")
+				require.NoError(t, err)
+				d, err := s.GetString("d")
+				require.NoError(t, err)
+				actual[b+","+d]++
+			}
+			require.NoError(t, s.Err())
+			s.Close()
+
+			err = txn.Commit()
+			require.NoError(t, err)
+
+			expected := make(map[string]int)
+			for i := 0; i < 150; i++ {
+				expected[fmt.Sprintf("a%v,d%v", i%50, i)]++
+			}
+			require.Equal(t, expected, actual)
+			require.Empty(t, tempFiles(t, cr.DBPath()))
+		})
+	}
+
+	t.Run("equal values of different representations", func(t *testing.T) {
+		cr := fake.NewTransactionCreater(blockSize, 20)
+		defer cr.Finish()
+
+		ctrl := gomock.NewController(t)
+		idxDriver := domain.NewIndexDriver(mock.NewMockIndexFactory(ctrl), mock.NewMockSearchCostCalculator(ctrl))
+		mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+		require.NoError(t, err)
+
+		pe := plan.NewExecutor(plan.NewBasicQueryPlanner(mmgr), plan.NewBasicUpdatePlanner(mmgr))
+
+		txn := cr.NewTxn()
+		cmds := []string{
+			"create table T1(A interval, B int)",
+			"create table T2(C interval, D bigint)",
+			"insert into T1(A, B) values (interval '1 day', 1)",
+			"insert into T1(A, B) values (interval '2 days', 2)",
+			"insert into T2(C, D) values (interval '24 hours', 1)",
+			"insert into T2(C, D) values (interval '1 day', 2)",
+		}
+		for _, cmd := range cmds {
+			_, err := pe.ExecuteUpdate(cmd, txn)
+			require.NoError(t, err)
+		}
+
+		count := func(lhsFld, rhsFld domain.FieldName) int {
+			lhs, err := plan.NewTablePlan(txn, "t1", mmgr)
+			require.NoError(t, err)
+			rhs, err := plan.NewTablePlan(txn, "t2", mmgr)
+			require.NoError(t, err)
+
+			s, err := plan.NewHashJoinPlan(txn, lhs, rhs, lhsFld, rhsFld).Open()
+			require.NoError(t, err)
+			defer s.Close()
+
+			n := 0
+			for s.HasNext() {
+				n++
+			}
+			require.NoError(t, s.Err())
+
+			return n
+		}
+
+		require.Equal(t, 2, count("a", "c"))
+		require.Equal(t, 2, count("b", "d"))
+		require.NoError(t, txn.Commit())
+	})
+}
+
+func TestMergeJoinPlan(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 8
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	ctrl := gomock.NewController(t)
+	idxDriver := domain.NewIndexDriver(mock.NewMockIndexFactory(ctrl), mock.NewMockSearchCostCalculator(ctrl))
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewBetterQueryPlanner(mmgr), plan.NewBasicUpdatePlanner(mmgr))
+
+	txn := cr.NewTxn()
+	_, err = pe.ExecuteUpdate("create table T1(A int, B varchar(9))", txn)
+	require.NoError(t, err)
+	_, err = pe.ExecuteUpdate("create table T2(C int, D varchar(9))", txn)
+	require.NoError(t, err)
+
+	// 両方の join field に重複がある. 逆順に insert して sort が必要な状態にする.
+	for i := 89; i >= 0; i-- {
+		_, err := pe.ExecuteUpdate(fmt.Sprintf("insert into T1(A, B) values (%v, 'b%v')", i%30, i), txn)
+		require.NoError(t, err)
+	}
+	for i := 149; i >= 0; i-- {
+		_, err := pe.ExecuteUpdate(fmt.Sprintf("insert into T2(C, D) values (%v, 'd%v')", i%50, i), txn)
+		require.NoError(t, err)
+	}
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	expected := make(map[string]int)
+	for i := 0; i < 90; i++ {
+		for j := 0; j < 150; j++ {
+			if i%30 == j%50 {
+				expected[fmt.Sprintf("b%v,d%v", i, j)]++
+			}
+		}
+	}
+
+	collect := func(t *testing.T, s domain.Scanner) map[string]int {
+		t.Helper()
+
+		actual := make(map[string]int)
+		for s.HasNext() {
+			a, err := s.GetInt32("a")
+			require.NoError(t, err)
+			c, err := s.GetInt32("c")
+			require.NoError(t, err)
+			require.Equal(t, a, c)
+			b, err := s.GetString("b")
+			require.NoError(t, err)
+			d, err := s.GetString("d")
+			require.NoError(t, err)
+			actual[b+","+d]++
+		}
+		require.NoError(t, s.Err())
+
+		return actual
+	}
+
+	t.Run("merge join plan", func(t *testing.T) {
+		txn := cr.NewTxn()
+		lhs, err := plan.NewTablePlan(txn, "t1", mmgr)
+		require.NoError(t, err)
+		rhs, err := plan.NewTablePlan(txn, "t2", mmgr)
+		require.NoError(t, err)
+
+		p := plan.NewMergeJoinPlan(txn, lhs, rhs, "a", "c")
+		s, err := p.Open()
+		require.NoError(t, err)
+
+		actual := collect(t, s)
+		require.NoError(t, s.BeforeFirst())
+		require.Equal(t, actual, collect(t, s))
+		s.Close()
+
+		err = txn.Commit()
+		require.NoError(t, err)
+
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("join planner", func(t *testing.T) {
+		txn := cr.NewTxn()
+		p, err := pe.CreateQueryPlan("select A, B, C, D from T1 join T2 on A = C", txn)
+		require.NoError(t, err)
+		s, err := p.Open()
+		require.NoError(t, err)
+
+		actual := collect(t, s)
+		s.Close()
+
+		err = txn.Commit()
+		require.NoError(t, err)
+
+		require.Equal(t, expected, actual)
+	})
+}
+
+func TestIndexJoinPlan(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 10
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(hash.NewIndexFactory(), hash.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewBetterQueryPlanner(mmgr), plan.NewBasicUpdatePlanner(mmgr))
+
+	txn := cr.NewTxn()
+	_, err = pe.ExecuteUpdate("create table T1(A int, B varchar(9))", txn)
+	require.NoError(t, err)
+	_, err = pe.ExecuteUpdate("create table T2(C int, D varchar(9))", txn)
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		_, err := pe.ExecuteUpdate(fmt.Sprintf("insert into T1(A, B) values (%v, 'b%v')", i*10, i), txn)
+		require.NoError(t, err)
+	}
+	for i := 0; i < 200; i++ {
+		_, err := pe.ExecuteUpdate(fmt.Sprintf("insert into T2(C, D) values (%v, 'd%v')", i%100, i), txn)
+		require.NoError(t, err)
+	}
+
+	_, err = pe.ExecuteUpdate("create index idx_c on T2(C)", txn)
+	require.NoError(t, err)
+
+	// 既存の record を index に登録する.
+	idxInfos, err := mmgr.GetIndexInfo("t2", txn)
+	require.NoError(t, err)
+	idx := indexOn(t, idxInfos, "c").Open()
+	tp, err := plan.NewTablePlan(txn, "t2", mmgr)
+	require.NoError(t, err)
+	ts, err := tp.Open()
+	require.NoError(t, err)
+	for ts.HasNext() {
+		val, err := ts.GetVal("c")
+		require.NoError(t, err)
+		err = idx.Insert(val, ts.(*domain.TableScan).RecordID())
+		require.NoError(t, err)
+	}
+	require.NoError(t, ts.Err())
+	ts.Close()
+	idx.Close()
+
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	expected := map[string]int{
+		"b0,d0": 1, "b0,d100": 1,
+		"b1,d10": 1, "b1,d110": 1,
+		"b2,d20": 1, "b2,d120": 1,
+		"b3,d30": 1, "b3,d130": 1,
+		"b4,d40": 1, "b4,d140": 1,
+	}
+
+	collect := func(t *testing.T, s domain.Scanner) map[string]int {
+		t.Helper()
+
+		actual := make(map[string]int)
+		for s.HasNext() {
+			b, err := s.GetString("b")
+			require.NoError(t, err)
+			d, err := s.GetString("d")
+			require.NoError(t, err)
+			actual[b+","+d]++
+		}
+		require.NoError(t, s.Err())
+
+		return actual
+	}
+
+	t.Run("index join plan", func(t *testing.T) {
+		txn := cr.NewTxn()
+		lhs, err := plan.NewTablePlan(txn, "t1", mmgr)
+		require.NoError(t, err)
+		rhs, err := plan.NewTablePlan(txn, "t2", mmgr)
+		require.NoError(t, err)
+		idxInfos, err := mmgr.GetIndexInfo("t2", txn)
+		require.NoError(t, err)
+
+		p := plan.NewIndexJoinPlan(lhs, rhs, indexOn(t, idxInfos, "c"), "a")
+		s, err := p.Open()
+		require.NoError(t, err)
+
+		actual := collect(t, s)
+		s.Close()
+
+		err = txn.Commit()
+		require.NoError(t, err)
+
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("join planner", func(t *testing.T) {
+		txn := cr.NewTxn()
+		p, err := pe.CreateQueryPlan("select B, D from T1, T2 where A = C", txn)
+		require.NoError(t, err)
+		s, err := p.Open()
+		require.NoError(t, err)
+
+		actual := collect(t, s)
+		s.Close()
+
+		err = txn.Commit()
+		require.NoError(t, err)
+
+		require.Equal(t, expected, actual)
+	})
+}
+
+func TestHeuristicQueryPlanner(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 10
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(hash.NewIndexFactory(), hash.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	ue := plan.NewBasicUpdatePlanner(mmgr)
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), ue)
+	basic := plan.NewExecutor(plan.NewBasicQueryPlanner(mmgr), ue)
+
+	txn := cr.NewTxn()
+	cmds := []string{
+		"create table T1(A int, B varchar(9))",
+		"create table T2(C int, D varchar(9))",
+		"create table T3(E int, F varchar(9))",
+		"create view V1 as select C, D from T2 where C < 3",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	for i := 0; i < 5; i++ {
+		_, err := pe.ExecuteUpdate(fmt.Sprintf("insert into T1(A, B) values (%v, 'b%v')", i, i), txn)
+		require.NoError(t, err)
+	}
+	for i := 0; i < 100; i++ {
+		_, err := pe.ExecuteUpdate(fmt.Sprintf("insert into T2(C, D) values (%v, 'd%v')", i%50, i), txn)
+		require.NoError(t, err)
+	}
+	for i := 0; i < 10; i++ {
+		_, err := pe.ExecuteUpdate(fmt.Sprintf("insert into T3(E, F) values (%v, 'f%v')", i%3, i), txn)
+		require.NoError(t, err)
+	}
+
+	_, err = pe.ExecuteUpdate("create index idx_c on T2(C)", txn)
+	require.NoError(t, err)
+
+	// 既存の record を index に登録する.
+	idxInfos, err := mmgr.GetIndexInfo("t2", txn)
+	require.NoError(t, err)
+	idx := indexOn(t, idxInfos, "c").Open()
+	tp, err := plan.NewTablePlan(txn, "t2", mmgr)
+	require.NoError(t, err)
+	ts, err := tp.Open()
+	require.NoError(t, err)
+	for ts.HasNext() {
+		val, err := ts.GetVal("c")
+		require.NoError(t, err)
+		err = idx.Insert(val, ts.(*domain.TableScan).RecordID())
+		require.NoError(t, err)
+	}
+	require.NoError(t, ts.Err())
+	ts.Close()
+	idx.Close()
+
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	collect := func(t *testing.T, p domain.Planner) map[string]int {
+		t.Helper()
+
+		s, err := p.Open()
+		require.NoError(t, err)
+		defer s.Close()
+
+		actual := make(map[string]int)
+		for s.HasNext() {
+			row := ""
+			for _, fld := range p.Schema().Fields() {
+				val, err := s.GetVal(fld)
+				require.NoError(t, err)
+				row += val.String() + ","
+			}
+			actual[row]++
+		}
+		require.NoError(t, s.Err())
+
+		return actual
+	}
+
+	t.Run("index select", func(t *testing.T) {
+		txn := cr.NewTxn()
+		p, err := pe.CreateQueryPlan("select D from T2 where C = 7 and D <> 'd57'", txn)
+		require.NoError(t, err)
+
+		tp, err := plan.NewTablePlan(txn, "t2", mmgr)
+		require.NoError(t, err)
+		require.Less(t, p.EstNumBlocks(), tp.EstNumBlocks())
+
+		require.Equal(t, map[string]int{"d7,": 1}, collect(t, p))
+
+		err = txn.Commit()
+		require.NoError(t, err)
+	})
+
+	queries := []string{
+		"select B, D from T1, T2 where A = C",
+		"select B, D, F from T3, T2, T1 where A = C and C = E and B <> 'b1'",
+		"select B, F from T1, T3 where A > 2",
+		"select B, D from T1, V1 where A = C",
+		"select B, D from T1, T2 where A = C and C = 2",
+		"select B, D, F from T1, T2 join T3 on C = E where A = C",
+		"select B, D from T1 right join T2 on A = C where D <> 'd3'",
+	}
+	for _, query := range queries {
+		query := query
+		t.Run(query, func(t *testing.T) {
+			txn := cr.NewTxn()
+			p, err := pe.CreateQueryPlan(query, txn)
+			require.NoError(t, err)
+			expected, err := basic.CreateQueryPlan(query, txn)
+			require.NoError(t, err)
+
+			require.Equal(t, collect(t, expected), collect(t, p))
+
+			err = txn.Commit()
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestIndexUpdatePlanner(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 10
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(hash.NewIndexFactory(), hash.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewBasicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr))
+
+	txn := cr.NewTxn()
+	_, err = pe.ExecuteUpdate("create table T1(A int, B varchar(9))", txn)
+	require.NoError(t, err)
+	for i := 0; i < 20; i++ {
+		_, err := pe.ExecuteUpdate(fmt.Sprintf("insert into T1(A, B) values (%v, 'b%v')", i%5, i), txn)
+		require.NoError(t, err)
+	}
+
+	// 既存の record は create index 時に登録される.
+	_, err = pe.ExecuteUpdate("create index idx_a on T1(A)", txn)
+	require.NoError(t, err)
+	_, err = pe.ExecuteUpdate("create index idx_b on T1(B)", txn)
+	require.NoError(t, err)
+
+	cmds := []string{
+		"insert into T1(A, B) values (3, 'x')",
+		"insert into T1(B) values ('y')",
+		"delete from T1 where A = 1",
+		"update T1 set A = 10 where B = 'b2'",
+		"update T1 set B = 'z' where A = 4",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	// index から引いた record を数える.
+	lookup := func(t *testing.T, txn domain.Transaction, fld domain.FieldName, val domain.Constant) []string {
+		t.Helper()
+
+		tp, err := plan.NewTablePlan(txn, "t1", mmgr)
+		require.NoError(t, err)
+		idxInfos, err := mmgr.GetIndexInfo("t1", txn)
+		require.NoError(t, err)
+
+		s, err := plan.NewIndexSelectPlan(tp, indexOn(t, idxInfos, fld), val).Open()
+		require.NoError(t, err)
+		defer s.Close()
+
+		actual := make([]string, 0)
+		for s.HasNext() {
+			b, err := s.GetString("b")
+			require.NoError(t, err)
+			actual = append(actual, b)
+		}
+		require.NoError(t, s.Err())
+
+		return actual
+	}
+
+	intVal := func(v int32) domain.Constant {
+		return domain.NewConstant(domain.Int32FieldType, v)
+	}
+	strVal := func(v string) domain.Constant {
+		return domain.NewConstant(domain.StringFieldType, v)
+	}
+
+	tests := []struct {
+		name     string
+		fld      domain.FieldName
+		val      domain.Constant
+		expected []string
+	}{
+		{name: "backfilled", fld: "b", val: strVal("b8"), expected: []string{"b8"}},
+		{name: "inserted", fld: "a", val: intVal(3), expected: []string{"b3", "b8", "b13", "b18", "x"}},
+		{name: "inserted without value", fld: "a", val: intVal(0), expected: []string{"b0", "b5", "b10", "b15"}},
+		{name: "deleted", fld: "a", val: intVal(1), expected: []string{}},
+		{name: "deleted from other index", fld: "b", val: strVal("b1"), expected: []string{}},
+		{name: "updated old value", fld: "a", val: intVal(2), expected: []string{"b7", "b12", "b17"}},
+		{name: "updated new value", fld: "a", val: intVal(10), expected: []string{"b2"}},
+		{name: "updated string", fld: "b", val: strVal("z"), expected: []string{"z", "z", "z", "z"}},
+		{name: "updated string old value", fld: "b", val: strVal("b4"), expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			actual := lookup(t, txn, tt.fld, tt.val)
+			err := txn.Commit()
+			require.NoError(t, err)
+
+			require.ElementsMatch(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestIndexRangePlan(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 20
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	ue := plan.NewIndexUpdatePlanner(mmgr)
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), ue)
+	basic := plan.NewExecutor(plan.NewBasicQueryPlanner(mmgr), ue)
+
+	txn := cr.NewTxn()
+	_, err = pe.ExecuteUpdate("create table T1(A int, B varchar(9))", txn)
+	require.NoError(t, err)
+	for i := 0; i < 300; i++ {
+		_, err := pe.ExecuteUpdate(fmt.Sprintf("insert into T1(A, B) values (%v, 'b%v')", i*7%100, i%4), txn)
+		require.NoError(t, err)
+	}
+	_, err = pe.ExecuteUpdate("create index idx_a on T1(A)", txn)
+	require.NoError(t, err)
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	rows := func(t *testing.T, p domain.Planner) []string {
+		t.Helper()
+
+		s, err := p.Open()
+		require.NoError(t, err)
+		defer s.Close()
+
+		actual := make([]string, 0)
+		for s.HasNext() {
+			row := ""
+			for _, fld := range p.Schema().Fields() {
+				val, err := s.GetVal(fld)
+				require.NoError(t, err)
+				row += val.String() + ","
+			}
+			actual = append(actual, row)
+		}
+		require.NoError(t, s.Err())
+
+		return actual
+	}
+
+	t.Run("range scan", func(t *testing.T) {
+		txn := cr.NewTxn()
+		r := domain.NewIndexRange().
+			WithLower(domain.NewConstant(domain.Int32FieldType, int32(10)), true).
+			WithUpper(domain.NewConstant(domain.Int32FieldType, int32(20)), false)
+		tp, err := plan.NewTablePlan(txn, "t1", mmgr)
+		require.NoError(t, err)
+		idxInfos, err := mmgr.GetIndexInfo("t1", txn)
+		require.NoError(t, err)
+
+		p := plan.NewProjectPlan(plan.NewIndexRangePlan(tp, indexOn(t, idxInfos, "a"), r.WithOrder(domain.Desc)), []domain.FieldName{"a"})
+		actual := rows(t, p)
+		require.Equal(t, 300/9, p.EstNumRecord())
+
+		expected := make([]string, 0)
+		for a := 19; a >= 10; a-- {
+			for i := 0; i < 3; i++ {
+				expected = append(expected, fmt.Sprintf("%v,", a))
+			}
+		}
+		require.Equal(t, expected, actual)
+
+		err = txn.Commit()
+		require.NoError(t, err)
+	})
+
+	column := func(t *testing.T, p domain.Planner, fld domain.FieldName) []domain.Constant {
+		t.Helper()
+
+		s, err := p.Open()
+		require.NoError(t, err)
+		defer s.Close()
+
+		vals := make([]domain.Constant, 0)
+		for s.HasNext() {
+			val, err := s.GetVal(fld)
+			require.NoError(t, err)
+			vals = append(vals, val)
+		}
+		require.NoError(t, s.Err())
+
+		return vals
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		orderBy   domain.FieldName
+		rangeScan bool
+	}{
+		{name: "range", query: "select A, B from T1 where A >= 10 and A < 20 and B <> 'b1'", rangeScan: true},
+		{name: "range with constant on lhs", query: "select A from T1 where 95 < A and 98 >= A", rangeScan: true},
+		{name: "one-sided range", query: "select A from T1 where A > 95", rangeScan: false},
+		{name: "order by", query: "select A from T1 order by A", orderBy: "a", rangeScan: true},
+		{name: "order by desc", query: "select B, A from T1 where A <= 30 order by A desc", orderBy: "a", rangeScan: true},
+		{name: "order by hidden field", query: "select B from T1 where A > 90 order by A", rangeScan: true},
+		{name: "order by computed field", query: "select A + 1 as A2 from T1 order by A2", orderBy: "a2", rangeScan: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			p, err := pe.CreateQueryPlan(tt.query, txn)
+			require.NoError(t, err)
+			expected, err := basic.CreateQueryPlan(tt.query, txn)
+			require.NoError(t, err)
+
+			_, isRangeScan := scanPlan(p).(*plan.IndexRangePlan)
+			require.Equal(t, tt.rangeScan, isRangeScan)
+
+			require.ElementsMatch(t, rows(t, expected), rows(t, p))
+
+			// 同じ key を持つ record の順序は plan によって異なるので key の列だけ比較する.
+			if tt.orderBy != "" {
+				require.Equal(t, column(t, expected, tt.orderBy), column(t, p, tt.orderBy))
+			}
+
+			err = txn.Commit()
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestExecutor_create_index_using(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 20
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator()).
+		Register(domain.HashIndexType, hash.NewIndexFactory(), hash.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	ue := plan.NewIndexUpdatePlanner(mmgr)
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), ue)
+	basic := plan.NewExecutor(plan.NewBasicQueryPlanner(mmgr), ue)
+
+	txn := cr.NewTxn()
+	_, err = pe.ExecuteUpdate("create table T1(A int, B int)", txn)
+	require.NoError(t, err)
+	for i := 0; i < 100; i++ {
+		_, err := pe.ExecuteUpdate(fmt.Sprintf("insert into T1(A, B) values (%v, %v)", i, i%10), txn)
+		require.NoError(t, err)
+	}
+	_, err = pe.ExecuteUpdate("create index idx_a on T1(A)", txn)
+	require.NoError(t, err)
+	_, err = pe.ExecuteUpdate("create index idx_b on T1(B) using hash", txn)
+	require.NoError(t, err)
+	_, err = pe.ExecuteUpdate("create index idx_c on T1(A) using gist", txn)
+	require.ErrorIs(t, err, domain.ErrUnknownIndexType)
+
+	idxInfos, err := mmgr.GetIndexInfo("t1", txn)
+	require.NoError(t, err)
+	require.Equal(t, domain.BTreeIndexType, indexOn(t, idxInfos, "a").IndexType())
+	require.True(t, indexOn(t, idxInfos, "a").SupportsRange())
+	require.Equal(t, domain.HashIndexType, indexOn(t, idxInfos, "b").IndexType())
+	require.False(t, indexOn(t, idxInfos, "b").SupportsRange())
+
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	rows := func(t *testing.T, p domain.Planner) []string {
+		t.Helper()
+
+		s, err := p.Open()
+		require.NoError(t, err)
+		defer s.Close()
+
+		actual := make([]string, 0)
+		for s.HasNext() {
+			val, err := s.GetVal("a")
+			require.NoError(t, err)
+			actual = append(actual, val.String())
+		}
+		require.NoError(t, s.Err())
+
+		return actual
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected interface{}
+	}{
+		{name: "equality on btree", query: "select A from T1 where A = 42", expected: &plan.IndexSelectPlan{}},
+		{name: "equality on hash", query: "select A from T1 where B = 3", expected: &plan.IndexSelectPlan{}},
+		{name: "range on btree", query: "select A from T1 where A >= 10 and A < 20", expected: &plan.IndexRangePlan{}},
+		{name: "range on hash", query: "select A from T1 where B >= 3 and B < 5", expected: &plan.TablePlan{}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			p, err := pe.CreateQueryPlan(tt.query, txn)
+			require.NoError(t, err)
+			expected, err := basic.CreateQueryPlan(tt.query, txn)
+			require.NoError(t, err)
+
+			require.IsType(t, tt.expected, scanPlan(p))
+			require.ElementsMatch(t, rows(t, expected), rows(t, p))
+
+			err = txn.Commit()
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestExecutor_composite_index(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 20
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator()).
+		Register(domain.HashIndexType, hash.NewIndexFactory(), hash.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	ue := plan.NewIndexUpdatePlanner(mmgr)
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), ue)
+	basic := plan.NewExecutor(plan.NewBasicQueryPlanner(mmgr), ue)
+
+	txn := cr.NewTxn()
+	_, err = pe.ExecuteUpdate("create table T1(Tenant int, ID int, Name varchar(9))", txn)
+	require.NoError(t, err)
+	for i := 0; i < 200; i++ {
+		_, err := pe.ExecuteUpdate(fmt.Sprintf("insert into T1(Tenant, ID, Name) values (%v, %v, 'n%v')", i%5, i*7%40, i%3), txn)
+		require.NoError(t, err)
+	}
+	_, err = pe.ExecuteUpdate("create index idx_tid on T1(Tenant, ID)", txn)
+	require.NoError(t, err)
+	_, err = pe.ExecuteUpdate("create index idx_tn on T1(Tenant, Name) using hash", txn)
+	require.NoError(t, err)
+
+	// 作成後の更新も composite index に反映される.
+	cmds := []string{
+		"insert into T1(Tenant, ID, Name) values (2, 100, 'new')",
+		"delete from T1 where Tenant = 1 and ID = 7",
+		"update T1 set ID = 200 where Tenant = 3 and ID = 3",
+		"update T1 set Name = 'z' where Tenant = 4",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	rows := func(t *testing.T, p domain.Planner) []string {
+		t.Helper()
+
+		s, err := p.Open()
+		require.NoError(t, err)
+		defer s.Close()
+
+		actual := make([]string, 0)
+		for s.HasNext() {
+			row := ""
+			for _, fld := range p.Schema().Fields() {
+				val, err := s.GetVal(fld)
+				require.NoError(t, err)
+				row += val.String() + ","
+			}
+			actual = append(actual, row)
+		}
+		require.NoError(t, s.Err())
+
+		return actual
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected interface{}
+		ordered  bool
+	}{
+		{name: "full key", query: "select Tenant, ID, Name from T1 where ID = 14 and Tenant = 2", expected: &plan.IndexSelectPlan{}},
+		{name: "full key by hash", query: "select Tenant, ID from T1 where Tenant = 4 and Name = 'z'", expected: &plan.IndexSelectPlan{}},
+		{name: "prefix", query: "select Tenant, ID from T1 where Tenant = 3", expected: &plan.IndexRangePlan{}},
+		{name: "prefix and range", query: "select ID from T1 where Tenant = 1 and ID >= 5 and ID < 20", expected: &plan.IndexRangePlan{}},
+		{name: "not prefix", query: "select Tenant from T1 where ID = 14", expected: &plan.TablePlan{}},
+		{name: "order by first field", query: "select Tenant, ID from T1 order by Tenant desc", expected: &plan.IndexRangePlan{}, ordered: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			p, err := pe.CreateQueryPlan(tt.query, txn)
+			require.NoError(t, err)
+			expected, err := basic.CreateQueryPlan(tt.query, txn)
+			require.NoError(t, err)
+
+			require.IsType(t, tt.expected, scanPlan(p))
+			want, got := rows(t, expected), rows(t, p)
+			require.NotEmpty(t, want)
+			require.ElementsMatch(t, want, got)
+
+			// 同じ key を持つ record の順序は plan によって異なるので先頭の列だけ比較する.
+			if tt.ordered {
+				first := func(rows []string) []string {
+					vals := make([]string, 0, len(rows))
+					for _, row := range rows {
+						vals = append(vals, strings.Split(row, ",")[0])
+					}
+
+					return vals
+				}
+				require.Equal(t, first(want), first(got))
+			}
+
+			err = txn.Commit()
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestExecutor_unique_constraint(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 20
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr))
+
+	txn := cr.NewTxn()
+	_, err = pe.ExecuteUpdate("create table T1(ID int primary key, Tenant int, Name varchar(9), unique (Tenant, Name))", txn)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		_, err := pe.ExecuteUpdate(fmt.Sprintf("insert into T1(ID, Tenant, Name) values (%v, %v, 'n%v')", i, i%2, i), txn)
+		require.NoError(t, err)
+	}
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	txn = cr.NewTxn()
+	cons, err := mmgr.GetConstraints("t1", txn)
+	require.NoError(t, err)
+	require.Equal(t, []domain.Constraint{
+		domain.NewConstraint(domain.PrimaryKeyConstraint, []domain.FieldName{"id"}).WithName("t1_pkey"),
+		domain.NewConstraint(domain.UniqueConstraint, []domain.FieldName{"tenant", "name"}).WithName("t1_tenant_name_key"),
+	}, cons)
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		cmd        string
+		constraint domain.IndexName
+	}{
+		{name: "insert duplicate primary key", cmd: "insert into T1(ID, Tenant, Name) values (3, 5, 'x')", constraint: "t1_pkey"},
+		{name: "insert duplicate composite key", cmd: "insert into T1(ID, Tenant, Name) values (20, 1, 'n3')", constraint: "t1_tenant_name_key"},
+		{name: "update to duplicate primary key", cmd: "update T1 set ID = 4 where ID = 2", constraint: "t1_pkey"},
+		{name: "update to duplicate composite key", cmd: "update T1 set Name = 'n0' where ID = 2", constraint: "t1_tenant_name_key"},
+	}
+
+	// 違反した更新は書き込まれないので, そのまま commit しても table は変わらない.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			defer txn.Commit()
+
+			_, err := pe.ExecuteUpdate(tt.cmd, txn)
+			require.Error(t, err)
+
+			var uerr *domain.UniqueViolationError
+			require.True(t, errors.As(err, &uerr))
+			require.Equal(t, tt.constraint, uerr.Constraint().Name())
+		})
+	}
+
+	txn = cr.NewTxn()
+	p, err := pe.CreateQueryPlan("select ID, Name from T1 where Tenant = 0", txn)
+	require.NoError(t, err)
+	s, err := p.Open()
+	require.NoError(t, err)
+	actual := make([]string, 0)
+	for s.HasNext() {
+		id, err := s.GetVal("id")
+		require.NoError(t, err)
+		name, err := s.GetVal("name")
+		require.NoError(t, err)
+		actual = append(actual, id.String()+","+name.String())
+	}
+	require.NoError(t, s.Err())
+	s.Close()
+	require.ElementsMatch(t, []string{"0,n0", "2,n2", "4,n4", "6,n6", "8,n8"}, actual)
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	// 違反しない更新は通り, 失敗した insert の行は残らない.
+	txn = cr.NewTxn()
+	cmds := []string{
+		"insert into T1(ID, Tenant, Name) values (20, 0, 'n3')",
+		"update T1 set ID = 30 where ID = 2",
+		"update T1 set Name = 'm4' where ID = 4",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	_, err = pe.ExecuteUpdate("insert into T1(ID, Tenant, Name) values (30, 9, 'x')", txn)
+	require.Error(t, err)
+
+	p, err = pe.CreateQueryPlan("select ID from T1 where Tenant = 9", txn)
+	require.NoError(t, err)
+	s, err = p.Open()
+	require.NoError(t, err)
+	require.False(t, s.HasNext())
+	require.NoError(t, s.Err())
+	s.Close()
+	err = txn.Commit()
+	require.NoError(t, err)
+}
+
+func TestExecutor_null(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 20
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr))
+
+	txn := cr.NewTxn()
+	cmds := []string{
+		"create table T1(A int, B varchar(9))",
+		"create index idx_a on T1(A)",
+		"insert into T1(A, B) values (1, 'b1')",
+		"insert into T1(A, B) values (2, null)",
+		"insert into T1(B) values ('b3')",
+		"insert into T1(A, B) values (4, 'b4')",
+		"insert into T1(A, B) values (null, 'b5')",
+		"update T1 set A = null where B = 'b4'",
+		"update T1 set B = null where A = 1",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{name: "index select", query: "select A, B from T1 where A = 1", expected: []string{"1,null"}},
+		{name: "is null", query: "select A, B from T1 where A is null", expected: []string{"null,b3", "null,b4", "null,b5"}},
+		{name: "is not null", query: "select A from T1 where B is not null", expected: []string{"null", "null", "null"}},
+		{name: "unknown", query: "select A, B from T1 where A < 3 or B = 'b3'", expected: []string{"1,null", "2,null", "null,b3"}},
+		{name: "null is last", query: "select A from T1 order by A", expected: []string{"1", "2", "null", "null", "null"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			defer txn.Commit()
+
+			p, err := pe.CreateQueryPlan(tt.query, txn)
+			require.NoError(t, err)
+			s, err := p.Open()
+			require.NoError(t, err)
+			defer s.Close()
+
+			actual := make([]string, 0)
+			for s.HasNext() {
+				vals := make([]string, 0)
+				for _, fld := range p.Schema().Fields() {
+					val, err := s.GetVal(fld)
+					require.NoError(t, err)
+					vals = append(vals, val.String())
+				}
+				actual = append(actual, strings.Join(vals, ","))
+			}
+			require.NoError(t, s.Err())
+
+			if strings.Contains(tt.query, "order by") {
+				require.Equal(t, tt.expected, actual)
+			} else {
+				require.ElementsMatch(t, tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestExecutor_field_constraints(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 20
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr))
+
+	txn := cr.NewTxn()
+	cmds := []string{
+		"create table T1(ID int primary key, Qty int not null default 1 check (Qty > 0), Note varchar(9) default 'none', Price int check (Price >= 0))",
+		"insert into T1(ID) values (1)",
+		"insert into T1(ID, Qty, Note, Price) values (2, 5, null, 10)",
+		"insert into T1(ID, Price) values (3, 0)",
+		"update T1 set Qty = 7 where ID = 3",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	errTests := []struct {
+		name string
+		cmd  string
+		err  error
+	}{
+		{name: "default type mismatch", cmd: "create table T2(A int default 'x')", err: domain.ErrTypeMismatch},
+		{name: "check unknown field", cmd: "create table T2(A int check (B > 0))", err: domain.ErrFieldNotFound},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			defer txn.Rollback()
+
+			_, err := pe.ExecuteUpdate(tt.cmd, txn)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	tests := []struct {
+		name    string
+		cmd     string
+		fld     domain.FieldName
+		notNull bool
+	}{
+		{name: "insert null into not null", cmd: "insert into T1(ID, Qty) values (4, null)", fld: "qty", notNull: true},
+		{name: "insert without primary key", cmd: "insert into T1(Qty) values (2)", fld: "id", notNull: true},
+		{name: "insert violating check", cmd: "insert into T1(ID, Qty) values (4, 0)", fld: "qty"},
+		{name: "update to null", cmd: "update T1 set Qty = null where ID = 2", fld: "qty", notNull: true},
+		{name: "update violating check", cmd: "update T1 set Price = -1 where ID = 1", fld: "price"},
+	}
+	// 違反した record は書き込まれないので, そのまま commit しても table は変わらない.
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			defer txn.Commit()
+
+			_, err := pe.ExecuteUpdate(tt.cmd, txn)
+			require.Error(t, err)
+
+			if tt.notNull {
+				var nerr *domain.NotNullViolationError
+				require.True(t, errors.As(err, &nerr))
+				require.Equal(t, tt.fld, nerr.FieldName())
+			} else {
+				var cerr *domain.CheckViolationError
+				require.True(t, errors.As(err, &cerr))
+				require.Equal(t, tt.fld, cerr.FieldName())
+			}
+		})
+	}
+
+	// 書き込めない値を含む record は残らない.
+	txn = cr.NewTxn()
+	_, err = pe.ExecuteUpdate("insert into T1(ID, Note) values (5, 'too long note')", txn)
+	require.Error(t, err)
+	_, err = pe.ExecuteUpdate("insert into T1(ID, Qty) values (6, 'x')", txn)
+	require.Error(t, err)
+	require.NoError(t, txn.Commit())
+
+	// 省略した field には default 値が入り, CHECK は NULL を通す.
+	txn = cr.NewTxn()
+	defer txn.Commit()
+
+	p, err := pe.CreateQueryPlan("select ID, Qty, Note, Price from T1", txn)
+	require.NoError(t, err)
+	s, err := p.Open()
+	require.NoError(t, err)
+	defer s.Close()
+
+	actual := make([]string, 0)
+	for s.HasNext() {
+		vals := make([]string, 0)
+		for _, fld := range p.Schema().Fields() {
+			val, err := s.GetVal(fld)
+			require.NoError(t, err)
+			vals = append(vals, val.String())
+		}
+		actual = append(actual, strings.Join(vals, ","))
+	}
+	require.NoError(t, s.Err())
+	require.ElementsMatch(t, []string{"1,1,none,null", "2,5,null,10", "3,7,none,0"}, actual)
+}
+
+func TestExecutor_foreign_key(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 30
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr))
+
+	txn := cr.NewTxn()
+	cmds := []string{
+		"create table P(ID int primary key, Code varchar(4) unique)",
+		"create table C1(ID int primary key, PID int references P on delete cascade)",
+		"create table C2(ID int, Code varchar(4) references P(Code) on delete set null)",
+		"create table C3(ID int, PID int references P(ID))",
+		"create table Tree(ID int primary key, Parent int references Tree on delete cascade)",
+		"insert into P(ID, Code) values (1, 'a')",
+		"insert into P(ID, Code) values (2, 'b')",
+		"insert into P(ID, Code) values (3, 'c')",
+		"insert into P(ID, Code) values (4, 'd')",
+		"insert into C1(ID, PID) values (10, 1)",
+		"insert into C1(ID, PID) values (11, 1)",
+		"insert into C1(ID, PID) values (12, 2)",
+		"insert into C2(ID, Code) values (20, 'a')",
+		"insert into C2(ID, Code) values (21, 'b')",
+		"insert into C3(ID, PID) values (30, 3)",
+		"insert into C3(ID, PID) values (31, null)",
+		"insert into Tree(ID, Parent) values (1, null)",
+		"insert into Tree(ID, Parent) values (2, 1)",
+		"insert into Tree(ID, Parent) values (3, 2)",
+		"insert into Tree(ID, Parent) values (4, 1)",
+		"insert into Tree(ID, Parent) values (5, 5)",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err, cmd)
+	}
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	txn = cr.NewTxn()
+	cons, err := mmgr.GetConstraints("c2", txn)
+	require.NoError(t, err)
+	require.Equal(t, []domain.Constraint{
+		domain.NewForeignKeyConstraint([]domain.FieldName{"code"}, "p", nil, domain.SetNullAction).
+			WithName("c2_code_fkey").
+			WithReference(domain.NewConstraint(domain.UniqueConstraint, []domain.FieldName{"code"}).WithName("p_code_key")),
+	}, cons)
+	tblNames, err := mmgr.GetReferencingTables("p", txn)
+	require.NoError(t, err)
+	require.Equal(t, []domain.TableName{"c1", "c2", "c3"}, tblNames)
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	errTests := []struct {
+		name string
+		cmd  string
+		err  error
+	}{
+		{name: "reference non unique field", cmd: "create table X(A int references C3(ID))", err: domain.ErrNoMatchingUniqueKey},
+		{name: "reference different type", cmd: "create table X(A varchar(4) references P)", err: domain.ErrTypeMismatch},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			defer txn.Rollback()
+
+			_, err := pe.ExecuteUpdate(tt.cmd, txn)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	tests := []struct {
+		name       string
+		cmd        string
+		constraint domain.IndexName
+	}{
+		{name: "insert without parent", cmd: "insert into C1(ID, PID) values (13, 9)", constraint: "c1_pid_fkey"},
+		{name: "update to missing parent", cmd: "update C1 set PID = 9 where ID = 10", constraint: "c1_pid_fkey"},
+		{name: "delete restricted parent", cmd: "delete from P where ID = 3", constraint: "c3_pid_fkey"},
+		{name: "update referenced key", cmd: "update P set ID = 9 where ID = 3", constraint: "c3_pid_fkey"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			defer txn.Rollback()
+
+			_, err := pe.ExecuteUpdate(tt.cmd, txn)
+			require.Error(t, err)
+
+			var ferr *domain.ForeignKeyViolationError
+			require.True(t, errors.As(err, &ferr))
+			require.Equal(t, tt.constraint, ferr.Constraint().Name())
+		})
+	}
+
+	// 参照先の無い値への更新は書き込まれないので, そのまま commit しても table は変わらない.
+	txn = cr.NewTxn()
+	_, err = pe.ExecuteUpdate("update C1 set PID = 9 where ID = 10", txn)
+	var ferr *domain.ForeignKeyViolationError
+	require.ErrorAs(t, err, &ferr)
+	_, err = pe.ExecuteUpdate("update P set ID = 9 where ID = 3", txn)
+	require.ErrorAs(t, err, &ferr)
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	txn = cr.NewTxn()
+	cmds = []string{
+		"insert into C1(ID, PID) values (13, null)",
+		"delete from P where ID = 1",
+		"delete from Tree where ID = 1",
+		"update P set Code = 'z' where ID = 4",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err, cmd)
+	}
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	queryTests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{name: "parent", query: "select ID, Code from P", expected: []string{"2,b", "3,c", "4,z"}},
+		{name: "cascade", query: "select ID, PID from C1", expected: []string{"12,2", "13,null"}},
+		{name: "set null", query: "select ID, Code from C2", expected: []string{"20,null", "21,b"}},
+		{name: "self reference", query: "select ID, Parent from Tree", expected: []string{"5,5"}},
+	}
+	for _, tt := range queryTests {
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			defer txn.Commit()
+
+			p, err := pe.CreateQueryPlan(tt.query, txn)
+			require.NoError(t, err)
+			s, err := p.Open()
+			require.NoError(t, err)
+			defer s.Close()
+
+			actual := make([]string, 0)
+			for s.HasNext() {
+				vals := make([]string, 0)
+				for _, fld := range p.Schema().Fields() {
+					val, err := s.GetVal(fld)
+					require.NoError(t, err)
+					vals = append(vals, val.String())
+				}
+				actual = append(actual, strings.Join(vals, ","))
+			}
+			require.NoError(t, s.Err())
+			require.ElementsMatch(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestExecutor_foreign_key_lock(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 30
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr))
+
+	txn := cr.NewTxn()
+	cmds := []string{
+		"create table P(ID int primary key)",
+		"create table C(ID int, PID int references P)",
+		"insert into P(ID) values (1)",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	// 参照を確認した transaction が終わるまで, 参照先の record は削除できない.
+	txn1 := cr.NewTxn()
+	_, err = pe.ExecuteUpdate("insert into C(ID, PID) values (10, 1)", txn1)
+	require.NoError(t, err)
+
+	txn2 := cr.NewTxn()
+	_, err = pe.ExecuteUpdate("delete from P where ID = 1", txn2)
+	require.ErrorIs(t, err, tx.ErrTransactionTimeoutExceeded)
+	err = txn2.Rollback()
+	require.NoError(t, err)
+
+	err = txn1.Commit()
+	require.NoError(t, err)
+
+	txn3 := cr.NewTxn()
+	defer txn3.Rollback()
+	_, err = pe.ExecuteUpdate("delete from P where ID = 1", txn3)
+	var ferr *domain.ForeignKeyViolationError
+	require.True(t, errors.As(err, &ferr))
+}
+
+func TestExecutor_drop(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 30
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator()).
+		Register(domain.HashIndexType, hash.NewIndexFactory(), hash.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr))
+
+	txn := cr.NewTxn()
+	cmds := []string{
+		"create table T1(ID int primary key, Name varchar(8))",
+		"create index t1_name_idx on T1(Name) using hash",
+		"create table C(ID int, PID int references T1)",
+		"create view V1 as select ID from T1",
+		"create table D(DID int)",
+		"create view VC as select ID from C where PID = 1",
+		"create view VD as select Name from T1 join D on ID = DID",
+		"insert into T1(ID, Name) values (1, 'a')",
+		"insert into T1(ID, Name) values (2, 'b')",
+		"insert into C(ID, PID) values (10, 1)",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	query := func(t *testing.T, q string) []string {
+		txn := cr.NewTxn()
+		defer txn.Commit()
+
+		p, err := pe.CreateQueryPlan(q, txn)
+		require.NoError(t, err)
+		s, err := p.Open()
+		require.NoError(t, err)
+		defer s.Close()
+
+		actual := make([]string, 0)
+		for s.HasNext() {
+			vals := make([]string, 0)
+			for _, fld := range p.Schema().Fields() {
+				val, err := s.GetVal(fld)
+				require.NoError(t, err)
+				vals = append(vals, val.String())
+			}
+			actual = append(actual, strings.Join(vals, ","))
+		}
+		require.NoError(t, s.Err())
+
+		return actual
+	}
+
+	errTests := []struct {
+		name string
+		cmd  string
+		err  error
+	}{
+		{name: "drop unknown table", cmd: "drop table Nope", err: domain.ErrTableNotFound},
+		{name: "drop unknown view", cmd: "drop view Nope", err: domain.ErrViewNotFound},
+		{name: "drop unknown index", cmd: "drop index nope_idx", err: domain.ErrIndexNotFound},
+		{name: "drop referenced table", cmd: "drop table T1", err: domain.ErrDependentObjectsExist},
+		{name: "drop table used by view", cmd: "drop table C", err: domain.ErrDependentObjectsExist},
+		{name: "drop table joined by view", cmd: "drop table D", err: domain.ErrDependentObjectsExist},
+		{name: "create index of existing name", cmd: "create index t1_name_idx on D(DID)", err: domain.ErrIndexExists},
+		{name: "create index of constraint name", cmd: "create index t1_pkey on D(DID)", err: domain.ErrIndexExists},
+		{name: "drop index of constraint", cmd: "drop index t1_pkey", err: domain.ErrDependentObjectsExist},
+		{name: "drop catalog", cmd: "drop table table_catalog", err: domain.ErrDropCatalog},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			defer txn.Rollback()
+
+			_, err := pe.ExecuteUpdate(tt.cmd, txn)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	t.Run("constraint does not take name of index", func(t *testing.T) {
+		txn := cr.NewTxn()
+		defer txn.Rollback()
+
+		cmds := []string{
+			"create index e_id_key on D(DID)",
+			"create table E(ID int unique)",
+			"drop index e_id_key",
+		}
+		for _, cmd := range cmds {
+			_, err := pe.ExecuteUpdate(cmd, txn)
+			require.NoError(t, err)
+		}
+
+		infos, err := mmgr.GetIndexInfo("e", txn)
+		require.NoError(t, err)
+		require.Len(t, infos, 1)
+		require.Equal(t, domain.IndexName("e_id_key1"), infos[0].IndexName())
+		infos, err = mmgr.GetIndexInfo("d", txn)
+		require.NoError(t, err)
+		require.Empty(t, infos)
+	})
+
+	t.Run("if exists", func(t *testing.T) {
+		txn := cr.NewTxn()
+		defer txn.Commit()
+
+		for _, cmd := range []string{"drop table if exists Nope", "drop view if exists Nope", "drop index if exists nope_idx"} {
+			_, err := pe.ExecuteUpdate(cmd, txn)
+			require.NoError(t, err)
+		}
+	})
+
+	drops := []string{
+		"drop view V1",
+		"drop view VC",
+		"drop view VD",
+		"drop index t1_name_idx",
+		"drop table D",
+		"drop table C",
+		"drop table T1",
+	}
+
+	t.Run("rollback keeps the table", func(t *testing.T) {
+		txn := cr.NewTxn()
+		for _, cmd := range drops {
+			_, err := pe.ExecuteUpdate(cmd, txn)
+			require.NoError(t, err)
+		}
+		err := txn.Rollback()
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"b"}, query(t, "select Name from T1 where Name = 'b'"))
+		require.ElementsMatch(t, []string{"1", "2"}, query(t, "select ID from V1"))
+		require.Equal(t, []string{"10,1"}, query(t, "select ID, PID from C"))
+
+		txn = cr.NewTxn()
+		defer txn.Commit()
+		infos, err := mmgr.GetIndexInfo("t1", txn)
+		require.NoError(t, err)
+		require.Len(t, infos, 2)
+	})
+
+	t.Run("commit removes the table", func(t *testing.T) {
+		txn := cr.NewTxn()
+		for _, cmd := range drops {
+			_, err := pe.ExecuteUpdate(cmd, txn)
+			require.NoError(t, err)
+		}
+		err := txn.Commit()
+		require.NoError(t, err)
+
+		txn = cr.NewTxn()
+		_, err = mmgr.GetTableLayout("t1", txn)
+		require.Error(t, err)
+		viewDef, err := mmgr.GetViewDef("v1", txn)
+		require.NoError(t, err)
+		require.Equal(t, domain.ViewDef(""), viewDef)
+		refs, err := mmgr.GetReferencingTables("t1", txn)
+		require.NoError(t, err)
+		require.Empty(t, refs)
+
+		// 同じ名前で作り直した table に古い record や index は残らない.
+		cmds := []string{
+			"create table T1(ID int, Name varchar(8))",
+			"insert into T1(ID, Name) values (3, 'b')",
+		}
+		for _, cmd := range cmds {
+			_, err := pe.ExecuteUpdate(cmd, txn)
+			require.NoError(t, err)
+		}
+		infos, err := mmgr.GetIndexInfo("t1", txn)
+		require.NoError(t, err)
+		require.Empty(t, infos)
+		err = txn.Commit()
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"3,b"}, query(t, "select ID, Name from T1"))
+	})
+}
+
+func TestExecutor_alter_table(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 30
+		numRows   = 30
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator()).
+		Register(domain.HashIndexType, hash.NewIndexFactory(), hash.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr))
+
+	txn := cr.NewTxn()
+	cmds := []string{
+		"create table T1(ID int primary key, Name varchar(8) check (Name <> 'z'))",
+		"create index t1_name_idx on T1(Name) using hash",
+		"create table C(ID int, PID int references T1)",
+		"create table D(A int, B int check (B > A))",
+	}
+	for i := 1; i <= numRows; i++ {
+		cmds = append(cmds, fmt.Sprintf("insert into T1(ID, Name) values (%v, 'n%v')", i, i))
+	}
+	cmds = append(cmds, "insert into C(ID, PID) values (10, 1)")
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	err = txn.Commit()
+	require.NoError(t, err)
+
+	query := func(t *testing.T, q string) []string {
+		txn := cr.NewTxn()
+		defer txn.Commit()
+
+		p, err := pe.CreateQueryPlan(q, txn)
+		require.NoError(t, err)
+		s, err := p.Open()
+		require.NoError(t, err)
+		defer s.Close()
+
+		actual := make([]string, 0)
+		for s.HasNext() {
+			vals := make([]string, 0)
+			for _, fld := range p.Schema().Fields() {
+				val, err := s.GetVal(fld)
+				require.NoError(t, err)
+				vals = append(vals, val.String())
+			}
+			actual = append(actual, strings.Join(vals, ","))
+		}
+		require.NoError(t, s.Err())
+
+		return actual
+	}
+
+	exec := func(t *testing.T, cmds ...string) {
+		txn := cr.NewTxn()
+		for _, cmd := range cmds {
+			_, err := pe.ExecuteUpdate(cmd, txn)
+			require.NoError(t, err)
+		}
+		err := txn.Commit()
+		require.NoError(t, err)
+	}
+
+	// embedded driver と同じく, 失敗した command の後も commit する.
+	execErr := func(t *testing.T, cmd string) error {
+		txn := cr.NewTxn()
+		defer txn.Commit()
+
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.Error(t, err)
+
+		return err
+	}
+
+	errTests := []struct {
+		name string
+		cmd  string
+		err  error
+	}{
+		{name: "add existing field", cmd: "alter table T1 add Name int", err: domain.ErrDuplicateField},
+		{name: "add to unknown table", cmd: "alter table Nope add X int", err: domain.ErrTableNotFound},
+		{name: "drop unknown field", cmd: "alter table T1 drop column Nope", err: domain.ErrFieldNotFound},
+		{name: "drop referenced field", cmd: "alter table T1 drop column ID", err: domain.ErrDependentObjectsExist},
+		{name: "drop field used by check", cmd: "alter table D drop column A", err: domain.ErrDependentObjectsExist},
+		{name: "rename to existing field", cmd: "alter table T1 rename Name to ID", err: domain.ErrDuplicateField},
+		{name: "rename unknown field", cmd: "alter table T1 rename Nope to X", err: domain.ErrFieldNotFound},
+		{name: "alter catalog", cmd: "alter table table_catalog rename to foo", err: domain.ErrAlterCatalog},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, execErr(t, tt.cmd), tt.err)
+		})
+	}
+
+	t.Run("constraint violations", func(t *testing.T) {
+		var nerr *domain.NotNullViolationError
+		require.True(t, errors.As(execErr(t, "alter table T1 add X int not null"), &nerr))
+
+		var uerr *domain.UniqueViolationError
+		require.True(t, errors.As(execErr(t, "alter table T1 add X int default 1 unique"), &uerr))
+
+		var cerr *domain.CheckViolationError
+		require.True(t, errors.As(execErr(t, "alter table T1 add X int default 0 check (X > 0)"), &cerr))
+
+		var ferr *domain.ForeignKeyViolationError
+		require.True(t, errors.As(execErr(t, "alter table C add X int default 99 references T1"), &ferr))
+
+		require.ErrorIs(t, execErr(t, "alter table C add X int references Nope"), domain.ErrTableNotFound)
+		require.ErrorIs(t, execErr(t, "alter table C add X int references T1(Name)"), domain.ErrNoMatchingUniqueKey)
+		require.ErrorIs(t, execErr(t, "alter table C add X varchar(8) references T1"), domain.ErrTypeMismatch)
+		require.ErrorIs(t, execErr(t, "alter table T1 add X int primary key"), domain.ErrMultiplePrimaryKeys)
+
+		// 検査は catalog と record を書き換える前に行うので, 失敗した command の変更は残らない.
+		require.Len(t, query(t, "select ID, Name from T1"), numRows)
+		require.Equal(t, []string{"2,n2"}, query(t, "select ID, Name from T1 where ID = 2"))
+		require.Equal(t, []string{"10,1"}, query(t, "select ID, PID from C"))
+		for _, tbl := range []domain.TableName{"t1", "c"} {
+			txn := cr.NewTxn()
+			layout, err := mmgr.GetTableLayout(tbl, txn)
+			require.NoError(t, err)
+			require.False(t, layout.Schema().HasField("x"))
+			err = txn.Commit()
+			require.NoError(t, err)
+		}
+
+		txn := cr.NewTxn()
+		defer txn.Rollback()
+		_, err := pe.ExecuteUpdate("alter table D drop column B", txn)
+		require.NoError(t, err)
+		_, err = pe.ExecuteUpdate("alter table D drop column A", txn)
+		require.ErrorIs(t, err, domain.ErrDropLastField)
+	})
+
+	t.Run("rollback keeps the table", func(t *testing.T) {
+		txn := cr.NewTxn()
+		for _, cmd := range []string{
+			"alter table T1 add X int default 1",
+			"alter table T1 drop Name",
+			"alter table T1 rename to T3",
+		} {
+			_, err := pe.ExecuteUpdate(cmd, txn)
+			require.NoError(t, err)
+		}
+		err := txn.Rollback()
+		require.NoError(t, err)
+
+		require.NoFileExists(t, filepath.Join(cr.DBPath(), "t3"))
+		require.Empty(t, tempFiles(t, cr.DBPath()))
+		require.Equal(t, []string{"2,n2"}, query(t, "select ID, Name from T1 where ID = 2"))
+		require.Equal(t, []string{"n7"}, query(t, "select Name from T1 where Name = 'n7'"))
+		require.Len(t, query(t, "select ID from T1"), numRows)
+	})
+
+	t.Run("add column", func(t *testing.T) {
+		exec(t,
+			"alter table T1 add column Score int default 5",
+			"alter table T1 add Code int unique",
+			"insert into T1(ID, Name) values (100, 'x')",
+		)
+
+		require.Empty(t, tempFiles(t, cr.DBPath()))
+		require.Len(t, query(t, "select ID from T1"), numRows+1)
+		require.Equal(t, []string{"3,n3,5,null"}, query(t, "select ID, Name, Score, Code from T1 where ID = 3"))
+		require.Equal(t, []string{"100,5"}, query(t, "select ID, Score from T1 where Name = 'x'"))
+		require.Equal(t, []string{"n7"}, query(t, "select Name from T1 where Name = 'n7'"))
+
+		var uerr *domain.UniqueViolationError
+		require.True(t, errors.As(execErr(t, "insert into T1(ID, Name) values (1, 'dup')"), &uerr))
+
+		exec(t, "update T1 set Code = 1 where ID = 1")
+		require.True(t, errors.As(execErr(t, "update T1 set Code = 1 where ID = 2"), &uerr))
+	})
+
+	t.Run("rename column", func(t *testing.T) {
+		exec(t, "alter table T1 rename column Name to Label")
+
+		require.Equal(t, []string{"n7"}, query(t, "select Label from T1 where Label = 'n7'"))
+
+		var cerr *domain.CheckViolationError
+		require.True(t, errors.As(execErr(t, "insert into T1(ID, Label) values (200, 'z')"), &cerr))
+	})
+
+	t.Run("drop column", func(t *testing.T) {
+		exec(t, "alter table T1 drop column Label")
+
+		require.Equal(t, []string{"3,5,null"}, query(t, "select ID, Score, Code from T1 where ID = 3"))
+		require.Len(t, query(t, "select ID from T1"), numRows+1)
+
+		txn := cr.NewTxn()
+		defer txn.Commit()
+		infos, err := mmgr.GetIndexInfo("t1", txn)
+		require.NoError(t, err)
+		names := make([]domain.IndexName, 0)
+		for _, info := range infos {
+			names = append(names, info.IndexName())
+		}
+		require.ElementsMatch(t, []domain.IndexName{"t1_pkey", "t1_code_key"}, names)
+	})
+
+	t.Run("rename table", func(t *testing.T) {
+		exec(t, "alter table T1 rename to T2")
+
+		require.NoFileExists(t, filepath.Join(cr.DBPath(), "t1"))
+		require.Empty(t, tempFiles(t, cr.DBPath()))
+		require.Equal(t, []string{"3,5"}, query(t, "select ID, Score from T2 where ID = 3"))
+
+		var ferr *domain.ForeignKeyViolationError
+		require.True(t, errors.As(execErr(t, "delete from T2 where ID = 1"), &ferr))
+		require.True(t, errors.As(execErr(t, "insert into C(ID, PID) values (11, 999)"), &ferr))
+		exec(t, "insert into C(ID, PID) values (11, 2)")
+
+		txn := cr.NewTxn()
+		defer txn.Commit()
+		_, err := mmgr.GetTableLayout("t1", txn)
+		require.Error(t, err)
+		refs, err := mmgr.GetReferencingTables("t2", txn)
+		require.NoError(t, err)
+		require.Equal(t, []domain.TableName{"c"}, refs)
+	})
+}
+
+func TestExecutor_slotted_table(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 30
+		numRows   = 30
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr))
+
+	txn := cr.NewTxn()
+	cmds := []string{
+		"create table T1(ID int primary key, Name varchar(200)) using slotted",
+	}
+	for i := 1; i <= numRows; i++ {
+		cmds = append(cmds, fmt.Sprintf("insert into T1(ID, Name) values (%v, 'n%v')", i, i))
+	}
+	cmds = append(cmds,
+		"update T1 set Name = 'a long name for id 3' where ID = 3",
+		"update T1 set Name = NULL where ID = 4",
+		"delete from T1 where ID > 5",
+		"alter table T1 add column Age int default 20",
+		"update T1 set Age = 30 where ID = 5",
+	)
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+
+	layout, err := mmgr.GetTableLayout("t1", txn)
+	require.NoError(t, err)
+	require.Equal(t, domain.SlottedRecordFormat, layout.Format())
+
+	p, err := pe.CreateQueryPlan("select ID, Name, Age from T1 order by ID", txn)
+	require.NoError(t, err)
+	s, err := p.Open()
+	require.NoError(t, err)
+
+	actual := make([]string, 0)
+	for s.HasNext() {
+		vals := make([]string, 0)
+		for _, fld := range p.Schema().Fields() {
+			val, err := s.GetVal(fld)
+			require.NoError(t, err)
+			vals = append(vals, val.String())
+		}
+		actual = append(actual, strings.Join(vals, ","))
+	}
+	require.NoError(t, s.Err())
+	s.Close()
+
+	expected := []string{
+		"1,n1,20",
+		"2,n2,20",
+		"3,a long name for id 3,20",
+		"4,null,20",
+		"5,n5,30",
+	}
+	require.Equal(t, expected, actual)
+
+	_, err = pe.ExecuteUpdate("insert into T1(ID, Name) values (1, 'dup')", txn)
+	var uerr *domain.UniqueViolationError
+	require.ErrorAs(t, err, &uerr)
+
+	err = txn.Commit()
+	require.NoError(t, err)
+}
+
+func TestExecutor_text_and_bytea(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 30
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr))
+
+	query := func(t *testing.T, q string, txn domain.Transaction) []string {
+		p, err := pe.CreateQueryPlan(q, txn)
+		require.NoError(t, err)
+		s, err := p.Open()
+		require.NoError(t, err)
+		defer s.Close()
+
+		actual := make([]string, 0)
+		for s.HasNext() {
+			vals := make([]string, 0)
+			for _, fld := range p.Schema().Fields() {
+				val, err := s.GetVal(fld)
+				require.NoError(t, err)
+				vals = append(vals, val.String())
+			}
+			actual = append(actual, strings.Join(vals, ","))
+		}
+		require.NoError(t, s.Err())
+
+		return actual
+	}
+
+	// block に収まらない値は overflow page の chain に保存される.
+	long := strings.Repeat("0123456789", 200)
+
+	txn := cr.NewTxn()
+	cmds := []string{
+		"create table T1(ID int primary key, Body text, Data bytea)",
+		fmt.Sprintf("insert into T1(ID, Body, Data) values (1, '%v', '\\\\x00ff10')", long),
+		"insert into T1(ID, Body, Data) values (2, 'short', 'raw')",
+		"insert into T1(ID, Body) values (3, '')",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	require.NoError(t, txn.Commit())
+
+	txn = cr.NewTxn()
+	expected := []string{
+		"1," + long + ",\\x00ff10",
+		"2,short,\\x726177",
+		"3,,null",
+	}
+	require.Equal(t, expected, query(t, "select ID, Body, Data from T1 order by ID", txn))
+	require.Equal(t, []string{"2"}, query(t, "select ID from T1 where Body = 'short'", txn))
+	require.Equal(t, []string{"short!"}, query(t, "select Body || '!' as B from T1 where ID = 2", txn))
+	require.NoError(t, txn.Commit())
+
+	// 更新と削除で解放した overflow page は再利用されるので file は大きくならない.
+	txn = cr.NewTxn()
+	size, err := txn.BlockLength(domain.TableName("t1").ToOverflowFileName())
+	require.NoError(t, err)
+	for _, cmd := range []string{
+		fmt.Sprintf("update T1 set Body = '%v' where ID = 1", strings.ToUpper(long)),
+		"delete from T1 where ID = 2",
+		fmt.Sprintf("insert into T1(ID, Body) values (4, '%v')", long[:700]),
+		"update T1 set Body = NULL where ID = 3",
+	} {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	size2, err := txn.BlockLength(domain.TableName("t1").ToOverflowFileName())
+	require.NoError(t, err)
+	require.Equal(t, size, size2)
+	require.NoError(t, txn.Commit())
+
+	txn = cr.NewTxn()
+	expected = []string{
+		"1," + strings.ToUpper(long),
+		"3,null",
+		"4," + long[:700],
+	}
+	require.Equal(t, expected, query(t, "select ID, Body from T1 order by ID", txn))
+
+	// rollback すると overflow page の値も元に戻る.
+	_, err = pe.ExecuteUpdate("update T1 set Body = 'changed'", txn)
+	require.NoError(t, err)
+	require.NoError(t, txn.Rollback())
+
+	txn = cr.NewTxn()
+	require.Equal(t, expected, query(t, "select ID, Body from T1 order by ID", txn))
+
+	_, err = pe.ExecuteUpdate("alter table T1 add column Note text default 'none'", txn)
+	require.NoError(t, err)
+	expected = []string{
+		"1," + strings.ToUpper(long) + ",none",
+		"3,null,none",
+		"4," + long[:700] + ",none",
+	}
+	require.Equal(t, expected, query(t, "select ID, Body, Note from T1 order by ID", txn))
+	require.NoError(t, txn.Commit())
+
+	errTests := []struct {
+		name string
+		cmd  string
+		err  error
+	}{
+		{name: "index on text", cmd: "create index t1_body_idx on T1(Body)", err: domain.ErrNotIndexable},
+		{name: "unique bytea", cmd: "create table T2(Data bytea unique)", err: domain.ErrNotIndexable},
+		{name: "invalid bytea", cmd: "insert into T1(ID, Data) values (5, '\\\\xzz')", err: domain.ErrInvalidBytes},
+		{name: "int to text", cmd: "update T1 set Body = 1", err: domain.ErrTypeMismatch},
+	}
+	for _, tt := range errTests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			defer txn.Rollback()
+
+			_, err := pe.ExecuteUpdate(tt.cmd, txn)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestExecutor_numeric_types(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 30
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr))
+
+	query := func(t *testing.T, q string, txn domain.Transaction) []string {
+		p, err := pe.CreateQueryPlan(q, txn)
+		require.NoError(t, err)
+		s, err := p.Open()
+		require.NoError(t, err)
+		defer s.Close()
+
+		actual := make([]string, 0)
+		for s.HasNext() {
+			vals := make([]string, 0)
+			for _, fld := range p.Schema().Fields() {
+				val, err := s.GetVal(fld)
+				require.NoError(t, err)
+				vals = append(vals, val.String())
+			}
+			actual = append(actual, strings.Join(vals, ","))
+		}
+		require.NoError(t, s.Err())
+
+		return actual
+	}
+
+	txn := cr.NewTxn()
+	cmds := []string{
+		"create table T1(S smallint, B bigint, F boolean, R real, D double precision)",
+		"create index t1_b_idx on T1(B)",
+		"insert into T1(S, B, F, R, D) values (1, 10000000000, true, 1.5, 0.25)",
+		"insert into T1(S, B, F, R, D) values (-2, -3, false, 2, 1e3)",
+		"insert into T1(S, B, F, R, D) values (3, 9223372036854775807, true, -0.5, 2.5e-1)",
+		"create table T2(S smallint, B bigint, F boolean, R real, D double precision) using slotted",
+		"insert into T2(S, B, F, R, D) values (7, 10000000000, false, 3.5, 0.125)",
+		"insert into T2(S, B) values (8, null)",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	require.NoError(t, txn.Commit())
+
+	txn = cr.NewTxn()
+	expected := []string{
+		"-2,-3,false,2,1000",
+		"1,10000000000,true,1.5,0.25",
+		"3,9223372036854775807,true,-0.5,0.25",
+	}
+	require.Equal(t, expected, query(t, "select S, B, F, R, D from T1 order by S", txn))
+	require.Equal(t, []string{"7,10000000000,false,3.5,0.125", "8,null,null,null,null"}, query(t, "select S, B, F, R, D from T2 order by S", txn))
+
+	// 異なる数値型どうしの比較と演算.
+	require.Equal(t, []string{"1"}, query(t, "select S from T1 where B = 10000000000", txn))
+	require.Equal(t, []string{"-2", "1"}, query(t, "select S from T1 where R >= S order by S", txn))
+	require.Equal(t, []string{"3"}, query(t, "select S from T1 where D = 0.25 and S > 2", txn))
+	require.Equal(t, []string{"-2"}, query(t, "select S from T1 where F = false", txn))
+	require.Equal(t, []string{"10000000001.5"}, query(t, "select B + R as X from T1 where S = 1", txn))
+	require.Equal(t, []string{"-1,500.125"}, query(t, "select sum(S) as X, avg(D) as Y from T1 where S < 3", txn))
+	require.NoError(t, txn.Commit())
+
+	// rollback すると 64 bit の値も元に戻る.
+	txn = cr.NewTxn()
+	for _, cmd := range []string{
+		"update T1 set B = B * 2 where S = -2",
+		"update T1 set D = D + 1 where S = -2",
+		"update T1 set F = true where S = -2",
+	} {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{"-6,true,1001"}, query(t, "select B, F, D from T1 where S = -2", txn))
+	require.NoError(t, txn.Rollback())
+
+	txn = cr.NewTxn()
+	require.Equal(t, []string{"-3,false,1000"}, query(t, "select B, F, D from T1 where S = -2", txn))
+	require.NoError(t, txn.Commit())
+
+	errTests := []struct {
+		name string
+		cmd  string
+		err  error
+	}{
+		{name: "smallint out of range", cmd: "insert into T1(S) values (40000)", err: domain.ErrNumericOutOfRange},
+		{name: "bigint overflow", cmd: "update T1 set B = B + 1 where S = 3", err: domain.ErrNumericOutOfRange},
+		{name: "double to bigint", cmd: "update T1 set B = 1.5", err: domain.ErrTypeMismatch},
+		{name: "int to boolean", cmd: "update T1 set F = 1", err: domain.ErrTypeMismatch},
+	}
+	for _, tt := range errTests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			defer txn.Rollback()
+
+			_, err := pe.ExecuteUpdate(tt.cmd, txn)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestExecutor_datetime_types(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 30
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr))
+
+	query := func(t *testing.T, q string, txn domain.Transaction) []string {
+		p, err := pe.CreateQueryPlan(q, txn)
+		require.NoError(t, err)
+		s, err := p.Open()
+		require.NoError(t, err)
+		defer s.Close()
+
+		actual := make([]string, 0)
+		for s.HasNext() {
+			vals := make([]string, 0)
+			for _, fld := range p.Schema().Fields() {
+				val, err := s.GetVal(fld)
+				require.NoError(t, err)
+				vals = append(vals, val.String())
+			}
+			actual = append(actual, strings.Join(vals, ","))
+		}
+		require.NoError(t, s.Err())
+
+		return actual
+	}
+
+	txn := cr.NewTxn()
+	cmds := []string{
+		"create table Ev(ID int primary key, D date default '2000-01-01' check (D >= date '2000-01-01'), T timestamp, TZ timestamp with time zone, Dur interval)",
+		"create index ev_t_idx on Ev(T)",
+		"insert into Ev(ID, D, T, TZ, Dur) values (1, date '2024-01-31', timestamp '2024-01-31 10:00:00', timestamptz '2024-01-31 10:00:00+09', interval '1 mon')",
+		"insert into Ev(ID, D, T, TZ, Dur) values (2, '2024-02-29', '2024-02-29 23:59:59.5', '2024-02-29 00:00:00', '2 hours 30 minutes')",
+		"insert into Ev(ID, T) values (3, timestamp '1999-12-31 23:59:59')",
+		"create table Slot(ID int, Dur interval, T timestamp) using slotted",
+		"insert into Slot(ID, Dur, T) values (1, interval '1 year 2 mons 3 days 04:05:06', timestamp '2024-01-01 00:00:00')",
+		"insert into Slot(ID) values (2)",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	require.NoError(t, txn.Commit())
+
+	txn = cr.NewTxn()
+	expected := []string{
+		"3,2000-01-01,1999-12-31 23:59:59,null,null",
+		"1,2024-01-31,2024-01-31 10:00:00,2024-01-31 01:00:00+00,1 mon",
+		"2,2024-02-29,2024-02-29 23:59:59.5,2024-02-29 00:00:00+00,02:30:00",
+	}
+	require.Equal(t, expected, query(t, "select ID, D, T, TZ, Dur from Ev order by T", txn))
+	require.Equal(t, []string{"1,1 year 2 mons 3 days 04:05:06,2024-01-01 00:00:00", "2,null,null"}, query(t, "select ID, Dur, T from Slot order by ID", txn))
+
+	// 日時の比較と演算.
+	require.Equal(t, []string{"1", "2"}, query(t, "select ID from Ev where T >= timestamp '2024-01-01 00:00:00' order by ID", txn))
+	require.Equal(t, []string{"2"}, query(t, "select ID from Ev where D = timestamp '2024-02-29 00:00:00'", txn))
+	require.Equal(t, []string{"2"}, query(t, "select ID from Ev where Dur > interval '2 hours' and Dur < interval '1 day'", txn))
+	require.Equal(t, []string{"1", "2"}, query(t, "select ID from Ev where D > '2024-01-01' order by ID", txn))
+	require.Equal(t, []string{"1"}, query(t, "select ID from Ev where T = '2024-01-31 10:00:00'", txn))
+	require.Equal(t, []string{"2"}, query(t, "select ID from Ev where Dur = '150 minutes'", txn))
+	bad, err := pe.CreateQueryPlan("select ID from Ev where D > 'x'", txn)
+	require.NoError(t, err)
+	bs, err := bad.Open()
+	require.NoError(t, err)
+	for bs.HasNext() {
+	}
+	require.Error(t, bs.Err())
+	bs.Close()
+	require.Equal(t, []string{"1,2024-02-01,2024-02-29 10:00:00,29"}, query(t, "select ID, D + 1 as N, T + Dur as E, date '2024-02-29' - D as Days from Ev where ID = 1", txn))
+	require.Equal(t, []string{"2,2 days 13:59:59.5"}, query(t, "select ID, T - timestamp '2024-02-27 10:00:00' as Diff from Ev where ID = 2", txn))
+	require.Equal(t, []string{"2000-01-01,2024-02-29"}, query(t, "select min(D) as Lo, max(D) as Hi from Ev", txn))
+	require.Equal(t, []string{"1", "2", "3"}, query(t, "select ID from Ev where T < now() and D <= current_date order by ID", txn))
+	require.NoError(t, txn.Commit())
+
+	// now() を含む view は参照するたびに評価する.
+	txn = cr.NewTxn()
+	_, err = pe.ExecuteUpdate("create view Recent as select ID from Ev where T > now() - interval '100 years'", txn)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1", "2", "3"}, query(t, "select ID from Recent order by ID", txn))
+	require.NoError(t, txn.Commit())
+
+	// rollback すると日時の値も元に戻る.
+	txn = cr.NewTxn()
+	for _, cmd := range []string{
+		"update Ev set T = T + interval '1 day' where ID = 1",
+		"update Ev set Dur = Dur + interval '1 day' where ID = 1",
+		"update Slot set Dur = -Dur where ID = 1",
+	} {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	require.Equal(t, []string{"2024-02-01 10:00:00,1 mon 1 day"}, query(t, "select T, Dur from Ev where ID = 1", txn))
+	require.Equal(t, []string{"-1 years -2 mons -3 days -04:05:06"}, query(t, "select Dur from Slot where ID = 1", txn))
+	require.NoError(t, txn.Rollback())
+
+	txn = cr.NewTxn()
+	require.Equal(t, []string{"2024-01-31 10:00:00,1 mon"}, query(t, "select T, Dur from Ev where ID = 1", txn))
+	require.Equal(t, []string{"1 year 2 mons 3 days 04:05:06"}, query(t, "select Dur from Slot where ID = 1", txn))
+	require.NoError(t, txn.Commit())
+
+	txn = cr.NewTxn()
+	_, err = pe.ExecuteUpdate("insert into Ev(ID, D) values (10, date '1999-12-31')", txn)
+	var cerr *domain.CheckViolationError
+	require.ErrorAs(t, err, &cerr)
+	require.NoError(t, txn.Rollback())
+
+	errTests := []struct {
+		name string
+		cmd  string
+		err  error
+	}{
+		{name: "invalid date", cmd: "insert into Ev(ID, D) values (10, '2024-02-30')", err: domain.ErrInvalidDatetime},
+		{name: "invalid interval", cmd: "update Ev set Dur = '1 fortnight'", err: domain.ErrInvalidDatetime},
+		{name: "date + date", cmd: "update Ev set D = D + D", err: domain.ErrTypeMismatch},
+		{name: "integer to date", cmd: "update Ev set D = 1", err: domain.ErrTypeMismatch},
+		{name: "index on interval", cmd: "create index ev_dur_idx on Ev(Dur)", err: domain.ErrNotIndexable},
+	}
+	for _, tt := range errTests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			defer txn.Rollback()
+
+			_, err := pe.ExecuteUpdate(tt.cmd, txn)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestExecutor_decimal_types(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 30
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr))
+
+	query := func(t *testing.T, q string, txn domain.Transaction) []string {
+		p, err := pe.CreateQueryPlan(q, txn)
+		require.NoError(t, err)
+		s, err := p.Open()
+		require.NoError(t, err)
+		defer s.Close()
+
+		actual := make([]string, 0)
+		for s.HasNext() {
+			vals := make([]string, 0)
+			for _, fld := range p.Schema().Fields() {
+				val, err := s.GetVal(fld)
+				require.NoError(t, err)
+				vals = append(vals, val.String())
+			}
+			actual = append(actual, strings.Join(vals, ","))
+		}
+		require.NoError(t, s.Err())
+
+		return actual
+	}
+
+	txn := cr.NewTxn()
+	cmds := []string{
+		"create table Acct(ID int primary key, Bal numeric(10, 2) default 0 check (Bal >= 0), Rate numeric)",
+		"create index acct_bal_idx on Acct(Bal)",
+		"insert into Acct(ID, Bal, Rate) values (1, 19.99, 0.1)",
+		"insert into Acct(ID, Bal, Rate) values (2, '1234.565', numeric '0.0375')",
+		"insert into Acct(ID) values (3)",
+		"insert into Acct(ID, Bal) values (4, 0.005)",
+		"create table Pay(PID int, Amt decimal(10, 2))",
+		"insert into Pay(PID, Amt) values (10, 19.990)",
+		"insert into Pay(PID, Amt) values (11, 5)",
+		"create table Big(ID int, N numeric(38, 10)) using slotted",
+		"insert into Big(ID, N) values (1, '1234567890123456789012345678.0123456789')",
+		"insert into Big(ID) values (2)",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	require.NoError(t, txn.Commit())
+
+	txn = cr.NewTxn()
+	// 代入時に field の scale に四捨五入する.
+	expected := []string{
+		"1,19.99,0.1",
+		"2,1234.57,0.0375",
+		"3,0.00,null",
+		"4,0.01,null",
+	}
+	require.Equal(t, expected, query(t, "select ID, Bal, Rate from Acct order by ID", txn))
+	require.Equal(t, []string{"1,1234567890123456789012345678.0123456789", "2,null"}, query(t, "select ID, N from Big order by ID", txn))
+
+	// 比較と演算.
+	require.Equal(t, []string{"1"}, query(t, "select ID from Acct where Bal = 19.99", txn))
+	require.Equal(t, []string{"4", "3"}, query(t, "select ID from Acct where Bal < 1 order by Bal desc", txn))
+	require.Equal(t, []string{"59.97,20.00,1.999"}, query(t, "select Bal * 3 as X, Bal + 0.01 as Y, Bal * Rate as Z from Acct where ID = 1", txn))
+	require.Equal(t, []string{"1254.57,313.6425000000000000"}, query(t, "select sum(Bal) as S, avg(Bal) as A from Acct", txn))
+	require.Equal(t, []string{"10,1"}, query(t, "select PID, ID from Pay, Acct where Amt = Bal", txn))
+	require.NoError(t, txn.Commit())
+
+	// NUMERIC の literal を含む view.
+	txn = cr.NewTxn()
+	_, err = pe.ExecuteUpdate("create view Rich as select ID from Acct where Bal > numeric '100.00'", txn)
+	require.NoError(t, err)
+	require.Equal(t, []string{"2"}, query(t, "select ID from Rich", txn))
+	require.NoError(t, txn.Commit())
+
+	// rollback すると元の値に戻る.
+	txn = cr.NewTxn()
+	_, err = pe.ExecuteUpdate("update Acct set Bal = Bal * 1.5 where ID = 1", txn)
+	require.NoError(t, err)
+	require.Equal(t, []string{"29.99"}, query(t, "select Bal from Acct where ID = 1", txn))
+	require.NoError(t, txn.Rollback())
+
+	txn = cr.NewTxn()
+	require.Equal(t, []string{"19.99"}, query(t, "select Bal from Acct where ID = 1", txn))
+	require.Equal(t, []string{"1"}, query(t, "select ID from Acct where Bal = 19.99", txn))
+	require.NoError(t, txn.Commit())
+
+	txn = cr.NewTxn()
+	_, err = pe.ExecuteUpdate("update Acct set Bal = Bal - 20 where ID = 1", txn)
+	var cerr *domain.CheckViolationError
+	require.ErrorAs(t, err, &cerr)
+	require.NoError(t, txn.Rollback())
+
+	errTests := []struct {
+		name string
+		cmd  string
+		err  error
+	}{
+		{name: "field overflow", cmd: "insert into Acct(ID, Bal) values (10, 99999999.995)", err: domain.ErrNumericOutOfRange},
+		{name: "invalid string", cmd: "insert into Acct(ID, Bal) values (10, 'abc')", err: domain.ErrInvalidNumeric},
+		{name: "invalid precision", cmd: "create table Bad(N numeric(39, 0))", err: domain.ErrInvalidNumericType},
+		{name: "numeric to integer", cmd: "update Acct set ID = Bal", err: domain.ErrTypeMismatch},
+		{name: "division by zero", cmd: "update Acct set Bal = Bal / 0", err: domain.ErrDivisionByZero},
+	}
+	for _, tt := range errTests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			txn := cr.NewTxn()
+			defer txn.Rollback()
+
+			_, err := pe.ExecuteUpdate(tt.cmd, txn)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestExecutor_multi_version(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 30
+	)
+
+	cr := fake.NewMultiVersionTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr))
+
+	query := func(t *testing.T, q string, txn domain.Transaction) []string {
+		p, err := pe.CreateQueryPlan(q, txn)
+		require.NoError(t, err)
+		s, err := p.Open()
+		require.NoError(t, err)
+		defer s.Close()
+
+		actual := make([]string, 0)
+		for s.HasNext() {
+			vals := make([]string, 0)
+			for _, fld := range p.Schema().Fields() {
+				val, err := s.GetVal(fld)
+				require.NoError(t, err)
+				vals = append(vals, val.String())
+			}
+			actual = append(actual, strings.Join(vals, ","))
+		}
+		require.NoError(t, s.Err())
+
+		return actual
+	}
+
+	txn := cr.NewTxn()
+	cmds := []string{
+		"create table Acct(ID int primary key, Name text, Bal int)",
+		"create index acct_bal_idx on Acct(Bal)",
+		"create table Audit(ID int)",
+		"insert into Acct(ID, Name, Bal) values (1, 'alice', 100)",
+		"insert into Acct(ID, Name, Bal) values (2, 'bob', 200)",
+		"insert into Acct(ID, Name, Bal) values (3, 'carol', 300)",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	require.NoError(t, txn.Commit())
+
+	initial := []string{"1,alice,100", "2,bob,200", "3,carol,300"}
+
+	// 読み込み中の transaction があっても書き込みは待たされず, 読み込み側は開始時の snapshot を読み続ける.
+	reader := cr.NewTxn()
+	require.Equal(t, initial, query(t, "select ID, Name, Bal from Acct order by ID", reader))
+
+	writer := cr.NewTxn()
+	for _, cmd := range []string{
+		"update Acct set Bal = 150 where ID = 1",
+		"update Acct set Name = 'alicia' where ID = 1",
+		"delete from Acct where ID = 2",
+		"insert into Acct(ID, Name, Bal) values (4, 'dave', 400)",
+	} {
+		_, err := pe.ExecuteUpdate(cmd, writer)
+		require.NoError(t, err)
+	}
+	updated := []string{"1,alicia,150", "3,carol,300", "4,dave,400"}
+	require.Equal(t, updated, query(t, "select ID, Name, Bal from Acct order by ID", writer))
+	require.Equal(t, []string{"1"}, query(t, "select ID from Acct where Bal = 150", writer))
+	require.Equal(t, initial, query(t, "select ID, Name, Bal from Acct order by ID", reader))
+	require.NoError(t, writer.Commit())
+
+	require.Equal(t, initial, query(t, "select ID, Name, Bal from Acct order by ID", reader))
+	require.Equal(t, []string{"1"}, query(t, "select ID from Acct where Bal = 100", reader))
+	require.Equal(t, []string{"bob"}, query(t, "select Name from Acct where ID = 2", reader))
+	require.NoError(t, reader.Commit())
+
+	txn = cr.NewTxn()
+	require.Equal(t, updated, query(t, "select ID, Name, Bal from Acct order by ID", txn))
+	require.Equal(t, []string{}, query(t, "select ID from Acct where Bal = 100", txn))
+	require.NoError(t, txn.Commit())
+
+	// 同じ record を先に更新した transaction が勝つ.
+	txn1 := cr.NewTxn()
+	txn2 := cr.NewTxn()
+	_, err = pe.ExecuteUpdate("update Acct set Bal = Bal + 1 where ID = 1", txn1)
+	require.NoError(t, err)
+	require.NoError(t, txn1.Commit())
+	_, err = pe.ExecuteUpdate("update Acct set Bal = Bal + 10 where ID = 1", txn2)
+	require.ErrorIs(t, err, domain.ErrSerializationFailure)
+	require.NoError(t, txn2.Rollback())
+
+	txn1 = cr.NewTxn()
+	txn2 = cr.NewTxn()
+	_, err = pe.ExecuteUpdate("delete from Acct where ID = 4", txn1)
+	require.NoError(t, err)
+	require.NoError(t, txn1.Commit())
+	_, err = pe.ExecuteUpdate("delete from Acct where ID = 4", txn2)
+	require.ErrorIs(t, err, domain.ErrSerializationFailure)
+	require.NoError(t, txn2.Rollback())
+
+	// 別の record の更新は衝突しない.
+	txn1 = cr.NewTxn()
+	txn2 = cr.NewTxn()
+	_, err = pe.ExecuteUpdate("update Acct set Bal = 1000 where ID = 1", txn1)
+	require.NoError(t, err)
+	require.NoError(t, txn1.Commit())
+	_, err = pe.ExecuteUpdate("update Acct set Bal = 3000 where ID = 3", txn2)
+	require.NoError(t, err)
+	require.NoError(t, txn2.Commit())
+
+	// rollback した変更は見えない.
+	txn = cr.NewTxn()
+	_, err = pe.ExecuteUpdate("update Acct set Name = 'x' where ID = 1", txn)
+	require.NoError(t, err)
+	_, err = pe.ExecuteUpdate("insert into Acct(ID, Name, Bal) values (5, 'eve', 500)", txn)
+	require.NoError(t, err)
+	require.NoError(t, txn.Rollback())
+
+	txn = cr.NewTxn()
+	require.Equal(t, []string{"1,alicia,1000", "3,carol,3000"}, query(t, "select ID, Name, Bal from Acct order by ID", txn))
+	require.NoError(t, txn.Commit())
+
+	// 更新した command が終われば snapshot を読むので, 後の query は他の transaction の書き込みを待たせない.
+	txn1 = cr.NewTxn()
+	_, err = pe.ExecuteUpdate("insert into Audit(ID) values (1)", txn1)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1,1000", "3,3000"}, query(t, "select ID, Bal from Acct order by ID", txn1))
+
+	txn2 = cr.NewTxn()
+	_, err = pe.ExecuteUpdate("update Acct set Bal = 3001 where ID = 3", txn2)
+	require.NoError(t, err)
+	require.NoError(t, txn2.Commit())
+
+	require.Equal(t, []string{"1,1000", "3,3000"}, query(t, "select ID, Bal from Acct order by ID", txn1))
+	require.NoError(t, txn1.Commit())
+
+	// 古い version の record は index からも見つかるので, 最新の page を読んでも table と index で食い違わない.
+	reader = cr.NewTxn()
+	writer = cr.NewTxn()
+	for _, cmd := range []string{
+		"update Acct set Bal = 2000 where ID = 1",
+		"delete from Acct where ID = 3",
+	} {
+		_, err := pe.ExecuteUpdate(cmd, writer)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Commit())
+
+	reader.ForUpdate(true)
+	require.Equal(t, []string{"1,1000", "3,3001"}, query(t, "select ID, Bal from Acct order by ID", reader))
+	require.Equal(t, []string{"1"}, query(t, "select ID from Acct where Name = 'alicia'", reader))
+	require.Equal(t, []string{"1"}, query(t, "select ID from Acct where Bal = 1000", reader))
+	require.Equal(t, []string{}, query(t, "select ID from Acct where Bal = 2000", reader))
+	require.Equal(t, []string{"3001"}, query(t, "select Bal from Acct where ID = 3", reader))
+	reader.ForUpdate(false)
+	require.NoError(t, reader.Commit())
+
+	// 制約は古い version を除いた最新の record で確かめる.
+	txn = cr.NewTxn()
+	for _, cmd := range []string{
+		"create table Card(ID int, Owner int references Acct on delete cascade)",
+		"insert into Acct(ID, Name, Bal) values (3, 'carl', 300)",
+		"update Acct set ID = 6 where ID = 1",
+		"insert into Acct(ID, Name, Bal) values (1, 'amy', 100)",
+		"insert into Card(ID, Owner) values (1, 6)",
+		"insert into Card(ID, Owner) values (2, 6)",
+		"insert into Card(ID, Owner) values (3, 3)",
+	} {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+	}
+	require.NoError(t, txn.Commit())
+
+	txn = cr.NewTxn()
+	var uerr *domain.UniqueViolationError
+	_, err = pe.ExecuteUpdate("insert into Acct(ID, Name, Bal) values (6, 'x', 0)", txn)
+	require.ErrorAs(t, err, &uerr)
+	_, err = pe.ExecuteUpdate("update Acct set Bal = 7 where ID = 6", txn)
+	require.NoError(t, err)
+	var ferr *domain.ForeignKeyViolationError
+	_, err = pe.ExecuteUpdate("update Acct set ID = 8 where ID = 3", txn)
+	require.ErrorAs(t, err, &ferr)
+	n, err := pe.ExecuteUpdate("delete from Acct where ID = 6", txn)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	_, err = pe.ExecuteUpdate("insert into Card(ID, Owner) values (4, 6)", txn)
+	require.ErrorAs(t, err, &ferr)
+	require.Equal(t, []string{"3,3"}, query(t, "select ID, Owner from Card order by ID", txn))
+	require.Equal(t, []string{"1,amy", "3,carl"}, query(t, "select ID, Name from Acct order by ID", txn))
+	require.NoError(t, txn.Commit())
+
+	// table を書き直すと古い version は index の entry と一緒に無くなる.
+	txn = cr.NewTxn()
+	_, err = pe.ExecuteUpdate("alter table Acct add column Note int", txn)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1,amy", "3,carl"}, query(t, "select ID, Name from Acct order by ID", txn))
+	require.Equal(t, []string{"carl"}, query(t, "select Name from Acct where ID = 3", txn))
+	require.Equal(t, []string{}, query(t, "select ID from Acct where Bal = 1000", txn))
+	require.NoError(t, txn.Commit())
+}
+
+func TestExecutor_prune_versions(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 30
+	)
+
+	cr := fake.NewMultiVersionTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr))
+
+	query := func(t *testing.T, q string, txn domain.Transaction) []string {
+		p, err := pe.CreateQueryPlan(q, txn)
+		require.NoError(t, err)
+		s, err := p.Open()
+		require.NoError(t, err)
+		defer s.Close()
+
+		actual := make([]string, 0)
+		for s.HasNext() {
+			vals := make([]string, 0)
+			for _, fld := range p.Schema().Fields() {
+				val, err := s.GetVal(fld)
+				require.NoError(t, err)
+				vals = append(vals, val.String())
+			}
+			actual = append(actual, strings.Join(vals, ","))
+		}
+		require.NoError(t, s.Err())
+
+		return actual
+	}
+
+	exec := func(t *testing.T, cmd string) {
+		txn := cr.NewTxn()
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err)
+		require.NoError(t, txn.Commit())
+	}
+
+	exec(t, "create table Hot(ID int primary key, Val int)")
+	exec(t, "create index hot_val_idx on Hot(Val)")
+	exec(t, "insert into Hot(ID, Val) values (1, 0)")
+
+	// 古い version を読む transaction がある間は version を残す.
+	reader := cr.NewTxn()
+	for i := 1; i <= 20; i++ {
+		exec(t, fmt.Sprintf("update Hot set Val = %v where ID = 1", i))
+	}
+	require.Equal(t, []string{"1,0"}, query(t, "select ID, Val from Hot", reader))
+	require.Equal(t, []string{"1,0"}, query(t, "select ID, Val from Hot where Val = 0", reader))
+	require.NoError(t, reader.Commit())
+
+	// 誰も読まなくなった version は回収され, 同じ record を何度更新しても table は大きくならない.
+	for i := 21; i <= 200; i++ {
+		exec(t, fmt.Sprintf("update Hot set Val = %v where ID = 1", i))
+	}
+
+	txn := cr.NewTxn()
+	blkLen, err := txn.BlockLength(domain.FileName("hot"))
+	require.NoError(t, err)
+	require.LessOrEqual(t, blkLen, int32(2))
+
+	require.Equal(t, []string{"1,200"}, query(t, "select ID, Val from Hot", txn))
+	require.Equal(t, []string{"1,200"}, query(t, "select ID, Val from Hot where ID = 1", txn))
+	require.Equal(t, []string{"1,200"}, query(t, "select ID, Val from Hot where Val = 200", txn))
+	require.Empty(t, query(t, "select ID, Val from Hot where Val = 100", txn))
+	require.NoError(t, txn.Commit())
+
+	exec(t, "delete from Hot where ID = 1")
+	exec(t, "insert into Hot(ID, Val) values (1, 100)")
+
+	txn = cr.NewTxn()
+	require.Equal(t, []string{"1,100"}, query(t, "select ID, Val from Hot where Val = 100", txn))
+	require.Equal(t, []string{"1,100"}, query(t, "select ID, Val from Hot where ID = 1", txn))
+	require.Empty(t, query(t, "select ID, Val from Hot where Val = 200", txn))
+	require.NoError(t, txn.Commit())
+}
+
 func TestExecutor_update_table_without_predicate(t *testing.T) {
 	const (
 		blockSize = 400
@@ -535,3 +3841,256 @@ func TestExecutor_create_view(t *testing.T) {
 		require.Equal(t, expected, actual)
 	})
 }
+
+func TestExecutor_keywords_as_identifiers(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 30
+	)
+
+	cr := fake.NewTransactionCreater(blockSize, numBuf)
+	defer cr.Finish()
+
+	idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator())
+	mmgr, err := metadata.NewManager(idxDriver, cr.FileMgr, cr.LogMgr, cr.BufMgr, cr.LockTbl, cr.Gen)
+	require.NoError(t, err)
+
+	pe := plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr))
+
+	txn := cr.NewTxn()
+	cmds := []string{
+		"create table u(date int, key int, text varchar(3))",
+		"create table time(zone int primary key, now int)",
+		"create index key_idx on u(key)",
+		"insert into u(date, key, text) values (1, 10, 'a')",
+		"insert into u(date, key, text) values (2, 20, 'b')",
+		"insert into time(zone, now) values (10, 100)",
+		"create view left as select date, text from u where key - 5 > 10",
+		"update u set text = 'c' where date = 2",
+		"alter table u add column to int",
+		"alter table u rename column date to add",
+		"alter table u rename column to to right",
+	}
+	for _, cmd := range cmds {
+		_, err := pe.ExecuteUpdate(cmd, txn)
+		require.NoError(t, err, cmd)
+	}
+	require.NoError(t, txn.Commit())
+
+	tests := []struct {
+		query    string
+		fields   []domain.FieldName
+		expected [][]any
+	}{
+		{
+			query:    "select add, key, text, right from u where key = 10",
+			fields:   []domain.FieldName{"add", "key", "text", "right"},
+			expected: [][]any{{int32(1), int32(10), "a", nil}},
+		},
+		{
+			query:    "select key, now from u join time on key = zone",
+			fields:   []domain.FieldName{"key", "now"},
+			expected: [][]any{{int32(10), int32(100)}},
+		},
+		{
+			query:    "select text from left",
+			fields:   []domain.FieldName{"text"},
+			expected: [][]any{{"c"}},
+		},
+		{
+			query:    "select key from u where date '2024-01-01' < now() order by key desc",
+			fields:   []domain.FieldName{"key"},
+			expected: [][]any{{int32(20)}, {int32(10)}},
+		},
+	}
+
+	for _, tt := range tests {
+		txn := cr.NewTxn()
+		p, err := pe.CreateQueryPlan(tt.query, txn)
+		require.NoError(t, err, tt.query)
+		s, err := p.Open()
+		require.NoError(t, err)
+
+		actual := make([][]any, 0)
+		for s.HasNext() {
+			row := make([]any, 0, len(tt.fields))
+			for _, fld := range tt.fields {
+				v, err := s.GetVal(fld)
+				require.NoError(t, err)
+				row = append(row, v.AsVal())
+			}
+			actual = append(actual, row)
+		}
+		require.NoError(t, s.Err())
+		s.Close()
+		require.NoError(t, txn.Commit())
+
+		require.Equal(t, tt.expected, actual, tt.query)
+	}
+}
+
+func TestExecutor_database_v0(t *testing.T) {
+	const (
+		blockSize = 400
+		numBuf    = 30
+	)
+
+	// testdata/v0 は null bitmap の導入前の版で作った database.
+	dir := t.TempDir()
+	entries, err := os.ReadDir("testdata/v0")
+	require.NoError(t, err)
+	for _, e := range entries {
+		b, err := os.ReadFile(filepath.Join("testdata/v0", e.Name()))
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, e.Name()), b, 0o644))
+	}
+
+	open := func(t *testing.T) (*plan.Executor, func() domain.Transaction) {
+		fileMgr, err := file.NewManager(file.ManagerConfig{DBPath: dir, BlockSize: blockSize, DirectIO: false})
+		require.NoError(t, err)
+		logMgr, err := log.NewManager(fileMgr, log.ManagerConfig{LogFileName: "logfile"})
+		require.NoError(t, err)
+		bufMgr, err := buffer.NewManager(fileMgr, logMgr, buffer.Config{NumberBuffer: numBuf, TimeoutMillisecond: 1000})
+		require.NoError(t, err)
+		lt := tx.NewLockTable(tx.LockTableConfig{LockTimeoutMillisecond: 1000})
+		gen := tx.NewNumberGenerator()
+
+		idxDriver := domain.NewIndexDriver(btree.NewIndexFactory(), btree.NewSearchCostCalculator())
+		mmgr, err := metadata.NewManager(idxDriver, fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+
+		newTxn := func() domain.Transaction {
+			txn, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+			require.NoError(t, err)
+
+			return txn
+		}
+
+		return plan.NewExecutor(plan.NewHeuristicQueryPlanner(mmgr), plan.NewIndexUpdatePlanner(mmgr)), newTxn
+	}
+
+	query := func(t *testing.T, pe *plan.Executor, txn domain.Transaction, q string) []string {
+		p, err := pe.CreateQueryPlan(q, txn)
+		require.NoError(t, err)
+		s, err := p.Open()
+		require.NoError(t, err)
+		defer s.Close()
+
+		flds := p.Schema().Fields()
+		actual := make([]string, 0)
+		for s.HasNext() {
+			row := make([]string, 0, len(flds))
+			for _, fld := range flds {
+				v, err := s.GetVal(fld)
+				require.NoError(t, err)
+				row = append(row, v.String())
+			}
+			actual = append(actual, strings.Join(row, " "))
+		}
+		require.NoError(t, s.Err())
+
+		return actual
+	}
+
+	t.Run("read records written before the null bitmap", func(t *testing.T) {
+		pe, newTxn := open(t)
+		txn := newTxn()
+
+		require.Equal(t, []string{"10 math", "20 compsci"}, query(t, pe, txn, "select did, dname from dept"))
+		require.Len(t, query(t, pe, txn, "select sid from student"), 29)
+		require.Equal(t, []string{"7 stu7 20"}, query(t, pe, txn, "select sid, sname, majorid from student where sid = 7"))
+		require.Empty(t, query(t, pe, txn, "select sid from student where sid = 3"))
+		require.Len(t, query(t, pe, txn, "select sid, sname from math_students"), 15)
+		require.NoError(t, txn.Commit())
+	})
+
+	t.Run("update records written before the null bitmap", func(t *testing.T) {
+		pe, newTxn := open(t)
+		txn := newTxn()
+
+		cmds := []string{
+			"insert into student(sid, sname, majorid) values (30, 'stu30', 10)",
+			"insert into student(sid, sname) values (31, 'stu31')",
+			"update student set sname = 'seven' where sid = 7",
+			"delete from student where sid = 8",
+			"create table t(a int, b varchar(3))",
+			"insert into t(a) values (1)",
+		}
+		for _, cmd := range cmds {
+			_, err := pe.ExecuteUpdate(cmd, txn)
+			require.NoError(t, err)
+		}
+
+		_, err := pe.ExecuteUpdate("update student set majorid = null where sid = 7", txn)
+		require.ErrorIs(t, err, domain.ErrNullNotSupported)
+
+		require.Equal(t, []string{"7 seven 20"}, query(t, pe, txn, "select sid, sname, majorid from student where sid = 7"))
+		require.Equal(t, []string{"31 stu31 0"}, query(t, pe, txn, "select sid, sname, majorid from student where sid = 31"))
+		require.Empty(t, query(t, pe, txn, "select sid from student where sid = 8"))
+		require.Equal(t, []string{"1 null"}, query(t, pe, txn, "select a, b from t"))
+		require.NoError(t, txn.Commit())
+	})
+
+	t.Run("reopen upgraded database", func(t *testing.T) {
+		pe, newTxn := open(t)
+		txn := newTxn()
+
+		require.Len(t, query(t, pe, txn, "select sid from student"), 30)
+		require.Equal(t, []string{"30 stu30"}, query(t, pe, txn, "select sid, sname from math_students where sid = 30"))
+
+		_, err := pe.ExecuteUpdate("alter table student add column email varchar(10)", txn)
+		require.NoError(t, err)
+		_, err = pe.ExecuteUpdate("update student set majorid = null where sid = 7", txn)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"7 seven null null"}, query(t, pe, txn, "select sid, sname, majorid, email from student where sid = 7"))
+		require.Len(t, query(t, pe, txn, "select sid from student"), 30)
+		require.NoError(t, txn.Commit())
+	})
+}
+
+// tempFiles returns the names of the files of temporary tables in dir.
+func tempFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	names := make([]string, 0)
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), domain.TempTablePrefix) {
+			names = append(names, e.Name())
+		}
+	}
+
+	return names
+}
+
+// indexOn returns the index whose key is flds.
+func indexOn(t *testing.T, infos []*domain.IndexInfo, flds ...domain.FieldName) *domain.IndexInfo {
+	t.Helper()
+
+	for _, info := range infos {
+		if fmt.Sprint(info.FieldNames()) == fmt.Sprint(flds) {
+			return info
+		}
+	}
+	require.Failf(t, "index not found", "%v", flds)
+
+	return nil
+}
+
+// scanPlan returns the plan under projections and selections.
+// projection と selection の下にある plan を返す.
+func scanPlan(p domain.Planner) domain.Planner {
+	for {
+		switch q := p.(type) {
+		case *plan.ProjectPlan:
+			p = q.Underlying()
+		case *plan.SelectPlan:
+			p = q.Underlying()
+		default:
+			return p
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package plan
+
+import (
+	"sort"
+
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+	"github.com/goropikari/simpledbgo/math"
+)
+
+// SortPlan is planner for sorting.
+// SortPlan は入力を buffer に収まる大きさの run に分割して sort し、temporary table に書き出す.
+// その後 run を merge して sort 済みの scan を返却する.
+// 入力が一つの run に収まる場合は temporary table に書き出さずに memory 上で sort する.
+type SortPlan struct {
+	txn  domain.Transaction
+	p    domain.Planner
+	sch  *domain.Schema
+	comp *domain.RecordComparator
+}
+
+// NewSortPlan constructs a SortPlan.
+func NewSortPlan(txn domain.Transaction, p domain.Planner, keys []domain.SortKey) *SortPlan {
+	return &SortPlan{
+		txn:  txn,
+		p:    p,
+		sch:  p.Schema(),
+		comp: domain.NewRecordComparator(keys),
+	}
+}
+
+// Open opens scanner.
+func (sp *SortPlan) Open() (domain.Scanner, error) {
+	src, err := sp.p.Open()
+	if err != nil {
+		return nil, errors.Err(err, "Open")
+	}
+
+	runs, recs, err := sp.splitIntoRuns(src)
+	src.Close()
+	if err != nil {
+		return nil, errors.Err(err, "splitIntoRuns")
+	}
+
+	if len(runs) == 0 {
+		sp.sortRecords(recs)
+
+		return domain.NewMemorySortScan(sp.sch, recs), nil
+	}
+
+	for len(runs) > sp.mergeWidth() {
+		runs, err = sp.doMergeIteration(runs)
+		if err != nil {
+			return nil, errors.Err(err, "doMergeIteration")
+		}
+	}
+
+	ss, err := domain.NewSortScan(runs, sp.comp)
+	if err != nil {
+		dropTempTables(runs)
+
+		return nil, errors.Err(err, "NewSortScan")
+	}
+
+	return ss, nil
+}
+
+// EstNumBlocks estimates the number of block access.
+// sort 済みの temporary table を読む cost だけを見積もる. sort 自体の cost は含めない.
+func (sp *SortPlan) EstNumBlocks() int {
+	layout := domain.NewLayout(sp.sch)
+	rpb := int(sp.txn.BlockSize()) / int(layout.SlotSize())
+	if rpb == 0 {
+		rpb = 1
+	}
+
+	return (sp.p.EstNumRecord() + rpb - 1) / rpb
+}
+
+// EstNumRecord estimates the number of record access.
+func (sp *SortPlan) EstNumRecord() int {
+	return sp.p.EstNumRecord()
+}
+
+// EstDistinctVals estimates the number of distinct value at given fldName.
+func (sp *SortPlan) EstDistinctVals(fldName domain.FieldName) int {
+	return sp.p.EstDistinctVals(fldName)
+}
+
+// Schema returns schema of sorted records.
+func (sp *SortPlan) Schema() *domain.Schema {
+	return sp.sch
+}
+
+// runSize returns the number of records sorted in memory at once.
+// 利用可能な buffer の半分に収まる分の record を一つの run とする.
+func (sp *SortPlan) runSize() int {
+	layout := domain.NewLayout(sp.sch)
+	rpb := int(sp.txn.BlockSize()) / int(layout.SlotSize())
+
+	return math.Max(1, sp.txn.Available()/2) * math.Max(1, rpb)
+}
+
+// mergeWidth returns the number of runs merged at once.
+// merge 中はそれぞれの run が buffer を一つずつ pin する.
+func (sp *SortPlan) mergeWidth() int {
+	return math.Max(2, sp.txn.Available()/2)
+}
+
+// splitIntoRuns writes the records of src into sorted runs.
+// 全ての record が一つの run に収まる場合は run を作らずに record をそのまま返す.
+func (sp *SortPlan) splitIntoRuns(src domain.Scanner) ([]*domain.TempTable, []map[domain.FieldName]domain.Constant, error) {
+	runs := make([]*domain.TempTable, 0)
+	if err := src.BeforeFirst(); err != nil {
+		return nil, nil, errors.Err(err, "BeforeFirst")
+	}
+
+	size := sp.runSize()
+	recs := make([]map[domain.FieldName]domain.Constant, 0, size)
+	for src.HasNext() {
+		rec := make(map[domain.FieldName]domain.Constant)
+		for _, fld := range sp.sch.Fields() {
+			val, err := src.GetVal(fld)
+			if err != nil {
+				dropTempTables(runs)
+
+				return nil, nil, errors.Err(err, "GetVal")
+			}
+			rec[fld] = val
+		}
+
+		// run が一杯になった時点で次の record があれば書き出す.
+		if len(recs) >= size {
+			run, err := sp.writeRun(recs)
+			if err != nil {
+				dropTempTables(runs)
+
+				return nil, nil, errors.Err(err, "writeRun")
+			}
+			runs = append(runs, run)
+			recs = recs[:0]
+		}
+		recs = append(recs, rec)
+	}
+	if err := src.Err(); err != nil {
+		dropTempTables(runs)
+
+		return nil, nil, errors.Err(err, "HasNext")
+	}
+
+	if len(runs) == 0 {
+		return nil, recs, nil
+	}
+
+	if len(recs) > 0 {
+		run, err := sp.writeRun(recs)
+		if err != nil {
+			dropTempTables(runs)
+
+			return nil, nil, errors.Err(err, "writeRun")
+		}
+		runs = append(runs, run)
+	}
+
+	return runs, nil, nil
+}
+
+// sortRecords sorts the records in memory.
+func (sp *SortPlan) sortRecords(recs []map[domain.FieldName]domain.Constant) {
+	sort.SliceStable(recs, func(i, j int) bool {
+		return sp.comp.CompareValues(recs[i], recs[j]) < 0
+	})
+}
+
+func (sp *SortPlan) writeRun(recs []map[domain.FieldName]domain.Constant) (*domain.TempTable, error) {
+	sp.sortRecords(recs)
+
+	run := domain.NewTempTable(sp.txn, sp.sch)
+	if err := sp.writeRecords(run, recs); err != nil {
+		dropTempTables([]*domain.TempTable{run})
+
+		return nil, errors.Err(err, "writeRecords")
+	}
+
+	return run, nil
+}
+
+func (sp *SortPlan) writeRecords(run *domain.TempTable, recs []map[domain.FieldName]domain.Constant) error {
+	dest, err := run.Open()
+	if err != nil {
+		return errors.Err(err, "Open")
+	}
+	defer dest.Close()
+
+	for _, rec := range recs {
+		if err := dest.AdvanceNextInsertSlotID(); err != nil {
+			return errors.Err(err, "AdvanceNextInsertSlotID")
+		}
+		for _, fld := range sp.sch.Fields() {
+			if err := dest.SetVal(fld, rec[fld]); err != nil {
+				return errors.Err(err, "SetVal")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (sp *SortPlan) doMergeIteration(runs []*domain.TempTable) ([]*domain.TempTable, error) {
+	width := sp.mergeWidth()
+	result := make([]*domain.TempTable, 0, (len(runs)+width-1)/width)
+	for len(runs) > 0 {
+		n := width
+		if n > len(runs) {
+			n = len(runs)
+		}
+
+		run, err := sp.mergeRuns(runs[:n])
+		if err != nil {
+			dropTempTables(result)
+			dropTempTables(runs[n:])
+
+			return nil, errors.Err(err, "mergeRuns")
+		}
+		result = append(result, run)
+		runs = runs[n:]
+	}
+
+	return result, nil
+}
+
+// mergeRuns merges the runs into a new run.
+// merge した run は SortScan を閉じる時に drop される.
+func (sp *SortPlan) mergeRuns(runs []*domain.TempTable) (*domain.TempTable, error) {
+	src, err := domain.NewSortScan(runs, sp.comp)
+	if err != nil {
+		dropTempTables(runs)
+
+		return nil, errors.Err(err, "NewSortScan")
+	}
+	defer src.Close()
+
+	result := domain.NewTempTable(sp.txn, sp.sch)
+	if err := sp.copyRecords(src, result); err != nil {
+		dropTempTables([]*domain.TempTable{result})
+
+		return nil, errors.Err(err, "copyRecords")
+	}
+
+	return result, nil
+}
+
+func (sp *SortPlan) copyRecords(src domain.Scanner, run *domain.TempTable) error {
+	dest, err := run.Open()
+	if err != nil {
+		return errors.Err(err, "Open")
+	}
+	defer dest.Close()
+
+	for src.HasNext() {
+		if err := dest.AdvanceNextInsertSlotID(); err != nil {
+			return errors.Err(err, "AdvanceNextInsertSlotID")
+		}
+		for _, fld := range sp.sch.Fields() {
+			val, err := src.GetVal(fld)
+			if err != nil {
+				return errors.Err(err, "GetVal")
+			}
+			if err := dest.SetVal(fld, val); err != nil {
+				return errors.Err(err, "SetVal")
+			}
+		}
+	}
+	if err := src.Err(); err != nil {
+		return errors.Err(err, "HasNext")
+	}
+
+	return nil
+}
+
+// dropTempTables drops the temporary tables which are no longer used.
+// 既に失敗している処理の後始末なので, drop の error は無視する.
+func dropTempTables(runs []*domain.TempTable) {
+	for _, run := range runs {
+		_ = run.Drop()
+	}
+}
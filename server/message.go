@@ -1,6 +1,11 @@
 package server
 
-import "encoding/binary"
+import (
+	"encoding/binary"
+
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+)
 
 // ref: https://www.postgresql.org/docs/14/protocol-message-formats.html
 
@@ -54,6 +59,61 @@ func makeParameterStatusMsg(param, value string) []byte {
 	return makeMsg('S', body)
 }
 
+// sqlState returns SQLSTATE code corresponding to err.
+// ref: https://www.postgresql.org/docs/14/errcodes-appendix.html
+func sqlState(err error) string {
+	var uerr *domain.UniqueViolationError
+	if errors.As(err, &uerr) {
+		return "23505" // unique_violation
+	}
+
+	var ferr *domain.ForeignKeyViolationError
+	if errors.As(err, &ferr) {
+		return "23503" // foreign_key_violation
+	}
+
+	var nerr *domain.NotNullViolationError
+	if errors.As(err, &nerr) {
+		return "23502" // not_null_violation
+	}
+
+	var cerr *domain.CheckViolationError
+	if errors.As(err, &cerr) {
+		return "23514" // check_violation
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrTableNotFound), errors.Is(err, domain.ErrViewNotFound):
+		return "42P01" // undefined_table
+	case errors.Is(err, domain.ErrIndexNotFound), errors.Is(err, domain.ErrUnknownRecordFormat), errors.Is(err, domain.ErrNotIndexable):
+		return "42704" // undefined_object
+	case errors.Is(err, domain.ErrDependentObjectsExist):
+		return "2BP01" // dependent_objects_still_exist
+	case errors.Is(err, domain.ErrFieldNotFound):
+		return "42703" // undefined_column
+	case errors.Is(err, domain.ErrDuplicateField):
+		return "42701" // duplicate_column
+	case errors.Is(err, domain.ErrIndexExists):
+		return "42P07" // duplicate_table
+	case errors.Is(err, domain.ErrInvalidBytes), errors.Is(err, domain.ErrInvalidNumeric):
+		return "22P02" // invalid_text_representation
+	case errors.Is(err, domain.ErrInvalidNumericType):
+		return "22023" // invalid_parameter_value
+	case errors.Is(err, domain.ErrNumericOutOfRange):
+		return "22003" // numeric_value_out_of_range
+	case errors.Is(err, domain.ErrInvalidDatetime):
+		return "22007" // invalid_datetime_format
+	case errors.Is(err, domain.ErrDatetimeOutOfRange):
+		return "22008" // datetime_field_overflow
+	case errors.Is(err, domain.ErrRecordTooLarge), errors.Is(err, domain.ErrPageFull):
+		return "54000" // program_limit_exceeded
+	case errors.Is(err, domain.ErrSerializationFailure):
+		return "40001" // serialization_failure
+	}
+
+	return "XX000" // internal_error
+}
+
 func makeErrorMsg(err error) []byte {
 	const errMsgEnd = 0x00
 	errMsg := err.Error()
@@ -68,6 +128,10 @@ func makeErrorMsg(err error) []byte {
 	body = append(body, []byte("ERROR")...)
 	body = append(body, nullEnd)
 
+	body = append(body, 'C') // SQLSTATE code
+	body = append(body, []byte(sqlState(err))...)
+	body = append(body, nullEnd)
+
 	body = append(body, 'M') // message
 	body = append(body, []byte(errMsg)...)
 	body = append(body, nullEnd)
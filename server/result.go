@@ -13,6 +13,61 @@ type Result struct {
 	typ     ResultType
 	records [][]any
 	fields  []string
+	types   []domain.FieldType
+}
+
+// pgType is a PostgreSQL data type sent in RowDescription.
+type pgType struct {
+	oid  uint32
+	size int16
+}
+
+// toPgType returns the PostgreSQL type corresponding to typ.
+// ref: https://github.com/postgres/postgres/blob/REL_14_STABLE/src/include/catalog/pg_type.dat
+func toPgType(typ domain.FieldType) pgType {
+	switch typ {
+	case domain.Int16FieldType:
+		return pgType{oid: 21, size: 2} // int2
+	case domain.Int32FieldType:
+		return pgType{oid: 23, size: 4} // int4
+	case domain.Int64FieldType:
+		return pgType{oid: 20, size: 8} // int8
+	case domain.BoolFieldType:
+		return pgType{oid: 16, size: 1} // bool
+	case domain.Float32FieldType:
+		return pgType{oid: 700, size: 4} // float4
+	case domain.Float64FieldType:
+		return pgType{oid: 701, size: 8} // float8
+	case domain.DateFieldType:
+		return pgType{oid: 1082, size: 4} // date
+	case domain.TimestampFieldType:
+		return pgType{oid: 1114, size: 8} // timestamp
+	case domain.TimestampTzFieldType:
+		return pgType{oid: 1184, size: 8} // timestamptz
+	case domain.IntervalFieldType:
+		return pgType{oid: 1186, size: 16} // interval
+	case domain.NumericFieldType:
+		return pgType{oid: 1700, size: -1} // numeric
+	case domain.TextFieldType:
+		return pgType{oid: 25, size: -1} // text
+	case domain.BytesFieldType:
+		return pgType{oid: 17, size: -1} // bytea
+	}
+
+	return pgType{oid: 1043, size: -1} // varchar
+}
+
+// textValue returns the text format of val.
+func textValue(val any) string {
+	if c, ok := val.(domain.Constant); ok && c.Type() == domain.BoolFieldType {
+		if b, _ := c.AsVal().(bool); b {
+			return "t"
+		}
+
+		return "f"
+	}
+
+	return fmt.Sprintf("%v", val)
 }
 
 func makeDataRow(rec []any) []byte {
@@ -22,11 +77,13 @@ func makeDataRow(rec []any) []byte {
 	binary.BigEndian.PutUint16(ncb, uint16(nc))
 	dataRow = append(dataRow, ncb...)
 	for _, val := range rec {
+		if c, ok := val.(domain.Constant); ok && c.IsNull() {
+			val = nil
+		}
 		if val == nil {
 			dataRow = append(dataRow, []byte{0xff, 0xff, 0xff, 0xff}...)
 		} else {
-			s := fmt.Sprintf("%v", val)
-			sb := []byte(s)
+			sb := []byte(textValue(val))
 			slen := len(sb)
 			lenByte := make([]byte, payloadBytesLength)
 			binary.BigEndian.PutUint32(lenByte, uint32(slen))
@@ -45,7 +102,7 @@ func makeDataRow(rec []any) []byte {
 	return payload
 }
 
-func makeColDesc(cols []string) []byte {
+func makeColDesc(cols []string, types []domain.FieldType) []byte {
 	payload := make([]byte, 0)
 	n := len(cols)
 	numCols := make([]byte, 2)
@@ -58,10 +115,14 @@ func makeColDesc(cols []string) []byte {
 		payload = append(payload, []byte{0x00, 0x00, 0x40, 0x06}...) // object id
 		idx := make([]byte, 2)
 		binary.BigEndian.PutUint16(idx, uint16(k+1))
-		// For simplicity, send value as varchar type.
+		typ := toPgType(types[k])
+		oid := make([]byte, 4)
+		binary.BigEndian.PutUint32(oid, typ.oid)
+		size := make([]byte, 2)
+		binary.BigEndian.PutUint16(size, uint16(typ.size))
 		payload = append(payload, idx...)                            // col id
-		payload = append(payload, []byte{0x00, 0x00, 0x04, 0x13}...) // data type
-		payload = append(payload, []byte{0xff, 0xff}...)             // data type size
+		payload = append(payload, oid...)                            // data type
+		payload = append(payload, size...)                           // data type size
 		payload = append(payload, []byte{0xff, 0xff, 0xff, 0xff}...) // type modifier
 		payload = append(payload, []byte{0x00, 0x00}...)             // format code
 	}
@@ -81,14 +142,14 @@ func makeDataRows(recs [][]any) []byte {
 // Rows is returned rows.
 type Rows struct {
 	scan   domain.Scanner
-	fields []domain.FieldName
+	schema *domain.Schema
 }
 
 func (cn *Connection) makeResult(rows *Rows) (Result, error) {
 	recs := make([][]any, 0)
 	for rows.scan.HasNext() {
 		rec := make([]any, 0)
-		for _, fld := range rows.fields {
+		for _, fld := range rows.schema.Fields() {
 			v, err := rows.scan.GetVal(fld)
 			if err != nil {
 				return Result{}, errors.Err(err, "GetVal")
@@ -102,13 +163,16 @@ func (cn *Connection) makeResult(rows *Rows) (Result, error) {
 	}
 
 	fields := make([]string, 0)
-	for _, fld := range rows.fields {
+	types := make([]domain.FieldType, 0)
+	for _, fld := range rows.schema.Fields() {
 		fields = append(fields, string(fld))
+		types = append(types, rows.schema.Type(fld))
 	}
 
 	return Result{
 		typ:     queryResult,
 		records: recs,
 		fields:  fields,
+		types:   types,
 	}, nil
 }
@@ -221,8 +221,7 @@ func (cn *Connection) sendReadyForQueryMsg() {
 }
 
 func (cn *Connection) sendResult(res Result) {
-	cols := res.fields
-	header := makeColDesc(cols)
+	header := makeColDesc(res.fields, res.types)
 	cn.conn.Write(header)
 	recs := res.records
 	if len(recs) != 0 {
@@ -282,7 +281,7 @@ func (cn *Connection) handleSelect(query string) (Result, error) {
 		return Result{}, errors.Err(err, "Open")
 	}
 
-	rows := &Rows{scan: scan, fields: p.Schema().Fields()}
+	rows := &Rows{scan: scan, schema: p.Schema()}
 	result, err := cn.makeResult(rows)
 	if err != nil {
 		return Result{}, cn.rollback(txn, err)
@@ -17,11 +17,19 @@ type TransactionCreater struct {
 }
 
 func NewTransactionCreater(blockSize int32, numBuf int) *TransactionCreater {
+	return newTransactionCreater(blockSize, numBuf, tx.TwoPhaseLocking)
+}
+
+func NewMultiVersionTransactionCreater(blockSize int32, numBuf int) *TransactionCreater {
+	return newTransactionCreater(blockSize, numBuf, tx.MultiVersion)
+}
+
+func newTransactionCreater(blockSize int32, numBuf int, cc tx.ConcurrencyControl) *TransactionCreater {
 	dbPath := RandString()
 	factory := NewNonDirectBufferManagerFactory(dbPath, blockSize, numBuf)
 	fileMgr, logMgr, bufMgr := factory.Create()
 
-	cfg := tx.LockTableConfig{LockTimeoutMillisecond: 1000}
+	cfg := tx.LockTableConfig{LockTimeoutMillisecond: 1000, ConcurrencyControl: cc}
 	lt := tx.NewLockTable(cfg)
 	gen := tx.NewNumberGenerator()
 
@@ -44,6 +52,10 @@ func (cr *TransactionCreater) NewTxn() domain.Transaction {
 	return txn
 }
 
+func (cr *TransactionCreater) DBPath() string {
+	return cr.factory.dbPath
+}
+
 func (cr *TransactionCreater) Finish() {
 	cr.factory.Finish()
 }
@@ -77,6 +77,21 @@ func (mr *MockByteBufferMockRecorder) GetInt32(offset interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInt32", reflect.TypeOf((*MockByteBuffer)(nil).GetInt32), offset)
 }
 
+// GetInt64 mocks base method.
+func (m *MockByteBuffer) GetInt64(offset int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInt64", offset)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInt64 indicates an expected call of GetInt64.
+func (mr *MockByteBufferMockRecorder) GetInt64(offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInt64", reflect.TypeOf((*MockByteBuffer)(nil).GetInt64), offset)
+}
+
 // GetString mocks base method.
 func (m *MockByteBuffer) GetString(offset int64) (string, error) {
 	m.ctrl.T.Helper()
@@ -176,6 +191,20 @@ func (mr *MockByteBufferMockRecorder) SetInt32(offset, val interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInt32", reflect.TypeOf((*MockByteBuffer)(nil).SetInt32), offset, val)
 }
 
+// SetInt64 mocks base method.
+func (m *MockByteBuffer) SetInt64(offset, val int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInt64", offset, val)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInt64 indicates an expected call of SetInt64.
+func (mr *MockByteBufferMockRecorder) SetInt64(offset, val interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInt64", reflect.TypeOf((*MockByteBuffer)(nil).SetInt64), offset, val)
+}
+
 // SetString mocks base method.
 func (m *MockByteBuffer) SetString(offset int64, val string) error {
 	m.ctrl.T.Helper()
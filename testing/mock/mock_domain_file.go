@@ -49,6 +49,20 @@ func (mr *MockExplorerMockRecorder) OpenFile(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenFile", reflect.TypeOf((*MockExplorer)(nil).OpenFile), arg0)
 }
 
+// RemoveFile mocks base method.
+func (m *MockExplorer) RemoveFile(arg0 domain.FileName) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFile", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFile indicates an expected call of RemoveFile.
+func (mr *MockExplorerMockRecorder) RemoveFile(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFile", reflect.TypeOf((*MockExplorer)(nil).RemoveFile), arg0)
+}
+
 // MockByteSliceFactory is a mock of ByteSliceFactory interface.
 type MockByteSliceFactory struct {
 	ctrl     *gomock.Controller
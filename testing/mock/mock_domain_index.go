@@ -85,6 +85,20 @@ func (mr *MockIndexerMockRecorder) BeforeFirst(searchKey interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeFirst", reflect.TypeOf((*MockIndexer)(nil).BeforeFirst), searchKey)
 }
 
+// BeforeRange mocks base method.
+func (m *MockIndexer) BeforeRange(r domain.IndexRange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeforeRange", r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BeforeRange indicates an expected call of BeforeRange.
+func (mr *MockIndexerMockRecorder) BeforeRange(r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeforeRange", reflect.TypeOf((*MockIndexer)(nil).BeforeRange), r)
+}
+
 // Close mocks base method.
 func (m *MockIndexer) Close() {
 	m.ctrl.T.Helper()
@@ -204,3 +218,31 @@ func (mr *MockIndexFactoryMockRecorder) Create(arg0, arg1, arg2 interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIndexFactory)(nil).Create), arg0, arg1, arg2)
 }
+
+// FileNames mocks base method.
+func (m *MockIndexFactory) FileNames(arg0 domain.IndexName) []domain.FileName {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FileNames", arg0)
+	ret0, _ := ret[0].([]domain.FileName)
+	return ret0
+}
+
+// FileNames indicates an expected call of FileNames.
+func (mr *MockIndexFactoryMockRecorder) FileNames(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FileNames", reflect.TypeOf((*MockIndexFactory)(nil).FileNames), arg0)
+}
+
+// SupportsRange mocks base method.
+func (m *MockIndexFactory) SupportsRange() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SupportsRange")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SupportsRange indicates an expected call of SupportsRange.
+func (mr *MockIndexFactoryMockRecorder) SupportsRange() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SupportsRange", reflect.TypeOf((*MockIndexFactory)(nil).SupportsRange))
+}
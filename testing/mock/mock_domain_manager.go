@@ -135,6 +135,20 @@ func (mr *MockFileManagerMockRecorder) IsInit() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsInit", reflect.TypeOf((*MockFileManager)(nil).IsInit))
 }
 
+// RemoveFile mocks base method.
+func (m *MockFileManager) RemoveFile(arg0 domain.FileName) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFile", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFile indicates an expected call of RemoveFile.
+func (mr *MockFileManagerMockRecorder) RemoveFile(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFile", reflect.TypeOf((*MockFileManager)(nil).RemoveFile), arg0)
+}
+
 // MockLogManager is a mock of LogManager interface.
 type MockLogManager struct {
 	ctrl     *gomock.Controller
@@ -334,32 +348,60 @@ func (m *MockMetadataManager) EXPECT() *MockMetadataManagerMockRecorder {
 	return m.recorder
 }
 
+// AddField mocks base method.
+func (m *MockMetadataManager) AddField(tblName domain.TableName, fldName domain.FieldName, sch *domain.Schema, txn domain.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddField", tblName, fldName, sch, txn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddField indicates an expected call of AddField.
+func (mr *MockMetadataManagerMockRecorder) AddField(tblName, fldName, sch, txn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddField", reflect.TypeOf((*MockMetadataManager)(nil).AddField), tblName, fldName, sch, txn)
+}
+
+// CreateConstraint mocks base method.
+func (m *MockMetadataManager) CreateConstraint(tblName domain.TableName, c domain.Constraint, txn domain.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateConstraint", tblName, c, txn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateConstraint indicates an expected call of CreateConstraint.
+func (mr *MockMetadataManagerMockRecorder) CreateConstraint(tblName, c, txn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateConstraint", reflect.TypeOf((*MockMetadataManager)(nil).CreateConstraint), tblName, c, txn)
+}
+
 // CreateIndex mocks base method.
-func (m *MockMetadataManager) CreateIndex(idxName domain.IndexName, tblName domain.TableName, fldName domain.FieldName, txn domain.Transaction) error {
+func (m *MockMetadataManager) CreateIndex(idxName domain.IndexName, tblName domain.TableName, fldNames []domain.FieldName, idxType domain.IndexType, txn domain.Transaction) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateIndex", idxName, tblName, fldName, txn)
+	ret := m.ctrl.Call(m, "CreateIndex", idxName, tblName, fldNames, idxType, txn)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateIndex indicates an expected call of CreateIndex.
-func (mr *MockMetadataManagerMockRecorder) CreateIndex(idxName, tblName, fldName, txn interface{}) *gomock.Call {
+func (mr *MockMetadataManagerMockRecorder) CreateIndex(idxName, tblName, fldNames, idxType, txn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIndex", reflect.TypeOf((*MockMetadataManager)(nil).CreateIndex), idxName, tblName, fldName, txn)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIndex", reflect.TypeOf((*MockMetadataManager)(nil).CreateIndex), idxName, tblName, fldNames, idxType, txn)
 }
 
 // CreateTable mocks base method.
-func (m *MockMetadataManager) CreateTable(tblName domain.TableName, sch *domain.Schema, txn domain.Transaction) error {
+func (m *MockMetadataManager) CreateTable(tblName domain.TableName, sch *domain.Schema, format domain.RecordFormat, txn domain.Transaction) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTable", tblName, sch, txn)
+	ret := m.ctrl.Call(m, "CreateTable", tblName, sch, format, txn)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateTable indicates an expected call of CreateTable.
-func (mr *MockMetadataManagerMockRecorder) CreateTable(tblName, sch, txn interface{}) *gomock.Call {
+func (mr *MockMetadataManagerMockRecorder) CreateTable(tblName, sch, format, txn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTable", reflect.TypeOf((*MockMetadataManager)(nil).CreateTable), tblName, sch, txn)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTable", reflect.TypeOf((*MockMetadataManager)(nil).CreateTable), tblName, sch, format, txn)
 }
 
 // CreateView mocks base method.
@@ -376,11 +418,82 @@ func (mr *MockMetadataManagerMockRecorder) CreateView(viewName, viewDef, txn int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateView", reflect.TypeOf((*MockMetadataManager)(nil).CreateView), viewName, viewDef, txn)
 }
 
+// DropField mocks base method.
+func (m *MockMetadataManager) DropField(tblName domain.TableName, fldName domain.FieldName, txn domain.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropField", tblName, fldName, txn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropField indicates an expected call of DropField.
+func (mr *MockMetadataManagerMockRecorder) DropField(tblName, fldName, txn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropField", reflect.TypeOf((*MockMetadataManager)(nil).DropField), tblName, fldName, txn)
+}
+
+// DropIndex mocks base method.
+func (m *MockMetadataManager) DropIndex(idxName domain.IndexName, txn domain.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropIndex", idxName, txn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropIndex indicates an expected call of DropIndex.
+func (mr *MockMetadataManagerMockRecorder) DropIndex(idxName, txn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropIndex", reflect.TypeOf((*MockMetadataManager)(nil).DropIndex), idxName, txn)
+}
+
+// DropTable mocks base method.
+func (m *MockMetadataManager) DropTable(tblName domain.TableName, txn domain.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropTable", tblName, txn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropTable indicates an expected call of DropTable.
+func (mr *MockMetadataManagerMockRecorder) DropTable(tblName, txn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropTable", reflect.TypeOf((*MockMetadataManager)(nil).DropTable), tblName, txn)
+}
+
+// DropView mocks base method.
+func (m *MockMetadataManager) DropView(viewName domain.ViewName, txn domain.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DropView", viewName, txn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DropView indicates an expected call of DropView.
+func (mr *MockMetadataManagerMockRecorder) DropView(viewName, txn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropView", reflect.TypeOf((*MockMetadataManager)(nil).DropView), viewName, txn)
+}
+
+// GetConstraints mocks base method.
+func (m *MockMetadataManager) GetConstraints(tblName domain.TableName, txn domain.Transaction) ([]domain.Constraint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConstraints", tblName, txn)
+	ret0, _ := ret[0].([]domain.Constraint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConstraints indicates an expected call of GetConstraints.
+func (mr *MockMetadataManagerMockRecorder) GetConstraints(tblName, txn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConstraints", reflect.TypeOf((*MockMetadataManager)(nil).GetConstraints), tblName, txn)
+}
+
 // GetIndexInfo mocks base method.
-func (m *MockMetadataManager) GetIndexInfo(tblName domain.TableName, txn domain.Transaction) (map[domain.FieldName]*domain.IndexInfo, error) {
+func (m *MockMetadataManager) GetIndexInfo(tblName domain.TableName, txn domain.Transaction) ([]*domain.IndexInfo, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetIndexInfo", tblName, txn)
-	ret0, _ := ret[0].(map[domain.FieldName]*domain.IndexInfo)
+	ret0, _ := ret[0].([]*domain.IndexInfo)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -391,6 +504,21 @@ func (mr *MockMetadataManagerMockRecorder) GetIndexInfo(tblName, txn interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIndexInfo", reflect.TypeOf((*MockMetadataManager)(nil).GetIndexInfo), tblName, txn)
 }
 
+// GetReferencingTables mocks base method.
+func (m *MockMetadataManager) GetReferencingTables(tblName domain.TableName, txn domain.Transaction) ([]domain.TableName, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReferencingTables", tblName, txn)
+	ret0, _ := ret[0].([]domain.TableName)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReferencingTables indicates an expected call of GetReferencingTables.
+func (mr *MockMetadataManagerMockRecorder) GetReferencingTables(tblName, txn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReferencingTables", reflect.TypeOf((*MockMetadataManager)(nil).GetReferencingTables), tblName, txn)
+}
+
 // GetStatInfo mocks base method.
 func (m *MockMetadataManager) GetStatInfo(tblName domain.TableName, layout *domain.Layout, txn domain.Transaction) (domain.StatInfo, error) {
 	m.ctrl.T.Helper()
@@ -436,6 +564,34 @@ func (mr *MockMetadataManagerMockRecorder) GetViewDef(viewName, txn interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetViewDef", reflect.TypeOf((*MockMetadataManager)(nil).GetViewDef), viewName, txn)
 }
 
+// RenameField mocks base method.
+func (m *MockMetadataManager) RenameField(tblName domain.TableName, oldName, newName domain.FieldName, txn domain.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameField", tblName, oldName, newName, txn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameField indicates an expected call of RenameField.
+func (mr *MockMetadataManagerMockRecorder) RenameField(tblName, oldName, newName, txn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameField", reflect.TypeOf((*MockMetadataManager)(nil).RenameField), tblName, oldName, newName, txn)
+}
+
+// RenameTable mocks base method.
+func (m *MockMetadataManager) RenameTable(tblName, newName domain.TableName, txn domain.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameTable", tblName, newName, txn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameTable indicates an expected call of RenameTable.
+func (mr *MockMetadataManagerMockRecorder) RenameTable(tblName, newName, txn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameTable", reflect.TypeOf((*MockMetadataManager)(nil).RenameTable), tblName, newName, txn)
+}
+
 // MockBufferPoolManager is a mock of BufferPoolManager interface.
 type MockBufferPoolManager struct {
 	ctrl     *gomock.Controller
@@ -473,6 +629,18 @@ func (mr *MockBufferPoolManagerMockRecorder) Available() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Available", reflect.TypeOf((*MockBufferPoolManager)(nil).Available))
 }
 
+// DiscardFile mocks base method.
+func (m *MockBufferPoolManager) DiscardFile(arg0 domain.FileName) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DiscardFile", arg0)
+}
+
+// DiscardFile indicates an expected call of DiscardFile.
+func (mr *MockBufferPoolManagerMockRecorder) DiscardFile(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscardFile", reflect.TypeOf((*MockBufferPoolManager)(nil).DiscardFile), arg0)
+}
+
 // FlushAll mocks base method.
 func (m *MockBufferPoolManager) FlushAll(txnum domain.TransactionNumber) error {
 	m.ctrl.T.Helper()
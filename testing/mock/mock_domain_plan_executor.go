@@ -166,6 +166,21 @@ func (m *MockUpdateExecutor) EXPECT() *MockUpdateExecutorMockRecorder {
 	return m.recorder
 }
 
+// ExecuteAlterTable mocks base method.
+func (m *MockUpdateExecutor) ExecuteAlterTable(arg0 *domain.AlterTableData, arg1 domain.Transaction) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteAlterTable", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteAlterTable indicates an expected call of ExecuteAlterTable.
+func (mr *MockUpdateExecutorMockRecorder) ExecuteAlterTable(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteAlterTable", reflect.TypeOf((*MockUpdateExecutor)(nil).ExecuteAlterTable), arg0, arg1)
+}
+
 // ExecuteCreateIndex mocks base method.
 func (m *MockUpdateExecutor) ExecuteCreateIndex(arg0 *domain.CreateIndexData, arg1 domain.Transaction) (int, error) {
 	m.ctrl.T.Helper()
@@ -226,6 +241,51 @@ func (mr *MockUpdateExecutorMockRecorder) ExecuteDelete(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteDelete", reflect.TypeOf((*MockUpdateExecutor)(nil).ExecuteDelete), arg0, arg1)
 }
 
+// ExecuteDropIndex mocks base method.
+func (m *MockUpdateExecutor) ExecuteDropIndex(arg0 *domain.DropIndexData, arg1 domain.Transaction) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteDropIndex", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteDropIndex indicates an expected call of ExecuteDropIndex.
+func (mr *MockUpdateExecutorMockRecorder) ExecuteDropIndex(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteDropIndex", reflect.TypeOf((*MockUpdateExecutor)(nil).ExecuteDropIndex), arg0, arg1)
+}
+
+// ExecuteDropTable mocks base method.
+func (m *MockUpdateExecutor) ExecuteDropTable(arg0 *domain.DropTableData, arg1 domain.Transaction) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteDropTable", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteDropTable indicates an expected call of ExecuteDropTable.
+func (mr *MockUpdateExecutorMockRecorder) ExecuteDropTable(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteDropTable", reflect.TypeOf((*MockUpdateExecutor)(nil).ExecuteDropTable), arg0, arg1)
+}
+
+// ExecuteDropView mocks base method.
+func (m *MockUpdateExecutor) ExecuteDropView(arg0 *domain.DropViewData, arg1 domain.Transaction) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExecuteDropView", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteDropView indicates an expected call of ExecuteDropView.
+func (mr *MockUpdateExecutorMockRecorder) ExecuteDropView(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteDropView", reflect.TypeOf((*MockUpdateExecutor)(nil).ExecuteDropView), arg0, arg1)
+}
+
 // ExecuteInsert mocks base method.
 func (m *MockUpdateExecutor) ExecuteInsert(arg0 *domain.InsertData, arg1 domain.Transaction) (int, error) {
 	m.ctrl.T.Helper()
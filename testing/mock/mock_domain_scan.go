@@ -339,6 +339,22 @@ func (mr *MockUpdateScannerMockRecorder) RecordID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordID", reflect.TypeOf((*MockUpdateScanner)(nil).RecordID))
 }
 
+// SaveVersion mocks base method.
+func (m *MockUpdateScanner) SaveVersion() (domain.RecordID, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveVersion")
+	ret0, _ := ret[0].(domain.RecordID)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SaveVersion indicates an expected call of SaveVersion.
+func (mr *MockUpdateScannerMockRecorder) SaveVersion() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveVersion", reflect.TypeOf((*MockUpdateScanner)(nil).SaveVersion))
+}
+
 // SetInt32 mocks base method.
 func (m *MockUpdateScanner) SetInt32(arg0 domain.FieldName, arg1 int32) error {
 	m.ctrl.T.Helper()
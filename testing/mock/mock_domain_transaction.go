@@ -34,6 +34,20 @@ func (m *MockTransaction) EXPECT() *MockTransactionMockRecorder {
 	return m.recorder
 }
 
+// Available mocks base method.
+func (m *MockTransaction) Available() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Available")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// Available indicates an expected call of Available.
+func (mr *MockTransactionMockRecorder) Available() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Available", reflect.TypeOf((*MockTransaction)(nil).Available))
+}
+
 // BlockLength mocks base method.
 func (m *MockTransaction) BlockLength(arg0 domain.FileName) (int32, error) {
 	m.ctrl.T.Helper()
@@ -92,6 +106,18 @@ func (mr *MockTransactionMockRecorder) ExtendFile(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendFile", reflect.TypeOf((*MockTransaction)(nil).ExtendFile), arg0)
 }
 
+// ForUpdate mocks base method.
+func (m *MockTransaction) ForUpdate(arg0 bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ForUpdate", arg0)
+}
+
+// ForUpdate indicates an expected call of ForUpdate.
+func (mr *MockTransactionMockRecorder) ForUpdate(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForUpdate", reflect.TypeOf((*MockTransaction)(nil).ForUpdate), arg0)
+}
+
 // GetInt32 mocks base method.
 func (m *MockTransaction) GetInt32(blk domain.Block, offset int64) (int32, error) {
 	m.ctrl.T.Helper()
@@ -107,6 +133,21 @@ func (mr *MockTransactionMockRecorder) GetInt32(blk, offset interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInt32", reflect.TypeOf((*MockTransaction)(nil).GetInt32), blk, offset)
 }
 
+// GetInt64 mocks base method.
+func (m *MockTransaction) GetInt64(blk domain.Block, offset int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInt64", blk, offset)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInt64 indicates an expected call of GetInt64.
+func (mr *MockTransactionMockRecorder) GetInt64(blk, offset interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInt64", reflect.TypeOf((*MockTransaction)(nil).GetInt64), blk, offset)
+}
+
 // GetString mocks base method.
 func (m *MockTransaction) GetString(blk domain.Block, offset int64) (string, error) {
 	m.ctrl.T.Helper()
@@ -122,6 +163,34 @@ func (mr *MockTransactionMockRecorder) GetString(blk, offset interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetString", reflect.TypeOf((*MockTransaction)(nil).GetString), blk, offset)
 }
 
+// GetTxNum mocks base method.
+func (m *MockTransaction) GetTxNum() domain.TransactionNumber {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTxNum")
+	ret0, _ := ret[0].(domain.TransactionNumber)
+	return ret0
+}
+
+// GetTxNum indicates an expected call of GetTxNum.
+func (mr *MockTransactionMockRecorder) GetTxNum() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTxNum", reflect.TypeOf((*MockTransaction)(nil).GetTxNum))
+}
+
+// IsVisibleToAll mocks base method.
+func (m *MockTransaction) IsVisibleToAll(arg0 domain.TransactionNumber) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsVisibleToAll", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsVisibleToAll indicates an expected call of IsVisibleToAll.
+func (mr *MockTransactionMockRecorder) IsVisibleToAll(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsVisibleToAll", reflect.TypeOf((*MockTransaction)(nil).IsVisibleToAll), arg0)
+}
+
 // Pin mocks base method.
 func (m *MockTransaction) Pin(arg0 domain.Block) error {
 	m.ctrl.T.Helper()
@@ -150,6 +219,34 @@ func (mr *MockTransactionMockRecorder) Recover() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Recover", reflect.TypeOf((*MockTransaction)(nil).Recover))
 }
 
+// RemoveFile mocks base method.
+func (m *MockTransaction) RemoveFile(arg0 domain.FileName) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFile", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFile indicates an expected call of RemoveFile.
+func (mr *MockTransactionMockRecorder) RemoveFile(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFile", reflect.TypeOf((*MockTransaction)(nil).RemoveFile), arg0)
+}
+
+// RemoveFileOnRollback mocks base method.
+func (m *MockTransaction) RemoveFileOnRollback(arg0 domain.FileName) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFileOnRollback", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFileOnRollback indicates an expected call of RemoveFileOnRollback.
+func (mr *MockTransactionMockRecorder) RemoveFileOnRollback(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFileOnRollback", reflect.TypeOf((*MockTransaction)(nil).RemoveFileOnRollback), arg0)
+}
+
 // Rollback mocks base method.
 func (m *MockTransaction) Rollback() error {
 	m.ctrl.T.Helper()
@@ -164,6 +261,20 @@ func (mr *MockTransactionMockRecorder) Rollback() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockTransaction)(nil).Rollback))
 }
 
+// SLock mocks base method.
+func (m *MockTransaction) SLock(arg0 domain.Block) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SLock", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SLock indicates an expected call of SLock.
+func (mr *MockTransactionMockRecorder) SLock(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SLock", reflect.TypeOf((*MockTransaction)(nil).SLock), arg0)
+}
+
 // SetInt32 mocks base method.
 func (m *MockTransaction) SetInt32(blk domain.Block, offset int64, val int32, writeLog bool) error {
 	m.ctrl.T.Helper()
@@ -178,6 +289,20 @@ func (mr *MockTransactionMockRecorder) SetInt32(blk, offset, val, writeLog inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInt32", reflect.TypeOf((*MockTransaction)(nil).SetInt32), blk, offset, val, writeLog)
 }
 
+// SetInt64 mocks base method.
+func (m *MockTransaction) SetInt64(blk domain.Block, offset, val int64, writeLog bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInt64", blk, offset, val, writeLog)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInt64 indicates an expected call of SetInt64.
+func (mr *MockTransactionMockRecorder) SetInt64(blk, offset, val, writeLog interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInt64", reflect.TypeOf((*MockTransaction)(nil).SetInt64), blk, offset, val, writeLog)
+}
+
 // SetString mocks base method.
 func (m *MockTransaction) SetString(blk domain.Block, offset int64, val string, writeLog bool) error {
 	m.ctrl.T.Helper()
@@ -192,6 +317,20 @@ func (mr *MockTransactionMockRecorder) SetString(blk, offset, val, writeLog inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetString", reflect.TypeOf((*MockTransaction)(nil).SetString), blk, offset, val, writeLog)
 }
 
+// Snapshot mocks base method.
+func (m *MockTransaction) Snapshot() *domain.Snapshot {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot")
+	ret0, _ := ret[0].(*domain.Snapshot)
+	return ret0
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockTransactionMockRecorder) Snapshot() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockTransaction)(nil).Snapshot))
+}
+
 // Unpin mocks base method.
 func (m *MockTransaction) Unpin(arg0 domain.Block) {
 	m.ctrl.T.Helper()
@@ -204,6 +343,20 @@ func (mr *MockTransactionMockRecorder) Unpin(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unpin", reflect.TypeOf((*MockTransaction)(nil).Unpin), arg0)
 }
 
+// XLock mocks base method.
+func (m *MockTransaction) XLock(arg0 domain.Block) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "XLock", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// XLock indicates an expected call of XLock.
+func (mr *MockTransactionMockRecorder) XLock(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "XLock", reflect.TypeOf((*MockTransaction)(nil).XLock), arg0)
+}
+
 // MockTxNumberGenerator is a mock of TxNumberGenerator interface.
 type MockTxNumberGenerator struct {
 	ctrl     *gomock.Controller
@@ -63,6 +63,20 @@ func (mr *MockTxVisitorMockRecorder) UndoSetInt32(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndoSetInt32", reflect.TypeOf((*MockTxVisitor)(nil).UndoSetInt32), arg0)
 }
 
+// UndoSetInt64 mocks base method.
+func (m *MockTxVisitor) UndoSetInt64(arg0 *logrecord.SetInt64Record) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndoSetInt64", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UndoSetInt64 indicates an expected call of UndoSetInt64.
+func (mr *MockTxVisitorMockRecorder) UndoSetInt64(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndoSetInt64", reflect.TypeOf((*MockTxVisitor)(nil).UndoSetInt64), arg0)
+}
+
 // UndoSetString mocks base method.
 func (m *MockTxVisitor) UndoSetString(arg0 *logrecord.SetStringRecord) error {
 	m.ctrl.T.Helper()
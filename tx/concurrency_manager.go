@@ -62,6 +62,11 @@ func (conMgr *ConcurrencyManager) XLock(blk domain.Block) error {
 	return nil
 }
 
+// HasXLock checks whether the exclusive lock of the blk is taken.
+func (conMgr *ConcurrencyManager) HasXLock(blk domain.Block) bool {
+	return conMgr.locks[blk] == Exclusive
+}
+
 // Release releases all taken locks.
 func (conMgr *ConcurrencyManager) Release() {
 	for blk := range conMgr.locks {
@@ -2,6 +2,8 @@ package tx
 
 import (
 	"errors"
+	"fmt"
+	"os"
 	"sync"
 	"time"
 
@@ -11,20 +13,52 @@ import (
 // ErrTransactionTimeoutExceeded is an error that means exceeding timeout.
 var ErrTransactionTimeoutExceeded = errors.New("transaction timeout exceeded")
 
+// ErrUnknownConcurrencyControl is an error that means the concurrency control is not supported.
+var ErrUnknownConcurrencyControl = errors.New("unknown concurrency control")
+
 type result struct {
 	err error
 }
 
+// ConcurrencyControl is a kind of concurrency control.
+type ConcurrencyControl string
+
+const (
+	// TwoPhaseLocking takes a shared lock for reading and an exclusive lock for writing.
+	TwoPhaseLocking ConcurrencyControl = "2pl"
+
+	// MultiVersion reads the snapshot of the database without locks.
+	// 書き込みは exclusive lock を取り, 同じ record を先に更新した transaction が勝つ.
+	MultiVersion ConcurrencyControl = "mvcc"
+)
+
 // LockTableConfig is configuration for LockTable.
 type LockTableConfig struct {
 	LockTimeoutMillisecond int
+	ConcurrencyControl     ConcurrencyControl
 }
 
 // NewLockTableConfig constructs a LockTableConfig.
-func NewLockTableConfig() LockTableConfig {
+// concurrency control は環境変数 SIMPLEDB_CONCURRENCY_CONTROL で選択できる.
+// 知らない値が指定された場合は黙って 2PL にせず error を返す.
+func NewLockTableConfig() (LockTableConfig, error) {
 	const timeout = 10000
 
-	return LockTableConfig{LockTimeoutMillisecond: timeout}
+	cfg := LockTableConfig{
+		LockTimeoutMillisecond: timeout,
+		ConcurrencyControl:     TwoPhaseLocking,
+	}
+
+	cc := ConcurrencyControl(os.Getenv("SIMPLEDB_CONCURRENCY_CONTROL"))
+	switch cc {
+	case "":
+	case TwoPhaseLocking, MultiVersion:
+		cfg.ConcurrencyControl = cc
+	default:
+		return LockTableConfig{}, fmt.Errorf("%w: %v", ErrUnknownConcurrencyControl, cc)
+	}
+
+	return cfg, nil
 }
 
 // LockTable manages locked Block which used by transaction.
@@ -33,6 +67,7 @@ type LockTable struct {
 	cond               *sync.Cond
 	locks              map[domain.Block]int
 	timeoutMillisecond time.Duration
+	versions           *versionTable
 }
 
 // NewLockTable constructs LockTable.
@@ -40,11 +75,17 @@ func NewLockTable(cfg LockTableConfig) *LockTable {
 	mu := &sync.Mutex{}
 	cond := sync.NewCond(mu)
 
+	var versions *versionTable
+	if cfg.ConcurrencyControl == MultiVersion {
+		versions = newVersionTable()
+	}
+
 	return &LockTable{
 		mu:                 mu,
 		cond:               cond,
 		locks:              make(map[domain.Block]int),
 		timeoutMillisecond: time.Duration(cfg.LockTimeoutMillisecond) * time.Millisecond,
+		versions:           versions,
 	}
 }
 
@@ -164,3 +164,25 @@ func TestLockTable_Lock(t *testing.T) {
 		wg.Wait()
 	})
 }
+
+func TestNewLockTableConfig(t *testing.T) {
+	tests := []struct {
+		env      string
+		expected tx.ConcurrencyControl
+	}{
+		{env: "", expected: tx.TwoPhaseLocking},
+		{env: "2pl", expected: tx.TwoPhaseLocking},
+		{env: "mvcc", expected: tx.MultiVersion},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("SIMPLEDB_CONCURRENCY_CONTROL", tt.env)
+		cfg, err := tx.NewLockTableConfig()
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, cfg.ConcurrencyControl)
+	}
+
+	t.Setenv("SIMPLEDB_CONCURRENCY_CONTROL", "MVCC2")
+	_, err := tx.NewLockTableConfig()
+	require.ErrorIs(t, err, tx.ErrUnknownConcurrencyControl)
+}
@@ -366,6 +366,85 @@ func (x *SetStringRecord) GetVal() string {
 	return ""
 }
 
+type SetInt64Record struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Filename    string `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	Txnum       int32  `protobuf:"varint,2,opt,name=txnum,proto3" json:"txnum,omitempty"`
+	BlockNumber int32  `protobuf:"varint,3,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	Offset      int64  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	Val         int64  `protobuf:"varint,5,opt,name=val,proto3" json:"val,omitempty"`
+}
+
+func (x *SetInt64Record) Reset() {
+	*x = SetInt64Record{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_tx_logrecord_protofile_record_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SetInt64Record) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetInt64Record) ProtoMessage() {}
+
+func (x *SetInt64Record) ProtoReflect() protoreflect.Message {
+	mi := &file_tx_logrecord_protofile_record_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetInt64Record.ProtoReflect.Descriptor instead.
+func (*SetInt64Record) Descriptor() ([]byte, []int) {
+	return file_tx_logrecord_protofile_record_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SetInt64Record) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *SetInt64Record) GetTxnum() int32 {
+	if x != nil {
+		return x.Txnum
+	}
+	return 0
+}
+
+func (x *SetInt64Record) GetBlockNumber() int32 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *SetInt64Record) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *SetInt64Record) GetVal() int64 {
+	if x != nil {
+		return x.Val
+	}
+	return 0
+}
+
 var File_tx_logrecord_protofile_record_proto protoreflect.FileDescriptor
 
 var file_tx_logrecord_protofile_record_proto_rawDesc = []byte{
@@ -400,8 +479,17 @@ var file_tx_logrecord_protofile_record_proto_rawDesc = []byte{
 	0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65,
 	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x12,
 	0x10, 0x0a, 0x03, 0x76, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x76, 0x61,
-	0x6c, 0x42, 0x0c, 0x5a, 0x0a, 0x2e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x62,
-	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6c, 0x22, 0x8f, 0x01, 0x0a, 0x0e, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x74, 0x36, 0x34, 0x52, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x74, 0x78, 0x6e, 0x75, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x74, 0x78, 0x6e, 0x75, 0x6d, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f,
+	0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x62, 0x6c,
+	0x6f, 0x63, 0x6b, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66,
+	0x73, 0x65, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65,
+	0x74, 0x12, 0x10, 0x0a, 0x03, 0x76, 0x61, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x03,
+	0x76, 0x61, 0x6c, 0x42, 0x0c, 0x5a, 0x0a, 0x2e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -416,7 +504,7 @@ func file_tx_logrecord_protofile_record_proto_rawDescGZIP() []byte {
 	return file_tx_logrecord_protofile_record_proto_rawDescData
 }
 
-var file_tx_logrecord_protofile_record_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_tx_logrecord_protofile_record_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_tx_logrecord_protofile_record_proto_goTypes = []interface{}{
 	(*StartRecord)(nil),      // 0: protobuf.StartRecord
 	(*CommitRecord)(nil),     // 1: protobuf.CommitRecord
@@ -424,6 +512,7 @@ var file_tx_logrecord_protofile_record_proto_goTypes = []interface{}{
 	(*CheckpointRecord)(nil), // 3: protobuf.CheckpointRecord
 	(*SetInt32Record)(nil),   // 4: protobuf.SetInt32Record
 	(*SetStringRecord)(nil),  // 5: protobuf.SetStringRecord
+	(*SetInt64Record)(nil),   // 6: protobuf.SetInt64Record
 }
 var file_tx_logrecord_protofile_record_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -511,6 +600,18 @@ func file_tx_logrecord_protofile_record_proto_init() {
 				return nil
 			}
 		}
+		file_tx_logrecord_protofile_record_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetInt64Record); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -518,7 +619,7 @@ func file_tx_logrecord_protofile_record_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_tx_logrecord_protofile_record_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   7,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
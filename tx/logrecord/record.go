@@ -33,6 +33,9 @@ const (
 
 	// SetString is set string record type.
 	SetString
+
+	// SetInt64 is set int64 record type.
+	SetInt64
 )
 
 // TxVisitor is an interface of visitor.
@@ -41,6 +44,7 @@ type TxVisitor interface {
 	Unpin(domain.Block)
 	UndoSetInt32(*SetInt32Record) error
 	UndoSetString(*SetStringRecord) error
+	UndoSetInt64(*SetInt64Record) error
 }
 
 // LogRecorder is an interface of log record.
@@ -327,3 +331,66 @@ func (rec *SetStringRecord) TxNumber() domain.TransactionNumber {
 func (rec *SetStringRecord) Undo(visitor TxVisitor) error {
 	return visitor.UndoSetString(rec)
 }
+
+// SetInt64Record is a model of set int64 log record.
+type SetInt64Record struct {
+	baseRecord
+	FileName    domain.FileName
+	TxNum       domain.TransactionNumber
+	BlockNumber domain.BlockNumber
+	Offset      int64
+	Val         int64
+}
+
+// Unmarshal parses the proto message in b and places the result in rec.
+func (rec *SetInt64Record) Unmarshal(b []byte) error {
+	pb := &protobuf.SetInt64Record{}
+	if err := proto.Unmarshal(b, pb); err != nil {
+		return errors.Err(err, "Unmarshal")
+	}
+
+	var err error
+	rec.FileName, err = domain.NewFileName(pb.Filename)
+	if err != nil {
+		return errors.Err(err, "NewFileName")
+	}
+
+	rec.TxNum = domain.TransactionNumber(pb.Txnum)
+	rec.BlockNumber, err = domain.NewBlockNumber(pb.BlockNumber)
+	if err != nil {
+		return errors.Err(err, "NewBlockNumber")
+	}
+
+	rec.Offset = pb.Offset
+	rec.Val = pb.Val
+
+	return nil
+}
+
+// Marshal encodes the rec.
+func (rec *SetInt64Record) Marshal() ([]byte, error) {
+	pb := &protobuf.SetInt64Record{
+		Filename:    rec.FileName.String(),
+		Txnum:       int32(rec.TxNum),
+		BlockNumber: int32(rec.BlockNumber),
+		Offset:      rec.Offset,
+		Val:         rec.Val,
+	}
+
+	return proto.Marshal(pb)
+}
+
+// Operator returns SetInt64.
+func (rec *SetInt64Record) Operator() RecordType {
+	return SetInt64
+}
+
+// TxNumber returns the transaction number.
+func (rec *SetInt64Record) TxNumber() domain.TransactionNumber {
+	return rec.TxNum
+}
+
+// Undo undoes set int64 operation.
+func (rec *SetInt64Record) Undo(visitor TxVisitor) error {
+	return visitor.UndoSetInt64(rec)
+}
@@ -208,3 +208,50 @@ func TestSetStringRecord_Error(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestSetInt64Record(t *testing.T) {
+	t.Run("marshal/unmarshal", func(t *testing.T) {
+		rec := &logrecord.SetInt64Record{
+			FileName:    "hoge",
+			TxNum:       123,
+			BlockNumber: 456,
+			Offset:      789,
+			Val:         -1 << 40,
+		}
+
+		bytes, err := rec.Marshal()
+		require.NoError(t, err)
+
+		rec2 := &logrecord.SetInt64Record{}
+		rec2.Unmarshal(bytes)
+
+		require.Equal(t, *rec, *rec2)
+	})
+
+	t.Run("set int64 record misc", func(t *testing.T) {
+		rec := &logrecord.SetInt64Record{
+			FileName:    "hoge",
+			TxNum:       123,
+			BlockNumber: 456,
+			Offset:      789,
+			Val:         111,
+		}
+
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		visitor := mock.NewMockTxVisitor(ctrl)
+		visitor.EXPECT().UndoSetInt64(gomock.Any()).Return(nil)
+
+		require.Equal(t, logrecord.SetInt64, rec.Operator())
+		require.Equal(t, domain.TransactionNumber(123), rec.TxNumber())
+		require.NoError(t, rec.Undo(visitor))
+	})
+}
+
+func TestSetInt64Record_Error(t *testing.T) {
+	t.Run("marshal/unmarshal", func(t *testing.T) {
+		rec := &logrecord.SetInt64Record{}
+		err := rec.Unmarshal([]byte{1, 2, 3})
+		require.Error(t, err)
+	})
+}
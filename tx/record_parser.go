@@ -39,6 +39,8 @@ func ParseRecord(b []byte) (logrecord.LogRecorder, error) {
 		rec = &logrecord.SetInt32Record{}
 	case logrecord.SetString:
 		rec = &logrecord.SetStringRecord{}
+	case logrecord.SetInt64:
+		rec = &logrecord.SetInt64Record{}
 	case logrecord.Rollback:
 		rec = &logrecord.RollbackRecord{}
 	default:
@@ -48,6 +48,17 @@ func TestParseRecord(t *testing.T) {
 				Val:         "piyo",
 			},
 		},
+		{
+			name: "set int64 log",
+			typ:  logrecord.SetInt64,
+			record: &logrecord.SetInt64Record{
+				FileName:    "hoge",
+				TxNum:       1,
+				BlockNumber: 2,
+				Offset:      3,
+				Val:         1 << 40,
+			},
+		},
 	}
 	for _, tt := range tests {
 		tt := tt
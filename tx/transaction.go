@@ -1,6 +1,8 @@
 package tx
 
 import (
+	"strings"
+
 	"github.com/goropikari/simpledbgo/common"
 	"github.com/goropikari/simpledbgo/domain"
 	"github.com/goropikari/simpledbgo/errors"
@@ -8,25 +10,47 @@ import (
 	"github.com/goropikari/simpledbgo/tx/logrecord"
 )
 
+// ErrFileRemoved is an error that means the file is removed by the transaction.
+var ErrFileRemoved = errors.New("file is removed by the transaction")
+
 // Transaction is a model of transaction.
 type Transaction struct {
-	fileMgr    domain.FileManager
-	logMgr     domain.LogManager
-	bufferMgr  domain.BufferPoolManager
-	concurMgr  *ConcurrencyManager
-	bufferList *BufferList
-	number     domain.TransactionNumber
+	fileMgr      domain.FileManager
+	logMgr       domain.LogManager
+	bufferMgr    domain.BufferPoolManager
+	concurMgr    *ConcurrencyManager
+	bufferList   *BufferList
+	number       domain.TransactionNumber
+	removedFiles map[domain.FileName]bool
+	createdFiles map[domain.FileName]bool
+	versions     *versionTable
+	snapshot     *domain.Snapshot
+	forUpdate    bool
 }
 
 // NewTransaction constructs Transaction.
+// lt が multi-version mode の場合は開始時の snapshot を取る.
 func NewTransaction(fileMgr domain.FileManager, logMgr domain.LogManager, bufferMgr domain.BufferPoolManager, lt *LockTable, gen domain.TxNumberGenerator) (*Transaction, error) {
 	txn := &Transaction{
-		fileMgr:    fileMgr,
-		logMgr:     logMgr,
-		bufferMgr:  bufferMgr,
-		concurMgr:  NewConcurrencyManager(lt),
-		bufferList: NewBufferList(bufferMgr),
-		number:     gen.Generate(),
+		fileMgr:      fileMgr,
+		logMgr:       logMgr,
+		bufferMgr:    bufferMgr,
+		concurMgr:    NewConcurrencyManager(lt),
+		bufferList:   NewBufferList(bufferMgr),
+		removedFiles: make(map[domain.FileName]bool),
+		createdFiles: make(map[domain.FileName]bool),
+		versions:     lt.versions,
+	}
+
+	if txn.versions == nil {
+		txn.number = gen.Generate()
+	} else {
+		num, snapshot, err := txn.versions.begin(gen, logMgr)
+		if err != nil {
+			return nil, errors.Err(err, "begin")
+		}
+		txn.number = num
+		txn.snapshot = snapshot
 	}
 
 	if _, err := txn.writeStartLog(); err != nil {
@@ -38,6 +62,10 @@ func NewTransaction(fileMgr domain.FileManager, logMgr domain.LogManager, buffer
 
 // Pin pins the blk by tx.
 func (tx *Transaction) Pin(blk domain.Block) error {
+	if tx.removedFiles[blk.FileName()] {
+		return errors.Wrap(ErrFileRemoved, blk.FileName().String())
+	}
+
 	if err := tx.bufferList.Pin(blk); err != nil {
 		return errors.Err(err, "Pin")
 	}
@@ -56,12 +84,40 @@ func (tx *Transaction) Commit() error {
 		return errors.Err(err, "commit")
 	}
 
-	tx.concurMgr.Release()
+	// file の削除が終わるまで他の transaction から使われないように lock を持ち続ける.
 	tx.bufferList.UnpinAll()
+	err := tx.removeFiles()
+	tx.endVersion(true)
+	tx.concurMgr.Release()
+	if err != nil {
+		return errors.Err(err, "removeFiles")
+	}
 
 	return nil
 }
 
+// removeFiles removes the files which are removed by the transaction.
+// commit log を書いた後に消すので, rollback された場合は file が残る.
+func (tx *Transaction) removeFiles() error {
+	for filename := range tx.removedFiles {
+		tx.bufferMgr.DiscardFile(filename)
+		if err := tx.fileMgr.RemoveFile(filename); err != nil {
+			return errors.Err(err, "RemoveFile")
+		}
+	}
+	tx.removedFiles = make(map[domain.FileName]bool)
+
+	return nil
+}
+
+// endVersion removes the snapshot of the transaction.
+// lock を解放する前に呼び, 次に block を書き換える transaction の page と混ざらないようにする.
+func (tx *Transaction) endVersion(committed bool) {
+	if tx.versions != nil {
+		tx.versions.end(tx.number, committed)
+	}
+}
+
 func (tx *Transaction) commit() error {
 	if err := tx.bufferMgr.FlushAll(tx.number); err != nil {
 		return errors.Err(err, "FlushAll")
@@ -81,12 +137,29 @@ func (tx *Transaction) commit() error {
 
 // Rollback rollbacks the transaction.
 func (tx *Transaction) Rollback() error {
+	// undo で削除予定の file に書き戻すことがあるので, 先に削除を取り消す.
+	// temporary table の file と transaction が作った file は rollback しても使わないので, undo の後に削除する.
+	discarded := tx.createdFiles
+	for filename := range tx.removedFiles {
+		if strings.HasPrefix(filename.String(), domain.TempTablePrefix) {
+			discarded[filename] = true
+		}
+	}
+	tx.removedFiles = make(map[domain.FileName]bool)
+	tx.createdFiles = make(map[domain.FileName]bool)
+
 	if err := tx.rollback(); err != nil {
 		return errors.Err(err, "rollback")
 	}
 
-	tx.concurMgr.Release()
 	tx.bufferList.UnpinAll()
+	tx.removedFiles = discarded
+	err := tx.removeFiles()
+	tx.endVersion(false)
+	tx.concurMgr.Release()
+	if err != nil {
+		return errors.Err(err, "removeFiles")
+	}
 
 	return nil
 }
@@ -235,14 +308,32 @@ func (tx *Transaction) UndoSetString(rec *logrecord.SetStringRecord) error {
 	return nil
 }
 
+// UndoSetInt64 undoes SetInt64 operation.
+func (tx *Transaction) UndoSetInt64(rec *logrecord.SetInt64Record) error {
+	blk := domain.NewBlock(rec.FileName, rec.BlockNumber)
+
+	if err := tx.Pin(blk); err != nil {
+		return errors.Err(err, "Pin")
+	}
+
+	if err := tx.SetInt64(blk, rec.Offset, rec.Val, false); err != nil {
+		return errors.Err(err, "SetInt64")
+	}
+
+	tx.Unpin(blk)
+
+	return nil
+}
+
 // GetInt32 gets int32 from the blk at offset.
 func (tx *Transaction) GetInt32(blk domain.Block, offset int64) (int32, error) {
-	if err := tx.concurMgr.SLock(blk); err != nil {
-		return 0, errors.Err(err, "SLock")
-	}
+	var x int32
+	err := tx.read(blk, func(page *domain.Page) error {
+		var err error
+		x, err = page.GetInt32(offset)
 
-	buf := tx.bufferList.GetBuffer(blk)
-	x, err := buf.Page().GetInt32(offset)
+		return err
+	})
 	if err != nil {
 		return 0, errors.Err(err, "GetInt32")
 	}
@@ -252,72 +343,159 @@ func (tx *Transaction) GetInt32(blk domain.Block, offset int64) (int32, error) {
 
 // SetInt32 sets int32 on the given block.
 func (tx *Transaction) SetInt32(blk domain.Block, offset int64, val int32, writeLog bool) error {
-	if err := tx.concurMgr.XLock(blk); err != nil {
-		return errors.Err(err, "XLock")
-	}
+	return tx.write(blk, func(page *domain.Page) error {
+		buf := tx.bufferList.GetBuffer(blk)
+		lsn := domain.DummyLSN
+		if writeLog {
+			var err error
+			oldval, err := page.GetInt32(offset)
+			if err != nil {
+				return errors.Err(err, "GetInt32")
+			}
 
-	buf := tx.bufferList.GetBuffer(blk)
-	lsn := domain.DummyLSN
-	if writeLog {
-		var err error
-		oldval, err := buf.Page().GetInt32(offset)
-		if err != nil {
-			return errors.Err(err, "GetInt32")
+			lsn, err = tx.writeSetInt32Log(buf.Block(), offset, oldval)
+			if err != nil {
+				return errors.Err(err, "writeSetInt32Log")
+			}
 		}
 
-		lsn, err = tx.writeSetInt32Log(buf.Block(), offset, oldval)
-		if err != nil {
-			return errors.Err(err, "writeSetInt32Log")
+		if err := page.SetInt32(offset, val); err != nil {
+			return errors.Err(err, "SetInt32")
 		}
-	}
 
-	if err := buf.Page().SetInt32(offset, val); err != nil {
-		return errors.Err(err, "SetInt32")
+		buf.SetModifiedTxNumber(tx.number, lsn)
+
+		return nil
+	})
+}
+
+// GetInt64 gets int64 from the blk at offset.
+func (tx *Transaction) GetInt64(blk domain.Block, offset int64) (int64, error) {
+	var x int64
+	err := tx.read(blk, func(page *domain.Page) error {
+		var err error
+		x, err = page.GetInt64(offset)
+
+		return err
+	})
+	if err != nil {
+		return 0, errors.Err(err, "GetInt64")
 	}
 
-	buf.SetModifiedTxNumber(tx.number, lsn)
+	return x, nil
+}
+
+// SetInt64 sets int64 on the given block.
+func (tx *Transaction) SetInt64(blk domain.Block, offset int64, val int64, writeLog bool) error {
+	return tx.write(blk, func(page *domain.Page) error {
+		buf := tx.bufferList.GetBuffer(blk)
+		lsn := domain.DummyLSN
+		if writeLog {
+			oldval, err := page.GetInt64(offset)
+			if err != nil {
+				return errors.Err(err, "GetInt64")
+			}
 
-	return nil
+			lsn, err = tx.writeSetInt64Log(buf.Block(), offset, oldval)
+			if err != nil {
+				return errors.Err(err, "writeSetInt64Log")
+			}
+		}
+
+		if err := page.SetInt64(offset, val); err != nil {
+			return errors.Err(err, "SetInt64")
+		}
+
+		buf.SetModifiedTxNumber(tx.number, lsn)
+
+		return nil
+	})
 }
 
 // GetString gets string from the blk.
 func (tx *Transaction) GetString(blk domain.Block, offset int64) (string, error) {
-	if err := tx.concurMgr.SLock(blk); err != nil {
-		return "", errors.Err(err, "SLock")
+	var x string
+	err := tx.read(blk, func(page *domain.Page) error {
+		var err error
+		x, err = page.GetString(offset)
+
+		return err
+	})
+	if err != nil {
+		return "", errors.Err(err, "GetString")
 	}
 
+	return x, nil
+}
+
+// read calls fn with the page of the blk which the transaction reads.
+// multi-version mode では lock を取らずに snapshot から見える page を読む.
+// 更新用の transaction と, block を書き換えた transaction は最新の page を読む.
+func (tx *Transaction) read(blk domain.Block, fn func(*domain.Page) error) error {
 	buf := tx.bufferList.GetBuffer(blk)
 
-	return buf.Page().GetString(offset)
+	if tx.versions == nil {
+		if err := tx.concurMgr.SLock(blk); err != nil {
+			return errors.Err(err, "SLock")
+		}
+
+		return fn(buf.Page())
+	}
+
+	if tx.concurMgr.HasXLock(blk) {
+		return tx.versions.readLatest(buf.Page(), fn)
+	}
+
+	if tx.forUpdate {
+		if err := tx.concurMgr.SLock(blk); err != nil {
+			return errors.Err(err, "SLock")
+		}
+
+		return tx.versions.readLatest(buf.Page(), fn)
+	}
+
+	return tx.versions.read(tx.snapshot, blk, buf.Page(), fn)
 }
 
-// SetString sets string on the blk.
-func (tx *Transaction) SetString(blk domain.Block, offset int64, val string, writeLog bool) error {
+// write calls fn which modifies the page of the blk.
+// multi-version mode では書き換える前の page を他の transaction のために残す.
+func (tx *Transaction) write(blk domain.Block, fn func(*domain.Page) error) error {
 	if err := tx.concurMgr.XLock(blk); err != nil {
 		return errors.Err(err, "XLock")
 	}
 
 	buf := tx.bufferList.GetBuffer(blk)
-	lsn := domain.DummyLSN
-	if writeLog {
-		oldval, err := buf.Page().GetString(offset)
-		if err != nil {
-			return errors.Err(err, "GetString")
-		}
-		lsn, err = tx.writeSetStringLog(buf.Block(), offset, oldval)
-		if err != nil {
-			return errors.Err(err, "writeSetStringLog")
-		}
+	if tx.versions == nil {
+		return fn(buf.Page())
 	}
 
-	page := buf.Page()
-	if err := page.SetString(offset, val); err != nil {
-		return errors.Err(err, "SetString")
-	}
+	return tx.versions.write(tx.number, blk, buf.Page(), fn)
+}
+
+// SetString sets string on the blk.
+func (tx *Transaction) SetString(blk domain.Block, offset int64, val string, writeLog bool) error {
+	return tx.write(blk, func(page *domain.Page) error {
+		buf := tx.bufferList.GetBuffer(blk)
+		lsn := domain.DummyLSN
+		if writeLog {
+			oldval, err := page.GetString(offset)
+			if err != nil {
+				return errors.Err(err, "GetString")
+			}
+			lsn, err = tx.writeSetStringLog(buf.Block(), offset, oldval)
+			if err != nil {
+				return errors.Err(err, "writeSetStringLog")
+			}
+		}
 
-	buf.SetModifiedTxNumber(tx.number, lsn)
+		if err := page.SetString(offset, val); err != nil {
+			return errors.Err(err, "SetString")
+		}
 
-	return nil
+		buf.SetModifiedTxNumber(tx.number, lsn)
+
+		return nil
+	})
 }
 
 func (tx *Transaction) writeStartLog() (domain.LSN, error) {
@@ -364,6 +542,18 @@ func (tx *Transaction) writeSetStringLog(blk domain.Block, offset int64, val str
 	return tx.writeLog(logrecord.SetString, record)
 }
 
+func (tx *Transaction) writeSetInt64Log(blk domain.Block, offset int64, val int64) (domain.LSN, error) {
+	record := &logrecord.SetInt64Record{
+		FileName:    blk.FileName(),
+		TxNum:       tx.number,
+		BlockNumber: blk.Number(),
+		Offset:      offset,
+		Val:         val,
+	}
+
+	return tx.writeLog(logrecord.SetInt64, record)
+}
+
 func (tx *Transaction) writeRollbackLog() (domain.LSN, error) {
 	record := &logrecord.RollbackRecord{TxNum: tx.number}
 
@@ -400,9 +590,16 @@ func (tx *Transaction) writeLog(typ logrecord.RecordType, record logrecord.LogRe
 // BlockLength returns block length of the `filename`.
 // original method name is `size`.
 func (tx *Transaction) BlockLength(filename domain.FileName) (int32, error) {
-	dummyBlk := domain.NewDummyBlock(filename)
-	if err := tx.concurMgr.SLock(dummyBlk); err != nil {
-		return 0, errors.Err(err, "SLock")
+	if tx.removedFiles[filename] {
+		return 0, errors.Wrap(ErrFileRemoved, filename.String())
+	}
+
+	// snapshot を読む場合は file の拡張を待たない. 後から追加された block は空の page として読める.
+	if tx.versions == nil || tx.forUpdate {
+		dummyBlk := domain.NewDummyBlock(filename)
+		if err := tx.concurMgr.SLock(dummyBlk); err != nil {
+			return 0, errors.Err(err, "SLock")
+		}
 	}
 
 	return tx.fileMgr.BlockLength(filename)
@@ -410,6 +607,10 @@ func (tx *Transaction) BlockLength(filename domain.FileName) (int32, error) {
 
 // ExtendFile extends the file by a block.
 func (tx *Transaction) ExtendFile(filename domain.FileName) (domain.Block, error) {
+	if tx.removedFiles[filename] {
+		return domain.Block{}, errors.Wrap(ErrFileRemoved, filename.String())
+	}
+
 	dummyBlk := domain.NewDummyBlock(filename)
 	if err := tx.concurMgr.XLock(dummyBlk); err != nil {
 		return domain.Block{}, errors.Err(err, "XLock")
@@ -418,6 +619,51 @@ func (tx *Transaction) ExtendFile(filename domain.FileName) (domain.Block, error
 	return tx.fileMgr.ExtendFile(filename)
 }
 
+// RemoveFile removes the file when the transaction is committed.
+// 他の transaction が file を使い終わるまで待つために size 用の dummy block の xlock を取る.
+func (tx *Transaction) RemoveFile(filename domain.FileName) error {
+	dummyBlk := domain.NewDummyBlock(filename)
+	if err := tx.concurMgr.XLock(dummyBlk); err != nil {
+		return errors.Err(err, "XLock")
+	}
+
+	tx.removedFiles[filename] = true
+
+	return nil
+}
+
+// RemoveFileOnRollback removes the file when the transaction is rolled back.
+// transaction の中で新しく作った file を, rollback した後に残さないために使う.
+func (tx *Transaction) RemoveFileOnRollback(filename domain.FileName) error {
+	dummyBlk := domain.NewDummyBlock(filename)
+	if err := tx.concurMgr.XLock(dummyBlk); err != nil {
+		return errors.Err(err, "XLock")
+	}
+
+	tx.createdFiles[filename] = true
+
+	return nil
+}
+
+// SLock takes shared lock of the blk until the transaction ends.
+// record を読まずに存在を保証したい場合に使う.
+func (tx *Transaction) SLock(blk domain.Block) error {
+	if err := tx.concurMgr.SLock(blk); err != nil {
+		return errors.Err(err, "SLock")
+	}
+
+	return nil
+}
+
+// XLock takes exclusive lock of the blk until the transaction ends.
+func (tx *Transaction) XLock(blk domain.Block) error {
+	if err := tx.concurMgr.XLock(blk); err != nil {
+		return errors.Err(err, "XLock")
+	}
+
+	return nil
+}
+
 // BlockSize returns block size.
 func (tx *Transaction) BlockSize() domain.BlockSize {
 	return tx.fileMgr.BlockSize()
@@ -428,6 +674,32 @@ func (tx *Transaction) Available() int {
 	return tx.bufferMgr.Available()
 }
 
+// GetTxNum returns the transaction number.
 func (tx *Transaction) GetTxNum() domain.TransactionNumber {
 	return tx.number
 }
+
+// Snapshot returns the snapshot of the transaction.
+// two-phase locking mode では nil を返す.
+func (tx *Transaction) Snapshot() *domain.Snapshot {
+	return tx.snapshot
+}
+
+// IsVisibleToAll checks whether the changes by txNum are visible to all running transactions.
+// two-phase locking mode では古い version を読む transaction は無いので常に true を返す.
+func (tx *Transaction) IsVisibleToAll(txNum domain.TransactionNumber) bool {
+	if tx.versions == nil {
+		return true
+	}
+
+	return tx.versions.visibleToAll(txNum)
+}
+
+// ForUpdate sets whether the transaction reads the latest pages with shared locks.
+// 更新する record を探す間だけ, 他の transaction の書き込みを待つようにする.
+// two-phase locking mode では何もしない.
+func (tx *Transaction) ForUpdate(forUpdate bool) {
+	if tx.versions != nil {
+		tx.forUpdate = forUpdate
+	}
+}
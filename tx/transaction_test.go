@@ -1,6 +1,7 @@
 package tx_test
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/goropikari/simpledbgo/domain"
@@ -237,6 +238,56 @@ func TestTransaction_GetSetString(t *testing.T) {
 	})
 }
 
+func TestTransaction_GetSetInt64(t *testing.T) {
+	t.Run("test rollback", func(t *testing.T) {
+		const (
+			blockSize = 100
+			numBuf    = 2
+		)
+
+		dbPath := fake.RandString()
+		factory := fake.NewNonDirectBufferManagerFactory(dbPath, blockSize, numBuf)
+		fileMgr, logMgr, bufMgr := factory.Create()
+		defer factory.Finish()
+
+		cfg := tx.LockTableConfig{LockTimeoutMillisecond: 1000}
+		lt := tx.NewLockTable(cfg)
+		gen := tx.NewNumberGenerator()
+
+		blk := domain.NewBlock(domain.FileName("table_"+fake.RandString()), domain.BlockNumber(0))
+		offset := int64(10)
+		val := int64(1) << 40
+
+		txn, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		err = txn.Pin(blk)
+		require.NoError(t, err)
+		err = txn.SetInt64(blk, offset, val, true)
+		require.NoError(t, err)
+		require.NoError(t, txn.Commit())
+
+		txn2, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		err = txn2.Pin(blk)
+		require.NoError(t, err)
+		err = txn2.SetInt64(blk, offset, -val, true)
+		require.NoError(t, err)
+		v, err := txn2.GetInt64(blk, offset)
+		require.NoError(t, err)
+		require.Equal(t, -val, v)
+		require.NoError(t, txn2.Rollback())
+
+		txn3, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		err = txn3.Pin(blk)
+		require.NoError(t, err)
+		v, err = txn3.GetInt64(blk, offset)
+		require.NoError(t, err)
+		require.Equal(t, val, v)
+		require.NoError(t, txn3.Commit())
+	})
+}
+
 func TestTransaction_Rollback(t *testing.T) {
 	t.Run("test commit", func(t *testing.T) {
 		const (
@@ -512,6 +563,294 @@ func TestTransaction_TransactionTimeout(t *testing.T) {
 	})
 }
 
+func TestTransaction_Lock(t *testing.T) {
+	t.Run("shared lock blocks exclusive lock until commit", func(t *testing.T) {
+		const (
+			blockSize = 100
+			numBuf    = 10
+		)
+
+		dbPath := "txn_" + fake.RandString()
+		filename := "table_" + fake.RandString()
+		blk := domain.NewBlock(domain.FileName(filename), domain.BlockNumber(0))
+
+		factory := fake.NewNonDirectBufferManagerFactory(dbPath, blockSize, numBuf)
+		fileMgr, logMgr, bufMgr := factory.Create()
+		defer factory.Finish()
+		cfg := tx.LockTableConfig{LockTimeoutMillisecond: 200}
+		lt := tx.NewLockTable(cfg)
+		gen := tx.NewNumberGenerator()
+
+		txn1, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		err = txn1.SLock(blk)
+		require.NoError(t, err)
+
+		txn2, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		err = txn2.SLock(blk)
+		require.NoError(t, err)
+		err = txn2.XLock(blk)
+		require.ErrorIs(t, err, tx.ErrTransactionTimeoutExceeded)
+		err = txn2.Rollback()
+		require.NoError(t, err)
+
+		err = txn1.Commit()
+		require.NoError(t, err)
+
+		txn3, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		err = txn3.XLock(blk)
+		require.NoError(t, err)
+		err = txn3.Commit()
+		require.NoError(t, err)
+	})
+}
+
+func TestTransaction_MultiVersion(t *testing.T) {
+	const (
+		blockSize = 100
+		numBuf    = 10
+	)
+
+	setup := func(t *testing.T) (func() *tx.Transaction, domain.Block, func()) {
+		dbPath := "txn_" + fake.RandString()
+		filename := "table_" + fake.RandString()
+		blk := domain.NewBlock(domain.FileName(filename), domain.BlockNumber(0))
+
+		factory := fake.NewNonDirectBufferManagerFactory(dbPath, blockSize, numBuf)
+		fileMgr, logMgr, bufMgr := factory.Create()
+		cfg := tx.LockTableConfig{LockTimeoutMillisecond: 200, ConcurrencyControl: tx.MultiVersion}
+		lt := tx.NewLockTable(cfg)
+		gen := tx.NewNumberGenerator()
+
+		newTxn := func() *tx.Transaction {
+			txn, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+			require.NoError(t, err)
+			require.NotNil(t, txn.Snapshot())
+			err = txn.Pin(blk)
+			require.NoError(t, err)
+
+			return txn
+		}
+
+		return newTxn, blk, factory.Finish
+	}
+
+	t.Run("reader does not block writer and reads its snapshot", func(t *testing.T) {
+		newTxn, blk, finish := setup(t)
+		defer finish()
+
+		txn1 := newTxn()
+		err := txn1.SetInt32(blk, 10, 100, true)
+		require.NoError(t, err)
+		err = txn1.Commit()
+		require.NoError(t, err)
+
+		reader := newTxn()
+		x, err := reader.GetInt32(blk, 10)
+		require.NoError(t, err)
+		require.Equal(t, int32(100), x)
+
+		writer := newTxn()
+		err = writer.SetInt32(blk, 10, 200, true)
+		require.NoError(t, err)
+		x, err = writer.GetInt32(blk, 10)
+		require.NoError(t, err)
+		require.Equal(t, int32(200), x)
+
+		x, err = reader.GetInt32(blk, 10)
+		require.NoError(t, err)
+		require.Equal(t, int32(100), x)
+
+		err = writer.Commit()
+		require.NoError(t, err)
+
+		x, err = reader.GetInt32(blk, 10)
+		require.NoError(t, err)
+		require.Equal(t, int32(100), x)
+		err = reader.Commit()
+		require.NoError(t, err)
+
+		txn2 := newTxn()
+		x, err = txn2.GetInt32(blk, 10)
+		require.NoError(t, err)
+		require.Equal(t, int32(200), x)
+		err = txn2.Commit()
+		require.NoError(t, err)
+	})
+
+	t.Run("rolled back changes are not visible", func(t *testing.T) {
+		newTxn, blk, finish := setup(t)
+		defer finish()
+
+		txn1 := newTxn()
+		err := txn1.SetString(blk, 10, "foo", true)
+		require.NoError(t, err)
+		err = txn1.Rollback()
+		require.NoError(t, err)
+
+		txn2 := newTxn()
+		x, err := txn2.GetString(blk, 10)
+		require.NoError(t, err)
+		require.Equal(t, "", x)
+		err = txn2.Commit()
+		require.NoError(t, err)
+	})
+
+	t.Run("writers block each other", func(t *testing.T) {
+		newTxn, blk, finish := setup(t)
+		defer finish()
+
+		txn1 := newTxn()
+		err := txn1.SetInt32(blk, 10, 100, true)
+		require.NoError(t, err)
+
+		txn2 := newTxn()
+		err = txn2.SetInt32(blk, 10, 200, true)
+		require.ErrorIs(t, err, tx.ErrTransactionTimeoutExceeded)
+	})
+}
+
+func TestTransaction_RemoveFile(t *testing.T) {
+	const (
+		blockSize = 100
+		numBuf    = 10
+	)
+
+	setup := func(t *testing.T, fileMgr domain.FileManager, logMgr domain.LogManager, bufMgr domain.BufferPoolManager, lt *tx.LockTable, gen domain.TxNumberGenerator, filename domain.FileName) {
+		txn, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		blk, err := txn.ExtendFile(filename)
+		require.NoError(t, err)
+		err = txn.Pin(blk)
+		require.NoError(t, err)
+		err = txn.SetInt32(blk, 0, 123, true)
+		require.NoError(t, err)
+		err = txn.Commit()
+		require.NoError(t, err)
+	}
+
+	t.Run("rollback keeps the file", func(t *testing.T) {
+		dbPath := "txn_" + fake.RandString()
+		filename := domain.FileName("table_" + fake.RandString())
+		factory := fake.NewNonDirectBufferManagerFactory(dbPath, blockSize, numBuf)
+		fileMgr, logMgr, bufMgr := factory.Create()
+		defer factory.Finish()
+		lt := tx.NewLockTable(tx.LockTableConfig{LockTimeoutMillisecond: 200})
+		gen := tx.NewNumberGenerator()
+		setup(t, fileMgr, logMgr, bufMgr, lt, gen, filename)
+
+		txn1, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		err = txn1.RemoveFile(filename)
+		require.NoError(t, err)
+		_, err = txn1.BlockLength(filename)
+		require.ErrorIs(t, err, tx.ErrFileRemoved)
+		err = txn1.Rollback()
+		require.NoError(t, err)
+
+		txn2, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		size, err := txn2.BlockLength(filename)
+		require.NoError(t, err)
+		require.Equal(t, int32(1), size)
+		blk := domain.NewBlock(filename, 0)
+		err = txn2.Pin(blk)
+		require.NoError(t, err)
+		val, err := txn2.GetInt32(blk, 0)
+		require.NoError(t, err)
+		require.Equal(t, int32(123), val)
+		err = txn2.Commit()
+		require.NoError(t, err)
+	})
+
+	t.Run("commit removes the file", func(t *testing.T) {
+		dbPath := "txn_" + fake.RandString()
+		filename := domain.FileName("table_" + fake.RandString())
+		factory := fake.NewNonDirectBufferManagerFactory(dbPath, blockSize, numBuf)
+		fileMgr, logMgr, bufMgr := factory.Create()
+		defer factory.Finish()
+		lt := tx.NewLockTable(tx.LockTableConfig{LockTimeoutMillisecond: 200})
+		gen := tx.NewNumberGenerator()
+		setup(t, fileMgr, logMgr, bufMgr, lt, gen, filename)
+
+		txn1, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		err = txn1.RemoveFile(filename)
+		require.NoError(t, err)
+
+		// 削除する transaction が commit するまで他の transaction は file を使えない.
+		txn2, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		_, err = txn2.BlockLength(filename)
+		require.ErrorIs(t, err, tx.ErrTransactionTimeoutExceeded)
+		err = txn2.Rollback()
+		require.NoError(t, err)
+
+		err = txn1.Commit()
+		require.NoError(t, err)
+
+		txn3, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		size, err := txn3.BlockLength(filename)
+		require.NoError(t, err)
+		require.Equal(t, int32(0), size)
+
+		// 古い内容が buffer に残っていないこと.
+		blk, err := txn3.ExtendFile(filename)
+		require.NoError(t, err)
+		err = txn3.Pin(blk)
+		require.NoError(t, err)
+		val, err := txn3.GetInt32(blk, 0)
+		require.NoError(t, err)
+		require.Equal(t, int32(0), val)
+		err = txn3.Commit()
+		require.NoError(t, err)
+	})
+}
+
+func TestTransaction_RemoveFileOnRollback(t *testing.T) {
+	const (
+		blockSize = 100
+		numBuf    = 10
+	)
+
+	for _, commit := range []bool{false, true} {
+		dbPath := "txn_" + fake.RandString()
+		filename := domain.FileName("table_" + fake.RandString())
+		factory := fake.NewNonDirectBufferManagerFactory(dbPath, blockSize, numBuf)
+		fileMgr, logMgr, bufMgr := factory.Create()
+		lt := tx.NewLockTable(tx.LockTableConfig{LockTimeoutMillisecond: 200})
+		gen := tx.NewNumberGenerator()
+
+		txn1, err := tx.NewTransaction(fileMgr, logMgr, bufMgr, lt, gen)
+		require.NoError(t, err)
+		err = txn1.RemoveFileOnRollback(filename)
+		require.NoError(t, err)
+		blk, err := txn1.ExtendFile(filename)
+		require.NoError(t, err)
+		err = txn1.Pin(blk)
+		require.NoError(t, err)
+		err = txn1.SetInt32(blk, 0, 123, true)
+		require.NoError(t, err)
+		if commit {
+			err = txn1.Commit()
+		} else {
+			err = txn1.Rollback()
+		}
+		require.NoError(t, err)
+
+		// rollback した場合だけ file が削除される.
+		if commit {
+			require.FileExists(t, filepath.Join(dbPath, filename.String()))
+		} else {
+			require.NoFileExists(t, filepath.Join(dbPath, filename.String()))
+		}
+		factory.Finish()
+	}
+}
+
 func TestTransaction_Size(t *testing.T) {
 	t.Run("test size", func(t *testing.T) {
 		const (
@@ -0,0 +1,186 @@
+package tx
+
+import (
+	"sync"
+
+	"github.com/goropikari/simpledbgo/domain"
+	"github.com/goropikari/simpledbgo/errors"
+	"github.com/goropikari/simpledbgo/lib/bytes"
+	"github.com/goropikari/simpledbgo/tx/logrecord"
+)
+
+// pageVersion is the page image before the writer modified the block.
+type pageVersion struct {
+	writer domain.TransactionNumber
+	image  *domain.Page
+}
+
+// versionTable manages snapshots of active transactions and old page images.
+// block ごとに, 書き込んだ transaction と書き込む前の page を古い順に保持する.
+type versionTable struct {
+	mu     sync.RWMutex
+	seeded bool
+	base   domain.TransactionNumber
+	active map[domain.TransactionNumber]*domain.Snapshot
+	pages  map[domain.Block][]pageVersion
+}
+
+func newVersionTable() *versionTable {
+	return &versionTable{
+		active: make(map[domain.TransactionNumber]*domain.Snapshot),
+		pages:  make(map[domain.Block][]pageVersion),
+	}
+}
+
+// begin numbers a new transaction and takes its snapshot.
+// record に記録された tx number と比較するため, 番号は log に残っている transaction より大きくする.
+func (vt *versionTable) begin(gen domain.TxNumberGenerator, logMgr domain.LogManager) (domain.TransactionNumber, *domain.Snapshot, error) {
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+
+	if !vt.seeded {
+		base, err := lastTxNumber(logMgr)
+		if err != nil {
+			return 0, nil, errors.Err(err, "lastTxNumber")
+		}
+		vt.base = base
+		vt.seeded = true
+	}
+
+	num := vt.base + gen.Generate()
+	active := make(map[domain.TransactionNumber]bool, len(vt.active))
+	for txNum := range vt.active {
+		active[txNum] = true
+	}
+	snapshot := domain.NewSnapshot(num, active)
+	vt.active[num] = snapshot
+
+	return num, snapshot, nil
+}
+
+// lastTxNumber returns the largest tx number written in the log.
+func lastTxNumber(logMgr domain.LogManager) (domain.TransactionNumber, error) {
+	iter, err := logMgr.Iterator()
+	if err != nil {
+		return 0, errors.Err(err, "Iterator")
+	}
+
+	var last domain.TransactionNumber
+	for iter.HasNext() {
+		data, err := iter.Next()
+		if err != nil {
+			return 0, errors.Err(err, "Next")
+		}
+
+		record, err := ParseRecord(data)
+		if err != nil {
+			return 0, errors.Err(err, "ParseRecord")
+		}
+
+		if record.Operator() == logrecord.Start && record.TxNumber() > last {
+			last = record.TxNumber()
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return 0, errors.Err(err, "HasNext")
+	}
+
+	return last, nil
+}
+
+// read calls fn with the page of the blk as seen from the snapshot.
+// snapshot から見えない transaction が書き込む前の page があればそれを, 無ければ最新の page を読む.
+func (vt *versionTable) read(snapshot *domain.Snapshot, blk domain.Block, live *domain.Page, fn func(*domain.Page) error) error {
+	vt.mu.RLock()
+	defer vt.mu.RUnlock()
+
+	for _, ver := range vt.pages[blk] {
+		if !snapshot.IsVisible(ver.writer) {
+			return fn(ver.image)
+		}
+	}
+
+	return fn(live)
+}
+
+// readLatest calls fn with the latest page.
+func (vt *versionTable) readLatest(live *domain.Page, fn func(*domain.Page) error) error {
+	vt.mu.RLock()
+	defer vt.mu.RUnlock()
+
+	return fn(live)
+}
+
+// write calls fn which modifies the latest page of the blk.
+// transaction が初めて block を書き換えるときに, 書き換える前の page を保存する.
+func (vt *versionTable) write(txNum domain.TransactionNumber, blk domain.Block, live *domain.Page, fn func(*domain.Page) error) error {
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+
+	history := vt.pages[blk]
+	if len(history) == 0 || history[len(history)-1].writer != txNum {
+		data := make([]byte, len(live.GetData()))
+		copy(data, live.GetData())
+		vt.pages[blk] = append(history, pageVersion{
+			writer: txNum,
+			image:  domain.NewPage(bytes.NewBufferBytes(data)),
+		})
+	}
+
+	return fn(live)
+}
+
+// end finishes the transaction and discards page images which no snapshot reads.
+// rollback した場合は書き換えが取り消されているので, その transaction の page を捨てる.
+func (vt *versionTable) end(txNum domain.TransactionNumber, committed bool) {
+	vt.mu.Lock()
+	defer vt.mu.Unlock()
+
+	delete(vt.active, txNum)
+
+	for blk, history := range vt.pages {
+		if !committed {
+			kept := history[:0]
+			for _, ver := range history {
+				if ver.writer != txNum {
+					kept = append(kept, ver)
+				}
+			}
+			history = kept
+		}
+
+		for len(history) > 0 && vt.isVisibleToAll(history[0].writer) {
+			history = history[1:]
+		}
+
+		if len(history) == 0 {
+			delete(vt.pages, blk)
+		} else {
+			vt.pages[blk] = history
+		}
+	}
+}
+
+// visibleToAll checks whether all active snapshots see the changes by txNum.
+func (vt *versionTable) visibleToAll(txNum domain.TransactionNumber) bool {
+	vt.mu.RLock()
+	defer vt.mu.RUnlock()
+
+	return vt.isVisibleToAll(txNum)
+}
+
+// isVisibleToAll checks whether all active snapshots see the changes by txNum.
+// 呼び出し側が lock を取っておく.
+func (vt *versionTable) isVisibleToAll(txNum domain.TransactionNumber) bool {
+	if _, ok := vt.active[txNum]; ok {
+		return false
+	}
+
+	for _, snapshot := range vt.active {
+		if !snapshot.IsVisible(txNum) {
+			return false
+		}
+	}
+
+	return true
+}